@@ -0,0 +1,5 @@
+// Package todov1 contém o código gerado a partir das definições protobuf da
+// API gRPC.
+package todov1
+
+//go:generate protoc -I ../.. --go_out=../.. --go_opt=paths=source_relative --go-grpc_out=../.. --go-grpc_opt=paths=source_relative todo/v1/task.proto todo/v1/user.proto todo/v1/onboarding.proto
@@ -0,0 +1,182 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        (unknown)
+// source: todo/v1/onboarding.proto
+
+package todov1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type SetupRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Email         string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetupRequest) Reset() {
+	*x = SetupRequest{}
+	mi := &file_todo_v1_onboarding_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetupRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetupRequest) ProtoMessage() {}
+
+func (x *SetupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_onboarding_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetupRequest.ProtoReflect.Descriptor instead.
+func (*SetupRequest) Descriptor() ([]byte, []int) {
+	return file_todo_v1_onboarding_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SetupRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SetupRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+type SetupResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetupResponse) Reset() {
+	*x = SetupResponse{}
+	mi := &file_todo_v1_onboarding_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetupResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetupResponse) ProtoMessage() {}
+
+func (x *SetupResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_onboarding_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetupResponse.ProtoReflect.Descriptor instead.
+func (*SetupResponse) Descriptor() ([]byte, []int) {
+	return file_todo_v1_onboarding_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SetupResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+var File_todo_v1_onboarding_proto protoreflect.FileDescriptor
+
+const file_todo_v1_onboarding_proto_rawDesc = "" +
+	"\n" +
+	"\x18todo/v1/onboarding.proto\x12\atodo.v1\"8\n" +
+	"\fSetupRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email\")\n" +
+	"\rSetupResponse\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage2K\n" +
+	"\x11OnboardingService\x126\n" +
+	"\x05Setup\x12\x15.todo.v1.SetupRequest\x1a\x16.todo.v1.SetupResponseB4Z2github.com/hoyci/todo-ddd/api/proto/todo/v1;todov1b\x06proto3"
+
+var (
+	file_todo_v1_onboarding_proto_rawDescOnce sync.Once
+	file_todo_v1_onboarding_proto_rawDescData []byte
+)
+
+func file_todo_v1_onboarding_proto_rawDescGZIP() []byte {
+	file_todo_v1_onboarding_proto_rawDescOnce.Do(func() {
+		file_todo_v1_onboarding_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_todo_v1_onboarding_proto_rawDesc), len(file_todo_v1_onboarding_proto_rawDesc)))
+	})
+	return file_todo_v1_onboarding_proto_rawDescData
+}
+
+var file_todo_v1_onboarding_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_todo_v1_onboarding_proto_goTypes = []any{
+	(*SetupRequest)(nil),  // 0: todo.v1.SetupRequest
+	(*SetupResponse)(nil), // 1: todo.v1.SetupResponse
+}
+var file_todo_v1_onboarding_proto_depIdxs = []int32{
+	0, // 0: todo.v1.OnboardingService.Setup:input_type -> todo.v1.SetupRequest
+	1, // 1: todo.v1.OnboardingService.Setup:output_type -> todo.v1.SetupResponse
+	1, // [1:2] is the sub-list for method output_type
+	0, // [0:1] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_todo_v1_onboarding_proto_init() }
+func file_todo_v1_onboarding_proto_init() {
+	if File_todo_v1_onboarding_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_todo_v1_onboarding_proto_rawDesc), len(file_todo_v1_onboarding_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_todo_v1_onboarding_proto_goTypes,
+		DependencyIndexes: file_todo_v1_onboarding_proto_depIdxs,
+		MessageInfos:      file_todo_v1_onboarding_proto_msgTypes,
+	}.Build()
+	File_todo_v1_onboarding_proto = out.File
+	file_todo_v1_onboarding_proto_goTypes = nil
+	file_todo_v1_onboarding_proto_depIdxs = nil
+}
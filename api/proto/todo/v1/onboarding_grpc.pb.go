@@ -0,0 +1,125 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: todo/v1/onboarding.proto
+
+package todov1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	OnboardingService_Setup_FullMethodName = "/todo.v1.OnboardingService/Setup"
+)
+
+// OnboardingServiceClient is the client API for OnboardingService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// OnboardingService cria o usuário junto com a tarefa de boas-vindas.
+type OnboardingServiceClient interface {
+	Setup(ctx context.Context, in *SetupRequest, opts ...grpc.CallOption) (*SetupResponse, error)
+}
+
+type onboardingServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewOnboardingServiceClient(cc grpc.ClientConnInterface) OnboardingServiceClient {
+	return &onboardingServiceClient{cc}
+}
+
+func (c *onboardingServiceClient) Setup(ctx context.Context, in *SetupRequest, opts ...grpc.CallOption) (*SetupResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetupResponse)
+	err := c.cc.Invoke(ctx, OnboardingService_Setup_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// OnboardingServiceServer is the server API for OnboardingService service.
+// All implementations must embed UnimplementedOnboardingServiceServer
+// for forward compatibility.
+//
+// OnboardingService cria o usuário junto com a tarefa de boas-vindas.
+type OnboardingServiceServer interface {
+	Setup(context.Context, *SetupRequest) (*SetupResponse, error)
+	mustEmbedUnimplementedOnboardingServiceServer()
+}
+
+// UnimplementedOnboardingServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedOnboardingServiceServer struct{}
+
+func (UnimplementedOnboardingServiceServer) Setup(context.Context, *SetupRequest) (*SetupResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Setup not implemented")
+}
+func (UnimplementedOnboardingServiceServer) mustEmbedUnimplementedOnboardingServiceServer() {}
+func (UnimplementedOnboardingServiceServer) testEmbeddedByValue()                           {}
+
+// UnsafeOnboardingServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to OnboardingServiceServer will
+// result in compilation errors.
+type UnsafeOnboardingServiceServer interface {
+	mustEmbedUnimplementedOnboardingServiceServer()
+}
+
+func RegisterOnboardingServiceServer(s grpc.ServiceRegistrar, srv OnboardingServiceServer) {
+	// If the following call pancis, it indicates UnimplementedOnboardingServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&OnboardingService_ServiceDesc, srv)
+}
+
+func _OnboardingService_Setup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OnboardingServiceServer).Setup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OnboardingService_Setup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OnboardingServiceServer).Setup(ctx, req.(*SetupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// OnboardingService_ServiceDesc is the grpc.ServiceDesc for OnboardingService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var OnboardingService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "todo.v1.OnboardingService",
+	HandlerType: (*OnboardingServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Setup",
+			Handler:    _OnboardingService_Setup_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "todo/v1/onboarding.proto",
+}
@@ -1,19 +1,71 @@
 package main
 
 import (
+	"cmp"
+	"context"
+	"crypto/rand"
+	"errors"
 	"log"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	_ "github.com/hoyci/todo-ddd/docs/swagger"
 	"github.com/hoyci/todo-ddd/internal/adapters/api"
 	"github.com/hoyci/todo-ddd/internal/adapters/api/handler"
+	"github.com/hoyci/todo-ddd/internal/adapters/blob"
 	"github.com/hoyci/todo-ddd/internal/adapters/db/sqlite"
+	"github.com/hoyci/todo-ddd/internal/adapters/events"
+	"github.com/hoyci/todo-ddd/internal/adapters/graphql"
+	grpcadapter "github.com/hoyci/todo-ddd/internal/adapters/grpc"
+	"github.com/hoyci/todo-ddd/internal/adapters/metrics"
+	"github.com/hoyci/todo-ddd/internal/adapters/notify"
+	"github.com/hoyci/todo-ddd/internal/adapters/ratelimit"
+	"github.com/hoyci/todo-ddd/internal/adapters/tracing"
+	domainNotification "github.com/hoyci/todo-ddd/pkg/domain/notification"
+	domainTask "github.com/hoyci/todo-ddd/pkg/domain/task"
+	domainUser "github.com/hoyci/todo-ddd/pkg/domain/user"
+	"github.com/hoyci/todo-ddd/pkg/domain/valueobject"
+	domainWorkspace "github.com/hoyci/todo-ddd/pkg/domain/workspace"
+	"github.com/hoyci/todo-ddd/pkg/logging"
+	"github.com/hoyci/todo-ddd/pkg/policy"
+	"github.com/hoyci/todo-ddd/pkg/usecase"
+	usecaseaudit "github.com/hoyci/todo-ddd/pkg/usecase/audit"
+	usecasenotification "github.com/hoyci/todo-ddd/pkg/usecase/notification"
 	usecasesetup "github.com/hoyci/todo-ddd/pkg/usecase/setup"
 	usecasetask "github.com/hoyci/todo-ddd/pkg/usecase/task"
 	usecaseuser "github.com/hoyci/todo-ddd/pkg/usecase/user"
+	usecaseworkspace "github.com/hoyci/todo-ddd/pkg/usecase/workspace"
 )
 
 func main() {
+	logConfig, err := logging.ConfigFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+	slog.SetDefault(logging.New(logConfig))
+
+	exporter, err := tracing.NewExporter(context.Background(), os.Getenv("OTEL_TRACES_EXPORTER"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	shutdownTracing, err := tracing.Setup(tracing.Config{ServiceName: "todo-ddd", Exporter: exporter})
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			log.Println("tracing shutdown:", err)
+		}
+	}()
+
 	db, err := sqlite.InitDB()
 	if err != nil {
 		log.Fatal(err)
@@ -21,20 +73,371 @@ func main() {
 
 	taskRepo := sqlite.NewSQLiteTaskRepository(db)
 	userRepo := sqlite.NewSQLiteUserRepository(db)
-	unitOfWork := sqlite.NewSQLiteUnitOfWork(db)
+	taskEventRepo := sqlite.NewSQLiteTaskEventRepository(db)
+	commentRepo := sqlite.NewSQLiteCommentRepository(db)
+	revisionRepo := sqlite.NewSQLiteRevisionRepository(db)
+	reminderRepo := sqlite.NewSQLiteReminderRepository(db)
+	notificationRepo := sqlite.NewSQLiteNotificationRepository(db)
+	preferenceRepo := sqlite.NewSQLiteNotificationPreferenceRepository(db)
+	attachmentRepo := sqlite.NewSQLiteAttachmentRepository(db)
+	shareRepo := sqlite.NewSQLiteShareRepository(db)
+	watcherRepo := sqlite.NewSQLiteWatcherRepository(db)
+	workspaceRepo := sqlite.NewSQLiteWorkspaceRepository(db)
+	invitationRepo := sqlite.NewSQLiteInvitationRepository(db)
+	roleStore := sqlite.NewSQLiteRoleStore(db)
+	auditRepo := sqlite.NewSQLiteAuditRepository(db)
+	// Cada stream SSE e cada conexão WebSocket ocupam uma assinatura do
+	// usuário, qualquer que seja o número de canais assinados nela.
+	eventBroker := events.NewBroker(5)
+	presence := events.NewPresence()
+	unitOfWork := sqlite.NewSQLiteUnitOfWork(db, eventBroker)
 
-	createTaskUC := &usecasetask.CreateTaskUseCase{UoW: unitOfWork}
-	listUC := &usecasetask.ListTaskUseCase{TaskRepo: taskRepo}
-	updateUC := &usecasetask.UpdateTaskUseCase{TaskRepo: taskRepo}
-	updateStatusUC := &usecasetask.UpdateTaskStatusUseCase{TaskRepo: taskRepo}
-	deleteUC := &usecasetask.DeleteTaskUseCase{TaskRepo: taskRepo}
+	var blobStore domainTask.BlobStore
+	if os.Getenv("BLOB_STORE") == "s3" {
+		blobStore, err = blob.NewS3Store(blob.S3Config{
+			Endpoint:        os.Getenv("S3_ENDPOINT"),
+			Region:          os.Getenv("S3_REGION"),
+			Bucket:          os.Getenv("S3_BUCKET"),
+			AccessKeyID:     os.Getenv("S3_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("S3_SECRET_ACCESS_KEY"),
+			PathStyle:       os.Getenv("S3_PATH_STYLE") == "true",
+		})
+	} else {
+		blobStore, err = blob.NewLocalStore(cmp.Or(os.Getenv("BLOB_DIR"), "./data/blobs"))
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	appMetrics := metrics.New(db, taskRepo)
+	usecase.SetObserver(appMetrics)
+
+	// ADMIN_USER_IDS concede o papel admin na subida, para que haja quem
+	// administre os papéis dos demais.
+	for _, id := range strings.Split(os.Getenv("ADMIN_USER_IDS"), ",") {
+		if id = strings.TrimSpace(id); id == "" {
+			continue
+		}
+		if err := roleStore.Grant(context.Background(), id, policy.RoleAdmin); err != nil {
+			log.Fatal("failed to grant admin role: ", err)
+		}
+	}
+	enforcer := &policy.Enforcer{Definition: policy.Default, Roles: roleStore}
 
-	createUserUC := &usecaseuser.CreateUserUseCase{UserRepo: userRepo}
-	updateUserUC := &usecaseuser.UpdateUserUseCase{UserRepo: userRepo}
-	deleteUserUC := &usecaseuser.DeleteUserUseCase{UserRepo: userRepo}
-	findUserUC := &usecaseuser.FindUserUseCase{UserRepo: userRepo}
+	// UNDO_WINDOW é por quanto tempo as mudanças em tarefas podem ser
+	// desfeitas; "0" desliga os tokens.
+	undoWindow := 5 * time.Minute
+	if raw := os.Getenv("UNDO_WINDOW"); raw != "" {
+		if undoWindow, err = time.ParseDuration(raw); err != nil {
+			log.Fatal("invalid UNDO_WINDOW: ", err)
+		}
+	}
+
+	// REQUIRE_VERIFIED_EMAIL impede quem não confirmou o email de criar
+	// tarefas.
+	requireVerified := false
+	if raw := os.Getenv("REQUIRE_VERIFIED_EMAIL"); raw != "" {
+		if requireVerified, err = strconv.ParseBool(raw); err != nil {
+			log.Fatal("invalid REQUIRE_VERIFIED_EMAIL: ", err)
+		}
+	}
 
-	setupUC := &usecasesetup.SetupOnboardingUseCase{UoW: unitOfWork}
+	// taskOptions vale para todos os casos de uso que escrevem tarefas: a
+	// janela de undo de cada mudança e as pré-condições de todo caminho de
+	// criação (simples, lote, importação e quick-add), que são o email
+	// confirmado, se exigido, e a permissão no workspace da requisição.
+	taskOptions := usecasetask.Options{UndoWindow: undoWindow, RequireVerifiedEmail: requireVerified}
+
+	// Criar tarefas, inclusive pelo lote, importação e quick-add, é uma ação
+	// sem recurso alvo; as operações do lote sobre tarefas existentes são
+	// conferidas uma a uma pelo acesso, dentro do próprio caso de uso.
+	createTaskUC := policy.Guard[usecasetask.CreateTaskInput, *usecasetask.CreateTaskOutput](
+		&usecasetask.CreateTaskUseCase{UoW: unitOfWork, Options: taskOptions},
+		enforcer, policy.TaskCreate, policy.NoResource[usecasetask.CreateTaskInput])
+	listUC := policy.Guard[string, []usecasetask.ListTaskOutput](
+		&usecasetask.ListTaskUseCase{TaskRepo: taskRepo, WorkspaceRepo: workspaceRepo}, enforcer, policy.TaskView,
+		usecasetask.TaskListResource(func(userID string) string { return userID }))
+	updateUC := policy.Guard[usecasetask.UpdateTaskInput, *usecasetask.UpdateTaskOutput](
+		&usecasetask.UpdateTaskUseCase{UoW: unitOfWork, Options: taskOptions}, enforcer, policy.TaskUpdate,
+		usecasetask.TaskResource(unitOfWork, func(in usecasetask.UpdateTaskInput) string { return in.TaskID }))
+	updateStatusUC := policy.Guard[usecasetask.UpdateTaskStatusInput, *usecasetask.UpdateTaskStatusOutput](
+		&usecasetask.UpdateTaskStatusUseCase{UoW: unitOfWork, Options: taskOptions}, enforcer, policy.TaskUpdate,
+		usecasetask.TaskResource(unitOfWork, func(in usecasetask.UpdateTaskStatusInput) string { return in.TaskID }))
+	reorderUC := policy.Guard[usecasetask.ReorderTaskInput, *domainTask.Task](
+		&usecasetask.ReorderTaskUseCase{UoW: unitOfWork}, enforcer, policy.TaskUpdate,
+		usecasetask.TaskResource(unitOfWork, func(in usecasetask.ReorderTaskInput) string { return in.TaskID }))
+	deleteUC := policy.Guard[usecasetask.DeleteTaskInput, *usecasetask.DeleteTaskOutput](
+		&usecasetask.DeleteTaskUseCase{UoW: unitOfWork, Options: taskOptions}, enforcer, policy.TaskDelete,
+		usecasetask.TaskResource(unitOfWork, func(in usecasetask.DeleteTaskInput) string { return in.TaskID }))
+	batchUC := policy.Guard[usecasetask.BatchTaskInput, *usecasetask.BatchTaskOutput](
+		&usecasetask.BatchTaskUseCase{UoW: unitOfWork, Options: taskOptions},
+		enforcer, policy.TaskCreate, policy.NoResource[usecasetask.BatchTaskInput])
+	patchUC := policy.Guard[usecasetask.PatchTaskInput, *usecasetask.PatchTaskOutput](
+		&usecasetask.PatchTaskUseCase{UoW: unitOfWork, Options: taskOptions}, enforcer, policy.TaskUpdate,
+		usecasetask.TaskResource(unitOfWork, func(in usecasetask.PatchTaskInput) string { return in.TaskID }))
+	listEventsUC := &usecasetask.ListTaskEventsUseCase{EventRepo: taskEventRepo}
+	taskEventsUC := policy.Guard[usecasetask.ListTaskEventsInput, *usecasetask.ListTaskEventsOutput](
+		listEventsUC, enforcer, policy.TaskView,
+		usecasetask.TaskResource(unitOfWork, func(in usecasetask.ListTaskEventsInput) string { return in.TaskID }))
+	importUC := policy.Guard[usecasetask.ImportTasksInput, *usecasetask.ImportTasksOutput](
+		&usecasetask.ImportTasksUseCase{UoW: unitOfWork, Options: taskOptions},
+		enforcer, policy.TaskCreate, policy.NoResource[usecasetask.ImportTasksInput])
+	quickAddUC := policy.Guard[usecasetask.QuickAddTaskInput, *usecasetask.QuickAddTaskOutput](
+		&usecasetask.QuickAddTaskUseCase{UoW: unitOfWork, Options: taskOptions},
+		enforcer, policy.TaskCreate, policy.NoResource[usecasetask.QuickAddTaskInput])
+	exportUC := policy.Guard[usecasetask.ExportTasksInput, *usecasetask.ExportTasksOutput](
+		&usecasetask.ExportTasksUseCase{TaskRepo: taskRepo}, enforcer, policy.TaskView,
+		usecasetask.TaskListResource(func(in usecasetask.ExportTasksInput) string { return in.UserID }))
+	// As tarefas de vários donos só podem ser pedidas em nome de quem
+	// chama; quais delas cada um enxerga, o próprio caso de uso filtra.
+	tasksByUsersUC := policy.Guard[usecasetask.ListTasksByUsersInput, map[string]*usecasetask.TaskPage](
+		&usecasetask.ListTasksByUsersUseCase{TaskRepo: taskRepo}, enforcer, policy.TaskView,
+		usecasetask.TaskListResource(func(in usecasetask.ListTasksByUsersInput) string { return in.ViewerID }))
+	taskCountsUC := policy.Guard[usecasetask.CountTasksByUsersInput, map[string]map[valueobject.Status]int](
+		&usecasetask.CountTasksByUsersUseCase{TaskRepo: taskRepo}, enforcer, policy.TaskView,
+		usecasetask.TaskListResource(func(in usecasetask.CountTasksByUsersInput) string { return in.ViewerID }))
+
+	addCommentUC := policy.Guard[usecasetask.AddCommentInput, *usecasetask.AddCommentOutput](
+		&usecasetask.AddCommentUseCase{UoW: unitOfWork}, enforcer, policy.TaskView,
+		usecasetask.TaskResource(unitOfWork, func(in usecasetask.AddCommentInput) string { return in.TaskID }))
+	editCommentUC := policy.Guard[usecasetask.EditCommentInput, *usecasetask.EditCommentOutput](
+		&usecasetask.EditCommentUseCase{UoW: unitOfWork}, enforcer, policy.TaskView,
+		usecasetask.TaskResource(unitOfWork, func(in usecasetask.EditCommentInput) string { return in.TaskID }))
+	deleteCommentUC := policy.GuardCommand[usecasetask.DeleteCommentInput](
+		&usecasetask.DeleteCommentUseCase{UoW: unitOfWork}, enforcer, policy.TaskView,
+		usecasetask.TaskResource(unitOfWork, func(in usecasetask.DeleteCommentInput) string { return in.TaskID }))
+	listCommentsUC := policy.Guard[usecasetask.ListCommentsInput, *usecasetask.ListCommentsOutput](
+		&usecasetask.ListCommentsUseCase{TaskRepo: taskRepo, CommentRepo: commentRepo}, enforcer, policy.TaskView,
+		usecasetask.TaskResource(unitOfWork, func(in usecasetask.ListCommentsInput) string { return in.TaskID }))
+	activityUC := policy.Guard[usecasetask.TaskActivityInput, *usecasetask.TaskActivityOutput](
+		&usecasetask.TaskActivityUseCase{TaskRepo: taskRepo, EventRepo: taskEventRepo, CommentRepo: commentRepo}, enforcer, policy.TaskView,
+		usecasetask.TaskResource(unitOfWork, func(in usecasetask.TaskActivityInput) string { return in.TaskID }))
+
+	listRevisionsUC := policy.Guard[usecasetask.ListRevisionsInput, *usecasetask.ListRevisionsOutput](
+		&usecasetask.ListRevisionsUseCase{TaskRepo: taskRepo, RevisionRepo: revisionRepo}, enforcer, policy.TaskView,
+		usecasetask.TaskResource(unitOfWork, func(in usecasetask.ListRevisionsInput) string { return in.TaskID }))
+	diffRevisionsUC := policy.Guard[usecasetask.DiffRevisionsInput, *domainTask.RevisionDiff](
+		&usecasetask.DiffRevisionsUseCase{TaskRepo: taskRepo, RevisionRepo: revisionRepo}, enforcer, policy.TaskView,
+		usecasetask.TaskResource(unitOfWork, func(in usecasetask.DiffRevisionsInput) string { return in.TaskID }))
+	revertTaskUC := policy.Guard[usecasetask.RevertTaskInput, *usecasetask.RevertTaskOutput](
+		&usecasetask.RevertTaskUseCase{UoW: unitOfWork, Options: taskOptions}, enforcer, policy.TaskUpdate,
+		usecasetask.TaskResource(unitOfWork, func(in usecasetask.RevertTaskInput) string { return in.TaskID }))
+	// O undo fica fora da política de propósito: o token não diz a tarefa
+	// antes de ser lido, e um mesmo token pode desfazer várias. O caso de uso
+	// confere que o token é de quem chama e, passo a passo, o acesso atual a
+	// cada tarefa, como editor para restaurar e dono para desfazer exclusões.
+	undoUC := &usecasetask.UndoUseCase{UoW: unitOfWork}
+
+	// Lembretes são de cada usuário, mas só em tarefas que ele enxerga.
+	addReminderUC := policy.Guard[usecasetask.AddReminderInput, *domainTask.Reminder](
+		&usecasetask.AddReminderUseCase{UoW: unitOfWork}, enforcer, policy.TaskView,
+		usecasetask.TaskResource(unitOfWork, func(in usecasetask.AddReminderInput) string { return in.TaskID }))
+	listRemindersUC := policy.Guard[usecasetask.ListRemindersInput, []*domainTask.Reminder](
+		&usecasetask.ListRemindersUseCase{TaskRepo: taskRepo, ReminderRepo: reminderRepo}, enforcer, policy.TaskView,
+		usecasetask.TaskResource(unitOfWork, func(in usecasetask.ListRemindersInput) string { return in.TaskID }))
+	deleteReminderUC := policy.GuardCommand[usecasetask.DeleteReminderInput](
+		&usecasetask.DeleteReminderUseCase{UoW: unitOfWork}, enforcer, policy.TaskView,
+		usecasetask.TaskResource(unitOfWork, func(in usecasetask.DeleteReminderInput) string { return in.TaskID }))
+
+	// Os emails saem por SMTP com SMTP_ADDR configurado; sem ele, são
+	// gravados em MAIL_DIR ou, na falta deste, só registrados no log.
+	var mailer domainNotification.Mailer = notify.LogMailer{}
+	switch {
+	case os.Getenv("SMTP_ADDR") != "":
+		if mailer, err = notify.NewSMTPMailer(notify.SMTPConfig{
+			Addr:     os.Getenv("SMTP_ADDR"),
+			From:     cmp.Or(os.Getenv("SMTP_FROM"), "todo@localhost"),
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+		}); err != nil {
+			log.Fatal(err)
+		}
+	case os.Getenv("MAIL_DIR") != "":
+		if mailer, err = notify.NewFileMailer(os.Getenv("MAIL_DIR")); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// Os canais de notificação: caixa de entrada e webhook sempre; email só
+	// quando os emails saem de fato, por SMTP ou para MAIL_DIR.
+	channels := []domainNotification.Channel{
+		&notify.InAppChannel{Inbox: notificationRepo},
+		notify.NewWebhookChannel(os.Getenv("WEBHOOK_SECRET")),
+	}
+	if _, logOnly := mailer.(notify.LogMailer); !logOnly {
+		channels = append(channels, &notify.EmailChannel{Mailer: mailer})
+	}
+	fireRemindersUC := &usecasenotification.FireRemindersUseCase{
+		UoW:        unitOfWork,
+		Dispatcher: &usecasenotification.Dispatcher{Channels: channels},
+	}
+	getPreferencesUC := &usecasenotification.GetPreferencesUseCase{PreferenceRepo: preferenceRepo}
+	updatePreferencesUC := &usecasenotification.UpdatePreferencesUseCase{UoW: unitOfWork}
+	listNotificationsUC := &usecasenotification.ListNotificationsUseCase{InboxRepo: notificationRepo}
+	countUnreadUC := &usecasenotification.CountUnreadUseCase{InboxRepo: notificationRepo}
+	markReadUC := &usecasenotification.MarkReadUseCase{UoW: unitOfWork}
+	markUnreadUC := &usecasenotification.MarkUnreadUseCase{UoW: unitOfWork}
+
+	const maxAttachmentSize = 10 << 20
+	uploadAttachmentUC := policy.Guard[usecasetask.UploadAttachmentInput, *usecasetask.UploadAttachmentOutput](
+		&usecasetask.UploadAttachmentUseCase{
+			UoW:     unitOfWork,
+			Blobs:   blobStore,
+			MaxSize: maxAttachmentSize,
+			AllowedTypes: []string{
+				"image/png", "image/jpeg", "image/gif", "image/webp",
+				"application/pdf", "text/plain",
+			},
+		}, enforcer, policy.TaskUpdate,
+		usecasetask.TaskResource(unitOfWork, func(in usecasetask.UploadAttachmentInput) string { return in.TaskID }))
+	listAttachmentsUC := policy.Guard[usecasetask.ListAttachmentsInput, []*domainTask.Attachment](
+		&usecasetask.ListAttachmentsUseCase{TaskRepo: taskRepo, AttachmentRepo: attachmentRepo}, enforcer, policy.TaskView,
+		usecasetask.TaskResource(unitOfWork, func(in usecasetask.ListAttachmentsInput) string { return in.TaskID }))
+	openAttachmentUC := policy.Guard[usecasetask.OpenAttachmentInput, *usecasetask.OpenAttachmentOutput](
+		&usecasetask.OpenAttachmentUseCase{TaskRepo: taskRepo, AttachmentRepo: attachmentRepo, Blobs: blobStore}, enforcer, policy.TaskView,
+		usecasetask.TaskResource(unitOfWork, func(in usecasetask.OpenAttachmentInput) string { return in.TaskID }))
+	deleteAttachmentUC := policy.GuardCommand[usecasetask.DeleteAttachmentInput](
+		&usecasetask.DeleteAttachmentUseCase{UoW: unitOfWork, Blobs: blobStore}, enforcer, policy.TaskUpdate,
+		usecasetask.TaskResource(unitOfWork, func(in usecasetask.DeleteAttachmentInput) string { return in.TaskID }))
+	purgeTasksUC := &usecasetask.PurgeTasksUseCase{UoW: unitOfWork, Blobs: blobStore}
+
+	assignTaskUC := policy.Guard[usecasetask.AssignTaskInput, *usecasetask.AssignTaskOutput](
+		&usecasetask.AssignTaskUseCase{UoW: unitOfWork, Options: taskOptions}, enforcer, policy.TaskUpdate,
+		usecasetask.TaskResource(unitOfWork, func(in usecasetask.AssignTaskInput) string { return in.TaskID }))
+	assignedTasksUC := &usecasetask.ListAssignedTasksUseCase{TaskRepo: taskRepo}
+	shareTaskUC := policy.Guard[usecasetask.ShareTaskInput, *domainTask.Share](
+		&usecasetask.ShareTaskUseCase{UoW: unitOfWork}, enforcer, policy.TaskShare,
+		usecasetask.TaskResource(unitOfWork, func(in usecasetask.ShareTaskInput) string { return in.TaskID }))
+	revokeShareUC := policy.GuardCommand[usecasetask.RevokeShareInput](
+		&usecasetask.RevokeShareUseCase{UoW: unitOfWork}, enforcer, policy.TaskShare,
+		usecasetask.TaskResource(unitOfWork, func(in usecasetask.RevokeShareInput) string { return in.TaskID }))
+	listSharesUC := policy.Guard[usecasetask.ListSharesInput, []*domainTask.Share](
+		&usecasetask.ListSharesUseCase{TaskRepo: taskRepo, ShareRepo: shareRepo}, enforcer, policy.TaskView,
+		usecasetask.TaskResource(unitOfWork, func(in usecasetask.ListSharesInput) string { return in.TaskID }))
+	watchTaskUC := policy.GuardCommand[usecasetask.WatchTaskInput](
+		&usecasetask.WatchTaskUseCase{UoW: unitOfWork}, enforcer, policy.TaskView,
+		usecasetask.TaskResource(unitOfWork, func(in usecasetask.WatchTaskInput) string { return in.TaskID }))
+	unwatchTaskUC := policy.GuardCommand[usecasetask.WatchTaskInput](
+		&usecasetask.UnwatchTaskUseCase{UoW: unitOfWork}, enforcer, policy.TaskView,
+		usecasetask.TaskResource(unitOfWork, func(in usecasetask.WatchTaskInput) string { return in.TaskID }))
+	listWatchersUC := policy.Guard[usecasetask.ListWatchersInput, []*domainTask.Watcher](
+		&usecasetask.ListWatchersUseCase{TaskRepo: taskRepo, WatcherRepo: watcherRepo}, enforcer, policy.TaskView,
+		usecasetask.TaskResource(unitOfWork, func(in usecasetask.ListWatchersInput) string { return in.TaskID }))
+
+	invitationTTL := 7 * 24 * time.Hour
+	if raw := os.Getenv("INVITATION_TTL"); raw != "" {
+		if invitationTTL, err = time.ParseDuration(raw); err != nil {
+			log.Fatal("invalid INVITATION_TTL: ", err)
+		}
+	}
+	createWorkspaceUC := policy.Guard[usecaseworkspace.CreateWorkspaceInput, *domainWorkspace.Workspace](
+		&usecaseworkspace.CreateWorkspaceUseCase{UoW: unitOfWork}, enforcer, policy.WorkspaceCreate,
+		policy.NoResource[usecaseworkspace.CreateWorkspaceInput])
+	listWorkspacesUC := policy.Guard[string, []*domainWorkspace.Workspace](
+		&usecaseworkspace.ListWorkspacesUseCase{WorkspaceRepo: workspaceRepo}, enforcer, policy.WorkspaceList,
+		policy.NoResource[string])
+	getWorkspaceUC := policy.Guard[usecaseworkspace.GetWorkspaceInput, *usecaseworkspace.GetWorkspaceOutput](
+		&usecaseworkspace.GetWorkspaceUseCase{WorkspaceRepo: workspaceRepo}, enforcer, policy.WorkspaceView,
+		usecaseworkspace.WorkspaceResource(workspaceRepo, func(in usecaseworkspace.GetWorkspaceInput) string { return in.WorkspaceID }))
+	renameWorkspaceUC := policy.Guard[usecaseworkspace.RenameWorkspaceInput, *domainWorkspace.Workspace](
+		&usecaseworkspace.RenameWorkspaceUseCase{UoW: unitOfWork}, enforcer, policy.WorkspaceManage,
+		usecaseworkspace.WorkspaceResource(workspaceRepo, func(in usecaseworkspace.RenameWorkspaceInput) string { return in.WorkspaceID }))
+	deleteWorkspaceUC := policy.GuardCommand[usecaseworkspace.DeleteWorkspaceInput](
+		&usecaseworkspace.DeleteWorkspaceUseCase{UoW: unitOfWork}, enforcer, policy.WorkspaceDelete,
+		usecaseworkspace.WorkspaceResource(workspaceRepo, func(in usecaseworkspace.DeleteWorkspaceInput) string { return in.WorkspaceID }))
+	listMembersUC := policy.Guard[usecaseworkspace.ListMembersInput, []*domainWorkspace.Member](
+		&usecaseworkspace.ListMembersUseCase{WorkspaceRepo: workspaceRepo}, enforcer, policy.WorkspaceView,
+		usecaseworkspace.WorkspaceResource(workspaceRepo, func(in usecaseworkspace.ListMembersInput) string { return in.WorkspaceID }))
+	changeMemberRoleUC := policy.Guard[usecaseworkspace.ChangeMemberRoleInput, *domainWorkspace.Member](
+		&usecaseworkspace.ChangeMemberRoleUseCase{UoW: unitOfWork}, enforcer, policy.WorkspaceManage,
+		usecaseworkspace.WorkspaceResource(workspaceRepo, func(in usecaseworkspace.ChangeMemberRoleInput) string { return in.WorkspaceID }))
+	// Sair do workspace é permitido a qualquer membro, então remover exige
+	// só a participação; o caso de uso confere o papel ao remover outro.
+	removeMemberUC := policy.GuardCommand[usecaseworkspace.RemoveMemberInput](
+		&usecaseworkspace.RemoveMemberUseCase{UoW: unitOfWork}, enforcer, policy.WorkspaceView,
+		usecaseworkspace.WorkspaceResource(workspaceRepo, func(in usecaseworkspace.RemoveMemberInput) string { return in.WorkspaceID }))
+	inviteMemberUC := policy.Guard[usecaseworkspace.InviteMemberInput, *domainWorkspace.Invitation](
+		&usecaseworkspace.InviteMemberUseCase{UoW: unitOfWork, TTL: invitationTTL}, enforcer, policy.WorkspaceManage,
+		usecaseworkspace.WorkspaceResource(workspaceRepo, func(in usecaseworkspace.InviteMemberInput) string { return in.WorkspaceID }))
+	listInvitationsUC := policy.Guard[usecaseworkspace.ListInvitationsInput, []*domainWorkspace.Invitation](
+		&usecaseworkspace.ListInvitationsUseCase{WorkspaceRepo: workspaceRepo, InvitationRepo: invitationRepo}, enforcer, policy.WorkspaceManage,
+		usecaseworkspace.WorkspaceResource(workspaceRepo, func(in usecaseworkspace.ListInvitationsInput) string { return in.WorkspaceID }))
+	revokeInvitationUC := policy.GuardCommand[usecaseworkspace.RevokeInvitationInput](
+		&usecaseworkspace.RevokeInvitationUseCase{UoW: unitOfWork}, enforcer, policy.WorkspaceManage,
+		usecaseworkspace.WorkspaceResource(workspaceRepo, func(in usecaseworkspace.RevokeInvitationInput) string { return in.WorkspaceID }))
+	acceptInvitationUC := policy.Guard[usecaseworkspace.AcceptInvitationInput, *domainWorkspace.Member](
+		&usecaseworkspace.AcceptInvitationUseCase{UoW: unitOfWork}, enforcer, policy.WorkspaceJoin,
+		policy.NoResource[usecaseworkspace.AcceptInvitationInput])
+
+	// Os tokens de verificação de email são assinados com
+	// VERIFICATION_SECRET e valem por VERIFICATION_TTL. Sem o segredo, um
+	// aleatório é gerado e os links enviados deixam de valer ao reiniciar.
+	verificationSecret := []byte(os.Getenv("VERIFICATION_SECRET"))
+	if len(verificationSecret) == 0 {
+		verificationSecret = make([]byte, 32)
+		if _, err := rand.Read(verificationSecret); err != nil {
+			log.Fatal(err)
+		}
+		log.Println("VERIFICATION_SECRET not set; verification links will not survive a restart")
+	}
+	verificationTTL := 24 * time.Hour
+	if raw := os.Getenv("VERIFICATION_TTL"); raw != "" {
+		if verificationTTL, err = time.ParseDuration(raw); err != nil {
+			log.Fatal("invalid VERIFICATION_TTL: ", err)
+		}
+	}
+	// VERIFICATION_RESEND_INTERVAL é o intervalo mínimo entre dois emails de
+	// verificação para o mesmo usuário.
+	resendInterval := time.Minute
+	if raw := os.Getenv("VERIFICATION_RESEND_INTERVAL"); raw != "" {
+		if resendInterval, err = time.ParseDuration(raw); err != nil {
+			log.Fatal("invalid VERIFICATION_RESEND_INTERVAL: ", err)
+		}
+	}
+	verifier := domainUser.NewVerifier(verificationSecret, verificationTTL)
+	verificationSender := &usecaseuser.VerificationSender{
+		Verifier: verifier,
+		Mailer:   mailer,
+		BaseURL:  cmp.Or(os.Getenv("APP_BASE_URL"), "http://localhost:8080"),
+	}
+	verifyEmailUC := &usecaseuser.VerifyEmailUseCase{UoW: unitOfWork, Verifier: verifier}
+	resendVerificationUC := &usecaseuser.ResendVerificationUseCase{UoW: unitOfWork, Sender: verificationSender, Interval: resendInterval}
+
+	createUserUC := &usecaseuser.CreateUserUseCase{UoW: unitOfWork, Verification: verificationSender}
+	updateUserUC := policy.Guard[usecaseuser.UpdateUserInput, *usecaseuser.UpdateUserOutput](
+		&usecaseuser.UpdateUserUseCase{UoW: unitOfWork}, enforcer, policy.UserUpdate,
+		usecaseuser.UserResource(func(in usecaseuser.UpdateUserInput) string { return in.ID }))
+	deleteUserUC := policy.GuardCommand[usecaseuser.DeleteUserInput](
+		&usecaseuser.DeleteUserUseCase{UoW: unitOfWork}, enforcer, policy.UserDelete,
+		usecaseuser.UserResource(func(in usecaseuser.DeleteUserInput) string { return in.ID }))
+	findUserUC := policy.Guard[usecaseuser.FindUserInput, *usecaseuser.FindUserOutput](
+		&usecaseuser.FindUserUseCase{UserRepo: userRepo}, enforcer, policy.UserView,
+		usecaseuser.UserResource(func(in usecaseuser.FindUserInput) string { return in.ID }))
+	patchUserUC := policy.Guard[usecaseuser.PatchUserInput, *usecaseuser.PatchUserOutput](
+		&usecaseuser.PatchUserUseCase{UoW: unitOfWork}, enforcer, policy.UserUpdate,
+		usecaseuser.UserResource(func(in usecaseuser.PatchUserInput) string { return in.ID }))
+	listUsersUC := policy.Guard[usecaseuser.ListUserInput, *usecaseuser.ListUserOutput](
+		&usecaseuser.ListUserUseCase{UserRepo: userRepo}, enforcer, policy.UserList,
+		policy.NoResource[usecaseuser.ListUserInput])
+	findUsersUC := policy.Guard[[]string, map[string]*domainUser.User](
+		&usecaseuser.FindUsersUseCase{UserRepo: userRepo}, enforcer, policy.UserView,
+		policy.NoResource[[]string])
+
+	listRolesUC := policy.Guard[usecaseuser.ListRolesInput, *usecaseuser.ListRolesOutput](
+		&usecaseuser.ListRolesUseCase{UserRepo: userRepo, Roles: roleStore}, enforcer, policy.AdminRoles,
+		policy.NoResource[usecaseuser.ListRolesInput])
+	grantRoleUC := policy.GuardCommand[usecaseuser.ChangeRoleInput](
+		&usecaseuser.GrantRoleUseCase{UoW: unitOfWork}, enforcer, policy.AdminRoles,
+		policy.NoResource[usecaseuser.ChangeRoleInput])
+	revokeRoleUC := policy.GuardCommand[usecaseuser.ChangeRoleInput](
+		&usecaseuser.RevokeRoleUseCase{UoW: unitOfWork}, enforcer, policy.AdminRoles,
+		policy.NoResource[usecaseuser.ChangeRoleInput])
+
+	listAuditUC := policy.Guard[usecaseaudit.ListEntriesInput, *usecaseaudit.ListEntriesOutput](
+		&usecaseaudit.ListEntriesUseCase{AuditRepo: auditRepo}, enforcer, policy.AdminAudit,
+		policy.NoResource[usecaseaudit.ListEntriesInput])
+
+	setupUC := &usecasesetup.SetupOnboardingUseCase{UoW: unitOfWork, Verification: verificationSender}
 
 	validate := validator.New()
 
@@ -44,23 +447,340 @@ func main() {
 		UpdateUC:       updateUC,
 		UpdateStatusUC: updateStatusUC,
 		DeleteUC:       deleteUC,
+		BatchUC:        batchUC,
+		PatchUC:        patchUC,
+		ExportUC:       exportUC,
+		ImportUC:       importUC,
+		QuickAddUC:     quickAddUC,
+		Validate:       validate,
+	}
+
+	commentHandler := &handler.CommentHandler{
+		AddUC:      addCommentUC,
+		EditUC:     editCommentUC,
+		DeleteUC:   deleteCommentUC,
+		ListUC:     listCommentsUC,
+		ActivityUC: activityUC,
+		Validate:   validate,
+	}
+	revisionHandler := &handler.RevisionHandler{
+		ListUC:   listRevisionsUC,
+		DiffUC:   diffRevisionsUC,
+		RevertUC: revertTaskUC,
+		Validate: validate,
+	}
+	undoHandler := &handler.UndoHandler{UndoUC: undoUC}
+	reminderHandler := &handler.ReminderHandler{
+		AddUC:    addReminderUC,
+		ListUC:   listRemindersUC,
+		DeleteUC: deleteReminderUC,
+		Validate: validate,
+	}
+	notificationHandler := &handler.NotificationHandler{
+		ListUC:              listNotificationsUC,
+		CountUnreadUC:       countUnreadUC,
+		MarkReadUC:          markReadUC,
+		MarkUnreadUC:        markUnreadUC,
+		GetPreferencesUC:    getPreferencesUC,
+		UpdatePreferencesUC: updatePreferencesUC,
+		Validate:            validate,
+	}
+
+	attachmentHandler := &handler.AttachmentHandler{
+		UploadUC: uploadAttachmentUC,
+		ListUC:   listAttachmentsUC,
+		OpenUC:   openAttachmentUC,
+		DeleteUC: deleteAttachmentUC,
+		MaxSize:  maxAttachmentSize,
+	}
+
+	sharingHandler := &handler.SharingHandler{
+		AssignUC:       assignTaskUC,
+		AssignedUC:     assignedTasksUC,
+		ShareUC:        shareTaskUC,
+		RevokeUC:       revokeShareUC,
+		ListSharesUC:   listSharesUC,
+		WatchUC:        watchTaskUC,
+		UnwatchUC:      unwatchTaskUC,
+		ListWatchersUC: listWatchersUC,
 		Validate:       validate,
 	}
 
+	workspaceHandler := &handler.WorkspaceHandler{
+		CreateUC:           createWorkspaceUC,
+		ListUC:             listWorkspacesUC,
+		GetUC:              getWorkspaceUC,
+		RenameUC:           renameWorkspaceUC,
+		DeleteUC:           deleteWorkspaceUC,
+		ListMembersUC:      listMembersUC,
+		ChangeRoleUC:       changeMemberRoleUC,
+		RemoveMemberUC:     removeMemberUC,
+		InviteUC:           inviteMemberUC,
+		ListInvitationsUC:  listInvitationsUC,
+		RevokeInvitationUC: revokeInvitationUC,
+		AcceptInvitationUC: acceptInvitationUC,
+		Validate:           validate,
+	}
+
 	userHandler := &handler.UserHandler{
 		CreateUC: createUserUC,
 		UpdateUC: updateUserUC,
 		DeleteUC: deleteUserUC,
 		FindUC:   findUserUC,
+		PatchUC:  patchUserUC,
 		Validate: validate,
 	}
 
+	adminHandler := &handler.AdminHandler{
+		ListRolesUC:  listRolesUC,
+		GrantRoleUC:  grantRoleUC,
+		RevokeRoleUC: revokeRoleUC,
+		ListAuditUC:  listAuditUC,
+		Validate:     validate,
+	}
+
 	setupHandler := &handler.OnboardingHandler{
 		SetupUC:  setupUC,
+		VerifyUC: verifyEmailUC,
+		ResendUC: resendVerificationUC,
 		Validate: validate,
 	}
 
-	router := api.SetupRouter(taskHandler, userHandler, setupHandler)
-	log.Println("Server running on :8080")
-	router.Run(":8080")
+	eventHandler := &handler.EventHandler{
+		ListUC:       listEventsUC,
+		Broker:       eventBroker,
+		Heartbeat:    15 * time.Second,
+		BatchSize:    100,
+		WriteTimeout: 10 * time.Second,
+	}
+
+	// WS_ALLOWED_ORIGINS lista, separadas por vírgula, as origens de
+	// navegador aceitas no WebSocket além da do próprio serviço.
+	var allowedOrigins []string
+	for _, origin := range strings.Split(os.Getenv("WS_ALLOWED_ORIGINS"), ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			allowedOrigins = append(allowedOrigins, origin)
+		}
+	}
+	realtimeHandler := &handler.RealtimeHandler{
+		ListEventsUC:   listEventsUC,
+		TaskEventsUC:   taskEventsUC,
+		GetWorkspaceUC: getWorkspaceUC,
+		UpdateStatusUC: updateStatusUC,
+		ReorderUC:      reorderUC,
+		Broker:         eventBroker,
+		Presence:       presence,
+		AllowedOrigins: allowedOrigins,
+		SendBuffer:     64,
+		WriteTimeout:   10 * time.Second,
+		BatchSize:      100,
+	}
+
+	graphqlServer, err := graphql.NewServer(&graphql.Resolver{
+		FindUsersUC:    findUsersUC,
+		FindUserUC:     findUserUC,
+		ListUsersUC:    listUsersUC,
+		CreateUserUC:   createUserUC,
+		UpdateUserUC:   updateUserUC,
+		DeleteUserUC:   deleteUserUC,
+		TasksByUsersUC: tasksByUsersUC,
+		TaskCountsUC:   taskCountsUC,
+		CreateTaskUC:   createTaskUC,
+		UpdateTaskUC:   updateUC,
+		UpdateStatusUC: updateStatusUC,
+		DeleteTaskUC:   deleteUC,
+		SetupUC:        setupUC,
+	}, graphql.Limits{MaxDepth: 8, MaxComplexity: 1000})
+	if err != nil {
+		log.Fatal(err)
+	}
+	graphqlHandler := &handler.GraphQLHandler{Server: graphqlServer}
+
+	var rateLimitStore ratelimit.Store = ratelimit.NewMemoryStore()
+	if os.Getenv("RATE_LIMIT_STORE") == "sqlite" {
+		rateLimitStore = sqlite.NewSQLiteRateLimitStore(db)
+	}
+	limiter := ratelimit.NewLimiter(rateLimitStore, map[string]ratelimit.Policy{
+		"POST /api/v1/tasks":                   {Name: "create_task", Limit: 30, Window: time.Minute},
+		"POST /api/v1/onboarding":              {Name: "onboarding", Limit: 5, Window: time.Minute},
+		"POST /api/v1/onboarding/verification": {Name: "verification", Limit: 5, Window: time.Minute},
+	}, &ratelimit.Policy{Name: "default", Limit: 300, Window: time.Minute})
+
+	idempotencyTTL := 24 * time.Hour
+	if raw := os.Getenv("IDEMPOTENCY_TTL"); raw != "" {
+		if idempotencyTTL, err = time.ParseDuration(raw); err != nil {
+			log.Fatal("invalid IDEMPOTENCY_TTL: ", err)
+		}
+	}
+
+	// HTTP_WRITE_TIMEOUT limita o tempo até o fim da resposta, contado desde
+	// a leitura dos headers. Os streams (SSE e WebSocket) renovam o prazo a
+	// cada mensagem.
+	writeTimeout := 30 * time.Second
+	if raw := os.Getenv("HTTP_WRITE_TIMEOUT"); raw != "" {
+		if writeTimeout, err = time.ParseDuration(raw); err != nil || writeTimeout <= 0 {
+			log.Fatal("invalid HTTP_WRITE_TIMEOUT: ", raw)
+		}
+	}
+
+	// IDEMPOTENCY_IN_FLIGHT_TIMEOUT é quando uma chave ainda em processamento
+	// passa a ser considerada abandonada. Precisa cobrir a requisição mais
+	// longa, limitada pelo HTTP_WRITE_TIMEOUT.
+	idempotencyInFlight := max(time.Minute, writeTimeout)
+	if raw := os.Getenv("IDEMPOTENCY_IN_FLIGHT_TIMEOUT"); raw != "" {
+		if idempotencyInFlight, err = time.ParseDuration(raw); err != nil {
+			log.Fatal("invalid IDEMPOTENCY_IN_FLIGHT_TIMEOUT: ", err)
+		}
+	}
+	if idempotencyInFlight < writeTimeout {
+		log.Fatalf("IDEMPOTENCY_IN_FLIGHT_TIMEOUT (%s) must be at least HTTP_WRITE_TIMEOUT (%s)", idempotencyInFlight, writeTimeout)
+	}
+	idempotencyStore := sqlite.NewSQLiteIdempotencyStore(db, idempotencyTTL, idempotencyInFlight)
+
+	// TRUSTED_PROXIES lista, separados por vírgula, os IPs ou CIDRs dos
+	// proxies à frente do serviço. Sem ele, X-Forwarded-For é ignorado.
+	var trustedProxies []string
+	for _, proxy := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			trustedProxies = append(trustedProxies, proxy)
+		}
+	}
+
+	router, err := api.SetupRouter(taskHandler, userHandler, setupHandler, eventHandler, realtimeHandler, graphqlHandler, commentHandler, revisionHandler, undoHandler, reminderHandler, notificationHandler, attachmentHandler, sharingHandler, workspaceHandler, adminHandler, appMetrics, limiter, idempotencyStore, writeTimeout, trustedProxies)
+	if err != nil {
+		log.Fatal("invalid TRUSTED_PROXIES: ", err)
+	}
+
+	// Conexões longas (SSE) são encerradas no shutdown pelo cancelamento do
+	// contexto base das requisições.
+	baseCtx, cancelRequests := context.WithCancel(context.Background())
+	srv := &http.Server{
+		Addr:         ":8080",
+		Handler:      router,
+		WriteTimeout: writeTimeout,
+		BaseContext:  func(net.Listener) context.Context { return baseCtx },
+	}
+	srv.RegisterOnShutdown(cancelRequests)
+
+	// Tarefas excluídas há mais de TASK_PURGE_AFTER são removidas de vez,
+	// junto com seus anexos.
+	if raw := os.Getenv("TASK_PURGE_AFTER"); raw != "" {
+		purgeAfter, err := time.ParseDuration(raw)
+		if err != nil {
+			log.Fatal("invalid TASK_PURGE_AFTER: ", err)
+		}
+		go purgeTasks(baseCtx, purgeTasksUC, purgeAfter)
+	}
+
+	// REMINDER_INTERVAL é de quanto em quanto tempo o agendador procura
+	// lembretes vencidos.
+	reminderInterval := time.Minute
+	if raw := os.Getenv("REMINDER_INTERVAL"); raw != "" {
+		if reminderInterval, err = time.ParseDuration(raw); err != nil || reminderInterval <= 0 {
+			log.Fatal("invalid REMINDER_INTERVAL: ", raw)
+		}
+	}
+	go fireReminders(baseCtx, fireRemindersUC, reminderInterval)
+	go func() {
+		log.Println("Server running on :8080")
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal(err)
+		}
+	}()
+
+	grpcServer := grpcadapter.NewServer(slog.Default(),
+		&grpcadapter.TaskService{
+			CreateUC:       createTaskUC,
+			ListUC:         listUC,
+			UpdateUC:       updateUC,
+			UpdateStatusUC: updateStatusUC,
+			DeleteUC:       deleteUC,
+			ListEventsUC:   listEventsUC,
+			Broker:         eventBroker,
+		},
+		&grpcadapter.UserService{
+			CreateUC: createUserUC,
+			FindUC:   findUserUC,
+			UpdateUC: updateUserUC,
+			DeleteUC: deleteUserUC,
+		},
+		&grpcadapter.OnboardingService{SetupUC: setupUC},
+	)
+	grpcAddr := os.Getenv("GRPC_ADDR")
+	if grpcAddr == "" {
+		grpcAddr = ":9090"
+	}
+	grpcListener, err := net.Listen("tcp", grpcAddr)
+	if err != nil {
+		log.Fatal(err)
+	}
+	go func() {
+		log.Println("gRPC server running on " + grpcAddr)
+		if err := grpcServer.Serve(grpcListener); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	<-ctx.Done()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Println("server shutdown:", err)
+	}
+
+	// Streams abertos (WatchTasks) impedem o GracefulStop de terminar; após o
+	// prazo eles são encerrados à força.
+	grpcStopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(grpcStopped)
+	}()
+	select {
+	case <-grpcStopped:
+	case <-shutdownCtx.Done():
+		grpcServer.Stop()
+	}
+}
+
+// purgeTasks roda o expurgo a cada hora, em lotes, até o contexto ser
+// cancelado.
+func purgeTasks(ctx context.Context, uc *usecasetask.PurgeTasksUseCase, after time.Duration) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+	for {
+		for {
+			out, err := uc.Execute(ctx, usecasetask.PurgeTasksInput{DeletedBefore: time.Now().Add(-after), Limit: 100})
+			if err != nil || out.Purged < 100 {
+				break
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// fireReminders dispara os lembretes vencidos a cada interval, em lotes,
+// até o contexto ser cancelado.
+func fireReminders(ctx context.Context, uc *usecasenotification.FireRemindersUseCase, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		for {
+			out, err := uc.Execute(ctx, usecasenotification.FireRemindersInput{Now: time.Now(), Limit: 100})
+			if err != nil || out.Fired+out.Postponed+out.Skipped < 100 {
+				break
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
 }
@@ -0,0 +1,39 @@
+// verify-audit percorre o log de auditoria conferindo o encadeamento de
+// hashes. Sai com código 1 se alguma entrada foi adulterada, removida ou
+// inserida fora de ordem.
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/hoyci/todo-ddd/internal/adapters/db/sqlite"
+	domainAudit "github.com/hoyci/todo-ddd/pkg/domain/audit"
+	usecaseaudit "github.com/hoyci/todo-ddd/pkg/usecase/audit"
+)
+
+func main() {
+	db, err := sqlite.InitDB()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	verifyUC := &usecaseaudit.VerifyChainUseCase{
+		AuditRepo: sqlite.NewSQLiteAuditRepository(db),
+		BatchSize: 500,
+	}
+	out, err := verifyUC.Execute(context.Background())
+	if errors.Is(err, domainAudit.ErrChainBroken) {
+		fmt.Fprintf(os.Stderr, "%v (after %d valid entries)\n", err, out.Entries)
+		os.Exit(1)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Printf("audit chain ok: %d entries, last hash %s\n", out.Entries, out.LastHash)
+}
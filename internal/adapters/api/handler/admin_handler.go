@@ -0,0 +1,263 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/hoyci/todo-ddd/internal/adapters/api/middleware"
+	domainAudit "github.com/hoyci/todo-ddd/pkg/domain/audit"
+	"github.com/hoyci/todo-ddd/pkg/policy"
+	"github.com/hoyci/todo-ddd/pkg/usecase"
+	usecaseaudit "github.com/hoyci/todo-ddd/pkg/usecase/audit"
+	usecaseuser "github.com/hoyci/todo-ddd/pkg/usecase/user"
+)
+
+// defaultAuditPageSize é usado quando a consulta ao log não informa limit.
+const defaultAuditPageSize = 50
+
+// AdminHandler expõe a administração de papéis globais e o log de
+// auditoria. Os casos de uso chegam decorados pela política, que só os
+// libera a admins.
+type AdminHandler struct {
+	ListRolesUC  policy.UseCase[usecaseuser.ListRolesInput, *usecaseuser.ListRolesOutput]
+	GrantRoleUC  policy.Command[usecaseuser.ChangeRoleInput]
+	RevokeRoleUC policy.Command[usecaseuser.ChangeRoleInput]
+	ListAuditUC  policy.UseCase[usecaseaudit.ListEntriesInput, *usecaseaudit.ListEntriesOutput]
+	Validate     *validator.Validate
+}
+
+//
+// ------------------- ROLES -------------------
+//
+
+// @Summary List a user's roles
+// @Description Lists the roles explicitly granted to a user. Every identified user also has the
+// @Description implicit "user" role. Requires the admin role.
+// @Tags admin
+// @Produce json
+// @Param X-User-ID header string true "User ID"
+// @Param id path string true "User ID"
+// @Success 200 {object} UserRolesResponse
+// @Failure 401 {object} TaskErrorResponse
+// @Failure 403 {object} TaskErrorResponse
+// @Failure 404 {object} TaskErrorResponse "User not found"
+// @Failure 500 {object} TaskErrorResponse
+// @Router /api/v1/admin/users/{id}/roles [get]
+func (h *AdminHandler) ListRoles(c *gin.Context) {
+	if _, ok := middleware.UserID(c); !ok {
+		c.JSON(http.StatusUnauthorized, TaskErrorResponse{Error: "missing or invalid " + middleware.UserIDHeader + " header"})
+		return
+	}
+
+	out, err := h.ListRolesUC.Execute(c.Request.Context(), usecaseuser.ListRolesInput{UserID: c.Param("id")})
+	if err != nil {
+		adminError(c, err)
+		return
+	}
+
+	roles := make([]string, 0, len(out.Roles))
+	for _, role := range out.Roles {
+		roles = append(roles, string(role))
+	}
+	c.JSON(http.StatusOK, UserRolesResponse{UserID: c.Param("id"), Roles: roles})
+}
+
+// @Summary Grant a role
+// @Description Grants a global role to a user. Granting a role the user already has is a no-op.
+// @Description Requires the admin role.
+// @Tags admin
+// @Param X-User-ID header string true "User ID"
+// @Param id path string true "User ID"
+// @Param role path string true "Role" Enums(user, admin)
+// @Success 204 "No Content"
+// @Failure 401 {object} TaskErrorResponse
+// @Failure 403 {object} TaskErrorResponse
+// @Failure 404 {object} TaskErrorResponse "User not found"
+// @Failure 422 {object} TaskErrorResponse "Unknown role"
+// @Failure 500 {object} TaskErrorResponse
+// @Router /api/v1/admin/users/{id}/roles/{role} [put]
+func (h *AdminHandler) GrantRole(c *gin.Context) {
+	if _, ok := middleware.UserID(c); !ok {
+		c.JSON(http.StatusUnauthorized, TaskErrorResponse{Error: "missing or invalid " + middleware.UserIDHeader + " header"})
+		return
+	}
+
+	err := h.GrantRoleUC.Execute(c.Request.Context(), usecaseuser.ChangeRoleInput{
+		UserID: c.Param("id"),
+		Role:   c.Param("role"),
+	})
+	if err != nil {
+		adminError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Revoke a role
+// @Description Revokes a global role from a user. Revoking a role the user does not have is a no-op.
+// @Description Requires the admin role.
+// @Tags admin
+// @Param X-User-ID header string true "User ID"
+// @Param id path string true "User ID"
+// @Param role path string true "Role" Enums(user, admin)
+// @Success 204 "No Content"
+// @Failure 401 {object} TaskErrorResponse
+// @Failure 403 {object} TaskErrorResponse
+// @Failure 422 {object} TaskErrorResponse "Unknown role"
+// @Failure 500 {object} TaskErrorResponse
+// @Router /api/v1/admin/users/{id}/roles/{role} [delete]
+func (h *AdminHandler) RevokeRole(c *gin.Context) {
+	if _, ok := middleware.UserID(c); !ok {
+		c.JSON(http.StatusUnauthorized, TaskErrorResponse{Error: "missing or invalid " + middleware.UserIDHeader + " header"})
+		return
+	}
+
+	err := h.RevokeRoleUC.Execute(c.Request.Context(), usecaseuser.ChangeRoleInput{
+		UserID: c.Param("id"),
+		Role:   c.Param("role"),
+	})
+	if err != nil {
+		adminError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+//
+// ------------------- AUDIT -------------------
+//
+
+// @Summary Query the audit log
+// @Description Lists audit log entries, oldest first, optionally filtered by actor, entity and
+// @Description time range (from inclusive, to exclusive). Pass next_after as after to get the next
+// @Description page. Requires the admin role.
+// @Tags admin
+// @Produce json
+// @Param X-User-ID header string true "User ID"
+// @Param actor_id query string false "Actor user ID"
+// @Param entity_type query string false "Entity type" Enums(task, comment, attachment, user, workspace)
+// @Param entity_id query string false "Entity ID"
+// @Param from query string false "Start of the range (RFC 3339)"
+// @Param to query string false "End of the range (RFC 3339)"
+// @Param after query int false "Sequence number from the previous page"
+// @Param limit query int false "Page size (1-200)" default(50)
+// @Success 200 {object} AuditPageResponse
+// @Failure 400 {object} TaskErrorResponse
+// @Failure 401 {object} TaskErrorResponse
+// @Failure 403 {object} TaskErrorResponse
+// @Failure 500 {object} TaskErrorResponse
+// @Router /api/v1/admin/audit [get]
+func (h *AdminHandler) ListAudit(c *gin.Context) {
+	if _, ok := middleware.UserID(c); !ok {
+		c.JSON(http.StatusUnauthorized, TaskErrorResponse{Error: "missing or invalid " + middleware.UserIDHeader + " header"})
+		return
+	}
+
+	var req ListAuditRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, TaskErrorResponse{Error: err.Error()})
+		return
+	}
+	if err := h.Validate.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, TaskErrorResponse{Error: err.Error()})
+		return
+	}
+	if req.Limit == 0 {
+		req.Limit = defaultAuditPageSize
+	}
+
+	out, err := h.ListAuditUC.Execute(c.Request.Context(), usecaseaudit.ListEntriesInput{
+		Query: domainAudit.Query{
+			ActorID:    req.ActorID,
+			EntityType: req.EntityType,
+			EntityID:   req.EntityID,
+			From:       req.From,
+			To:         req.To,
+			AfterSeq:   req.After,
+			Limit:      req.Limit,
+		},
+	})
+	if err != nil {
+		adminError(c, err)
+		return
+	}
+
+	resp := AuditPageResponse{
+		Entries:   make([]AuditEntryResponse, 0, len(out.Entries)),
+		NextAfter: out.NextSeq,
+	}
+	for _, entry := range out.Entries {
+		resp.Entries = append(resp.Entries, AuditEntryResponse{
+			Seq:        entry.Seq,
+			OccurredAt: entry.OccurredAt,
+			ActorID:    entry.ActorID,
+			Action:     entry.Action,
+			EntityType: entry.EntityType,
+			EntityID:   entry.EntityID,
+			Changes:    entry.Changes,
+			RequestID:  entry.RequestID,
+			IP:         entry.IP,
+			PrevHash:   entry.PrevHash,
+			Hash:       entry.Hash,
+		})
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func adminError(c *gin.Context, err error) {
+	switch usecase.ErrorKind(err) {
+	case usecase.ErrorKindValidation:
+		c.JSON(http.StatusUnprocessableEntity, TaskErrorResponse{Error: err.Error()})
+	case usecase.ErrorKindNotFound:
+		c.JSON(http.StatusNotFound, TaskErrorResponse{Error: err.Error()})
+	case usecase.ErrorKindForbidden:
+		c.JSON(http.StatusForbidden, TaskErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, TaskErrorResponse{Error: usecase.ErrUnknown.Error()})
+	}
+}
+
+//
+// ------------------- REQUESTS / RESPONSES -------------------
+//
+
+type ListAuditRequest struct {
+	ActorID    string    `form:"actor_id"`
+	EntityType string    `form:"entity_type"`
+	EntityID   string    `form:"entity_id"`
+	From       time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To         time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	After      int64     `form:"after" validate:"omitempty,min=0"`
+	Limit      int       `form:"limit" validate:"omitempty,min=1,max=200"`
+}
+
+type AuditEntryResponse struct {
+	Seq        int64     `json:"seq" example:"42"`
+	OccurredAt time.Time `json:"occurred_at"`
+	// ActorID é vazio nas operações do sistema, como a limpeza de tarefas.
+	ActorID    string `json:"actor_id"`
+	Action     string `json:"action" example:"task.updated"`
+	EntityType string `json:"entity_type" example:"task"`
+	EntityID   string `json:"entity_id"`
+	// Changes mapeia cada campo alterado para os valores antes e depois.
+	Changes   json.RawMessage `json:"changes" swaggertype:"object"`
+	RequestID string          `json:"request_id"`
+	IP        string          `json:"ip"`
+	PrevHash  string          `json:"prev_hash"`
+	Hash      string          `json:"hash"`
+}
+
+type AuditPageResponse struct {
+	Entries   []AuditEntryResponse `json:"entries"`
+	NextAfter int64                `json:"next_after,omitempty"`
+}
+
+type UserRolesResponse struct {
+	UserID string   `json:"user_id" example:"6f1c2e7a-8d3b-4b52-9a57-0c1f4e2d9b10"`
+	Roles  []string `json:"roles" example:"admin"`
+}
@@ -0,0 +1,273 @@
+package handler
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/gabriel-vasile/mimetype"
+	"github.com/gin-gonic/gin"
+	"github.com/hoyci/todo-ddd/internal/adapters/api/middleware"
+	domainTask "github.com/hoyci/todo-ddd/pkg/domain/task"
+	"github.com/hoyci/todo-ddd/pkg/policy"
+	usecase "github.com/hoyci/todo-ddd/pkg/usecase"
+	usecasetask "github.com/hoyci/todo-ddd/pkg/usecase/task"
+)
+
+// multipartOverhead é a folga, além do tamanho máximo do arquivo, para os
+// cabeçalhos e delimitadores do corpo multipart.
+const multipartOverhead = 64 << 10
+
+type AttachmentHandler struct {
+	UploadUC policy.UseCase[usecasetask.UploadAttachmentInput, *usecasetask.UploadAttachmentOutput]
+	ListUC   policy.UseCase[usecasetask.ListAttachmentsInput, []*domainTask.Attachment]
+	OpenUC   policy.UseCase[usecasetask.OpenAttachmentInput, *usecasetask.OpenAttachmentOutput]
+	DeleteUC policy.Command[usecasetask.DeleteAttachmentInput]
+	// MaxSize é o mesmo limite do UploadAttachmentUseCase, usado para
+	// recusar o corpo antes de lê-lo inteiro.
+	MaxSize int64
+}
+
+//
+// ------------------- UPLOAD -------------------
+//
+
+// @Summary Attach a file to a task
+// @Description Uploads a file as multipart/form-data (field "file"). The type is detected from the
+// @Description content, not from the file name or the part header. Files with the same content are
+// @Description stored once.
+// @Tags attachments
+// @Accept multipart/form-data
+// @Produce json
+// @Param X-User-ID header string true "User ID"
+// @Param id path string true "Task ID"
+// @Param file formData file true "File"
+// @Success 201 {object} UploadAttachmentResponse
+// @Failure 400 {object} TaskErrorResponse
+// @Failure 401 {object} TaskErrorResponse
+// @Failure 403 {object} TaskErrorResponse
+// @Failure 404 {object} TaskErrorResponse "Task not found"
+// @Failure 413 {object} TaskErrorResponse
+// @Failure 415 {object} TaskErrorResponse
+// @Failure 422 {object} TaskErrorResponse
+// @Failure 500 {object} TaskErrorResponse
+// @Router /api/v1/tasks/{id}/attachments [post]
+func (h *AttachmentHandler) Upload(c *gin.Context) {
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, TaskErrorResponse{Error: "missing or invalid " + middleware.UserIDHeader + " header"})
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.MaxSize+multipartOverhead)
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, TaskErrorResponse{Error: usecase.ErrAttachmentTooLarge.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, TaskErrorResponse{Error: err.Error()})
+		return
+	}
+	defer file.Close()
+
+	detected, err := mimetype.DetectReader(file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, TaskErrorResponse{Error: err.Error()})
+		return
+	}
+	if _, err := file.Seek(0, 0); err != nil {
+		c.JSON(http.StatusInternalServerError, TaskErrorResponse{Error: usecase.ErrUnknown.Error()})
+		return
+	}
+
+	out, err := h.UploadUC.Execute(c.Request.Context(), usecasetask.UploadAttachmentInput{
+		TaskID:      c.Param("id"),
+		UserID:      userID,
+		FileName:    header.Filename,
+		ContentType: detected.String(),
+		Content:     file,
+	})
+	if err != nil {
+		attachmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, UploadAttachmentResponse{
+		AttachmentResponse: newAttachmentResponse(out.Attachment),
+		Deduplicated:       out.Deduplicated,
+	})
+}
+
+//
+// ------------------- LIST -------------------
+//
+
+// @Summary List attachments
+// @Description Lists the attachments of a task, oldest first.
+// @Tags attachments
+// @Produce json
+// @Param X-User-ID header string true "User ID"
+// @Param id path string true "Task ID"
+// @Success 200 {array} AttachmentResponse
+// @Failure 401 {object} TaskErrorResponse
+// @Failure 404 {object} TaskErrorResponse "Task not found"
+// @Failure 500 {object} TaskErrorResponse
+// @Router /api/v1/tasks/{id}/attachments [get]
+func (h *AttachmentHandler) List(c *gin.Context) {
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, TaskErrorResponse{Error: "missing or invalid " + middleware.UserIDHeader + " header"})
+		return
+	}
+
+	attachments, err := h.ListUC.Execute(c.Request.Context(), usecasetask.ListAttachmentsInput{
+		TaskID: c.Param("id"),
+		UserID: userID,
+	})
+	if err != nil {
+		attachmentError(c, err)
+		return
+	}
+
+	resp := make([]AttachmentResponse, 0, len(attachments))
+	for _, attachment := range attachments {
+		resp = append(resp, newAttachmentResponse(attachment))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+//
+// ------------------- DOWNLOAD -------------------
+//
+
+// @Summary Download an attachment
+// @Description Streams the attachment content. Supports Range requests (206 Partial Content) and
+// @Description conditional requests with the content hash as ETag.
+// @Tags attachments
+// @Produce octet-stream
+// @Param X-User-ID header string true "User ID"
+// @Param id path string true "Task ID"
+// @Param attachment_id path string true "Attachment ID"
+// @Param Range header string false "Byte range, e.g. bytes=0-1023"
+// @Success 200 {file} file
+// @Success 206 {file} file "Partial content"
+// @Failure 401 {object} TaskErrorResponse
+// @Failure 404 {object} TaskErrorResponse "Task or attachment not found"
+// @Failure 416 "Range not satisfiable"
+// @Failure 500 {object} TaskErrorResponse
+// @Router /api/v1/tasks/{id}/attachments/{attachment_id} [get]
+func (h *AttachmentHandler) Download(c *gin.Context) {
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, TaskErrorResponse{Error: "missing or invalid " + middleware.UserIDHeader + " header"})
+		return
+	}
+
+	out, err := h.OpenUC.Execute(c.Request.Context(), usecasetask.OpenAttachmentInput{
+		TaskID:       c.Param("id"),
+		AttachmentID: c.Param("attachment_id"),
+		UserID:       userID,
+	})
+	if err != nil {
+		attachmentError(c, err)
+		return
+	}
+	defer out.Content.Close()
+
+	c.Header("Content-Type", out.ContentType)
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": out.FileName}))
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("ETag", `"`+out.Hash+`"`)
+	// ServeContent trata Range, If-Range e If-None-Match.
+	http.ServeContent(c.Writer, c.Request, out.FileName, out.CreatedAt, out.Content)
+}
+
+//
+// ------------------- DELETE -------------------
+//
+
+// @Summary Delete an attachment
+// @Description Removes the attachment; its content is deleted once no other attachment uses it.
+// @Tags attachments
+// @Param X-User-ID header string true "User ID"
+// @Param id path string true "Task ID"
+// @Param attachment_id path string true "Attachment ID"
+// @Success 204 "No Content"
+// @Failure 401 {object} TaskErrorResponse
+// @Failure 403 {object} TaskErrorResponse
+// @Failure 404 {object} TaskErrorResponse "Task or attachment not found"
+// @Failure 500 {object} TaskErrorResponse
+// @Router /api/v1/tasks/{id}/attachments/{attachment_id} [delete]
+func (h *AttachmentHandler) Delete(c *gin.Context) {
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, TaskErrorResponse{Error: "missing or invalid " + middleware.UserIDHeader + " header"})
+		return
+	}
+
+	err := h.DeleteUC.Execute(c.Request.Context(), usecasetask.DeleteAttachmentInput{
+		TaskID:       c.Param("id"),
+		AttachmentID: c.Param("attachment_id"),
+		UserID:       userID,
+	})
+	if err != nil {
+		attachmentError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func attachmentError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrAttachmentTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, TaskErrorResponse{Error: err.Error()})
+	case errors.Is(err, usecase.ErrAttachmentTypeNotAllowed):
+		c.JSON(http.StatusUnsupportedMediaType, TaskErrorResponse{Error: err.Error()})
+	case usecase.ErrorKind(err) == usecase.ErrorKindValidation:
+		c.JSON(http.StatusUnprocessableEntity, TaskErrorResponse{Error: err.Error()})
+	case usecase.ErrorKind(err) == usecase.ErrorKindNotFound:
+		c.JSON(http.StatusNotFound, TaskErrorResponse{Error: err.Error()})
+	case usecase.ErrorKind(err) == usecase.ErrorKindForbidden:
+		c.JSON(http.StatusForbidden, TaskErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, TaskErrorResponse{Error: usecase.ErrUnknown.Error()})
+	}
+}
+
+//
+// ------------------- REQUESTS / RESPONSES -------------------
+//
+
+type AttachmentResponse struct {
+	ID          string    `json:"id"`
+	TaskID      string    `json:"task_id"`
+	UploaderID  string    `json:"uploader_id"`
+	FileName    string    `json:"file_name" example:"comprovante.pdf"`
+	ContentType string    `json:"content_type" example:"application/pdf"`
+	Size        int64     `json:"size"`
+	SHA256      string    `json:"sha256"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type UploadAttachmentResponse struct {
+	AttachmentResponse
+	// Deduplicated indica que o conteúdo já estava armazenado.
+	Deduplicated bool `json:"deduplicated"`
+}
+
+func newAttachmentResponse(attachment *domainTask.Attachment) AttachmentResponse {
+	return AttachmentResponse{
+		ID:          attachment.ID,
+		TaskID:      attachment.TaskID,
+		UploaderID:  attachment.UploaderID,
+		FileName:    attachment.FileName,
+		ContentType: attachment.ContentType,
+		Size:        attachment.Size,
+		SHA256:      attachment.Hash,
+		CreatedAt:   attachment.CreatedAt,
+	}
+}
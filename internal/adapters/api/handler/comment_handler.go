@@ -0,0 +1,368 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/hoyci/todo-ddd/internal/adapters/api/middleware"
+	domainTask "github.com/hoyci/todo-ddd/pkg/domain/task"
+	"github.com/hoyci/todo-ddd/pkg/policy"
+	usecase "github.com/hoyci/todo-ddd/pkg/usecase"
+	usecasetask "github.com/hoyci/todo-ddd/pkg/usecase/task"
+)
+
+// defaultCommentPageSize é usado quando a listagem não informa limit.
+const defaultCommentPageSize = 20
+
+type CommentHandler struct {
+	AddUC      policy.UseCase[usecasetask.AddCommentInput, *usecasetask.AddCommentOutput]
+	EditUC     policy.UseCase[usecasetask.EditCommentInput, *usecasetask.EditCommentOutput]
+	DeleteUC   policy.Command[usecasetask.DeleteCommentInput]
+	ListUC     policy.UseCase[usecasetask.ListCommentsInput, *usecasetask.ListCommentsOutput]
+	ActivityUC policy.UseCase[usecasetask.TaskActivityInput, *usecasetask.TaskActivityOutput]
+	Validate   *validator.Validate
+}
+
+//
+// ------------------- ADD -------------------
+//
+
+// @Summary Comment on a task
+// @Description Adds a markdown comment, authored by the user in X-User-ID, to one of their tasks.
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Param X-User-ID header string true "User ID"
+// @Param id path string true "Task ID"
+// @Param body body CommentRequest true "Comment"
+// @Success 201 {object} CommentResponse
+// @Failure 400 {object} TaskErrorResponse
+// @Failure 401 {object} TaskErrorResponse
+// @Failure 404 {object} TaskErrorResponse "Task not found"
+// @Failure 422 {object} TaskErrorResponse
+// @Failure 500 {object} TaskErrorResponse
+// @Router /api/v1/tasks/{id}/comments [post]
+func (h *CommentHandler) Add(c *gin.Context) {
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, TaskErrorResponse{Error: "missing or invalid " + middleware.UserIDHeader + " header"})
+		return
+	}
+
+	var req CommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, TaskErrorResponse{Error: err.Error()})
+		return
+	}
+	if err := h.Validate.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, TaskErrorResponse{Error: err.Error()})
+		return
+	}
+
+	out, err := h.AddUC.Execute(c.Request.Context(), usecasetask.AddCommentInput{
+		TaskID: c.Param("id"),
+		UserID: userID,
+		Body:   req.Body,
+	})
+	if err != nil {
+		commentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, newCommentResponse(out.Comment))
+}
+
+//
+// ------------------- EDIT -------------------
+//
+
+// @Summary Edit a comment
+// @Description Replaces the body of a comment. Only its author can edit it; the previous body is
+// @Description kept in the comment history.
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Param X-User-ID header string true "User ID"
+// @Param id path string true "Task ID"
+// @Param comment_id path string true "Comment ID"
+// @Param body body CommentRequest true "Comment"
+// @Success 200 {object} CommentResponse
+// @Failure 400 {object} TaskErrorResponse
+// @Failure 401 {object} TaskErrorResponse
+// @Failure 403 {object} TaskErrorResponse "Not the author"
+// @Failure 404 {object} TaskErrorResponse "Task or comment not found"
+// @Failure 422 {object} TaskErrorResponse
+// @Failure 500 {object} TaskErrorResponse
+// @Router /api/v1/tasks/{id}/comments/{comment_id} [put]
+func (h *CommentHandler) Edit(c *gin.Context) {
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, TaskErrorResponse{Error: "missing or invalid " + middleware.UserIDHeader + " header"})
+		return
+	}
+
+	var req CommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, TaskErrorResponse{Error: err.Error()})
+		return
+	}
+	if err := h.Validate.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, TaskErrorResponse{Error: err.Error()})
+		return
+	}
+
+	out, err := h.EditUC.Execute(c.Request.Context(), usecasetask.EditCommentInput{
+		TaskID:    c.Param("id"),
+		CommentID: c.Param("comment_id"),
+		UserID:    userID,
+		Body:      req.Body,
+	})
+	if err != nil {
+		commentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newCommentResponse(out.Comment))
+}
+
+//
+// ------------------- DELETE -------------------
+//
+
+// @Summary Delete a comment
+// @Description Soft deletes a comment. Only its author can delete it.
+// @Tags comments
+// @Param X-User-ID header string true "User ID"
+// @Param id path string true "Task ID"
+// @Param comment_id path string true "Comment ID"
+// @Success 204 "No Content"
+// @Failure 401 {object} TaskErrorResponse
+// @Failure 403 {object} TaskErrorResponse "Not the author"
+// @Failure 404 {object} TaskErrorResponse "Task or comment not found"
+// @Failure 500 {object} TaskErrorResponse
+// @Router /api/v1/tasks/{id}/comments/{comment_id} [delete]
+func (h *CommentHandler) Delete(c *gin.Context) {
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, TaskErrorResponse{Error: "missing or invalid " + middleware.UserIDHeader + " header"})
+		return
+	}
+
+	err := h.DeleteUC.Execute(c.Request.Context(), usecasetask.DeleteCommentInput{
+		TaskID:    c.Param("id"),
+		CommentID: c.Param("comment_id"),
+		UserID:    userID,
+	})
+	if err != nil {
+		commentError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+//
+// ------------------- LIST -------------------
+//
+
+// @Summary List comments
+// @Description Lists the comments of a task, oldest first. Pass next_cursor as after to get the
+// @Description next page.
+// @Tags comments
+// @Produce json
+// @Param X-User-ID header string true "User ID"
+// @Param id path string true "Task ID"
+// @Param limit query int false "Page size (1-100)" default(20)
+// @Param after query string false "Cursor from the previous page"
+// @Success 200 {object} CommentPageResponse
+// @Failure 400 {object} TaskErrorResponse
+// @Failure 401 {object} TaskErrorResponse
+// @Failure 404 {object} TaskErrorResponse "Task not found"
+// @Failure 500 {object} TaskErrorResponse
+// @Router /api/v1/tasks/{id}/comments [get]
+func (h *CommentHandler) List(c *gin.Context) {
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, TaskErrorResponse{Error: "missing or invalid " + middleware.UserIDHeader + " header"})
+		return
+	}
+
+	var req ListCommentsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, TaskErrorResponse{Error: err.Error()})
+		return
+	}
+	if err := h.Validate.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, TaskErrorResponse{Error: err.Error()})
+		return
+	}
+	if req.Limit == 0 {
+		req.Limit = defaultCommentPageSize
+	}
+
+	out, err := h.ListUC.Execute(c.Request.Context(), usecasetask.ListCommentsInput{
+		TaskID: c.Param("id"),
+		UserID: userID,
+		After:  req.After,
+		Limit:  req.Limit,
+	})
+	if err != nil {
+		if usecase.ErrorKind(err) == usecase.ErrorKindValidation {
+			c.JSON(http.StatusBadRequest, TaskErrorResponse{Error: err.Error()})
+			return
+		}
+		commentError(c, err)
+		return
+	}
+
+	resp := CommentPageResponse{
+		Comments:   make([]CommentResponse, 0, len(out.Comments)),
+		NextCursor: out.NextCursor,
+	}
+	for _, comment := range out.Comments {
+		resp.Comments = append(resp.Comments, newCommentResponse(comment))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+//
+// ------------------- ACTIVITY -------------------
+//
+
+// @Summary Task activity feed
+// @Description Comments, field changes and status transitions of a task, oldest first. Deleted
+// @Description comments keep their place in the feed without the body.
+// @Tags comments
+// @Produce json
+// @Param X-User-ID header string true "User ID"
+// @Param id path string true "Task ID"
+// @Success 200 {array} ActivityResponse
+// @Failure 401 {object} TaskErrorResponse
+// @Failure 404 {object} TaskErrorResponse "Task not found"
+// @Failure 500 {object} TaskErrorResponse
+// @Router /api/v1/tasks/{id}/activity [get]
+func (h *CommentHandler) Activity(c *gin.Context) {
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, TaskErrorResponse{Error: "missing or invalid " + middleware.UserIDHeader + " header"})
+		return
+	}
+
+	out, err := h.ActivityUC.Execute(c.Request.Context(), usecasetask.TaskActivityInput{
+		TaskID: c.Param("id"),
+		UserID: userID,
+	})
+	if err != nil {
+		commentError(c, err)
+		return
+	}
+
+	resp := make([]ActivityResponse, 0, len(out.Activity))
+	for _, entry := range out.Activity {
+		item := ActivityResponse{
+			Kind:       string(entry.Kind),
+			OccurredAt: entry.OccurredAt,
+			ActorID:    entry.ActorID,
+		}
+		for _, change := range entry.Changes {
+			item.Changes = append(item.Changes, FieldChangeResponse{
+				Field: change.Field,
+				From:  change.From,
+				To:    change.To,
+			})
+		}
+		if entry.Comment != nil {
+			comment := newCommentResponse(entry.Comment)
+			item.Comment = &comment
+		}
+		resp = append(resp, item)
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func commentError(c *gin.Context, err error) {
+	switch usecase.ErrorKind(err) {
+	case usecase.ErrorKindValidation:
+		c.JSON(http.StatusUnprocessableEntity, TaskErrorResponse{Error: err.Error()})
+	case usecase.ErrorKindNotFound:
+		c.JSON(http.StatusNotFound, TaskErrorResponse{Error: err.Error()})
+	case usecase.ErrorKindForbidden:
+		c.JSON(http.StatusForbidden, TaskErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, TaskErrorResponse{Error: usecase.ErrUnknown.Error()})
+	}
+}
+
+//
+// ------------------- REQUESTS / RESPONSES -------------------
+//
+
+type CommentRequest struct {
+	Body string `json:"body" validate:"required" example:"Paguei via **Pix**, falta o comprovante."`
+}
+
+type ListCommentsRequest struct {
+	Limit int    `form:"limit" validate:"omitempty,min=1,max=100"`
+	After string `form:"after"`
+}
+
+type CommentResponse struct {
+	ID       string `json:"id"`
+	TaskID   string `json:"task_id"`
+	AuthorID string `json:"author_id"`
+	// Body é omitido em comentários excluídos.
+	Body      string                    `json:"body,omitempty"`
+	History   []CommentRevisionResponse `json:"history,omitempty"`
+	CreatedAt time.Time                 `json:"created_at"`
+	UpdatedAt *time.Time                `json:"updated_at"`
+	DeletedAt *time.Time                `json:"deleted_at,omitempty"`
+}
+
+type CommentRevisionResponse struct {
+	Body     string    `json:"body"`
+	EditedAt time.Time `json:"edited_at"`
+}
+
+type CommentPageResponse struct {
+	Comments   []CommentResponse `json:"comments"`
+	NextCursor string            `json:"next_cursor,omitempty"`
+}
+
+type ActivityResponse struct {
+	Kind       string                `json:"kind" enums:"task_created,fields_changed,status_changed,task_deleted,task_restored,comment"`
+	OccurredAt time.Time             `json:"occurred_at"`
+	ActorID    string                `json:"actor_id"`
+	Changes    []FieldChangeResponse `json:"changes,omitempty"`
+	Comment    *CommentResponse      `json:"comment,omitempty"`
+}
+
+type FieldChangeResponse struct {
+	Field string `json:"field" example:"priority"`
+	From  any    `json:"from"`
+	To    any    `json:"to"`
+}
+
+func newCommentResponse(comment *domainTask.Comment) CommentResponse {
+	resp := CommentResponse{
+		ID:        comment.ID,
+		TaskID:    comment.TaskID,
+		AuthorID:  comment.AuthorID,
+		CreatedAt: comment.CreatedAt,
+		UpdatedAt: comment.UpdatedAt,
+		DeletedAt: comment.DeletedAt,
+	}
+	if comment.DeletedAt != nil {
+		return resp
+	}
+
+	resp.Body = comment.Body
+	for _, revision := range comment.History {
+		resp.History = append(resp.History, CommentRevisionResponse{
+			Body:     revision.Body,
+			EditedAt: revision.EditedAt,
+		})
+	}
+	return resp
+}
@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	"github.com/hoyci/todo-ddd/internal/adapters/api/middleware"
+	"github.com/hoyci/todo-ddd/internal/adapters/events"
+	domainTask "github.com/hoyci/todo-ddd/pkg/domain/task"
+	usecase "github.com/hoyci/todo-ddd/pkg/usecase"
+	usecasetask "github.com/hoyci/todo-ddd/pkg/usecase/task"
+)
+
+// EventHandler transmite, via Server-Sent Events, as mudanças nas tarefas do
+// usuário autenticado.
+type EventHandler struct {
+	ListUC *usecasetask.ListTaskEventsUseCase
+	Broker *events.Broker
+	// Heartbeat é o intervalo entre comentários enviados para manter a
+	// conexão aberta em proxies.
+	Heartbeat time.Duration
+	// BatchSize limita quantos eventos são lidos do log de uma vez.
+	BatchSize int
+	// WriteTimeout encerra conexões de clientes que não consomem os eventos.
+	WriteTimeout time.Duration
+}
+
+// @Summary Stream task events
+// @Description Server-Sent Events stream of task.created, task.updated, task.status_changed and
+// @Description task.deleted events for the user in X-User-ID. Send Last-Event-ID to resume after
+// @Description a given event; without it only new events are sent. A comment is sent periodically
+// @Description as a heartbeat.
+// @Tags events
+// @Produce text/event-stream
+// @Param X-User-ID header string true "User ID"
+// @Param Last-Event-ID header string false "Resume after this event ID"
+// @Success 200 {object} TaskEventResponse "One SSE message per event"
+// @Failure 400 {object} TaskErrorResponse
+// @Failure 401 {object} TaskErrorResponse
+// @Failure 429 {object} TaskErrorResponse "Too many open streams"
+// @Failure 500 {object} TaskErrorResponse
+// @Router /api/v1/events [get]
+func (h *EventHandler) Stream(c *gin.Context) {
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, TaskErrorResponse{Error: "missing or invalid " + middleware.UserIDHeader + " header"})
+		return
+	}
+
+	afterID := int64(-1)
+	if raw := c.GetHeader("Last-Event-ID"); raw != "" {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || id < 0 {
+			c.JSON(http.StatusBadRequest, TaskErrorResponse{Error: "invalid Last-Event-ID header"})
+			return
+		}
+		afterID = id
+	}
+
+	// A assinatura é feita antes da primeira leitura do log para que nenhum
+	// evento confirmado entre as duas operações seja perdido.
+	sub, err := h.Broker.Subscribe(userID)
+	if err != nil {
+		if errors.Is(err, events.ErrTooManySubscriptions) {
+			c.JSON(http.StatusTooManyRequests, TaskErrorResponse{Error: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, TaskErrorResponse{Error: usecase.ErrUnknown.Error()})
+		return
+	}
+	defer sub.Close()
+
+	ctx := c.Request.Context()
+	if afterID < 0 {
+		out, err := h.ListUC.Execute(ctx, usecasetask.ListTaskEventsInput{UserID: userID, AfterID: -1})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, TaskErrorResponse{Error: usecase.ErrUnknown.Error()})
+			return
+		}
+		afterID = out.LastID
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	rc := http.NewResponseController(c.Writer)
+	send := func(write func() error) error {
+		if err := rc.SetWriteDeadline(time.Now().Add(h.WriteTimeout)); err != nil && !errors.Is(err, http.ErrNotSupported) {
+			return err
+		}
+		if err := write(); err != nil {
+			return err
+		}
+		return rc.Flush()
+	}
+
+	if err := send(func() error {
+		_, err := c.Writer.WriteString("retry: 3000\n\n")
+		return err
+	}); err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(h.Heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		// Lê o log em lotes até alcançar o último evento.
+		for {
+			out, err := h.ListUC.Execute(ctx, usecasetask.ListTaskEventsInput{
+				UserID:  userID,
+				AfterID: afterID,
+				Limit:   h.BatchSize,
+			})
+			if err != nil {
+				return
+			}
+
+			for _, event := range out.Events {
+				err := send(func() error {
+					return sse.Encode(c.Writer, sse.Event{
+						Id:    strconv.FormatInt(event.ID, 10),
+						Event: string(event.Type),
+						Data:  newTaskEventResponse(event),
+					})
+				})
+				if err != nil {
+					return
+				}
+			}
+			afterID = out.LastID
+
+			if len(out.Events) < h.BatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-sub.C():
+		case <-heartbeat.C:
+			err := send(func() error {
+				_, err := c.Writer.WriteString(": heartbeat\n\n")
+				return err
+			})
+			if err != nil {
+				return
+			}
+		}
+	}
+}
+
+type TaskEventResponse struct {
+	ID         int64        `json:"id"`
+	Type       string       `json:"type"`
+	TaskID     string       `json:"task_id"`
+	OccurredAt time.Time    `json:"occurred_at"`
+	Task       TaskResponse `json:"task"`
+}
+
+func newTaskEventResponse(event domainTask.Event) TaskEventResponse {
+	return TaskEventResponse{
+		ID:         event.ID,
+		Type:       string(event.Type),
+		TaskID:     event.TaskID,
+		OccurredAt: event.OccurredAt,
+		Task:       newTaskResponse(&event.Task),
+	}
+}
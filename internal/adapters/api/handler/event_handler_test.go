@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hoyci/todo-ddd/internal/adapters/api/middleware"
+	"github.com/hoyci/todo-ddd/internal/adapters/db/sqlite"
+	"github.com/hoyci/todo-ddd/internal/adapters/events"
+	usecasetask "github.com/hoyci/todo-ddd/pkg/usecase/task"
+)
+
+// sseStream lê as mensagens de uma conexão SSE aberta.
+type sseStream struct {
+	t      *testing.T
+	reader *bufio.Reader
+}
+
+// openStream abre GET /events e devolve o stream, ou o código de status se
+// a conexão for recusada.
+func openStream(t *testing.T, server *httptest.Server, userID, lastEventID string) (*sseStream, int) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/events", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set(middleware.UserIDHeader, userID)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	if resp.StatusCode != http.StatusOK {
+		return nil, resp.StatusCode
+	}
+	return &sseStream{t: t, reader: bufio.NewReader(resp.Body)}, resp.StatusCode
+}
+
+// next devolve a próxima mensagem, com as linhas de campo e comentário.
+func (s *sseStream) next() []string {
+	s.t.Helper()
+	var lines []string
+	for {
+		line, err := s.reader.ReadString('\n')
+		if err != nil {
+			s.t.Fatalf("reading stream: %v (after %q)", err, lines)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			if len(lines) > 0 {
+				return lines
+			}
+			continue
+		}
+		lines = append(lines, line)
+	}
+}
+
+// nextEvent pula comentários e devolve o ID e o tipo do próximo evento.
+func (s *sseStream) nextEvent() (int64, string) {
+	s.t.Helper()
+	for {
+		var id int64
+		var kind string
+		for _, line := range s.next() {
+			switch {
+			case strings.HasPrefix(line, "id:"):
+				parsed, err := strconv.ParseInt(strings.TrimSpace(strings.TrimPrefix(line, "id:")), 10, 64)
+				if err != nil {
+					s.t.Fatal(err)
+				}
+				id = parsed
+			case strings.HasPrefix(line, "event:"):
+				kind = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+			}
+		}
+		if kind != "" {
+			return id, kind
+		}
+	}
+}
+
+// eventServer expõe GET /events como em main, com o intervalo de heartbeat
+// dado e lotes de dois eventos.
+func eventServer(t *testing.T, list *usecasetask.ListTaskEventsUseCase, broker *events.Broker, heartbeat time.Duration) *httptest.Server {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	h := &EventHandler{ListUC: list, Broker: broker, Heartbeat: heartbeat, BatchSize: 2, WriteTimeout: time.Second}
+	r := gin.New()
+	r.Use(middleware.Identity())
+	r.GET("/events", h.Stream)
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestEventStream(t *testing.T) {
+	db := newTestDB(t)
+	broker := events.NewBroker(0)
+	uow := sqlite.NewSQLiteUnitOfWork(db, broker)
+	ctx := context.Background()
+	owner := saveTestUser(t, uow, "owner@example.com")
+
+	list := &usecasetask.ListTaskEventsUseCase{EventRepo: sqlite.NewSQLiteTaskEventRepository(db)}
+	server := eventServer(t, list, broker, time.Hour)
+
+	create := func(title string) {
+		t.Helper()
+		if _, err := (&usecasetask.CreateTaskUseCase{UoW: uow}).Execute(ctx, usecasetask.CreateTaskInput{Title: title, Priority: 1, UserID: owner}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := range 5 {
+		create("Task " + strconv.Itoa(i))
+	}
+	out, err := list.Execute(ctx, usecasetask.ListTaskEventsInput{UserID: owner, AfterID: 0, Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out.Events) != 5 {
+		t.Fatalf("got %d events in the log, want 5", len(out.Events))
+	}
+	ids := make([]int64, len(out.Events))
+	for i, event := range out.Events {
+		ids[i] = event.ID
+	}
+
+	t.Run("resumes after Last-Event-ID", func(t *testing.T) {
+		// Os quatro eventos seguintes chegam em ordem, em lotes de dois.
+		stream, status := openStream(t, server, owner, strconv.FormatInt(ids[0], 10))
+		if status != http.StatusOK {
+			t.Fatalf("status = %d, want %d", status, http.StatusOK)
+		}
+		if got := stream.next(); len(got) != 1 || got[0] != "retry: 3000" {
+			t.Fatalf("first message = %q, want the retry interval", got)
+		}
+		for _, want := range ids[1:] {
+			if id, kind := stream.nextEvent(); id != want || kind != "task.created" {
+				t.Fatalf("event = %d %s, want %d task.created", id, kind, want)
+			}
+		}
+
+		// Um evento novo chega pela mesma conexão.
+		create("Live")
+		if id, _ := stream.nextEvent(); id <= ids[len(ids)-1] {
+			t.Errorf("live event ID = %d, want one after %d", id, ids[len(ids)-1])
+		}
+	})
+
+	t.Run("without Last-Event-ID only new events", func(t *testing.T) {
+		stream, _ := openStream(t, server, owner, "")
+		stream.next()
+		create("New")
+		last, err := list.Execute(ctx, usecasetask.ListTaskEventsInput{UserID: owner, AfterID: -1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id, _ := stream.nextEvent(); id != last.LastID {
+			t.Errorf("first event ID = %d, want the new event %d", id, last.LastID)
+		}
+	})
+
+	t.Run("invalid Last-Event-ID", func(t *testing.T) {
+		for _, raw := range []string{"abc", "-1"} {
+			if _, status := openStream(t, server, owner, raw); status != http.StatusBadRequest {
+				t.Errorf("Last-Event-ID %q: status = %d, want %d", raw, status, http.StatusBadRequest)
+			}
+		}
+	})
+
+	t.Run("heartbeat", func(t *testing.T) {
+		stream, _ := openStream(t, eventServer(t, list, broker, 10*time.Millisecond), owner, "")
+		stream.next()
+		for range 2 {
+			if got := stream.next(); len(got) != 1 || got[0] != ": heartbeat" {
+				t.Fatalf("message = %q, want a heartbeat comment", got)
+			}
+		}
+	})
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hoyci/todo-ddd/internal/adapters/api/middleware"
+	"github.com/hoyci/todo-ddd/internal/adapters/graphql"
+)
+
+type GraphQLHandler struct {
+	Server *graphql.Server
+}
+
+// @Summary GraphQL endpoint
+// @Description Executes GraphQL queries and mutations over users and tasks. Fields that act on the
+// @Description current user (viewer, task, tasks and the task mutations) require X-User-ID. Queries
+// @Description that exceed the depth or complexity limits are rejected before execution.
+// @Tags GraphQL
+// @Accept json
+// @Produce json
+// @Param X-User-ID header string false "User ID"
+// @Param request body GraphQLRequest true "GraphQL request"
+// @Success 200 {object} GraphQLResponse "Executed; field errors are reported in errors"
+// @Failure 400 {object} GraphQLResponse "Syntax, validation or limit error"
+// @Router /api/v1/graphql [post]
+func (h *GraphQLHandler) Query(c *gin.Context) {
+	var req GraphQLRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, GraphQLResponse{Errors: []GraphQLError{{Message: err.Error()}}})
+		return
+	}
+
+	userID, _ := middleware.UserID(c)
+	result, err := h.Server.Execute(c.Request.Context(), userID, graphql.Request{
+		Query:         req.Query,
+		OperationName: req.OperationName,
+		Variables:     req.Variables,
+	})
+	if err != nil {
+		var rejected *graphql.RejectedError
+		if errors.As(err, &rejected) {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": rejected.Errors})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, GraphQLResponse{Errors: []GraphQLError{{Message: "unexpected error"}}})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+//
+// ------------------- REQUESTS / RESPONSES -------------------
+//
+
+type GraphQLRequest struct {
+	Query         string         `json:"query" binding:"required"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+type GraphQLResponse struct {
+	Data   any            `json:"data,omitempty"`
+	Errors []GraphQLError `json:"errors,omitempty"`
+}
+
+type GraphQLError struct {
+	Message    string         `json:"message"`
+	Path       []any          `json:"path,omitempty"`
+	Extensions map[string]any `json:"extensions,omitempty"`
+}
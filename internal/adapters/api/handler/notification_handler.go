@@ -0,0 +1,389 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/hoyci/todo-ddd/internal/adapters/api/middleware"
+	domainNotification "github.com/hoyci/todo-ddd/pkg/domain/notification"
+	"github.com/hoyci/todo-ddd/pkg/usecase"
+	usecasenotification "github.com/hoyci/todo-ddd/pkg/usecase/notification"
+)
+
+// defaultNotificationPageSize é usado quando a listagem não informa limit.
+const defaultNotificationPageSize = 20
+
+type NotificationHandler struct {
+	ListUC              *usecasenotification.ListNotificationsUseCase
+	CountUnreadUC       *usecasenotification.CountUnreadUseCase
+	MarkReadUC          *usecasenotification.MarkReadUseCase
+	MarkUnreadUC        *usecasenotification.MarkUnreadUseCase
+	GetPreferencesUC    *usecasenotification.GetPreferencesUseCase
+	UpdatePreferencesUC *usecasenotification.UpdatePreferencesUseCase
+	Validate            *validator.Validate
+}
+
+//
+// ------------------- INBOX -------------------
+//
+
+// @Summary List my notifications
+// @Description Lists the caller's in-app notifications, newest first: assignments, comments,
+// @Description mentions and reminders. Notifications expire 90 days after they are created. Pass
+// @Description next_cursor as after to get the next page.
+// @Tags notifications
+// @Produce json
+// @Param X-User-ID header string true "User ID"
+// @Param unread query bool false "Only unread notifications"
+// @Param limit query int false "Page size (1-100)" default(20)
+// @Param after query string false "Cursor from the previous page"
+// @Success 200 {object} NotificationPageResponse
+// @Failure 400 {object} TaskErrorResponse
+// @Failure 401 {object} TaskErrorResponse
+// @Failure 500 {object} TaskErrorResponse
+// @Router /api/v1/notifications [get]
+func (h *NotificationHandler) List(c *gin.Context) {
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, TaskErrorResponse{Error: "missing or invalid " + middleware.UserIDHeader + " header"})
+		return
+	}
+
+	var req ListNotificationsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, TaskErrorResponse{Error: err.Error()})
+		return
+	}
+	if err := h.Validate.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, TaskErrorResponse{Error: err.Error()})
+		return
+	}
+	if req.Limit == 0 {
+		req.Limit = defaultNotificationPageSize
+	}
+
+	out, err := h.ListUC.Execute(c.Request.Context(), usecasenotification.ListNotificationsInput{
+		UserID:     userID,
+		UnreadOnly: req.Unread,
+		After:      req.After,
+		Limit:      req.Limit,
+	})
+	if err != nil {
+		if usecase.ErrorKind(err) == usecase.ErrorKindValidation {
+			c.JSON(http.StatusBadRequest, TaskErrorResponse{Error: err.Error()})
+			return
+		}
+		taskError(c, err)
+		return
+	}
+
+	resp := NotificationPageResponse{
+		Notifications: make([]NotificationResponse, 0, len(out.Notifications)),
+		NextCursor:    out.NextCursor,
+		Unread:        out.Unread,
+	}
+	for _, notification := range out.Notifications {
+		resp.Notifications = append(resp.Notifications, newNotificationResponse(notification))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// @Summary Count my unread notifications
+// @Tags notifications
+// @Produce json
+// @Param X-User-ID header string true "User ID"
+// @Success 200 {object} UnreadCountResponse
+// @Failure 401 {object} TaskErrorResponse
+// @Failure 500 {object} TaskErrorResponse
+// @Router /api/v1/notifications/unread-count [get]
+func (h *NotificationHandler) UnreadCount(c *gin.Context) {
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, TaskErrorResponse{Error: "missing or invalid " + middleware.UserIDHeader + " header"})
+		return
+	}
+
+	count, err := h.CountUnreadUC.Execute(c.Request.Context(), userID)
+	if err != nil {
+		taskError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, UnreadCountResponse{Unread: count})
+}
+
+// @Summary Mark notifications as read
+// @Description Marks the given notifications, or all of them with all=true, as read. IDs that are
+// @Description not the caller's, already read or expired are ignored.
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Param X-User-ID header string true "User ID"
+// @Param body body MarkReadRequest true "Notifications"
+// @Success 200 {object} MarkReadResponse
+// @Failure 400 {object} TaskErrorResponse
+// @Failure 401 {object} TaskErrorResponse
+// @Failure 500 {object} TaskErrorResponse
+// @Router /api/v1/notifications/read [post]
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, TaskErrorResponse{Error: "missing or invalid " + middleware.UserIDHeader + " header"})
+		return
+	}
+
+	var req MarkReadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, TaskErrorResponse{Error: err.Error()})
+		return
+	}
+	if err := h.Validate.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, TaskErrorResponse{Error: err.Error()})
+		return
+	}
+
+	out, err := h.MarkReadUC.Execute(c.Request.Context(), usecasenotification.MarkReadInput{
+		UserID: userID,
+		IDs:    req.IDs,
+		All:    req.All,
+	})
+	if err != nil {
+		taskError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, MarkReadResponse{Updated: out.Updated, Unread: out.Unread})
+}
+
+// @Summary Mark notifications as unread
+// @Description Marks the given notifications as unread again. IDs that are not the caller's,
+// @Description already unread or expired are ignored.
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Param X-User-ID header string true "User ID"
+// @Param body body MarkUnreadRequest true "Notifications"
+// @Success 200 {object} MarkReadResponse
+// @Failure 400 {object} TaskErrorResponse
+// @Failure 401 {object} TaskErrorResponse
+// @Failure 500 {object} TaskErrorResponse
+// @Router /api/v1/notifications/unread [post]
+func (h *NotificationHandler) MarkUnread(c *gin.Context) {
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, TaskErrorResponse{Error: "missing or invalid " + middleware.UserIDHeader + " header"})
+		return
+	}
+
+	var req MarkUnreadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, TaskErrorResponse{Error: err.Error()})
+		return
+	}
+	if err := h.Validate.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, TaskErrorResponse{Error: err.Error()})
+		return
+	}
+
+	out, err := h.MarkUnreadUC.Execute(c.Request.Context(), usecasenotification.MarkUnreadInput{
+		UserID: userID,
+		IDs:    req.IDs,
+	})
+	if err != nil {
+		taskError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, MarkReadResponse{Updated: out.Updated, Unread: out.Unread})
+}
+
+//
+// ------------------- PREFERENCES -------------------
+//
+
+// @Summary Get my notification preferences
+// @Description Returns the caller's notification channels and quiet hours. Users who never saved
+// @Description preferences get the defaults: in-app and email, no quiet hours.
+// @Tags notifications
+// @Produce json
+// @Param X-User-ID header string true "User ID"
+// @Success 200 {object} NotificationPreferencesResponse
+// @Failure 401 {object} TaskErrorResponse
+// @Failure 500 {object} TaskErrorResponse
+// @Router /api/v1/notifications/preferences [get]
+func (h *NotificationHandler) GetPreferences(c *gin.Context) {
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, TaskErrorResponse{Error: "missing or invalid " + middleware.UserIDHeader + " header"})
+		return
+	}
+
+	prefs, err := h.GetPreferencesUC.Execute(c.Request.Context(), userID)
+	if err != nil {
+		taskError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newNotificationPreferencesResponse(prefs))
+}
+
+// @Summary Replace my notification preferences
+// @Description Sets the channels reminders are delivered through and the daily quiet hours. A
+// @Description reminder due during quiet hours is delivered when they end. The webhook channel
+// @Description needs webhook_url; omit quiet_hours to turn them off.
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Param X-User-ID header string true "User ID"
+// @Param body body NotificationPreferencesRequest true "Preferences"
+// @Success 200 {object} NotificationPreferencesResponse
+// @Failure 400 {object} TaskErrorResponse
+// @Failure 401 {object} TaskErrorResponse
+// @Failure 422 {object} TaskErrorResponse "Invalid channel, webhook URL or quiet hours"
+// @Failure 500 {object} TaskErrorResponse
+// @Router /api/v1/notifications/preferences [put]
+func (h *NotificationHandler) UpdatePreferences(c *gin.Context) {
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, TaskErrorResponse{Error: "missing or invalid " + middleware.UserIDHeader + " header"})
+		return
+	}
+
+	var req NotificationPreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, TaskErrorResponse{Error: err.Error()})
+		return
+	}
+	if err := h.Validate.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, TaskErrorResponse{Error: err.Error()})
+		return
+	}
+
+	input := usecasenotification.UpdatePreferencesInput{
+		UserID:     userID,
+		Channels:   req.Channels,
+		WebhookURL: req.WebhookURL,
+	}
+	if req.QuietHours != nil {
+		input.QuietHours = &usecasenotification.QuietHoursInput{
+			Start:    req.QuietHours.Start,
+			End:      req.QuietHours.End,
+			Timezone: req.QuietHours.Timezone,
+		}
+	}
+	prefs, err := h.UpdatePreferencesUC.Execute(c.Request.Context(), input)
+	if err != nil {
+		taskError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newNotificationPreferencesResponse(prefs))
+}
+
+//
+// ------------------- REQUESTS / RESPONSES -------------------
+//
+
+type ListNotificationsRequest struct {
+	Unread bool   `form:"unread"`
+	Limit  int    `form:"limit" validate:"omitempty,min=1,max=100"`
+	After  string `form:"after"`
+}
+
+type MarkReadRequest struct {
+	IDs []string `json:"ids" validate:"required_without=All,max=100" example:"3f2a9c4e-1b7d-4e8a-9c61-2d5f0e7b8a14"`
+	All bool     `json:"all"`
+}
+
+type MarkUnreadRequest struct {
+	IDs []string `json:"ids" validate:"required,min=1,max=100" example:"3f2a9c4e-1b7d-4e8a-9c61-2d5f0e7b8a14"`
+}
+
+type NotificationResponse struct {
+	ID        string     `json:"id"`
+	Kind      string     `json:"kind" enums:"reminder,assigned,comment,mention"`
+	TaskID    string     `json:"task_id,omitempty"`
+	Title     string     `json:"title"`
+	Body      string     `json:"body"`
+	Read      bool       `json:"read"`
+	ReadAt    *time.Time `json:"read_at"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt time.Time  `json:"expires_at"`
+}
+
+type NotificationPageResponse struct {
+	Notifications []NotificationResponse `json:"notifications"`
+	NextCursor    string                 `json:"next_cursor,omitempty"`
+	// Unread conta todas as não lidas, não só as da página.
+	Unread int `json:"unread"`
+}
+
+type UnreadCountResponse struct {
+	Unread int `json:"unread"`
+}
+
+type MarkReadResponse struct {
+	Updated int `json:"updated"`
+	Unread  int `json:"unread"`
+}
+
+func newNotificationResponse(notification *domainNotification.Notification) NotificationResponse {
+	return NotificationResponse{
+		ID:        notification.ID,
+		Kind:      string(notification.Kind),
+		TaskID:    notification.TaskID,
+		Title:     notification.Title,
+		Body:      notification.Body,
+		Read:      notification.Read(),
+		ReadAt:    notification.ReadAt,
+		CreatedAt: notification.CreatedAt,
+		ExpiresAt: notification.ExpiresAt,
+	}
+}
+
+type NotificationPreferencesRequest struct {
+	Channels   []string           `json:"channels" validate:"required" example:"in_app,email"`
+	WebhookURL string             `json:"webhook_url" example:"https://hooks.example.com/todo"`
+	QuietHours *QuietHoursRequest `json:"quiet_hours"`
+}
+
+type QuietHoursRequest struct {
+	Start string `json:"start" validate:"required" example:"22:00"`
+	End   string `json:"end" validate:"required" example:"07:00"`
+	// Timezone é um fuso IANA; vazio é UTC.
+	Timezone string `json:"timezone" example:"America/Sao_Paulo"`
+}
+
+type NotificationPreferencesResponse struct {
+	Channels   []string            `json:"channels" example:"in_app,email"`
+	WebhookURL string              `json:"webhook_url,omitempty"`
+	QuietHours *QuietHoursResponse `json:"quiet_hours"`
+	UpdatedAt  *time.Time          `json:"updated_at"`
+}
+
+type QuietHoursResponse struct {
+	Start    string `json:"start" example:"22:00"`
+	End      string `json:"end" example:"07:00"`
+	Timezone string `json:"timezone" example:"America/Sao_Paulo"`
+}
+
+func newNotificationPreferencesResponse(prefs *domainNotification.Preferences) NotificationPreferencesResponse {
+	resp := NotificationPreferencesResponse{
+		Channels:   make([]string, len(prefs.Channels)),
+		WebhookURL: prefs.WebhookURL,
+		UpdatedAt:  prefs.UpdatedAt,
+	}
+	for i, channel := range prefs.Channels {
+		resp.Channels[i] = string(channel)
+	}
+	if !prefs.QuietHours.IsZero() {
+		resp.QuietHours = &QuietHoursResponse{
+			Start:    prefs.QuietHours.StartClock(),
+			End:      prefs.QuietHours.EndClock(),
+			Timezone: prefs.QuietHours.Timezone,
+		}
+	}
+	return resp
+}
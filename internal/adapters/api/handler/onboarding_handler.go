@@ -2,18 +2,23 @@ package handler
 
 import (
 	"errors"
-	"fmt"
+	"math"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 	"github.com/hoyci/todo-ddd/pkg/domain/valueobject"
+	"github.com/hoyci/todo-ddd/pkg/logging"
 	"github.com/hoyci/todo-ddd/pkg/usecase"
 	usecasesetup "github.com/hoyci/todo-ddd/pkg/usecase/setup"
+	usecaseuser "github.com/hoyci/todo-ddd/pkg/usecase/user"
 )
 
 type OnboardingHandler struct {
 	SetupUC  *usecasesetup.SetupOnboardingUseCase
+	VerifyUC *usecaseuser.VerifyEmailUseCase
+	ResendUC *usecaseuser.ResendVerificationUseCase
 	Validate *validator.Validate
 }
 
@@ -22,7 +27,8 @@ type OnboardingHandler struct {
 //
 
 // @Summary Complete user onboarding
-// @Description Initiates or finalizes the onboarding process for a new user.
+// @Description Initiates or finalizes the onboarding process for a new user. The user starts pending
+// @Description verification and is emailed a link to confirm the address.
 // @Tags Onboarding
 // @Accept json
 // @Produce json
@@ -45,7 +51,7 @@ func (h *OnboardingHandler) Setup(c *gin.Context) {
 		return
 	}
 
-	err := h.SetupUC.Execute(usecasesetup.SetupOnboardingInput{
+	err := h.SetupUC.Execute(c.Request.Context(), usecasesetup.SetupOnboardingInput{
 		Name:  req.Name,
 		Email: req.Email,
 	})
@@ -67,8 +73,9 @@ func (h *OnboardingHandler) Setup(c *gin.Context) {
 			c.JSON(http.StatusBadRequest, OnboardingErrorResponse{
 				Error: err.Error(),
 			})
+			return
 		default:
-			fmt.Println("error", err)
+			logging.FromContext(c.Request.Context()).Error("unexpected error on onboarding", "error", err)
 			c.JSON(http.StatusInternalServerError, OnboardingErrorResponse{
 				Error: "unexpected error",
 			})
@@ -81,6 +88,91 @@ func (h *OnboardingHandler) Setup(c *gin.Context) {
 	})
 }
 
+//
+// ------------------- VERIFICATION -------------------
+//
+
+// @Summary Verify an email address
+// @Description Confirms the email address with the signed token from the verification email. The
+// @Description token expires and is only valid while the user keeps the email it was sent to.
+// @Tags Onboarding
+// @Produce json
+// @Param token query string true "Verification token"
+// @Success 200 {object} OnboardingResponse "Email verified"
+// @Failure 400 {object} OnboardingErrorResponse "Missing, invalid or expired token"
+// @Failure 404 {object} OnboardingErrorResponse "User not found"
+// @Failure 409 {object} OnboardingErrorResponse "Email already verified"
+// @Failure 500 {object} OnboardingErrorResponse "Unexpected internal server error"
+// @Router /api/v1/onboarding/verify [get]
+func (h *OnboardingHandler) Verify(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		c.JSON(http.StatusBadRequest, OnboardingErrorResponse{Error: "missing token"})
+		return
+	}
+
+	if _, err := h.VerifyUC.Execute(c.Request.Context(), usecaseuser.VerifyEmailInput{Token: token}); err != nil {
+		verificationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, OnboardingResponse{Message: "email verified successfully"})
+}
+
+// @Summary Resend the verification email
+// @Description Sends a new verification email to a user still pending verification. Only one email
+// @Description is sent per user within the resend interval; earlier requests get 429.
+// @Tags Onboarding
+// @Accept json
+// @Produce json
+// @Param body body ResendVerificationRequest true "Email to verify"
+// @Success 202 {object} OnboardingResponse "Verification email sent"
+// @Failure 400 {object} OnboardingErrorResponse "Invalid request payload"
+// @Failure 404 {object} OnboardingErrorResponse "User not found"
+// @Failure 409 {object} OnboardingErrorResponse "Email already verified"
+// @Failure 429 {object} OnboardingErrorResponse "Sent too recently; see Retry-After"
+// @Failure 500 {object} OnboardingErrorResponse "Unexpected internal server error"
+// @Header 429 {integer} Retry-After "Seconds until a new email can be sent"
+// @Router /api/v1/onboarding/verification [post]
+func (h *OnboardingHandler) Resend(c *gin.Context) {
+	var req ResendVerificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, OnboardingErrorResponse{Error: err.Error()})
+		return
+	}
+	if err := h.Validate.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, OnboardingErrorResponse{Error: err.Error()})
+		return
+	}
+
+	out, err := h.ResendUC.Execute(c.Request.Context(), usecaseuser.ResendVerificationInput{Email: req.Email})
+	if err != nil {
+		verificationError(c, err)
+		return
+	}
+	if !out.Sent {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(out.RetryAfter.Seconds()))))
+		c.JSON(http.StatusTooManyRequests, OnboardingErrorResponse{Error: "verification email sent too recently"})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, OnboardingResponse{Message: "verification email sent"})
+}
+
+func verificationError(c *gin.Context, err error) {
+	switch usecase.ErrorKind(err) {
+	case usecase.ErrorKindValidation:
+		c.JSON(http.StatusBadRequest, OnboardingErrorResponse{Error: err.Error()})
+	case usecase.ErrorKindNotFound:
+		c.JSON(http.StatusNotFound, OnboardingErrorResponse{Error: err.Error()})
+	case usecase.ErrorKindConflict:
+		c.JSON(http.StatusConflict, OnboardingErrorResponse{Error: err.Error()})
+	default:
+		logging.FromContext(c.Request.Context()).Error("unexpected error on email verification", "error", err)
+		c.JSON(http.StatusInternalServerError, OnboardingErrorResponse{Error: "unexpected error"})
+	}
+}
+
 //
 // ------------------- REQUESTS / RESPONSES -------------------
 //
@@ -90,6 +182,10 @@ type OnboardingRequest struct {
 	Email string `json:"email" validate:"required,email"`
 }
 
+type ResendVerificationRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
 type OnboardingResponse struct {
 	Message string `json:"message"`
 }
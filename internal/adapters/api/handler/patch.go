@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"mime"
+	"net/http"
+	"slices"
+	"strings"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	MergePatchContentType = "application/merge-patch+json"
+	JSONPatchContentType  = "application/json-patch+json"
+)
+
+var (
+	errUnsupportedPatchType = errors.New("unsupported patch content type")
+	errMalformedPatch       = errors.New("malformed patch document")
+)
+
+// jsonPatcher aplica um documento de patch (RFC 7386 ou RFC 6902) sobre a
+// representação JSON de um recurso. O documento é validado ao ser criado,
+// antes de qualquer acesso ao banco.
+type jsonPatcher struct {
+	apply func(doc []byte) ([]byte, error)
+	// fields são os membros do recurso que o patch toca; "" é o documento
+	// inteiro.
+	fields []string
+}
+
+func newJSONPatcher(c *gin.Context) (*jsonPatcher, error) {
+	mediaType, _, err := mime.ParseMediaType(c.ContentType())
+	if err != nil {
+		return nil, errUnsupportedPatchType
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errMalformedPatch, err)
+	}
+
+	switch mediaType {
+	case MergePatchContentType, "application/json":
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(body, &fields); err != nil || fields == nil {
+			return nil, fmt.Errorf("%w: merge patch must be a JSON object", errMalformedPatch)
+		}
+		return &jsonPatcher{
+			apply: func(doc []byte) ([]byte, error) {
+				return jsonpatch.MergePatch(doc, body)
+			},
+			fields: slices.Sorted(maps.Keys(fields)),
+		}, nil
+
+	case JSONPatchContentType:
+		patch, err := jsonpatch.DecodePatch(body)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", errMalformedPatch, err)
+		}
+		patcher := &jsonPatcher{apply: patch.Apply}
+		for _, op := range patch {
+			paths := []string{}
+			if path, err := op.Path(); err == nil {
+				paths = append(paths, path)
+			}
+			if from, err := op.From(); err == nil {
+				paths = append(paths, from)
+			}
+			for _, path := range paths {
+				patcher.fields = append(patcher.fields, topLevelField(path))
+			}
+		}
+		return patcher, nil
+
+	default:
+		return nil, errUnsupportedPatchType
+	}
+}
+
+// topLevelField devolve o membro do documento apontado por um JSON Pointer
+// (RFC 6901).
+func topLevelField(pointer string) string {
+	field, _, _ := strings.Cut(strings.TrimPrefix(pointer, "/"), "/")
+	return strings.NewReplacer("~1", "/", "~0", "~").Replace(field)
+}
+
+// Apply serializa current, aplica o patch e decodifica o resultado em
+// desired. Campos que current não tem, no patch ou no documento
+// resultante, e caminhos que não existem dão errMalformedPatch; uma
+// operação "test" que falha é devolvida como está.
+func (p *jsonPatcher) Apply(current, desired any) error {
+	doc, err := json.Marshal(current)
+	if err != nil {
+		return err
+	}
+
+	var known map[string]json.RawMessage
+	if err := json.Unmarshal(doc, &known); err != nil {
+		return err
+	}
+	for _, field := range p.fields {
+		if _, ok := known[field]; !ok && field != "" {
+			return fmt.Errorf("%w: unknown field %q", errMalformedPatch, field)
+		}
+	}
+
+	patched, err := p.apply(doc)
+	if err != nil {
+		if patchConflict(err) {
+			return err
+		}
+		return fmt.Errorf("%w: %w", errMalformedPatch, err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(patched))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(desired); err != nil {
+		return fmt.Errorf("%w: %w", errMalformedPatch, err)
+	}
+	return nil
+}
+
+// patchRequestError responde a erros de leitura do documento de patch.
+func patchRequestError(c *gin.Context, err error) {
+	status := http.StatusBadRequest
+	if errors.Is(err, errUnsupportedPatchType) {
+		c.Header("Accept-Patch", MergePatchContentType+", "+JSONPatchContentType)
+		status = http.StatusUnsupportedMediaType
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
+
+// patchConflict indica que uma operação "test" do JSON Patch falhou, ou
+// seja, o recurso não está no estado esperado pelo cliente.
+func patchConflict(err error) bool {
+	return errors.Is(err, jsonpatch.ErrTestFailed)
+}
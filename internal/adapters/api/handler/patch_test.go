@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/gin-gonic/gin"
+)
+
+func newTestPatcher(t *testing.T, contentType, body string) (*jsonPatcher, error) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(body))
+	c.Request.Header.Set("Content-Type", contentType)
+	return newJSONPatcher(c)
+}
+
+func TestJSONPatcher(t *testing.T) {
+	title, priority, status := "Write docs", 1, "new"
+	recurrence := "FREQ=WEEKLY"
+	current := TaskPatchDocument{Title: &title, Priority: &priority, Status: &status, Tags: []string{"work"}, Recurrence: &recurrence}
+	due := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		check       func(TaskPatchDocument) bool
+		wantErr     error
+	}{
+		{
+			name:        "merge patch sets due_at and tags",
+			contentType: MergePatchContentType,
+			body:        `{"due_at":"2026-05-01T09:00:00Z","tags":["work","home"]}`,
+			check: func(d TaskPatchDocument) bool {
+				return d.DueAt != nil && d.DueAt.Equal(due) && len(d.Tags) == 2 && *d.Title == title
+			},
+		},
+		{
+			name:        "merge patch clears recurrence",
+			contentType: MergePatchContentType,
+			body:        `{"recurrence":null}`,
+			check:       func(d TaskPatchDocument) bool { return d.Recurrence == nil },
+		},
+		{
+			name:        "merge patch unknown field",
+			contentType: MergePatchContentType,
+			body:        `{"colour":"red"}`,
+			wantErr:     errMalformedPatch,
+		},
+		{
+			name:        "merge patch unknown field set to null",
+			contentType: MergePatchContentType,
+			body:        `{"colour":null}`,
+			wantErr:     errMalformedPatch,
+		},
+		{
+			name:        "merge patch wrong type",
+			contentType: MergePatchContentType,
+			body:        `{"due_at":"tomorrow"}`,
+			wantErr:     errMalformedPatch,
+		},
+		{
+			name:        "json patch replaces null due_at",
+			contentType: JSONPatchContentType,
+			body:        `[{"op":"replace","path":"/due_at","value":"2026-05-01T09:00:00Z"}]`,
+			check:       func(d TaskPatchDocument) bool { return d.DueAt != nil && d.DueAt.Equal(due) },
+		},
+		{
+			name:        "json patch appends a tag",
+			contentType: JSONPatchContentType,
+			body:        `[{"op":"add","path":"/tags/-","value":"home"}]`,
+			check:       func(d TaskPatchDocument) bool { return len(d.Tags) == 2 && d.Tags[1] == "home" },
+		},
+		{
+			name:        "json patch unknown path",
+			contentType: JSONPatchContentType,
+			body:        `[{"op":"add","path":"/colour","value":"red"}]`,
+			wantErr:     errMalformedPatch,
+		},
+		{
+			name:        "json patch remove of unknown path",
+			contentType: JSONPatchContentType,
+			body:        `[{"op":"remove","path":"/colour"}]`,
+			wantErr:     errMalformedPatch,
+		},
+		{
+			name:        "json patch move from unknown path",
+			contentType: JSONPatchContentType,
+			body:        `[{"op":"move","from":"/colour","path":"/title"}]`,
+			wantErr:     errMalformedPatch,
+		},
+		{
+			name:        "json patch index out of range",
+			contentType: JSONPatchContentType,
+			body:        `[{"op":"remove","path":"/tags/5"}]`,
+			wantErr:     errMalformedPatch,
+		},
+		{
+			name:        "json patch failed test",
+			contentType: JSONPatchContentType,
+			body:        `[{"op":"test","path":"/title","value":"Other"}]`,
+			wantErr:     jsonpatch.ErrTestFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patcher, err := newTestPatcher(t, tt.contentType, tt.body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var desired TaskPatchDocument
+			err = patcher.Apply(current, &desired)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Apply() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.check != nil && !tt.check(desired) {
+				t.Errorf("patched document = %+v", desired)
+			}
+		})
+	}
+}
+
+func TestNewJSONPatcherRejects(t *testing.T) {
+	tests := []struct {
+		contentType string
+		body        string
+		wantErr     error
+	}{
+		{"text/plain", `{}`, errUnsupportedPatchType},
+		{MergePatchContentType, `[]`, errMalformedPatch},
+		{JSONPatchContentType, `{"op":"add"}`, errMalformedPatch},
+	}
+	for _, tt := range tests {
+		if _, err := newTestPatcher(t, tt.contentType, tt.body); !errors.Is(err, tt.wantErr) {
+			t.Errorf("newJSONPatcher(%s, %s) error = %v, want %v", tt.contentType, tt.body, err, tt.wantErr)
+		}
+	}
+}
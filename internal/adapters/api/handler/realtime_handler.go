@@ -0,0 +1,500 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hoyci/todo-ddd/internal/adapters/api/middleware"
+	"github.com/hoyci/todo-ddd/internal/adapters/events"
+	domainTask "github.com/hoyci/todo-ddd/pkg/domain/task"
+	"github.com/hoyci/todo-ddd/pkg/domain/valueobject"
+	domainWorkspace "github.com/hoyci/todo-ddd/pkg/domain/workspace"
+	"github.com/hoyci/todo-ddd/pkg/logging"
+	"github.com/hoyci/todo-ddd/pkg/policy"
+	usecase "github.com/hoyci/todo-ddd/pkg/usecase"
+	usecasetask "github.com/hoyci/todo-ddd/pkg/usecase/task"
+	usecaseworkspace "github.com/hoyci/todo-ddd/pkg/usecase/workspace"
+	"golang.org/x/net/websocket"
+)
+
+// Tipos de canal: a lista de tarefas do próprio usuário, as tarefas de um
+// workspace do qual ele é membro e uma tarefa que ele enxerga (por exemplo,
+// compartilhada com ele).
+const (
+	taskListChannel  = "tasks"
+	workspaceChannel = "workspace"
+	taskChannel      = "task"
+)
+
+var (
+	errInvalidChannel  = errors.New("channel must be tasks:<user_id>, workspace:<workspace_id> or task:<task_id>")
+	errForeignTaskList = errors.New("cannot subscribe to another user's tasks")
+	errOriginForbidden = errors.New("origin not allowed")
+)
+
+// RealtimeHandler expõe um canal WebSocket bidirecional: o cliente assina
+// listas de tarefas, recebe os eventos e a presença de quem as visualiza e
+// envia comandos, executados pelos mesmos casos de uso da API REST.
+type RealtimeHandler struct {
+	ListEventsUC *usecasetask.ListTaskEventsUseCase
+	// TaskEventsUC lista os eventos de uma tarefa e GetWorkspaceUC confere
+	// a assinatura de um workspace; ambos passam pela política.
+	TaskEventsUC   policy.UseCase[usecasetask.ListTaskEventsInput, *usecasetask.ListTaskEventsOutput]
+	GetWorkspaceUC policy.UseCase[usecaseworkspace.GetWorkspaceInput, *usecaseworkspace.GetWorkspaceOutput]
+	UpdateStatusUC policy.UseCase[usecasetask.UpdateTaskStatusInput, *usecasetask.UpdateTaskStatusOutput]
+	ReorderUC      policy.UseCase[usecasetask.ReorderTaskInput, *domainTask.Task]
+	Broker         *events.Broker
+	Presence       *events.Presence
+	// AllowedOrigins são as origens, além da do próprio serviço, de onde um
+	// navegador pode abrir a conexão; "*" aceita qualquer uma.
+	AllowedOrigins []string
+	// SendBuffer limita as mensagens pendentes por conexão; clientes que
+	// não acompanham são desconectados.
+	SendBuffer   int
+	WriteTimeout time.Duration
+	BatchSize    int
+}
+
+// @Summary Real-time task channel
+// @Description WebSocket endpoint. Authenticate with X-User-ID on the handshake or, from browsers, with
+// @Description the user_id query parameter. Browser origins other than the service's own must be allowed
+// @Description in WS_ALLOWED_ORIGINS. Then exchange JSON messages: subscribe/unsubscribe to
+// @Description "tasks:<user_id>" (own tasks), "workspace:<workspace_id>" or "task:<task_id>" (a task
+// @Description you can view, such as one shared with you), receive "event" and "presence" messages,
+// @Description send "command" messages (update_status, reorder) and "ping". Commands on workspace tasks
+// @Description carry workspace_id.
+// @Tags realtime
+// @Param X-User-ID header string false "User ID"
+// @Param user_id query string false "User ID, for clients that cannot set headers"
+// @Success 101 "Switching Protocols"
+// @Failure 401 {object} TaskErrorResponse
+// @Failure 403 "Origin not allowed"
+// @Failure 429 {object} TaskErrorResponse "Too many open streams"
+// @Failure 500 {object} TaskErrorResponse
+// @Router /api/v1/ws [get]
+func (h *RealtimeHandler) Connect(c *gin.Context) {
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, TaskErrorResponse{Error: "missing or invalid " + middleware.UserIDHeader + " header or " + middleware.UserIDQuery + " parameter"})
+		return
+	}
+
+	// Os eventos de todos os canais chegam avisando os destinatários deles
+	// (dono, quem acompanha, responsável, compartilhamentos e membros do
+	// workspace), então cada conexão ocupa uma única assinatura do usuário
+	// no broker, repartida entre os canais que ela assina.
+	sub, err := h.Broker.Subscribe(userID)
+	if err != nil {
+		if errors.Is(err, events.ErrTooManySubscriptions) {
+			c.JSON(http.StatusTooManyRequests, TaskErrorResponse{Error: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, TaskErrorResponse{Error: usecase.ErrUnknown.Error()})
+		return
+	}
+	defer sub.Close()
+
+	// O contexto da requisição continua válido enquanto a conexão estiver
+	// aberta e é cancelado no shutdown do servidor.
+	reqCtx := c.Request.Context()
+	server := websocket.Server{
+		// A autenticação não depende de cookies, mas o usuário pode vir na
+		// URL; conferir a origem impede que outro site abra a conexão em
+		// nome de quem o visita.
+		Handshake: func(_ *websocket.Config, req *http.Request) error {
+			return h.checkOrigin(req)
+		},
+		Handler: func(conn *websocket.Conn) {
+			ctx, cancel := context.WithCancel(reqCtx)
+			defer cancel()
+
+			conn.MaxPayloadBytes = 4 << 10
+			session := &realtimeSession{
+				h:        h,
+				ctx:      ctx,
+				cancel:   cancel,
+				userID:   userID,
+				sub:      sub,
+				out:      make(chan RealtimeMessage, h.SendBuffer),
+				channels: make(map[string]*realtimeChannel),
+			}
+			session.run(conn)
+		},
+	}
+	server.ServeHTTP(c.Writer, c.Request)
+}
+
+// checkOrigin aceita clientes fora do navegador, que não enviam Origin, a
+// própria origem do serviço e as de AllowedOrigins.
+func (h *RealtimeHandler) checkOrigin(req *http.Request) error {
+	origin := req.Header.Get("Origin")
+	if origin == "" {
+		return nil
+	}
+	parsed, err := url.Parse(origin)
+	if err != nil {
+		return errOriginForbidden
+	}
+	if strings.EqualFold(parsed.Host, req.Host) ||
+		slices.Contains(h.AllowedOrigins, "*") ||
+		slices.ContainsFunc(h.AllowedOrigins, func(allowed string) bool { return strings.EqualFold(allowed, origin) }) {
+		return nil
+	}
+	return errOriginForbidden
+}
+
+type realtimeSession struct {
+	h      *RealtimeHandler
+	ctx    context.Context
+	cancel context.CancelFunc
+	userID string
+	sub    *events.Subscription
+	out    chan RealtimeMessage
+
+	mu       sync.Mutex
+	channels map[string]*realtimeChannel
+}
+
+// realtimeChannel é um canal assinado na sessão. notify recebe os avisos
+// do broker repassados pela sessão.
+type realtimeChannel struct {
+	cancel context.CancelFunc
+	notify chan struct{}
+}
+
+func (s *realtimeSession) run(conn *websocket.Conn) {
+	go s.writeLoop(conn)
+	go s.dispatch()
+	defer s.closeChannels()
+
+	for {
+		var req RealtimeRequest
+		if err := websocket.JSON.Receive(conn, &req); err != nil {
+			return
+		}
+		s.handle(req)
+	}
+}
+
+func (s *realtimeSession) writeLoop(conn *websocket.Conn) {
+	defer conn.Close()
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case msg := <-s.out:
+			_ = conn.SetWriteDeadline(time.Now().Add(s.h.WriteTimeout))
+			if err := websocket.JSON.Send(conn, msg); err != nil {
+				s.cancel()
+				return
+			}
+		}
+	}
+}
+
+// dispatch repassa cada aviso do broker a todos os canais assinados; cada
+// um lê o próprio feed e descarta o que não lhe diz respeito. Avisos
+// consecutivos são agrupados em um só, como no broker.
+func (s *realtimeSession) dispatch() {
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-s.sub.C():
+			s.mu.Lock()
+			for _, ch := range s.channels {
+				select {
+				case ch.notify <- struct{}{}:
+				default:
+				}
+			}
+			s.mu.Unlock()
+		}
+	}
+}
+
+// send enfileira a mensagem sem bloquear. Se a fila estiver cheia o
+// cliente é considerado lento e a conexão é encerrada.
+func (s *realtimeSession) send(msg RealtimeMessage) {
+	select {
+	case s.out <- msg:
+	default:
+		logging.FromContext(s.ctx).Warn("closing slow websocket client", "userID", s.userID)
+		s.cancel()
+	}
+}
+
+func (s *realtimeSession) fail(id, code, message string) {
+	s.send(RealtimeMessage{Type: "error", ID: id, Error: &RealtimeError{Code: code, Message: message}})
+}
+
+// failUseCase responde com o tipo do erro de um caso de uso, sem expor
+// erros internos nem o motivo de um recurso não ser encontrado.
+func (s *realtimeSession) failUseCase(id string, err error, notFound error) {
+	kind := usecase.ErrorKind(err)
+	message := err.Error()
+	switch kind {
+	case usecase.ErrorKindInternal:
+		message = usecase.ErrUnknown.Error()
+	case usecase.ErrorKindNotFound:
+		message = notFound.Error()
+	}
+	s.fail(id, kind, message)
+}
+
+func (s *realtimeSession) handle(req RealtimeRequest) {
+	switch req.Type {
+	case "ping":
+		s.send(RealtimeMessage{Type: "pong", ID: req.ID})
+	case "subscribe":
+		s.subscribe(req)
+	case "unsubscribe":
+		s.unsubscribe(req)
+	case "command":
+		s.command(req)
+	default:
+		s.fail(req.ID, "invalid_message", "unknown message type")
+	}
+}
+
+// eventFeed lê os eventos de um canal a partir de afterID; afterID negativo
+// devolve só o ID do evento mais recente.
+type eventFeed func(ctx context.Context, afterID int64, limit int) (*usecasetask.ListTaskEventsOutput, error)
+
+// feed monta a leitura dos eventos do canal, conferindo antes se o usuário
+// pode assiná-lo.
+func (s *realtimeSession) feed(channel string) (eventFeed, error) {
+	kind, id, _ := strings.Cut(channel, ":")
+	if id == "" {
+		return nil, errInvalidChannel
+	}
+
+	switch kind {
+	case taskListChannel:
+		if id != s.userID {
+			return nil, errForeignTaskList
+		}
+		return func(ctx context.Context, afterID int64, limit int) (*usecasetask.ListTaskEventsOutput, error) {
+			return s.h.ListEventsUC.Execute(ctx, usecasetask.ListTaskEventsInput{UserID: s.userID, AfterID: afterID, Limit: limit})
+		}, nil
+
+	case workspaceChannel:
+		if _, err := s.h.GetWorkspaceUC.Execute(s.ctx, usecaseworkspace.GetWorkspaceInput{WorkspaceID: id, UserID: s.userID}); err != nil {
+			return nil, err
+		}
+		// O log só devolve eventos do workspace enquanto o usuário for
+		// membro, então quem sai dele deixa de recebê-los.
+		return func(ctx context.Context, afterID int64, limit int) (*usecasetask.ListTaskEventsOutput, error) {
+			ctx = domainWorkspace.WithScope(ctx, id)
+			return s.h.ListEventsUC.Execute(ctx, usecasetask.ListTaskEventsInput{UserID: s.userID, AfterID: afterID, Limit: limit})
+		}, nil
+
+	case taskChannel:
+		feed := func(ctx context.Context, afterID int64, limit int) (*usecasetask.ListTaskEventsOutput, error) {
+			return s.h.TaskEventsUC.Execute(ctx, usecasetask.ListTaskEventsInput{UserID: s.userID, TaskID: id, AfterID: afterID, Limit: limit})
+		}
+		// A primeira leitura confere o acesso à tarefa.
+		if _, err := feed(s.ctx, -1, 0); err != nil {
+			return nil, err
+		}
+		return feed, nil
+
+	default:
+		return nil, errInvalidChannel
+	}
+}
+
+func (s *realtimeSession) subscribe(req RealtimeRequest) {
+	s.mu.Lock()
+	_, subscribed := s.channels[req.Channel]
+	s.mu.Unlock()
+	if subscribed {
+		s.send(RealtimeMessage{Type: "ack", ID: req.ID, Channel: req.Channel})
+		return
+	}
+
+	feed, err := s.feed(req.Channel)
+	switch {
+	case errors.Is(err, errInvalidChannel):
+		s.fail(req.ID, "invalid_channel", err.Error())
+		return
+	case errors.Is(err, errForeignTaskList):
+		s.fail(req.ID, usecase.ErrorKindForbidden, err.Error())
+		return
+	case err != nil:
+		notFound := usecase.ErrTaskNotFound
+		if strings.HasPrefix(req.Channel, workspaceChannel+":") {
+			notFound = usecase.ErrWorkspaceNotFound
+		}
+		s.failUseCase(req.ID, err, notFound)
+		return
+	}
+
+	// Sem last_event_id, apenas eventos novos são entregues.
+	var afterID int64
+	if req.LastEventID != nil && *req.LastEventID >= 0 {
+		afterID = *req.LastEventID
+	} else {
+		out, err := feed(s.ctx, -1, 0)
+		if err != nil {
+			s.fail(req.ID, usecase.ErrorKindInternal, usecase.ErrUnknown.Error())
+			return
+		}
+		afterID = out.LastID
+	}
+
+	presence, leave := s.h.Presence.Join(req.Channel, s.userID)
+
+	// O canal é registrado antes da primeira leitura do log em forward,
+	// então nenhum evento confirmado depois de afterID é perdido.
+	ctx, cancel := context.WithCancel(s.ctx)
+	ch := &realtimeChannel{cancel: cancel, notify: make(chan struct{}, 1)}
+	s.mu.Lock()
+	s.channels[req.Channel] = ch
+	s.mu.Unlock()
+
+	s.send(RealtimeMessage{Type: "ack", ID: req.ID, Channel: req.Channel})
+
+	go func() {
+		defer leave()
+		s.forward(ctx, req.Channel, feed, afterID, ch.notify, presence)
+	}()
+}
+
+// forward entrega os eventos do log a partir de afterID e as mudanças de
+// presença do canal até a assinatura ser cancelada.
+func (s *realtimeSession) forward(ctx context.Context, channel string, feed eventFeed, afterID int64, notify <-chan struct{}, presence <-chan struct{}) {
+	for {
+		for {
+			out, err := feed(ctx, afterID, s.h.BatchSize)
+			if err != nil {
+				return
+			}
+			for _, event := range out.Events {
+				resp := newTaskEventResponse(event)
+				s.send(RealtimeMessage{Type: "event", Channel: channel, Event: &resp})
+			}
+			afterID = out.LastID
+			if len(out.Events) < s.h.BatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-notify:
+		case <-presence:
+			s.send(RealtimeMessage{Type: "presence", Channel: channel, Viewers: s.h.Presence.Viewers(channel)})
+		}
+	}
+}
+
+func (s *realtimeSession) unsubscribe(req RealtimeRequest) {
+	s.mu.Lock()
+	ch, ok := s.channels[req.Channel]
+	delete(s.channels, req.Channel)
+	s.mu.Unlock()
+
+	if ok {
+		ch.cancel()
+	}
+	s.send(RealtimeMessage{Type: "ack", ID: req.ID, Channel: req.Channel})
+}
+
+func (s *realtimeSession) closeChannels() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for channel, ch := range s.channels {
+		ch.cancel()
+		delete(s.channels, channel)
+	}
+	s.cancel()
+}
+
+func (s *realtimeSession) command(req RealtimeRequest) {
+	ctx := s.ctx
+	if req.WorkspaceID != "" {
+		ctx = domainWorkspace.WithScope(ctx, req.WorkspaceID)
+	}
+
+	switch req.Command {
+	case "update_status":
+		status, err := valueobject.NewStatus(req.Status)
+		if err != nil {
+			s.fail(req.ID, usecase.ErrorKindValidation, err.Error())
+			return
+		}
+		out, err := s.h.UpdateStatusUC.Execute(ctx, usecasetask.UpdateTaskStatusInput{
+			TaskID: req.TaskID,
+			Status: status,
+			UserID: s.userID,
+		})
+		if err != nil {
+			s.failUseCase(req.ID, err, usecase.ErrTaskNotFound)
+			return
+		}
+		task := newTaskResponse(&out.Task)
+		s.send(RealtimeMessage{Type: "ack", ID: req.ID, Task: &task})
+
+	case "reorder":
+		if req.Position == nil {
+			s.fail(req.ID, usecase.ErrorKindValidation, usecase.ErrInvalidPosition.Error())
+			return
+		}
+		out, err := s.h.ReorderUC.Execute(ctx, usecasetask.ReorderTaskInput{
+			TaskID:   req.TaskID,
+			UserID:   s.userID,
+			Position: *req.Position,
+		})
+		if err != nil {
+			s.failUseCase(req.ID, err, usecase.ErrTaskNotFound)
+			return
+		}
+		task := newTaskResponse(out)
+		s.send(RealtimeMessage{Type: "ack", ID: req.ID, Task: &task})
+
+	default:
+		s.fail(req.ID, "unsupported_command", "unknown command")
+	}
+}
+
+// RealtimeRequest é uma mensagem enviada pelo cliente.
+type RealtimeRequest struct {
+	Type        string `json:"type" enums:"subscribe,unsubscribe,command,ping"`
+	ID          string `json:"id,omitempty"`
+	Channel     string `json:"channel,omitempty"`
+	LastEventID *int64 `json:"last_event_id,omitempty"`
+	Command     string `json:"command,omitempty" enums:"update_status,reorder"`
+	TaskID      string `json:"task_id,omitempty"`
+	// WorkspaceID é o workspace da tarefa do comando; vazio é o espaço
+	// pessoal.
+	WorkspaceID string `json:"workspace_id,omitempty"`
+	Status      string `json:"status,omitempty"`
+	// Position é o novo índice da tarefa na lista, a partir de zero, no
+	// comando reorder.
+	Position *int `json:"position,omitempty"`
+}
+
+// RealtimeMessage é uma mensagem enviada pelo servidor.
+type RealtimeMessage struct {
+	Type    string             `json:"type" enums:"ack,error,event,presence,pong"`
+	ID      string             `json:"id,omitempty"`
+	Channel string             `json:"channel,omitempty"`
+	Event   *TaskEventResponse `json:"event,omitempty"`
+	Task    *TaskResponse      `json:"task,omitempty"`
+	Viewers []string           `json:"viewers,omitempty"`
+	Error   *RealtimeError     `json:"error,omitempty"`
+}
+
+type RealtimeError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
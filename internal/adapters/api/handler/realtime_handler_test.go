@@ -0,0 +1,225 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hoyci/todo-ddd/internal/adapters/api/middleware"
+	"github.com/hoyci/todo-ddd/internal/adapters/db/sqlite"
+	"github.com/hoyci/todo-ddd/internal/adapters/events"
+	"github.com/hoyci/todo-ddd/pkg/domain"
+	"github.com/hoyci/todo-ddd/pkg/policy"
+	usecase "github.com/hoyci/todo-ddd/pkg/usecase"
+	usecasetask "github.com/hoyci/todo-ddd/pkg/usecase/task"
+	usecaseworkspace "github.com/hoyci/todo-ddd/pkg/usecase/workspace"
+	"golang.org/x/net/websocket"
+)
+
+// realtimeServer expõe GET /ws com os casos de uso protegidos como em main.
+func realtimeServer(t *testing.T, db *sql.DB, uow domain.UnitOfWork, broker *events.Broker) *httptest.Server {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	enforcer := &policy.Enforcer{Definition: policy.Default, Roles: sqlite.NewSQLiteRoleStore(db)}
+	workspaceRepo := sqlite.NewSQLiteWorkspaceRepository(db)
+	listEvents := &usecasetask.ListTaskEventsUseCase{EventRepo: sqlite.NewSQLiteTaskEventRepository(db)}
+	h := &RealtimeHandler{
+		ListEventsUC: listEvents,
+		TaskEventsUC: policy.Guard[usecasetask.ListTaskEventsInput, *usecasetask.ListTaskEventsOutput](
+			listEvents, enforcer, policy.TaskView,
+			usecasetask.TaskResource(uow, func(in usecasetask.ListTaskEventsInput) string { return in.TaskID })),
+		GetWorkspaceUC: policy.Guard[usecaseworkspace.GetWorkspaceInput, *usecaseworkspace.GetWorkspaceOutput](
+			&usecaseworkspace.GetWorkspaceUseCase{WorkspaceRepo: workspaceRepo}, enforcer, policy.WorkspaceView,
+			usecaseworkspace.WorkspaceResource(workspaceRepo, func(in usecaseworkspace.GetWorkspaceInput) string { return in.WorkspaceID })),
+		Broker:       broker,
+		Presence:     events.NewPresence(),
+		SendBuffer:   64,
+		WriteTimeout: time.Second,
+		BatchSize:    100,
+	}
+	r := gin.New()
+	r.Use(middleware.Identity())
+	r.GET("/ws", h.Connect)
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// wsClient é uma conexão WebSocket aberta nos testes.
+type wsClient struct {
+	t    *testing.T
+	conn *websocket.Conn
+}
+
+func dialRealtime(t *testing.T, server *httptest.Server, userID string) *wsClient {
+	t.Helper()
+	config, err := websocket.NewConfig("ws"+strings.TrimPrefix(server.URL, "http")+"/ws", server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.Header.Set(middleware.UserIDHeader, userID)
+	conn, err := websocket.DialConfig(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return &wsClient{t: t, conn: conn}
+}
+
+func (c *wsClient) send(req RealtimeRequest) {
+	c.t.Helper()
+	if err := websocket.JSON.Send(c.conn, req); err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+// receive devolve a próxima mensagem que não seja de presença.
+func (c *wsClient) receive() RealtimeMessage {
+	c.t.Helper()
+	if err := c.conn.SetReadDeadline(time.Now().Add(5 * time.Second)); err != nil {
+		c.t.Fatal(err)
+	}
+	for {
+		var msg RealtimeMessage
+		if err := websocket.JSON.Receive(c.conn, &msg); err != nil {
+			c.t.Fatalf("receiving message: %v", err)
+		}
+		if msg.Type != "presence" {
+			return msg
+		}
+	}
+}
+
+// handshakeStatus abre GET /ws sem o upgrade, para ver se a conexão seria
+// recusada antes do handshake.
+func handshakeStatus(t *testing.T, server *httptest.Server, userID string) int {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/ws", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set(middleware.UserIDHeader, userID)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestRealtimeSharesOneSubscription(t *testing.T) {
+	db := newTestDB(t)
+	// Com o limite de uma conexão por usuário, assinar vários canais na
+	// mesma sessão não pode esgotá-lo.
+	broker := events.NewBroker(1)
+	uow := sqlite.NewSQLiteUnitOfWork(db, broker)
+	ctx := context.Background()
+	owner := saveTestUser(t, uow, "owner@example.com")
+	recipient := saveTestUser(t, uow, "recipient@example.com")
+
+	task, err := (&usecasetask.CreateTaskUseCase{UoW: uow}).Execute(ctx, usecasetask.CreateTaskInput{Title: "Shared", Priority: 1, UserID: owner})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := (&usecasetask.ShareTaskUseCase{UoW: uow}).Execute(ctx, usecasetask.ShareTaskInput{TaskID: task.ID, UserID: owner, TargetUserID: recipient, Permission: "view"}); err != nil {
+		t.Fatal(err)
+	}
+
+	server := realtimeServer(t, db, uow, broker)
+	client := dialRealtime(t, server, recipient)
+	channels := []string{"tasks:" + recipient, "task:" + task.ID}
+	for _, channel := range channels {
+		client.send(RealtimeRequest{Type: "subscribe", ID: channel, Channel: channel})
+		if msg := client.receive(); msg.Type != "ack" || msg.Channel != channel {
+			t.Fatalf("subscribe %s: got %+v, want an ack", channel, msg)
+		}
+	}
+
+	// Um aviso do broker chega aos dois canais.
+	if _, err := (&usecasetask.UpdateTaskUseCase{UoW: uow}).Execute(ctx, usecasetask.UpdateTaskInput{TaskID: task.ID, Title: "Renamed", Priority: 2, UserID: owner}); err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]bool)
+	for range channels {
+		msg := client.receive()
+		if msg.Type != "event" || msg.Event == nil || msg.Event.TaskID != task.ID {
+			t.Fatalf("got %+v, want an event for the task", msg)
+		}
+		got[msg.Channel] = true
+	}
+	for _, channel := range channels {
+		if !got[channel] {
+			t.Errorf("no event on %s", channel)
+		}
+	}
+
+	// A sessão ocupa a única vaga do usuário até ser fechada.
+	if status := handshakeStatus(t, server, recipient); status != http.StatusTooManyRequests {
+		t.Errorf("second connection: status = %d, want %d", status, http.StatusTooManyRequests)
+	}
+	client.conn.Close()
+	deadline := time.Now().Add(5 * time.Second)
+	for handshakeStatus(t, server, recipient) == http.StatusTooManyRequests {
+		if time.Now().After(deadline) {
+			t.Fatal("subscription not released after the connection closed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestRealtimeSubscribe(t *testing.T) {
+	db := newTestDB(t)
+	broker := events.NewBroker(0)
+	uow := sqlite.NewSQLiteUnitOfWork(db, broker)
+	ctx := context.Background()
+	owner := saveTestUser(t, uow, "owner@example.com")
+	stranger := saveTestUser(t, uow, "stranger@example.com")
+
+	task, err := (&usecasetask.CreateTaskUseCase{UoW: uow}).Execute(ctx, usecasetask.CreateTaskInput{Title: "Private", Priority: 1, UserID: owner})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := realtimeServer(t, db, uow, broker)
+	client := dialRealtime(t, server, stranger)
+	tests := []struct {
+		channel  string
+		wantCode string
+	}{
+		{"tasks:" + owner, usecase.ErrorKindForbidden},
+		{"task:" + task.ID, usecase.ErrorKindNotFound},
+		{"workspace:missing", usecase.ErrorKindNotFound},
+		{"tasks", "invalid_channel"},
+		{"projects:" + owner, "invalid_channel"},
+	}
+	for _, tt := range tests {
+		client.send(RealtimeRequest{Type: "subscribe", ID: tt.channel, Channel: tt.channel})
+		msg := client.receive()
+		if msg.Type != "error" || msg.ID != tt.channel || msg.Error == nil || msg.Error.Code != tt.wantCode {
+			t.Errorf("subscribe %s: got %+v, want error %s", tt.channel, msg, tt.wantCode)
+		}
+	}
+
+	// Depois de cancelar a assinatura, os eventos do canal deixam de chegar.
+	own := "tasks:" + stranger
+	client.send(RealtimeRequest{Type: "subscribe", ID: "sub", Channel: own})
+	if msg := client.receive(); msg.Type != "ack" {
+		t.Fatalf("subscribe own tasks: got %+v, want an ack", msg)
+	}
+	client.send(RealtimeRequest{Type: "unsubscribe", ID: "unsub", Channel: own})
+	if msg := client.receive(); msg.Type != "ack" || msg.ID != "unsub" {
+		t.Fatalf("unsubscribe: got %+v, want an ack", msg)
+	}
+	if _, err := (&usecasetask.CreateTaskUseCase{UoW: uow}).Execute(ctx, usecasetask.CreateTaskInput{Title: "Mine", Priority: 1, UserID: stranger}); err != nil {
+		t.Fatal(err)
+	}
+	client.send(RealtimeRequest{Type: "ping", ID: "ping"})
+	if msg := client.receive(); msg.Type != "pong" {
+		t.Errorf("after unsubscribing: got %+v, want only the pong", msg)
+	}
+}
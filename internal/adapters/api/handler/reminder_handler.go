@@ -0,0 +1,189 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/hoyci/todo-ddd/internal/adapters/api/middleware"
+	domainTask "github.com/hoyci/todo-ddd/pkg/domain/task"
+	"github.com/hoyci/todo-ddd/pkg/policy"
+	usecasetask "github.com/hoyci/todo-ddd/pkg/usecase/task"
+)
+
+type ReminderHandler struct {
+	AddUC    policy.UseCase[usecasetask.AddReminderInput, *domainTask.Reminder]
+	ListUC   policy.UseCase[usecasetask.ListRemindersInput, []*domainTask.Reminder]
+	DeleteUC policy.Command[usecasetask.DeleteReminderInput]
+	Validate *validator.Validate
+}
+
+//
+// ------------------- ADD -------------------
+//
+
+// @Summary Add a reminder to a task
+// @Description Schedules a reminder for the caller, either at an absolute time or a duration before
+// @Description the task due date (exactly one of at and before_due). Offset reminders follow the due
+// @Description date when it changes. Requires view access.
+// @Tags reminders
+// @Accept json
+// @Produce json
+// @Param X-User-ID header string true "User ID"
+// @Param id path string true "Task ID"
+// @Param body body ReminderRequest true "Reminder"
+// @Success 201 {object} ReminderResponse
+// @Failure 400 {object} TaskErrorResponse
+// @Failure 401 {object} TaskErrorResponse
+// @Failure 404 {object} TaskErrorResponse "Task not found"
+// @Failure 422 {object} TaskErrorResponse "Invalid or past reminder time, or task without due date"
+// @Failure 500 {object} TaskErrorResponse
+// @Router /api/v1/tasks/{id}/reminders [post]
+func (h *ReminderHandler) Add(c *gin.Context) {
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, TaskErrorResponse{Error: "missing or invalid " + middleware.UserIDHeader + " header"})
+		return
+	}
+
+	var req ReminderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, TaskErrorResponse{Error: err.Error()})
+		return
+	}
+	if err := h.Validate.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, TaskErrorResponse{Error: err.Error()})
+		return
+	}
+	var beforeDue time.Duration
+	if req.BeforeDue != "" {
+		var err error
+		if beforeDue, err = time.ParseDuration(req.BeforeDue); err != nil {
+			c.JSON(http.StatusBadRequest, TaskErrorResponse{Error: "before_due must be a duration such as 30m or 24h"})
+			return
+		}
+	}
+
+	reminder, err := h.AddUC.Execute(c.Request.Context(), usecasetask.AddReminderInput{
+		TaskID:    c.Param("id"),
+		UserID:    userID,
+		At:        req.At,
+		BeforeDue: beforeDue,
+	})
+	if err != nil {
+		taskError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, newReminderResponse(reminder))
+}
+
+//
+// ------------------- LIST -------------------
+//
+
+// @Summary List my reminders on a task
+// @Description Lists the reminders the caller scheduled on the task, fired or not, by fire time.
+// @Tags reminders
+// @Produce json
+// @Param X-User-ID header string true "User ID"
+// @Param id path string true "Task ID"
+// @Success 200 {array} ReminderResponse
+// @Failure 401 {object} TaskErrorResponse
+// @Failure 404 {object} TaskErrorResponse "Task not found"
+// @Failure 500 {object} TaskErrorResponse
+// @Router /api/v1/tasks/{id}/reminders [get]
+func (h *ReminderHandler) List(c *gin.Context) {
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, TaskErrorResponse{Error: "missing or invalid " + middleware.UserIDHeader + " header"})
+		return
+	}
+
+	reminders, err := h.ListUC.Execute(c.Request.Context(), usecasetask.ListRemindersInput{
+		TaskID: c.Param("id"),
+		UserID: userID,
+	})
+	if err != nil {
+		taskError(c, err)
+		return
+	}
+
+	resp := make([]ReminderResponse, 0, len(reminders))
+	for _, reminder := range reminders {
+		resp = append(resp, newReminderResponse(reminder))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+//
+// ------------------- DELETE -------------------
+//
+
+// @Summary Cancel a reminder
+// @Description Deletes one of the caller's reminders on the task.
+// @Tags reminders
+// @Param X-User-ID header string true "User ID"
+// @Param id path string true "Task ID"
+// @Param reminder_id path string true "Reminder ID"
+// @Success 204 "No Content"
+// @Failure 401 {object} TaskErrorResponse
+// @Failure 404 {object} TaskErrorResponse "Task or reminder not found"
+// @Failure 500 {object} TaskErrorResponse
+// @Router /api/v1/tasks/{id}/reminders/{reminder_id} [delete]
+func (h *ReminderHandler) Delete(c *gin.Context) {
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, TaskErrorResponse{Error: "missing or invalid " + middleware.UserIDHeader + " header"})
+		return
+	}
+
+	err := h.DeleteUC.Execute(c.Request.Context(), usecasetask.DeleteReminderInput{
+		TaskID:     c.Param("id"),
+		ReminderID: c.Param("reminder_id"),
+		UserID:     userID,
+	})
+	if err != nil {
+		taskError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+//
+// ------------------- REQUESTS / RESPONSES -------------------
+//
+
+type ReminderRequest struct {
+	At *time.Time `json:"at" validate:"required_without=BeforeDue" example:"2026-03-01T09:00:00Z"`
+	// BeforeDue é uma duração no formato do Go (30m, 2h, 24h).
+	BeforeDue string `json:"before_due" validate:"required_without=At" example:"1h"`
+}
+
+type ReminderResponse struct {
+	ID        string     `json:"id"`
+	TaskID    string     `json:"task_id"`
+	At        *time.Time `json:"at,omitempty"`
+	BeforeDue string     `json:"before_due,omitempty" example:"1h0m0s"`
+	// FireAt já considera adiamentos pelo horário de silêncio.
+	FireAt    time.Time  `json:"fire_at"`
+	FiredAt   *time.Time `json:"fired_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func newReminderResponse(reminder *domainTask.Reminder) ReminderResponse {
+	resp := ReminderResponse{
+		ID:        reminder.ID,
+		TaskID:    reminder.TaskID,
+		At:        reminder.At,
+		FireAt:    reminder.FireAt,
+		FiredAt:   reminder.FiredAt,
+		CreatedAt: reminder.CreatedAt,
+	}
+	if reminder.BeforeDue > 0 {
+		resp.BeforeDue = reminder.BeforeDue.String()
+	}
+	return resp
+}
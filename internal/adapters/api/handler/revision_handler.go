@@ -0,0 +1,242 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/hoyci/todo-ddd/internal/adapters/api/middleware"
+	domainTask "github.com/hoyci/todo-ddd/pkg/domain/task"
+	"github.com/hoyci/todo-ddd/pkg/policy"
+	usecasetask "github.com/hoyci/todo-ddd/pkg/usecase/task"
+)
+
+type RevisionHandler struct {
+	ListUC   policy.UseCase[usecasetask.ListRevisionsInput, *usecasetask.ListRevisionsOutput]
+	DiffUC   policy.UseCase[usecasetask.DiffRevisionsInput, *domainTask.RevisionDiff]
+	RevertUC policy.UseCase[usecasetask.RevertTaskInput, *usecasetask.RevertTaskOutput]
+	Validate *validator.Validate
+}
+
+//
+// ------------------- LIST -------------------
+//
+
+// @Summary List task revisions
+// @Description Lists every saved state of a task, oldest first. A revision is recorded on each
+// @Description create, update, status change, assignment and revert.
+// @Tags revisions
+// @Produce json
+// @Param X-User-ID header string true "User ID"
+// @Param id path string true "Task ID"
+// @Success 200 {array} RevisionResponse
+// @Failure 401 {object} TaskErrorResponse
+// @Failure 404 {object} TaskErrorResponse "Task not found"
+// @Failure 500 {object} TaskErrorResponse
+// @Router /api/v1/tasks/{id}/revisions [get]
+func (h *RevisionHandler) List(c *gin.Context) {
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, TaskErrorResponse{Error: "missing or invalid " + middleware.UserIDHeader + " header"})
+		return
+	}
+
+	out, err := h.ListUC.Execute(c.Request.Context(), usecasetask.ListRevisionsInput{
+		TaskID: c.Param("id"),
+		UserID: userID,
+	})
+	if err != nil {
+		taskError(c, err)
+		return
+	}
+
+	resp := make([]RevisionResponse, 0, len(out.Revisions))
+	for _, revision := range out.Revisions {
+		resp = append(resp, newRevisionResponse(revision))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+//
+// ------------------- DIFF -------------------
+//
+
+// @Summary Diff two task revisions
+// @Description Compares two revisions of a task: the fields that differ and a line diff of the
+// @Description description. from may be newer than to.
+// @Tags revisions
+// @Produce json
+// @Param X-User-ID header string true "User ID"
+// @Param id path string true "Task ID"
+// @Param from query int true "Base revision number"
+// @Param to query int true "Compared revision number"
+// @Success 200 {object} RevisionDiffResponse
+// @Failure 400 {object} TaskErrorResponse
+// @Failure 401 {object} TaskErrorResponse
+// @Failure 404 {object} TaskErrorResponse "Task or revision not found"
+// @Failure 500 {object} TaskErrorResponse
+// @Router /api/v1/tasks/{id}/revisions/diff [get]
+func (h *RevisionHandler) Diff(c *gin.Context) {
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, TaskErrorResponse{Error: "missing or invalid " + middleware.UserIDHeader + " header"})
+		return
+	}
+
+	var req DiffRevisionsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, TaskErrorResponse{Error: err.Error()})
+		return
+	}
+	if err := h.Validate.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, TaskErrorResponse{Error: err.Error()})
+		return
+	}
+
+	diff, err := h.DiffUC.Execute(c.Request.Context(), usecasetask.DiffRevisionsInput{
+		TaskID: c.Param("id"),
+		UserID: userID,
+		From:   req.From,
+		To:     req.To,
+	})
+	if err != nil {
+		taskError(c, err)
+		return
+	}
+
+	resp := RevisionDiffResponse{
+		From:    diff.From,
+		To:      diff.To,
+		Changes: make([]FieldChangeResponse, 0, len(diff.Changes)),
+	}
+	for _, change := range diff.Changes {
+		resp.Changes = append(resp.Changes, FieldChangeResponse{
+			Field: change.Field,
+			From:  change.From,
+			To:    change.To,
+		})
+	}
+	for _, line := range diff.Description {
+		resp.Description = append(resp.Description, LineChangeResponse{
+			Op:   string(line.Op),
+			Text: line.Text,
+		})
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+//
+// ------------------- REVERT -------------------
+//
+
+// @Summary Revert a task to a revision
+// @Description Restores the fields of a task from one of its revisions. The result is recorded as
+// @Description a new revision; earlier revisions are kept. Requires edit access.
+// @Tags revisions
+// @Produce json
+// @Param X-User-ID header string true "User ID"
+// @Param id path string true "Task ID"
+// @Param number path int true "Revision number"
+// @Success 200 {object} RevertTaskResponse
+// @Header 200 {string} X-Undo-Token "Token for POST /api/v1/undo/{token}"
+// @Header 200 {string} X-Undo-Expires-At "When the undo token expires (RFC 3339)"
+// @Failure 400 {object} TaskErrorResponse
+// @Failure 401 {object} TaskErrorResponse
+// @Failure 403 {object} TaskErrorResponse
+// @Failure 404 {object} TaskErrorResponse "Task or revision not found"
+// @Failure 500 {object} TaskErrorResponse
+// @Router /api/v1/tasks/{id}/revisions/{number}/revert [post]
+func (h *RevisionHandler) Revert(c *gin.Context) {
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, TaskErrorResponse{Error: "missing or invalid " + middleware.UserIDHeader + " header"})
+		return
+	}
+
+	number, err := strconv.Atoi(c.Param("number"))
+	if err != nil || number < 1 {
+		c.JSON(http.StatusBadRequest, TaskErrorResponse{Error: "revision number must be a positive integer"})
+		return
+	}
+
+	out, err := h.RevertUC.Execute(c.Request.Context(), usecasetask.RevertTaskInput{
+		TaskID: c.Param("id"),
+		UserID: userID,
+		Number: number,
+	})
+	if err != nil {
+		taskError(c, err)
+		return
+	}
+
+	resp := RevertTaskResponse{Task: newTaskResponse(&out.Task)}
+	if out.Revision != nil {
+		revision := newRevisionResponse(out.Revision)
+		resp.Revision = &revision
+	}
+	setUndoHeaders(c, out.Undo)
+	c.JSON(http.StatusOK, resp)
+}
+
+//
+// ------------------- REQUESTS / RESPONSES -------------------
+//
+
+type DiffRevisionsRequest struct {
+	From int `form:"from" validate:"required,min=1"`
+	To   int `form:"to" validate:"required,min=1"`
+}
+
+type RevisionResponse struct {
+	Number      int        `json:"number" example:"3"`
+	AuthorID    string     `json:"author_id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Priority    int        `json:"priority"`
+	Status      string     `json:"status"`
+	AssigneeID  string     `json:"assignee_id,omitempty"`
+	DueAt       *time.Time `json:"due_at,omitempty"`
+	Tags        []string   `json:"tags,omitempty"`
+	Recurrence  string     `json:"recurrence,omitempty" example:"FREQ=WEEKLY;INTERVAL=2"`
+	// RevertedFrom só aparece em revisões criadas por uma reversão.
+	RevertedFrom int       `json:"reverted_from,omitempty" example:"1"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type RevisionDiffResponse struct {
+	From    int                   `json:"from"`
+	To      int                   `json:"to"`
+	Changes []FieldChangeResponse `json:"changes"`
+	// Description é omitido quando a descrição não mudou.
+	Description []LineChangeResponse `json:"description,omitempty"`
+}
+
+type LineChangeResponse struct {
+	Op   string `json:"op" enums:"equal,insert,delete"`
+	Text string `json:"text"`
+}
+
+type RevertTaskResponse struct {
+	Task TaskResponse `json:"task"`
+	// Revision é omitido quando a tarefa já estava no estado pedido.
+	Revision *RevisionResponse `json:"revision,omitempty"`
+}
+
+func newRevisionResponse(revision *domainTask.Revision) RevisionResponse {
+	return RevisionResponse{
+		Number:       revision.Number,
+		AuthorID:     revision.AuthorID,
+		Title:        revision.Title,
+		Description:  revision.Description,
+		Priority:     int(revision.Priority),
+		Status:       string(revision.Status),
+		AssigneeID:   revision.AssigneeID,
+		DueAt:        revision.DueAt,
+		Tags:         tagStrings(revision.Tags),
+		Recurrence:   revision.Recurrence.String(),
+		RevertedFrom: revision.RevertedFrom,
+		CreatedAt:    revision.CreatedAt,
+	}
+}
@@ -0,0 +1,375 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/hoyci/todo-ddd/internal/adapters/api/middleware"
+	domainTask "github.com/hoyci/todo-ddd/pkg/domain/task"
+	"github.com/hoyci/todo-ddd/pkg/policy"
+	usecasetask "github.com/hoyci/todo-ddd/pkg/usecase/task"
+)
+
+type SharingHandler struct {
+	AssignUC       policy.UseCase[usecasetask.AssignTaskInput, *usecasetask.AssignTaskOutput]
+	AssignedUC     *usecasetask.ListAssignedTasksUseCase
+	ShareUC        policy.UseCase[usecasetask.ShareTaskInput, *domainTask.Share]
+	RevokeUC       policy.Command[usecasetask.RevokeShareInput]
+	ListSharesUC   policy.UseCase[usecasetask.ListSharesInput, []*domainTask.Share]
+	WatchUC        policy.Command[usecasetask.WatchTaskInput]
+	UnwatchUC      policy.Command[usecasetask.WatchTaskInput]
+	ListWatchersUC policy.UseCase[usecasetask.ListWatchersInput, []*domainTask.Watcher]
+	Validate       *validator.Validate
+}
+
+//
+// ------------------- ASSIGN -------------------
+//
+
+// @Summary Assign a task
+// @Description Sets who is responsible for the task. The assignee can edit the task and starts
+// @Description watching it. Requires edit access.
+// @Tags sharing
+// @Accept json
+// @Produce json
+// @Param X-User-ID header string true "User ID"
+// @Param id path string true "Task ID"
+// @Param body body AssignTaskRequest true "Assignee"
+// @Success 200 {object} TaskResponse
+// @Header 200 {string} X-Undo-Token "Token for POST /api/v1/undo/{token}"
+// @Header 200 {string} X-Undo-Expires-At "When the undo token expires (RFC 3339)"
+// @Failure 400 {object} TaskErrorResponse
+// @Failure 401 {object} TaskErrorResponse
+// @Failure 403 {object} TaskErrorResponse
+// @Failure 404 {object} TaskErrorResponse "Task or assignee not found"
+// @Failure 500 {object} TaskErrorResponse
+// @Router /api/v1/tasks/{id}/assignee [put]
+func (h *SharingHandler) Assign(c *gin.Context) {
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, TaskErrorResponse{Error: "missing or invalid " + middleware.UserIDHeader + " header"})
+		return
+	}
+
+	var req AssignTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, TaskErrorResponse{Error: err.Error()})
+		return
+	}
+	if err := h.Validate.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, TaskErrorResponse{Error: err.Error()})
+		return
+	}
+
+	h.assign(c, userID, req.AssigneeID)
+}
+
+// @Summary Unassign a task
+// @Description Removes the assignee. Requires edit access.
+// @Tags sharing
+// @Produce json
+// @Param X-User-ID header string true "User ID"
+// @Param id path string true "Task ID"
+// @Success 200 {object} TaskResponse
+// @Header 200 {string} X-Undo-Token "Token for POST /api/v1/undo/{token}"
+// @Header 200 {string} X-Undo-Expires-At "When the undo token expires (RFC 3339)"
+// @Failure 401 {object} TaskErrorResponse
+// @Failure 403 {object} TaskErrorResponse
+// @Failure 404 {object} TaskErrorResponse "Task not found"
+// @Failure 500 {object} TaskErrorResponse
+// @Router /api/v1/tasks/{id}/assignee [delete]
+func (h *SharingHandler) Unassign(c *gin.Context) {
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, TaskErrorResponse{Error: "missing or invalid " + middleware.UserIDHeader + " header"})
+		return
+	}
+
+	h.assign(c, userID, "")
+}
+
+func (h *SharingHandler) assign(c *gin.Context, userID, assigneeID string) {
+	out, err := h.AssignUC.Execute(c.Request.Context(), usecasetask.AssignTaskInput{
+		TaskID:     c.Param("id"),
+		UserID:     userID,
+		AssigneeID: assigneeID,
+	})
+	if err != nil {
+		taskError(c, err)
+		return
+	}
+
+	setUndoHeaders(c, out.Undo)
+	c.JSON(http.StatusOK, newTaskResponse(&out.Task))
+}
+
+// @Summary List tasks assigned to me
+// @Description Lists the active tasks assigned to the caller, from any owner, by due date.
+// @Tags sharing
+// @Produce json
+// @Param X-User-ID header string true "User ID"
+// @Success 200 {array} TaskResponse
+// @Failure 401 {object} TaskErrorResponse
+// @Failure 500 {object} TaskErrorResponse
+// @Router /api/v1/tasks/assigned [get]
+func (h *SharingHandler) Assigned(c *gin.Context) {
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, TaskErrorResponse{Error: "missing or invalid " + middleware.UserIDHeader + " header"})
+		return
+	}
+
+	tasks, err := h.AssignedUC.Execute(c.Request.Context(), userID)
+	if err != nil {
+		taskError(c, err)
+		return
+	}
+
+	resp := make([]TaskResponse, 0, len(tasks))
+	for _, task := range tasks {
+		resp = append(resp, newTaskResponse(task))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+//
+// ------------------- SHARES -------------------
+//
+
+// @Summary Share a task
+// @Description Grants another user view or edit access to the task, or changes the permission of
+// @Description an existing grant. Only the owner can share.
+// @Tags sharing
+// @Accept json
+// @Produce json
+// @Param X-User-ID header string true "User ID"
+// @Param id path string true "Task ID"
+// @Param user_id path string true "User receiving access"
+// @Param body body ShareTaskRequest true "Permission"
+// @Success 200 {object} ShareResponse
+// @Failure 400 {object} TaskErrorResponse
+// @Failure 401 {object} TaskErrorResponse
+// @Failure 403 {object} TaskErrorResponse
+// @Failure 404 {object} TaskErrorResponse "Task or user not found"
+// @Failure 422 {object} TaskErrorResponse
+// @Failure 500 {object} TaskErrorResponse
+// @Router /api/v1/tasks/{id}/shares/{user_id} [put]
+func (h *SharingHandler) Share(c *gin.Context) {
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, TaskErrorResponse{Error: "missing or invalid " + middleware.UserIDHeader + " header"})
+		return
+	}
+
+	var req ShareTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, TaskErrorResponse{Error: err.Error()})
+		return
+	}
+	if err := h.Validate.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, TaskErrorResponse{Error: err.Error()})
+		return
+	}
+
+	share, err := h.ShareUC.Execute(c.Request.Context(), usecasetask.ShareTaskInput{
+		TaskID:       c.Param("id"),
+		UserID:       userID,
+		TargetUserID: c.Param("user_id"),
+		Permission:   req.Permission,
+	})
+	if err != nil {
+		taskError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newShareResponse(share))
+}
+
+// @Summary Revoke a share
+// @Description Removes the user's access to the task. Only the owner can revoke.
+// @Tags sharing
+// @Param X-User-ID header string true "User ID"
+// @Param id path string true "Task ID"
+// @Param user_id path string true "User losing access"
+// @Success 204 "No Content"
+// @Failure 401 {object} TaskErrorResponse
+// @Failure 403 {object} TaskErrorResponse
+// @Failure 404 {object} TaskErrorResponse "Task or share not found"
+// @Failure 500 {object} TaskErrorResponse
+// @Router /api/v1/tasks/{id}/shares/{user_id} [delete]
+func (h *SharingHandler) Revoke(c *gin.Context) {
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, TaskErrorResponse{Error: "missing or invalid " + middleware.UserIDHeader + " header"})
+		return
+	}
+
+	err := h.RevokeUC.Execute(c.Request.Context(), usecasetask.RevokeShareInput{
+		TaskID:       c.Param("id"),
+		UserID:       userID,
+		TargetUserID: c.Param("user_id"),
+	})
+	if err != nil {
+		taskError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary List shares
+// @Description Lists who the task is shared with.
+// @Tags sharing
+// @Produce json
+// @Param X-User-ID header string true "User ID"
+// @Param id path string true "Task ID"
+// @Success 200 {array} ShareResponse
+// @Failure 401 {object} TaskErrorResponse
+// @Failure 404 {object} TaskErrorResponse "Task not found"
+// @Failure 500 {object} TaskErrorResponse
+// @Router /api/v1/tasks/{id}/shares [get]
+func (h *SharingHandler) ListShares(c *gin.Context) {
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, TaskErrorResponse{Error: "missing or invalid " + middleware.UserIDHeader + " header"})
+		return
+	}
+
+	shares, err := h.ListSharesUC.Execute(c.Request.Context(), usecasetask.ListSharesInput{
+		TaskID: c.Param("id"),
+		UserID: userID,
+	})
+	if err != nil {
+		taskError(c, err)
+		return
+	}
+
+	resp := make([]ShareResponse, 0, len(shares))
+	for _, share := range shares {
+		resp = append(resp, newShareResponse(share))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+//
+// ------------------- WATCHERS -------------------
+//
+
+// @Summary Watch a task
+// @Description The caller starts receiving the task's events on their event stream.
+// @Tags sharing
+// @Param X-User-ID header string true "User ID"
+// @Param id path string true "Task ID"
+// @Success 204 "No Content"
+// @Failure 401 {object} TaskErrorResponse
+// @Failure 404 {object} TaskErrorResponse "Task not found"
+// @Failure 500 {object} TaskErrorResponse
+// @Router /api/v1/tasks/{id}/watch [put]
+func (h *SharingHandler) Watch(c *gin.Context) {
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, TaskErrorResponse{Error: "missing or invalid " + middleware.UserIDHeader + " header"})
+		return
+	}
+
+	err := h.WatchUC.Execute(c.Request.Context(), usecasetask.WatchTaskInput{TaskID: c.Param("id"), UserID: userID})
+	if err != nil {
+		taskError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Stop watching a task
+// @Tags sharing
+// @Param X-User-ID header string true "User ID"
+// @Param id path string true "Task ID"
+// @Success 204 "No Content"
+// @Failure 401 {object} TaskErrorResponse
+// @Failure 404 {object} TaskErrorResponse "Task not found"
+// @Failure 500 {object} TaskErrorResponse
+// @Router /api/v1/tasks/{id}/watch [delete]
+func (h *SharingHandler) Unwatch(c *gin.Context) {
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, TaskErrorResponse{Error: "missing or invalid " + middleware.UserIDHeader + " header"})
+		return
+	}
+
+	err := h.UnwatchUC.Execute(c.Request.Context(), usecasetask.WatchTaskInput{TaskID: c.Param("id"), UserID: userID})
+	if err != nil {
+		taskError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary List watchers
+// @Tags sharing
+// @Produce json
+// @Param X-User-ID header string true "User ID"
+// @Param id path string true "Task ID"
+// @Success 200 {array} WatcherResponse
+// @Failure 401 {object} TaskErrorResponse
+// @Failure 404 {object} TaskErrorResponse "Task not found"
+// @Failure 500 {object} TaskErrorResponse
+// @Router /api/v1/tasks/{id}/watchers [get]
+func (h *SharingHandler) ListWatchers(c *gin.Context) {
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, TaskErrorResponse{Error: "missing or invalid " + middleware.UserIDHeader + " header"})
+		return
+	}
+
+	watchers, err := h.ListWatchersUC.Execute(c.Request.Context(), usecasetask.ListWatchersInput{
+		TaskID: c.Param("id"),
+		UserID: userID,
+	})
+	if err != nil {
+		taskError(c, err)
+		return
+	}
+
+	resp := make([]WatcherResponse, 0, len(watchers))
+	for _, watcher := range watchers {
+		resp = append(resp, WatcherResponse{UserID: watcher.UserID, CreatedAt: watcher.CreatedAt})
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+//
+// ------------------- REQUESTS / RESPONSES -------------------
+//
+
+type AssignTaskRequest struct {
+	AssigneeID string `json:"assignee_id" validate:"required"`
+}
+
+type ShareTaskRequest struct {
+	Permission string `json:"permission" validate:"required" enums:"view,edit" example:"edit"`
+}
+
+type ShareResponse struct {
+	UserID     string    `json:"user_id"`
+	Permission string    `json:"permission" example:"view"`
+	GrantedBy  string    `json:"granted_by"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type WatcherResponse struct {
+	UserID    string    `json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func newShareResponse(share *domainTask.Share) ShareResponse {
+	return ShareResponse{
+		UserID:     share.UserID,
+		Permission: string(share.Permission),
+		GrantedBy:  share.GrantedBy,
+		CreatedAt:  share.CreatedAt,
+	}
+}
@@ -1,23 +1,36 @@
 package handler
 
 import (
+	"cmp"
 	"errors"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
+	"github.com/hoyci/todo-ddd/internal/adapters/api/middleware"
+	"github.com/hoyci/todo-ddd/internal/adapters/export"
+	"github.com/hoyci/todo-ddd/internal/adapters/importer"
+	domainTask "github.com/hoyci/todo-ddd/pkg/domain/task"
 	"github.com/hoyci/todo-ddd/pkg/domain/valueobject"
+	"github.com/hoyci/todo-ddd/pkg/logging"
+	"github.com/hoyci/todo-ddd/pkg/policy"
 	usecase "github.com/hoyci/todo-ddd/pkg/usecase"
 	usecasetask "github.com/hoyci/todo-ddd/pkg/usecase/task"
 )
 
 type TaskHandler struct {
-	CreateUC       *usecasetask.CreateTaskUseCase
-	UpdateUC       *usecasetask.UpdateTaskUseCase
-	UpdateStatusUC *usecasetask.UpdateTaskStatusUseCase
-	DeleteUC       *usecasetask.DeleteTaskUseCase
-	ListUC         *usecasetask.ListTaskUseCase
+	CreateUC       policy.UseCase[usecasetask.CreateTaskInput, *usecasetask.CreateTaskOutput]
+	UpdateUC       policy.UseCase[usecasetask.UpdateTaskInput, *usecasetask.UpdateTaskOutput]
+	UpdateStatusUC policy.UseCase[usecasetask.UpdateTaskStatusInput, *usecasetask.UpdateTaskStatusOutput]
+	DeleteUC       policy.UseCase[usecasetask.DeleteTaskInput, *usecasetask.DeleteTaskOutput]
+	ListUC         policy.UseCase[string, []usecasetask.ListTaskOutput]
+	BatchUC        policy.UseCase[usecasetask.BatchTaskInput, *usecasetask.BatchTaskOutput]
+	PatchUC        policy.UseCase[usecasetask.PatchTaskInput, *usecasetask.PatchTaskOutput]
+	ExportUC       policy.UseCase[usecasetask.ExportTasksInput, *usecasetask.ExportTasksOutput]
+	ImportUC       policy.UseCase[usecasetask.ImportTasksInput, *usecasetask.ImportTasksOutput]
+	QuickAddUC     policy.UseCase[usecasetask.QuickAddTaskInput, *usecasetask.QuickAddTaskOutput]
 	Validate       *validator.Validate
 }
 
@@ -30,11 +43,24 @@ type TaskHandler struct {
 // @Tags tasks
 // @Accept json
 // @Produce json
+// @Param X-User-ID header string true "User ID"
 // @Param task body CreateTaskRequest true "Task data"
+// @Param X-Workspace-ID header string false "Workspace ID; omitted for the personal space"
 // @Success 201 {object} TaskResponse
+// @Header 201 {string} X-Undo-Token "Token for POST /api/v1/undo/{token}"
+// @Header 201 {string} X-Undo-Expires-At "When the undo token expires (RFC 3339)"
 // @Failure 400 {object} map[string]string
+// @Failure 401 {object} TaskErrorResponse
+// @Failure 403 {object} TaskErrorResponse "Not allowed to create tasks here, or email not verified"
+// @Failure 404 {object} TaskErrorResponse "Workspace not found"
 // @Router /api/v1/tasks [post]
 func (h *TaskHandler) Create(c *gin.Context) {
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, TaskErrorResponse{Error: "missing or invalid " + middleware.UserIDHeader + " header"})
+		return
+	}
+
 	var req CreateTaskRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -46,11 +72,11 @@ func (h *TaskHandler) Create(c *gin.Context) {
 		return
 	}
 
-	out, err := h.CreateUC.Execute(usecasetask.CreateTaskInput{
+	out, err := h.CreateUC.Execute(c.Request.Context(), usecasetask.CreateTaskInput{
 		Title:       req.Title,
 		Description: req.Description,
 		Priority:    valueobject.Priority(req.Priority),
-		UserID:      req.UserID,
+		UserID:      userID,
 	})
 	if err != nil {
 		switch {
@@ -64,10 +90,19 @@ func (h *TaskHandler) Create(c *gin.Context) {
 				Error: usecase.ErrUnknown.Error(),
 			})
 			return
+		case errors.Is(err, usecase.ErrWorkspaceNotFound):
+			c.JSON(http.StatusNotFound, TaskErrorResponse{Error: err.Error()})
+			return
+		case errors.Is(err, usecase.ErrWorkspaceForbidden),
+			errors.Is(err, usecase.ErrEmailNotVerified),
+			errors.Is(err, policy.ErrDenied):
+			c.JSON(http.StatusForbidden, TaskErrorResponse{Error: err.Error()})
+			return
 		case errors.Is(err, usecase.ErrUnknown):
 			c.JSON(http.StatusInternalServerError, TaskErrorResponse{
 				Error: usecase.ErrUnknown.Error(),
 			})
+			return
 		default:
 			c.JSON(http.StatusInternalServerError, TaskErrorResponse{
 				Error: usecase.ErrUnknown.Error(),
@@ -78,15 +113,8 @@ func (h *TaskHandler) Create(c *gin.Context) {
 
 	task := out.Task
 
-	c.JSON(http.StatusCreated, TaskResponse{
-		ID:          task.ID,
-		Title:       task.Title,
-		Description: task.Description,
-		Priority:    int(task.Priority),
-		Status:      string(task.Status),
-		CreatedAt:   task.CreatedAt,
-		UpdatedAt:   task.UpdatedAt,
-	})
+	setUndoHeaders(c, out.Undo)
+	c.JSON(http.StatusCreated, newTaskResponse(task))
 }
 
 //
@@ -98,11 +126,22 @@ func (h *TaskHandler) Create(c *gin.Context) {
 // @Tags tasks
 // @Accept json
 // @Produce json
+// @Param X-User-ID header string true "User ID"
 // @Param id path string true "Task ID"
 // @Param task body UpdateTaskRequest true "Updated data"
 // @Success 200 {object} TaskResponse
+// @Header 200 {string} X-Undo-Token "Token for POST /api/v1/undo/{token}"
+// @Header 200 {string} X-Undo-Expires-At "When the undo token expires (RFC 3339)"
+// @Failure 401 {object} TaskErrorResponse
+// @Failure 403 {object} TaskErrorResponse
+// @Failure 404 {object} TaskErrorResponse "Task not found"
 // @Router /api/v1/tasks/{id} [put]
 func (h *TaskHandler) Update(c *gin.Context) {
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, TaskErrorResponse{Error: "missing or invalid " + middleware.UserIDHeader + " header"})
+		return
+	}
 	id := c.Param("id")
 
 	var req UpdateTaskRequest
@@ -115,27 +154,20 @@ func (h *TaskHandler) Update(c *gin.Context) {
 		return
 	}
 
-	task, err := h.UpdateUC.Execute(usecasetask.UpdateTaskInput{
+	task, err := h.UpdateUC.Execute(c.Request.Context(), usecasetask.UpdateTaskInput{
 		TaskID:      id,
-		UserID:      "123",
+		UserID:      userID,
 		Title:       req.Title,
 		Description: req.Description,
 		Priority:    valueobject.Priority(req.Priority),
 	})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		taskError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, TaskResponse{
-		ID:          task.ID,
-		Title:       task.Title,
-		Description: task.Description,
-		Priority:    int(task.Priority),
-		Status:      string(task.Status),
-		CreatedAt:   task.CreatedAt,
-		UpdatedAt:   task.UpdatedAt,
-	})
+	setUndoHeaders(c, task.Undo)
+	c.JSON(http.StatusOK, newTaskResponse(&task.Task))
 }
 
 //
@@ -147,12 +179,23 @@ func (h *TaskHandler) Update(c *gin.Context) {
 // @Tags tasks
 // @Accept json
 // @Produce json
+// @Param X-User-ID header string true "User ID"
 // @Param id path string true "Task ID"
 // @Param body body UpdateTaskStatusRequest true "Status data"
 // @Success 200 {object} TaskResponse
+// @Header 200 {string} X-Undo-Token "Token for POST /api/v1/undo/{token}"
+// @Header 200 {string} X-Undo-Expires-At "When the undo token expires (RFC 3339)"
 // @Failure 400 {object} map[string]string
+// @Failure 401 {object} TaskErrorResponse
+// @Failure 403 {object} TaskErrorResponse
+// @Failure 404 {object} TaskErrorResponse "Task not found"
 // @Router /api/v1/tasks/{id}/status [patch]
 func (h *TaskHandler) UpdateStatus(c *gin.Context) {
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, TaskErrorResponse{Error: "missing or invalid " + middleware.UserIDHeader + " header"})
+		return
+	}
 	id := c.Param("id")
 
 	var req UpdateTaskStatusRequest
@@ -164,23 +207,16 @@ func (h *TaskHandler) UpdateStatus(c *gin.Context) {
 	input := usecasetask.UpdateTaskStatusInput{
 		TaskID: id,
 		Status: valueobject.Status(req.Status),
-		UserID: "123",
+		UserID: userID,
 	}
-	task, err := h.UpdateStatusUC.Execute(input)
+	task, err := h.UpdateStatusUC.Execute(c.Request.Context(), input)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		taskError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, TaskResponse{
-		ID:          task.ID,
-		Title:       task.Title,
-		Description: task.Description,
-		Priority:    int(task.Priority),
-		Status:      string(task.Status),
-		CreatedAt:   task.CreatedAt,
-		UpdatedAt:   task.UpdatedAt,
-	})
+	setUndoHeaders(c, task.Undo)
+	c.JSON(http.StatusOK, newTaskResponse(&task.Task))
 }
 
 //
@@ -188,17 +224,33 @@ func (h *TaskHandler) UpdateStatus(c *gin.Context) {
 //
 
 // @Summary List tasks by user
-// @Description List all tasks for a given user ID
+// @Description List all tasks of the calling user in the list's manual order: tasks never reordered first, then by position. The user ID in the path must be the caller's.
 // @Tags tasks
 // @Accept json
 // @Produce json
+// @Param X-User-ID header string true "User ID"
+// @Param user_id path string true "User ID; must match X-User-ID"
 // @Success 200 {array} TaskResponse
+// @Failure 401 {object} TaskErrorResponse
+// @Failure 403 {object} TaskErrorResponse
+// @Failure 404 {object} TaskErrorResponse "Workspace not found"
 // @Router /api/v1/tasks/{user_id} [get]
 func (h *TaskHandler) List(c *gin.Context) {
-	userID := c.Param("user_id")
-	tasks, err := h.ListUC.Execute(userID)
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, TaskErrorResponse{Error: "missing or invalid " + middleware.UserIDHeader + " header"})
+		return
+	}
+	// A lista é sempre a de quem chama; o ID do caminho só é aceito se for
+	// o mesmo.
+	if c.Param("id") != userID {
+		c.JSON(http.StatusForbidden, TaskErrorResponse{Error: policy.ErrDenied.Error()})
+		return
+	}
+
+	tasks, err := h.ListUC.Execute(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		taskError(c, err)
 		return
 	}
 
@@ -210,6 +262,7 @@ func (h *TaskHandler) List(c *gin.Context) {
 			Description: t.Task.Description,
 			Status:      string(t.Task.Status),
 			Priority:    int(t.Task.Priority),
+			Position:    t.Task.Position,
 			CreatedAt:   t.Task.CreatedAt,
 			UpdatedAt:   t.Task.UpdatedAt,
 		})
@@ -223,25 +276,488 @@ func (h *TaskHandler) List(c *gin.Context) {
 //
 
 // @Summary Delete a task
-// @Description Soft delete a task by ID
+// @Description Soft delete a task by ID. Only the owner can delete.
 // @Tags tasks
 // @Accept json
 // @Produce json
+// @Param X-User-ID header string true "User ID"
 // @Param id path string true "Task ID"
 // @Success 204 "No Content"
+// @Header 204 {string} X-Undo-Token "Token for POST /api/v1/undo/{token}"
+// @Header 204 {string} X-Undo-Expires-At "When the undo token expires (RFC 3339)"
+// @Failure 401 {object} TaskErrorResponse
+// @Failure 403 {object} TaskErrorResponse
+// @Failure 404 {object} TaskErrorResponse "Task not found"
 // @Router /api/v1/tasks/{id} [delete]
 func (h *TaskHandler) Delete(c *gin.Context) {
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, TaskErrorResponse{Error: "missing or invalid " + middleware.UserIDHeader + " header"})
+		return
+	}
 	id := c.Param("id")
 
-	_, err := h.DeleteUC.Execute(usecasetask.DeleteTaskInput{TaskID: id})
+	out, err := h.DeleteUC.Execute(c.Request.Context(), usecasetask.DeleteTaskInput{TaskID: id, UserID: userID})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		taskError(c, err)
 		return
 	}
 
+	setUndoHeaders(c, out.Undo)
 	c.Status(http.StatusNoContent)
 }
 
+//
+// ------------------- BATCH -------------------
+//
+
+// @Summary Apply task operations in bulk
+// @Description Apply a list of create, update, status and delete operations in a single transaction.
+// @Description In all_or_nothing mode any failure rolls back every operation; in best_effort mode
+// @Description valid operations are committed and failures are reported per operation.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param X-User-ID header string true "User ID"
+// @Param body body BatchTaskRequest true "Batch operations"
+// @Success 200 {object} BatchTaskResponse "Batch committed"
+// @Header 200 {string} X-Undo-Token "Token for POST /api/v1/undo/{token}"
+// @Header 200 {string} X-Undo-Expires-At "When the undo token expires (RFC 3339)"
+// @Failure 400 {object} TaskErrorResponse
+// @Failure 401 {object} TaskErrorResponse
+// @Failure 403 {object} TaskErrorResponse
+// @Failure 404 {object} TaskErrorResponse "User not found"
+// @Failure 422 {object} BatchTaskResponse "Batch rolled back"
+// @Failure 500 {object} TaskErrorResponse
+// @Router /api/v1/tasks/batch [post]
+func (h *TaskHandler) Batch(c *gin.Context) {
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, TaskErrorResponse{Error: "missing or invalid " + middleware.UserIDHeader + " header"})
+		return
+	}
+
+	var req BatchTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.Validate.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	input := usecasetask.BatchTaskInput{
+		UserID:     userID,
+		Mode:       usecasetask.BatchMode(req.Mode),
+		Operations: make([]usecasetask.BatchOperation, 0, len(req.Operations)),
+	}
+	for _, op := range req.Operations {
+		input.Operations = append(input.Operations, usecasetask.BatchOperation{
+			Type:        usecasetask.BatchOperationType(op.Type),
+			TaskID:      op.TaskID,
+			Title:       op.Title,
+			Description: op.Description,
+			Priority:    op.Priority,
+			Status:      op.Status,
+		})
+	}
+
+	out, err := h.BatchUC.Execute(c.Request.Context(), input)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrUserNotFoundOrDeleted):
+			c.JSON(http.StatusNotFound, TaskErrorResponse{
+				Error: usecase.ErrUserNotFoundOrDeleted.Error(),
+			})
+		case errors.Is(err, policy.ErrDenied):
+			c.JSON(http.StatusForbidden, TaskErrorResponse{Error: err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, TaskErrorResponse{
+				Error: usecase.ErrUnknown.Error(),
+			})
+		}
+		return
+	}
+
+	resp := BatchTaskResponse{
+		Mode:      req.Mode,
+		Committed: out.Committed,
+		Results:   make([]BatchOperationResponse, 0, len(out.Results)),
+	}
+	for i, result := range out.Results {
+		opResp := BatchOperationResponse{
+			Index:  i,
+			Type:   string(result.Type),
+			TaskID: result.TaskID,
+			Status: "ok",
+		}
+		if result.Err != nil {
+			opResp.Status = "error"
+			opResp.Error = &BatchOperationError{
+				Code:    usecase.ErrorKind(result.Err),
+				Message: result.Err.Error(),
+			}
+			if opResp.Error.Code == usecase.ErrorKindInternal {
+				opResp.Error.Message = usecase.ErrUnknown.Error()
+			}
+		} else if result.Type != usecasetask.BatchOperationDelete {
+			task := newTaskResponse(result.Task)
+			opResp.Task = &task
+		}
+		resp.Results = append(resp.Results, opResp)
+	}
+
+	status := http.StatusOK
+	if !out.Committed {
+		status = http.StatusUnprocessableEntity
+	}
+	setUndoHeaders(c, out.Undo)
+	c.JSON(status, resp)
+}
+
+//
+// ------------------- PATCH -------------------
+//
+
+// @Summary Partially update a task
+// @Description Apply a JSON Merge Patch (application/merge-patch+json) or a JSON Patch
+// @Description (application/json-patch+json) to a task owned by the user in X-User-ID.
+// @Description A null description, due_at, tags or recurrence clears it; title, priority and status
+// @Description cannot be null. Unknown fields and paths are rejected with 400.
+// @Tags tasks
+// @Accept application/merge-patch+json,application/json-patch+json
+// @Produce json
+// @Param X-User-ID header string true "User ID"
+// @Param id path string true "Task ID"
+// @Param body body TaskPatchDocument true "Patch document"
+// @Success 200 {object} TaskResponse
+// @Header 200 {string} X-Undo-Token "Token for POST /api/v1/undo/{token}"
+// @Header 200 {string} X-Undo-Expires-At "When the undo token expires (RFC 3339)"
+// @Failure 400 {object} TaskErrorResponse "Malformed patch or unknown field"
+// @Failure 401 {object} TaskErrorResponse
+// @Failure 403 {object} TaskErrorResponse
+// @Failure 404 {object} TaskErrorResponse
+// @Failure 409 {object} TaskErrorResponse "JSON Patch test operation failed"
+// @Failure 415 {object} TaskErrorResponse
+// @Failure 422 {object} TaskErrorResponse "Patched task is invalid"
+// @Failure 500 {object} TaskErrorResponse
+// @Router /api/v1/tasks/{id} [patch]
+func (h *TaskHandler) Patch(c *gin.Context) {
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, TaskErrorResponse{Error: "missing or invalid " + middleware.UserIDHeader + " header"})
+		return
+	}
+
+	patcher, err := newJSONPatcher(c)
+	if err != nil {
+		patchRequestError(c, err)
+		return
+	}
+
+	out, err := h.PatchUC.Execute(c.Request.Context(), usecasetask.PatchTaskInput{
+		TaskID: c.Param("id"),
+		UserID: userID,
+		Patch: func(current usecasetask.TaskFields) (usecasetask.TaskFields, error) {
+			var desired TaskPatchDocument
+			if err := patcher.Apply(TaskPatchDocument(current), &desired); err != nil {
+				return usecasetask.TaskFields{}, err
+			}
+			return usecasetask.TaskFields(desired), nil
+		},
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrTaskNotFound):
+			c.JSON(http.StatusNotFound, TaskErrorResponse{Error: err.Error()})
+		case errors.Is(err, errMalformedPatch):
+			c.JSON(http.StatusBadRequest, TaskErrorResponse{Error: err.Error()})
+		case patchConflict(err):
+			c.JSON(http.StatusConflict, TaskErrorResponse{Error: err.Error()})
+		case usecase.ErrorKind(err) == usecase.ErrorKindValidation:
+			c.JSON(http.StatusUnprocessableEntity, TaskErrorResponse{Error: err.Error()})
+		case usecase.ErrorKind(err) == usecase.ErrorKindForbidden:
+			c.JSON(http.StatusForbidden, TaskErrorResponse{Error: err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, TaskErrorResponse{Error: usecase.ErrUnknown.Error()})
+		}
+		return
+	}
+
+	setUndoHeaders(c, out.Undo)
+	c.JSON(http.StatusOK, newTaskResponse(&out.Task))
+}
+
+//
+// ------------------- EXPORT -------------------
+//
+
+// @Summary Export tasks
+// @Description Streams the tasks of the user in X-User-ID as CSV, JSON or iCalendar (one VTODO per
+// @Description task). Use columns to choose and order the CSV columns. CSV cells starting with =, +, -,
+// @Description @, tab or carriage return are prefixed with an apostrophe so spreadsheets read them as text.
+// @Tags tasks
+// @Produce text/csv,application/json,text/calendar
+// @Param X-User-ID header string true "User ID"
+// @Param format query string false "csv, json or ics" default(json)
+// @Param include_deleted query bool false "Include deleted tasks"
+// @Param columns query string false "Comma-separated CSV columns: id,title,description,priority,status,created_at,updated_at,deleted_at"
+// @Success 200 {array} export.Task
+// @Failure 400 {object} TaskErrorResponse
+// @Failure 401 {object} TaskErrorResponse
+// @Failure 500 {object} TaskErrorResponse
+// @Router /api/v1/tasks/export [get]
+func (h *TaskHandler) Export(c *gin.Context) {
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, TaskErrorResponse{Error: "missing or invalid " + middleware.UserIDHeader + " header"})
+		return
+	}
+
+	var req ExportTasksRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, TaskErrorResponse{Error: err.Error()})
+		return
+	}
+	format, err := export.ParseFormat(cmp.Or(req.Format, string(export.FormatJSON)))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, TaskErrorResponse{Error: err.Error()})
+		return
+	}
+	var columns []string
+	if req.Columns != "" {
+		columns = strings.Split(req.Columns, ",")
+	}
+
+	encoder, err := export.NewEncoder(format, c.Writer, columns)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, TaskErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.Header("Content-Type", format.ContentType())
+	c.Header("Content-Disposition", `attachment; filename="tasks.`+string(format)+`"`)
+	c.Status(http.StatusOK)
+
+	_, err = h.ExportUC.Execute(c.Request.Context(), usecasetask.ExportTasksInput{
+		UserID:         userID,
+		IncludeDeleted: req.IncludeDeleted,
+		Encoder:        encoder,
+	})
+	if err != nil {
+		// Com a resposta já iniciada, só resta interromper o corpo.
+		if c.Writer.Written() {
+			logging.FromContext(c.Request.Context()).Error("export interrupted", "error", err)
+			c.Abort()
+			return
+		}
+		c.Header("Content-Type", "")
+		c.Header("Content-Disposition", "")
+		taskError(c, err)
+	}
+}
+
+//
+// ------------------- IMPORT -------------------
+//
+
+// maxImportSize limita o tamanho do arquivo importado.
+const maxImportSize = 2 << 20
+
+// @Summary Import tasks
+// @Description Imports tasks for the user in X-User-ID from a CSV (with a header row), todo.txt or
+// @Description JSON export file sent as the request body. Every row is validated; invalid rows are
+// @Description reported with their line and skipped, and the valid ones are saved in a single
+// @Description transaction. With dry_run nothing is saved. CSV columns default to the field names
+// @Description (title, description, priority, status) and can be mapped with mapping[field]=Column.
+// @Tags tasks
+// @Accept text/csv,text/plain,application/json
+// @Produce json
+// @Param X-User-ID header string true "User ID"
+// @Param X-Workspace-ID header string false "Workspace ID; omitted for the personal space"
+// @Param format query string true "csv, todotxt or json"
+// @Param dry_run query bool false "Validate without saving"
+// @Param mapping[title] query string false "CSV column holding the title"
+// @Param body body string true "File contents"
+// @Success 200 {object} ImportTasksResponse
+// @Header 200 {string} X-Undo-Token "Token for POST /api/v1/undo/{token}"
+// @Header 200 {string} X-Undo-Expires-At "When the undo token expires (RFC 3339)"
+// @Failure 400 {object} TaskErrorResponse "Unreadable file or invalid parameters"
+// @Failure 401 {object} TaskErrorResponse
+// @Failure 403 {object} TaskErrorResponse "Not allowed to create tasks here, or email not verified"
+// @Failure 404 {object} TaskErrorResponse "User not found"
+// @Failure 413 {object} TaskErrorResponse
+// @Failure 500 {object} TaskErrorResponse
+// @Router /api/v1/tasks/import [post]
+func (h *TaskHandler) Import(c *gin.Context) {
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, TaskErrorResponse{Error: "missing or invalid " + middleware.UserIDHeader + " header"})
+		return
+	}
+
+	var req ImportTasksRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, TaskErrorResponse{Error: err.Error()})
+		return
+	}
+	format, err := importer.ParseFormat(req.Format)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, TaskErrorResponse{Error: err.Error()})
+		return
+	}
+
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)
+	rows, err := importer.Parse(format, body, c.QueryMap("mapping"))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, TaskErrorResponse{Error: "file is larger than 2 MiB"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, TaskErrorResponse{Error: err.Error()})
+		return
+	}
+
+	out, err := h.ImportUC.Execute(c.Request.Context(), usecasetask.ImportTasksInput{
+		UserID: userID,
+		Rows:   rows,
+		DryRun: req.DryRun,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrUserNotFoundOrDeleted),
+			errors.Is(err, usecase.ErrWorkspaceNotFound):
+			c.JSON(http.StatusNotFound, TaskErrorResponse{Error: err.Error()})
+		case errors.Is(err, usecase.ErrWorkspaceForbidden),
+			errors.Is(err, usecase.ErrEmailNotVerified),
+			errors.Is(err, policy.ErrDenied):
+			c.JSON(http.StatusForbidden, TaskErrorResponse{Error: err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, TaskErrorResponse{Error: usecase.ErrUnknown.Error()})
+		}
+		return
+	}
+
+	resp := ImportTasksResponse{
+		DryRun:    req.DryRun,
+		Committed: out.Committed,
+		Valid:     len(out.Tasks),
+		Invalid:   len(out.Errors),
+		Errors:    make([]ImportRowErrorResponse, 0, len(out.Errors)),
+		Tasks:     make([]TaskResponse, 0, len(out.Tasks)),
+	}
+	for _, rowErr := range out.Errors {
+		resp.Errors = append(resp.Errors, ImportRowErrorResponse{
+			Line:    rowErr.Line,
+			Code:    usecase.ErrorKind(rowErr.Err),
+			Message: rowErr.Err.Error(),
+		})
+	}
+	for _, task := range out.Tasks {
+		resp.Tasks = append(resp.Tasks, newTaskResponse(task))
+	}
+	setUndoHeaders(c, out.Undo)
+	c.JSON(http.StatusOK, resp)
+}
+
+//
+// ------------------- QUICK ADD -------------------
+//
+
+// @Summary Quick-add a task from a sentence
+// @Description Parses a sentence like "Pay rent tomorrow 9am !high #finance every month" (English or Portuguese) into title, due date, priority, tags and recurrence.
+// @Description With dry_run=true nothing is saved and the interpretation is returned for confirmation.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param X-User-ID header string true "User ID"
+// @Param X-Workspace-ID header string false "Workspace ID; omitted for the personal space"
+// @Param dry_run query bool false "Only parse and validate"
+// @Param body body QuickAddTaskRequest true "Sentence"
+// @Success 200 {object} QuickAddTaskResponse "Dry run"
+// @Success 201 {object} QuickAddTaskResponse
+// @Header 201 {string} X-Undo-Token "Token for POST /api/v1/undo/{token}"
+// @Header 201 {string} X-Undo-Expires-At "When the undo token expires (RFC 3339)"
+// @Failure 400 {object} TaskErrorResponse
+// @Failure 401 {object} TaskErrorResponse
+// @Failure 403 {object} TaskErrorResponse "Not allowed to create tasks here, or email not verified"
+// @Failure 404 {object} TaskErrorResponse "User not found"
+// @Failure 422 {object} TaskErrorResponse "Nothing left for the title"
+// @Failure 500 {object} TaskErrorResponse
+// @Router /api/v1/tasks/quick-add [post]
+func (h *TaskHandler) QuickAdd(c *gin.Context) {
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, TaskErrorResponse{Error: "missing or invalid " + middleware.UserIDHeader + " header"})
+		return
+	}
+
+	var req QuickAddTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, TaskErrorResponse{Error: err.Error()})
+		return
+	}
+	if err := h.Validate.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, TaskErrorResponse{Error: err.Error()})
+		return
+	}
+	location, err := time.LoadLocation(cmp.Or(req.Timezone, "UTC"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, TaskErrorResponse{Error: "unknown timezone " + req.Timezone})
+		return
+	}
+	dryRun := c.Query("dry_run") == "true"
+
+	out, err := h.QuickAddUC.Execute(c.Request.Context(), usecasetask.QuickAddTaskInput{
+		UserID: userID,
+		Text:   req.Text,
+		Now:    time.Now().In(location),
+		DryRun: dryRun,
+	})
+	if err != nil {
+		switch usecase.ErrorKind(err) {
+		case usecase.ErrorKindValidation:
+			c.JSON(http.StatusUnprocessableEntity, TaskErrorResponse{Error: err.Error()})
+		case usecase.ErrorKindNotFound:
+			c.JSON(http.StatusNotFound, TaskErrorResponse{Error: err.Error()})
+		case usecase.ErrorKindForbidden:
+			c.JSON(http.StatusForbidden, TaskErrorResponse{Error: err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, TaskErrorResponse{Error: usecase.ErrUnknown.Error()})
+		}
+		return
+	}
+
+	resp := QuickAddTaskResponse{
+		DryRun:     dryRun,
+		Title:      out.Input.Title,
+		DueAt:      out.Input.DueAt,
+		Priority:   int(out.Input.Priority),
+		Tags:       tagStrings(out.Input.Tags),
+		Recurrence: out.Input.Recurrence.String(),
+		Spans:      make([]QuickAddSpan, 0, len(out.Parsed.Spans)),
+	}
+	for _, span := range out.Parsed.Spans {
+		resp.Spans = append(resp.Spans, QuickAddSpan{
+			Kind:  string(span.Kind),
+			Start: span.Start,
+			End:   span.End,
+			Text:  span.Text,
+		})
+	}
+	if out.Task == nil {
+		c.JSON(http.StatusOK, resp)
+		return
+	}
+	task := newTaskResponse(out.Task)
+	resp.Task = &task
+	setUndoHeaders(c, out.Undo)
+	c.JSON(http.StatusCreated, resp)
+}
+
 //
 // ------------------- REQUESTS / RESPONSES -------------------
 //
@@ -250,7 +766,6 @@ type CreateTaskRequest struct {
 	Title       string `json:"title" validate:"required,min=3"`
 	Description string `json:"description"`
 	Priority    int    `json:"priority" validate:"required,min=1,max=3"`
-	UserID      string `json:"user_id" validate:"required,uuid"`
 }
 
 type TaskResponse struct {
@@ -259,6 +774,11 @@ type TaskResponse struct {
 	Description string     `json:"description"`
 	Priority    int        `json:"priority"`
 	Status      string     `json:"status"`
+	AssigneeID  string     `json:"assignee_id,omitempty"`
+	DueAt       *time.Time `json:"due_at,omitempty"`
+	Tags        []string   `json:"tags,omitempty"`
+	Recurrence  string     `json:"recurrence,omitempty" example:"FREQ=WEEKLY;INTERVAL=2"`
+	Position    int        `json:"position,omitempty"`
 	CreatedAt   time.Time  `json:"created_at"`
 	UpdatedAt   *time.Time `json:"updated_at"`
 }
@@ -269,10 +789,146 @@ type UpdateTaskRequest struct {
 	Priority    int    `json:"priority" validate:"min=1,max=3"`
 }
 
+// TaskPatchDocument é a representação da tarefa sobre a qual o patch é aplicado.
+type TaskPatchDocument struct {
+	Title       *string    `json:"title"`
+	Description *string    `json:"description"`
+	Priority    *int       `json:"priority"`
+	Status      *string    `json:"status"`
+	DueAt       *time.Time `json:"due_at"`
+	Tags        []string   `json:"tags"`
+	Recurrence  *string    `json:"recurrence" example:"FREQ=WEEKLY;INTERVAL=2"`
+}
+
 type UpdateTaskStatusRequest struct {
 	Status string `json:"status" validate:"required,oneof=new in_progress completed"`
 }
 
+type ExportTasksRequest struct {
+	Format         string `form:"format"`
+	IncludeDeleted bool   `form:"include_deleted"`
+	Columns        string `form:"columns"`
+}
+
+type ImportTasksRequest struct {
+	Format string `form:"format"`
+	DryRun bool   `form:"dry_run"`
+}
+
+type ImportTasksResponse struct {
+	DryRun    bool                     `json:"dry_run"`
+	Committed bool                     `json:"committed"`
+	Valid     int                      `json:"valid"`
+	Invalid   int                      `json:"invalid"`
+	Errors    []ImportRowErrorResponse `json:"errors"`
+	Tasks     []TaskResponse           `json:"tasks"`
+}
+
+type QuickAddTaskRequest struct {
+	Text string `json:"text" validate:"required,max=500" example:"Pagar aluguel amanhã 9h !alta #financas todo mês"`
+	// Timezone é um nome IANA; as datas relativas são calculadas nele.
+	Timezone string `json:"timezone" example:"America/Sao_Paulo"`
+}
+
+type QuickAddTaskResponse struct {
+	DryRun     bool           `json:"dry_run"`
+	Title      string         `json:"title"`
+	DueAt      *time.Time     `json:"due_at"`
+	Priority   int            `json:"priority"`
+	Tags       []string       `json:"tags"`
+	Recurrence string         `json:"recurrence,omitempty"`
+	Spans      []QuickAddSpan `json:"spans"`
+	Task       *TaskResponse  `json:"task,omitempty"`
+}
+
+// QuickAddSpan marca um trecho interpretado do texto. Start e End são
+// posições em caracteres (runas), com End exclusivo.
+type QuickAddSpan struct {
+	Kind  string `json:"kind" enums:"date,time,priority,tag,recurrence"`
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+	Text  string `json:"text"`
+}
+
+type ImportRowErrorResponse struct {
+	Line    int    `json:"line"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
 type TaskErrorResponse struct {
 	Error string `json:"error"`
 }
+
+type BatchTaskRequest struct {
+	Mode       string                  `json:"mode" validate:"required,oneof=all_or_nothing best_effort"`
+	Operations []BatchOperationRequest `json:"operations" validate:"required,min=1,max=100,dive"`
+}
+
+type BatchOperationRequest struct {
+	Type        string `json:"type" validate:"required,oneof=create update status delete"`
+	TaskID      string `json:"task_id" validate:"required_unless=Type create"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Priority    int    `json:"priority"`
+	Status      string `json:"status"`
+}
+
+type BatchTaskResponse struct {
+	Mode      string                   `json:"mode"`
+	Committed bool                     `json:"committed"`
+	Results   []BatchOperationResponse `json:"results"`
+}
+
+type BatchOperationResponse struct {
+	Index  int                  `json:"index"`
+	Type   string               `json:"type"`
+	TaskID string               `json:"task_id,omitempty"`
+	Status string               `json:"status"`
+	Task   *TaskResponse        `json:"task,omitempty"`
+	Error  *BatchOperationError `json:"error,omitempty"`
+}
+
+type BatchOperationError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func newTaskResponse(task *domainTask.Task) TaskResponse {
+	return TaskResponse{
+		ID:          task.ID,
+		Title:       task.Title,
+		Description: task.Description,
+		Priority:    int(task.Priority),
+		Status:      string(task.Status),
+		AssigneeID:  task.AssigneeID,
+		DueAt:       task.DueAt,
+		Tags:        tagStrings(task.Tags),
+		Recurrence:  task.Recurrence.String(),
+		Position:    task.Position,
+		CreatedAt:   task.CreatedAt,
+		UpdatedAt:   task.UpdatedAt,
+	}
+}
+
+// taskError responde com o status correspondente ao tipo do erro.
+func taskError(c *gin.Context, err error) {
+	switch usecase.ErrorKind(err) {
+	case usecase.ErrorKindValidation:
+		c.JSON(http.StatusUnprocessableEntity, TaskErrorResponse{Error: err.Error()})
+	case usecase.ErrorKindNotFound:
+		c.JSON(http.StatusNotFound, TaskErrorResponse{Error: err.Error()})
+	case usecase.ErrorKindForbidden:
+		c.JSON(http.StatusForbidden, TaskErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, TaskErrorResponse{Error: usecase.ErrUnknown.Error()})
+	}
+}
+
+func tagStrings(tags []valueobject.Tag) []string {
+	out := make([]string, len(tags))
+	for i, tag := range tags {
+		out[i] = string(tag)
+	}
+	return out
+}
@@ -0,0 +1,284 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hoyci/todo-ddd/internal/adapters/api/middleware"
+	"github.com/hoyci/todo-ddd/internal/adapters/db/sqlite"
+	"github.com/hoyci/todo-ddd/internal/adapters/events"
+	"github.com/hoyci/todo-ddd/pkg/domain"
+	domainUser "github.com/hoyci/todo-ddd/pkg/domain/user"
+	domainWorkspace "github.com/hoyci/todo-ddd/pkg/domain/workspace"
+	"github.com/hoyci/todo-ddd/pkg/policy"
+	usecasetask "github.com/hoyci/todo-ddd/pkg/usecase/task"
+)
+
+// newTestDB abre um banco SQLite temporário com o esquema completo.
+func newTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	t.Chdir(t.TempDir())
+	if err := os.Mkdir("data", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	db, err := sqlite.InitDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func saveTestUser(t *testing.T, uow domain.UnitOfWork, email string) string {
+	t.Helper()
+	user, err := domainUser.NewUser("Test User", email)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = uow.Execute(context.Background(), func(ctx context.Context, work domain.Work) error {
+		return work.UserRepo().Save(ctx, *user)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return user.ID
+}
+
+// taskListRouter expõe GET /tasks/:id com o caso de uso protegido como em
+// main.
+func taskListRouter(db *sql.DB, uow domain.UnitOfWork) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	enforcer := &policy.Enforcer{Definition: policy.Default, Roles: sqlite.NewSQLiteRoleStore(db)}
+	h := &TaskHandler{
+		ListUC: policy.Guard[string, []usecasetask.ListTaskOutput](
+			&usecasetask.ListTaskUseCase{TaskRepo: sqlite.NewSQLiteTaskRepository(db), WorkspaceRepo: sqlite.NewSQLiteWorkspaceRepository(db)}, enforcer, policy.TaskView,
+			usecasetask.TaskListResource(func(userID string) string { return userID })),
+	}
+	r := gin.New()
+	r.Use(middleware.Identity(), middleware.Workspace())
+	r.GET("/tasks/:id", h.List)
+	return r
+}
+
+func TestListTasksAuthorization(t *testing.T) {
+	db := newTestDB(t)
+	uow := sqlite.NewSQLiteUnitOfWork(db, events.NewBroker(0))
+	ctx := context.Background()
+	owner := saveTestUser(t, uow, "owner@example.com")
+	recipient := saveTestUser(t, uow, "recipient@example.com")
+	assignee := saveTestUser(t, uow, "assignee@example.com")
+
+	task, err := (&usecasetask.CreateTaskUseCase{UoW: uow}).Execute(ctx, usecasetask.CreateTaskInput{Title: "Private", Priority: 1, UserID: owner})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := (&usecasetask.ShareTaskUseCase{UoW: uow}).Execute(ctx, usecasetask.ShareTaskInput{TaskID: task.ID, UserID: owner, TargetUserID: recipient, Permission: "edit"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := (&usecasetask.AssignTaskUseCase{UoW: uow}).Execute(ctx, usecasetask.AssignTaskInput{TaskID: task.ID, UserID: owner, AssigneeID: assignee}); err != nil {
+		t.Fatal(err)
+	}
+
+	router := taskListRouter(db, uow)
+	tests := []struct {
+		name     string
+		callerID string
+		pathID   string
+		want     int
+		tasks    int
+	}{
+		{"anonymous", "", owner, http.StatusUnauthorized, 0},
+		{"share recipient reads the owner's list", recipient, owner, http.StatusForbidden, 0},
+		{"assignee reads the owner's list", assignee, owner, http.StatusForbidden, 0},
+		{"owner", owner, owner, http.StatusOK, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/tasks/"+tt.pathID, nil)
+			if tt.callerID != "" {
+				req.Header.Set(middleware.UserIDHeader, tt.callerID)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			if tt.want != http.StatusOK {
+				return
+			}
+			var tasks []TaskResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &tasks); err != nil {
+				t.Fatal(err)
+			}
+			if len(tasks) != tt.tasks {
+				t.Errorf("got %d tasks, want %d", len(tasks), tt.tasks)
+			}
+		})
+	}
+
+	// A política também barra quem chama o caso de uso por outro caminho
+	// com o ID de outro usuário.
+	enforcer := &policy.Enforcer{Definition: policy.Default, Roles: sqlite.NewSQLiteRoleStore(db)}
+	guarded := policy.Guard[string, []usecasetask.ListTaskOutput](
+		&usecasetask.ListTaskUseCase{TaskRepo: sqlite.NewSQLiteTaskRepository(db), WorkspaceRepo: sqlite.NewSQLiteWorkspaceRepository(db)}, enforcer, policy.TaskView,
+		usecasetask.TaskListResource(func(userID string) string { return userID }))
+	if _, err := guarded.Execute(policy.WithSubject(ctx, recipient), owner); !errors.Is(err, policy.ErrDenied) {
+		t.Errorf("guarded list of another user: error = %v, want %v", err, policy.ErrDenied)
+	}
+}
+
+func TestListTasksWorkspaceScope(t *testing.T) {
+	db := newTestDB(t)
+	uow := sqlite.NewSQLiteUnitOfWork(db, events.NewBroker(0))
+	ctx := context.Background()
+	member := saveTestUser(t, uow, "member@example.com")
+	outsider := saveTestUser(t, uow, "outsider@example.com")
+
+	workspace, owner, err := domainWorkspace.NewWorkspace("Team", member)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = uow.Execute(ctx, func(ctx context.Context, work domain.Work) error {
+		if err := work.WorkspaceRepo().Save(ctx, workspace); err != nil {
+			return err
+		}
+		return work.WorkspaceRepo().SaveMember(ctx, owner)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	scoped := domainWorkspace.WithScope(ctx, workspace.ID)
+	if _, err := (&usecasetask.CreateTaskUseCase{UoW: uow}).Execute(scoped, usecasetask.CreateTaskInput{Title: "Shared", Priority: 1, UserID: member}); err != nil {
+		t.Fatal(err)
+	}
+
+	router := taskListRouter(db, uow)
+	tests := []struct {
+		name     string
+		callerID string
+		pathID   string
+		want     int
+	}{
+		{"anonymous with a member in the path", "", member, http.StatusUnauthorized},
+		{"outsider with a member in the path", outsider, member, http.StatusForbidden},
+		{"outsider listing own tasks", outsider, outsider, http.StatusNotFound},
+		{"member", member, member, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/tasks/"+tt.pathID, nil)
+			req.Header.Set(middleware.WorkspaceIDHeader, workspace.ID)
+			if tt.callerID != "" {
+				req.Header.Set(middleware.UserIDHeader, tt.callerID)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			if tt.want != http.StatusOK {
+				return
+			}
+			var tasks []TaskResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &tasks); err != nil {
+				t.Fatal(err)
+			}
+			if len(tasks) != 1 || tasks[0].Title != "Shared" {
+				t.Errorf("tasks = %+v, want the workspace task", tasks)
+			}
+		})
+	}
+}
+
+func TestExportTasksScope(t *testing.T) {
+	db := newTestDB(t)
+	uow := sqlite.NewSQLiteUnitOfWork(db, events.NewBroker(0))
+	ctx := context.Background()
+	owner := saveTestUser(t, uow, "owner@example.com")
+	other := saveTestUser(t, uow, "other@example.com")
+
+	workspace, member, err := domainWorkspace.NewWorkspace("Team", owner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = uow.Execute(ctx, func(ctx context.Context, work domain.Work) error {
+		if err := work.WorkspaceRepo().Save(ctx, workspace); err != nil {
+			return err
+		}
+		return work.WorkspaceRepo().SaveMember(ctx, member)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	create := func(ctx context.Context, title, userID string) string {
+		t.Helper()
+		out, err := (&usecasetask.CreateTaskUseCase{UoW: uow}).Execute(ctx, usecasetask.CreateTaskInput{Title: title, Priority: 1, UserID: userID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return out.ID
+	}
+	create(ctx, "Owner's", owner)
+	// Uma tarefa compartilhada com o dono da exportação continua sendo de
+	// quem a criou.
+	shared := create(ctx, "Other's", other)
+	if _, err := (&usecasetask.ShareTaskUseCase{UoW: uow}).Execute(ctx, usecasetask.ShareTaskInput{TaskID: shared, UserID: other, TargetUserID: owner, Permission: "edit"}); err != nil {
+		t.Fatal(err)
+	}
+	create(domainWorkspace.WithScope(ctx, workspace.ID), "Team's", owner)
+
+	gin.SetMode(gin.TestMode)
+	enforcer := &policy.Enforcer{Definition: policy.Default, Roles: sqlite.NewSQLiteRoleStore(db)}
+	h := &TaskHandler{
+		ExportUC: policy.Guard[usecasetask.ExportTasksInput, *usecasetask.ExportTasksOutput](
+			&usecasetask.ExportTasksUseCase{TaskRepo: sqlite.NewSQLiteTaskRepository(db)}, enforcer, policy.TaskView,
+			usecasetask.TaskListResource(func(in usecasetask.ExportTasksInput) string { return in.UserID })),
+	}
+	router := gin.New()
+	router.Use(middleware.Identity(), middleware.Workspace())
+	router.GET("/tasks/export", h.Export)
+
+	tests := []struct {
+		name        string
+		callerID    string
+		workspaceID string
+		want        int
+		titles      string
+	}{
+		{"anonymous", "", "", http.StatusUnauthorized, ""},
+		{"owner", owner, "", http.StatusOK, "title\nOwner's\n"},
+		{"other", other, "", http.StatusOK, "title\nOther's\n"},
+		{"owner in the workspace", owner, workspace.ID, http.StatusOK, "title\nTeam's\n"},
+		{"outsider in the workspace", other, workspace.ID, http.StatusOK, "title\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/tasks/export?format=csv&columns=title", nil)
+			if tt.callerID != "" {
+				req.Header.Set(middleware.UserIDHeader, tt.callerID)
+			}
+			if tt.workspaceID != "" {
+				req.Header.Set(middleware.WorkspaceIDHeader, tt.workspaceID)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			if tt.want == http.StatusOK && rec.Body.String() != tt.titles {
+				t.Errorf("export = %q, want %q", rec.Body.String(), tt.titles)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hoyci/todo-ddd/internal/adapters/api/middleware"
+	domainUndo "github.com/hoyci/todo-ddd/pkg/domain/undo"
+	usecase "github.com/hoyci/todo-ddd/pkg/usecase"
+	usecasetask "github.com/hoyci/todo-ddd/pkg/usecase/task"
+)
+
+const (
+	UndoTokenHeader     = "X-Undo-Token"
+	UndoExpiresAtHeader = "X-Undo-Expires-At"
+)
+
+// setUndoHeaders informa o token que desfaz a mudança, quando houver. Vai
+// em cabeçalhos para valer também nas respostas sem corpo.
+func setUndoHeaders(c *gin.Context, undo *domainUndo.Undo) {
+	if undo == nil {
+		return
+	}
+	c.Header(UndoTokenHeader, undo.Token)
+	c.Header(UndoExpiresAtHeader, undo.ExpiresAt.UTC().Format(time.RFC3339))
+}
+
+type UndoHandler struct {
+	UndoUC *usecasetask.UndoUseCase
+}
+
+// @Summary Undo a change
+// @Description Reverses the change that returned the token in the X-Undo-Token header (create,
+// @Description update, status, patch, assignment, revert, delete, batch or import) in a single
+// @Description transaction. Each token works once, only for the user who made the change and
+// @Description until X-Undo-Expires-At. It is refused if any affected task changed since.
+// @Tags tasks
+// @Produce json
+// @Param X-User-ID header string true "User ID"
+// @Param token path string true "Undo token"
+// @Success 200 {object} UndoResponse
+// @Failure 401 {object} TaskErrorResponse
+// @Failure 403 {object} TaskErrorResponse
+// @Failure 404 {object} TaskErrorResponse "Token or task not found"
+// @Failure 409 {object} TaskErrorResponse "A task changed since"
+// @Failure 422 {object} TaskErrorResponse "Token expired or already used"
+// @Failure 500 {object} TaskErrorResponse
+// @Router /api/v1/undo/{token} [post]
+func (h *UndoHandler) Undo(c *gin.Context) {
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, TaskErrorResponse{Error: "missing or invalid " + middleware.UserIDHeader + " header"})
+		return
+	}
+
+	out, err := h.UndoUC.Execute(c.Request.Context(), usecasetask.UndoInput{
+		Token:  c.Param("token"),
+		UserID: userID,
+	})
+	if err != nil {
+		if usecase.ErrorKind(err) == usecase.ErrorKindConflict {
+			c.JSON(http.StatusConflict, TaskErrorResponse{Error: err.Error()})
+			return
+		}
+		taskError(c, err)
+		return
+	}
+
+	resp := UndoResponse{
+		Action: out.Action,
+		Tasks:  make([]UndoneTaskResponse, 0, len(out.Tasks)),
+	}
+	for _, task := range out.Tasks {
+		resp.Tasks = append(resp.Tasks, UndoneTaskResponse{
+			TaskResponse: newTaskResponse(task),
+			Deleted:      task.DeletedAt != nil,
+		})
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+type UndoResponse struct {
+	// Action é a mudança desfeita.
+	Action string               `json:"action" example:"task.deleted"`
+	Tasks  []UndoneTaskResponse `json:"tasks"`
+}
+
+// UndoneTaskResponse é a tarefa no estado em que ficou; desfazer uma criação
+// a deixa excluída.
+type UndoneTaskResponse struct {
+	TaskResponse
+	Deleted bool `json:"deleted"`
+}
@@ -1,20 +1,24 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
+	"github.com/hoyci/todo-ddd/pkg/policy"
+	baseusecase "github.com/hoyci/todo-ddd/pkg/usecase"
 	usecase "github.com/hoyci/todo-ddd/pkg/usecase/user"
 )
 
 // UserHandler agrupa os casos de uso relacionados a usuários
 type UserHandler struct {
 	CreateUC *usecase.CreateUserUseCase
-	UpdateUC *usecase.UpdateUserUseCase
-	DeleteUC *usecase.DeleteUserUseCase
-	FindUC   *usecase.FindUserUseCase
+	UpdateUC policy.UseCase[usecase.UpdateUserInput, *usecase.UpdateUserOutput]
+	DeleteUC policy.Command[usecase.DeleteUserInput]
+	FindUC   policy.UseCase[usecase.FindUserInput, *usecase.FindUserOutput]
+	PatchUC  policy.UseCase[usecase.PatchUserInput, *usecase.PatchUserOutput]
 	Validate *validator.Validate
 }
 
@@ -43,7 +47,7 @@ func (h *UserHandler) Create(c *gin.Context) {
 		return
 	}
 
-	out, err := h.CreateUC.Execute(usecase.CreateUserInput{
+	out, err := h.CreateUC.Execute(c.Request.Context(), usecase.CreateUserInput{
 		Name:  req.Name,
 		Email: req.Email,
 	})
@@ -54,11 +58,12 @@ func (h *UserHandler) Create(c *gin.Context) {
 
 	u := out.User
 	c.JSON(http.StatusCreated, UserResponse{
-		ID:        u.ID,
-		Name:      u.Name,
-		Email:     u.Email,
-		CreatedAt: u.CreatedAt,
-		UpdatedAt: u.UpdatedAt,
+		ID:            u.ID,
+		Name:          u.Name,
+		Email:         u.Email,
+		EmailVerified: u.Verified(),
+		CreatedAt:     u.CreatedAt,
+		UpdatedAt:     u.UpdatedAt,
 	})
 }
 
@@ -71,24 +76,31 @@ func (h *UserHandler) Create(c *gin.Context) {
 // @Tags users
 // @Accept json
 // @Produce json
+// @Param X-User-ID header string true "User ID"
 // @Param id path string true "User ID"
 // @Success 200 {object} UserResponse
+// @Failure 403 {object} map[string]string
 // @Router /api/v1/users/{id} [get]
 func (h *UserHandler) FindByID(c *gin.Context) {
 	id := c.Param("id")
 
-	u, err := h.FindUC.Execute(usecase.FindUserInput{ID: id})
+	u, err := h.FindUC.Execute(c.Request.Context(), usecase.FindUserInput{ID: id})
 	if err != nil {
+		if errors.Is(err, policy.ErrDenied) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
 		return
 	}
 
 	c.JSON(http.StatusOK, UserResponse{
-		ID:        u.User.ID,
-		Name:      u.User.Name,
-		Email:     u.User.Email,
-		CreatedAt: u.User.CreatedAt,
-		UpdatedAt: u.User.UpdatedAt,
+		ID:            u.User.ID,
+		Name:          u.User.Name,
+		Email:         u.User.Email,
+		EmailVerified: u.User.Verified(),
+		CreatedAt:     u.User.CreatedAt,
+		UpdatedAt:     u.User.UpdatedAt,
 	})
 }
 
@@ -97,13 +109,15 @@ func (h *UserHandler) FindByID(c *gin.Context) {
 //
 
 // @Summary Update a user
-// @Description Update name or email of a user
+// @Description Update name or email of a user. Only the user themself or an admin may do it.
 // @Tags users
 // @Accept json
 // @Produce json
+// @Param X-User-ID header string true "User ID"
 // @Param id path string true "User ID"
 // @Param user body UpdateUserRequest true "Updated data"
 // @Success 200 {object} UserResponse
+// @Failure 403 {object} map[string]string
 // @Router /api/v1/users/{id} [put]
 func (h *UserHandler) Update(c *gin.Context) {
 	id := c.Param("id")
@@ -118,23 +132,95 @@ func (h *UserHandler) Update(c *gin.Context) {
 		return
 	}
 
-	out, err := h.UpdateUC.Execute(usecase.UpdateUserInput{
+	out, err := h.UpdateUC.Execute(c.Request.Context(), usecase.UpdateUserInput{
 		ID:    id,
 		Name:  req.Name,
 		Email: req.Email,
 	})
 	if err != nil {
+		if errors.Is(err, policy.ErrDenied) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
 	u := out.User
 	c.JSON(http.StatusOK, UserResponse{
-		ID:        u.ID,
-		Name:      u.Name,
-		Email:     u.Email,
-		CreatedAt: u.CreatedAt,
-		UpdatedAt: u.UpdatedAt,
+		ID:            u.ID,
+		Name:          u.Name,
+		Email:         u.Email,
+		EmailVerified: u.Verified(),
+		CreatedAt:     u.CreatedAt,
+		UpdatedAt:     u.UpdatedAt,
+	})
+}
+
+//
+// ------------------- PATCH -------------------
+//
+
+// @Summary Partially update a user
+// @Description Apply a JSON Merge Patch (application/merge-patch+json) or a JSON Patch
+// @Description (application/json-patch+json) to a user. Name and email cannot be null.
+// @Tags users
+// @Accept application/merge-patch+json,application/json-patch+json
+// @Produce json
+// @Param X-User-ID header string true "User ID"
+// @Param id path string true "User ID"
+// @Param user body UserPatchDocument true "Patch document"
+// @Success 200 {object} UserResponse
+// @Failure 400 {object} map[string]string "Malformed patch or unknown field"
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string "Email already in use or JSON Patch test operation failed"
+// @Failure 415 {object} map[string]string
+// @Failure 422 {object} map[string]string "Patched user is invalid"
+// @Router /api/v1/users/{id} [patch]
+func (h *UserHandler) Patch(c *gin.Context) {
+	patcher, err := newJSONPatcher(c)
+	if err != nil {
+		patchRequestError(c, err)
+		return
+	}
+
+	out, err := h.PatchUC.Execute(c.Request.Context(), usecase.PatchUserInput{
+		ID: c.Param("id"),
+		Patch: func(current usecase.UserFields) (usecase.UserFields, error) {
+			var desired UserPatchDocument
+			if err := patcher.Apply(UserPatchDocument(current), &desired); err != nil {
+				return usecase.UserFields{}, err
+			}
+			return usecase.UserFields(desired), nil
+		},
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, baseusecase.ErrUserNotFoundOrDeleted):
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		case errors.Is(err, errMalformedPatch):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, policy.ErrDenied):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case errors.Is(err, baseusecase.ErrUserAlreadyExists), patchConflict(err):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case baseusecase.ErrorKind(err) == baseusecase.ErrorKindValidation:
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": baseusecase.ErrUnknown.Error()})
+		}
+		return
+	}
+
+	u := out.User
+	c.JSON(http.StatusOK, UserResponse{
+		ID:            u.ID,
+		Name:          u.Name,
+		Email:         u.Email,
+		EmailVerified: u.Verified(),
+		CreatedAt:     u.CreatedAt,
+		UpdatedAt:     u.UpdatedAt,
 	})
 }
 
@@ -147,13 +233,19 @@ func (h *UserHandler) Update(c *gin.Context) {
 // @Tags users
 // @Accept json
 // @Produce json
+// @Param X-User-ID header string true "User ID"
 // @Param id path string true "User ID"
 // @Success 204 "No Content"
+// @Failure 403 {object} map[string]string
 // @Router /api/v1/users/{id} [delete]
 func (h *UserHandler) Delete(c *gin.Context) {
 	id := c.Param("id")
 
-	if err := h.DeleteUC.Execute(usecase.DeleteUserInput{ID: id}); err != nil {
+	if err := h.DeleteUC.Execute(c.Request.Context(), usecase.DeleteUserInput{ID: id}); err != nil {
+		if errors.Is(err, policy.ErrDenied) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -175,10 +267,17 @@ type UpdateUserRequest struct {
 	Email string `json:"email" validate:"required,email"`
 }
 
+// UserPatchDocument é a representação do usuário sobre a qual o patch é aplicado.
+type UserPatchDocument struct {
+	Name  *string `json:"name"`
+	Email *string `json:"email"`
+}
+
 type UserResponse struct {
-	ID        string     `json:"id"`
-	Name      string     `json:"name"`
-	Email     string     `json:"email"`
-	CreatedAt time.Time  `json:"created_at"`
-	UpdatedAt *time.Time `json:"updated_at"`
+	ID            string     `json:"id"`
+	Name          string     `json:"name"`
+	Email         string     `json:"email"`
+	EmailVerified bool       `json:"email_verified"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     *time.Time `json:"updated_at"`
 }
@@ -0,0 +1,598 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/hoyci/todo-ddd/internal/adapters/api/middleware"
+	domainWorkspace "github.com/hoyci/todo-ddd/pkg/domain/workspace"
+	"github.com/hoyci/todo-ddd/pkg/policy"
+	"github.com/hoyci/todo-ddd/pkg/usecase"
+	usecaseworkspace "github.com/hoyci/todo-ddd/pkg/usecase/workspace"
+)
+
+type WorkspaceHandler struct {
+	CreateUC           policy.UseCase[usecaseworkspace.CreateWorkspaceInput, *domainWorkspace.Workspace]
+	ListUC             policy.UseCase[string, []*domainWorkspace.Workspace]
+	GetUC              policy.UseCase[usecaseworkspace.GetWorkspaceInput, *usecaseworkspace.GetWorkspaceOutput]
+	RenameUC           policy.UseCase[usecaseworkspace.RenameWorkspaceInput, *domainWorkspace.Workspace]
+	DeleteUC           policy.Command[usecaseworkspace.DeleteWorkspaceInput]
+	ListMembersUC      policy.UseCase[usecaseworkspace.ListMembersInput, []*domainWorkspace.Member]
+	ChangeRoleUC       policy.UseCase[usecaseworkspace.ChangeMemberRoleInput, *domainWorkspace.Member]
+	RemoveMemberUC     policy.Command[usecaseworkspace.RemoveMemberInput]
+	InviteUC           policy.UseCase[usecaseworkspace.InviteMemberInput, *domainWorkspace.Invitation]
+	ListInvitationsUC  policy.UseCase[usecaseworkspace.ListInvitationsInput, []*domainWorkspace.Invitation]
+	RevokeInvitationUC policy.Command[usecaseworkspace.RevokeInvitationInput]
+	AcceptInvitationUC policy.UseCase[usecaseworkspace.AcceptInvitationInput, *domainWorkspace.Member]
+	Validate           *validator.Validate
+}
+
+//
+// ------------------- WORKSPACES -------------------
+//
+
+// @Summary Create a workspace
+// @Description Creates a workspace with the caller as its owner. Tasks created with the
+// @Description X-Workspace-ID header set to its ID belong to it and are visible to every member.
+// @Tags workspaces
+// @Accept json
+// @Produce json
+// @Param X-User-ID header string true "User ID"
+// @Param body body CreateWorkspaceRequest true "Workspace"
+// @Success 201 {object} WorkspaceResponse
+// @Failure 400 {object} TaskErrorResponse
+// @Failure 401 {object} TaskErrorResponse
+// @Failure 404 {object} TaskErrorResponse "User not found"
+// @Failure 422 {object} TaskErrorResponse "Invalid name"
+// @Failure 500 {object} TaskErrorResponse
+// @Router /api/v1/workspaces [post]
+func (h *WorkspaceHandler) Create(c *gin.Context) {
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, TaskErrorResponse{Error: "missing or invalid " + middleware.UserIDHeader + " header"})
+		return
+	}
+
+	var req CreateWorkspaceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, TaskErrorResponse{Error: err.Error()})
+		return
+	}
+	if err := h.Validate.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, TaskErrorResponse{Error: err.Error()})
+		return
+	}
+
+	workspace, err := h.CreateUC.Execute(c.Request.Context(), usecaseworkspace.CreateWorkspaceInput{
+		UserID: userID,
+		Name:   req.Name,
+	})
+	if err != nil {
+		workspaceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, newWorkspaceResponse(workspace, ""))
+}
+
+// @Summary List my workspaces
+// @Description Lists the workspaces the caller is a member of.
+// @Tags workspaces
+// @Produce json
+// @Param X-User-ID header string true "User ID"
+// @Success 200 {array} WorkspaceResponse
+// @Failure 401 {object} TaskErrorResponse
+// @Failure 500 {object} TaskErrorResponse
+// @Router /api/v1/workspaces [get]
+func (h *WorkspaceHandler) List(c *gin.Context) {
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, TaskErrorResponse{Error: "missing or invalid " + middleware.UserIDHeader + " header"})
+		return
+	}
+
+	workspaces, err := h.ListUC.Execute(c.Request.Context(), userID)
+	if err != nil {
+		workspaceError(c, err)
+		return
+	}
+
+	resp := make([]WorkspaceResponse, 0, len(workspaces))
+	for _, workspace := range workspaces {
+		resp = append(resp, newWorkspaceResponse(workspace, ""))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// @Summary Get a workspace
+// @Description Returns the workspace and the caller's role in it.
+// @Tags workspaces
+// @Produce json
+// @Param X-User-ID header string true "User ID"
+// @Param id path string true "Workspace ID"
+// @Success 200 {object} WorkspaceResponse
+// @Failure 401 {object} TaskErrorResponse
+// @Failure 404 {object} TaskErrorResponse "Workspace not found or caller is not a member"
+// @Failure 500 {object} TaskErrorResponse
+// @Router /api/v1/workspaces/{id} [get]
+func (h *WorkspaceHandler) Get(c *gin.Context) {
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, TaskErrorResponse{Error: "missing or invalid " + middleware.UserIDHeader + " header"})
+		return
+	}
+
+	out, err := h.GetUC.Execute(c.Request.Context(), usecaseworkspace.GetWorkspaceInput{
+		WorkspaceID: c.Param("id"),
+		UserID:      userID,
+	})
+	if err != nil {
+		workspaceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newWorkspaceResponse(out.Workspace, string(out.Member.Role)))
+}
+
+// @Summary Rename a workspace
+// @Description Requires the owner or admin role.
+// @Tags workspaces
+// @Accept json
+// @Produce json
+// @Param X-User-ID header string true "User ID"
+// @Param id path string true "Workspace ID"
+// @Param body body CreateWorkspaceRequest true "New name"
+// @Success 200 {object} WorkspaceResponse
+// @Failure 400 {object} TaskErrorResponse
+// @Failure 401 {object} TaskErrorResponse
+// @Failure 403 {object} TaskErrorResponse
+// @Failure 404 {object} TaskErrorResponse "Workspace not found or caller is not a member"
+// @Failure 422 {object} TaskErrorResponse "Invalid name"
+// @Failure 500 {object} TaskErrorResponse
+// @Router /api/v1/workspaces/{id} [patch]
+func (h *WorkspaceHandler) Rename(c *gin.Context) {
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, TaskErrorResponse{Error: "missing or invalid " + middleware.UserIDHeader + " header"})
+		return
+	}
+
+	var req CreateWorkspaceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, TaskErrorResponse{Error: err.Error()})
+		return
+	}
+	if err := h.Validate.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, TaskErrorResponse{Error: err.Error()})
+		return
+	}
+
+	workspace, err := h.RenameUC.Execute(c.Request.Context(), usecaseworkspace.RenameWorkspaceInput{
+		WorkspaceID: c.Param("id"),
+		UserID:      userID,
+		Name:        req.Name,
+	})
+	if err != nil {
+		workspaceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newWorkspaceResponse(workspace, ""))
+}
+
+// @Summary Delete a workspace
+// @Description Deletes the workspace; its tasks stop being visible to anyone. Owner only.
+// @Tags workspaces
+// @Param X-User-ID header string true "User ID"
+// @Param id path string true "Workspace ID"
+// @Success 204
+// @Failure 401 {object} TaskErrorResponse
+// @Failure 403 {object} TaskErrorResponse
+// @Failure 404 {object} TaskErrorResponse "Workspace not found or caller is not a member"
+// @Failure 500 {object} TaskErrorResponse
+// @Router /api/v1/workspaces/{id} [delete]
+func (h *WorkspaceHandler) Delete(c *gin.Context) {
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, TaskErrorResponse{Error: "missing or invalid " + middleware.UserIDHeader + " header"})
+		return
+	}
+
+	err := h.DeleteUC.Execute(c.Request.Context(), usecaseworkspace.DeleteWorkspaceInput{
+		WorkspaceID: c.Param("id"),
+		UserID:      userID,
+	})
+	if err != nil {
+		workspaceError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+//
+// ------------------- MEMBERS -------------------
+//
+
+// @Summary List workspace members
+// @Tags workspaces
+// @Produce json
+// @Param X-User-ID header string true "User ID"
+// @Param id path string true "Workspace ID"
+// @Success 200 {array} MemberResponse
+// @Failure 401 {object} TaskErrorResponse
+// @Failure 404 {object} TaskErrorResponse "Workspace not found or caller is not a member"
+// @Failure 500 {object} TaskErrorResponse
+// @Router /api/v1/workspaces/{id}/members [get]
+func (h *WorkspaceHandler) ListMembers(c *gin.Context) {
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, TaskErrorResponse{Error: "missing or invalid " + middleware.UserIDHeader + " header"})
+		return
+	}
+
+	members, err := h.ListMembersUC.Execute(c.Request.Context(), usecaseworkspace.ListMembersInput{
+		WorkspaceID: c.Param("id"),
+		UserID:      userID,
+	})
+	if err != nil {
+		workspaceError(c, err)
+		return
+	}
+
+	resp := make([]MemberResponse, 0, len(members))
+	for _, member := range members {
+		resp = append(resp, newMemberResponse(member))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// @Summary Change a member's role
+// @Description Requires the owner or admin role; only the owner grants or revokes admin. The owner
+// @Description role cannot be granted or changed.
+// @Tags workspaces
+// @Accept json
+// @Produce json
+// @Param X-User-ID header string true "User ID"
+// @Param id path string true "Workspace ID"
+// @Param user_id path string true "Member user ID"
+// @Param body body ChangeMemberRoleRequest true "Role"
+// @Success 200 {object} MemberResponse
+// @Failure 400 {object} TaskErrorResponse
+// @Failure 401 {object} TaskErrorResponse
+// @Failure 403 {object} TaskErrorResponse
+// @Failure 404 {object} TaskErrorResponse "Workspace or member not found"
+// @Failure 422 {object} TaskErrorResponse "Invalid role"
+// @Failure 500 {object} TaskErrorResponse
+// @Router /api/v1/workspaces/{id}/members/{user_id} [put]
+func (h *WorkspaceHandler) ChangeRole(c *gin.Context) {
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, TaskErrorResponse{Error: "missing or invalid " + middleware.UserIDHeader + " header"})
+		return
+	}
+
+	var req ChangeMemberRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, TaskErrorResponse{Error: err.Error()})
+		return
+	}
+	if err := h.Validate.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, TaskErrorResponse{Error: err.Error()})
+		return
+	}
+
+	member, err := h.ChangeRoleUC.Execute(c.Request.Context(), usecaseworkspace.ChangeMemberRoleInput{
+		WorkspaceID:  c.Param("id"),
+		UserID:       userID,
+		TargetUserID: c.Param("user_id"),
+		Role:         req.Role,
+	})
+	if err != nil {
+		workspaceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newMemberResponse(member))
+}
+
+// @Summary Remove a member
+// @Description Removes a member from the workspace. Any member can remove themselves; removing others
+// @Description requires the owner or admin role, and only the owner removes admins. The owner cannot
+// @Description be removed.
+// @Tags workspaces
+// @Param X-User-ID header string true "User ID"
+// @Param id path string true "Workspace ID"
+// @Param user_id path string true "Member user ID"
+// @Success 204
+// @Failure 401 {object} TaskErrorResponse
+// @Failure 403 {object} TaskErrorResponse
+// @Failure 404 {object} TaskErrorResponse "Workspace or member not found"
+// @Failure 422 {object} TaskErrorResponse "Owner cannot be removed"
+// @Failure 500 {object} TaskErrorResponse
+// @Router /api/v1/workspaces/{id}/members/{user_id} [delete]
+func (h *WorkspaceHandler) RemoveMember(c *gin.Context) {
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, TaskErrorResponse{Error: "missing or invalid " + middleware.UserIDHeader + " header"})
+		return
+	}
+
+	err := h.RemoveMemberUC.Execute(c.Request.Context(), usecaseworkspace.RemoveMemberInput{
+		WorkspaceID:  c.Param("id"),
+		UserID:       userID,
+		TargetUserID: c.Param("user_id"),
+	})
+	if err != nil {
+		workspaceError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+//
+// ------------------- INVITATIONS -------------------
+//
+
+// @Summary Invite someone to a workspace
+// @Description Creates an invitation for an email with a role. The response carries the token the
+// @Description invited user presents to accept it. Requires the owner or admin role; only the owner
+// @Description invites admins.
+// @Tags workspaces
+// @Accept json
+// @Produce json
+// @Param X-User-ID header string true "User ID"
+// @Param id path string true "Workspace ID"
+// @Param body body InviteMemberRequest true "Invitation"
+// @Success 201 {object} InvitationResponse
+// @Failure 400 {object} TaskErrorResponse
+// @Failure 401 {object} TaskErrorResponse
+// @Failure 403 {object} TaskErrorResponse
+// @Failure 404 {object} TaskErrorResponse "Workspace not found or caller is not a member"
+// @Failure 409 {object} TaskErrorResponse "Already a member"
+// @Failure 422 {object} TaskErrorResponse "Invalid email or role"
+// @Failure 500 {object} TaskErrorResponse
+// @Router /api/v1/workspaces/{id}/invitations [post]
+func (h *WorkspaceHandler) Invite(c *gin.Context) {
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, TaskErrorResponse{Error: "missing or invalid " + middleware.UserIDHeader + " header"})
+		return
+	}
+
+	var req InviteMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, TaskErrorResponse{Error: err.Error()})
+		return
+	}
+	if err := h.Validate.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, TaskErrorResponse{Error: err.Error()})
+		return
+	}
+
+	invitation, err := h.InviteUC.Execute(c.Request.Context(), usecaseworkspace.InviteMemberInput{
+		WorkspaceID: c.Param("id"),
+		UserID:      userID,
+		Email:       req.Email,
+		Role:        req.Role,
+	})
+	if err != nil {
+		workspaceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, newInvitationResponse(invitation, true))
+}
+
+// @Summary List pending invitations
+// @Description Lists invitations not yet accepted nor expired. Requires the owner or admin role.
+// @Tags workspaces
+// @Produce json
+// @Param X-User-ID header string true "User ID"
+// @Param id path string true "Workspace ID"
+// @Success 200 {array} InvitationResponse
+// @Failure 401 {object} TaskErrorResponse
+// @Failure 403 {object} TaskErrorResponse
+// @Failure 404 {object} TaskErrorResponse "Workspace not found or caller is not a member"
+// @Failure 500 {object} TaskErrorResponse
+// @Router /api/v1/workspaces/{id}/invitations [get]
+func (h *WorkspaceHandler) ListInvitations(c *gin.Context) {
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, TaskErrorResponse{Error: "missing or invalid " + middleware.UserIDHeader + " header"})
+		return
+	}
+
+	invitations, err := h.ListInvitationsUC.Execute(c.Request.Context(), usecaseworkspace.ListInvitationsInput{
+		WorkspaceID: c.Param("id"),
+		UserID:      userID,
+	})
+	if err != nil {
+		workspaceError(c, err)
+		return
+	}
+
+	resp := make([]InvitationResponse, 0, len(invitations))
+	for _, invitation := range invitations {
+		resp = append(resp, newInvitationResponse(invitation, false))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// @Summary Revoke an invitation
+// @Description Requires the owner or admin role.
+// @Tags workspaces
+// @Param X-User-ID header string true "User ID"
+// @Param id path string true "Workspace ID"
+// @Param invitation_id path string true "Invitation ID"
+// @Success 204
+// @Failure 401 {object} TaskErrorResponse
+// @Failure 403 {object} TaskErrorResponse
+// @Failure 404 {object} TaskErrorResponse "Workspace or invitation not found"
+// @Failure 500 {object} TaskErrorResponse
+// @Router /api/v1/workspaces/{id}/invitations/{invitation_id} [delete]
+func (h *WorkspaceHandler) RevokeInvitation(c *gin.Context) {
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, TaskErrorResponse{Error: "missing or invalid " + middleware.UserIDHeader + " header"})
+		return
+	}
+
+	err := h.RevokeInvitationUC.Execute(c.Request.Context(), usecaseworkspace.RevokeInvitationInput{
+		WorkspaceID:  c.Param("id"),
+		UserID:       userID,
+		InvitationID: c.Param("invitation_id"),
+	})
+	if err != nil {
+		workspaceError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Accept an invitation
+// @Description Joins the workspace with the invitation's role. The caller's email must be the one
+// @Description the invitation was sent to.
+// @Tags workspaces
+// @Accept json
+// @Produce json
+// @Param X-User-ID header string true "User ID"
+// @Param body body AcceptInvitationRequest true "Invitation token"
+// @Success 200 {object} MemberResponse
+// @Failure 400 {object} TaskErrorResponse
+// @Failure 401 {object} TaskErrorResponse
+// @Failure 403 {object} TaskErrorResponse "Invitation sent to another email"
+// @Failure 404 {object} TaskErrorResponse "Invitation not found"
+// @Failure 409 {object} TaskErrorResponse "Already a member"
+// @Failure 422 {object} TaskErrorResponse "Invitation expired or already accepted"
+// @Failure 500 {object} TaskErrorResponse
+// @Router /api/v1/invitations/accept [post]
+func (h *WorkspaceHandler) AcceptInvitation(c *gin.Context) {
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, TaskErrorResponse{Error: "missing or invalid " + middleware.UserIDHeader + " header"})
+		return
+	}
+
+	var req AcceptInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, TaskErrorResponse{Error: err.Error()})
+		return
+	}
+	if err := h.Validate.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, TaskErrorResponse{Error: err.Error()})
+		return
+	}
+
+	member, err := h.AcceptInvitationUC.Execute(c.Request.Context(), usecaseworkspace.AcceptInvitationInput{
+		UserID: userID,
+		Token:  req.Token,
+	})
+	if err != nil {
+		workspaceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newMemberResponse(member))
+}
+
+func workspaceError(c *gin.Context, err error) {
+	switch usecase.ErrorKind(err) {
+	case usecase.ErrorKindValidation:
+		c.JSON(http.StatusUnprocessableEntity, TaskErrorResponse{Error: err.Error()})
+	case usecase.ErrorKindNotFound:
+		c.JSON(http.StatusNotFound, TaskErrorResponse{Error: err.Error()})
+	case usecase.ErrorKindConflict:
+		c.JSON(http.StatusConflict, TaskErrorResponse{Error: err.Error()})
+	case usecase.ErrorKindForbidden:
+		c.JSON(http.StatusForbidden, TaskErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, TaskErrorResponse{Error: usecase.ErrUnknown.Error()})
+	}
+}
+
+//
+// ------------------- REQUESTS / RESPONSES -------------------
+//
+
+type CreateWorkspaceRequest struct {
+	Name string `json:"name" validate:"required" example:"Platform team"`
+}
+
+type ChangeMemberRoleRequest struct {
+	Role string `json:"role" validate:"required" enums:"admin,member,guest" example:"member"`
+}
+
+type InviteMemberRequest struct {
+	Email string `json:"email" validate:"required" example:"ana@example.com"`
+	Role  string `json:"role" validate:"required" enums:"admin,member,guest" example:"member"`
+}
+
+type AcceptInvitationRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+type WorkspaceResponse struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	OwnerID string `json:"owner_id"`
+	// Role é o papel de quem consultou; só aparece na consulta individual.
+	Role      string     `json:"role,omitempty" example:"member"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at"`
+}
+
+type MemberResponse struct {
+	UserID   string    `json:"user_id"`
+	Role     string    `json:"role" example:"member"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+type InvitationResponse struct {
+	ID        string `json:"id"`
+	Email     string `json:"email"`
+	Role      string `json:"role" example:"member"`
+	InvitedBy string `json:"invited_by"`
+	// Token só é devolvido na criação do convite.
+	Token     string    `json:"token,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func newWorkspaceResponse(workspace *domainWorkspace.Workspace, role string) WorkspaceResponse {
+	return WorkspaceResponse{
+		ID:        workspace.ID,
+		Name:      workspace.Name,
+		OwnerID:   workspace.OwnerID,
+		Role:      role,
+		CreatedAt: workspace.CreatedAt,
+		UpdatedAt: workspace.UpdatedAt,
+	}
+}
+
+func newMemberResponse(member *domainWorkspace.Member) MemberResponse {
+	return MemberResponse{
+		UserID:   member.UserID,
+		Role:     string(member.Role),
+		JoinedAt: member.JoinedAt,
+	}
+}
+
+func newInvitationResponse(invitation *domainWorkspace.Invitation, withToken bool) InvitationResponse {
+	resp := InvitationResponse{
+		ID:        invitation.ID,
+		Email:     invitation.Email,
+		Role:      string(invitation.Role),
+		InvitedBy: invitation.InvitedBy,
+		CreatedAt: invitation.CreatedAt,
+		ExpiresAt: invitation.ExpiresAt,
+	}
+	if withToken {
+		resp.Token = invitation.Token
+	}
+	return resp
+}
@@ -0,0 +1,168 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hoyci/todo-ddd/internal/adapters/idempotency"
+	domainWorkspace "github.com/hoyci/todo-ddd/pkg/domain/workspace"
+	"github.com/hoyci/todo-ddd/pkg/logging"
+)
+
+const maxIdempotencyKeyLength = 255
+
+// maxIdempotentBodySize limita o corpo lido para a impressão digital. Cobre
+// o maior corpo aceito nas rotas idempotentes, o arquivo da importação.
+const maxIdempotentBodySize = 2 << 20
+
+// replayedHeaders são os cabeçalhos gravados junto com a resposta e
+// reproduzidos na repetição; os demais são recalculados a cada requisição.
+var replayedHeaders = []string{"Location", "X-Undo-Token", "X-Undo-Expires-At"}
+
+// Idempotency grava a resposta de requisições com o header Idempotency-Key e
+// a reproduz quando a mesma chave é reenviada. O escopo da chave inclui a
+// rota, o usuário e o workspace, para que clientes diferentes não colidam.
+//
+// A requisição original é cancelada depois de timeout, o mesmo WriteTimeout
+// do servidor: a partir daí a resposta não chega mais ao cliente, e o store
+// precisa de um prazo de chave em processamento maior que ele.
+func Idempotency(store idempotency.Store, timeout time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(idempotency.Header)
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "idempotency key too long"})
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxIdempotentBodySize))
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{"error": "request body too large"})
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "failed to read request body"})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := c.Request.Context()
+		logger := logging.ForPackage(ctx, "api")
+
+		scope := c.Request.Method + " " + c.FullPath() + " " + subject(c)
+		if workspaceID := domainWorkspace.ScopeFrom(ctx); workspaceID != "" {
+			scope += " workspace:" + workspaceID
+		}
+		fingerprint := idempotency.Fingerprint(c.Request.Method, c.Request.URL.RequestURI(), body)
+
+		existing, reserved, err := store.Reserve(ctx, scope, key, fingerprint, time.Now())
+		if err != nil {
+			if errors.Is(err, idempotency.ErrKeyNotFound) {
+				c.Header("Retry-After", "1")
+				c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "idempotency key is being reset, retry"})
+				return
+			}
+			logger.Error("idempotency store unavailable", "error", err)
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "unexpected error"})
+			return
+		}
+
+		if !reserved {
+			switch {
+			case existing.Fingerprint != fingerprint:
+				c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{
+					"error": "idempotency key already used with a different payload",
+				})
+			case !existing.Completed:
+				c.Header("Retry-After", "1")
+				c.AbortWithStatusJSON(http.StatusConflict, gin.H{
+					"error": "a request with this idempotency key is still in progress",
+				})
+			default:
+				for name, values := range existing.Response.Headers {
+					for _, value := range values {
+						c.Writer.Header().Add(name, value)
+					}
+				}
+				c.Header("Idempotent-Replayed", "true")
+				c.Data(existing.Response.StatusCode, existing.Response.ContentType, existing.Response.Body)
+				c.Abort()
+			}
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+
+		handlerCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(handlerCtx)
+
+		defer func() {
+			// Erros do servidor (ou panics) liberam a chave para nova tentativa.
+			if p := recover(); p != nil {
+				releaseKey(c, store, scope, key)
+				panic(p)
+			}
+			if c.Writer.Status() >= http.StatusInternalServerError {
+				releaseKey(c, store, scope, key)
+				return
+			}
+
+			err := store.Complete(ctx, scope, key, idempotency.Response{
+				StatusCode:  c.Writer.Status(),
+				ContentType: c.Writer.Header().Get("Content-Type"),
+				Headers:     replayableHeaders(c.Writer.Header()),
+				Body:        recorder.body.Bytes(),
+			}, time.Now())
+			if err != nil {
+				logger.Error("error storing idempotent response", "error", err)
+			}
+		}()
+
+		c.Next()
+	}
+}
+
+func replayableHeaders(header http.Header) http.Header {
+	kept := http.Header{}
+	for _, name := range replayedHeaders {
+		if values := header.Values(name); len(values) > 0 {
+			kept[http.CanonicalHeaderKey(name)] = values
+		}
+	}
+	return kept
+}
+
+// releaseKey usa um contexto próprio: o da requisição pode já ter expirado,
+// e a chave precisa ser liberada mesmo assim.
+func releaseKey(c *gin.Context, store idempotency.Store, scope, key string) {
+	ctx := context.WithoutCancel(c.Request.Context())
+	if err := store.Release(ctx, scope, key); err != nil {
+		logging.ForPackage(ctx, "api").Error("error releasing idempotency key", "error", err)
+	}
+}
+
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,173 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hoyci/todo-ddd/internal/adapters/idempotency"
+)
+
+// memoryStore guarda as chaves em um mapa, sem expiração.
+type memoryStore struct {
+	mu      sync.Mutex
+	records map[string]*idempotency.Record
+}
+
+func (s *memoryStore) Reserve(_ context.Context, scope, key, fingerprint string, _ time.Time) (*idempotency.Record, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if record, ok := s.records[scope+" "+key]; ok {
+		return record, false, nil
+	}
+	s.records[scope+" "+key] = &idempotency.Record{Fingerprint: fingerprint}
+	return nil, true, nil
+}
+
+func (s *memoryStore) Complete(_ context.Context, scope, key string, response idempotency.Response, _ time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	record := s.records[scope+" "+key]
+	record.Completed = true
+	record.Response = response
+	return nil
+}
+
+func (s *memoryStore) Release(_ context.Context, scope, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, scope+" "+key)
+	return nil
+}
+
+func TestIdempotencyReplay(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store := &memoryStore{records: map[string]*idempotency.Record{}}
+
+	calls := 0
+	router := gin.New()
+	router.POST("/tasks", Idempotency(store, time.Minute), func(c *gin.Context) {
+		calls++
+		if _, ok := c.Request.Context().Deadline(); !ok {
+			t.Error("the original request has no deadline")
+		}
+		c.Header("Location", "/api/v1/tasks/t1")
+		c.Header("X-Undo-Token", "undo-1")
+		c.Header("X-Undo-Expires-At", "2026-01-01T00:00:00Z")
+		c.Header("X-Not-Replayed", "1")
+		c.JSON(http.StatusCreated, gin.H{"id": "t1"})
+	})
+
+	send := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/tasks", strings.NewReader(`{"title":"x"}`))
+		req.Header.Set(idempotency.Header, "key-1")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+	first := send()
+	replay := send()
+
+	if calls != 1 {
+		t.Fatalf("handler ran %d times, want 1", calls)
+	}
+	if replay.Header().Get("Idempotent-Replayed") != "true" {
+		t.Error("the second response is not marked as replayed")
+	}
+	if replay.Code != first.Code || replay.Body.String() != first.Body.String() {
+		t.Errorf("replay = %d %s, want %d %s", replay.Code, replay.Body, first.Code, first.Body)
+	}
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"Location", "/api/v1/tasks/t1"},
+		{"X-Undo-Token", "undo-1"},
+		{"X-Undo-Expires-At", "2026-01-01T00:00:00Z"},
+		{"Content-Type", "application/json; charset=utf-8"},
+		{"X-Not-Replayed", ""},
+	}
+	for _, tt := range tests {
+		if got := replay.Header().Get(tt.header); got != tt.want {
+			t.Errorf("replayed %s = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestIdempotencyTimeoutReleasesKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store := &memoryStore{records: map[string]*idempotency.Record{}}
+
+	router := gin.New()
+	router.POST("/slow", Idempotency(store, 10*time.Millisecond), func(c *gin.Context) {
+		<-c.Request.Context().Done()
+		c.Status(http.StatusInternalServerError)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/slow", nil)
+	req.Header.Set(idempotency.Header, "key-1")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	if len(store.records) != 0 {
+		t.Errorf("the key of a request cut by the timeout was kept: %v", store.records)
+	}
+}
+
+func TestIdempotencyFingerprint(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store := &memoryStore{records: map[string]*idempotency.Record{}}
+
+	calls := 0
+	router := gin.New()
+	router.POST("/tasks/import", Idempotency(store, time.Minute), func(c *gin.Context) {
+		calls++
+		c.Status(http.StatusOK)
+	})
+
+	send := func(key, target, body string) int {
+		req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+		req.Header.Set(idempotency.Header, key)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	if got := send("key-1", "/tasks/import?dry_run=true", "title\nx\n"); got != http.StatusOK {
+		t.Fatalf("first request = %d, want %d", got, http.StatusOK)
+	}
+
+	tests := []struct {
+		name   string
+		target string
+		body   string
+		want   int
+	}{
+		{"same request", "/tasks/import?dry_run=true", "title\nx\n", http.StatusOK},
+		// A query muda o efeito da importação, então conta como outro payload.
+		{"another query", "/tasks/import?dry_run=false", "title\nx\n", http.StatusUnprocessableEntity},
+		{"no query", "/tasks/import", "title\nx\n", http.StatusUnprocessableEntity},
+		{"another body", "/tasks/import?dry_run=true", "title\ny\n", http.StatusUnprocessableEntity},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := send("key-1", tt.target, tt.body); got != tt.want {
+				t.Errorf("status = %d, want %d", got, tt.want)
+			}
+		})
+	}
+	if calls != 1 {
+		t.Errorf("handler ran %d times, want 1", calls)
+	}
+
+	// O corpo é lido com limite; acima dele nada é reservado nem executado.
+	if got := send("key-2", "/tasks/import", strings.Repeat("x", maxIdempotentBodySize+1)); got != http.StatusRequestEntityTooLarge {
+		t.Errorf("oversized body = %d, want %d", got, http.StatusRequestEntityTooLarge)
+	}
+	if len(store.records) != 1 || calls != 1 {
+		t.Errorf("after the oversized body: %d keys reserved and %d calls, want 1 and 1", len(store.records), calls)
+	}
+}
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/hoyci/todo-ddd/pkg/policy"
+)
+
+// UserIDHeader identifica o usuário que faz a requisição. A API ainda não
+// possui autenticação própria, então o valor é tratado como informado pelo
+// cliente (ou por um gateway à frente do serviço).
+const UserIDHeader = "X-User-ID"
+
+// UserIDQuery substitui UserIDHeader onde o cliente não consegue enviar
+// cabeçalhos próprios, como no handshake WebSocket de um navegador.
+const UserIDQuery = "user_id"
+
+const userIDKey = "user_id"
+
+func Identity() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		setUserID(c, c.GetHeader(UserIDHeader))
+		c.Next()
+	}
+}
+
+// QueryIdentity aceita o usuário em UserIDQuery quando o cabeçalho não foi
+// enviado. O valor tem a mesma confiança do cabeçalho, então a rota deve
+// conferir a origem de requisições vindas de navegadores.
+func QueryIdentity() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := UserID(c); !ok {
+			setUserID(c, c.Query(UserIDQuery))
+		}
+		c.Next()
+	}
+}
+
+func setUserID(c *gin.Context, userID string) {
+	if userID == "" {
+		return
+	}
+	if _, err := uuid.Parse(userID); err == nil {
+		c.Set(userIDKey, userID)
+		c.Request = c.Request.WithContext(policy.WithSubject(c.Request.Context(), userID))
+	}
+}
+
+func UserID(c *gin.Context) (string, bool) {
+	userID := c.GetString(userIDKey)
+	return userID, userID != ""
+}
+
+// subject identifica quem faz a requisição: o usuário, quando conhecido, ou
+// o IP do cliente.
+func subject(c *gin.Context) string {
+	if userID, ok := UserID(c); ok {
+		return "user:" + userID
+	}
+	return "ip:" + c.ClientIP()
+}
@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hoyci/todo-ddd/pkg/logging"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Logger injeta no contexto da requisição um logger enriquecido com request
+// ID, rota, usuário e trace, e registra uma linha de acesso ao final.
+func Logger(base *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		ctx := c.Request.Context()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+
+		attrs := []any{
+			"request_id", GetRequestID(c),
+			"method", c.Request.Method,
+			"route", route,
+		}
+		if userID, ok := UserID(c); ok {
+			attrs = append(attrs, "user_id", userID)
+		}
+		if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+			attrs = append(attrs, "trace_id", sc.TraceID().String())
+		}
+
+		logger := base.With(attrs...)
+		c.Request = c.Request.WithContext(logging.WithLogger(ctx, logger))
+
+		c.Next()
+
+		status := c.Writer.Status()
+		level := slog.LevelInfo
+		switch {
+		case status >= 500:
+			level = slog.LevelError
+		case status >= 400:
+			level = slog.LevelWarn
+		}
+
+		logger.With(logging.PackageKey, "api").Log(ctx, level, "request completed",
+			"status", status,
+			"path", c.Request.URL.Path,
+			"client_ip", c.ClientIP(),
+			"latency_ms", time.Since(start).Milliseconds(),
+		)
+	}
+}
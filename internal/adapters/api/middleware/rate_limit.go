@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hoyci/todo-ddd/internal/adapters/ratelimit"
+	"github.com/hoyci/todo-ddd/pkg/logging"
+)
+
+// RateLimit aplica a política da rota por IP e, quando identificado, também
+// por usuário. Se o store falhar a requisição segue, para não derrubar a API
+// por causa do limitador.
+func RateLimit(limiter *ratelimit.Limiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		policy, ok := limiter.PolicyFor(c.Request.Method, c.FullPath())
+		if !ok {
+			c.Next()
+			return
+		}
+
+		result, err := limiter.Allow(c.Request.Context(), policy, rateLimitSubjects(c)...)
+		if err != nil {
+			logging.ForPackage(c.Request.Context(), "api").Error("rate limiter unavailable", "error", err)
+			c.Next()
+			return
+		}
+
+		c.Header("RateLimit-Policy", policy.String())
+		c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("RateLimit-Reset", strconv.Itoa(int(result.Reset.Seconds())))
+
+		if !result.Allowed {
+			c.Header("Retry-After", strconv.Itoa(int(result.RetryAfter.Seconds())))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "rate limit exceeded"})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// rateLimitSubjects devolve os buckets consumidos pela requisição. O do IP
+// vale sempre: X-User-ID é informado pelo cliente, então trocar de usuário a
+// cada requisição não escapa do limite.
+func rateLimitSubjects(c *gin.Context) []string {
+	subjects := []string{"ip:" + c.ClientIP()}
+	if userID, ok := UserID(c); ok {
+		subjects = append(subjects, "user:"+userID)
+	}
+	return subjects
+}
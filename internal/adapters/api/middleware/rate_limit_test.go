@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hoyci/todo-ddd/internal/adapters/ratelimit"
+)
+
+// failingStore simula o store do limitador fora do ar.
+type failingStore struct{}
+
+func (failingStore) Take(context.Context, string, ratelimit.Policy, time.Time) (ratelimit.Result, error) {
+	return ratelimit.Result{}, errors.New("store unavailable")
+}
+
+func TestRateLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	limiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), map[string]ratelimit.Policy{
+		"POST /tasks": {Name: "create_task", Limit: 2, Window: time.Minute},
+	}, nil)
+
+	router := gin.New()
+	router.Use(Identity(), RateLimit(limiter))
+	router.POST("/tasks", func(c *gin.Context) { c.Status(http.StatusCreated) })
+	router.GET("/tasks", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	send := func(method, userID string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/tasks", nil)
+		if userID != "" {
+			req.Header.Set(UserIDHeader, userID)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	const u1 = "00000000-0000-0000-0000-000000000001"
+	const u2 = "00000000-0000-0000-0000-000000000002"
+	tests := []struct {
+		name       string
+		method     string
+		userID     string
+		want       int
+		remaining  string
+		retryAfter string
+	}{
+		{"first", http.MethodPost, u1, http.StatusCreated, "1", ""},
+		{"second", http.MethodPost, u1, http.StatusCreated, "0", ""},
+		{"over the limit", http.MethodPost, u1, http.StatusTooManyRequests, "0", "30"},
+		// O bucket do IP também esgotou: outro X-User-ID não escapa.
+		{"another user from the same IP", http.MethodPost, u2, http.StatusTooManyRequests, "0", "30"},
+		{"route without a policy", http.MethodGet, u1, http.StatusOK, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := send(tt.method, tt.userID)
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.want)
+			}
+			if got := rec.Header().Get("RateLimit-Remaining"); got != tt.remaining {
+				t.Errorf("RateLimit-Remaining = %q, want %q", got, tt.remaining)
+			}
+			if got := rec.Header().Get("Retry-After"); got != tt.retryAfter {
+				t.Errorf("Retry-After = %q, want %q", got, tt.retryAfter)
+			}
+			if tt.remaining != "" && rec.Header().Get("RateLimit-Policy") != "2;w=60" {
+				t.Errorf("RateLimit-Policy = %q, want %q", rec.Header().Get("RateLimit-Policy"), "2;w=60")
+			}
+		})
+	}
+
+	// Com o store fora do ar a requisição segue sem limite.
+	failing := gin.New()
+	failing.Use(RateLimit(ratelimit.NewLimiter(failingStore{}, nil, &ratelimit.Policy{Name: "default", Limit: 1, Window: time.Minute})))
+	failing.GET("/tasks", func(c *gin.Context) { c.Status(http.StatusOK) })
+	rec := httptest.NewRecorder()
+	failing.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tasks", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("status with the store down = %d, want %d", rec.Code, http.StatusOK)
+	}
+}
@@ -0,0 +1,16 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	domainAudit "github.com/hoyci/todo-ddd/pkg/domain/audit"
+)
+
+// SourceIP disponibiliza no contexto o IP do cliente, gravado nas entradas
+// do log de auditoria. X-Forwarded-For só é considerado quando vem de um dos
+// proxies confiáveis do roteador; de resto vale o endereço da conexão.
+func SourceIP() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request = c.Request.WithContext(domainAudit.WithSourceIP(c.Request.Context(), c.ClientIP()))
+		c.Next()
+	}
+}
@@ -0,0 +1,149 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hoyci/todo-ddd/internal/adapters/db/sqlite"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracing(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	// Os tracers dos pacotes delegam ao provider global, que só pode ser
+	// trocado uma vez por processo.
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	t.Chdir(t.TempDir())
+	if err := os.Mkdir("data", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	db, err := sqlite.InitDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	users := sqlite.NewSQLiteUserRepository(db)
+	tasks := sqlite.NewSQLiteTaskRepository(db)
+
+	router := gin.New()
+	router.Use(RequestID(), Tracing())
+	router.GET("/users/:id/tasks", func(c *gin.Context) {
+		ctx := c.Request.Context()
+		// Usuário inexistente: sql.ErrNoRows não marca o span como erro.
+		if _, err := users.FindByID(ctx, c.Param("id")); err == nil {
+			t.Error("found a user in an empty database")
+		}
+		if _, err := tasks.List(ctx, c.Param("id")); err != nil {
+			t.Error(err)
+		}
+		if c.Query("fail") != "" {
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+		c.Status(http.StatusOK)
+	})
+
+	const parent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	tests := []struct {
+		name        string
+		target      string
+		traceparent string
+		wantStatus  codes.Code
+	}{
+		{"continues the incoming trace", "/users/u1/tasks", parent, codes.Unset},
+		{"starts a new trace", "/users/u1/tasks", "", codes.Unset},
+		{"server error", "/users/u1/tasks?fail=1", parent, codes.Error},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder.Reset()
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.traceparent != "" {
+				req.Header.Set("traceparent", tt.traceparent)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			var server sdktrace.ReadOnlySpan
+			var queries []sdktrace.ReadOnlySpan
+			for _, span := range recorder.Ended() {
+				switch span.SpanKind() {
+				case trace.SpanKindServer:
+					server = span
+				case trace.SpanKindClient:
+					queries = append(queries, span)
+				}
+			}
+			if server == nil {
+				t.Fatal("no server span recorded")
+			}
+
+			if server.Name() != "GET /users/:id/tasks" {
+				t.Errorf("server span name = %q, want the route", server.Name())
+			}
+			if v := spanAttr(server, "http.route"); v.AsString() != "/users/:id/tasks" {
+				t.Errorf("http.route = %q", v.AsString())
+			}
+			if v := spanAttr(server, "http.response.status_code"); int(v.AsInt64()) != rec.Code {
+				t.Errorf("http.response.status_code = %d, want %d", v.AsInt64(), rec.Code)
+			}
+			if v := spanAttr(server, "http.request_id"); v.AsString() == "" || v.AsString() != rec.Header().Get(RequestIDHeader) {
+				t.Errorf("http.request_id = %q, want the response request ID", v.AsString())
+			}
+			if server.Status().Code != tt.wantStatus {
+				t.Errorf("server span status = %v, want %v", server.Status().Code, tt.wantStatus)
+			}
+
+			remote := server.Parent()
+			if tt.traceparent != "" {
+				if remote.TraceID().String() != "4bf92f3577b34da6a3ce929d0e0e4736" || remote.SpanID().String() != "00f067aa0ba902b7" || !remote.IsRemote() {
+					t.Errorf("server span parent = %v, want the incoming traceparent", remote)
+				}
+			} else if remote.IsValid() {
+				t.Errorf("server span has parent %v without a traceparent", remote)
+			}
+
+			// A resposta leva o traceparent do span do servidor.
+			want := "00-" + server.SpanContext().TraceID().String() + "-" + server.SpanContext().SpanID().String() + "-01"
+			if got := rec.Header().Get("traceparent"); got != want {
+				t.Errorf("response traceparent = %q, want %q", got, want)
+			}
+
+			if len(queries) != 2 {
+				t.Fatalf("recorded %d query spans, want 2", len(queries))
+			}
+			for _, query := range queries {
+				if query.Parent().SpanID() != server.SpanContext().SpanID() || query.SpanContext().TraceID() != server.SpanContext().TraceID() {
+					t.Errorf("%s is not a child of the server span", query.Name())
+				}
+				if query.Name() != "sqlite.select" || query.Status().Code != codes.Unset {
+					t.Errorf("query span = %s with status %v, want sqlite.select without error", query.Name(), query.Status().Code)
+				}
+				if v := spanAttr(query, "db.system"); v.AsString() != "sqlite" {
+					t.Errorf("%s db.system = %q, want sqlite", query.Name(), v.AsString())
+				}
+			}
+		})
+	}
+}
+
+func spanAttr(span sdktrace.ReadOnlySpan, key attribute.Key) attribute.Value {
+	for _, attr := range span.Attributes() {
+		if attr.Key == key {
+			return attr.Value
+		}
+	}
+	return attribute.Value{}
+}
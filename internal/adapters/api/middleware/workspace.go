@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	domainWorkspace "github.com/hoyci/todo-ddd/pkg/domain/workspace"
+)
+
+// WorkspaceIDHeader seleciona o workspace em que a requisição acontece.
+// Sem ele, as tarefas são as do espaço pessoal do usuário.
+const WorkspaceIDHeader = "X-Workspace-ID"
+
+// Workspace grava o workspace no contexto da requisição, de onde os
+// repositórios o leem para filtrar as consultas. Um ID inválido é rejeitado
+// em vez de ignorado, para não cair no espaço pessoal por engano.
+func Workspace() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		workspaceID := c.GetHeader(WorkspaceIDHeader)
+		if workspaceID == "" {
+			c.Next()
+			return
+		}
+		if _, err := uuid.Parse(workspaceID); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid " + WorkspaceIDHeader + " header"})
+			return
+		}
+
+		c.Request = c.Request.WithContext(domainWorkspace.WithScope(c.Request.Context(), workspaceID))
+		c.Next()
+	}
+}
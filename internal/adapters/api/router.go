@@ -1,8 +1,15 @@
 package api
 
 import (
+	"log/slog"
+	"time"
+
 	"github.com/gin-gonic/gin"
 	"github.com/hoyci/todo-ddd/internal/adapters/api/handler"
+	"github.com/hoyci/todo-ddd/internal/adapters/api/middleware"
+	"github.com/hoyci/todo-ddd/internal/adapters/idempotency"
+	"github.com/hoyci/todo-ddd/internal/adapters/metrics"
+	"github.com/hoyci/todo-ddd/internal/adapters/ratelimit"
 	swagFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
@@ -11,26 +18,135 @@ func SetupRouter(
 	taskHandler *handler.TaskHandler,
 	userHandler *handler.UserHandler,
 	onboardingHandler *handler.OnboardingHandler,
-) *gin.Engine {
-	r := gin.Default()
+	eventHandler *handler.EventHandler,
+	realtimeHandler *handler.RealtimeHandler,
+	graphqlHandler *handler.GraphQLHandler,
+	commentHandler *handler.CommentHandler,
+	revisionHandler *handler.RevisionHandler,
+	undoHandler *handler.UndoHandler,
+	reminderHandler *handler.ReminderHandler,
+	notificationHandler *handler.NotificationHandler,
+	attachmentHandler *handler.AttachmentHandler,
+	sharingHandler *handler.SharingHandler,
+	workspaceHandler *handler.WorkspaceHandler,
+	adminHandler *handler.AdminHandler,
+	appMetrics *metrics.Metrics,
+	limiter *ratelimit.Limiter,
+	idempotencyStore idempotency.Store,
+	writeTimeout time.Duration,
+	trustedProxies []string,
+) (*gin.Engine, error) {
+	r := gin.New()
+	// Só os proxies listados podem informar o IP do cliente em
+	// X-Forwarded-For; sem nenhum, vale o endereço da conexão. É esse IP que
+	// identifica o bucket do rate limit e a origem no log de auditoria.
+	if err := r.SetTrustedProxies(trustedProxies); err != nil {
+		return nil, err
+	}
+	// Workspace vem depois do log, do trace e das métricas, para que as
+	// requisições recusadas por um X-Workspace-ID inválido também apareçam
+	// neles.
+	r.Use(
+		gin.Recovery(),
+		middleware.RequestID(),
+		middleware.SourceIP(),
+		middleware.Identity(),
+		middleware.Tracing(),
+		middleware.Logger(slog.Default()),
+		middleware.Metrics(appMetrics),
+		middleware.Workspace(),
+	)
 
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swagFiles.Handler))
+	r.GET("/metrics", gin.WrapH(appMetrics.Handler()))
 
-	v1 := r.Group("/api/v1")
+	idempotent := middleware.Idempotency(idempotencyStore, writeTimeout)
+
+	v1 := r.Group("/api/v1", middleware.RateLimit(limiter))
 	{
-		v1.POST("/tasks", taskHandler.Create)
-		v1.GET("/tasks/:user_id", taskHandler.List)
+		v1.POST("/tasks", idempotent, taskHandler.Create)
+		v1.POST("/tasks/batch", idempotent, taskHandler.Batch)
+		v1.POST("/tasks/import", idempotent, taskHandler.Import)
+		v1.POST("/tasks/quick-add", idempotent, taskHandler.QuickAdd)
+		v1.GET("/tasks/export", taskHandler.Export)
+		v1.GET("/tasks/assigned", sharingHandler.Assigned)
+		// O gin exige o mesmo nome de parâmetro em /tasks/:id/...; aqui o
+		// segmento é o ID do usuário.
+		v1.GET("/tasks/:id", taskHandler.List)
+		v1.GET("/events", eventHandler.Stream)
+		v1.GET("/ws", middleware.QueryIdentity(), realtimeHandler.Connect)
 		v1.PUT("/tasks/:id", taskHandler.Update)
+		v1.PATCH("/tasks/:id", taskHandler.Patch)
 		v1.PATCH("/tasks/:id/status", taskHandler.UpdateStatus)
 		v1.DELETE("/tasks/:id", taskHandler.Delete)
 
+		v1.POST("/tasks/:id/comments", idempotent, commentHandler.Add)
+		v1.GET("/tasks/:id/comments", commentHandler.List)
+		v1.PUT("/tasks/:id/comments/:comment_id", commentHandler.Edit)
+		v1.DELETE("/tasks/:id/comments/:comment_id", commentHandler.Delete)
+		v1.GET("/tasks/:id/activity", commentHandler.Activity)
+
+		v1.GET("/tasks/:id/revisions", revisionHandler.List)
+		v1.GET("/tasks/:id/revisions/diff", revisionHandler.Diff)
+		v1.POST("/tasks/:id/revisions/:number/revert", revisionHandler.Revert)
+
+		v1.POST("/undo/:token", undoHandler.Undo)
+
+		v1.POST("/tasks/:id/reminders", reminderHandler.Add)
+		v1.GET("/tasks/:id/reminders", reminderHandler.List)
+		v1.DELETE("/tasks/:id/reminders/:reminder_id", reminderHandler.Delete)
+
+		v1.GET("/notifications", notificationHandler.List)
+		v1.GET("/notifications/unread-count", notificationHandler.UnreadCount)
+		v1.POST("/notifications/read", notificationHandler.MarkRead)
+		v1.POST("/notifications/unread", notificationHandler.MarkUnread)
+		v1.GET("/notifications/preferences", notificationHandler.GetPreferences)
+		v1.PUT("/notifications/preferences", notificationHandler.UpdatePreferences)
+
+		v1.POST("/tasks/:id/attachments", attachmentHandler.Upload)
+		v1.GET("/tasks/:id/attachments", attachmentHandler.List)
+		v1.GET("/tasks/:id/attachments/:attachment_id", attachmentHandler.Download)
+		v1.DELETE("/tasks/:id/attachments/:attachment_id", attachmentHandler.Delete)
+
+		v1.PUT("/tasks/:id/assignee", sharingHandler.Assign)
+		v1.DELETE("/tasks/:id/assignee", sharingHandler.Unassign)
+		v1.GET("/tasks/:id/shares", sharingHandler.ListShares)
+		v1.PUT("/tasks/:id/shares/:user_id", sharingHandler.Share)
+		v1.DELETE("/tasks/:id/shares/:user_id", sharingHandler.Revoke)
+		v1.GET("/tasks/:id/watchers", sharingHandler.ListWatchers)
+		v1.PUT("/tasks/:id/watch", sharingHandler.Watch)
+		v1.DELETE("/tasks/:id/watch", sharingHandler.Unwatch)
+
+		v1.POST("/workspaces", workspaceHandler.Create)
+		v1.GET("/workspaces", workspaceHandler.List)
+		v1.GET("/workspaces/:id", workspaceHandler.Get)
+		v1.PATCH("/workspaces/:id", workspaceHandler.Rename)
+		v1.DELETE("/workspaces/:id", workspaceHandler.Delete)
+		v1.GET("/workspaces/:id/members", workspaceHandler.ListMembers)
+		v1.PUT("/workspaces/:id/members/:user_id", workspaceHandler.ChangeRole)
+		v1.DELETE("/workspaces/:id/members/:user_id", workspaceHandler.RemoveMember)
+		v1.POST("/workspaces/:id/invitations", workspaceHandler.Invite)
+		v1.GET("/workspaces/:id/invitations", workspaceHandler.ListInvitations)
+		v1.DELETE("/workspaces/:id/invitations/:invitation_id", workspaceHandler.RevokeInvitation)
+		v1.POST("/invitations/accept", workspaceHandler.AcceptInvitation)
+
 		v1.POST("/users/", userHandler.Create)
 		v1.GET("/users/:id", userHandler.FindByID)
 		v1.PUT("/users/:id", userHandler.Update)
+		v1.PATCH("/users/:id", userHandler.Patch)
 		v1.DELETE("/users/:id", userHandler.Delete)
 
-		v1.POST("/onboarding", onboardingHandler.Setup)
+		v1.GET("/admin/users/:id/roles", adminHandler.ListRoles)
+		v1.PUT("/admin/users/:id/roles/:role", adminHandler.GrantRole)
+		v1.DELETE("/admin/users/:id/roles/:role", adminHandler.RevokeRole)
+		v1.GET("/admin/audit", adminHandler.ListAudit)
+
+		v1.POST("/onboarding", idempotent, onboardingHandler.Setup)
+		v1.GET("/onboarding/verify", onboardingHandler.Verify)
+		v1.POST("/onboarding/verification", onboardingHandler.Resend)
+
+		v1.POST("/graphql", graphqlHandler.Query)
 	}
 
-	return r
+	return r, nil
 }
@@ -0,0 +1,142 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hoyci/todo-ddd/internal/adapters/api/handler"
+	"github.com/hoyci/todo-ddd/internal/adapters/db/sqlite"
+	"github.com/hoyci/todo-ddd/internal/adapters/metrics"
+	"github.com/hoyci/todo-ddd/internal/adapters/ratelimit"
+	domainAudit "github.com/hoyci/todo-ddd/pkg/domain/audit"
+)
+
+// newTestRouter monta o roteador de produção com handlers vazios; as
+// requisições dos testes param antes de chegar aos casos de uso.
+func newTestRouter(t *testing.T, limiter *ratelimit.Limiter, trustedProxies []string) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	t.Chdir(t.TempDir())
+	if err := os.Mkdir("data", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	db, err := sqlite.InitDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	r, err := SetupRouter(
+		&handler.TaskHandler{}, &handler.UserHandler{}, &handler.OnboardingHandler{}, &handler.EventHandler{},
+		&handler.RealtimeHandler{}, &handler.GraphQLHandler{}, &handler.CommentHandler{}, &handler.RevisionHandler{},
+		&handler.UndoHandler{}, &handler.ReminderHandler{}, &handler.NotificationHandler{}, &handler.AttachmentHandler{},
+		&handler.SharingHandler{}, &handler.WorkspaceHandler{}, &handler.AdminHandler{},
+		metrics.New(db, sqlite.NewSQLiteTaskRepository(db)), limiter, nil, time.Minute, trustedProxies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestRateLimitIgnoresSpoofedForwardedFor(t *testing.T) {
+	tests := []struct {
+		name           string
+		trustedProxies []string
+		want           int
+	}{
+		// Sem proxy confiável, trocar X-Forwarded-For a cada requisição não
+		// troca de bucket.
+		{"no trusted proxies", nil, http.StatusTooManyRequests},
+		{"untrusted proxy", []string{"192.0.2.0/24"}, http.StatusTooManyRequests},
+		// Atrás de um proxy confiável, cada cliente tem o seu bucket.
+		{"trusted proxy", []string{"10.0.0.1"}, http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), map[string]ratelimit.Policy{
+				"GET /api/v1/tasks/export": {Name: "export", Limit: 1, Window: time.Minute},
+			}, nil)
+			router := newTestRouter(t, limiter, tt.trustedProxies)
+
+			get := func(forwardedFor string) int {
+				req := httptest.NewRequest(http.MethodGet, "/api/v1/tasks/export", nil)
+				req.RemoteAddr = "10.0.0.1:40000"
+				req.Header.Set("X-Forwarded-For", forwardedFor)
+				rec := httptest.NewRecorder()
+				router.ServeHTTP(rec, req)
+				return rec.Code
+			}
+			// A requisição sem X-User-ID consome o token e para no handler.
+			if got := get("203.0.113.1"); got != http.StatusUnauthorized {
+				t.Fatalf("first request = %d, want %d", got, http.StatusUnauthorized)
+			}
+			if got := get("203.0.113.2"); got != tt.want {
+				t.Errorf("request with another X-Forwarded-For = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetupRouterRejectsInvalidTrustedProxy(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	_, err := SetupRouter(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, 0, []string{"not-an-ip"})
+	if err == nil {
+		t.Error("SetupRouter accepted an invalid trusted proxy")
+	}
+}
+
+func TestAuditSourceIPIgnoresSpoofedForwardedFor(t *testing.T) {
+	tests := []struct {
+		name           string
+		trustedProxies []string
+		remoteAddr     string
+		want           string
+	}{
+		{"no trusted proxies", nil, "10.0.0.1:40000", "10.0.0.1"},
+		{"untrusted proxy", []string{"10.0.0.1"}, "192.0.2.7:40000", "192.0.2.7"},
+		{"trusted proxy", []string{"10.0.0.1"}, "10.0.0.1:40000", "203.0.113.1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newTestRouter(t, ratelimit.NewLimiter(ratelimit.NewMemoryStore(), nil, nil), tt.trustedProxies)
+			// A rota extra passa pelos mesmos middlewares globais e devolve o
+			// IP que o log de auditoria gravaria.
+			router.GET("/source-ip", func(c *gin.Context) {
+				c.String(http.StatusOK, domainAudit.SourceIPFrom(c.Request.Context()))
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/source-ip", nil)
+			req.RemoteAddr = tt.remoteAddr
+			req.Header.Set("X-Forwarded-For", "203.0.113.1")
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if got := rec.Body.String(); got != tt.want {
+				t.Errorf("source IP = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInvalidWorkspaceIsMeasured(t *testing.T) {
+	router := newTestRouter(t, ratelimit.NewLimiter(ratelimit.NewMemoryStore(), nil, nil), nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/tasks/export", nil)
+	req.Header.Set("X-Workspace-ID", "not-a-uuid")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if want := `route="/api/v1/tasks/export",status="400"} 1`; !strings.Contains(rec.Body.String(), want) {
+		t.Errorf("metrics do not count the rejected request: want %s", want)
+	}
+}
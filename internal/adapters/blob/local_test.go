@@ -0,0 +1,128 @@
+package blob
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	domainTask "github.com/hoyci/todo-ddd/pkg/domain/task"
+)
+
+// testStore exercita o contrato de domainTask.BlobStore, comum aos dois
+// adaptadores.
+func testStore(t *testing.T, store domainTask.BlobStore) {
+	t.Helper()
+	ctx := context.Background()
+	key := domainTask.BlobKey("0123abcd")
+	content := "hello, attachments"
+
+	if err := store.Put(ctx, key, strings.NewReader(content), int64(len(content)), "text/plain"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		offset int64
+		whence int
+		want   string
+	}{
+		{"whole object", 0, io.SeekStart, content},
+		{"from offset", 7, io.SeekStart, "attachments"},
+		{"from end", -5, io.SeekEnd, "ments"},
+		{"past the end", 100, io.SeekStart, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj, err := store.Open(ctx, key)
+			if err != nil {
+				t.Fatalf("Open: %v", err)
+			}
+			defer obj.Close()
+			if _, err := obj.Seek(tt.offset, tt.whence); err != nil {
+				t.Fatalf("Seek: %v", err)
+			}
+			got, err := io.ReadAll(obj)
+			if err != nil {
+				t.Fatalf("ReadAll: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("read %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// Chaves são endereçadas pelo conteúdo: gravar de novo a mesma chave
+	// mantém um único objeto legível.
+	if err := store.Put(ctx, key, strings.NewReader(content), int64(len(content)), "text/plain"); err != nil {
+		t.Fatalf("second Put: %v", err)
+	}
+	obj, err := store.Open(ctx, key)
+	if err != nil {
+		t.Fatalf("Open after second Put: %v", err)
+	}
+	got, err := io.ReadAll(obj)
+	obj.Close()
+	if err != nil || string(got) != content {
+		t.Errorf("after second Put read %q, %v", got, err)
+	}
+
+	if err := store.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := store.Open(ctx, key); !errors.Is(err, domainTask.ErrBlobNotFound) {
+		t.Errorf("Open after Delete error = %v, want ErrBlobNotFound", err)
+	}
+	if err := store.Delete(ctx, key); err != nil {
+		t.Errorf("Delete of a missing key = %v, want nil", err)
+	}
+}
+
+func TestLocalStore(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "blobs")
+	store, err := NewLocalStore(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testStore(t, store)
+
+	// Put usa arquivos temporários; nenhum pode sobrar.
+	err = filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if strings.HasPrefix(d.Name(), ".upload-") {
+			t.Errorf("temporary file left behind: %s", path)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLocalStoreInvalidKey(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	for _, key := range []string{"../escape", "/absolute", `sha256\abc`, "a//b", ""} {
+		t.Run(key, func(t *testing.T) {
+			if err := store.Put(ctx, key, bytes.NewReader(nil), 0, "text/plain"); err == nil {
+				t.Error("Put accepted the key")
+			}
+			if _, err := store.Open(ctx, key); err == nil || errors.Is(err, domainTask.ErrBlobNotFound) {
+				t.Errorf("Open error = %v, want an invalid key error", err)
+			}
+			if err := store.Delete(ctx, key); err == nil {
+				t.Error("Delete accepted the key")
+			}
+		})
+	}
+}
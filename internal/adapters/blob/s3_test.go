@@ -0,0 +1,275 @@
+package blob
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hoyci/todo-ddd/internal/adapters/db/sqlite"
+	"github.com/hoyci/todo-ddd/internal/adapters/events"
+	domainTask "github.com/hoyci/todo-ddd/pkg/domain/task"
+	usecasetask "github.com/hoyci/todo-ddd/pkg/usecase/task"
+	usecaseuser "github.com/hoyci/todo-ddd/pkg/usecase/user"
+)
+
+// fakeS3 guarda os objetos em memória e responde ao subconjunto da API REST
+// usado pelo S3Store.
+type fakeS3 struct {
+	t       *testing.T
+	mu      sync.Mutex
+	objects map[string]string
+	calls   map[string]int
+}
+
+func newFakeS3(t *testing.T) (*fakeS3, *httptest.Server) {
+	f := &fakeS3{t: t, objects: map[string]string{}, calls: map[string]int{}}
+	server := httptest.NewServer(f)
+	t.Cleanup(server.Close)
+	return f, server
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls[r.Method]++
+
+	if r.Header.Get("x-amz-date") == "" || r.Header.Get("x-amz-content-sha256") != "UNSIGNED-PAYLOAD" {
+		f.t.Errorf("%s %s without the SigV4 headers", r.Method, r.URL.Path)
+	}
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/") {
+		f.t.Errorf("%s %s Authorization = %q", r.Method, r.URL.Path, r.Header.Get("Authorization"))
+	}
+
+	content, ok := f.objects[r.URL.Path]
+	switch r.Method {
+	case http.MethodPut:
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if int64(len(body)) != r.ContentLength {
+			f.t.Errorf("PUT Content-Length = %d, body has %d bytes", r.ContentLength, len(body))
+		}
+		f.objects[r.URL.Path] = string(body)
+	case http.MethodHead, http.MethodGet:
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		status := http.StatusOK
+		if rng := r.Header.Get("Range"); rng != "" {
+			start, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(rng, "bytes="), "-"))
+			if err != nil || start >= len(content) {
+				w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+				return
+			}
+			content = content[start:]
+			status = http.StatusPartialContent
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+		w.WriteHeader(status)
+		if r.Method == http.MethodGet {
+			io.WriteString(w, content)
+		}
+	case http.MethodDelete:
+		// Como o S3, o fake responde 404 para chaves que não existem.
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		delete(f.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func newTestS3Store(t *testing.T, endpoint string) *S3Store {
+	t.Helper()
+	store, err := NewS3Store(S3Config{
+		Endpoint:        endpoint,
+		Region:          "us-east-1",
+		Bucket:          "attachments",
+		AccessKeyID:     "AKID",
+		SecretAccessKey: "secret",
+		PathStyle:       true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
+
+func TestS3Store(t *testing.T) {
+	fake, server := newFakeS3(t)
+	testStore(t, newTestS3Store(t, server.URL))
+
+	if len(fake.objects) != 0 {
+		t.Errorf("objects left in the bucket: %v", fake.objects)
+	}
+}
+
+func TestS3StoreRangeRead(t *testing.T) {
+	fake, server := newFakeS3(t)
+	store := newTestS3Store(t, server.URL)
+	ctx := context.Background()
+
+	if err := store.Put(ctx, "k", strings.NewReader("0123456789"), 10, "text/plain"); err != nil {
+		t.Fatal(err)
+	}
+	obj, err := store.Open(ctx, "k")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer obj.Close()
+
+	// Seek antes de ler não baixa nada; a leitura pede só o trecho.
+	if _, err := obj.Seek(6, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	if fake.calls[http.MethodGet] != 0 {
+		t.Errorf("Seek issued %d GETs", fake.calls[http.MethodGet])
+	}
+	got, err := io.ReadAll(obj)
+	if err != nil || string(got) != "6789" {
+		t.Errorf("read %q, %v; want the suffix", got, err)
+	}
+	if fake.calls[http.MethodGet] != 1 {
+		t.Errorf("GETs = %d, want 1", fake.calls[http.MethodGet])
+	}
+}
+
+func TestS3StoreObjectURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		endpoint string
+		path     bool
+		want     string
+	}{
+		{"path style", "http://localhost:9000", true, "http://localhost:9000/attachments/sha256/abc"},
+		{"path style with base path", "http://localhost:9000/s3/", true, "http://localhost:9000/s3/attachments/sha256/abc"},
+		{"virtual host", "https://s3.us-east-1.amazonaws.com", false, "https://attachments.s3.us-east-1.amazonaws.com/sha256/abc"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, err := NewS3Store(S3Config{Endpoint: tt.endpoint, Region: "us-east-1", Bucket: "attachments", PathStyle: tt.path})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := store.objectURL("sha256/abc").String(); got != tt.want {
+				t.Errorf("objectURL = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestS3StoreSign(t *testing.T) {
+	store := newTestS3Store(t, "http://localhost:9000")
+	store.now = func() time.Time { return time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC) }
+
+	req, err := store.newRequest(context.Background(), http.MethodGet, "sha256/abc", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := req.Header.Get("x-amz-date"); got != "20260102T030405Z" {
+		t.Errorf("x-amz-date = %q", got)
+	}
+	auth := req.Header.Get("Authorization")
+	for _, part := range []string{
+		"AWS4-HMAC-SHA256 ",
+		"Credential=AKID/20260102/us-east-1/s3/aws4_request",
+		"SignedHeaders=host;x-amz-content-sha256;x-amz-date",
+		"Signature=",
+	} {
+		if !strings.Contains(auth, part) {
+			t.Errorf("Authorization %q lacks %q", auth, part)
+		}
+	}
+
+	// A assinatura é determinística para o mesmo instante.
+	again, _ := store.newRequest(context.Background(), http.MethodGet, "sha256/abc", nil)
+	if again.Header.Get("Authorization") != auth {
+		t.Error("signing the same request twice gave different signatures")
+	}
+
+	anonymous, err := NewS3Store(S3Config{Endpoint: "http://localhost:9000", Region: "us-east-1", Bucket: "b", PathStyle: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, _ = anonymous.newRequest(context.Background(), http.MethodGet, "k", nil)
+	if req.Header.Get("Authorization") != "" {
+		t.Error("requests without credentials must not be signed")
+	}
+}
+
+// TestS3AttachmentDedup sobe os anexos pelos casos de uso reais: o mesmo
+// conteúdo é enviado ao bucket uma vez e só sai dele quando o último anexo
+// que o referencia é removido.
+func TestS3AttachmentDedup(t *testing.T) {
+	fake, server := newFakeS3(t)
+	store := newTestS3Store(t, server.URL)
+
+	t.Chdir(t.TempDir())
+	if err := os.Mkdir("data", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	db, err := sqlite.InitDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	unitOfWork := sqlite.NewSQLiteUnitOfWork(db, events.NewBroker(0))
+	ctx := context.Background()
+
+	user, err := (&usecaseuser.CreateUserUseCase{UoW: unitOfWork}).Execute(ctx, usecaseuser.CreateUserInput{Name: "Ada", Email: "ada@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	task, err := (&usecasetask.CreateTaskUseCase{UoW: unitOfWork}).Execute(ctx, usecasetask.CreateTaskInput{Title: "Report", Priority: 1, UserID: user.User.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	upload := &usecasetask.UploadAttachmentUseCase{UoW: unitOfWork, Blobs: store, MaxSize: 1 << 10, AllowedTypes: []string{"text/plain"}}
+	remove := &usecasetask.DeleteAttachmentUseCase{UoW: unitOfWork, Blobs: store}
+
+	var attachments []*domainTask.Attachment
+	for i, wantDedup := range []bool{false, true} {
+		out, err := upload.Execute(ctx, usecasetask.UploadAttachmentInput{
+			TaskID:      task.ID,
+			UserID:      user.User.ID,
+			FileName:    "report-" + strconv.Itoa(i) + ".txt",
+			ContentType: "text/plain",
+			Content:     strings.NewReader("same content"),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if out.Deduplicated != wantDedup {
+			t.Errorf("upload %d Deduplicated = %v, want %v", i, out.Deduplicated, wantDedup)
+		}
+		attachments = append(attachments, out.Attachment)
+	}
+	if fake.calls[http.MethodPut] != 1 || len(fake.objects) != 1 {
+		t.Fatalf("PUTs = %d, objects = %d; want the content stored once", fake.calls[http.MethodPut], len(fake.objects))
+	}
+
+	key := "/attachments/" + attachments[0].BlobKey()
+	for i, stillStored := range []bool{true, false} {
+		err := remove.Execute(ctx, usecasetask.DeleteAttachmentInput{TaskID: task.ID, AttachmentID: attachments[i].ID, UserID: user.User.ID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := fake.objects[key]; ok != stillStored {
+			t.Errorf("after deleting attachment %d, object stored = %v, want %v", i, ok, stillStored)
+		}
+	}
+}
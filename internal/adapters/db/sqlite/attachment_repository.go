@@ -0,0 +1,114 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	domain "github.com/hoyci/todo-ddd/pkg/domain/task"
+)
+
+type SQLiteAttachmentRepository struct {
+	db *sql.DB
+	tx *sql.Tx
+}
+
+func NewSQLiteAttachmentRepository(db *sql.DB) *SQLiteAttachmentRepository {
+	return &SQLiteAttachmentRepository{db: db}
+}
+
+func (r *SQLiteAttachmentRepository) WithTx(tx *sql.Tx) *SQLiteAttachmentRepository {
+	return &SQLiteAttachmentRepository{tx: tx}
+}
+
+func (r *SQLiteAttachmentRepository) getExecutor() SQLExecutor {
+	if r.tx != nil {
+		return traced(r.tx)
+	}
+	return traced(r.db)
+}
+
+const attachmentColumns = `id, task_id, uploader_id, file_name, content_type, size, hash, created_at`
+
+func scanAttachment(row rowScanner) (*domain.Attachment, error) {
+	a := &domain.Attachment{}
+	if err := row.Scan(&a.ID, &a.TaskID, &a.UploaderID, &a.FileName, &a.ContentType, &a.Size, &a.Hash, &a.CreatedAt); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func (r *SQLiteAttachmentRepository) Save(ctx context.Context, attachment *domain.Attachment) error {
+	_, err := r.getExecutor().ExecContext(ctx, `
+		INSERT INTO task_attachments (id, task_id, uploader_id, file_name, content_type, size, hash, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		attachment.ID, attachment.TaskID, attachment.UploaderID, attachment.FileName, attachment.ContentType,
+		attachment.Size, attachment.Hash, attachment.CreatedAt)
+	return err
+}
+
+func (r *SQLiteAttachmentRepository) FindByID(ctx context.Context, id, taskID string) (*domain.Attachment, error) {
+	row := r.getExecutor().QueryRowContext(ctx, `SELECT `+attachmentColumns+` FROM task_attachments WHERE id = ? AND task_id = ?`, id, taskID)
+	return scanAttachment(row)
+}
+
+func (r *SQLiteAttachmentRepository) ListByTask(ctx context.Context, taskID string) ([]*domain.Attachment, error) {
+	rows, err := r.getExecutor().QueryContext(ctx, `
+		SELECT `+attachmentColumns+`
+		FROM task_attachments
+		WHERE task_id = ?
+		ORDER BY created_at, id`, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var attachments []*domain.Attachment
+	for rows.Next() {
+		a, err := scanAttachment(rows)
+		if err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, a)
+	}
+	return attachments, rows.Err()
+}
+
+func (r *SQLiteAttachmentRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.getExecutor().ExecContext(ctx, `DELETE FROM task_attachments WHERE id = ?`, id)
+	return err
+}
+
+func (r *SQLiteAttachmentRepository) DeleteByTasks(ctx context.Context, taskIDs []string) ([]string, error) {
+	if len(taskIDs) == 0 {
+		return nil, nil
+	}
+
+	args := make([]any, len(taskIDs))
+	for i, id := range taskIDs {
+		args[i] = id
+	}
+	rows, err := r.getExecutor().QueryContext(ctx, `
+		DELETE FROM task_attachments
+		WHERE task_id IN (`+placeholders(len(taskIDs))+`)
+		RETURNING hash`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var hashes []string
+	for rows.Next() {
+		var hash string
+		if err := rows.Scan(&hash); err != nil {
+			return nil, err
+		}
+		hashes = append(hashes, hash)
+	}
+	return hashes, rows.Err()
+}
+
+func (r *SQLiteAttachmentRepository) CountByHash(ctx context.Context, hash string) (int, error) {
+	var count int
+	err := r.getExecutor().QueryRowContext(ctx, `SELECT COUNT(*) FROM task_attachments WHERE hash = ?`, hash).Scan(&count)
+	return count, err
+}
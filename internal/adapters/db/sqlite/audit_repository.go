@@ -0,0 +1,127 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	domain "github.com/hoyci/todo-ddd/pkg/domain/audit"
+)
+
+type SQLiteAuditRepository struct {
+	db *sql.DB
+	tx *sql.Tx
+}
+
+func NewSQLiteAuditRepository(db *sql.DB) *SQLiteAuditRepository {
+	return &SQLiteAuditRepository{db: db}
+}
+
+func (r *SQLiteAuditRepository) WithTx(tx *sql.Tx) *SQLiteAuditRepository {
+	return &SQLiteAuditRepository{tx: tx}
+}
+
+func (r *SQLiteAuditRepository) getExecutor() SQLExecutor {
+	if r.tx != nil {
+		return traced(r.tx)
+	}
+	return traced(r.db)
+}
+
+// occurred_at é gravado em nanossegundos Unix: o filtro por período compara
+// inteiros e o instante volta idêntico, como exige o hash.
+const auditColumns = `seq, occurred_at, actor_id, action, entity_type, entity_id, changes, request_id, ip, prev_hash, hash`
+
+func scanAuditEntry(row rowScanner) (*domain.Entry, error) {
+	var (
+		e          domain.Entry
+		occurredAt int64
+		changes    string
+	)
+	if err := row.Scan(&e.Seq, &occurredAt, &e.ActorID, &e.Action, &e.EntityType, &e.EntityID, &changes, &e.RequestID, &e.IP, &e.PrevHash, &e.Hash); err != nil {
+		return nil, err
+	}
+	e.OccurredAt = time.Unix(0, occurredAt).UTC()
+	e.Changes = []byte(changes)
+	return &e, nil
+}
+
+// Append lê a última entrada e grava a nova em seguida, na mesma transação.
+// prev_hash é único: duas transações que encadeassem na mesma entrada não
+// chegam a ser confirmadas juntas.
+func (r *SQLiteAuditRepository) Append(ctx context.Context, entry *domain.Entry) error {
+	exec := r.getExecutor()
+
+	var (
+		lastSeq  int64
+		lastHash string
+	)
+	err := exec.QueryRowContext(ctx, `SELECT seq, hash FROM audit_log ORDER BY seq DESC LIMIT 1`).Scan(&lastSeq, &lastHash)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	entry.Seq = lastSeq + 1
+	entry.PrevHash = lastHash
+	entry.Hash = entry.ComputeHash()
+
+	_, err = exec.ExecContext(ctx, `
+		INSERT INTO audit_log (`+auditColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		entry.Seq, entry.OccurredAt.UnixNano(), entry.ActorID, entry.Action, entry.EntityType, entry.EntityID,
+		string(entry.Changes), entry.RequestID, entry.IP, entry.PrevHash, entry.Hash)
+	return err
+}
+
+func (r *SQLiteAuditRepository) List(ctx context.Context, query domain.Query) ([]*domain.Entry, error) {
+	conditions := []string{"seq > ?"}
+	args := []any{query.AfterSeq}
+	if query.ActorID != "" {
+		conditions = append(conditions, "actor_id = ?")
+		args = append(args, query.ActorID)
+	}
+	if query.EntityType != "" {
+		conditions = append(conditions, "entity_type = ?")
+		args = append(args, query.EntityType)
+	}
+	if query.EntityID != "" {
+		conditions = append(conditions, "entity_id = ?")
+		args = append(args, query.EntityID)
+	}
+	if !query.From.IsZero() {
+		conditions = append(conditions, "occurred_at >= ?")
+		args = append(args, query.From.UnixNano())
+	}
+	if !query.To.IsZero() {
+		conditions = append(conditions, "occurred_at < ?")
+		args = append(args, query.To.UnixNano())
+	}
+
+	limit := ""
+	if query.Limit > 0 {
+		limit = " LIMIT ?"
+		args = append(args, query.Limit)
+	}
+
+	rows, err := r.getExecutor().QueryContext(ctx, `
+		SELECT `+auditColumns+`
+		FROM audit_log
+		WHERE `+strings.Join(conditions, " AND ")+`
+		ORDER BY seq`+limit, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*domain.Entry
+	for rows.Next() {
+		entry, err := scanAuditEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
@@ -0,0 +1,75 @@
+package sqlite
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	domainAudit "github.com/hoyci/todo-ddd/pkg/domain/audit"
+	usecaseaudit "github.com/hoyci/todo-ddd/pkg/usecase/audit"
+)
+
+func TestAuditRepository(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewSQLiteAuditRepository(db)
+	ctx := context.Background()
+
+	start := time.Date(2026, 3, 1, 12, 0, 0, 123456789, time.UTC)
+	for i, actor := range []string{"u1", "u2", "u1"} {
+		entry, err := domainAudit.NewEntry("task.updated", "task", "t1", nil, map[string]int{"n": i})
+		if err != nil {
+			t.Fatal(err)
+		}
+		entry.ActorID = actor
+		entry.OccurredAt = start.Add(time.Duration(i) * time.Hour)
+		if err := repo.Append(ctx, entry); err != nil {
+			t.Fatal(err)
+		}
+		if entry.Seq != int64(i+1) || entry.Hash == "" {
+			t.Fatalf("entry %d was not chained: %+v", i, entry)
+		}
+	}
+
+	// Os hashes gravados conferem com o conteúdo lido de volta, inclusive
+	// os nanossegundos de occurred_at.
+	out, err := (&usecaseaudit.VerifyChainUseCase{AuditRepo: repo, BatchSize: 2}).Execute(ctx)
+	if err != nil || out.Entries != 3 {
+		t.Fatalf("Verify = %+v, %v", out, err)
+	}
+
+	tests := []struct {
+		name  string
+		query domainAudit.Query
+		want  []int64
+	}{
+		{"all", domainAudit.Query{}, []int64{1, 2, 3}},
+		{"by actor", domainAudit.Query{ActorID: "u1"}, []int64{1, 3}},
+		{"from", domainAudit.Query{From: start.Add(time.Hour)}, []int64{2, 3}},
+		{"to is exclusive", domainAudit.Query{To: start.Add(time.Hour)}, []int64{1}},
+		{"after and limit", domainAudit.Query{AfterSeq: 1, Limit: 1}, []int64{2}},
+		{"other entity", domainAudit.Query{EntityID: "t2"}, nil},
+	}
+	for _, tt := range tests {
+		entries, err := repo.List(ctx, tt.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var seqs []int64
+		for _, entry := range entries {
+			seqs = append(seqs, entry.Seq)
+		}
+		if !slices.Equal(seqs, tt.want) {
+			t.Errorf("%s: seqs = %v, want %v", tt.name, seqs, tt.want)
+		}
+	}
+
+	for _, statement := range []string{
+		`UPDATE audit_log SET action = 'task.deleted' WHERE seq = 2`,
+		`DELETE FROM audit_log WHERE seq = 3`,
+	} {
+		if _, err := db.Exec(statement); err == nil {
+			t.Errorf("%q was accepted by the append-only log", statement)
+		}
+	}
+}
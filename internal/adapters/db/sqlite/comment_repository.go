@@ -0,0 +1,154 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	domain "github.com/hoyci/todo-ddd/pkg/domain/task"
+)
+
+// commentRevision é o formato de cada versão anterior na coluna history.
+type commentRevision struct {
+	Body     string    `json:"body"`
+	EditedAt time.Time `json:"edited_at"`
+}
+
+type SQLiteCommentRepository struct {
+	db *sql.DB
+	tx *sql.Tx
+}
+
+func NewSQLiteCommentRepository(db *sql.DB) *SQLiteCommentRepository {
+	return &SQLiteCommentRepository{db: db}
+}
+
+func (r *SQLiteCommentRepository) WithTx(tx *sql.Tx) *SQLiteCommentRepository {
+	return &SQLiteCommentRepository{tx: tx}
+}
+
+func (r *SQLiteCommentRepository) getExecutor() SQLExecutor {
+	if r.tx != nil {
+		return traced(r.tx)
+	}
+	return traced(r.db)
+}
+
+const commentColumns = `id, task_id, author_id, body, history, created_at, updated_at, deleted_at`
+
+// scanComment lê uma linha selecionada com commentColumns.
+func scanComment(row rowScanner) (*domain.Comment, error) {
+	var (
+		c       domain.Comment
+		history sql.NullString
+	)
+	if err := row.Scan(&c.ID, &c.TaskID, &c.AuthorID, &c.Body, &history, &c.CreatedAt, &c.UpdatedAt, &c.DeletedAt); err != nil {
+		return nil, err
+	}
+
+	if history.String != "" {
+		var revisions []commentRevision
+		if err := json.Unmarshal([]byte(history.String), &revisions); err != nil {
+			return nil, err
+		}
+		for _, revision := range revisions {
+			c.History = append(c.History, domain.CommentRevision{Body: revision.Body, EditedAt: revision.EditedAt})
+		}
+	}
+	return &c, nil
+}
+
+func encodeHistory(history []domain.CommentRevision) (string, error) {
+	if len(history) == 0 {
+		return "", nil
+	}
+	revisions := make([]commentRevision, len(history))
+	for i, revision := range history {
+		revisions[i] = commentRevision{Body: revision.Body, EditedAt: revision.EditedAt}
+	}
+	data, err := json.Marshal(revisions)
+	return string(data), err
+}
+
+func (r *SQLiteCommentRepository) Save(ctx context.Context, comment *domain.Comment) error {
+	history, err := encodeHistory(comment.History)
+	if err != nil {
+		return err
+	}
+	_, err = r.getExecutor().ExecContext(ctx, `
+		INSERT INTO task_comments (id, task_id, author_id, body, history, created_at, updated_at, deleted_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		comment.ID, comment.TaskID, comment.AuthorID, comment.Body, history, comment.CreatedAt, comment.UpdatedAt, comment.DeletedAt)
+	return err
+}
+
+func (r *SQLiteCommentRepository) Update(ctx context.Context, comment *domain.Comment) error {
+	history, err := encodeHistory(comment.History)
+	if err != nil {
+		return err
+	}
+	_, err = r.getExecutor().ExecContext(ctx, `
+		UPDATE task_comments
+		SET body = ?,
+			history = ?,
+			updated_at = ?,
+			deleted_at = ?
+		WHERE id = ?`,
+		comment.Body, history, comment.UpdatedAt, comment.DeletedAt, comment.ID)
+	return err
+}
+
+func (r *SQLiteCommentRepository) DeleteByTasks(ctx context.Context, taskIDs []string) error {
+	if len(taskIDs) == 0 {
+		return nil
+	}
+	args := make([]any, len(taskIDs))
+	for i, id := range taskIDs {
+		args[i] = id
+	}
+	_, err := r.getExecutor().ExecContext(ctx, `DELETE FROM task_comments WHERE task_id IN (`+placeholders(len(taskIDs))+`)`, args...)
+	return err
+}
+
+func (r *SQLiteCommentRepository) FindByID(ctx context.Context, id, taskID string) (*domain.Comment, error) {
+	row := r.getExecutor().QueryRowContext(ctx, `SELECT `+commentColumns+` FROM task_comments WHERE id = ? AND task_id = ?`, id, taskID)
+	return scanComment(row)
+}
+
+// ListPage pagina por (created_at, id), como SQLiteTaskRepository.ListPage.
+func (r *SQLiteCommentRepository) ListPage(ctx context.Context, query domain.CommentPageQuery) ([]*domain.Comment, error) {
+	sqlQuery := `
+		SELECT ` + commentColumns + `
+		FROM task_comments
+		WHERE task_id = ?`
+	args := []any{query.TaskID}
+	if !query.IncludeDeleted {
+		sqlQuery += ` AND deleted_at IS NULL`
+	}
+	if query.AfterID != "" {
+		sqlQuery += ` AND (created_at, id) > (SELECT created_at, id FROM task_comments WHERE id = ?)`
+		args = append(args, query.AfterID)
+	}
+	sqlQuery += ` ORDER BY created_at, id`
+	if query.Limit > 0 {
+		sqlQuery += ` LIMIT ?`
+		args = append(args, query.Limit)
+	}
+
+	rows, err := r.getExecutor().QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var comments []*domain.Comment
+	for rows.Next() {
+		c, err := scanComment(rows)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, c)
+	}
+	return comments, rows.Err()
+}
@@ -1,20 +1,26 @@
 package sqlite
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"strings"
+	"time"
 
+	domainNotification "github.com/hoyci/todo-ddd/pkg/domain/notification"
 	_ "modernc.org/sqlite"
 )
 
 type SQLExecutor interface {
-	Exec(query string, args ...interface{}) (sql.Result, error)
-	QueryRow(query string, args ...interface{}) *sql.Row
-	Query(query string, args ...interface{}) (*sql.Rows, error)
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
 }
 
 func InitDB() (*sql.DB, error) {
-	db, err := sql.Open("sqlite", "./data/app.db")
+	// Leituras dos streams de eventos concorrem com transações de escrita;
+	// busy_timeout faz a conexão aguardar o lock em vez de falhar.
+	db, err := sql.Open("sqlite", "./data/app.db?_pragma=busy_timeout(5000)")
 	if err != nil {
 		return nil, fmt.Errorf("failed to open db: %w", err)
 	}
@@ -45,17 +51,378 @@ func initSchema(db *sql.DB) error {
 			description TEXT,
 			priority INTEGER,
 			status TEXT NOT NULL,
+			user_id TEXT,
 			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 			updated_at TIMESTAMP,
 			deleted_at TIMESTAMP
 		);
 		`,
+		`
+		CREATE TABLE IF NOT EXISTS rate_limit_buckets (
+			key TEXT PRIMARY KEY,
+			tokens REAL NOT NULL,
+			allowed INTEGER NOT NULL,
+			updated_at REAL NOT NULL
+		);
+		`,
+		`
+		CREATE TABLE IF NOT EXISTS idempotency_keys (
+			scope TEXT NOT NULL,
+			key TEXT NOT NULL,
+			fingerprint TEXT NOT NULL,
+			completed INTEGER NOT NULL DEFAULT 0,
+			status_code INTEGER,
+			content_type TEXT,
+			response_body BLOB,
+			created_at REAL NOT NULL,
+			expires_at REAL NOT NULL,
+			PRIMARY KEY (scope, key)
+		);
+		`,
+		`
+		CREATE TABLE IF NOT EXISTS task_events (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			user_id TEXT NOT NULL,
+			task_id TEXT NOT NULL,
+			type TEXT NOT NULL,
+			payload TEXT NOT NULL,
+			occurred_at TIMESTAMP NOT NULL
+		);
+		`,
+		`
+		CREATE TABLE IF NOT EXISTS task_comments (
+			id TEXT PRIMARY KEY,
+			task_id TEXT NOT NULL,
+			author_id TEXT NOT NULL,
+			body TEXT NOT NULL,
+			history TEXT,
+			created_at TIMESTAMP NOT NULL,
+			updated_at TIMESTAMP,
+			deleted_at TIMESTAMP
+		);
+		`,
+		`
+		CREATE TABLE IF NOT EXISTS task_attachments (
+			id TEXT PRIMARY KEY,
+			task_id TEXT NOT NULL,
+			uploader_id TEXT NOT NULL,
+			file_name TEXT NOT NULL,
+			content_type TEXT NOT NULL,
+			size INTEGER NOT NULL,
+			hash TEXT NOT NULL,
+			created_at TIMESTAMP NOT NULL
+		);
+		`,
+		`
+		CREATE TABLE IF NOT EXISTS task_shares (
+			task_id TEXT NOT NULL,
+			user_id TEXT NOT NULL,
+			permission TEXT NOT NULL,
+			granted_by TEXT NOT NULL,
+			created_at TIMESTAMP NOT NULL,
+			PRIMARY KEY (task_id, user_id)
+		);
+		`,
+		`
+		CREATE TABLE IF NOT EXISTS task_watchers (
+			task_id TEXT NOT NULL,
+			user_id TEXT NOT NULL,
+			created_at TIMESTAMP NOT NULL,
+			PRIMARY KEY (task_id, user_id)
+		);
+		`,
+		`
+		CREATE TABLE IF NOT EXISTS workspaces (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			owner_id TEXT NOT NULL,
+			created_at TIMESTAMP NOT NULL,
+			updated_at TIMESTAMP,
+			deleted_at TIMESTAMP
+		);
+		`,
+		`
+		CREATE TABLE IF NOT EXISTS workspace_members (
+			workspace_id TEXT NOT NULL,
+			user_id TEXT NOT NULL,
+			role TEXT NOT NULL,
+			joined_at TIMESTAMP NOT NULL,
+			PRIMARY KEY (workspace_id, user_id)
+		);
+		`,
+		`
+		CREATE TABLE IF NOT EXISTS workspace_invitations (
+			id TEXT PRIMARY KEY,
+			workspace_id TEXT NOT NULL,
+			email TEXT NOT NULL,
+			role TEXT NOT NULL,
+			token TEXT NOT NULL UNIQUE,
+			invited_by TEXT NOT NULL,
+			created_at TIMESTAMP NOT NULL,
+			expires_at TIMESTAMP NOT NULL,
+			accepted_at TIMESTAMP
+		);
+		`,
+		`
+		CREATE TABLE IF NOT EXISTS user_roles (
+			user_id TEXT NOT NULL,
+			role TEXT NOT NULL,
+			granted_at TIMESTAMP NOT NULL,
+			PRIMARY KEY (user_id, role)
+		);
+		`,
+		`
+		CREATE TABLE IF NOT EXISTS task_revisions (
+			task_id TEXT NOT NULL,
+			number INTEGER NOT NULL,
+			author_id TEXT NOT NULL,
+			title TEXT NOT NULL,
+			description TEXT NOT NULL,
+			priority INTEGER NOT NULL,
+			status TEXT NOT NULL,
+			assignee_id TEXT NOT NULL,
+			due_at TIMESTAMP,
+			tags TEXT NOT NULL,
+			recurrence TEXT NOT NULL,
+			reverted_from INTEGER NOT NULL DEFAULT 0,
+			created_at TIMESTAMP NOT NULL,
+			PRIMARY KEY (task_id, number)
+		);
+		`,
+		`
+		CREATE TABLE IF NOT EXISTS task_undos (
+			token TEXT PRIMARY KEY,
+			user_id TEXT NOT NULL,
+			action TEXT NOT NULL,
+			steps TEXT NOT NULL,
+			created_at REAL NOT NULL,
+			expires_at REAL NOT NULL,
+			used_at REAL
+		);
+		`,
+		`
+		CREATE TABLE IF NOT EXISTS task_reminders (
+			id TEXT PRIMARY KEY,
+			task_id TEXT NOT NULL,
+			workspace_id TEXT NOT NULL DEFAULT '',
+			user_id TEXT NOT NULL,
+			at TIMESTAMP,
+			before_due INTEGER NOT NULL DEFAULT 0,
+			fire_at REAL NOT NULL,
+			fired_at REAL,
+			created_at TIMESTAMP NOT NULL
+		);
+		`,
+		`
+		CREATE TABLE IF NOT EXISTS notifications (
+			id TEXT PRIMARY KEY,
+			user_id TEXT NOT NULL,
+			kind TEXT NOT NULL,
+			task_id TEXT NOT NULL DEFAULT '',
+			title TEXT NOT NULL,
+			body TEXT NOT NULL,
+			created_at REAL NOT NULL
+		);
+		`,
+		`
+		CREATE TABLE IF NOT EXISTS notification_preferences (
+			user_id TEXT PRIMARY KEY,
+			channels TEXT NOT NULL,
+			webhook_url TEXT NOT NULL DEFAULT '',
+			quiet_start INTEGER,
+			quiet_end INTEGER,
+			timezone TEXT NOT NULL DEFAULT '',
+			updated_at TIMESTAMP
+		);
+		`,
+		`
+		CREATE TABLE IF NOT EXISTS audit_log (
+			seq INTEGER PRIMARY KEY,
+			occurred_at INTEGER NOT NULL,
+			actor_id TEXT NOT NULL,
+			action TEXT NOT NULL,
+			entity_type TEXT NOT NULL,
+			entity_id TEXT NOT NULL,
+			changes TEXT NOT NULL,
+			request_id TEXT NOT NULL,
+			ip TEXT NOT NULL,
+			prev_hash TEXT NOT NULL UNIQUE,
+			hash TEXT NOT NULL UNIQUE
+		);
+		`,
+		// O log de auditoria só aceita inclusões.
+		`
+		CREATE TRIGGER IF NOT EXISTS audit_log_no_update
+		BEFORE UPDATE ON audit_log
+		BEGIN
+			SELECT RAISE(ABORT, 'audit log is append-only');
+		END;
+		`,
+		`
+		CREATE TRIGGER IF NOT EXISTS audit_log_no_delete
+		BEFORE DELETE ON audit_log
+		BEGIN
+			SELECT RAISE(ABORT, 'audit log is append-only');
+		END;
+		`,
 	}
 
+	// active_workspace_members ignora participações em workspaces
+	// excluídos; as consultas de tarefas e eventos checam membros por ela.
+	schemas = append(schemas, `
+		CREATE VIEW IF NOT EXISTS active_workspace_members AS
+		SELECT m.workspace_id, m.user_id, m.role, m.joined_at
+		FROM workspace_members m
+		JOIN workspaces w ON w.id = m.workspace_id
+		WHERE w.deleted_at IS NULL;
+		`)
+
 	for _, schema := range schemas {
 		if _, err := db.Exec(schema); err != nil {
 			return fmt.Errorf("create schema: %w", err)
 		}
 	}
+
+	// Usuários anteriores à verificação de email são considerados
+	// verificados; o backfill só roda na migração que cria a coluna.
+	hadVerification, err := hasColumn(db, "users", "verified_at")
+	if err != nil {
+		return fmt.Errorf("inspect users: %w", err)
+	}
+
+	// Colunas adicionadas depois da criação das tabelas; bancos existentes
+	// recebem a coluna via ALTER TABLE.
+	columns := []struct {
+		table      string
+		name       string
+		definition string
+	}{
+		{"tasks", "user_id", "TEXT"},
+		{"tasks", "due_at", "TIMESTAMP"},
+		{"tasks", "tags", "TEXT"},
+		{"tasks", "recurrence", "TEXT"},
+		{"tasks", "assignee_id", "TEXT"},
+		// Vazio é o espaço pessoal; tarefas anteriores aos workspaces ficam nele.
+		{"tasks", "workspace_id", "TEXT NOT NULL DEFAULT ''"},
+		{"tasks", "position", "INTEGER NOT NULL DEFAULT 0"},
+		{"task_events", "workspace_id", "TEXT NOT NULL DEFAULT ''"},
+		{"notifications", "read_at", "REAL"},
+		{"notifications", "expires_at", "REAL NOT NULL DEFAULT 0"},
+		{"users", "verified_at", "TIMESTAMP"},
+		{"users", "verification_sent_at", "TIMESTAMP"},
+		{"idempotency_keys", "response_headers", "TEXT"},
+	}
+
+	for _, column := range columns {
+		if err := addColumnIfMissing(db, column.table, column.name, column.definition); err != nil {
+			return fmt.Errorf("migrate %s.%s: %w", column.table, column.name, err)
+		}
+	}
+
+	if !hadVerification {
+		if _, err := db.Exec(`UPDATE users SET verified_at = created_at WHERE verified_at IS NULL`); err != nil {
+			return fmt.Errorf("backfill user verification: %w", err)
+		}
+	}
+
+	indexes := []string{
+		`CREATE INDEX IF NOT EXISTS idx_tasks_user_id ON tasks (user_id);`,
+		`CREATE INDEX IF NOT EXISTS idx_task_events_user_id ON task_events (user_id, id);`,
+		`CREATE INDEX IF NOT EXISTS idx_task_events_task_id ON task_events (task_id, id);`,
+		`CREATE INDEX IF NOT EXISTS idx_task_comments_task_id ON task_comments (task_id, created_at, id);`,
+		`CREATE INDEX IF NOT EXISTS idx_task_attachments_task_id ON task_attachments (task_id, created_at);`,
+		`CREATE INDEX IF NOT EXISTS idx_task_attachments_hash ON task_attachments (hash);`,
+		`CREATE INDEX IF NOT EXISTS idx_tasks_assignee_id ON tasks (assignee_id);`,
+		`CREATE INDEX IF NOT EXISTS idx_task_shares_user_id ON task_shares (user_id);`,
+		`CREATE INDEX IF NOT EXISTS idx_task_watchers_user_id ON task_watchers (user_id);`,
+		`CREATE INDEX IF NOT EXISTS idx_tasks_workspace_id ON tasks (workspace_id, user_id);`,
+		`CREATE INDEX IF NOT EXISTS idx_task_events_workspace_id ON task_events (workspace_id, id);`,
+		`CREATE INDEX IF NOT EXISTS idx_workspace_members_user_id ON workspace_members (user_id);`,
+		`CREATE INDEX IF NOT EXISTS idx_workspace_invitations_workspace_id ON workspace_invitations (workspace_id, created_at);`,
+		`CREATE INDEX IF NOT EXISTS idx_task_undos_expires_at ON task_undos (expires_at);`,
+		`CREATE INDEX IF NOT EXISTS idx_task_reminders_task_id ON task_reminders (task_id, fire_at);`,
+		`CREATE INDEX IF NOT EXISTS idx_task_reminders_pending ON task_reminders (fire_at) WHERE fired_at IS NULL;`,
+		`CREATE INDEX IF NOT EXISTS idx_notifications_user_id ON notifications (user_id, created_at);`,
+		`CREATE INDEX IF NOT EXISTS idx_notifications_unread ON notifications (user_id) WHERE read_at IS NULL;`,
+		`CREATE INDEX IF NOT EXISTS idx_notifications_expires_at ON notifications (expires_at);`,
+		`CREATE INDEX IF NOT EXISTS idx_audit_log_actor_id ON audit_log (actor_id, seq);`,
+		`CREATE INDEX IF NOT EXISTS idx_audit_log_entity ON audit_log (entity_type, entity_id, seq);`,
+		`CREATE INDEX IF NOT EXISTS idx_audit_log_occurred_at ON audit_log (occurred_at);`,
+	}
+
+	for _, index := range indexes {
+		if _, err := db.Exec(index); err != nil {
+			return fmt.Errorf("create index: %w", err)
+		}
+	}
+
+	// Tarefas anteriores ao histórico de revisões ganham o estado atual como
+	// revisão 1, para que a primeira edição não perca o texto original.
+	if _, err := db.Exec(`
+		INSERT INTO task_revisions (task_id, number, author_id, title, description, priority, status, assignee_id, due_at, tags, recurrence, created_at)
+		SELECT t.id, 1, COALESCE(t.user_id, ''), t.title, COALESCE(t.description, ''), COALESCE(t.priority, 0), t.status, COALESCE(t.assignee_id, ''),
+			t.due_at, COALESCE(t.tags, ''), COALESCE(t.recurrence, ''), COALESCE(t.updated_at, t.created_at)
+		FROM tasks t
+		WHERE NOT EXISTS (SELECT 1 FROM task_revisions r WHERE r.task_id = t.id)`); err != nil {
+		return fmt.Errorf("backfill task revisions: %w", err)
+	}
+
+	// Notificações anteriores à expiração ganham o prazo padrão a partir da
+	// criação.
+	if _, err := db.Exec(`UPDATE notifications SET expires_at = created_at + ? WHERE expires_at = 0`,
+		domainNotification.Retention.Seconds()); err != nil {
+		return fmt.Errorf("backfill notification expiry: %w", err)
+	}
 	return nil
 }
+
+func addColumnIfMissing(db *sql.DB, table, column, definition string) error {
+	exists, err := hasColumn(db, table, column)
+	if err != nil || exists {
+		return err
+	}
+	_, err = db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition))
+	return err
+}
+
+func hasColumn(db *sql.DB, table, column string) (bool, error) {
+	rows, err := db.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			cid       int
+			name      string
+			colType   string
+			notNull   int
+			dfltValue sql.NullString
+			pk        int
+		)
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &dfltValue, &pk); err != nil {
+			return false, err
+		}
+		if name == column {
+			return true, nil
+		}
+	}
+	return false, rows.Err()
+}
+
+// placeholders devolve "?, ?, ..." com n parâmetros, para cláusulas IN.
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+// unixSeconds converte instantes para REAL, permitindo comparações e
+// aritmética de tempo direto no SQL.
+func unixSeconds(t time.Time) float64 {
+	return float64(t.UnixNano()) / float64(time.Second)
+}
+
+// fromUnixSeconds é o inverso de unixSeconds, com precisão de microssegundos.
+func fromUnixSeconds(seconds float64) time.Time {
+	return time.UnixMicro(int64(seconds * 1e6)).UTC()
+}
@@ -0,0 +1,123 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/hoyci/todo-ddd/internal/adapters/idempotency"
+	"github.com/hoyci/todo-ddd/pkg/logging"
+)
+
+type SQLiteIdempotencyStore struct {
+	db  *sql.DB
+	ttl time.Duration
+	// inFlightTimeout libera chaves presas em processamento, por exemplo
+	// quando o processo caiu antes de registrar a resposta. Deve ser maior
+	// que o tempo máximo de uma requisição, senão uma repetição executa de
+	// novo uma requisição que ainda está em andamento.
+	inFlightTimeout time.Duration
+	mu              sync.Mutex
+	lastPrune       time.Time
+}
+
+func NewSQLiteIdempotencyStore(db *sql.DB, ttl, inFlightTimeout time.Duration) *SQLiteIdempotencyStore {
+	return &SQLiteIdempotencyStore{db: db, ttl: ttl, inFlightTimeout: inFlightTimeout}
+}
+
+func (s *SQLiteIdempotencyStore) Reserve(ctx context.Context, scope, key, fingerprint string, now time.Time) (*idempotency.Record, bool, error) {
+	s.pruneExpired(ctx, now)
+
+	exec := traced(s.db)
+
+	// Chaves expiradas ou abandonadas em processamento podem ser reutilizadas.
+	_, err := exec.ExecContext(ctx, `
+		DELETE FROM idempotency_keys
+		WHERE scope = ? AND key = ?
+			AND (expires_at < ? OR (completed = 0 AND created_at < ?))`,
+		scope, key, unixSeconds(now), unixSeconds(now.Add(-s.inFlightTimeout)))
+	if err != nil {
+		return nil, false, err
+	}
+
+	res, err := exec.ExecContext(ctx, `
+		INSERT INTO idempotency_keys (scope, key, fingerprint, completed, created_at, expires_at)
+		VALUES (?, ?, ?, 0, ?, ?)
+		ON CONFLICT(scope, key) DO NOTHING`,
+		scope, key, fingerprint, unixSeconds(now), unixSeconds(now.Add(s.ttl)))
+	if err != nil {
+		return nil, false, err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return nil, false, err
+	} else if n == 1 {
+		return nil, true, nil
+	}
+
+	record := &idempotency.Record{}
+	var statusCode sql.NullInt64
+	var contentType, headers sql.NullString
+	err = exec.QueryRowContext(ctx, `
+		SELECT fingerprint, completed, status_code, content_type, response_headers, response_body
+		FROM idempotency_keys WHERE scope = ? AND key = ?`, scope, key).
+		Scan(&record.Fingerprint, &record.Completed, &statusCode, &contentType, &headers, &record.Response.Body)
+	if errors.Is(err, sql.ErrNoRows) {
+		// A chave expirou entre o INSERT e o SELECT; o cliente pode tentar de novo.
+		return nil, false, idempotency.ErrKeyNotFound
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	record.Response.StatusCode = int(statusCode.Int64)
+	record.Response.ContentType = contentType.String
+	if headers.Valid {
+		if err := json.Unmarshal([]byte(headers.String), &record.Response.Headers); err != nil {
+			return nil, false, err
+		}
+	}
+
+	return record, false, nil
+}
+
+func (s *SQLiteIdempotencyStore) Complete(ctx context.Context, scope, key string, response idempotency.Response, now time.Time) error {
+	var headers sql.NullString
+	if len(response.Headers) > 0 {
+		raw, err := json.Marshal(response.Headers)
+		if err != nil {
+			return err
+		}
+		headers = sql.NullString{String: string(raw), Valid: true}
+	}
+
+	_, err := traced(s.db).ExecContext(ctx, `
+		UPDATE idempotency_keys
+		SET completed = 1, status_code = ?, content_type = ?, response_headers = ?, response_body = ?, expires_at = ?
+		WHERE scope = ? AND key = ?`,
+		response.StatusCode, response.ContentType, headers, response.Body, unixSeconds(now.Add(s.ttl)), scope, key)
+	return err
+}
+
+func (s *SQLiteIdempotencyStore) Release(ctx context.Context, scope, key string) error {
+	_, err := traced(s.db).ExecContext(ctx, `
+		DELETE FROM idempotency_keys WHERE scope = ? AND key = ? AND completed = 0`,
+		scope, key)
+	return err
+}
+
+func (s *SQLiteIdempotencyStore) pruneExpired(ctx context.Context, now time.Time) {
+	s.mu.Lock()
+	if now.Sub(s.lastPrune) < time.Minute {
+		s.mu.Unlock()
+		return
+	}
+	s.lastPrune = now
+	s.mu.Unlock()
+
+	_, err := traced(s.db).ExecContext(ctx, `DELETE FROM idempotency_keys WHERE expires_at < ?`, unixSeconds(now))
+	if err != nil {
+		logging.ForPackage(ctx, "sqlite").Warn("error pruning idempotency keys", "error", err)
+	}
+}
@@ -0,0 +1,63 @@
+package sqlite
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hoyci/todo-ddd/internal/adapters/idempotency"
+)
+
+func TestIdempotencyStore(t *testing.T) {
+	db := openTestDB(t)
+	store := NewSQLiteIdempotencyStore(db, time.Hour, 2*time.Minute)
+	ctx := context.Background()
+	now := time.Now()
+
+	if _, reserved, err := store.Reserve(ctx, "scope", "k1", "fp", now); err != nil || !reserved {
+		t.Fatalf("first Reserve = %v, %v", reserved, err)
+	}
+
+	// Ainda em processamento dentro do prazo: a chave continua presa.
+	existing, reserved, err := store.Reserve(ctx, "scope", "k1", "fp", now.Add(time.Minute))
+	if err != nil || reserved || existing.Completed {
+		t.Fatalf("Reserve in flight = %+v, %v, %v", existing, reserved, err)
+	}
+
+	response := idempotency.Response{
+		StatusCode:  http.StatusCreated,
+		ContentType: "application/json",
+		Headers:     http.Header{"Location": {"/api/v1/tasks/t1"}, "X-Undo-Token": {"undo-1"}},
+		Body:        []byte(`{"id":"t1"}`),
+	}
+	if err := store.Complete(ctx, "scope", "k1", response, now); err != nil {
+		t.Fatal(err)
+	}
+	existing, reserved, err = store.Reserve(ctx, "scope", "k1", "fp", now.Add(5*time.Minute))
+	if err != nil || reserved || !existing.Completed {
+		t.Fatalf("Reserve after Complete = %+v, %v, %v", existing, reserved, err)
+	}
+	got := existing.Response
+	if got.StatusCode != response.StatusCode || string(got.Body) != string(response.Body) ||
+		got.Headers.Get("Location") != "/api/v1/tasks/t1" || got.Headers.Get("X-Undo-Token") != "undo-1" {
+		t.Errorf("stored response = %+v", got)
+	}
+
+	// Uma chave abandonada em processamento é liberada depois do prazo.
+	if _, reserved, err := store.Reserve(ctx, "scope", "k2", "fp", now); err != nil || !reserved {
+		t.Fatalf("Reserve k2 = %v, %v", reserved, err)
+	}
+	tests := []struct {
+		after time.Duration
+		want  bool
+	}{
+		{time.Minute, false},
+		{2*time.Minute + time.Second, true},
+	}
+	for _, tt := range tests {
+		if _, reserved, err := store.Reserve(ctx, "scope", "k2", "fp", now.Add(tt.after)); err != nil || reserved != tt.want {
+			t.Errorf("Reserve k2 after %s = %v, %v; want %v", tt.after, reserved, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	domain "github.com/hoyci/todo-ddd/pkg/domain/workspace"
+)
+
+type SQLiteInvitationRepository struct {
+	db *sql.DB
+	tx *sql.Tx
+}
+
+func NewSQLiteInvitationRepository(db *sql.DB) *SQLiteInvitationRepository {
+	return &SQLiteInvitationRepository{db: db}
+}
+
+func (r *SQLiteInvitationRepository) WithTx(tx *sql.Tx) *SQLiteInvitationRepository {
+	return &SQLiteInvitationRepository{tx: tx}
+}
+
+func (r *SQLiteInvitationRepository) getExecutor() SQLExecutor {
+	if r.tx != nil {
+		return traced(r.tx)
+	}
+	return traced(r.db)
+}
+
+const invitationColumns = `id, workspace_id, email, role, token, invited_by, created_at, expires_at, accepted_at`
+
+func scanInvitation(row rowScanner) (*domain.Invitation, error) {
+	var i domain.Invitation
+	if err := row.Scan(&i.ID, &i.WorkspaceID, &i.Email, &i.Role, &i.Token, &i.InvitedBy, &i.CreatedAt, &i.ExpiresAt, &i.AcceptedAt); err != nil {
+		return nil, err
+	}
+	return &i, nil
+}
+
+func (r *SQLiteInvitationRepository) Save(ctx context.Context, invitation *domain.Invitation) error {
+	_, err := r.getExecutor().ExecContext(ctx, `
+		INSERT INTO workspace_invitations (`+invitationColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		invitation.ID, invitation.WorkspaceID, invitation.Email, invitation.Role, invitation.Token, invitation.InvitedBy,
+		invitation.CreatedAt, invitation.ExpiresAt, invitation.AcceptedAt)
+	return err
+}
+
+func (r *SQLiteInvitationRepository) Update(ctx context.Context, invitation *domain.Invitation) error {
+	_, err := r.getExecutor().ExecContext(ctx, `
+		UPDATE workspace_invitations SET accepted_at = ? WHERE id = ?`,
+		invitation.AcceptedAt, invitation.ID)
+	return err
+}
+
+func (r *SQLiteInvitationRepository) FindByToken(ctx context.Context, token string) (*domain.Invitation, error) {
+	row := r.getExecutor().QueryRowContext(ctx, `
+		SELECT `+invitationColumns+` FROM workspace_invitations WHERE token = ?`, token)
+	return scanInvitation(row)
+}
+
+func (r *SQLiteInvitationRepository) FindByID(ctx context.Context, workspaceID, id string) (*domain.Invitation, error) {
+	row := r.getExecutor().QueryRowContext(ctx, `
+		SELECT `+invitationColumns+` FROM workspace_invitations WHERE workspace_id = ? AND id = ?`, workspaceID, id)
+	return scanInvitation(row)
+}
+
+// ListPending filtra a validade em Go: o driver grava instantes como texto,
+// que não se compara de forma confiável com parâmetros de tempo.
+func (r *SQLiteInvitationRepository) ListPending(ctx context.Context, workspaceID string) ([]*domain.Invitation, error) {
+	rows, err := r.getExecutor().QueryContext(ctx, `
+		SELECT `+invitationColumns+`
+		FROM workspace_invitations
+		WHERE workspace_id = ? AND accepted_at IS NULL
+		ORDER BY created_at, id`, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	now := time.Now()
+	var invitations []*domain.Invitation
+	for rows.Next() {
+		i, err := scanInvitation(rows)
+		if err != nil {
+			return nil, err
+		}
+		if i.Pending(now) {
+			invitations = append(invitations, i)
+		}
+	}
+	return invitations, rows.Err()
+}
+
+func (r *SQLiteInvitationRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.getExecutor().ExecContext(ctx, `DELETE FROM workspace_invitations WHERE id = ?`, id)
+	return err
+}
@@ -0,0 +1,85 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	domain "github.com/hoyci/todo-ddd/pkg/domain/notification"
+	"github.com/hoyci/todo-ddd/pkg/domain/valueobject"
+)
+
+type SQLiteNotificationPreferenceRepository struct {
+	db *sql.DB
+	tx *sql.Tx
+}
+
+func NewSQLiteNotificationPreferenceRepository(db *sql.DB) *SQLiteNotificationPreferenceRepository {
+	return &SQLiteNotificationPreferenceRepository{db: db}
+}
+
+func (r *SQLiteNotificationPreferenceRepository) WithTx(tx *sql.Tx) *SQLiteNotificationPreferenceRepository {
+	return &SQLiteNotificationPreferenceRepository{tx: tx}
+}
+
+func (r *SQLiteNotificationPreferenceRepository) getExecutor() SQLExecutor {
+	if r.tx != nil {
+		return traced(r.tx)
+	}
+	return traced(r.db)
+}
+
+// O horário de silêncio é gravado em minutos desde a meia-noite; quiet_start
+// NULL significa que não há.
+func (r *SQLiteNotificationPreferenceRepository) Find(ctx context.Context, userID string) (*domain.Preferences, error) {
+	var (
+		prefs                domain.Preferences
+		channels             string
+		quietStart, quietEnd sql.NullInt64
+		timezone             string
+	)
+	err := r.getExecutor().QueryRowContext(ctx, `
+		SELECT user_id, channels, webhook_url, quiet_start, quiet_end, timezone, updated_at
+		FROM notification_preferences
+		WHERE user_id = ?`, userID).
+		Scan(&prefs.UserID, &channels, &prefs.WebhookURL, &quietStart, &quietEnd, &timezone, &prefs.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if channels != "" {
+		for _, channel := range strings.Split(channels, ",") {
+			prefs.Channels = append(prefs.Channels, valueobject.NotificationChannel(channel))
+		}
+	}
+	if quietStart.Valid && quietEnd.Valid {
+		if prefs.QuietHours, err = valueobject.NewQuietHours(int(quietStart.Int64), int(quietEnd.Int64), timezone); err != nil {
+			return nil, err
+		}
+	}
+	return &prefs, nil
+}
+
+func (r *SQLiteNotificationPreferenceRepository) Save(ctx context.Context, prefs *domain.Preferences) error {
+	channels := make([]string, len(prefs.Channels))
+	for i, channel := range prefs.Channels {
+		channels[i] = string(channel)
+	}
+	var quietStart, quietEnd any
+	if !prefs.QuietHours.IsZero() {
+		quietStart, quietEnd = prefs.QuietHours.Start, prefs.QuietHours.End
+	}
+
+	_, err := r.getExecutor().ExecContext(ctx, `
+		INSERT INTO notification_preferences (user_id, channels, webhook_url, quiet_start, quiet_end, timezone, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (user_id) DO UPDATE SET
+			channels = excluded.channels,
+			webhook_url = excluded.webhook_url,
+			quiet_start = excluded.quiet_start,
+			quiet_end = excluded.quiet_end,
+			timezone = excluded.timezone,
+			updated_at = excluded.updated_at`,
+		prefs.UserID, strings.Join(channels, ","), prefs.WebhookURL, quietStart, quietEnd, prefs.QuietHours.Timezone, prefs.UpdatedAt)
+	return err
+}
@@ -0,0 +1,162 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	domain "github.com/hoyci/todo-ddd/pkg/domain/notification"
+)
+
+type SQLiteNotificationRepository struct {
+	db *sql.DB
+	tx *sql.Tx
+}
+
+func NewSQLiteNotificationRepository(db *sql.DB) *SQLiteNotificationRepository {
+	return &SQLiteNotificationRepository{db: db}
+}
+
+func (r *SQLiteNotificationRepository) WithTx(tx *sql.Tx) *SQLiteNotificationRepository {
+	return &SQLiteNotificationRepository{tx: tx}
+}
+
+func (r *SQLiteNotificationRepository) getExecutor() SQLExecutor {
+	if r.tx != nil {
+		return traced(r.tx)
+	}
+	return traced(r.db)
+}
+
+// created_at, read_at e expires_at são REAL (ver unixSeconds), para que a
+// paginação e a expiração comparem números.
+const notificationColumns = `id, user_id, kind, task_id, title, body, created_at, read_at, expires_at`
+
+func scanNotification(row rowScanner) (*domain.Notification, error) {
+	var (
+		n         domain.Notification
+		createdAt float64
+		readAt    sql.NullFloat64
+		expiresAt float64
+	)
+	if err := row.Scan(&n.ID, &n.UserID, &n.Kind, &n.TaskID, &n.Title, &n.Body, &createdAt, &readAt, &expiresAt); err != nil {
+		return nil, err
+	}
+
+	n.CreatedAt = fromUnixSeconds(createdAt)
+	n.ExpiresAt = fromUnixSeconds(expiresAt)
+	if readAt.Valid {
+		t := fromUnixSeconds(readAt.Float64)
+		n.ReadAt = &t
+	}
+	return &n, nil
+}
+
+func (r *SQLiteNotificationRepository) Save(ctx context.Context, notification *domain.Notification) error {
+	var readAt any
+	if notification.ReadAt != nil {
+		readAt = unixSeconds(*notification.ReadAt)
+	}
+	if _, err := r.getExecutor().ExecContext(ctx, `DELETE FROM notifications WHERE expires_at <= ?`, unixSeconds(time.Now())); err != nil {
+		return err
+	}
+	_, err := r.getExecutor().ExecContext(ctx, `
+		INSERT INTO notifications (`+notificationColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		notification.ID, notification.UserID, notification.Kind, notification.TaskID, notification.Title, notification.Body,
+		unixSeconds(notification.CreatedAt), readAt, unixSeconds(notification.ExpiresAt))
+	return err
+}
+
+func (r *SQLiteNotificationRepository) Find(ctx context.Context, userID, id string) (*domain.Notification, error) {
+	row := r.getExecutor().QueryRowContext(ctx, `
+		SELECT `+notificationColumns+`
+		FROM notifications
+		WHERE id = ? AND user_id = ? AND expires_at > ?`,
+		id, userID, unixSeconds(time.Now()))
+	return scanNotification(row)
+}
+
+// List pagina por (created_at, id) em ordem decrescente: a mais recente
+// primeiro.
+func (r *SQLiteNotificationRepository) List(ctx context.Context, query domain.InboxQuery) ([]*domain.Notification, error) {
+	sqlQuery := `
+		SELECT ` + notificationColumns + `
+		FROM notifications
+		WHERE user_id = ? AND expires_at > ?`
+	args := []any{query.UserID, unixSeconds(time.Now())}
+	if query.UnreadOnly {
+		sqlQuery += ` AND read_at IS NULL`
+	}
+	if query.AfterID != "" {
+		sqlQuery += ` AND (created_at, id) < (SELECT created_at, id FROM notifications WHERE id = ?)`
+		args = append(args, query.AfterID)
+	}
+	sqlQuery += ` ORDER BY created_at DESC, id DESC`
+	if query.Limit > 0 {
+		sqlQuery += ` LIMIT ?`
+		args = append(args, query.Limit)
+	}
+
+	rows, err := r.getExecutor().QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var notifications []*domain.Notification
+	for rows.Next() {
+		n, err := scanNotification(rows)
+		if err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, n)
+	}
+	return notifications, rows.Err()
+}
+
+func (r *SQLiteNotificationRepository) CountUnread(ctx context.Context, userID string) (int, error) {
+	var count int
+	err := r.getExecutor().QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM notifications
+		WHERE user_id = ? AND read_at IS NULL AND expires_at > ?`,
+		userID, unixSeconds(time.Now())).Scan(&count)
+	return count, err
+}
+
+func (r *SQLiteNotificationRepository) SetRead(ctx context.Context, userID string, ids []string, at *time.Time) (int, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	query := `UPDATE notifications SET read_at = ? WHERE user_id = ? AND expires_at > ?`
+	args := []any{nil, userID, unixSeconds(time.Now())}
+	if at != nil {
+		args[0] = unixSeconds(*at)
+		query += ` AND read_at IS NULL`
+	} else {
+		query += ` AND read_at IS NOT NULL`
+	}
+	query += ` AND id IN (` + placeholders(len(ids)) + `)`
+	for _, id := range ids {
+		args = append(args, id)
+	}
+
+	result, err := r.getExecutor().ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	affected, err := result.RowsAffected()
+	return int(affected), err
+}
+
+func (r *SQLiteNotificationRepository) MarkAllRead(ctx context.Context, userID string, at time.Time) (int, error) {
+	result, err := r.getExecutor().ExecContext(ctx, `
+		UPDATE notifications SET read_at = ?
+		WHERE user_id = ? AND read_at IS NULL AND expires_at > ?`,
+		unixSeconds(at), userID, unixSeconds(time.Now()))
+	if err != nil {
+		return 0, err
+	}
+	affected, err := result.RowsAffected()
+	return int(affected), err
+}
@@ -0,0 +1,91 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	domainNotification "github.com/hoyci/todo-ddd/pkg/domain/notification"
+)
+
+func TestInboxExpiry(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewSQLiteNotificationRepository(db)
+	ctx := context.Background()
+	now := time.Now()
+
+	save := func(userID, title string, createdAt, expiresAt time.Time) string {
+		t.Helper()
+		n := domainNotification.NewNotification(userID, domainNotification.KindComment, "t1", title, "")
+		n.CreatedAt, n.ExpiresAt = createdAt, expiresAt
+		if err := repo.Save(ctx, n); err != nil {
+			t.Fatal(err)
+		}
+		return n.ID
+	}
+	var live []string
+	for i := range 3 {
+		live = append(live, save("u1", "live", now.Add(time.Duration(i-10)*time.Minute), now.Add(time.Hour)))
+	}
+	save("u2", "other user", now, now.Add(time.Hour))
+	// Gravada por último: Save só apaga as expiradas que já estavam lá.
+	expired := save("u1", "expired", now.Add(-time.Minute), now.Add(-time.Second))
+
+	if _, err := repo.Find(ctx, "u1", expired); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Find expired: error = %v, want %v", err, sql.ErrNoRows)
+	}
+	if _, err := repo.Find(ctx, "u2", live[0]); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Find another user's notification: error = %v, want %v", err, sql.ErrNoRows)
+	}
+
+	list, err := repo.List(ctx, domainNotification.InboxQuery{UserID: "u1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, n := range list {
+		ids = append(ids, n.ID)
+	}
+	if want := []string{live[2], live[1], live[0]}; !slices.Equal(ids, want) {
+		t.Errorf("List = %v, want the live notifications newest first %v", ids, want)
+	}
+
+	if count, err := repo.CountUnread(ctx, "u1"); err != nil || count != 3 {
+		t.Errorf("CountUnread = %d, %v; want 3", count, err)
+	}
+	tests := []struct {
+		name   string
+		userID string
+		ids    []string
+		at     *time.Time
+		want   int
+	}{
+		{"expired is not marked", "u1", []string{expired}, &now, 0},
+		{"another user's is not marked", "u2", []string{live[0]}, &now, 0},
+		{"mark read", "u1", []string{live[0], expired}, &now, 1},
+		{"already read", "u1", []string{live[0]}, &now, 0},
+		{"mark unread", "u1", []string{live[0]}, nil, 1},
+		{"already unread", "u1", []string{live[0]}, nil, 0},
+		{"no ids", "u1", nil, &now, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := repo.SetRead(ctx, tt.userID, tt.ids, tt.at); err != nil || got != tt.want {
+				t.Errorf("SetRead = %d, %v; want %d", got, err, tt.want)
+			}
+		})
+	}
+	if got, err := repo.MarkAllRead(ctx, "u1", now); err != nil || got != 3 {
+		t.Errorf("MarkAllRead = %d, %v; want the 3 live notifications", got, err)
+	}
+
+	// A próxima gravação apaga a expirada de vez.
+	save("u1", "new", now, now.Add(time.Hour))
+	var rows int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM notifications WHERE id = ?`, expired).Scan(&rows); err != nil || rows != 0 {
+		t.Errorf("expired rows after Save = %d, %v; want 0", rows, err)
+	}
+}
@@ -0,0 +1,70 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/hoyci/todo-ddd/internal/adapters/ratelimit"
+	"github.com/hoyci/todo-ddd/pkg/logging"
+)
+
+// SQLiteRateLimitStore compartilha os buckets entre processos que usam o
+// mesmo arquivo SQLite. Reposição e consumo acontecem em um único UPSERT,
+// que o SQLite serializa, dispensando locks na aplicação.
+type SQLiteRateLimitStore struct {
+	db        *sql.DB
+	mu        sync.Mutex
+	lastPrune time.Time
+}
+
+const rateLimitRetention = 24 * time.Hour
+
+func NewSQLiteRateLimitStore(db *sql.DB) *SQLiteRateLimitStore {
+	return &SQLiteRateLimitStore{db: db}
+}
+
+func (s *SQLiteRateLimitStore) Take(ctx context.Context, key string, policy ratelimit.Policy, now time.Time) (ratelimit.Result, error) {
+	s.pruneIdle(ctx, now)
+
+	// ?1 = key, ?2 = limite, ?3 = agora (segundos), ?4 = tokens por segundo.
+	// As expressões do SET enxergam os valores antigos da linha.
+	row := traced(s.db).QueryRowContext(ctx, `
+		INSERT INTO rate_limit_buckets (key, tokens, allowed, updated_at)
+		VALUES (?1, ?2 - 1, 1, ?3)
+		ON CONFLICT(key) DO UPDATE SET
+			allowed = MIN(?2, tokens + MAX(0, ?3 - updated_at) * ?4) >= 1,
+			tokens = MIN(?2, tokens + MAX(0, ?3 - updated_at) * ?4)
+				- (MIN(?2, tokens + MAX(0, ?3 - updated_at) * ?4) >= 1),
+			updated_at = ?3
+		RETURNING tokens, allowed`,
+		key, policy.Limit, unixSeconds(now), policy.Rate())
+
+	var tokens float64
+	var allowed bool
+	if err := row.Scan(&tokens, &allowed); err != nil {
+		return ratelimit.Result{}, err
+	}
+
+	return ratelimit.NewResult(policy, tokens, allowed), nil
+}
+
+// pruneIdle remove, no máximo uma vez por minuto, buckets sem uso há mais de
+// rateLimitRetention. Depois desse tempo qualquer política já os reabasteceu.
+func (s *SQLiteRateLimitStore) pruneIdle(ctx context.Context, now time.Time) {
+	s.mu.Lock()
+	if now.Sub(s.lastPrune) < time.Minute {
+		s.mu.Unlock()
+		return
+	}
+	s.lastPrune = now
+	s.mu.Unlock()
+
+	_, err := traced(s.db).ExecContext(ctx, `
+		DELETE FROM rate_limit_buckets WHERE updated_at < ?`,
+		unixSeconds(now.Add(-rateLimitRetention)))
+	if err != nil {
+		logging.ForPackage(ctx, "sqlite").Warn("error pruning rate limit buckets", "error", err)
+	}
+}
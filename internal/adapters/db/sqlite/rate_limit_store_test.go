@@ -0,0 +1,86 @@
+package sqlite
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hoyci/todo-ddd/internal/adapters/ratelimit"
+)
+
+func TestRateLimitStore(t *testing.T) {
+	store := NewSQLiteRateLimitStore(openTestDB(t))
+	policy := ratelimit.Policy{Name: "test", Limit: 3, Window: 3 * time.Second}
+	start := time.Unix(1_700_000_000, 0)
+
+	// Os mesmos passos do MemoryStore: o UPSERT precisa repor e consumir
+	// como ele.
+	steps := []struct {
+		name       string
+		key        string
+		at         time.Duration
+		allowed    bool
+		remaining  int
+		retryAfter time.Duration
+	}{
+		{"burst 1", "a", 0, true, 2, 0},
+		{"burst 2", "a", 0, true, 1, 0},
+		{"burst 3", "a", 0, true, 0, 0},
+		{"burst exhausted", "a", 0, false, 0, time.Second},
+		{"other key has its own bucket", "b", 0, true, 2, 0},
+		{"half a token refilled", "a", 500 * time.Millisecond, false, 0, time.Second},
+		{"one token refilled", "a", time.Second, true, 0, 0},
+		{"clock going back", "a", 0, false, 0, time.Second},
+		{"refilled up to the limit only", "a", time.Hour, true, 2, 0},
+	}
+	for _, step := range steps {
+		got, err := store.Take(context.Background(), step.key, policy, start.Add(step.at))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Allowed != step.allowed || got.Remaining != step.remaining || got.RetryAfter != step.retryAfter {
+			t.Errorf("%s: Take = %+v, want allowed %v, remaining %d, retry after %s", step.name, got, step.allowed, step.remaining, step.retryAfter)
+		}
+	}
+
+	// Buckets parados além da retenção são apagados.
+	if _, err := store.Take(context.Background(), "c", policy, start.Add(48*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	var rows int
+	if err := store.db.QueryRow(`SELECT COUNT(*) FROM rate_limit_buckets`).Scan(&rows); err != nil || rows != 1 {
+		t.Errorf("buckets after pruning = %d, %v; want only the new one", rows, err)
+	}
+}
+
+func TestRateLimitStoreConcurrentTakes(t *testing.T) {
+	store := NewSQLiteRateLimitStore(openTestDB(t))
+	policy := ratelimit.Policy{Name: "test", Limit: 5, Window: time.Hour}
+	now := time.Now()
+
+	// Com o UPSERT atômico, nunca passam mais requisições que o limite.
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	allowed := 0
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result, err := store.Take(context.Background(), "shared", policy, now)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if result.Allowed {
+				mu.Lock()
+				allowed++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if allowed != policy.Limit {
+		t.Errorf("%d concurrent takes allowed, want %d", allowed, policy.Limit)
+	}
+}
@@ -0,0 +1,133 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	domain "github.com/hoyci/todo-ddd/pkg/domain/task"
+)
+
+type SQLiteReminderRepository struct {
+	db *sql.DB
+	tx *sql.Tx
+}
+
+func NewSQLiteReminderRepository(db *sql.DB) *SQLiteReminderRepository {
+	return &SQLiteReminderRepository{db: db}
+}
+
+func (r *SQLiteReminderRepository) WithTx(tx *sql.Tx) *SQLiteReminderRepository {
+	return &SQLiteReminderRepository{tx: tx}
+}
+
+func (r *SQLiteReminderRepository) getExecutor() SQLExecutor {
+	if r.tx != nil {
+		return traced(r.tx)
+	}
+	return traced(r.db)
+}
+
+// fire_at e fired_at são REAL (ver unixSeconds), para que o agendador
+// compare números; before_due fica em segundos.
+const reminderColumns = `id, task_id, workspace_id, user_id, at, before_due, fire_at, fired_at, created_at`
+
+func scanReminder(row rowScanner) (*domain.Reminder, error) {
+	var (
+		rem       domain.Reminder
+		beforeDue int64
+		fireAt    float64
+		firedAt   sql.NullFloat64
+	)
+	if err := row.Scan(&rem.ID, &rem.TaskID, &rem.WorkspaceID, &rem.UserID, &rem.At, &beforeDue, &fireAt, &firedAt, &rem.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	rem.BeforeDue = time.Duration(beforeDue) * time.Second
+	rem.FireAt = fromUnixSeconds(fireAt)
+	if firedAt.Valid {
+		t := fromUnixSeconds(firedAt.Float64)
+		rem.FiredAt = &t
+	}
+	return &rem, nil
+}
+
+func firedAtValue(reminder *domain.Reminder) any {
+	if reminder.FiredAt == nil {
+		return nil
+	}
+	return unixSeconds(*reminder.FiredAt)
+}
+
+func (r *SQLiteReminderRepository) Save(ctx context.Context, reminder *domain.Reminder) error {
+	_, err := r.getExecutor().ExecContext(ctx, `
+		INSERT INTO task_reminders (`+reminderColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		reminder.ID, reminder.TaskID, reminder.WorkspaceID, reminder.UserID, reminder.At, int64(reminder.BeforeDue/time.Second),
+		unixSeconds(reminder.FireAt), firedAtValue(reminder), reminder.CreatedAt)
+	return err
+}
+
+func (r *SQLiteReminderRepository) Update(ctx context.Context, reminder *domain.Reminder) error {
+	_, err := r.getExecutor().ExecContext(ctx, `
+		UPDATE task_reminders SET fire_at = ?, fired_at = ? WHERE id = ?`,
+		unixSeconds(reminder.FireAt), firedAtValue(reminder), reminder.ID)
+	return err
+}
+
+func (r *SQLiteReminderRepository) Find(ctx context.Context, taskID, id string) (*domain.Reminder, error) {
+	row := r.getExecutor().QueryRowContext(ctx, `SELECT `+reminderColumns+` FROM task_reminders WHERE task_id = ? AND id = ?`, taskID, id)
+	return scanReminder(row)
+}
+
+func (r *SQLiteReminderRepository) ListByTask(ctx context.Context, taskID string) ([]*domain.Reminder, error) {
+	return r.list(ctx, `
+		SELECT `+reminderColumns+`
+		FROM task_reminders
+		WHERE task_id = ?
+		ORDER BY fire_at, id`, taskID)
+}
+
+func (r *SQLiteReminderRepository) ListDue(ctx context.Context, before time.Time, limit int) ([]*domain.Reminder, error) {
+	return r.list(ctx, `
+		SELECT `+reminderColumns+`
+		FROM task_reminders
+		WHERE fired_at IS NULL AND fire_at <= ?
+		ORDER BY fire_at, id
+		LIMIT ?`, unixSeconds(before), limit)
+}
+
+func (r *SQLiteReminderRepository) list(ctx context.Context, query string, args ...any) ([]*domain.Reminder, error) {
+	rows, err := r.getExecutor().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reminders []*domain.Reminder
+	for rows.Next() {
+		rem, err := scanReminder(rows)
+		if err != nil {
+			return nil, err
+		}
+		reminders = append(reminders, rem)
+	}
+	return reminders, rows.Err()
+}
+
+func (r *SQLiteReminderRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.getExecutor().ExecContext(ctx, `DELETE FROM task_reminders WHERE id = ?`, id)
+	return err
+}
+
+func (r *SQLiteReminderRepository) DeleteByTasks(ctx context.Context, taskIDs []string) error {
+	if len(taskIDs) == 0 {
+		return nil
+	}
+	args := make([]any, len(taskIDs))
+	for i, id := range taskIDs {
+		args[i] = id
+	}
+	_, err := r.getExecutor().ExecContext(ctx, `DELETE FROM task_reminders WHERE task_id IN (`+placeholders(len(taskIDs))+`)`, args...)
+	return err
+}
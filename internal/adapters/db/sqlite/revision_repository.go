@@ -0,0 +1,115 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	domain "github.com/hoyci/todo-ddd/pkg/domain/task"
+	"github.com/hoyci/todo-ddd/pkg/domain/valueobject"
+)
+
+type SQLiteRevisionRepository struct {
+	db *sql.DB
+	tx *sql.Tx
+}
+
+func NewSQLiteRevisionRepository(db *sql.DB) *SQLiteRevisionRepository {
+	return &SQLiteRevisionRepository{db: db}
+}
+
+func (r *SQLiteRevisionRepository) WithTx(tx *sql.Tx) *SQLiteRevisionRepository {
+	return &SQLiteRevisionRepository{tx: tx}
+}
+
+func (r *SQLiteRevisionRepository) getExecutor() SQLExecutor {
+	if r.tx != nil {
+		return traced(r.tx)
+	}
+	return traced(r.db)
+}
+
+const revisionColumns = `task_id, number, author_id, title, description, priority, status, assignee_id, due_at, tags, recurrence, reverted_from, created_at`
+
+// scanRevision lê uma linha selecionada com revisionColumns.
+func scanRevision(row rowScanner) (*domain.Revision, error) {
+	var (
+		rev              domain.Revision
+		tags, recurrence string
+	)
+	if err := row.Scan(&rev.TaskID, &rev.Number, &rev.AuthorID, &rev.Title, &rev.Description, &rev.Priority, &rev.Status, &rev.AssigneeID,
+		&rev.DueAt, &tags, &recurrence, &rev.RevertedFrom, &rev.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	if tags != "" {
+		rev.Tags = parseTags(strings.Split(tags, ","))
+	}
+	var err error
+	if rev.Recurrence, err = valueobject.ParseRecurrence(recurrence); err != nil {
+		return nil, err
+	}
+	return &rev, nil
+}
+
+// Append numera a revisão a partir da última da tarefa. A chave primária
+// (task_id, number) impede que duas transações gravem o mesmo número.
+func (r *SQLiteRevisionRepository) Append(ctx context.Context, revision *domain.Revision) error {
+	last, err := r.LastNumber(ctx, revision.TaskID)
+	if err != nil {
+		return err
+	}
+
+	revision.Number = last + 1
+	_, err = r.getExecutor().ExecContext(ctx, `
+		INSERT INTO task_revisions (`+revisionColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		revision.TaskID, revision.Number, revision.AuthorID, revision.Title, revision.Description, revision.Priority, revision.Status, revision.AssigneeID,
+		revision.DueAt, joinTags(revision.Tags), revision.Recurrence.String(), revision.RevertedFrom, revision.CreatedAt)
+	return err
+}
+
+func (r *SQLiteRevisionRepository) ListByTask(ctx context.Context, taskID string) ([]*domain.Revision, error) {
+	rows, err := r.getExecutor().QueryContext(ctx, `
+		SELECT `+revisionColumns+`
+		FROM task_revisions
+		WHERE task_id = ?
+		ORDER BY number`, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var revisions []*domain.Revision
+	for rows.Next() {
+		rev, err := scanRevision(rows)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, rev)
+	}
+	return revisions, rows.Err()
+}
+
+func (r *SQLiteRevisionRepository) Find(ctx context.Context, taskID string, number int) (*domain.Revision, error) {
+	row := r.getExecutor().QueryRowContext(ctx, `SELECT `+revisionColumns+` FROM task_revisions WHERE task_id = ? AND number = ?`, taskID, number)
+	return scanRevision(row)
+}
+
+func (r *SQLiteRevisionRepository) LastNumber(ctx context.Context, taskID string) (int, error) {
+	var last int
+	err := r.getExecutor().QueryRowContext(ctx, `SELECT COALESCE(MAX(number), 0) FROM task_revisions WHERE task_id = ?`, taskID).Scan(&last)
+	return last, err
+}
+
+func (r *SQLiteRevisionRepository) DeleteByTasks(ctx context.Context, taskIDs []string) error {
+	if len(taskIDs) == 0 {
+		return nil
+	}
+	args := make([]any, len(taskIDs))
+	for i, id := range taskIDs {
+		args[i] = id
+	}
+	_, err := r.getExecutor().ExecContext(ctx, `DELETE FROM task_revisions WHERE task_id IN (`+placeholders(len(taskIDs))+`)`, args...)
+	return err
+}
@@ -0,0 +1,68 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/hoyci/todo-ddd/pkg/policy"
+)
+
+// SQLiteRoleStore guarda os papéis globais concedidos a cada usuário. A
+// política o consulta fora da unidade de trabalho, antes de o caso de uso
+// abrir a transação; as concessões passam por ela, para serem auditadas.
+type SQLiteRoleStore struct {
+	db *sql.DB
+	tx *sql.Tx
+}
+
+func NewSQLiteRoleStore(db *sql.DB) *SQLiteRoleStore {
+	return &SQLiteRoleStore{db: db}
+}
+
+func (s *SQLiteRoleStore) WithTx(tx *sql.Tx) *SQLiteRoleStore {
+	return &SQLiteRoleStore{tx: tx}
+}
+
+func (s *SQLiteRoleStore) getExecutor() SQLExecutor {
+	if s.tx != nil {
+		return traced(s.tx)
+	}
+	return traced(s.db)
+}
+
+func (s *SQLiteRoleStore) Roles(ctx context.Context, userID string) ([]policy.Role, error) {
+	rows, err := s.getExecutor().QueryContext(ctx, `
+		SELECT role
+		FROM user_roles
+		WHERE user_id = ?
+		ORDER BY role`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var roles []policy.Role
+	for rows.Next() {
+		var role policy.Role
+		if err := rows.Scan(&role); err != nil {
+			return nil, err
+		}
+		roles = append(roles, role)
+	}
+	return roles, rows.Err()
+}
+
+func (s *SQLiteRoleStore) Grant(ctx context.Context, userID string, role policy.Role) error {
+	_, err := s.getExecutor().ExecContext(ctx, `
+		INSERT INTO user_roles (user_id, role, granted_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT (user_id, role) DO NOTHING`,
+		userID, role, time.Now())
+	return err
+}
+
+func (s *SQLiteRoleStore) Revoke(ctx context.Context, userID string, role policy.Role) error {
+	_, err := s.getExecutor().ExecContext(ctx, `DELETE FROM user_roles WHERE user_id = ? AND role = ?`, userID, role)
+	return err
+}
@@ -0,0 +1,94 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	domain "github.com/hoyci/todo-ddd/pkg/domain/task"
+)
+
+type SQLiteShareRepository struct {
+	db *sql.DB
+	tx *sql.Tx
+}
+
+func NewSQLiteShareRepository(db *sql.DB) *SQLiteShareRepository {
+	return &SQLiteShareRepository{db: db}
+}
+
+func (r *SQLiteShareRepository) WithTx(tx *sql.Tx) *SQLiteShareRepository {
+	return &SQLiteShareRepository{tx: tx}
+}
+
+func (r *SQLiteShareRepository) getExecutor() SQLExecutor {
+	if r.tx != nil {
+		return traced(r.tx)
+	}
+	return traced(r.db)
+}
+
+const shareColumns = `task_id, user_id, permission, granted_by, created_at`
+
+func scanShare(row rowScanner) (*domain.Share, error) {
+	var s domain.Share
+	if err := row.Scan(&s.TaskID, &s.UserID, &s.Permission, &s.GrantedBy, &s.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Save mantém created_at e granted_by de um compartilhamento existente e
+// troca só a permissão.
+func (r *SQLiteShareRepository) Save(ctx context.Context, share *domain.Share) error {
+	_, err := r.getExecutor().ExecContext(ctx, `
+		INSERT INTO task_shares (task_id, user_id, permission, granted_by, created_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (task_id, user_id) DO UPDATE SET permission = excluded.permission`,
+		share.TaskID, share.UserID, share.Permission, share.GrantedBy, share.CreatedAt)
+	return err
+}
+
+func (r *SQLiteShareRepository) Find(ctx context.Context, taskID, userID string) (*domain.Share, error) {
+	row := r.getExecutor().QueryRowContext(ctx, `
+		SELECT `+shareColumns+` FROM task_shares WHERE task_id = ? AND user_id = ?`, taskID, userID)
+	return scanShare(row)
+}
+
+func (r *SQLiteShareRepository) ListByTask(ctx context.Context, taskID string) ([]*domain.Share, error) {
+	rows, err := r.getExecutor().QueryContext(ctx, `
+		SELECT `+shareColumns+`
+		FROM task_shares
+		WHERE task_id = ?
+		ORDER BY created_at, user_id`, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var shares []*domain.Share
+	for rows.Next() {
+		s, err := scanShare(rows)
+		if err != nil {
+			return nil, err
+		}
+		shares = append(shares, s)
+	}
+	return shares, rows.Err()
+}
+
+func (r *SQLiteShareRepository) Delete(ctx context.Context, taskID, userID string) error {
+	_, err := r.getExecutor().ExecContext(ctx, `DELETE FROM task_shares WHERE task_id = ? AND user_id = ?`, taskID, userID)
+	return err
+}
+
+func (r *SQLiteShareRepository) DeleteByTasks(ctx context.Context, taskIDs []string) error {
+	if len(taskIDs) == 0 {
+		return nil
+	}
+	args := make([]any, len(taskIDs))
+	for i, id := range taskIDs {
+		args[i] = id
+	}
+	_, err := r.getExecutor().ExecContext(ctx, `DELETE FROM task_shares WHERE task_id IN (`+placeholders(len(taskIDs))+`)`, args...)
+	return err
+}
@@ -0,0 +1,220 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	domain "github.com/hoyci/todo-ddd/pkg/domain/task"
+	"github.com/hoyci/todo-ddd/pkg/domain/valueobject"
+	workspaceDomain "github.com/hoyci/todo-ddd/pkg/domain/workspace"
+)
+
+// taskEventPayload é o estado da tarefa gravado junto com cada evento.
+type taskEventPayload struct {
+	ID          string     `json:"id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Priority    int        `json:"priority"`
+	Status      string     `json:"status"`
+	UserID      string     `json:"user_id"`
+	AssigneeID  string     `json:"assignee_id,omitempty"`
+	DueAt       *time.Time `json:"due_at,omitempty"`
+	Tags        []string   `json:"tags,omitempty"`
+	Recurrence  string     `json:"recurrence,omitempty"`
+	Position    int        `json:"position,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   *time.Time `json:"updated_at"`
+	DeletedAt   *time.Time `json:"deleted_at"`
+}
+
+type SQLiteTaskEventRepository struct {
+	db *sql.DB
+	tx *sql.Tx
+	// appended acumula os eventos gravados na transação, para publicação
+	// após o commit.
+	appended []domain.Event
+}
+
+func NewSQLiteTaskEventRepository(db *sql.DB) *SQLiteTaskEventRepository {
+	return &SQLiteTaskEventRepository{db: db}
+}
+
+func (r *SQLiteTaskEventRepository) WithTx(tx *sql.Tx) *SQLiteTaskEventRepository {
+	return &SQLiteTaskEventRepository{tx: tx}
+}
+
+func (r *SQLiteTaskEventRepository) getExecutor() SQLExecutor {
+	if r.tx != nil {
+		return traced(r.tx)
+	}
+	return traced(r.db)
+}
+
+func (r *SQLiteTaskEventRepository) Append(ctx context.Context, events []domain.Event) error {
+	for i := range events {
+		event := &events[i]
+		payload, err := json.Marshal(taskEventPayload{
+			ID:          event.Task.ID,
+			Title:       event.Task.Title,
+			Description: event.Task.Description,
+			Priority:    int(event.Task.Priority),
+			Status:      string(event.Task.Status),
+			UserID:      event.Task.UserID,
+			AssigneeID:  event.Task.AssigneeID,
+			DueAt:       event.Task.DueAt,
+			Tags:        tagStrings(event.Task.Tags),
+			Recurrence:  event.Task.Recurrence.String(),
+			Position:    event.Task.Position,
+			CreatedAt:   event.Task.CreatedAt,
+			UpdatedAt:   event.Task.UpdatedAt,
+			DeletedAt:   event.Task.DeletedAt,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = r.getExecutor().QueryRowContext(ctx, `
+			INSERT INTO task_events (user_id, workspace_id, task_id, type, payload, occurred_at)
+			VALUES (?, ?, ?, ?, ?, ?)
+			RETURNING id`,
+			event.UserID, event.Task.WorkspaceID, event.TaskID, event.Type, string(payload), event.OccurredAt).
+			Scan(&event.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	r.appended = append(r.appended, events...)
+	return nil
+}
+
+// eventsInScope restringe o feed (com a tabela task_events como e) ao
+// workspace do contexto, seguido de cinco parâmetros userID. São os mesmos
+// destinatários que o Broker avisa: no espaço pessoal, os eventos das
+// tarefas do usuário, das que ele acompanha, das atribuídas a ele e das
+// compartilhadas com ele; em um workspace, os de todas as tarefas, desde que
+// ele seja membro.
+const eventsInScope = `e.workspace_id = ? AND (
+	(e.workspace_id = '' AND (
+		e.user_id = ? OR
+		e.task_id IN (SELECT task_id FROM task_watchers WHERE user_id = ?) OR
+		e.task_id IN (SELECT id FROM tasks WHERE assignee_id = ? AND assignee_id <> '') OR
+		e.task_id IN (SELECT task_id FROM task_shares WHERE user_id = ?)
+	)) OR
+	EXISTS (SELECT 1 FROM active_workspace_members m WHERE m.workspace_id = e.workspace_id AND m.user_id = ?)
+)`
+
+func (r *SQLiteTaskEventRepository) ListAfter(ctx context.Context, userID string, afterID int64, limit int) ([]domain.Event, error) {
+	rows, err := r.getExecutor().QueryContext(ctx, `
+		SELECT `+eventColumns+`
+		FROM task_events e
+		WHERE e.id > ? AND `+eventsInScope+`
+		ORDER BY e.id
+		LIMIT ?`, afterID, workspaceDomain.ScopeFrom(ctx), userID, userID, userID, userID, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanEvents(rows)
+}
+
+func (r *SQLiteTaskEventRepository) ListByTask(ctx context.Context, taskID string) ([]domain.Event, error) {
+	rows, err := r.getExecutor().QueryContext(ctx, `
+		SELECT `+eventColumns+`
+		FROM task_events e
+		WHERE e.task_id = ? AND e.workspace_id = ?
+		ORDER BY e.id`, taskID, workspaceDomain.ScopeFrom(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanEvents(rows)
+}
+
+// eventOfVisibleTask restringe a consulta (com a tabela task_events como e)
+// à tarefa do primeiro parâmetro, no workspace do contexto, seguido dos
+// parâmetros de visibleTo.
+const eventOfVisibleTask = `e.task_id = ? AND e.workspace_id = ? AND
+	EXISTS (SELECT 1 FROM tasks t WHERE t.id = e.task_id AND ` + visibleTo + `)`
+
+func (r *SQLiteTaskEventRepository) ListByTaskAfter(ctx context.Context, taskID, userID string, afterID int64, limit int) ([]domain.Event, error) {
+	scope := workspaceDomain.ScopeFrom(ctx)
+	rows, err := r.getExecutor().QueryContext(ctx, `
+		SELECT `+eventColumns+`
+		FROM task_events e
+		WHERE e.id > ? AND `+eventOfVisibleTask+`
+		ORDER BY e.id
+		LIMIT ?`, afterID, taskID, scope, scope, userID, userID, userID, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanEvents(rows)
+}
+
+const eventColumns = `e.id, e.user_id, e.workspace_id, e.task_id, e.type, e.payload, e.occurred_at`
+
+func scanEvents(rows *sql.Rows) ([]domain.Event, error) {
+	var events []domain.Event
+	for rows.Next() {
+		var (
+			event       domain.Event
+			workspaceID string
+			payload     string
+			p           taskEventPayload
+		)
+		if err := rows.Scan(&event.ID, &event.UserID, &workspaceID, &event.TaskID, &event.Type, &payload, &event.OccurredAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(payload), &p); err != nil {
+			return nil, err
+		}
+		recurrence, err := valueobject.ParseRecurrence(p.Recurrence)
+		if err != nil {
+			return nil, err
+		}
+		event.Task = domain.Task{
+			ID:          p.ID,
+			Title:       p.Title,
+			Description: p.Description,
+			Priority:    valueobject.Priority(p.Priority),
+			Status:      valueobject.Status(p.Status),
+			UserID:      p.UserID,
+			AssigneeID:  p.AssigneeID,
+			WorkspaceID: workspaceID,
+			DueAt:       p.DueAt,
+			Tags:        parseTags(p.Tags),
+			Recurrence:  recurrence,
+			Position:    p.Position,
+			CreatedAt:   p.CreatedAt,
+			UpdatedAt:   p.UpdatedAt,
+			DeletedAt:   p.DeletedAt,
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+func (r *SQLiteTaskEventRepository) LastID(ctx context.Context, userID string) (int64, error) {
+	var id int64
+	err := r.getExecutor().QueryRowContext(ctx, `
+		SELECT COALESCE(MAX(e.id), 0)
+		FROM task_events e
+		WHERE `+eventsInScope, workspaceDomain.ScopeFrom(ctx), userID, userID, userID, userID, userID).Scan(&id)
+	return id, err
+}
+
+func (r *SQLiteTaskEventRepository) LastIDByTask(ctx context.Context, taskID, userID string) (int64, error) {
+	scope := workspaceDomain.ScopeFrom(ctx)
+	var id int64
+	err := r.getExecutor().QueryRowContext(ctx, `
+		SELECT COALESCE(MAX(e.id), 0)
+		FROM task_events e
+		WHERE `+eventOfVisibleTask, taskID, scope, scope, userID, userID, userID, userID).Scan(&id)
+	return id, err
+}
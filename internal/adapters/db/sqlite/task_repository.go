@@ -1,10 +1,14 @@
 package sqlite
 
 import (
+	"context"
 	"database/sql"
+	"strings"
 	"time"
 
 	domain "github.com/hoyci/todo-ddd/pkg/domain/task"
+	"github.com/hoyci/todo-ddd/pkg/domain/valueobject"
+	workspaceDomain "github.com/hoyci/todo-ddd/pkg/domain/workspace"
 	_ "modernc.org/sqlite"
 )
 
@@ -23,44 +27,151 @@ func (r *SQLiteTaskRepository) WithTx(tx *sql.Tx) *SQLiteTaskRepository {
 
 func (r *SQLiteTaskRepository) getExecutor() SQLExecutor {
 	if r.tx != nil {
-		return r.tx
+		return traced(r.tx)
 	}
-	return r.db
+	return traced(r.db)
 }
 
-func (r *SQLiteTaskRepository) Save(task *domain.Task) error {
-	_, err := r.getExecutor().Exec(`
-		INSERT INTO tasks (id, title, description, priority, status, created_at, updated_at, deleted_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
-		task.ID, task.Title, task.Description, task.Priority, task.Status, task.CreatedAt, task.UpdatedAt, task.DeletedAt)
+const taskColumns = `id, title, description, priority, status, user_id, assignee_id, workspace_id, due_at, tags, recurrence, position, created_at, updated_at, deleted_at`
+
+// visibleInScope restringe a consulta (com a tabela tasks como t) ao
+// workspace do contexto, seguido de dois parâmetros userID: no espaço
+// pessoal só o dono enxerga a tarefa; em um workspace, qualquer membro.
+const visibleInScope = `t.workspace_id = ? AND (
+	(t.workspace_id = '' AND t.user_id = ?) OR
+	EXISTS (SELECT 1 FROM active_workspace_members m WHERE m.workspace_id = t.workspace_id AND m.user_id = ?)
+)`
+
+// visibleTo restringe a consulta (com a tabela tasks como t) ao workspace
+// do contexto, seguido de quatro parâmetros userID: no espaço pessoal
+// enxergam a tarefa o dono, o responsável e quem a recebeu compartilhada;
+// em um workspace, qualquer membro.
+const visibleTo = `t.workspace_id = ? AND (
+	(t.workspace_id = '' AND (
+		t.user_id = ? OR
+		t.assignee_id = ? OR
+		EXISTS (SELECT 1 FROM task_shares s WHERE s.task_id = t.id AND s.user_id = ?)
+	)) OR
+	EXISTS (SELECT 1 FROM active_workspace_members m WHERE m.workspace_id = t.workspace_id AND m.user_id = ?)
+)`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanTask lê uma linha selecionada com taskColumns.
+func scanTask(row rowScanner) (*domain.Task, error) {
+	var (
+		t                domain.Task
+		description      sql.NullString
+		assigneeID       sql.NullString
+		tags, recurrence sql.NullString
+	)
+	if err := row.Scan(&t.ID, &t.Title, &description, &t.Priority, &t.Status, &t.UserID, &assigneeID, &t.WorkspaceID, &t.DueAt, &tags, &recurrence,
+		&t.Position, &t.CreatedAt, &t.UpdatedAt, &t.DeletedAt); err != nil {
+		return nil, err
+	}
+	t.Description = description.String
+	t.AssigneeID = assigneeID.String
+
+	if tags.String != "" {
+		t.Tags = parseTags(strings.Split(tags.String, ","))
+	}
+	var err error
+	if t.Recurrence, err = valueobject.ParseRecurrence(recurrence.String); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// joinTags grava as tags separadas por vírgula, que não é aceita em
+// valueobject.Tag.
+func joinTags(tags []valueobject.Tag) string {
+	return strings.Join(tagStrings(tags), ",")
+}
+
+func tagStrings(tags []valueobject.Tag) []string {
+	parts := make([]string, len(tags))
+	for i, tag := range tags {
+		parts[i] = string(tag)
+	}
+	return parts
+}
+
+func parseTags(parts []string) []valueobject.Tag {
+	var tags []valueobject.Tag
+	for _, part := range parts {
+		tags = append(tags, valueobject.Tag(part))
+	}
+	return tags
+}
+
+func (r *SQLiteTaskRepository) Save(ctx context.Context, task *domain.Task) error {
+	_, err := r.getExecutor().ExecContext(ctx, `
+		INSERT INTO tasks (`+taskColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		task.ID, task.Title, task.Description, task.Priority, task.Status, task.UserID, task.AssigneeID, task.WorkspaceID, task.DueAt, joinTags(task.Tags), task.Recurrence.String(),
+		task.Position, task.CreatedAt, task.UpdatedAt, task.DeletedAt)
 	return err
 }
 
-func (r *SQLiteTaskRepository) Update(task *domain.Task) error {
-	_, err := r.getExecutor().Exec(`
+func (r *SQLiteTaskRepository) Update(ctx context.Context, task *domain.Task) error {
+	_, err := r.getExecutor().ExecContext(ctx, `
 		UPDATE tasks 
 		SET title = ?, 
 			description = ?, 
 			priority = ?, 
 			status = ?, 
+			assignee_id = ?,
+			due_at = ?,
+			tags = ?,
+			recurrence = ?,
 			updated_at = ? 
-		WHERE id = ? AND deleted_at IS NULL`,
-		task.Title, task.Description, task.Priority, task.Status, task.UpdatedAt, task.ID)
+		WHERE id = ? AND workspace_id = ? AND deleted_at IS NULL`,
+		task.Title, task.Description, task.Priority, task.Status, task.AssigneeID, task.DueAt, joinTags(task.Tags), task.Recurrence.String(), task.UpdatedAt,
+		task.ID, workspaceDomain.ScopeFrom(ctx))
 	return err
 }
 
-func (r *SQLiteTaskRepository) FindByID(id, userID string) (*domain.Task, error) {
-	row := r.getExecutor().QueryRow(`SELECT id, title, description, priority, status, created_at, updated_at, deleted_at FROM tasks t WHERE t.id = ? AND t.user_id = ?`, id, userID)
-	task := &domain.Task{}
-	err := row.Scan(&task.ID, &task.Title, &task.Description, &task.Priority, &task.Status, &task.CreatedAt, &task.UpdatedAt, &task.DeletedAt)
+func (r *SQLiteTaskRepository) FindByID(ctx context.Context, id, userID string) (*domain.Task, error) {
+	row := r.getExecutor().QueryRowContext(ctx, `
+		SELECT `+taskColumns+`
+		FROM tasks t
+		WHERE t.id = ? AND `+visibleTo, id, workspaceDomain.ScopeFrom(ctx), userID, userID, userID, userID)
+	return scanTask(row)
+}
+
+func (r *SQLiteTaskRepository) List(ctx context.Context, userID string) ([]*domain.Task, error) {
+	rows, err := r.getExecutor().QueryContext(ctx, `
+		SELECT `+taskColumns+`
+		FROM tasks t
+		WHERE `+visibleInScope+` AND t.deleted_at IS NULL
+		ORDER BY t.position, t.created_at, t.id`, workspaceDomain.ScopeFrom(ctx), userID, userID)
 	if err != nil {
 		return nil, err
 	}
-	return task, nil
+	defer rows.Close()
+
+	var tasks []*domain.Task
+	for rows.Next() {
+		t, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, t)
+	}
+	return tasks, nil
 }
 
-func (r *SQLiteTaskRepository) List(userID string) ([]*domain.Task, error) {
-	rows, err := r.getExecutor().Query(`SELECT id, title, description, priority, status, created_at, updated_at FROM tasks t WHERE t.user_id = ? AND t.deleted_at is null`, userID)
+func (r *SQLiteTaskRepository) ListAssigned(ctx context.Context, assigneeID string) ([]*domain.Task, error) {
+	rows, err := r.getExecutor().QueryContext(ctx, `
+		SELECT `+taskColumns+`
+		FROM tasks t
+		WHERE t.assignee_id = ? AND t.workspace_id = ? AND t.deleted_at IS NULL AND (
+			t.workspace_id = '' OR
+			EXISTS (SELECT 1 FROM active_workspace_members m WHERE m.workspace_id = t.workspace_id AND m.user_id = t.assignee_id)
+		)
+		ORDER BY t.due_at IS NULL, t.due_at, t.created_at, t.id`, assigneeID, workspaceDomain.ScopeFrom(ctx))
 	if err != nil {
 		return nil, err
 	}
@@ -68,21 +179,242 @@ func (r *SQLiteTaskRepository) List(userID string) ([]*domain.Task, error) {
 
 	var tasks []*domain.Task
 	for rows.Next() {
-		t := &domain.Task{}
-		if err := rows.Scan(&t.ID, &t.Title, &t.Description, &t.Priority, &t.Status, &t.CreatedAt, &t.UpdatedAt); err != nil {
+		t, err := scanTask(rows)
+		if err != nil {
 			return nil, err
 		}
 		tasks = append(tasks, t)
 	}
-	return tasks, nil
+	return tasks, rows.Err()
+}
+
+func (r *SQLiteTaskRepository) ListByUserIDs(ctx context.Context, query domain.TasksByUsersQuery) ([]*domain.Task, error) {
+	if len(query.UserIDs) == 0 {
+		return nil, nil
+	}
+
+	where, args := tasksByUsersWhere(ctx, query)
+	if query.AfterID != "" {
+		where += ` AND (t.created_at, t.id) > (SELECT created_at, id FROM tasks WHERE id = ?)`
+		args = append(args, query.AfterID)
+	}
+	sqlQuery := `
+		SELECT ` + taskColumns + `
+		FROM tasks t
+		WHERE ` + where + `
+		ORDER BY t.created_at, t.id`
+	if query.Limit > 0 {
+		// ROW_NUMBER numera as tarefas de cada dono, limitando a página de
+		// todos eles na mesma consulta.
+		sqlQuery = `
+			SELECT ` + taskColumns + `
+			FROM (
+				SELECT t.*, ROW_NUMBER() OVER (PARTITION BY t.user_id ORDER BY t.created_at, t.id) AS owner_rank
+				FROM tasks t
+				WHERE ` + where + `
+			)
+			WHERE owner_rank <= ?
+			ORDER BY created_at, id`
+		args = append(args, query.Limit)
+	}
+
+	rows, err := r.getExecutor().QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tasks []*domain.Task
+	for rows.Next() {
+		t, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, t)
+	}
+	return tasks, rows.Err()
 }
 
-func (r *SQLiteTaskRepository) Delete(id string, timestamp time.Time) error {
+func (r *SQLiteTaskRepository) CountByUserIDs(ctx context.Context, query domain.TasksByUsersQuery) ([]domain.UserTaskCount, error) {
+	if len(query.UserIDs) == 0 {
+		return nil, nil
+	}
+
+	where, args := tasksByUsersWhere(ctx, query)
+	rows, err := r.getExecutor().QueryContext(ctx, `
+		SELECT t.user_id, t.status, COUNT(*)
+		FROM tasks t
+		WHERE `+where+`
+		GROUP BY t.user_id, t.status`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var counts []domain.UserTaskCount
+	for rows.Next() {
+		c := domain.UserTaskCount{}
+		if err := rows.Scan(&c.UserID, &c.Status, &c.Count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, c)
+	}
+	return counts, rows.Err()
+}
+
+// tasksByUsersWhere monta o filtro (com a tabela tasks como t) comum a
+// ListByUserIDs e CountByUserIDs, sem o cursor.
+func tasksByUsersWhere(ctx context.Context, query domain.TasksByUsersQuery) (string, []any) {
+	args := make([]any, 0, len(query.UserIDs)+8)
+	for _, id := range query.UserIDs {
+		args = append(args, id)
+	}
+	args = append(args, workspaceDomain.ScopeFrom(ctx), query.ViewerID, query.ViewerID, query.ViewerID, query.ViewerID)
+	where := `t.user_id IN (` + placeholders(len(query.UserIDs)) + `) AND t.deleted_at IS NULL AND ` + visibleTo
+
+	if query.Filter.Status != "" {
+		where += ` AND t.status = ?`
+		args = append(args, query.Filter.Status)
+	}
+	if query.Filter.Priority != 0 {
+		where += ` AND t.priority = ?`
+		args = append(args, query.Filter.Priority)
+	}
+	if query.Filter.Search != "" {
+		// instr em vez de LIKE, para que % e _ da busca não virem curingas.
+		search := strings.ToLower(query.Filter.Search)
+		where += ` AND (instr(lower(t.title), ?) > 0 OR instr(lower(coalesce(t.description, '')), ?) > 0)`
+		args = append(args, search, search)
+	}
+	return where, args
+}
+
+// ListPage pagina por (created_at, id) em vez de OFFSET, para que cada
+// página custe o mesmo independentemente da posição.
+func (r *SQLiteTaskRepository) ListPage(ctx context.Context, query domain.TaskPageQuery) ([]*domain.Task, error) {
+	sqlQuery := `
+		SELECT ` + taskColumns + `
+		FROM tasks t
+		WHERE ` + visibleInScope
+	args := []any{workspaceDomain.ScopeFrom(ctx), query.UserID, query.UserID}
+	if !query.IncludeDeleted {
+		sqlQuery += ` AND t.deleted_at IS NULL`
+	}
+	if query.After != nil {
+		// Compara com o valor gravado, e não com o time.Time lido: o driver
+		// formata parâmetros de tempo de outro jeito que o texto armazenado.
+		sqlQuery += ` AND (t.created_at, t.id) > (SELECT created_at, id FROM tasks WHERE id = ?)`
+		args = append(args, query.After.ID)
+	}
+	sqlQuery += ` ORDER BY t.created_at, t.id LIMIT ?`
+	args = append(args, query.Limit)
+
+	rows, err := r.getExecutor().QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tasks []*domain.Task
+	for rows.Next() {
+		t, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, t)
+	}
+	return tasks, rows.Err()
+}
+
+func (r *SQLiteTaskRepository) Reorder(ctx context.Context, ids []string) error {
+	for i, id := range ids {
+		_, err := r.getExecutor().ExecContext(ctx, `
+			UPDATE tasks
+			SET position = ?
+			WHERE id = ? AND workspace_id = ? AND position <> ?`,
+			i+1, id, workspaceDomain.ScopeFrom(ctx), i+1)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *SQLiteTaskRepository) Delete(ctx context.Context, id string, timestamp time.Time) error {
 	query := `
 		UPDATE tasks 
 		SET deleted_at = ?
-		WHERE id = ? AND deleted_at IS NULL
+		WHERE id = ? AND workspace_id = ? AND deleted_at IS NULL
 	`
-	_, err := r.getExecutor().Exec(query, timestamp, id)
+	_, err := r.getExecutor().ExecContext(ctx, query, timestamp, id, workspaceDomain.ScopeFrom(ctx))
 	return err
 }
+
+func (r *SQLiteTaskRepository) Restore(ctx context.Context, id string, timestamp time.Time) error {
+	_, err := r.getExecutor().ExecContext(ctx, `
+		UPDATE tasks
+		SET deleted_at = NULL,
+			updated_at = ?
+		WHERE id = ? AND workspace_id = ? AND deleted_at IS NOT NULL`,
+		timestamp, id, workspaceDomain.ScopeFrom(ctx))
+	return err
+}
+
+// ListDeletedBefore, Purge e CountOpen servem à manutenção e às métricas e
+// por isso valem para todos os workspaces.
+func (r *SQLiteTaskRepository) ListDeletedBefore(ctx context.Context, before time.Time, limit int) ([]string, error) {
+	rows, err := r.getExecutor().QueryContext(ctx, `
+		SELECT id
+		FROM tasks
+		WHERE deleted_at IS NOT NULL AND deleted_at < ?
+		ORDER BY deleted_at
+		LIMIT ?`, before, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func (r *SQLiteTaskRepository) Purge(ctx context.Context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	_, err := r.getExecutor().ExecContext(ctx, `DELETE FROM tasks WHERE id IN (`+placeholders(len(ids))+`)`, args...)
+	return err
+}
+
+func (r *SQLiteTaskRepository) CountOpen(ctx context.Context) ([]domain.OpenTaskCount, error) {
+	rows, err := r.getExecutor().QueryContext(ctx, `
+		SELECT status, priority, COUNT(*)
+		FROM tasks
+		WHERE deleted_at IS NULL AND status != ?
+		GROUP BY status, priority`, valueobject.StatusCompleted)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var counts []domain.OpenTaskCount
+	for rows.Next() {
+		c := domain.OpenTaskCount{}
+		if err := rows.Scan(&c.Status, &c.Priority, &c.Count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, c)
+	}
+	return counts, rows.Err()
+}
@@ -0,0 +1,149 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"testing"
+
+	taskDomain "github.com/hoyci/todo-ddd/pkg/domain/task"
+	"github.com/hoyci/todo-ddd/pkg/domain/valueobject"
+	workspaceDomain "github.com/hoyci/todo-ddd/pkg/domain/workspace"
+)
+
+// TestWorkspaceIsolation confere que as consultas de tarefas só devolvem
+// as do workspace do contexto, e só para quem participa dele.
+func TestWorkspaceIsolation(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	tasks := NewSQLiteTaskRepository(db)
+	workspaces := NewSQLiteWorkspaceRepository(db)
+
+	newWorkspace := func(name, ownerID string, members ...string) *workspaceDomain.Workspace {
+		workspace, owner, err := workspaceDomain.NewWorkspace(name, ownerID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := workspaces.Save(ctx, workspace); err != nil {
+			t.Fatal(err)
+		}
+		all := []*workspaceDomain.Member{owner}
+		for _, userID := range members {
+			all = append(all, &workspaceDomain.Member{WorkspaceID: workspace.ID, UserID: userID, Role: valueobject.RoleGuest, JoinedAt: owner.JoinedAt})
+		}
+		for _, member := range all {
+			if err := workspaces.SaveMember(ctx, member); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return workspace
+	}
+	a := newWorkspace("Team A", "u1", "u2")
+	b := newWorkspace("Team B", "u2")
+
+	newTask := func(title, userID, workspaceID string) string {
+		task, err := taskDomain.NewTask(title, "", userID, valueobject.Low, taskDomain.InWorkspace(workspaceID))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := tasks.Save(ctx, task); err != nil {
+			t.Fatal(err)
+		}
+		return task.Title
+	}
+	newTask("p1", "u1", "")
+	newTask("p2", "u2", "")
+	newTask("a1", "u1", a.ID)
+	newTask("b1", "u2", b.ID)
+
+	titles := func(list []*taskDomain.Task) []string {
+		var out []string
+		for _, task := range list {
+			out = append(out, task.Title)
+		}
+		slices.Sort(out)
+		return out
+	}
+	check := func(t *testing.T, scope, viewer string, want []string) {
+		t.Helper()
+		scoped := workspaceDomain.WithScope(ctx, scope)
+
+		list, err := tasks.List(scoped, viewer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := titles(list); !slices.Equal(got, want) {
+			t.Errorf("List(%q, %s) = %v, want %v", scope, viewer, got, want)
+		}
+
+		page, err := tasks.ListPage(scoped, taskDomain.TaskPageQuery{UserID: viewer, Limit: 10})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := titles(page); !slices.Equal(got, want) {
+			t.Errorf("ListPage(%q, %s) = %v, want %v", scope, viewer, got, want)
+		}
+
+		byUsers, err := tasks.ListByUserIDs(scoped, taskDomain.TasksByUsersQuery{UserIDs: []string{"u1", "u2", "u3"}, ViewerID: viewer})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := titles(byUsers); !slices.Equal(got, want) {
+			t.Errorf("ListByUserIDs(%q, %s) = %v, want %v", scope, viewer, got, want)
+		}
+
+		for _, task := range append(list, byUsers...) {
+			if _, err := tasks.FindByID(scoped, task.ID, viewer); err != nil {
+				t.Errorf("FindByID(%q, %s, %s) = %v", scope, viewer, task.Title, err)
+			}
+		}
+	}
+
+	tests := []struct {
+		scope  string
+		viewer string
+		want   []string
+	}{
+		{"", "u1", []string{"p1"}},
+		{"", "u2", []string{"p2"}},
+		{"", "u3", nil},
+		{a.ID, "u1", []string{"a1"}},
+		{a.ID, "u2", []string{"a1"}},
+		{a.ID, "u3", nil},
+		{b.ID, "u1", nil},
+		{b.ID, "u2", []string{"b1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.scope+"/"+tt.viewer, func(t *testing.T) {
+			check(t, tt.scope, tt.viewer, tt.want)
+		})
+	}
+
+	// Uma tarefa de workspace não aparece fora dele, nem pelo ID.
+	list, err := tasks.List(workspaceDomain.WithScope(ctx, a.ID), "u1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, scope := range []string{"", b.ID} {
+		if _, err := tasks.FindByID(workspaceDomain.WithScope(ctx, scope), list[0].ID, "u2"); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("FindByID of a1 in scope %q = %v, want sql.ErrNoRows", scope, err)
+		}
+	}
+
+	t.Run("removed member", func(t *testing.T) {
+		if err := workspaces.RemoveMember(ctx, a.ID, "u2"); err != nil {
+			t.Fatal(err)
+		}
+		check(t, a.ID, "u2", nil)
+		check(t, a.ID, "u1", []string{"a1"})
+	})
+
+	t.Run("deleted workspace", func(t *testing.T) {
+		a.Delete()
+		if err := workspaces.Update(ctx, a); err != nil {
+			t.Fatal(err)
+		}
+		check(t, a.ID, "u1", nil)
+	})
+}
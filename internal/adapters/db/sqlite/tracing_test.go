@@ -0,0 +1,86 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracedExecutor(t *testing.T) {
+	// O tracer do pacote delega ao provider global, que só pode ser trocado
+	// uma vez por processo.
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+	db := traced(openTestDB(t))
+
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "use case")
+	tests := []struct {
+		name      string
+		run       func() error
+		span      string
+		statement string
+		status    codes.Code
+	}{
+		{
+			name: "exec",
+			run: func() error {
+				_, err := db.ExecContext(ctx, "DELETE   FROM tasks\n\t\tWHERE id = ?", "t1")
+				return err
+			},
+			span:      "sqlite.delete",
+			statement: "DELETE FROM tasks WHERE id = ?",
+		},
+		{
+			name: "no rows is not an error",
+			run: func() error {
+				var id string
+				db.QueryRowContext(ctx, "SELECT id FROM tasks WHERE id = ?", "t1").Scan(&id)
+				return nil
+			},
+			span:      "sqlite.select",
+			statement: "SELECT id FROM tasks WHERE id = ?",
+		},
+		{
+			name: "failed query",
+			run: func() error {
+				rows, err := db.QueryContext(ctx, "SELECT missing FROM tasks")
+				if err == nil {
+					rows.Close()
+				}
+				return nil
+			},
+			span:      "sqlite.select",
+			statement: "SELECT missing FROM tasks",
+			status:    codes.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder.Reset()
+			if err := tt.run(); err != nil {
+				t.Fatal(err)
+			}
+			spans := recorder.Ended()
+			if len(spans) != 1 {
+				t.Fatalf("recorded %d spans, want 1", len(spans))
+			}
+			span := spans[0]
+			if span.Name() != tt.span || span.Status().Code != tt.status {
+				t.Errorf("span = %s with status %v, want %s with %v", span.Name(), span.Status().Code, tt.span, tt.status)
+			}
+			if span.Parent().SpanID() != parent.SpanContext().SpanID() {
+				t.Error("query span is not a child of the span in the context")
+			}
+			for _, attr := range span.Attributes() {
+				if attr.Key == "db.statement" && attr.Value.AsString() != tt.statement {
+					t.Errorf("db.statement = %q, want %q", attr.Value.AsString(), tt.statement)
+				}
+			}
+		})
+	}
+}
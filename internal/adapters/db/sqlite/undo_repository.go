@@ -0,0 +1,86 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	domain "github.com/hoyci/todo-ddd/pkg/domain/undo"
+)
+
+type SQLiteUndoRepository struct {
+	db *sql.DB
+	tx *sql.Tx
+}
+
+func NewSQLiteUndoRepository(db *sql.DB) *SQLiteUndoRepository {
+	return &SQLiteUndoRepository{db: db}
+}
+
+func (r *SQLiteUndoRepository) WithTx(tx *sql.Tx) *SQLiteUndoRepository {
+	return &SQLiteUndoRepository{tx: tx}
+}
+
+func (r *SQLiteUndoRepository) getExecutor() SQLExecutor {
+	if r.tx != nil {
+		return traced(r.tx)
+	}
+	return traced(r.db)
+}
+
+// Os instantes são gravados como REAL (ver unixSeconds) para que a limpeza
+// dos expirados compare números.
+func (r *SQLiteUndoRepository) Save(ctx context.Context, undo *domain.Undo) error {
+	steps, err := json.Marshal(undo.Steps)
+	if err != nil {
+		return err
+	}
+
+	exec := r.getExecutor()
+	if _, err := exec.ExecContext(ctx, `DELETE FROM task_undos WHERE expires_at < ?`, unixSeconds(time.Now())); err != nil {
+		return err
+	}
+	_, err = exec.ExecContext(ctx, `
+		INSERT INTO task_undos (token, user_id, action, steps, created_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		undo.Token, undo.UserID, undo.Action, string(steps), unixSeconds(undo.CreatedAt), unixSeconds(undo.ExpiresAt))
+	return err
+}
+
+func (r *SQLiteUndoRepository) FindByToken(ctx context.Context, token string) (*domain.Undo, error) {
+	var (
+		undo                 domain.Undo
+		steps                string
+		createdAt, expiresAt float64
+		usedAt               sql.NullFloat64
+	)
+	err := r.getExecutor().QueryRowContext(ctx, `
+		SELECT token, user_id, action, steps, created_at, expires_at, used_at
+		FROM task_undos
+		WHERE token = ?`, token).
+		Scan(&undo.Token, &undo.UserID, &undo.Action, &steps, &createdAt, &expiresAt, &usedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(steps), &undo.Steps); err != nil {
+		return nil, err
+	}
+	undo.CreatedAt = fromUnixSeconds(createdAt)
+	undo.ExpiresAt = fromUnixSeconds(expiresAt)
+	if usedAt.Valid {
+		t := fromUnixSeconds(usedAt.Float64)
+		undo.UsedAt = &t
+	}
+	return &undo, nil
+}
+
+func (r *SQLiteUndoRepository) Update(ctx context.Context, undo *domain.Undo) error {
+	var usedAt any
+	if undo.UsedAt != nil {
+		usedAt = unixSeconds(*undo.UsedAt)
+	}
+	_, err := r.getExecutor().ExecContext(ctx, `UPDATE task_undos SET used_at = ? WHERE token = ?`, usedAt, undo.Token)
+	return err
+}
@@ -4,34 +4,116 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
-	"log/slog"
 
 	"github.com/hoyci/todo-ddd/pkg/domain"
+	auditDomain "github.com/hoyci/todo-ddd/pkg/domain/audit"
+	notificationDomain "github.com/hoyci/todo-ddd/pkg/domain/notification"
 	taskDomain "github.com/hoyci/todo-ddd/pkg/domain/task"
+	undoDomain "github.com/hoyci/todo-ddd/pkg/domain/undo"
 	userDomain "github.com/hoyci/todo-ddd/pkg/domain/user"
+	workspaceDomain "github.com/hoyci/todo-ddd/pkg/domain/workspace"
+	"github.com/hoyci/todo-ddd/pkg/logging"
+	"github.com/hoyci/todo-ddd/pkg/policy"
+	"go.opentelemetry.io/otel/codes"
 )
 
 type sqliteWork struct {
-	tx       *sql.Tx
-	userRepo *SQLiteUserRepository
-	taskRepo *SQLiteTaskRepository
+	tx          *sql.Tx
+	savepoints  int
+	userRepo    *SQLiteUserRepository
+	taskRepo    *SQLiteTaskRepository
+	events      *SQLiteTaskEventRepository
+	comments    *SQLiteCommentRepository
+	revisions   *SQLiteRevisionRepository
+	reminders   *SQLiteReminderRepository
+	undos       *SQLiteUndoRepository
+	attachments *SQLiteAttachmentRepository
+	shares      *SQLiteShareRepository
+	watchers    *SQLiteWatcherRepository
+	workspaces  *SQLiteWorkspaceRepository
+	invitations *SQLiteInvitationRepository
+	roles       *SQLiteRoleStore
+	audit       *SQLiteAuditRepository
+	preferences *SQLiteNotificationPreferenceRepository
+	inbox       *SQLiteNotificationRepository
 }
 
-func (w *sqliteWork) UserRepo() userDomain.UserRepository { return w.userRepo }
-func (w *sqliteWork) TaskRepo() taskDomain.TaskRepository { return w.taskRepo }
+func (w *sqliteWork) UserRepo() userDomain.UserRepository       { return w.userRepo }
+func (w *sqliteWork) TaskRepo() taskDomain.TaskRepository       { return w.taskRepo }
+func (w *sqliteWork) TaskEventRepo() taskDomain.EventRepository { return w.events }
+func (w *sqliteWork) CommentRepo() taskDomain.CommentRepository { return w.comments }
+func (w *sqliteWork) RevisionRepo() taskDomain.RevisionRepository {
+	return w.revisions
+}
+func (w *sqliteWork) ReminderRepo() taskDomain.ReminderRepository {
+	return w.reminders
+}
+func (w *sqliteWork) UndoRepo() undoDomain.Repository { return w.undos }
+func (w *sqliteWork) AttachmentRepo() taskDomain.AttachmentRepository {
+	return w.attachments
+}
+func (w *sqliteWork) ShareRepo() taskDomain.ShareRepository     { return w.shares }
+func (w *sqliteWork) WatcherRepo() taskDomain.WatcherRepository { return w.watchers }
+func (w *sqliteWork) WorkspaceRepo() workspaceDomain.WorkspaceRepository {
+	return w.workspaces
+}
+func (w *sqliteWork) InvitationRepo() workspaceDomain.InvitationRepository {
+	return w.invitations
+}
+func (w *sqliteWork) RoleRepo() policy.RoleStore        { return w.roles }
+func (w *sqliteWork) AuditRepo() auditDomain.Repository { return w.audit }
+func (w *sqliteWork) NotificationPreferenceRepo() notificationDomain.PreferenceRepository {
+	return w.preferences
+}
+func (w *sqliteWork) InboxRepo() notificationDomain.InboxRepository { return w.inbox }
+
+func (w *sqliteWork) Savepoint(ctx context.Context, fn func(ctx context.Context) error) error {
+	w.savepoints++
+	name := fmt.Sprintf("sp%d", w.savepoints)
+	if _, err := w.tx.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+		return err
+	}
+
+	// Eventos gravados no savepoint desfeito também não são publicados.
+	appended := len(w.events.appended)
+	if err := fn(ctx); err != nil {
+		if _, rbErr := w.tx.ExecContext(ctx, "ROLLBACK TO "+name); rbErr != nil {
+			return fmt.Errorf("savepoint err: %v, rb err: %v", err, rbErr)
+		}
+		w.events.appended = w.events.appended[:appended]
+		if _, relErr := w.tx.ExecContext(ctx, "RELEASE "+name); relErr != nil {
+			return fmt.Errorf("savepoint err: %v, release err: %v", err, relErr)
+		}
+		return err
+	}
+	_, err := w.tx.ExecContext(ctx, "RELEASE "+name)
+	return err
+}
 
 type SQLiteUnitOfWork struct {
-	db *sql.DB
+	db        *sql.DB
+	publisher taskDomain.EventPublisher
 }
 
-func NewSQLiteUnitOfWork(db *sql.DB) domain.UnitOfWork {
-	return &SQLiteUnitOfWork{db: db}
+// NewSQLiteUnitOfWork cria a unidade de trabalho. Se publisher não for nil,
+// recebe os eventos de tarefa registrados em cada transação confirmada.
+func NewSQLiteUnitOfWork(db *sql.DB, publisher taskDomain.EventPublisher) domain.UnitOfWork {
+	return &SQLiteUnitOfWork{db: db, publisher: publisher}
 }
 
-func (uow *SQLiteUnitOfWork) Execute(ctx context.Context, fn func(work domain.Work) error) error {
+func (uow *SQLiteUnitOfWork) Execute(ctx context.Context, fn func(ctx context.Context, work domain.Work) error) (err error) {
+	ctx, span := tracer.Start(ctx, "sqlite.transaction")
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+
 	tx, err := uow.db.BeginTx(ctx, nil)
 	if err != nil {
-		slog.Error("error on begin tx", "error", err)
+		logging.ForPackage(ctx, "sqlite").Error("error on begin tx", "error", err)
 		return err
 	}
 	defer func() {
@@ -42,17 +124,38 @@ func (uow *SQLiteUnitOfWork) Execute(ctx context.Context, fn func(work domain.Wo
 	}()
 
 	work := &sqliteWork{
-		tx:       tx,
-		userRepo: NewSQLiteUserRepository(uow.db).WithTx(tx),
-		taskRepo: NewSQLiteTaskRepository(uow.db).WithTx(tx),
+		tx:          tx,
+		userRepo:    NewSQLiteUserRepository(uow.db).WithTx(tx),
+		taskRepo:    NewSQLiteTaskRepository(uow.db).WithTx(tx),
+		events:      NewSQLiteTaskEventRepository(uow.db).WithTx(tx),
+		comments:    NewSQLiteCommentRepository(uow.db).WithTx(tx),
+		revisions:   NewSQLiteRevisionRepository(uow.db).WithTx(tx),
+		reminders:   NewSQLiteReminderRepository(uow.db).WithTx(tx),
+		undos:       NewSQLiteUndoRepository(uow.db).WithTx(tx),
+		attachments: NewSQLiteAttachmentRepository(uow.db).WithTx(tx),
+		shares:      NewSQLiteShareRepository(uow.db).WithTx(tx),
+		watchers:    NewSQLiteWatcherRepository(uow.db).WithTx(tx),
+		workspaces:  NewSQLiteWorkspaceRepository(uow.db).WithTx(tx),
+		invitations: NewSQLiteInvitationRepository(uow.db).WithTx(tx),
+		roles:       NewSQLiteRoleStore(uow.db).WithTx(tx),
+		audit:       NewSQLiteAuditRepository(uow.db).WithTx(tx),
+		preferences: NewSQLiteNotificationPreferenceRepository(uow.db).WithTx(tx),
+		inbox:       NewSQLiteNotificationRepository(uow.db).WithTx(tx),
 	}
 
-	if err := fn(work); err != nil {
+	if err := fn(ctx, work); err != nil {
 		if rbErr := tx.Rollback(); rbErr != nil {
 			return fmt.Errorf("tx err: %v, rb err: %v", err, rbErr)
 		}
 		return err
 	}
 
-	return tx.Commit()
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	if uow.publisher != nil && len(work.events.appended) > 0 {
+		uow.publisher.Publish(work.events.appended)
+	}
+	return nil
 }
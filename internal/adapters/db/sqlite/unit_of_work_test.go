@@ -0,0 +1,111 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/hoyci/todo-ddd/pkg/domain"
+	taskDomain "github.com/hoyci/todo-ddd/pkg/domain/task"
+	"github.com/hoyci/todo-ddd/pkg/domain/valueobject"
+)
+
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	// Como em InitDB, a conexão aguarda o lock em vez de falhar.
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "test.db")+"?_pragma=busy_timeout(5000)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := initSchema(db); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+type recordingPublisher struct{ events []taskDomain.Event }
+
+func (p *recordingPublisher) Publish(events []taskDomain.Event) {
+	p.events = append(p.events, events...)
+}
+
+func TestSavepoint(t *testing.T) {
+	db := openTestDB(t)
+	publisher := &recordingPublisher{}
+	uow := NewSQLiteUnitOfWork(db, publisher)
+	errHalfway := errors.New("failed after writing")
+
+	// save grava a tarefa e o evento dela, como os casos de uso fazem.
+	save := func(ctx context.Context, work domain.Work, title string) error {
+		task, err := taskDomain.NewTask(title, "", "u1", valueobject.Priority(1))
+		if err != nil {
+			return err
+		}
+		if err := work.TaskRepo().Save(ctx, task); err != nil {
+			return err
+		}
+		return work.TaskEventRepo().Append(ctx, task.PullEvents())
+	}
+
+	tests := []struct {
+		title string
+		fail  bool
+	}{
+		{"kept before", false},
+		{"rolled back", true},
+		{"kept after", false},
+	}
+	err := uow.Execute(context.Background(), func(ctx context.Context, work domain.Work) error {
+		for _, tt := range tests {
+			err := work.Savepoint(ctx, func(ctx context.Context) error {
+				if err := save(ctx, work, tt.title); err != nil {
+					return err
+				}
+				if tt.fail {
+					return errHalfway
+				}
+				return nil
+			})
+			if tt.fail != errors.Is(err, errHalfway) {
+				t.Errorf("Savepoint(%q) error = %v", tt.title, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var titles []string
+	rows, err := db.Query(`SELECT title FROM tasks ORDER BY created_at`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var title string
+		if err := rows.Scan(&title); err != nil {
+			t.Fatal(err)
+		}
+		titles = append(titles, title)
+	}
+	if len(titles) != 2 || titles[0] != "kept before" || titles[1] != "kept after" {
+		t.Errorf("stored tasks = %v, want the two kept ones", titles)
+	}
+
+	var logged int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM task_events`).Scan(&logged); err != nil {
+		t.Fatal(err)
+	}
+	if logged != 2 || len(publisher.events) != 2 {
+		t.Errorf("events logged = %d, published = %d, want 2 and 2", logged, len(publisher.events))
+	}
+	for _, event := range publisher.events {
+		if event.Task.Title == "rolled back" {
+			t.Error("an event from the rolled back savepoint was published")
+		}
+	}
+}
@@ -1,7 +1,9 @@
 package sqlite
 
 import (
+	"context"
 	"database/sql"
+	"strings"
 	"time"
 
 	domain "github.com/hoyci/todo-ddd/pkg/domain/user"
@@ -23,53 +25,87 @@ func (r *SQLiteUserRepository) WithTx(tx *sql.Tx) *SQLiteUserRepository {
 
 func (r *SQLiteUserRepository) getExecutor() SQLExecutor {
 	if r.tx != nil {
-		return r.tx
+		return traced(r.tx)
 	}
 
-	return r.db
+	return traced(r.db)
+}
+
+const userColumns = `id, name, email, verified_at, verification_sent_at, created_at, updated_at, deleted_at`
+
+func scanUser(row rowScanner) (*domain.User, error) {
+	u := &domain.User{}
+	if err := row.Scan(&u.ID, &u.Name, &u.Email, &u.VerifiedAt, &u.VerificationSentAt, &u.CreatedAt, &u.UpdatedAt, &u.DeletedAt); err != nil {
+		return nil, err
+	}
+	return u, nil
 }
 
 // ------------------- CREATE -------------------
-func (r *SQLiteUserRepository) Save(user domain.User) error {
-	_, err := r.getExecutor().Exec(`
-		INSERT INTO users (id, name, email, created_at, updated_at, deleted_at)
-		VALUES (?, ?, ?, ?, ?, ?)`,
-		user.ID, user.Name, user.Email, user.CreatedAt, user.UpdatedAt, user.DeletedAt)
+func (r *SQLiteUserRepository) Save(ctx context.Context, user domain.User) error {
+	_, err := r.getExecutor().ExecContext(ctx, `
+		INSERT INTO users (`+userColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		user.ID, user.Name, user.Email, user.VerifiedAt, user.VerificationSentAt, user.CreatedAt, user.UpdatedAt, user.DeletedAt)
 	return err
 }
 
 // ------------------- READ -------------------
-func (r *SQLiteUserRepository) FindByID(id string) (*domain.User, error) {
-	row := r.getExecutor().QueryRow(`
-		SELECT id, name, email, created_at, updated_at, deleted_at 
+func (r *SQLiteUserRepository) FindByID(ctx context.Context, id string) (*domain.User, error) {
+	row := r.getExecutor().QueryRowContext(ctx, `
+		SELECT `+userColumns+`
 		FROM users WHERE id = ?`, id)
-
-	u := &domain.User{}
-	err := row.Scan(&u.ID, &u.Name, &u.Email, &u.CreatedAt, &u.UpdatedAt, &u.DeletedAt)
-	if err != nil {
-		return nil, err
-	}
-	return u, nil
+	return scanUser(row)
 }
 
-func (r *SQLiteUserRepository) FindByEmail(email string) (*domain.User, error) {
-	row := r.getExecutor().QueryRow(`
-		SELECT id, name, email, created_at, updated_at, deleted_at 
+func (r *SQLiteUserRepository) FindByEmail(ctx context.Context, email string) (*domain.User, error) {
+	row := r.getExecutor().QueryRowContext(ctx, `
+		SELECT `+userColumns+`
 		FROM users WHERE email = ?`, email)
+	return scanUser(row)
+}
 
-	u := &domain.User{}
-	err := row.Scan(&u.ID, &u.Name, &u.Email, &u.CreatedAt, &u.UpdatedAt, &u.DeletedAt)
+func (r *SQLiteUserRepository) FindByIDs(ctx context.Context, ids []string) ([]*domain.User, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	rows, err := r.getExecutor().QueryContext(ctx, `
+		SELECT `+userColumns+`
+		FROM users WHERE id IN (`+placeholders(len(ids))+`) AND deleted_at IS NULL`, args...)
 	if err != nil {
 		return nil, err
 	}
-	return u, nil
+	defer rows.Close()
+
+	var users []*domain.User
+	for rows.Next() {
+		u, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, u)
+	}
+	return users, rows.Err()
 }
 
 // ------------------- LIST -------------------
-func (r *SQLiteUserRepository) List() ([]*domain.User, error) {
-	rows, err := r.getExecutor().Query(`
-		SELECT id, name, email, created_at, updated_at, deleted_at 
-		FROM users WHERE deleted_at IS NULL`)
+func (r *SQLiteUserRepository) ListPage(ctx context.Context, query domain.UserPageQuery) ([]*domain.User, error) {
+	where, args := userSearchWhere(query.Search)
+	if query.AfterID != "" {
+		where += ` AND (created_at, id) > (SELECT created_at, id FROM users WHERE id = ?)`
+		args = append(args, query.AfterID)
+	}
+	args = append(args, query.Limit)
+
+	rows, err := r.getExecutor().QueryContext(ctx, `
+		SELECT `+userColumns+`
+		FROM users WHERE `+where+`
+		ORDER BY created_at, id LIMIT ?`, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -77,28 +113,45 @@ func (r *SQLiteUserRepository) List() ([]*domain.User, error) {
 
 	var users []*domain.User
 	for rows.Next() {
-		u := &domain.User{}
-		if err := rows.Scan(&u.ID, &u.Name, &u.Email, &u.CreatedAt, &u.UpdatedAt, &u.DeletedAt); err != nil {
+		u, err := scanUser(rows)
+		if err != nil {
 			return nil, err
 		}
 		users = append(users, u)
 	}
-	return users, nil
+	return users, rows.Err()
+}
+
+func (r *SQLiteUserRepository) Count(ctx context.Context, search string) (int, error) {
+	where, args := userSearchWhere(search)
+	var count int
+	err := r.getExecutor().QueryRowContext(ctx, `SELECT COUNT(*) FROM users WHERE `+where, args...).Scan(&count)
+	return count, err
+}
+
+// userSearchWhere seleciona os usuários ativos cujo nome ou email contém
+// search, sem diferenciar maiúsculas.
+func userSearchWhere(search string) (string, []any) {
+	if search == "" {
+		return `deleted_at IS NULL`, nil
+	}
+	search = strings.ToLower(search)
+	return `deleted_at IS NULL AND (instr(lower(name), ?) > 0 OR instr(lower(email), ?) > 0)`, []any{search, search}
 }
 
 // ------------------- UPDATE -------------------
-func (r *SQLiteUserRepository) Update(user domain.User) error {
-	_, err := r.getExecutor().Exec(`
+func (r *SQLiteUserRepository) Update(ctx context.Context, user domain.User) error {
+	_, err := r.getExecutor().ExecContext(ctx, `
 		UPDATE users 
-		SET name = ?, email = ?, updated_at = ?
+		SET name = ?, email = ?, verified_at = ?, verification_sent_at = ?, updated_at = ?
 		WHERE id = ? AND deleted_at IS NULL`,
-		user.Name, user.Email, user.UpdatedAt, user.ID)
+		user.Name, user.Email, user.VerifiedAt, user.VerificationSentAt, user.UpdatedAt, user.ID)
 	return err
 }
 
 // ------------------- DELETE (SOFT) -------------------
-func (r *SQLiteUserRepository) Delete(id string, timestamp time.Time) error {
-	_, err := r.getExecutor().Exec(`
+func (r *SQLiteUserRepository) Delete(ctx context.Context, id string, timestamp time.Time) error {
+	_, err := r.getExecutor().ExecContext(ctx, `
 		UPDATE users 
 		SET deleted_at = ?
 		WHERE id = ? AND deleted_at IS NULL`,
@@ -0,0 +1,76 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	domain "github.com/hoyci/todo-ddd/pkg/domain/task"
+)
+
+type SQLiteWatcherRepository struct {
+	db *sql.DB
+	tx *sql.Tx
+}
+
+func NewSQLiteWatcherRepository(db *sql.DB) *SQLiteWatcherRepository {
+	return &SQLiteWatcherRepository{db: db}
+}
+
+func (r *SQLiteWatcherRepository) WithTx(tx *sql.Tx) *SQLiteWatcherRepository {
+	return &SQLiteWatcherRepository{tx: tx}
+}
+
+func (r *SQLiteWatcherRepository) getExecutor() SQLExecutor {
+	if r.tx != nil {
+		return traced(r.tx)
+	}
+	return traced(r.db)
+}
+
+func (r *SQLiteWatcherRepository) Add(ctx context.Context, watcher *domain.Watcher) error {
+	_, err := r.getExecutor().ExecContext(ctx, `
+		INSERT INTO task_watchers (task_id, user_id, created_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT (task_id, user_id) DO NOTHING`,
+		watcher.TaskID, watcher.UserID, watcher.CreatedAt)
+	return err
+}
+
+func (r *SQLiteWatcherRepository) Remove(ctx context.Context, taskID, userID string) error {
+	_, err := r.getExecutor().ExecContext(ctx, `DELETE FROM task_watchers WHERE task_id = ? AND user_id = ?`, taskID, userID)
+	return err
+}
+
+func (r *SQLiteWatcherRepository) ListByTask(ctx context.Context, taskID string) ([]*domain.Watcher, error) {
+	rows, err := r.getExecutor().QueryContext(ctx, `
+		SELECT task_id, user_id, created_at
+		FROM task_watchers
+		WHERE task_id = ?
+		ORDER BY created_at, user_id`, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var watchers []*domain.Watcher
+	for rows.Next() {
+		var w domain.Watcher
+		if err := rows.Scan(&w.TaskID, &w.UserID, &w.CreatedAt); err != nil {
+			return nil, err
+		}
+		watchers = append(watchers, &w)
+	}
+	return watchers, rows.Err()
+}
+
+func (r *SQLiteWatcherRepository) DeleteByTasks(ctx context.Context, taskIDs []string) error {
+	if len(taskIDs) == 0 {
+		return nil
+	}
+	args := make([]any, len(taskIDs))
+	for i, id := range taskIDs {
+		args[i] = id
+	}
+	_, err := r.getExecutor().ExecContext(ctx, `DELETE FROM task_watchers WHERE task_id IN (`+placeholders(len(taskIDs))+`)`, args...)
+	return err
+}
@@ -0,0 +1,145 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	domain "github.com/hoyci/todo-ddd/pkg/domain/workspace"
+)
+
+type SQLiteWorkspaceRepository struct {
+	db *sql.DB
+	tx *sql.Tx
+}
+
+func NewSQLiteWorkspaceRepository(db *sql.DB) *SQLiteWorkspaceRepository {
+	return &SQLiteWorkspaceRepository{db: db}
+}
+
+func (r *SQLiteWorkspaceRepository) WithTx(tx *sql.Tx) *SQLiteWorkspaceRepository {
+	return &SQLiteWorkspaceRepository{tx: tx}
+}
+
+func (r *SQLiteWorkspaceRepository) getExecutor() SQLExecutor {
+	if r.tx != nil {
+		return traced(r.tx)
+	}
+	return traced(r.db)
+}
+
+const (
+	workspaceColumns = `w.id, w.name, w.owner_id, w.created_at, w.updated_at, w.deleted_at`
+	memberColumns    = `workspace_id, user_id, role, joined_at`
+)
+
+func scanWorkspace(row rowScanner) (*domain.Workspace, error) {
+	var w domain.Workspace
+	if err := row.Scan(&w.ID, &w.Name, &w.OwnerID, &w.CreatedAt, &w.UpdatedAt, &w.DeletedAt); err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+func scanMember(row rowScanner) (*domain.Member, error) {
+	var m domain.Member
+	if err := row.Scan(&m.WorkspaceID, &m.UserID, &m.Role, &m.JoinedAt); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (r *SQLiteWorkspaceRepository) Save(ctx context.Context, workspace *domain.Workspace) error {
+	_, err := r.getExecutor().ExecContext(ctx, `
+		INSERT INTO workspaces (id, name, owner_id, created_at, updated_at, deleted_at)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		workspace.ID, workspace.Name, workspace.OwnerID, workspace.CreatedAt, workspace.UpdatedAt, workspace.DeletedAt)
+	return err
+}
+
+func (r *SQLiteWorkspaceRepository) Update(ctx context.Context, workspace *domain.Workspace) error {
+	_, err := r.getExecutor().ExecContext(ctx, `
+		UPDATE workspaces
+		SET name = ?,
+			updated_at = ?,
+			deleted_at = ?
+		WHERE id = ? AND deleted_at IS NULL`,
+		workspace.Name, workspace.UpdatedAt, workspace.DeletedAt, workspace.ID)
+	return err
+}
+
+func (r *SQLiteWorkspaceRepository) FindByID(ctx context.Context, id string) (*domain.Workspace, error) {
+	row := r.getExecutor().QueryRowContext(ctx, `
+		SELECT `+workspaceColumns+` FROM workspaces w WHERE w.id = ? AND w.deleted_at IS NULL`, id)
+	return scanWorkspace(row)
+}
+
+func (r *SQLiteWorkspaceRepository) ListByMember(ctx context.Context, userID string) ([]*domain.Workspace, error) {
+	rows, err := r.getExecutor().QueryContext(ctx, `
+		SELECT `+workspaceColumns+`
+		FROM workspaces w
+		JOIN workspace_members m ON m.workspace_id = w.id
+		WHERE m.user_id = ? AND w.deleted_at IS NULL
+		ORDER BY w.created_at, w.id`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var workspaces []*domain.Workspace
+	for rows.Next() {
+		w, err := scanWorkspace(rows)
+		if err != nil {
+			return nil, err
+		}
+		workspaces = append(workspaces, w)
+	}
+	return workspaces, rows.Err()
+}
+
+// SaveMember mantém joined_at de uma participação existente e troca só o
+// papel.
+func (r *SQLiteWorkspaceRepository) SaveMember(ctx context.Context, member *domain.Member) error {
+	_, err := r.getExecutor().ExecContext(ctx, `
+		INSERT INTO workspace_members (workspace_id, user_id, role, joined_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT (workspace_id, user_id) DO UPDATE SET role = excluded.role`,
+		member.WorkspaceID, member.UserID, member.Role, member.JoinedAt)
+	return err
+}
+
+// FindMember ignora participações em workspaces excluídos, para que o
+// papel deixe de valer junto com o workspace.
+func (r *SQLiteWorkspaceRepository) FindMember(ctx context.Context, workspaceID, userID string) (*domain.Member, error) {
+	row := r.getExecutor().QueryRowContext(ctx, `
+		SELECT `+memberColumns+`
+		FROM active_workspace_members
+		WHERE workspace_id = ? AND user_id = ?`, workspaceID, userID)
+	return scanMember(row)
+}
+
+func (r *SQLiteWorkspaceRepository) ListMembers(ctx context.Context, workspaceID string) ([]*domain.Member, error) {
+	rows, err := r.getExecutor().QueryContext(ctx, `
+		SELECT `+memberColumns+`
+		FROM workspace_members
+		WHERE workspace_id = ?
+		ORDER BY joined_at, user_id`, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var members []*domain.Member
+	for rows.Next() {
+		m, err := scanMember(rows)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, m)
+	}
+	return members, rows.Err()
+}
+
+func (r *SQLiteWorkspaceRepository) RemoveMember(ctx context.Context, workspaceID, userID string) error {
+	_, err := r.getExecutor().ExecContext(ctx, `DELETE FROM workspace_members WHERE workspace_id = ? AND user_id = ?`, workspaceID, userID)
+	return err
+}
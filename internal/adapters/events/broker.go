@@ -0,0 +1,102 @@
+package events
+
+import (
+	"errors"
+	"sync"
+
+	domain "github.com/hoyci/todo-ddd/pkg/domain/task"
+)
+
+var ErrTooManySubscriptions = errors.New("too many event streams open for this user")
+
+// Broker avisa as conexões abertas de cada usuário que há eventos novos no
+// log. O aviso não carrega os eventos: cada conexão lê o log a partir do
+// último ID entregue, então um assinante lento nunca bloqueia a publicação.
+type Broker struct {
+	mu          sync.Mutex
+	subscribers map[string]map[*Subscription]struct{}
+	maxPerUser  int
+}
+
+// NewBroker cria o broker. maxPerUser limita as conexões simultâneas de um
+// mesmo usuário; zero desativa o limite.
+func NewBroker(maxPerUser int) *Broker {
+	return &Broker{
+		subscribers: make(map[string]map[*Subscription]struct{}),
+		maxPerUser:  maxPerUser,
+	}
+}
+
+type Subscription struct {
+	broker *Broker
+	userID string
+	notify chan struct{}
+	once   sync.Once
+}
+
+// C recebe um valor sempre que houver eventos novos. Avisos consecutivos
+// são agrupados em um só.
+func (s *Subscription) C() <-chan struct{} {
+	return s.notify
+}
+
+func (s *Subscription) Close() {
+	s.once.Do(func() { s.broker.unsubscribe(s) })
+}
+
+func (b *Broker) Subscribe(userID string) (*Subscription, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	subs := b.subscribers[userID]
+	if b.maxPerUser > 0 && len(subs) >= b.maxPerUser {
+		return nil, ErrTooManySubscriptions
+	}
+	if subs == nil {
+		subs = make(map[*Subscription]struct{})
+		b.subscribers[userID] = subs
+	}
+
+	sub := &Subscription{broker: b, userID: userID, notify: make(chan struct{}, 1)}
+	subs[sub] = struct{}{}
+	return sub, nil
+}
+
+func (b *Broker) unsubscribe(sub *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	subs := b.subscribers[sub.userID]
+	delete(subs, sub)
+	if len(subs) == 0 {
+		delete(b.subscribers, sub.userID)
+	}
+}
+
+func (b *Broker) Publish(events []domain.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	notified := make(map[string]bool)
+	for _, event := range events {
+		b.notify(notified, event.UserID)
+		for _, watcherID := range event.WatcherIDs {
+			b.notify(notified, watcherID)
+		}
+	}
+}
+
+// notify avisa as conexões do usuário, uma vez por publicação.
+func (b *Broker) notify(notified map[string]bool, userID string) {
+	if notified[userID] {
+		return
+	}
+	notified[userID] = true
+
+	for sub := range b.subscribers[userID] {
+		select {
+		case sub.notify <- struct{}{}:
+		default:
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package events
+
+import (
+	"slices"
+	"sync"
+)
+
+// Presence registra quem está visualizando cada canal e avisa os
+// participantes quando a lista muda.
+type Presence struct {
+	mu       sync.Mutex
+	viewers  map[string]map[string]int // canal -> usuário -> conexões
+	watchers map[string]map[chan struct{}]struct{}
+}
+
+func NewPresence() *Presence {
+	return &Presence{
+		viewers:  make(map[string]map[string]int),
+		watchers: make(map[string]map[chan struct{}]struct{}),
+	}
+}
+
+// Join registra o usuário no canal. O canal devolvido recebe um valor a
+// cada mudança na lista de visualizadores, inclusive a própria entrada;
+// leave desfaz o registro.
+func (p *Presence) Join(channel, userID string) (updates <-chan struct{}, leave func()) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.viewers[channel] == nil {
+		p.viewers[channel] = make(map[string]int)
+		p.watchers[channel] = make(map[chan struct{}]struct{})
+	}
+
+	notify := make(chan struct{}, 1)
+	p.watchers[channel][notify] = struct{}{}
+	p.viewers[channel][userID]++
+	p.broadcast(channel)
+
+	var once sync.Once
+	return notify, func() {
+		once.Do(func() {
+			p.mu.Lock()
+			defer p.mu.Unlock()
+
+			delete(p.watchers[channel], notify)
+			if p.viewers[channel][userID]--; p.viewers[channel][userID] == 0 {
+				delete(p.viewers[channel], userID)
+				p.broadcast(channel)
+			}
+			if len(p.watchers[channel]) == 0 {
+				delete(p.watchers, channel)
+				delete(p.viewers, channel)
+			}
+		})
+	}
+}
+
+// Viewers devolve, em ordem, os usuários que visualizam o canal.
+func (p *Presence) Viewers(channel string) []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	viewers := make([]string, 0, len(p.viewers[channel]))
+	for userID := range p.viewers[channel] {
+		viewers = append(viewers, userID)
+	}
+	slices.Sort(viewers)
+	return viewers
+}
+
+func (p *Presence) broadcast(channel string) {
+	for notify := range p.watchers[channel] {
+		select {
+		case notify <- struct{}{}:
+		default:
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	domainTask "github.com/hoyci/todo-ddd/pkg/domain/task"
+)
+
+// csvColumns define, na ordem padrão, as colunas aceitas em columns.
+var csvColumns = []struct {
+	name  string
+	value func(t *domainTask.Task) string
+}{
+	{"id", func(t *domainTask.Task) string { return t.ID }},
+	{"title", func(t *domainTask.Task) string { return t.Title }},
+	{"description", func(t *domainTask.Task) string { return t.Description }},
+	{"priority", func(t *domainTask.Task) string { return strconv.Itoa(int(t.Priority)) }},
+	{"status", func(t *domainTask.Task) string { return string(t.Status) }},
+	{"due_at", func(t *domainTask.Task) string { return formatTime(t.DueAt) }},
+	{"tags", func(t *domainTask.Task) string { return strings.Join(tagStrings(t.Tags), " ") }},
+	{"recurrence", func(t *domainTask.Task) string { return t.Recurrence.String() }},
+	{"created_at", func(t *domainTask.Task) string { return formatTime(&t.CreatedAt) }},
+	{"updated_at", func(t *domainTask.Task) string { return formatTime(t.UpdatedAt) }},
+	{"deleted_at", func(t *domainTask.Task) string { return formatTime(t.DeletedAt) }},
+}
+
+// csvFormulaPrefixes são os caracteres que fazem planilhas interpretarem a
+// célula como fórmula.
+const csvFormulaPrefixes = "=+-@\t\r"
+
+// escapeFormula antepõe um apóstrofo a células que seriam lidas como
+// fórmula, para que um título como =HYPERLINK(...) chegue à planilha como
+// texto.
+func escapeFormula(value string) string {
+	if value != "" && strings.ContainsRune(csvFormulaPrefixes, rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+type UnknownColumnError struct {
+	Column string
+}
+
+func (e *UnknownColumnError) Error() string {
+	return fmt.Sprintf("unknown column %q", e.Column)
+}
+
+type CSVEncoder struct {
+	w       *csv.Writer
+	values  []func(t *domainTask.Task) string
+	header  []string
+	started bool
+}
+
+func NewCSVEncoder(w io.Writer, columns []string) (*CSVEncoder, error) {
+	enc := &CSVEncoder{w: csv.NewWriter(w)}
+	if len(columns) == 0 {
+		for _, col := range csvColumns {
+			columns = append(columns, col.name)
+		}
+	}
+
+	for _, name := range columns {
+		found := false
+		for _, col := range csvColumns {
+			if col.name == name {
+				enc.header = append(enc.header, col.name)
+				enc.values = append(enc.values, col.value)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, &UnknownColumnError{Column: name}
+		}
+	}
+	return enc, nil
+}
+
+func (e *CSVEncoder) writeHeader() error {
+	if e.started {
+		return nil
+	}
+	e.started = true
+	return e.w.Write(e.header)
+}
+
+func (e *CSVEncoder) Encode(t *domainTask.Task) error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+
+	record := make([]string, len(e.values))
+	for i, value := range e.values {
+		record[i] = escapeFormula(value(t))
+	}
+	return e.w.Write(record)
+}
+
+func (e *CSVEncoder) Close() error {
+	// Sem tarefas, o arquivo ainda leva o cabeçalho.
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+	e.w.Flush()
+	return e.w.Error()
+}
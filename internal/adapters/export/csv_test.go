@@ -0,0 +1,88 @@
+package export
+
+import (
+	"encoding/csv"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	domainTask "github.com/hoyci/todo-ddd/pkg/domain/task"
+	"github.com/hoyci/todo-ddd/pkg/domain/valueobject"
+)
+
+func TestCSVEncoderEscapesFormulas(t *testing.T) {
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{"=HYPERLINK(\"http://evil.example\")", "'=HYPERLINK(\"http://evil.example\")"},
+		{"+1+2", "'+1+2"},
+		{"-2+3", "'-2+3"},
+		{"@SUM(A1)", "'@SUM(A1)"},
+		{"\tcmd", "'\tcmd"},
+		{"\rcmd", "'\rcmd"},
+		{"Buy milk", "Buy milk"},
+		{"a=b", "a=b"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		var b strings.Builder
+		enc, err := NewCSVEncoder(&b, []string{"title", "description", "priority"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		task := &domainTask.Task{Title: tt.title, Description: tt.title, Priority: valueobject.High}
+		if err := enc.Encode(task); err != nil {
+			t.Fatal(err)
+		}
+		if err := enc.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		records, err := csv.NewReader(strings.NewReader(b.String())).ReadAll()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(records) != 2 {
+			t.Fatalf("title %q: got %d records, want header and one row", tt.title, len(records))
+		}
+		if got := records[1]; got[0] != tt.want || got[1] != tt.want || got[2] != "3" {
+			t.Errorf("title %q: row = %q, want %q in title and description", tt.title, got, tt.want)
+		}
+	}
+}
+
+func TestCSVEncoderColumns(t *testing.T) {
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	task := &domainTask.Task{ID: "t1", Title: "Report", Status: valueobject.StatusNew, CreatedAt: created}
+
+	var b strings.Builder
+	enc, err := NewCSVEncoder(&b, []string{"status", "id", "created_at"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := enc.Encode(task); err != nil {
+		t.Fatal(err)
+	}
+	if err := enc.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if want := "status,id,created_at\nnew,t1,2024-05-01T12:00:00Z\n"; b.String() != want {
+		t.Errorf("csv = %q, want %q", b.String(), want)
+	}
+
+	var empty strings.Builder
+	enc, err = NewCSVEncoder(&empty, []string{"title"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := enc.Close(); err != nil || empty.String() != "title\n" {
+		t.Errorf("empty export = %q, %v; want only the header", empty.String(), err)
+	}
+
+	var unknown *UnknownColumnError
+	if _, err := NewCSVEncoder(&b, []string{"title", "owner"}); !errors.As(err, &unknown) || unknown.Column != "owner" {
+		t.Errorf("unknown column: error = %v, want UnknownColumnError for owner", err)
+	}
+}
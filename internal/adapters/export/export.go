@@ -0,0 +1,71 @@
+package export
+
+import (
+	"errors"
+	"io"
+	"time"
+
+	"github.com/hoyci/todo-ddd/pkg/domain/valueobject"
+	usecasetask "github.com/hoyci/todo-ddd/pkg/usecase/task"
+)
+
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatJSON Format = "json"
+	FormatICS  Format = "ics"
+)
+
+var ErrUnsupportedFormat = errors.New("format must be one of csv, json, ics")
+
+func ParseFormat(raw string) (Format, error) {
+	switch format := Format(raw); format {
+	case FormatCSV, FormatJSON, FormatICS:
+		return format, nil
+	default:
+		return "", ErrUnsupportedFormat
+	}
+}
+
+func (f Format) ContentType() string {
+	switch f {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatICS:
+		return "text/calendar; charset=utf-8"
+	default:
+		return "application/json; charset=utf-8"
+	}
+}
+
+// NewEncoder cria o encoder do formato. columns só vale para CSV; vazio
+// exporta todas as colunas.
+func NewEncoder(format Format, w io.Writer, columns []string) (usecasetask.TaskEncoder, error) {
+	switch format {
+	case FormatCSV:
+		return NewCSVEncoder(w, columns)
+	case FormatICS:
+		return NewICSEncoder(w, time.Now()), nil
+	case FormatJSON:
+		return NewJSONEncoder(w), nil
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
+
+func tagStrings(tags []valueobject.Tag) []string {
+	out := make([]string, len(tags))
+	for i, tag := range tags {
+		out[i] = string(tag)
+	}
+	return out
+}
+
+// formatTime é o formato de data usado em CSV e JSON.
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,131 @@
+package export
+
+import (
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	domainTask "github.com/hoyci/todo-ddd/pkg/domain/task"
+	"github.com/hoyci/todo-ddd/pkg/domain/valueobject"
+)
+
+const (
+	icsTimeFormat = "20060102T150405Z"
+	// icsLineLimit é o tamanho máximo de linha da RFC 5545, em octetos.
+	icsLineLimit = 75
+)
+
+var icsStatus = map[valueobject.Status]string{
+	valueobject.StatusNew:        "NEEDS-ACTION",
+	valueobject.StatusInProgress: "IN-PROCESS",
+	valueobject.StatusCompleted:  "COMPLETED",
+}
+
+// icsPriority mapeia a prioridade para a escala do iCalendar, em que 1 é a
+// mais alta e 9 a mais baixa.
+var icsPriority = map[valueobject.Priority]int{
+	valueobject.High:   1,
+	valueobject.Medium: 5,
+	valueobject.Low:    9,
+}
+
+var icsEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// ICSEncoder escreve cada tarefa como um VTODO. Sem DTSTART, os clientes
+// ancoram a RRULE no DUE, então ela só é emitida em tarefas com vencimento.
+type ICSEncoder struct {
+	w       io.Writer
+	stamp   string
+	started bool
+	err     error
+}
+
+func NewICSEncoder(w io.Writer, now time.Time) *ICSEncoder {
+	return &ICSEncoder{w: w, stamp: now.UTC().Format(icsTimeFormat)}
+}
+
+func (e *ICSEncoder) begin() {
+	if e.started {
+		return
+	}
+	e.started = true
+	e.line("BEGIN:VCALENDAR")
+	e.line("VERSION:2.0")
+	e.line("PRODID:-//todo-ddd//Tasks//EN")
+}
+
+func (e *ICSEncoder) Encode(t *domainTask.Task) error {
+	e.begin()
+
+	e.line("BEGIN:VTODO")
+	e.line("UID:" + t.ID)
+	e.line("DTSTAMP:" + e.stamp)
+	e.line("CREATED:" + t.CreatedAt.UTC().Format(icsTimeFormat))
+	if t.UpdatedAt != nil {
+		e.line("LAST-MODIFIED:" + t.UpdatedAt.UTC().Format(icsTimeFormat))
+	}
+	e.line("SUMMARY:" + icsEscaper.Replace(t.Title))
+	if t.Description != "" {
+		e.line("DESCRIPTION:" + icsEscaper.Replace(t.Description))
+	}
+
+	status := icsStatus[t.Status]
+	if t.DeletedAt != nil {
+		status = "CANCELLED"
+	}
+	if status != "" {
+		e.line("STATUS:" + status)
+	}
+	if t.Status == valueobject.StatusCompleted {
+		e.line("PERCENT-COMPLETE:100")
+	}
+	if priority, ok := icsPriority[t.Priority]; ok {
+		e.line("PRIORITY:" + strconv.Itoa(priority))
+	}
+	if t.DueAt != nil {
+		e.line("DUE:" + t.DueAt.UTC().Format(icsTimeFormat))
+		if !t.Recurrence.IsZero() {
+			e.line("RRULE:" + t.Recurrence.String())
+		}
+	}
+	if len(t.Tags) > 0 {
+		e.line("CATEGORIES:" + strings.Join(tagStrings(t.Tags), ","))
+	}
+	e.line("END:VTODO")
+
+	return e.err
+}
+
+func (e *ICSEncoder) Close() error {
+	e.begin()
+	e.line("END:VCALENDAR")
+	return e.err
+}
+
+// line escreve uma linha terminada em CRLF, dobrando-a a cada 75 octetos
+// sem partir caracteres UTF-8.
+func (e *ICSEncoder) line(content string) {
+	if e.err != nil {
+		return
+	}
+
+	var b strings.Builder
+	limit := icsLineLimit
+	for len(content) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(content[cut]) {
+			cut--
+		}
+		b.WriteString(content[:cut])
+		b.WriteString("\r\n ")
+		content = content[cut:]
+		// A continuação começa com um espaço, que conta no limite.
+		limit = icsLineLimit - 1
+	}
+	b.WriteString(content)
+	b.WriteString("\r\n")
+
+	_, e.err = io.WriteString(e.w, b.String())
+}
@@ -0,0 +1,127 @@
+package export
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	domainTask "github.com/hoyci/todo-ddd/pkg/domain/task"
+	"github.com/hoyci/todo-ddd/pkg/domain/valueobject"
+)
+
+// encodeICS exporta as tarefas e devolve as linhas físicas, sem o CRLF.
+func encodeICS(t *testing.T, tasks ...*domainTask.Task) []string {
+	t.Helper()
+	var b strings.Builder
+	enc := NewICSEncoder(&b, time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+	for _, task := range tasks {
+		if err := enc.Encode(task); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := enc.Close(); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	if !strings.HasSuffix(out, "\r\n") {
+		t.Fatalf("output does not end with CRLF: %q", out)
+	}
+	lines := strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n")
+	for _, line := range lines {
+		if strings.ContainsAny(line, "\r\n") {
+			t.Fatalf("bare line break in %q", line)
+		}
+	}
+	return lines
+}
+
+// unfold junta as linhas de continuação, como faz um cliente iCalendar.
+func unfold(lines []string) []string {
+	var out []string
+	for _, line := range lines {
+		if strings.HasPrefix(line, " ") && len(out) > 0 {
+			out[len(out)-1] += line[1:]
+			continue
+		}
+		out = append(out, line)
+	}
+	return out
+}
+
+func property(lines []string, name string) (string, bool) {
+	for _, line := range lines {
+		if value, ok := strings.CutPrefix(line, name+":"); ok {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+func TestICSEncoderEscaping(t *testing.T) {
+	task := &domainTask.Task{
+		ID:          "t1",
+		Title:       `Call Ana, Bob; and C:\temp`,
+		Description: "first line\nsecond line\r\nthird",
+		Priority:    valueobject.High,
+		Status:      valueobject.StatusNew,
+	}
+	lines := unfold(encodeICS(t, task))
+
+	if got, _ := property(lines, "SUMMARY"); got != `Call Ana\, Bob\; and C:\\temp` {
+		t.Errorf("SUMMARY = %q", got)
+	}
+	if got, _ := property(lines, "DESCRIPTION"); got != `first line\nsecond line\nthird` {
+		t.Errorf("DESCRIPTION = %q", got)
+	}
+	if got, _ := property(lines, "PRIORITY"); got != "1" {
+		t.Errorf("PRIORITY = %q, want 1", got)
+	}
+	if lines[0] != "BEGIN:VCALENDAR" || lines[len(lines)-1] != "END:VCALENDAR" {
+		t.Errorf("calendar not wrapped in VCALENDAR: %q", lines)
+	}
+}
+
+func TestICSEncoderFolding(t *testing.T) {
+	tests := []struct {
+		name  string
+		title string
+	}{
+		{"ascii", strings.Repeat("abcdefghij", 20)},
+		// Caracteres de dois e três octetos não podem ser partidos na dobra.
+		{"multibyte", strings.Repeat("ação ", 40)},
+		{"cjk", strings.Repeat("任務", 60)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := encodeICS(t, &domainTask.Task{ID: "t1", Title: tt.title})
+
+			folded := 0
+			for _, line := range lines {
+				if len(line) > icsLineLimit {
+					t.Errorf("line of %d octets: %q", len(line), line)
+				}
+				if !utf8.ValidString(line) {
+					t.Errorf("line splits a UTF-8 character: %q", line)
+				}
+				if strings.HasPrefix(line, " ") {
+					folded++
+				}
+			}
+			if folded == 0 {
+				t.Error("long SUMMARY was not folded")
+			}
+			if got, _ := property(unfold(lines), "SUMMARY"); got != tt.title {
+				t.Errorf("unfolded SUMMARY = %q, want %q", got, tt.title)
+			}
+		})
+	}
+}
+
+func TestICSEncoderEmpty(t *testing.T) {
+	lines := encodeICS(t)
+	want := []string{"BEGIN:VCALENDAR", "VERSION:2.0", "PRODID:-//todo-ddd//Tasks//EN", "END:VCALENDAR"}
+	if strings.Join(lines, "|") != strings.Join(want, "|") {
+		t.Errorf("empty calendar = %q, want %q", lines, want)
+	}
+}
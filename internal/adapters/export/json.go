@@ -0,0 +1,82 @@
+package export
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	domainTask "github.com/hoyci/todo-ddd/pkg/domain/task"
+)
+
+// Task é o formato de cada item da exportação JSON, aceito de volta pela
+// importação.
+type Task struct {
+	ID          string     `json:"id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Priority    int        `json:"priority"`
+	Status      string     `json:"status"`
+	DueAt       *time.Time `json:"due_at,omitempty"`
+	Tags        []string   `json:"tags,omitempty"`
+	Recurrence  string     `json:"recurrence,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   *time.Time `json:"updated_at,omitempty"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
+}
+
+// JSONEncoder escreve um array JSON item a item, sem montar a lista em
+// memória.
+type JSONEncoder struct {
+	w     io.Writer
+	count int
+}
+
+func NewJSONEncoder(w io.Writer) *JSONEncoder {
+	return &JSONEncoder{w: w}
+}
+
+func (e *JSONEncoder) Encode(t *domainTask.Task) error {
+	sep := ",\n"
+	if e.count == 0 {
+		sep = "[\n"
+	}
+	item, err := json.Marshal(Task{
+		ID:          t.ID,
+		Title:       t.Title,
+		Description: t.Description,
+		Priority:    int(t.Priority),
+		Status:      string(t.Status),
+		DueAt:       utc(t.DueAt),
+		Tags:        tagStrings(t.Tags),
+		Recurrence:  t.Recurrence.String(),
+		CreatedAt:   t.CreatedAt.UTC(),
+		UpdatedAt:   utc(t.UpdatedAt),
+		DeletedAt:   utc(t.DeletedAt),
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(e.w, sep); err != nil {
+		return err
+	}
+	e.count++
+	_, err = e.w.Write(item)
+	return err
+}
+
+func (e *JSONEncoder) Close() error {
+	closing := "\n]\n"
+	if e.count == 0 {
+		closing = "[]\n"
+	}
+	_, err := io.WriteString(e.w, closing)
+	return err
+}
+
+func utc(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	u := t.UTC()
+	return &u
+}
@@ -0,0 +1,131 @@
+package graphql
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// Limits protegem o servidor de consultas caras. A complexidade soma 1 por
+// campo e multiplica o custo dos campos paginados pelo tamanho da página
+// pedida (ou DefaultPageSize).
+type Limits struct {
+	MaxDepth      int
+	MaxComplexity int
+}
+
+var ErrOperationNotFound = errors.New("operation not found")
+
+type analysis struct {
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]any
+}
+
+// analyze devolve a profundidade e a complexidade da operação escolhida.
+// Campos de introspecção (__schema, __type...) não contam.
+func analyze(doc *ast.Document, operationName string, variables map[string]any) (depth, complexity int, err error) {
+	a := analysis{fragments: make(map[string]*ast.FragmentDefinition), variables: variables}
+
+	var operations []*ast.OperationDefinition
+	for _, def := range doc.Definitions {
+		switch def := def.(type) {
+		case *ast.FragmentDefinition:
+			a.fragments[def.Name.Value] = def
+		case *ast.OperationDefinition:
+			if operationName == "" || (def.Name != nil && def.Name.Value == operationName) {
+				operations = append(operations, def)
+			}
+		}
+	}
+	if len(operations) != 1 {
+		if len(operations) > 1 {
+			return 0, 0, errors.New("operationName is required when the document has multiple operations")
+		}
+		return 0, 0, ErrOperationNotFound
+	}
+
+	depth, complexity = a.selectionSet(operations[0].SelectionSet, 0, map[string]bool{})
+	return depth, complexity, nil
+}
+
+func (a analysis) selectionSet(set *ast.SelectionSet, level int, visiting map[string]bool) (depth, complexity int) {
+	if set == nil {
+		return level, 0
+	}
+	depth = level
+
+	for _, selection := range set.Selections {
+		var d, c int
+		switch selection := selection.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(selection.Name.Value, "__") {
+				continue
+			}
+			d, c = a.selectionSet(selection.SelectionSet, level+1, visiting)
+			c = 1 + a.multiplier(selection)*c
+		case *ast.InlineFragment:
+			d, c = a.selectionSet(selection.SelectionSet, level, visiting)
+		case *ast.FragmentSpread:
+			name := selection.Name.Value
+			fragment, ok := a.fragments[name]
+			// Ciclos entre fragments são rejeitados pela validação do
+			// schema; aqui só evitamos a recursão infinita.
+			if !ok || visiting[name] {
+				continue
+			}
+			visiting[name] = true
+			d, c = a.selectionSet(fragment.SelectionSet, level, visiting)
+			delete(visiting, name)
+		}
+		depth = max(depth, d)
+		complexity += c
+	}
+	return depth, complexity
+}
+
+// multiplier é o tamanho da página de um campo paginado, ou 1.
+func (a analysis) multiplier(field *ast.Field) int {
+	if !paginatedFields[field.Name.Value] {
+		return 1
+	}
+	for _, arg := range field.Arguments {
+		if arg.Name.Value != "first" {
+			continue
+		}
+		switch value := arg.Value.(type) {
+		case *ast.IntValue:
+			if n, err := strconv.Atoi(value.Value); err == nil && n > 0 {
+				return n
+			}
+		case *ast.Variable:
+			if n, ok := intVariable(a.variables[value.Name.Value]); ok && n > 0 {
+				return n
+			}
+		}
+	}
+	return DefaultPageSize
+}
+
+func intVariable(v any) (int, bool) {
+	switch n := v.(type) {
+	case int:
+		return n, true
+	case float64:
+		return int(n), true
+	default:
+		return 0, false
+	}
+}
+
+func (l Limits) check(depth, complexity int) error {
+	if l.MaxDepth > 0 && depth > l.MaxDepth {
+		return fmt.Errorf("query depth %d exceeds the maximum of %d", depth, l.MaxDepth)
+	}
+	if l.MaxComplexity > 0 && complexity > l.MaxComplexity {
+		return fmt.Errorf("query complexity %d exceeds the maximum of %d", complexity, l.MaxComplexity)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql/language/parser"
+)
+
+func TestAnalyze(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		operationName  string
+		variables      map[string]any
+		wantDepth      int
+		wantComplexity int
+	}{
+		{"flat", `{ viewer { id name } }`, "", nil, 2, 3},
+		{"page size from first", `{ tasks(first: 10) { nodes { id } } }`, "", nil, 3, 21},
+		{"default page size", `{ tasks { nodes { id } } }`, "", nil, 3, 1 + DefaultPageSize*2},
+		{"page size from a variable", `query($n: Int) { tasks(first: $n) { nodes { id } } }`, "", map[string]any{"n": float64(5)}, 3, 11},
+		{"nested pages multiply", `{ users(first: 10) { nodes { tasks(first: 10) { nodes { id } } } } }`, "", nil, 5, 221},
+		{"fragment spread", `{ viewer { ...F } } fragment F on User { id name }`, "", nil, 2, 3},
+		{"inline fragment", `{ viewer { ... on User { id } } }`, "", nil, 2, 2},
+		{"introspection ignored", `{ __schema { types { name } } viewer { id } }`, "", nil, 2, 2},
+		{"named operation", `query A { viewer { id } } query B { tasks(first: 2) { nodes { id } } }`, "B", nil, 3, 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := parser.Parse(parser.ParseParams{Source: tt.query})
+			if err != nil {
+				t.Fatal(err)
+			}
+			depth, complexity, err := analyze(doc, tt.operationName, tt.variables)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if depth != tt.wantDepth || complexity != tt.wantComplexity {
+				t.Errorf("depth, complexity = %d, %d; want %d, %d", depth, complexity, tt.wantDepth, tt.wantComplexity)
+			}
+		})
+	}
+
+	doc, err := parser.Parse(parser.ParseParams{Source: `query A { viewer { id } } query B { viewer { id } }`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := analyze(doc, "", nil); err == nil {
+		t.Error("ambiguous document without operationName accepted")
+	}
+	if _, _, err := analyze(doc, "C", nil); !errors.Is(err, ErrOperationNotFound) {
+		t.Errorf("unknown operationName: error = %v, want %v", err, ErrOperationNotFound)
+	}
+}
+
+func TestServerLimits(t *testing.T) {
+	server, err := NewServer(&Resolver{}, Limits{MaxDepth: 4, MaxComplexity: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		query   string
+		wantErr string
+	}{
+		{"too deep", `{ tasks(first: 1) { nodes { user { tasks(first: 1) { totalCount } } } } }`, "query depth 5 exceeds the maximum of 4"},
+		{"too complex", `{ tasks(first: 50) { nodes { id title } } }`, "query complexity 151 exceeds the maximum of 100"},
+		{"too complex by default page size", `{ tasks { nodes { id title description status priority } } }`, "query complexity 121 exceeds"},
+		{"invalid against the schema", `{ tasks { nope } }`, "Cannot query field"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// A consulta é recusada antes de chegar aos resolvers, que não
+			// estão configurados.
+			_, err := server.Execute(context.Background(), "u1", Request{Query: tt.query})
+			var rejected *RejectedError
+			if !errors.As(err, &rejected) || !strings.Contains(rejected.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want a rejection containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
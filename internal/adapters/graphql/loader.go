@@ -0,0 +1,128 @@
+package graphql
+
+import (
+	"context"
+	"sync"
+
+	domainTask "github.com/hoyci/todo-ddd/pkg/domain/task"
+	domainUser "github.com/hoyci/todo-ddd/pkg/domain/user"
+	"github.com/hoyci/todo-ddd/pkg/domain/valueobject"
+	usecasetask "github.com/hoyci/todo-ddd/pkg/usecase/task"
+)
+
+// loader agrupa as chaves pedidas pelos resolvers de um mesmo nível da
+// consulta e as busca em um único lote. Os resolvers devolvem o thunk de
+// Load; o executor só o avalia depois de resolver os irmãos, quando todas
+// as chaves do nível já foram registradas.
+type loader[K comparable, V any] struct {
+	fetch func(ctx context.Context, keys []K) (map[K]V, error)
+
+	mu      sync.Mutex
+	pending []K
+	results map[K]V
+	errs    map[K]error
+}
+
+func newLoader[K comparable, V any](fetch func(ctx context.Context, keys []K) (map[K]V, error)) *loader[K, V] {
+	return &loader[K, V]{
+		fetch:   fetch,
+		results: make(map[K]V),
+		errs:    make(map[K]error),
+	}
+}
+
+func (l *loader[K, V]) Load(ctx context.Context, key K) func() (V, error) {
+	l.mu.Lock()
+	if !l.done(key) && !l.queued(key) {
+		l.pending = append(l.pending, key)
+	}
+	l.mu.Unlock()
+
+	return func() (V, error) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		if !l.done(key) {
+			if !l.queued(key) {
+				l.pending = append(l.pending, key)
+			}
+			l.flush(ctx)
+		}
+		return l.results[key], l.errs[key]
+	}
+}
+
+// Reset descarta o cache; as mutações o chamam para que campos resolvidos
+// depois delas vejam o estado novo.
+func (l *loader[K, V]) Reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	clear(l.results)
+	clear(l.errs)
+}
+
+func (l *loader[K, V]) flush(ctx context.Context) {
+	keys := l.pending
+	l.pending = nil
+
+	values, err := l.fetch(ctx, keys)
+	for _, key := range keys {
+		if err != nil {
+			l.errs[key] = err
+			continue
+		}
+		// Chaves sem resultado ficam com o valor zero, para não serem
+		// buscadas de novo.
+		l.results[key] = values[key]
+	}
+}
+
+func (l *loader[K, V]) done(key K) bool {
+	if _, ok := l.results[key]; ok {
+		return true
+	}
+	_, ok := l.errs[key]
+	return ok
+}
+
+func (l *loader[K, V]) queued(key K) bool {
+	for _, k := range l.pending {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// loaders são criados por requisição: o cache não sobrevive a ela, então
+// mutações de uma requisição nunca servem dados velhos para outra.
+type loaders struct {
+	tasksByUser *loader[taskPageKey, *usecasetask.TaskPage]
+	taskCounts  *loader[taskCountKey, map[valueobject.Status]int]
+	users       *loader[string, *domainUser.User]
+}
+
+// taskPageKey identifica a página das tarefas de um dono. Donos pedidos
+// com os mesmos argumentos entram na mesma consulta.
+type taskPageKey struct {
+	UserID string
+	Filter domainTask.TaskFilter
+	After  string
+	First  int
+}
+
+type taskCountKey struct {
+	UserID string
+	Filter domainTask.TaskFilter
+}
+
+type loadersContextKey struct{}
+
+func withLoaders(ctx context.Context, l *loaders) context.Context {
+	return context.WithValue(ctx, loadersContextKey{}, l)
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersContextKey{}).(*loaders)
+}
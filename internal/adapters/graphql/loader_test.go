@@ -0,0 +1,61 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestLoader(t *testing.T) {
+	ctx := context.Background()
+	var batches [][]string
+	fail := false
+	l := newLoader(func(_ context.Context, keys []string) (map[string]int, error) {
+		batches = append(batches, slices.Clone(keys))
+		if fail {
+			return nil, errors.New("fetch failed")
+		}
+		out := make(map[string]int)
+		for _, key := range keys {
+			if key != "missing" {
+				out[key] = len(key)
+			}
+		}
+		return out, nil
+	})
+
+	// As chaves registradas antes do primeiro thunk entram no mesmo lote,
+	// sem repetição.
+	a, b, again, missing := l.Load(ctx, "a"), l.Load(ctx, "bb"), l.Load(ctx, "a"), l.Load(ctx, "missing")
+	for _, tt := range []struct {
+		load func() (int, error)
+		want int
+	}{{a, 1}, {b, 2}, {again, 1}, {missing, 0}} {
+		if got, err := tt.load(); err != nil || got != tt.want {
+			t.Errorf("got %d, %v; want %d", got, err, tt.want)
+		}
+	}
+	if len(batches) != 1 || !slices.Equal(batches[0], []string{"a", "bb", "missing"}) {
+		t.Fatalf("batches = %v, want one batch with a, bb and missing", batches)
+	}
+
+	// Chaves já carregadas, mesmo sem resultado, vêm do cache.
+	if _, err := l.Load(ctx, "missing")(); err != nil || len(batches) != 1 {
+		t.Errorf("cached key fetched again: %d batches, error %v", len(batches), err)
+	}
+
+	// Depois de Reset, a chave é buscada de novo e o erro do lote chega a
+	// cada chave dele.
+	l.Reset()
+	fail = true
+	c, a := l.Load(ctx, "c"), l.Load(ctx, "a")
+	for _, load := range []func() (int, error){c, a} {
+		if _, err := load(); err == nil {
+			t.Error("fetch error not returned")
+		}
+	}
+	if len(batches) != 2 || !slices.Equal(batches[1], []string{"c", "a"}) {
+		t.Errorf("batches = %v, want a second batch with c and a", batches)
+	}
+}
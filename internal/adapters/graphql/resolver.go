@@ -0,0 +1,508 @@
+package graphql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	graphqlgo "github.com/graphql-go/graphql"
+	domainTask "github.com/hoyci/todo-ddd/pkg/domain/task"
+	domainUser "github.com/hoyci/todo-ddd/pkg/domain/user"
+	"github.com/hoyci/todo-ddd/pkg/domain/valueobject"
+	"github.com/hoyci/todo-ddd/pkg/logging"
+	"github.com/hoyci/todo-ddd/pkg/policy"
+	"github.com/hoyci/todo-ddd/pkg/usecase"
+	usecasesetup "github.com/hoyci/todo-ddd/pkg/usecase/setup"
+	usecasetask "github.com/hoyci/todo-ddd/pkg/usecase/task"
+	usecaseuser "github.com/hoyci/todo-ddd/pkg/usecase/user"
+)
+
+// Resolver liga o schema aos mesmos casos de uso usados pelos adapters
+// REST e gRPC.
+type Resolver struct {
+	FindUsersUC    policy.UseCase[[]string, map[string]*domainUser.User]
+	FindUserUC     policy.UseCase[usecaseuser.FindUserInput, *usecaseuser.FindUserOutput]
+	ListUsersUC    policy.UseCase[usecaseuser.ListUserInput, *usecaseuser.ListUserOutput]
+	CreateUserUC   *usecaseuser.CreateUserUseCase
+	UpdateUserUC   policy.UseCase[usecaseuser.UpdateUserInput, *usecaseuser.UpdateUserOutput]
+	DeleteUserUC   policy.Command[usecaseuser.DeleteUserInput]
+	TasksByUsersUC policy.UseCase[usecasetask.ListTasksByUsersInput, map[string]*usecasetask.TaskPage]
+	TaskCountsUC   policy.UseCase[usecasetask.CountTasksByUsersInput, map[string]map[valueobject.Status]int]
+	CreateTaskUC   policy.UseCase[usecasetask.CreateTaskInput, *usecasetask.CreateTaskOutput]
+	UpdateTaskUC   policy.UseCase[usecasetask.UpdateTaskInput, *usecasetask.UpdateTaskOutput]
+	UpdateStatusUC policy.UseCase[usecasetask.UpdateTaskStatusInput, *usecasetask.UpdateTaskStatusOutput]
+	DeleteTaskUC   policy.UseCase[usecasetask.DeleteTaskInput, *usecasetask.DeleteTaskOutput]
+	SetupUC        *usecasesetup.SetupOnboardingUseCase
+}
+
+// resolverError carrega um código em extensions.code, no formato usado
+// pelos clientes GraphQL mais comuns.
+type resolverError struct {
+	message string
+	code    string
+}
+
+func (e *resolverError) Error() string { return e.message }
+
+func (e *resolverError) Extensions() map[string]any {
+	return map[string]any{"code": e.code}
+}
+
+var (
+	errUnauthenticated = &resolverError{message: "missing or invalid X-User-ID header", code: "UNAUTHENTICATED"}
+	errInvalidCursor   = &resolverError{message: "invalid cursor", code: "BAD_USER_INPUT"}
+	errInvalidFirst    = &resolverError{message: fmt.Sprintf("first must be between 1 and %d", MaxPageSize), code: "BAD_USER_INPUT"}
+)
+
+// toError traduz erros dos casos de uso com a mesma classificação das
+// métricas e dos demais adapters; erros internos não vazam detalhes.
+func toError(ctx context.Context, err error) error {
+	var resolverErr *resolverError
+	if errors.As(err, &resolverErr) {
+		return err
+	}
+
+	switch usecase.ErrorKind(err) {
+	case usecase.ErrorKindValidation:
+		return &resolverError{message: err.Error(), code: "BAD_USER_INPUT"}
+	case usecase.ErrorKindNotFound:
+		if errors.Is(err, sql.ErrNoRows) {
+			return &resolverError{message: "resource not found", code: "NOT_FOUND"}
+		}
+		return &resolverError{message: err.Error(), code: "NOT_FOUND"}
+	case usecase.ErrorKindConflict:
+		return &resolverError{message: err.Error(), code: "CONFLICT"}
+	case usecase.ErrorKindForbidden:
+		return &resolverError{message: err.Error(), code: "FORBIDDEN"}
+	case usecase.ErrorKindAborted:
+		return &resolverError{message: err.Error(), code: "ABORTED"}
+	default:
+		logging.ForPackage(ctx, "graphql").Error("unexpected error", "error", err)
+		return &resolverError{message: usecase.ErrUnknown.Error(), code: "INTERNAL"}
+	}
+}
+
+type userIDContextKey struct{}
+
+func withUserID(ctx context.Context, userID string) context.Context {
+	return context.WithValue(policy.WithSubject(ctx, userID), userIDContextKey{}, userID)
+}
+
+func requireUser(ctx context.Context) (string, error) {
+	userID, _ := ctx.Value(userIDContextKey{}).(string)
+	if userID == "" {
+		return "", errUnauthenticated
+	}
+	return userID, nil
+}
+
+func (r *Resolver) newLoaders() *loaders {
+	return &loaders{
+		tasksByUser: newLoader(r.fetchTaskPages),
+		taskCounts:  newLoader(r.fetchTaskCounts),
+		users:       newLoader(r.FindUsersUC.Execute),
+	}
+}
+
+// fetchTaskPages agrupa os donos pedidos com os mesmos argumentos e busca
+// as páginas de cada grupo em uma consulta, só com as tarefas que o
+// usuário autenticado enxerga.
+func (r *Resolver) fetchTaskPages(ctx context.Context, keys []taskPageKey) (map[taskPageKey]*usecasetask.TaskPage, error) {
+	viewerID, err := requireUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make(map[taskPageKey][]string)
+	for _, key := range keys {
+		group := key
+		group.UserID = ""
+		groups[group] = append(groups[group], key.UserID)
+	}
+
+	out := make(map[taskPageKey]*usecasetask.TaskPage, len(keys))
+	for group, userIDs := range groups {
+		pages, err := r.TasksByUsersUC.Execute(ctx, usecasetask.ListTasksByUsersInput{
+			ViewerID: viewerID,
+			UserIDs:  userIDs,
+			Filter:   group.Filter,
+			After:    group.After,
+			Limit:    group.First,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for userID, page := range pages {
+			key := group
+			key.UserID = userID
+			out[key] = page
+		}
+	}
+	return out, nil
+}
+
+func (r *Resolver) fetchTaskCounts(ctx context.Context, keys []taskCountKey) (map[taskCountKey]map[valueobject.Status]int, error) {
+	viewerID, err := requireUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make(map[domainTask.TaskFilter][]string)
+	for _, key := range keys {
+		groups[key.Filter] = append(groups[key.Filter], key.UserID)
+	}
+
+	out := make(map[taskCountKey]map[valueobject.Status]int, len(keys))
+	for filter, userIDs := range groups {
+		counts, err := r.TaskCountsUC.Execute(ctx, usecasetask.CountTasksByUsersInput{
+			ViewerID: viewerID,
+			UserIDs:  userIDs,
+			Filter:   filter,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for userID, byStatus := range counts {
+			out[taskCountKey{UserID: userID, Filter: filter}] = byStatus
+		}
+	}
+	return out, nil
+}
+
+// thunk adia a leitura do loader para depois que o executor resolver os
+// campos irmãos, permitindo que todos entrem no mesmo lote.
+func thunk[V any](ctx context.Context, load func() (V, error), convert func(V) (any, error)) func() (any, error) {
+	return func() (any, error) {
+		v, err := load()
+		if err != nil {
+			return nil, toError(ctx, err)
+		}
+		out, err := convert(v)
+		if err != nil {
+			return nil, toError(ctx, err)
+		}
+		return out, nil
+	}
+}
+
+func userOrNil(u *domainUser.User) (any, error) {
+	if u == nil {
+		return nil, nil
+	}
+	return u, nil
+}
+
+func (r *Resolver) taskUser(p graphqlgo.ResolveParams) (any, error) {
+	task := p.Source.(*domainTask.Task)
+	return thunk(p.Context, loadersFrom(p.Context).users.Load(p.Context, task.UserID), userOrNil), nil
+}
+
+func (r *Resolver) taskAssignee(p graphqlgo.ResolveParams) (any, error) {
+	task := p.Source.(*domainTask.Task)
+	if task.AssigneeID == "" {
+		return nil, nil
+	}
+	return thunk(p.Context, loadersFrom(p.Context).users.Load(p.Context, task.AssigneeID), userOrNil), nil
+}
+
+// userTasks pagina as tarefas do usuário que o autenticado enxerga, pela
+// mesma regra de acesso dos casos de uso.
+func (r *Resolver) userTasks(p graphqlgo.ResolveParams) (any, error) {
+	if _, err := requireUser(p.Context); err != nil {
+		return nil, err
+	}
+	return r.taskConnection(p.Context, p.Source.(*domainUser.User).ID, p.Args)
+}
+
+func (r *Resolver) userTaskCounts(p graphqlgo.ResolveParams) (any, error) {
+	if _, err := requireUser(p.Context); err != nil {
+		return nil, err
+	}
+	user := p.Source.(*domainUser.User)
+	load := loadersFrom(p.Context).taskCounts.Load(p.Context, taskCountKey{UserID: user.ID})
+	return thunk(p.Context, load, func(byStatus map[valueobject.Status]int) (any, error) {
+		return taskCounts{
+			Total:      total(byStatus),
+			New:        byStatus[valueobject.StatusNew],
+			InProgress: byStatus[valueobject.StatusInProgress],
+			Completed:  byStatus[valueobject.StatusCompleted],
+		}, nil
+	}), nil
+}
+
+// taskConnection monta a conexão das tarefas do dono a partir da página e
+// da contagem, pedidas aos loaders juntas para entrarem no mesmo lote.
+func (r *Resolver) taskConnection(ctx context.Context, userID string, args map[string]any) (any, error) {
+	first, after, err := readPage(args)
+	if err != nil {
+		return nil, err
+	}
+	filter := taskFilter(args)
+
+	l := loadersFrom(ctx)
+	loadPage := l.tasksByUser.Load(ctx, taskPageKey{UserID: userID, Filter: filter, After: after, First: first})
+	loadCounts := l.taskCounts.Load(ctx, taskCountKey{UserID: userID, Filter: filter})
+	return func() (any, error) {
+		page, err := loadPage()
+		if err != nil {
+			return nil, toError(ctx, err)
+		}
+		byStatus, err := loadCounts()
+		if err != nil {
+			return nil, toError(ctx, err)
+		}
+		return newConnection(page.Tasks, page.HasMore, total(byStatus), func(t *domainTask.Task) string { return t.ID }), nil
+	}, nil
+}
+
+func total(byStatus map[valueobject.Status]int) int {
+	n := 0
+	for _, count := range byStatus {
+		n += count
+	}
+	return n
+}
+
+func (r *Resolver) viewer(p graphqlgo.ResolveParams) (any, error) {
+	userID, err := requireUser(p.Context)
+	if err != nil {
+		return nil, err
+	}
+	return thunk(p.Context, loadersFrom(p.Context).users.Load(p.Context, userID), userOrNil), nil
+}
+
+func (r *Resolver) user(p graphqlgo.ResolveParams) (any, error) {
+	if _, err := requireUser(p.Context); err != nil {
+		return nil, err
+	}
+	id, _ := p.Args["id"].(string)
+
+	out, err := r.FindUserUC.Execute(p.Context, usecaseuser.FindUserInput{ID: id})
+	if err != nil {
+		if usecase.ErrorKind(err) == usecase.ErrorKindNotFound {
+			return nil, nil
+		}
+		return nil, toError(p.Context, err)
+	}
+	if out.User.DeletedAt != nil {
+		return nil, nil
+	}
+	return out.User, nil
+}
+
+func (r *Resolver) users(p graphqlgo.ResolveParams) (any, error) {
+	if _, err := requireUser(p.Context); err != nil {
+		return nil, err
+	}
+	first, after, err := readPage(p.Args)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := r.ListUsersUC.Execute(p.Context, usecaseuser.ListUserInput{
+		Search: userSearch(p.Args),
+		After:  after,
+		Limit:  first,
+	})
+	if err != nil {
+		return nil, toError(p.Context, err)
+	}
+	return newConnection(out.Users, out.HasMore, out.Total, func(u *domainUser.User) string { return u.ID }), nil
+}
+
+func (r *Resolver) task(p graphqlgo.ResolveParams) (any, error) {
+	userID, err := requireUser(p.Context)
+	if err != nil {
+		return nil, err
+	}
+	id, _ := p.Args["id"].(string)
+
+	load := loadersFrom(p.Context).tasksByUser.Load(p.Context, taskPageKey{UserID: userID})
+	return thunk(p.Context, load, func(page *usecasetask.TaskPage) (any, error) {
+		for _, t := range page.Tasks {
+			if t.ID == id {
+				return t, nil
+			}
+		}
+		return nil, nil
+	}), nil
+}
+
+func (r *Resolver) tasks(p graphqlgo.ResolveParams) (any, error) {
+	userID, err := requireUser(p.Context)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.taskConnection(p.Context, userID, p.Args)
+}
+
+func (r *Resolver) createTask(p graphqlgo.ResolveParams) (any, error) {
+	userID, err := requireUser(p.Context)
+	if err != nil {
+		return nil, err
+	}
+	input := p.Args["input"].(map[string]any)
+	priority, err := valueobject.NewPriority(input["priority"].(int))
+	if err != nil {
+		return nil, toError(p.Context, err)
+	}
+	description, _ := input["description"].(string)
+
+	out, err := r.CreateTaskUC.Execute(p.Context, usecasetask.CreateTaskInput{
+		Title:       input["title"].(string),
+		Description: description,
+		Priority:    priority,
+		UserID:      userID,
+	})
+	if err != nil {
+		return nil, toError(p.Context, err)
+	}
+	loadersFrom(p.Context).tasksByUser.Reset()
+	return out.Task, nil
+}
+
+func (r *Resolver) updateTask(p graphqlgo.ResolveParams) (any, error) {
+	userID, err := requireUser(p.Context)
+	if err != nil {
+		return nil, err
+	}
+	input := p.Args["input"].(map[string]any)
+	priority, err := valueobject.NewPriority(input["priority"].(int))
+	if err != nil {
+		return nil, toError(p.Context, err)
+	}
+	description, _ := input["description"].(string)
+
+	out, err := r.UpdateTaskUC.Execute(p.Context, usecasetask.UpdateTaskInput{
+		TaskID:      p.Args["id"].(string),
+		Title:       input["title"].(string),
+		Description: description,
+		Priority:    priority,
+		UserID:      userID,
+	})
+	if err != nil {
+		return nil, toError(p.Context, err)
+	}
+	loadersFrom(p.Context).tasksByUser.Reset()
+	return &out.Task, nil
+}
+
+func (r *Resolver) updateTaskStatus(p graphqlgo.ResolveParams) (any, error) {
+	userID, err := requireUser(p.Context)
+	if err != nil {
+		return nil, err
+	}
+	status, err := valueobject.NewStatus(p.Args["status"].(string))
+	if err != nil {
+		return nil, toError(p.Context, err)
+	}
+
+	out, err := r.UpdateStatusUC.Execute(p.Context, usecasetask.UpdateTaskStatusInput{
+		TaskID: p.Args["id"].(string),
+		Status: status,
+		UserID: userID,
+	})
+	if err != nil {
+		return nil, toError(p.Context, err)
+	}
+	loadersFrom(p.Context).tasksByUser.Reset()
+	return &out.Task, nil
+}
+
+func (r *Resolver) deleteTask(p graphqlgo.ResolveParams) (any, error) {
+	userID, err := requireUser(p.Context)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := r.DeleteTaskUC.Execute(p.Context, usecasetask.DeleteTaskInput{TaskID: p.Args["id"].(string), UserID: userID})
+	if err != nil {
+		return nil, toError(p.Context, err)
+	}
+	loadersFrom(p.Context).tasksByUser.Reset()
+	return out.ID, nil
+}
+
+func (r *Resolver) createUser(p graphqlgo.ResolveParams) (any, error) {
+	input := p.Args["input"].(map[string]any)
+	out, err := r.CreateUserUC.Execute(p.Context, usecaseuser.CreateUserInput{
+		Name:  input["name"].(string),
+		Email: input["email"].(string),
+	})
+	if err != nil {
+		return nil, toError(p.Context, err)
+	}
+	return out.User, nil
+}
+
+func (r *Resolver) updateUser(p graphqlgo.ResolveParams) (any, error) {
+	input := p.Args["input"].(map[string]any)
+	out, err := r.UpdateUserUC.Execute(p.Context, usecaseuser.UpdateUserInput{
+		ID:    p.Args["id"].(string),
+		Name:  input["name"].(string),
+		Email: input["email"].(string),
+	})
+	if err != nil {
+		return nil, toError(p.Context, err)
+	}
+	loadersFrom(p.Context).users.Reset()
+	return out.User, nil
+}
+
+func (r *Resolver) deleteUser(p graphqlgo.ResolveParams) (any, error) {
+	id := p.Args["id"].(string)
+	if err := r.DeleteUserUC.Execute(p.Context, usecaseuser.DeleteUserInput{ID: id}); err != nil {
+		return nil, toError(p.Context, err)
+	}
+	loadersFrom(p.Context).users.Reset()
+	return id, nil
+}
+
+func (r *Resolver) onboard(p graphqlgo.ResolveParams) (any, error) {
+	input := p.Args["input"].(map[string]any)
+	err := r.SetupUC.Execute(p.Context, usecasesetup.SetupOnboardingInput{
+		Name:  input["name"].(string),
+		Email: input["email"].(string),
+	})
+	if err != nil {
+		return nil, toError(p.Context, err)
+	}
+	return true, nil
+}
+
+// readPage lê first e after. O cursor é o ID do último item da página,
+// codificado para ser opaco ao cliente.
+func readPage(args map[string]any) (first int, after string, err error) {
+	first, _ = args["first"].(int)
+	if first < 1 || first > MaxPageSize {
+		return 0, "", errInvalidFirst
+	}
+	if cursor, _ := args["after"].(string); cursor != "" {
+		raw, err := base64.RawURLEncoding.DecodeString(cursor)
+		if err != nil || len(raw) == 0 {
+			return 0, "", errInvalidCursor
+		}
+		after = string(raw)
+	}
+	return first, after, nil
+}
+
+// newConnection monta a conexão de uma página já paginada no repositório.
+func newConnection[T any](nodes []T, hasNextPage bool, total int, id func(T) string) *connection[T] {
+	if nodes == nil {
+		nodes = []T{}
+	}
+	page := &connection[T]{
+		Nodes:      nodes,
+		PageInfo:   pageInfo{HasNextPage: hasNextPage},
+		TotalCount: total,
+	}
+	if len(nodes) > 0 {
+		cursor := base64.RawURLEncoding.EncodeToString([]byte(id(nodes[len(nodes)-1])))
+		page.PageInfo.EndCursor = &cursor
+	}
+	return page
+}
@@ -0,0 +1,192 @@
+package graphql
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"testing"
+
+	graphqlgo "github.com/graphql-go/graphql"
+	domainTask "github.com/hoyci/todo-ddd/pkg/domain/task"
+	domainUser "github.com/hoyci/todo-ddd/pkg/domain/user"
+	"github.com/hoyci/todo-ddd/pkg/domain/valueobject"
+	usecasetask "github.com/hoyci/todo-ddd/pkg/usecase/task"
+	usecaseuser "github.com/hoyci/todo-ddd/pkg/usecase/user"
+)
+
+// stubUseCase registra as entradas recebidas e responde com fn.
+type stubUseCase[I, O any] struct {
+	calls []I
+	fn    func(I) (O, error)
+}
+
+func (uc *stubUseCase[I, O]) Execute(_ context.Context, input I) (O, error) {
+	uc.calls = append(uc.calls, input)
+	return uc.fn(input)
+}
+
+// stubResolver serve os usuários u1, u2 e u3, cada um com uma tarefa
+// atribuída a a1, contando as chamadas a cada caso de uso.
+type stubResolver struct {
+	*Resolver
+	listUsers    *stubUseCase[usecaseuser.ListUserInput, *usecaseuser.ListUserOutput]
+	findUsers    *stubUseCase[[]string, map[string]*domainUser.User]
+	tasksByUsers *stubUseCase[usecasetask.ListTasksByUsersInput, map[string]*usecasetask.TaskPage]
+	taskCounts   *stubUseCase[usecasetask.CountTasksByUsersInput, map[string]map[valueobject.Status]int]
+}
+
+func newStubResolver() *stubResolver {
+	users := make(map[string]*domainUser.User)
+	for _, id := range []string{"u1", "u2", "u3", "a1"} {
+		users[id] = &domainUser.User{ID: id, Name: "User " + id, Email: id + "@example.com"}
+	}
+
+	s := &stubResolver{
+		listUsers: &stubUseCase[usecaseuser.ListUserInput, *usecaseuser.ListUserOutput]{fn: func(usecaseuser.ListUserInput) (*usecaseuser.ListUserOutput, error) {
+			return &usecaseuser.ListUserOutput{Users: []*domainUser.User{users["u1"], users["u2"], users["u3"]}, Total: 3}, nil
+		}},
+		findUsers: &stubUseCase[[]string, map[string]*domainUser.User]{fn: func(ids []string) (map[string]*domainUser.User, error) {
+			out := make(map[string]*domainUser.User)
+			for _, id := range ids {
+				out[id] = users[id]
+			}
+			return out, nil
+		}},
+		tasksByUsers: &stubUseCase[usecasetask.ListTasksByUsersInput, map[string]*usecasetask.TaskPage]{fn: func(in usecasetask.ListTasksByUsersInput) (map[string]*usecasetask.TaskPage, error) {
+			out := make(map[string]*usecasetask.TaskPage)
+			for i, id := range in.UserIDs {
+				task := &domainTask.Task{ID: "t" + strconv.Itoa(i), Title: "Task of " + id, UserID: id, AssigneeID: "a1", Status: valueobject.StatusNew}
+				out[id] = &usecasetask.TaskPage{Tasks: []*domainTask.Task{task}}
+			}
+			return out, nil
+		}},
+		taskCounts: &stubUseCase[usecasetask.CountTasksByUsersInput, map[string]map[valueobject.Status]int]{fn: func(in usecasetask.CountTasksByUsersInput) (map[string]map[valueobject.Status]int, error) {
+			out := make(map[string]map[valueobject.Status]int)
+			for _, id := range in.UserIDs {
+				out[id] = map[valueobject.Status]int{valueobject.StatusNew: 1}
+			}
+			return out, nil
+		}},
+	}
+	s.Resolver = &Resolver{
+		ListUsersUC:    s.listUsers,
+		FindUsersUC:    s.findUsers,
+		TasksByUsersUC: s.tasksByUsers,
+		TaskCountsUC:   s.taskCounts,
+	}
+	return s
+}
+
+func execute(t *testing.T, r *Resolver, query string) *graphqlgo.Result {
+	t.Helper()
+	server, err := NewServer(r, Limits{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := server.Execute(context.Background(), "u1", Request{Query: query})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func TestPageSizeBounds(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		valid bool
+	}{
+		{"tasks first zero", `{ tasks(first: 0) { totalCount } }`, false},
+		{"tasks first negative", `{ tasks(first: -1) { totalCount } }`, false},
+		{"tasks first above the maximum", `{ tasks(first: ` + strconv.Itoa(MaxPageSize+1) + `) { totalCount } }`, false},
+		{"users first above the maximum", `{ users(first: 1000) { totalCount } }`, false},
+		{"nested first above the maximum", `{ viewer { tasks(first: 1000) { totalCount } } }`, false},
+		{"tasks first one", `{ tasks(first: 1) { totalCount } }`, true},
+		{"tasks first at the maximum", `{ tasks(first: ` + strconv.Itoa(MaxPageSize) + `) { totalCount } }`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newStubResolver()
+			result := execute(t, s.Resolver, tt.query)
+
+			if tt.valid {
+				if len(result.Errors) != 0 {
+					t.Fatalf("errors = %v, want none", result.Errors)
+				}
+				return
+			}
+			if len(result.Errors) != 1 || result.Errors[0].Message != errInvalidFirst.Error() {
+				t.Fatalf("errors = %v, want %q", result.Errors, errInvalidFirst)
+			}
+			if code := result.Errors[0].Extensions["code"]; code != "BAD_USER_INPUT" {
+				t.Errorf("extensions.code = %v, want BAD_USER_INPUT", code)
+			}
+			// A página inválida não chega aos casos de uso de listagem.
+			if len(s.tasksByUsers.calls) != 0 || len(s.listUsers.calls) != 0 {
+				t.Errorf("use cases called with an invalid page: tasks %d, users %d", len(s.tasksByUsers.calls), len(s.listUsers.calls))
+			}
+		})
+	}
+}
+
+func TestDataLoaderBatching(t *testing.T) {
+	s := newStubResolver()
+	result := execute(t, s.Resolver, `{
+		users(first: 3) {
+			nodes {
+				id
+				tasks(first: 5) { totalCount nodes { id user { id } assignee { id } } }
+				taskCounts { total }
+			}
+		}
+	}`)
+	if len(result.Errors) != 0 {
+		t.Fatalf("errors = %v", result.Errors)
+	}
+
+	// Um nível da consulta vira uma chamada por caso de uso, com os donos
+	// de todos os irmãos.
+	if len(s.tasksByUsers.calls) != 1 {
+		t.Fatalf("tasks loaded in %d calls, want 1", len(s.tasksByUsers.calls))
+	}
+	if got := sorted(s.tasksByUsers.calls[0].UserIDs); !slices.Equal(got, []string{"u1", "u2", "u3"}) {
+		t.Errorf("tasks loaded for %v, want u1, u2 and u3", got)
+	}
+	if s.tasksByUsers.calls[0].ViewerID != "u1" || s.tasksByUsers.calls[0].Limit != 5 {
+		t.Errorf("tasks input = %+v, want viewer u1 and limit 5", s.tasksByUsers.calls[0])
+	}
+	// totalCount e taskCounts pedem a mesma contagem e dividem a chamada.
+	if len(s.taskCounts.calls) != 1 {
+		t.Errorf("counts loaded in %d calls, want 1", len(s.taskCounts.calls))
+	}
+	// Os donos e os responsáveis das tarefas vêm em uma única busca, sem
+	// repetir a1.
+	if len(s.findUsers.calls) != 1 {
+		t.Fatalf("users loaded in %d calls, want 1", len(s.findUsers.calls))
+	}
+	if got := sorted(s.findUsers.calls[0]); !slices.Equal(got, []string{"a1", "u1", "u2", "u3"}) {
+		t.Errorf("users loaded = %v, want a1, u1, u2 and u3", got)
+	}
+
+	nodes := result.Data.(map[string]any)["users"].(map[string]any)["nodes"].([]any)
+	for _, node := range nodes {
+		user := node.(map[string]any)
+		tasks := user["tasks"].(map[string]any)["nodes"].([]any)
+		if len(tasks) != 1 {
+			t.Fatalf("user %v has %d tasks, want 1", user["id"], len(tasks))
+		}
+		task := tasks[0].(map[string]any)
+		if owner := task["user"].(map[string]any)["id"]; owner != user["id"] {
+			t.Errorf("task of %v resolved owner %v", user["id"], owner)
+		}
+		if assignee := task["assignee"].(map[string]any)["id"]; assignee != "a1" {
+			t.Errorf("task of %v resolved assignee %v, want a1", user["id"], assignee)
+		}
+	}
+}
+
+func sorted(ids []string) []string {
+	out := slices.Clone(ids)
+	slices.Sort(out)
+	return out
+}
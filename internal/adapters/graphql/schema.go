@@ -0,0 +1,287 @@
+package graphql
+
+import (
+	graphqlgo "github.com/graphql-go/graphql"
+	domainTask "github.com/hoyci/todo-ddd/pkg/domain/task"
+	domainUser "github.com/hoyci/todo-ddd/pkg/domain/user"
+	"github.com/hoyci/todo-ddd/pkg/domain/valueobject"
+)
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// paginatedFields são os campos que recebem first/after; a análise de
+// complexidade multiplica o custo dos seus filhos pelo tamanho da página.
+var paginatedFields = map[string]bool{"users": true, "tasks": true}
+
+var taskStatusEnum = graphqlgo.NewEnum(graphqlgo.EnumConfig{
+	Name: "TaskStatus",
+	Values: graphqlgo.EnumValueConfigMap{
+		"NEW":         {Value: string(valueobject.StatusNew)},
+		"IN_PROGRESS": {Value: string(valueobject.StatusInProgress)},
+		"COMPLETED":   {Value: string(valueobject.StatusCompleted)},
+	},
+})
+
+var pageInfoType = graphqlgo.NewObject(graphqlgo.ObjectConfig{
+	Name: "PageInfo",
+	Fields: graphqlgo.Fields{
+		"hasNextPage": {Type: graphqlgo.NewNonNull(graphqlgo.Boolean)},
+		"endCursor":   {Type: graphqlgo.String},
+	},
+})
+
+var taskCountsType = graphqlgo.NewObject(graphqlgo.ObjectConfig{
+	Name: "TaskCounts",
+	Fields: graphqlgo.Fields{
+		"total":      {Type: graphqlgo.NewNonNull(graphqlgo.Int)},
+		"new":        {Type: graphqlgo.NewNonNull(graphqlgo.Int)},
+		"inProgress": {Type: graphqlgo.NewNonNull(graphqlgo.Int)},
+		"completed":  {Type: graphqlgo.NewNonNull(graphqlgo.Int)},
+	},
+})
+
+var taskFilterInput = graphqlgo.NewInputObject(graphqlgo.InputObjectConfig{
+	Name: "TaskFilter",
+	Fields: graphqlgo.InputObjectConfigFieldMap{
+		"status":   {Type: taskStatusEnum},
+		"priority": {Type: graphqlgo.Int},
+		"search":   {Type: graphqlgo.String, Description: "Busca no título e na descrição, sem diferenciar maiúsculas."},
+	},
+})
+
+var userFilterInput = graphqlgo.NewInputObject(graphqlgo.InputObjectConfig{
+	Name: "UserFilter",
+	Fields: graphqlgo.InputObjectConfigFieldMap{
+		"search": {Type: graphqlgo.String, Description: "Busca no nome e no e-mail, sem diferenciar maiúsculas."},
+	},
+})
+
+var taskInput = graphqlgo.NewInputObject(graphqlgo.InputObjectConfig{
+	Name: "TaskInput",
+	Fields: graphqlgo.InputObjectConfigFieldMap{
+		"title":       {Type: graphqlgo.NewNonNull(graphqlgo.String)},
+		"description": {Type: graphqlgo.String},
+		"priority":    {Type: graphqlgo.NewNonNull(graphqlgo.Int)},
+	},
+})
+
+var userInput = graphqlgo.NewInputObject(graphqlgo.InputObjectConfig{
+	Name: "UserInput",
+	Fields: graphqlgo.InputObjectConfigFieldMap{
+		"name":  {Type: graphqlgo.NewNonNull(graphqlgo.String)},
+		"email": {Type: graphqlgo.NewNonNull(graphqlgo.String)},
+	},
+})
+
+func pageArgs(filter *graphqlgo.InputObject) graphqlgo.FieldConfigArgument {
+	return graphqlgo.FieldConfigArgument{
+		"first":  {Type: graphqlgo.Int, DefaultValue: DefaultPageSize},
+		"after":  {Type: graphqlgo.String},
+		"filter": {Type: filter},
+	}
+}
+
+func connectionType(name string, node graphqlgo.Output) *graphqlgo.Object {
+	return graphqlgo.NewObject(graphqlgo.ObjectConfig{
+		Name: name,
+		Fields: graphqlgo.Fields{
+			"nodes":      {Type: graphqlgo.NewNonNull(graphqlgo.NewList(graphqlgo.NewNonNull(node)))},
+			"pageInfo":   {Type: graphqlgo.NewNonNull(pageInfoType)},
+			"totalCount": {Type: graphqlgo.NewNonNull(graphqlgo.Int)},
+		},
+	})
+}
+
+// newSchema monta o schema. Os campos escalares usam o resolver padrão,
+// que lê os campos das entidades pelo nome.
+func newSchema(r *Resolver) (graphqlgo.Schema, error) {
+	var userType *graphqlgo.Object
+
+	taskType := graphqlgo.NewObject(graphqlgo.ObjectConfig{
+		Name: "Task",
+		Fields: graphqlgo.FieldsThunk(func() graphqlgo.Fields {
+			return graphqlgo.Fields{
+				"id":          {Type: graphqlgo.NewNonNull(graphqlgo.ID)},
+				"title":       {Type: graphqlgo.NewNonNull(graphqlgo.String)},
+				"description": {Type: graphqlgo.NewNonNull(graphqlgo.String)},
+				"priority": {
+					Type: graphqlgo.NewNonNull(graphqlgo.Int),
+					Resolve: func(p graphqlgo.ResolveParams) (any, error) {
+						return int(p.Source.(*domainTask.Task).Priority), nil
+					},
+				},
+				"status": {
+					Type: graphqlgo.NewNonNull(taskStatusEnum),
+					Resolve: func(p graphqlgo.ResolveParams) (any, error) {
+						return string(p.Source.(*domainTask.Task).Status), nil
+					},
+				},
+				"dueAt": {Type: graphqlgo.DateTime},
+				"tags": {
+					Type: graphqlgo.NewNonNull(graphqlgo.NewList(graphqlgo.NewNonNull(graphqlgo.String))),
+					Resolve: func(p graphqlgo.ResolveParams) (any, error) {
+						tags := p.Source.(*domainTask.Task).Tags
+						out := make([]string, len(tags))
+						for i, tag := range tags {
+							out[i] = string(tag)
+						}
+						return out, nil
+					},
+				},
+				"recurrence": {
+					Type:        graphqlgo.String,
+					Description: "Regra no formato RRULE, como FREQ=WEEKLY;INTERVAL=2.",
+					Resolve: func(p graphqlgo.ResolveParams) (any, error) {
+						if recurrence := p.Source.(*domainTask.Task).Recurrence; !recurrence.IsZero() {
+							return recurrence.String(), nil
+						}
+						return nil, nil
+					},
+				},
+				"createdAt": {Type: graphqlgo.NewNonNull(graphqlgo.DateTime)},
+				"updatedAt": {Type: graphqlgo.DateTime},
+				"user":      {Type: userType, Resolve: r.taskUser},
+				"assignee":  {Type: userType, Resolve: r.taskAssignee},
+			}
+		}),
+	})
+	taskConnectionType := connectionType("TaskConnection", taskType)
+
+	userType = graphqlgo.NewObject(graphqlgo.ObjectConfig{
+		Name: "User",
+		Fields: graphqlgo.Fields{
+			"id":    {Type: graphqlgo.NewNonNull(graphqlgo.ID)},
+			"name":  {Type: graphqlgo.NewNonNull(graphqlgo.String)},
+			"email": {Type: graphqlgo.NewNonNull(graphqlgo.String)},
+			"emailVerified": {
+				Type: graphqlgo.NewNonNull(graphqlgo.Boolean),
+				Resolve: func(p graphqlgo.ResolveParams) (any, error) {
+					return p.Source.(*domainUser.User).Verified(), nil
+				},
+			},
+			"createdAt": {Type: graphqlgo.NewNonNull(graphqlgo.DateTime)},
+			"updatedAt": {Type: graphqlgo.DateTime},
+			"tasks": {
+				Type:    graphqlgo.NewNonNull(taskConnectionType),
+				Args:    pageArgs(taskFilterInput),
+				Resolve: r.userTasks,
+			},
+			"taskCounts": {Type: graphqlgo.NewNonNull(taskCountsType), Resolve: r.userTaskCounts},
+		},
+	})
+	userConnectionType := connectionType("UserConnection", userType)
+
+	idArgs := graphqlgo.FieldConfigArgument{"id": {Type: graphqlgo.NewNonNull(graphqlgo.ID)}}
+
+	query := graphqlgo.NewObject(graphqlgo.ObjectConfig{
+		Name: "Query",
+		Fields: graphqlgo.Fields{
+			"viewer": {Type: userType, Description: "Usuário do header X-User-ID.", Resolve: r.viewer},
+			"user":   {Type: userType, Args: idArgs, Resolve: r.user},
+			"users": {
+				Type:    graphqlgo.NewNonNull(userConnectionType),
+				Args:    pageArgs(userFilterInput),
+				Resolve: r.users,
+			},
+			"task": {Type: taskType, Description: "Tarefa do usuário autenticado.", Args: idArgs, Resolve: r.task},
+			"tasks": {
+				Type:        graphqlgo.NewNonNull(taskConnectionType),
+				Description: "Tarefas do usuário autenticado.",
+				Args:        pageArgs(taskFilterInput),
+				Resolve:     r.tasks,
+			},
+		},
+	})
+
+	mutation := graphqlgo.NewObject(graphqlgo.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphqlgo.Fields{
+			"createTask": {
+				Type:    graphqlgo.NewNonNull(taskType),
+				Args:    graphqlgo.FieldConfigArgument{"input": {Type: graphqlgo.NewNonNull(taskInput)}},
+				Resolve: r.createTask,
+			},
+			"updateTask": {
+				Type: graphqlgo.NewNonNull(taskType),
+				Args: graphqlgo.FieldConfigArgument{
+					"id":    {Type: graphqlgo.NewNonNull(graphqlgo.ID)},
+					"input": {Type: graphqlgo.NewNonNull(taskInput)},
+				},
+				Resolve: r.updateTask,
+			},
+			"updateTaskStatus": {
+				Type: graphqlgo.NewNonNull(taskType),
+				Args: graphqlgo.FieldConfigArgument{
+					"id":     {Type: graphqlgo.NewNonNull(graphqlgo.ID)},
+					"status": {Type: graphqlgo.NewNonNull(taskStatusEnum)},
+				},
+				Resolve: r.updateTaskStatus,
+			},
+			"deleteTask": {Type: graphqlgo.NewNonNull(graphqlgo.ID), Args: idArgs, Resolve: r.deleteTask},
+			"createUser": {
+				Type:    graphqlgo.NewNonNull(userType),
+				Args:    graphqlgo.FieldConfigArgument{"input": {Type: graphqlgo.NewNonNull(userInput)}},
+				Resolve: r.createUser,
+			},
+			"updateUser": {
+				Type: graphqlgo.NewNonNull(userType),
+				Args: graphqlgo.FieldConfigArgument{
+					"id":    {Type: graphqlgo.NewNonNull(graphqlgo.ID)},
+					"input": {Type: graphqlgo.NewNonNull(userInput)},
+				},
+				Resolve: r.updateUser,
+			},
+			"deleteUser": {Type: graphqlgo.NewNonNull(graphqlgo.ID), Args: idArgs, Resolve: r.deleteUser},
+			"onboard": {
+				Type:        graphqlgo.NewNonNull(graphqlgo.Boolean),
+				Description: "Cria o usuário e a tarefa de boas-vindas.",
+				Args:        graphqlgo.FieldConfigArgument{"input": {Type: graphqlgo.NewNonNull(userInput)}},
+				Resolve:     r.onboard,
+			},
+		},
+	})
+
+	return graphqlgo.NewSchema(graphqlgo.SchemaConfig{Query: query, Mutation: mutation})
+}
+
+type pageInfo struct {
+	HasNextPage bool    `json:"hasNextPage"`
+	EndCursor   *string `json:"endCursor"`
+}
+
+type connection[T any] struct {
+	Nodes      []T      `json:"nodes"`
+	PageInfo   pageInfo `json:"pageInfo"`
+	TotalCount int      `json:"totalCount"`
+}
+
+type taskCounts struct {
+	Total      int `json:"total"`
+	New        int `json:"new"`
+	InProgress int `json:"inProgress"`
+	Completed  int `json:"completed"`
+}
+
+// taskFilter lê o argumento filter das conexões de tarefas; campos
+// ausentes não filtram.
+func taskFilter(args map[string]any) domainTask.TaskFilter {
+	filter, _ := args["filter"].(map[string]any)
+	status, _ := filter["status"].(string)
+	priority, _ := filter["priority"].(int)
+	search, _ := filter["search"].(string)
+	return domainTask.TaskFilter{
+		Status:   valueobject.Status(status),
+		Priority: valueobject.Priority(priority),
+		Search:   search,
+	}
+}
+
+// userSearch lê a busca do argumento filter da conexão de usuários.
+func userSearch(args map[string]any) string {
+	filter, _ := args["filter"].(map[string]any)
+	search, _ := filter["search"].(string)
+	return search
+}
@@ -0,0 +1,73 @@
+package graphql
+
+import (
+	"context"
+
+	graphqlgo "github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/parser"
+)
+
+type Request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+// RejectedError indica que a consulta nem chegou a ser executada: erro de
+// sintaxe, de validação contra o schema ou limite excedido.
+type RejectedError struct {
+	Errors []gqlerrors.FormattedError
+}
+
+func (e *RejectedError) Error() string {
+	if len(e.Errors) == 0 {
+		return "query rejected"
+	}
+	return e.Errors[0].Message
+}
+
+type Server struct {
+	schema   graphqlgo.Schema
+	resolver *Resolver
+	limits   Limits
+}
+
+func NewServer(resolver *Resolver, limits Limits) (*Server, error) {
+	schema, err := newSchema(resolver)
+	if err != nil {
+		return nil, err
+	}
+	return &Server{schema: schema, resolver: resolver, limits: limits}, nil
+}
+
+// Execute valida a consulta, aplica os limites de profundidade e
+// complexidade e a executa. userID vazio significa requisição anônima; os
+// campos que exigem usuário respondem com UNAUTHENTICATED.
+func (s *Server) Execute(ctx context.Context, userID string, req Request) (*graphqlgo.Result, error) {
+	doc, err := parser.Parse(parser.ParseParams{Source: req.Query})
+	if err != nil {
+		return nil, &RejectedError{Errors: gqlerrors.FormatErrors(err)}
+	}
+
+	if validation := graphqlgo.ValidateDocument(&s.schema, doc, nil); !validation.IsValid {
+		return nil, &RejectedError{Errors: validation.Errors}
+	}
+
+	depth, complexity, err := analyze(doc, req.OperationName, req.Variables)
+	if err == nil {
+		err = s.limits.check(depth, complexity)
+	}
+	if err != nil {
+		return nil, &RejectedError{Errors: gqlerrors.FormatErrors(err)}
+	}
+
+	ctx = withLoaders(withUserID(ctx, userID), s.resolver.newLoaders())
+	return graphqlgo.Execute(graphqlgo.ExecuteParams{
+		Schema:        s.schema,
+		AST:           doc,
+		OperationName: req.OperationName,
+		Args:          req.Variables,
+		Context:       ctx,
+	}), nil
+}
@@ -0,0 +1,180 @@
+package grpc
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"net"
+	"time"
+
+	"github.com/google/uuid"
+	domainAudit "github.com/hoyci/todo-ddd/pkg/domain/audit"
+	domainWorkspace "github.com/hoyci/todo-ddd/pkg/domain/workspace"
+	"github.com/hoyci/todo-ddd/pkg/logging"
+	"github.com/hoyci/todo-ddd/pkg/policy"
+	"github.com/hoyci/todo-ddd/pkg/usecase"
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// userIDKey é o equivalente, em metadata, ao header X-User-ID da API REST.
+	userIDKey    = "x-user-id"
+	requestIDKey = "x-request-id"
+	// workspaceIDKey é o equivalente ao header X-Workspace-ID.
+	workspaceIDKey = "x-workspace-id"
+)
+
+type userIDContextKey struct{}
+
+// authenticate lê o usuário e o workspace do metadata. Chamadas sem usuário
+// seguem adiante; os métodos que exigem autenticação usam requireUser. O
+// workspace é repassado como veio: um ID inválido não corresponde a nenhuma
+// tarefa, em vez de cair no espaço pessoal.
+func authenticate(ctx context.Context) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(workspaceIDKey); len(values) > 0 {
+		ctx = domainWorkspace.WithScope(ctx, values[0])
+	}
+	if values := md.Get(userIDKey); len(values) > 0 {
+		if _, err := uuid.Parse(values[0]); err == nil {
+			ctx = policy.WithSubject(ctx, values[0])
+			return context.WithValue(ctx, userIDContextKey{}, values[0])
+		}
+	}
+	return ctx
+}
+
+func requireUser(ctx context.Context) (string, error) {
+	userID, _ := ctx.Value(userIDContextKey{}).(string)
+	if userID == "" {
+		return "", status.Error(codes.Unauthenticated, "missing or invalid "+userIDKey+" metadata")
+	}
+	return userID, nil
+}
+
+// withRequestLogger reaproveita o x-request-id recebido ou gera um novo e
+// injeta no contexto um logger enriquecido com ele, o método e o usuário,
+// além do IP do cliente para o log de auditoria.
+func withRequestLogger(ctx context.Context, base *slog.Logger, method string) (context.Context, *slog.Logger) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	requestID := uuid.New().String()
+	if values := md.Get(requestIDKey); len(values) > 0 && values[0] != "" && len(values[0]) <= 128 {
+		requestID = values[0]
+	}
+	_ = grpclib.SetHeader(ctx, metadata.Pairs(requestIDKey, requestID))
+
+	attrs := []any{"request_id", requestID, "method", method}
+	if userID, _ := ctx.Value(userIDContextKey{}).(string); userID != "" {
+		attrs = append(attrs, "user_id", userID)
+	}
+	logger := base.With(attrs...)
+	if p, ok := peer.FromContext(ctx); ok {
+		if host, _, err := net.SplitHostPort(p.Addr.String()); err == nil {
+			ctx = domainAudit.WithSourceIP(ctx, host)
+		}
+	}
+	return logging.WithRequestID(logging.WithLogger(ctx, logger), requestID), logger
+}
+
+func logCompletion(ctx context.Context, logger *slog.Logger, start time.Time, err error) {
+	code := status.Code(err)
+	level := slog.LevelInfo
+	switch code {
+	case codes.OK, codes.Canceled:
+	case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unavailable:
+		level = slog.LevelError
+	default:
+		level = slog.LevelWarn
+	}
+
+	logger.With(logging.PackageKey, "grpc").Log(ctx, level, "rpc completed",
+		"code", code.String(),
+		"latency_ms", time.Since(start).Milliseconds(),
+	)
+}
+
+// toStatus traduz erros dos casos de uso em status gRPC, usando a mesma
+// classificação das métricas e da API REST.
+func toStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	if errors.Is(err, context.Canceled) {
+		return status.Error(codes.Canceled, err.Error())
+	}
+
+	switch usecase.ErrorKind(err) {
+	case usecase.ErrorKindValidation:
+		return status.Error(codes.InvalidArgument, err.Error())
+	case usecase.ErrorKindNotFound:
+		if errors.Is(err, sql.ErrNoRows) {
+			return status.Error(codes.NotFound, "resource not found")
+		}
+		return status.Error(codes.NotFound, err.Error())
+	case usecase.ErrorKindConflict:
+		return status.Error(codes.AlreadyExists, err.Error())
+	case usecase.ErrorKindForbidden:
+		return status.Error(codes.PermissionDenied, err.Error())
+	case usecase.ErrorKindAborted:
+		return status.Error(codes.Aborted, err.Error())
+	default:
+		return status.Error(codes.Internal, usecase.ErrUnknown.Error())
+	}
+}
+
+func unaryInterceptor(base *slog.Logger) grpclib.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpclib.UnaryServerInfo, handler grpclib.UnaryHandler) (any, error) {
+		start := time.Now()
+		ctx = authenticate(ctx)
+		ctx, logger := withRequestLogger(ctx, base, info.FullMethod)
+
+		resp, err := handler(ctx, req)
+		if err != nil {
+			if status.Code(toStatus(err)) == codes.Internal {
+				logger.Error("unexpected error", "error", err)
+			}
+			err = toStatus(err)
+		}
+
+		logCompletion(ctx, logger, start, err)
+		return resp, err
+	}
+}
+
+func streamInterceptor(base *slog.Logger) grpclib.StreamServerInterceptor {
+	return func(srv any, ss grpclib.ServerStream, info *grpclib.StreamServerInfo, handler grpclib.StreamHandler) error {
+		start := time.Now()
+		ctx := authenticate(ss.Context())
+		ctx, logger := withRequestLogger(ctx, base, info.FullMethod)
+
+		err := handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+		if err != nil {
+			if status.Code(toStatus(err)) == codes.Internal {
+				logger.Error("unexpected error", "error", err)
+			}
+			err = toStatus(err)
+		}
+
+		logCompletion(ctx, logger, start, err)
+		return err
+	}
+}
+
+// contextStream substitui o contexto do stream pelo contexto enriquecido
+// pelos interceptors.
+type contextStream struct {
+	grpclib.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,22 @@
+package grpc
+
+import (
+	"context"
+
+	todov1 "github.com/hoyci/todo-ddd/api/proto/todo/v1"
+	usecasesetup "github.com/hoyci/todo-ddd/pkg/usecase/setup"
+)
+
+type OnboardingService struct {
+	todov1.UnimplementedOnboardingServiceServer
+
+	SetupUC *usecasesetup.SetupOnboardingUseCase
+}
+
+func (s *OnboardingService) Setup(ctx context.Context, req *todov1.SetupRequest) (*todov1.SetupResponse, error) {
+	err := s.SetupUC.Execute(ctx, usecasesetup.SetupOnboardingInput{Name: req.GetName(), Email: req.GetEmail()})
+	if err != nil {
+		return nil, err
+	}
+	return &todov1.SetupResponse{Message: "onboarding done successfully"}, nil
+}
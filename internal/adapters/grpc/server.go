@@ -0,0 +1,22 @@
+package grpc
+
+import (
+	"log/slog"
+
+	todov1 "github.com/hoyci/todo-ddd/api/proto/todo/v1"
+	grpclib "google.golang.org/grpc"
+)
+
+// NewServer cria o servidor gRPC com os serviços registrados e os
+// interceptors de autenticação, logging e tradução de erros.
+func NewServer(logger *slog.Logger, tasks *TaskService, users *UserService, onboarding *OnboardingService) *grpclib.Server {
+	server := grpclib.NewServer(
+		grpclib.UnaryInterceptor(unaryInterceptor(logger)),
+		grpclib.StreamInterceptor(streamInterceptor(logger)),
+	)
+
+	todov1.RegisterTaskServiceServer(server, tasks)
+	todov1.RegisterUserServiceServer(server, users)
+	todov1.RegisterOnboardingServiceServer(server, onboarding)
+	return server
+}
@@ -0,0 +1,206 @@
+package grpc
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	todov1 "github.com/hoyci/todo-ddd/api/proto/todo/v1"
+	"github.com/hoyci/todo-ddd/internal/adapters/db/sqlite"
+	"github.com/hoyci/todo-ddd/internal/adapters/events"
+	"github.com/hoyci/todo-ddd/pkg/domain/valueobject"
+	"github.com/hoyci/todo-ddd/pkg/policy"
+	"github.com/hoyci/todo-ddd/pkg/usecase"
+	usecasetask "github.com/hoyci/todo-ddd/pkg/usecase/task"
+	usecaseuser "github.com/hoyci/todo-ddd/pkg/usecase/user"
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// newTestClient sobe o servidor com os casos de uso reais sobre um banco
+// SQLite temporário e devolve uma conexão em memória com ele.
+func newTestClient(t *testing.T) *grpclib.ClientConn {
+	t.Helper()
+	t.Chdir(t.TempDir())
+	if err := os.Mkdir("data", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	db, err := sqlite.InitDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	broker := events.NewBroker(0)
+	unitOfWork := sqlite.NewSQLiteUnitOfWork(db, broker)
+	tasks := &TaskService{
+		CreateUC:     &usecasetask.CreateTaskUseCase{UoW: unitOfWork},
+		ListUC:       &usecasetask.ListTaskUseCase{TaskRepo: sqlite.NewSQLiteTaskRepository(db)},
+		ListEventsUC: &usecasetask.ListTaskEventsUseCase{EventRepo: sqlite.NewSQLiteTaskEventRepository(db)},
+		Broker:       broker,
+	}
+	users := &UserService{CreateUC: &usecaseuser.CreateUserUseCase{UoW: unitOfWork}}
+	server := NewServer(slog.New(slog.DiscardHandler), tasks, users, &OnboardingService{})
+
+	listener := bufconn.Listen(1 << 20)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpclib.NewClient("passthrough:///bufconn",
+		grpclib.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpclib.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func createTestUser(t *testing.T, conn *grpclib.ClientConn) string {
+	t.Helper()
+	user, err := todov1.NewUserServiceClient(conn).CreateUser(context.Background(), &todov1.CreateUserRequest{
+		Name:  "Grace Hopper",
+		Email: "grace@example.com",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return user.GetId()
+}
+
+func asUser(userID string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), userIDKey, userID)
+}
+
+func TestAuthentication(t *testing.T) {
+	conn := newTestClient(t)
+	userID := createTestUser(t, conn)
+	client := todov1.NewTaskServiceClient(conn)
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want codes.Code
+	}{
+		{"missing metadata", context.Background(), codes.Unauthenticated},
+		{"empty user", asUser(""), codes.Unauthenticated},
+		{"invalid user", asUser("not-a-uuid"), codes.Unauthenticated},
+		{"valid user", asUser(userID), codes.OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.ListTasks(tt.ctx, &todov1.ListTasksRequest{})
+			if got := status.Code(err); got != tt.want {
+				t.Errorf("ListTasks code = %v, want %v (%v)", got, tt.want, err)
+			}
+
+			stream, err := client.WatchTasks(tt.ctx, &todov1.WatchTasksRequest{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.want == codes.OK {
+				return
+			}
+			if _, err := stream.Recv(); status.Code(err) != tt.want {
+				t.Errorf("WatchTasks code = %v, want %v (%v)", status.Code(err), tt.want, err)
+			}
+		})
+	}
+}
+
+func TestRequestIDHeader(t *testing.T) {
+	conn := newTestClient(t)
+	client := todov1.NewTaskServiceClient(conn)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), requestIDKey, "req-123")
+	var header metadata.MD
+	_, _ = client.ListTasks(ctx, &todov1.ListTasksRequest{}, grpclib.Header(&header))
+	if got := header.Get(requestIDKey); len(got) != 1 || got[0] != "req-123" {
+		t.Errorf("%s header = %v, want the received ID", requestIDKey, got)
+	}
+}
+
+func TestWatchTasks(t *testing.T) {
+	conn := newTestClient(t)
+	userID := createTestUser(t, conn)
+	client := todov1.NewTaskServiceClient(conn)
+
+	ctx, cancel := context.WithTimeout(asUser(userID), 5*time.Second)
+	defer cancel()
+
+	// Lendo desde o início do log, o evento chega mesmo se a tarefa for
+	// criada antes de o servidor assinar o broker.
+	from := int64(0)
+	stream, err := client.WatchTasks(ctx, &todov1.WatchTasksRequest{AfterEventId: &from})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	created, err := client.CreateTask(ctx, &todov1.CreateTaskRequest{Title: "Write tests", Priority: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	event, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event.GetType() != todov1.TaskEventType_TASK_EVENT_TYPE_CREATED || event.GetTaskId() != created.GetId() {
+		t.Errorf("event = %v %q, want CREATED for %q", event.GetType(), event.GetTaskId(), created.GetId())
+	}
+	if event.GetTask().GetTitle() != "Write tests" {
+		t.Errorf("event task title = %q", event.GetTask().GetTitle())
+	}
+
+	negative := int64(-1)
+	stream, err = client.WatchTasks(ctx, &todov1.WatchTasksRequest{AfterEventId: &negative})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.Recv(); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("negative after_event_id code = %v, want InvalidArgument", status.Code(err))
+	}
+}
+
+func TestToStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		code    codes.Code
+		message string
+	}{
+		{"validation", valueobject.ErrEmptyTitle, codes.InvalidArgument, valueobject.ErrEmptyTitle.Error()},
+		{"not found", usecase.ErrTaskNotFound, codes.NotFound, usecase.ErrTaskNotFound.Error()},
+		{"wrapped not found", fmt.Errorf("find: %w", usecase.ErrWorkspaceNotFound), codes.NotFound, "find: " + usecase.ErrWorkspaceNotFound.Error()},
+		{"missing row hides the query", fmt.Errorf("select tasks: %w", sql.ErrNoRows), codes.NotFound, "resource not found"},
+		{"conflict", usecase.ErrUserAlreadyExists, codes.AlreadyExists, usecase.ErrUserAlreadyExists.Error()},
+		{"forbidden", usecase.ErrTaskForbidden, codes.PermissionDenied, usecase.ErrTaskForbidden.Error()},
+		{"policy denied", policy.ErrDenied, codes.PermissionDenied, policy.ErrDenied.Error()},
+		{"aborted", usecase.ErrBatchAborted, codes.Aborted, usecase.ErrBatchAborted.Error()},
+		{"canceled", context.Canceled, codes.Canceled, context.Canceled.Error()},
+		{"status kept", status.Error(codes.ResourceExhausted, "slow down"), codes.ResourceExhausted, "slow down"},
+		{"internal hides details", errors.New("disk on fire"), codes.Internal, usecase.ErrUnknown.Error()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st, _ := status.FromError(toStatus(tt.err))
+			if st.Code() != tt.code || st.Message() != tt.message {
+				t.Errorf("toStatus(%v) = %v %q, want %v %q", tt.err, st.Code(), st.Message(), tt.code, tt.message)
+			}
+		})
+	}
+
+	if toStatus(nil) != nil {
+		t.Error("toStatus(nil) should be nil")
+	}
+}
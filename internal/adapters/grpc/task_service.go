@@ -0,0 +1,236 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	todov1 "github.com/hoyci/todo-ddd/api/proto/todo/v1"
+	"github.com/hoyci/todo-ddd/internal/adapters/events"
+	domainTask "github.com/hoyci/todo-ddd/pkg/domain/task"
+	"github.com/hoyci/todo-ddd/pkg/domain/valueobject"
+	"github.com/hoyci/todo-ddd/pkg/policy"
+	usecasetask "github.com/hoyci/todo-ddd/pkg/usecase/task"
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// watchBatchSize limita quantos eventos são lidos do log de uma vez no
+// WatchTasks.
+const watchBatchSize = 100
+
+type TaskService struct {
+	todov1.UnimplementedTaskServiceServer
+
+	CreateUC       policy.UseCase[usecasetask.CreateTaskInput, *usecasetask.CreateTaskOutput]
+	ListUC         policy.UseCase[string, []usecasetask.ListTaskOutput]
+	UpdateUC       policy.UseCase[usecasetask.UpdateTaskInput, *usecasetask.UpdateTaskOutput]
+	UpdateStatusUC policy.UseCase[usecasetask.UpdateTaskStatusInput, *usecasetask.UpdateTaskStatusOutput]
+	DeleteUC       policy.UseCase[usecasetask.DeleteTaskInput, *usecasetask.DeleteTaskOutput]
+	ListEventsUC   *usecasetask.ListTaskEventsUseCase
+	Broker         *events.Broker
+}
+
+func (s *TaskService) CreateTask(ctx context.Context, req *todov1.CreateTaskRequest) (*todov1.Task, error) {
+	userID, err := requireUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	priority, err := valueobject.NewPriority(int(req.GetPriority()))
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := s.CreateUC.Execute(ctx, usecasetask.CreateTaskInput{
+		Title:       req.GetTitle(),
+		Description: req.GetDescription(),
+		Priority:    priority,
+		UserID:      userID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return newTask(out.Task), nil
+}
+
+func (s *TaskService) ListTasks(ctx context.Context, _ *todov1.ListTasksRequest) (*todov1.ListTasksResponse, error) {
+	userID, err := requireUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := s.ListUC.Execute(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &todov1.ListTasksResponse{Tasks: make([]*todov1.Task, 0, len(out))}
+	for _, t := range out {
+		resp.Tasks = append(resp.Tasks, newTask(t.Task))
+	}
+	return resp, nil
+}
+
+func (s *TaskService) UpdateTask(ctx context.Context, req *todov1.UpdateTaskRequest) (*todov1.Task, error) {
+	userID, err := requireUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	priority, err := valueobject.NewPriority(int(req.GetPriority()))
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := s.UpdateUC.Execute(ctx, usecasetask.UpdateTaskInput{
+		TaskID:      req.GetId(),
+		Title:       req.GetTitle(),
+		Description: req.GetDescription(),
+		Priority:    priority,
+		UserID:      userID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return newTask(&out.Task), nil
+}
+
+func (s *TaskService) UpdateTaskStatus(ctx context.Context, req *todov1.UpdateTaskStatusRequest) (*todov1.Task, error) {
+	userID, err := requireUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	taskStatus, ok := statusFromProto[req.GetStatus()]
+	if !ok {
+		return nil, valueobject.ErrInvalidStatus
+	}
+
+	out, err := s.UpdateStatusUC.Execute(ctx, usecasetask.UpdateTaskStatusInput{
+		TaskID: req.GetId(),
+		Status: taskStatus,
+		UserID: userID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return newTask(&out.Task), nil
+}
+
+func (s *TaskService) DeleteTask(ctx context.Context, req *todov1.DeleteTaskRequest) (*todov1.DeleteTaskResponse, error) {
+	userID, err := requireUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := s.DeleteUC.Execute(ctx, usecasetask.DeleteTaskInput{TaskID: req.GetId(), UserID: userID})
+	if err != nil {
+		return nil, err
+	}
+	return &todov1.DeleteTaskResponse{Id: out.ID}, nil
+}
+
+func (s *TaskService) WatchTasks(req *todov1.WatchTasksRequest, stream grpclib.ServerStreamingServer[todov1.TaskEvent]) error {
+	ctx := stream.Context()
+	userID, err := requireUser(ctx)
+	if err != nil {
+		return err
+	}
+
+	// A assinatura é feita antes da primeira leitura do log para que nenhum
+	// evento confirmado entre as duas operações seja perdido.
+	sub, err := s.Broker.Subscribe(userID)
+	if err != nil {
+		if errors.Is(err, events.ErrTooManySubscriptions) {
+			return status.Error(codes.ResourceExhausted, err.Error())
+		}
+		return err
+	}
+	defer sub.Close()
+
+	afterID := int64(-1)
+	if req.AfterEventId != nil {
+		if afterID = req.GetAfterEventId(); afterID < 0 {
+			return status.Error(codes.InvalidArgument, "after_event_id must not be negative")
+		}
+	}
+	if afterID < 0 {
+		out, err := s.ListEventsUC.Execute(ctx, usecasetask.ListTaskEventsInput{UserID: userID, AfterID: -1})
+		if err != nil {
+			return err
+		}
+		afterID = out.LastID
+	}
+
+	for {
+		for {
+			out, err := s.ListEventsUC.Execute(ctx, usecasetask.ListTaskEventsInput{
+				UserID:  userID,
+				AfterID: afterID,
+				Limit:   watchBatchSize,
+			})
+			if err != nil {
+				return err
+			}
+			for _, event := range out.Events {
+				if err := stream.Send(newTaskEvent(event)); err != nil {
+					return err
+				}
+			}
+			afterID = out.LastID
+			if len(out.Events) < watchBatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-sub.C():
+		}
+	}
+}
+
+var statusFromProto = map[todov1.TaskStatus]valueobject.Status{
+	todov1.TaskStatus_TASK_STATUS_NEW:         valueobject.StatusNew,
+	todov1.TaskStatus_TASK_STATUS_IN_PROGRESS: valueobject.StatusInProgress,
+	todov1.TaskStatus_TASK_STATUS_COMPLETED:   valueobject.StatusCompleted,
+}
+
+var statusToProto = map[valueobject.Status]todov1.TaskStatus{
+	valueobject.StatusNew:        todov1.TaskStatus_TASK_STATUS_NEW,
+	valueobject.StatusInProgress: todov1.TaskStatus_TASK_STATUS_IN_PROGRESS,
+	valueobject.StatusCompleted:  todov1.TaskStatus_TASK_STATUS_COMPLETED,
+}
+
+var eventTypeToProto = map[domainTask.EventType]todov1.TaskEventType{
+	domainTask.EventCreated:       todov1.TaskEventType_TASK_EVENT_TYPE_CREATED,
+	domainTask.EventUpdated:       todov1.TaskEventType_TASK_EVENT_TYPE_UPDATED,
+	domainTask.EventStatusChanged: todov1.TaskEventType_TASK_EVENT_TYPE_STATUS_CHANGED,
+	domainTask.EventDeleted:       todov1.TaskEventType_TASK_EVENT_TYPE_DELETED,
+}
+
+func newTask(task *domainTask.Task) *todov1.Task {
+	resp := &todov1.Task{
+		Id:          task.ID,
+		Title:       task.Title,
+		Description: task.Description,
+		Priority:    todov1.TaskPriority(task.Priority),
+		Status:      statusToProto[task.Status],
+		UserId:      task.UserID,
+		CreatedAt:   timestamppb.New(task.CreatedAt),
+	}
+	if task.UpdatedAt != nil {
+		resp.UpdatedAt = timestamppb.New(*task.UpdatedAt)
+	}
+	return resp
+}
+
+func newTaskEvent(event domainTask.Event) *todov1.TaskEvent {
+	return &todov1.TaskEvent{
+		Id:         event.ID,
+		Type:       eventTypeToProto[event.Type],
+		TaskId:     event.TaskID,
+		OccurredAt: timestamppb.New(event.OccurredAt),
+		Task:       newTask(&event.Task),
+	}
+}
@@ -0,0 +1,68 @@
+package grpc
+
+import (
+	"context"
+
+	todov1 "github.com/hoyci/todo-ddd/api/proto/todo/v1"
+	domainUser "github.com/hoyci/todo-ddd/pkg/domain/user"
+	"github.com/hoyci/todo-ddd/pkg/policy"
+	usecaseuser "github.com/hoyci/todo-ddd/pkg/usecase/user"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type UserService struct {
+	todov1.UnimplementedUserServiceServer
+
+	CreateUC *usecaseuser.CreateUserUseCase
+	FindUC   policy.UseCase[usecaseuser.FindUserInput, *usecaseuser.FindUserOutput]
+	UpdateUC policy.UseCase[usecaseuser.UpdateUserInput, *usecaseuser.UpdateUserOutput]
+	DeleteUC policy.Command[usecaseuser.DeleteUserInput]
+}
+
+func (s *UserService) CreateUser(ctx context.Context, req *todov1.CreateUserRequest) (*todov1.User, error) {
+	out, err := s.CreateUC.Execute(ctx, usecaseuser.CreateUserInput{Name: req.GetName(), Email: req.GetEmail()})
+	if err != nil {
+		return nil, err
+	}
+	return newUser(out.User), nil
+}
+
+func (s *UserService) GetUser(ctx context.Context, req *todov1.GetUserRequest) (*todov1.User, error) {
+	out, err := s.FindUC.Execute(ctx, usecaseuser.FindUserInput{ID: req.GetId()})
+	if err != nil {
+		return nil, err
+	}
+	return newUser(out.User), nil
+}
+
+func (s *UserService) UpdateUser(ctx context.Context, req *todov1.UpdateUserRequest) (*todov1.User, error) {
+	out, err := s.UpdateUC.Execute(ctx, usecaseuser.UpdateUserInput{
+		ID:    req.GetId(),
+		Name:  req.GetName(),
+		Email: req.GetEmail(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return newUser(out.User), nil
+}
+
+func (s *UserService) DeleteUser(ctx context.Context, req *todov1.DeleteUserRequest) (*todov1.DeleteUserResponse, error) {
+	if err := s.DeleteUC.Execute(ctx, usecaseuser.DeleteUserInput{ID: req.GetId()}); err != nil {
+		return nil, err
+	}
+	return &todov1.DeleteUserResponse{}, nil
+}
+
+func newUser(user *domainUser.User) *todov1.User {
+	resp := &todov1.User{
+		Id:        user.ID,
+		Name:      user.Name,
+		Email:     user.Email,
+		CreatedAt: timestamppb.New(user.CreatedAt),
+	}
+	if user.UpdatedAt != nil {
+		resp.UpdatedAt = timestamppb.New(*user.UpdatedAt)
+	}
+	return resp
+}
@@ -0,0 +1,52 @@
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"time"
+)
+
+const Header = "Idempotency-Key"
+
+var ErrKeyNotFound = errors.New("idempotency key not found")
+
+type Response struct {
+	StatusCode  int
+	ContentType string
+	// Headers são os cabeçalhos da resposta original que o cliente precisa
+	// receber de novo na repetição, como Location e X-Undo-Token.
+	Headers http.Header
+	Body    []byte
+}
+
+// Record é o estado de uma chave já vista. Enquanto Completed for false a
+// requisição original ainda está em processamento.
+type Record struct {
+	Fingerprint string
+	Completed   bool
+	Response    Response
+}
+
+// Store persiste as chaves. Reserve deve ser atômico: entre requisições
+// concorrentes com a mesma chave, apenas uma recebe reserved == true.
+type Store interface {
+	Reserve(ctx context.Context, scope, key, fingerprint string, now time.Time) (existing *Record, reserved bool, err error)
+	Complete(ctx context.Context, scope, key string, response Response, now time.Time) error
+	Release(ctx context.Context, scope, key string) error
+}
+
+// Fingerprint identifica o conteúdo da requisição, para detectar o reuso de
+// uma chave com outro payload. target é o caminho com a query string, que
+// também muda o efeito da requisição (ex: dry_run na importação).
+func Fingerprint(method, target string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method))
+	h.Write([]byte{0})
+	h.Write([]byte(target))
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
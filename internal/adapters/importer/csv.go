@@ -0,0 +1,107 @@
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	usecasetask "github.com/hoyci/todo-ddd/pkg/usecase/task"
+)
+
+// csvFields são os campos que podem ser mapeados para colunas. Sem
+// mapeamento, a coluna tem o nome do campo, como na exportação.
+var csvFields = []string{"title", "description", "priority", "status"}
+
+var ErrMissingTitleColumn = errors.New("csv has no title column")
+
+type UnknownFieldError struct {
+	Field string
+}
+
+func (e *UnknownFieldError) Error() string {
+	return fmt.Sprintf("unknown field %q; mappable fields are %s", e.Field, strings.Join(csvFields, ", "))
+}
+
+type MissingColumnError struct {
+	Column string
+}
+
+func (e *MissingColumnError) Error() string {
+	return fmt.Sprintf("csv has no column %q", e.Column)
+}
+
+// ParseCSV lê um CSV com cabeçalho. mapping associa campos a nomes de
+// coluna (ex: title → "Tarefa"); os nomes são comparados sem diferenciar
+// maiúsculas. Colunas não mapeadas são ignoradas.
+func ParseCSV(r io.Reader, mapping map[string]string) ([]usecasetask.ImportRow, error) {
+	for field := range mapping {
+		if !slices.Contains(csvFields, field) {
+			return nil, &UnknownFieldError{Field: field}
+		}
+	}
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, ErrMissingTitleColumn
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make(map[string]int, len(csvFields))
+	for _, field := range csvFields {
+		name, mapped := mapping[field]
+		if !mapped {
+			name = field
+		}
+		// Planilhas salvas como "CSV UTF-8" começam com BOM.
+		index := slices.IndexFunc(header, func(h string) bool {
+			return strings.EqualFold(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")), name)
+		})
+		switch {
+		case index >= 0:
+			columns[field] = index
+		case field == "title" && !mapped:
+			return nil, ErrMissingTitleColumn
+		case mapped:
+			return nil, &MissingColumnError{Column: name}
+		}
+	}
+
+	var rows []usecasetask.ImportRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+
+		value := func(field string) string {
+			index, ok := columns[field]
+			if !ok || index >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[index])
+		}
+
+		row := usecasetask.ImportRow{
+			Line:        line,
+			Title:       value("title"),
+			Description: value("description"),
+			Status:      strings.ToLower(value("status")),
+		}
+		row.Priority, row.Err = parsePriority(value("priority"))
+		rows = append(rows, row)
+	}
+}
@@ -0,0 +1,71 @@
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/hoyci/todo-ddd/pkg/domain/valueobject"
+	"github.com/hoyci/todo-ddd/pkg/usecase"
+	usecasetask "github.com/hoyci/todo-ddd/pkg/usecase/task"
+)
+
+type Format string
+
+const (
+	FormatCSV     Format = "csv"
+	FormatTodoTxt Format = "todotxt"
+	FormatJSON    Format = "json"
+)
+
+var ErrUnsupportedFormat = errors.New("format must be one of csv, todotxt, json")
+
+func ParseFormat(raw string) (Format, error) {
+	switch format := Format(raw); format {
+	case FormatCSV, FormatTodoTxt, FormatJSON:
+		return format, nil
+	default:
+		return "", ErrUnsupportedFormat
+	}
+}
+
+// Parse lê o arquivo no formato indicado. Erros que impedem a leitura do
+// arquivo inteiro (JSON malformado, CSV sem coluna de título) são
+// devolvidos; problemas de uma linha só ficam em ImportRow.Err.
+func Parse(format Format, r io.Reader, mapping map[string]string) ([]usecasetask.ImportRow, error) {
+	switch format {
+	case FormatCSV:
+		return ParseCSV(r, mapping)
+	case FormatTodoTxt:
+		return ParseTodoTxt(r)
+	case FormatJSON:
+		return ParseJSON(r)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
+
+var priorityNames = map[string]valueobject.Priority{
+	"low":    valueobject.Low,
+	"medium": valueobject.Medium,
+	"high":   valueobject.High,
+}
+
+// parsePriority aceita o número (1 a 3) ou o nome da prioridade; vazio é
+// zero, "não informada". A faixa é validada pelo caso de uso.
+func parsePriority(raw string) (int, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return 0, nil
+	}
+	if p, ok := priorityNames[strings.ToLower(raw)]; ok {
+		return int(p), nil
+	}
+	p, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, fmt.Errorf("%w: priority %q is not a number or one of low, medium, high", usecase.ErrInvalidImportRow, raw)
+	}
+	return p, nil
+}
@@ -0,0 +1,197 @@
+package importer
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hoyci/todo-ddd/pkg/usecase"
+	usecasetask "github.com/hoyci/todo-ddd/pkg/usecase/task"
+)
+
+// checkRows compara as linhas lidas; Err é comparado com errors.Is.
+func checkRows(t *testing.T, got, want []usecasetask.ImportRow) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d rows, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if !errors.Is(got[i].Err, want[i].Err) {
+			t.Errorf("row %d error = %v, want %v", i, got[i].Err, want[i].Err)
+		}
+		g, w := got[i], want[i]
+		g.Err, w.Err = nil, nil
+		if !reflect.DeepEqual(g, w) {
+			t.Errorf("row %d = %+v, want %+v", i, g, w)
+		}
+	}
+}
+
+func TestParseCSV(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		mapping map[string]string
+		want    []usecasetask.ImportRow
+		wantErr error
+	}{
+		{
+			name:  "export header",
+			input: "id,title,description,priority,status\n1,Buy milk,2 liters,3,Completed\n2,Call mom,,,\n",
+			want: []usecasetask.ImportRow{
+				{Line: 2, Title: "Buy milk", Description: "2 liters", Priority: 3, Status: "completed"},
+				{Line: 3, Title: "Call mom"},
+			},
+		},
+		{
+			name:    "mapped columns with BOM and any case",
+			input:   "\ufeffTarefa,Notas\nRegar plantas,high\n",
+			mapping: map[string]string{"title": "tarefa"},
+			want:    []usecasetask.ImportRow{{Line: 2, Title: "Regar plantas"}},
+		},
+		{
+			name:    "mapped priority column",
+			input:   "Tarefa,Urgência\nRegar plantas,high\n\nPagar conta, 2 \n",
+			mapping: map[string]string{"title": "Tarefa", "priority": "urgência"},
+			want: []usecasetask.ImportRow{
+				{Line: 2, Title: "Regar plantas", Priority: 3},
+				{Line: 4, Title: "Pagar conta", Priority: 2},
+			},
+		},
+		{
+			name:  "invalid priority stays in the row",
+			input: "title,priority\nTask,urgent\n",
+			want:  []usecasetask.ImportRow{{Line: 2, Title: "Task", Err: usecase.ErrInvalidImportRow}},
+		},
+		{
+			name:  "short record",
+			input: "title,description,priority\nOnly title\n",
+			want:  []usecasetask.ImportRow{{Line: 2, Title: "Only title"}},
+		},
+		{
+			name:    "no title column",
+			input:   "name,priority\nTask,1\n",
+			wantErr: ErrMissingTitleColumn,
+		},
+		{
+			name:    "empty file",
+			input:   "",
+			wantErr: ErrMissingTitleColumn,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := ParseCSV(strings.NewReader(tt.input), tt.mapping)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			checkRows(t, rows, tt.want)
+		})
+	}
+}
+
+func TestParseCSVMappingErrors(t *testing.T) {
+	var unknown *UnknownFieldError
+	if _, err := ParseCSV(strings.NewReader("title\nx\n"), map[string]string{"due": "Prazo"}); !errors.As(err, &unknown) || unknown.Field != "due" {
+		t.Errorf("unknown field error = %v", err)
+	}
+	var missing *MissingColumnError
+	if _, err := ParseCSV(strings.NewReader("title\nx\n"), map[string]string{"description": "Notes"}); !errors.As(err, &missing) || missing.Column != "Notes" {
+		t.Errorf("missing column error = %v", err)
+	}
+}
+
+func TestParseTodoTxt(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []usecasetask.ImportRow
+	}{
+		{
+			name:  "priority and creation date",
+			input: "(A) 2026-01-15 Call mom +family @phone\n",
+			want:  []usecasetask.ImportRow{{Line: 1, Title: "Call mom", Description: "+family @phone", Priority: 3}},
+		},
+		{
+			name:  "completed with dates and pri tag",
+			input: "x 2026-01-20 2026-01-15 Pay rent pri:B\n",
+			want:  []usecasetask.ImportRow{{Line: 1, Title: "Pay rent", Status: "completed", Priority: 2}},
+		},
+		{
+			name:  "low priorities and blank lines",
+			input: "(C) Water plants\n\n(Z) Someday\n",
+			want: []usecasetask.ImportRow{
+				{Line: 1, Title: "Water plants", Priority: 1},
+				{Line: 3, Title: "Someday", Priority: 1},
+			},
+		},
+		{
+			name:  "lowercase x and lone sigils are words",
+			input: "xylophone lesson + @\n",
+			want:  []usecasetask.ImportRow{{Line: 1, Title: "xylophone lesson + @"}},
+		},
+		{
+			name:  "priority must be followed by space",
+			input: "(A)no space\n",
+			want:  []usecasetask.ImportRow{{Line: 1, Title: "(A)no space"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := ParseTodoTxt(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkRows(t, rows, tt.want)
+		})
+	}
+}
+
+func TestParseTodoTxtLineTooLong(t *testing.T) {
+	input := "ok\n" + strings.Repeat("a", 70*1024) + "\n"
+	if _, err := ParseTodoTxt(strings.NewReader(input)); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("error = %v, want line 2 too long", err)
+	}
+}
+
+func TestParseJSON(t *testing.T) {
+	due := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	input := `[
+		{"id":"a","title":"Buy milk","priority":2,"status":"in_progress","due_at":"2026-03-01T12:00:00Z","tags":["home"],"recurrence":"FREQ=WEEKLY"},
+		{"id":"b","title":"Gone","priority":1,"status":"new","deleted_at":"2026-01-01T00:00:00Z"}
+	]`
+	rows, err := ParseJSON(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkRows(t, rows, []usecasetask.ImportRow{
+		{Line: 1, Title: "Buy milk", Priority: 2, Status: "in_progress", DueAt: &due, Tags: []string{"home"}, Recurrence: "FREQ=WEEKLY"},
+		{Line: 2, Title: "Gone", Priority: 1, Status: "new", Err: usecase.ErrInvalidImportRow},
+	})
+
+	if _, err := ParseJSON(strings.NewReader(`{"title":"not an array"}`)); err == nil {
+		t.Error("a JSON object was accepted")
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    Format
+		wantErr error
+	}{
+		{"csv", FormatCSV, nil},
+		{"todotxt", FormatTodoTxt, nil},
+		{"json", FormatJSON, nil},
+		{"CSV", "", ErrUnsupportedFormat},
+		{"xlsx", "", ErrUnsupportedFormat},
+	}
+	for _, tt := range tests {
+		got, err := ParseFormat(tt.raw)
+		if got != tt.want || !errors.Is(err, tt.wantErr) {
+			t.Errorf("ParseFormat(%q) = %q, %v; want %q, %v", tt.raw, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/hoyci/todo-ddd/internal/adapters/export"
+	"github.com/hoyci/todo-ddd/pkg/usecase"
+	usecasetask "github.com/hoyci/todo-ddd/pkg/usecase/task"
+)
+
+// ParseJSON lê o array gerado pela exportação JSON. IDs e datas não são
+// reaproveitados: cada item vira uma tarefa nova. Itens excluídos na
+// origem são reportados e não importados.
+func ParseJSON(r io.Reader) ([]usecasetask.ImportRow, error) {
+	var items []export.Task
+	if err := json.NewDecoder(r).Decode(&items); err != nil {
+		return nil, err
+	}
+
+	rows := make([]usecasetask.ImportRow, 0, len(items))
+	for i, item := range items {
+		row := usecasetask.ImportRow{
+			Line:        i + 1,
+			Title:       item.Title,
+			Description: item.Description,
+			Priority:    item.Priority,
+			Status:      item.Status,
+			DueAt:       item.DueAt,
+			Tags:        item.Tags,
+			Recurrence:  item.Recurrence,
+		}
+		if item.DeletedAt != nil {
+			row.Err = fmt.Errorf("%w: task was deleted in the export", usecase.ErrInvalidImportRow)
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
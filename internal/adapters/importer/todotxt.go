@@ -0,0 +1,85 @@
+package importer
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/hoyci/todo-ddd/pkg/domain/valueobject"
+	usecasetask "github.com/hoyci/todo-ddd/pkg/usecase/task"
+)
+
+var (
+	todoTxtPriority = regexp.MustCompile(`^\(([A-Z])\)\s+`)
+	todoTxtDate     = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}\s+`)
+)
+
+// todoTxtPriorityFor mapeia (A) para alta, (B) para média e (C) em diante
+// para baixa.
+func todoTxtPriorityFor(letter string) int {
+	switch letter {
+	case "A":
+		return int(valueobject.High)
+	case "B":
+		return int(valueobject.Medium)
+	default:
+		return int(valueobject.Low)
+	}
+}
+
+// ParseTodoTxt lê o formato todo.txt (http://todotxt.org): "x" no início
+// marca a tarefa como concluída, (A) é a prioridade e as datas de conclusão
+// e criação são descartadas. As tarefas não têm projetos nem contextos,
+// então +projeto e @contexto saem do título e vão para a descrição, para
+// não se perderem.
+func ParseTodoTxt(r io.Reader) ([]usecasetask.ImportRow, error) {
+	var rows []usecasetask.ImportRow
+	scanner := bufio.NewScanner(r)
+	line := 1
+	for ; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		row := usecasetask.ImportRow{Line: line}
+
+		if strings.HasPrefix(text, "x ") {
+			row.Status = string(valueobject.StatusCompleted)
+			text = strings.TrimSpace(text[2:])
+			// Data de conclusão.
+			text = todoTxtDate.ReplaceAllString(text, "")
+		}
+		if m := todoTxtPriority.FindStringSubmatch(text); m != nil {
+			row.Priority = todoTxtPriorityFor(m[1])
+			text = text[len(m[0]):]
+		}
+		// Data de criação.
+		text = todoTxtDate.ReplaceAllString(text, "")
+
+		var words, tags []string
+		for _, word := range strings.Fields(text) {
+			switch {
+			case len(word) > 1 && (word[0] == '+' || word[0] == '@'):
+				tags = append(tags, word)
+			case strings.HasPrefix(word, "pri:") && len(word) == 5:
+				// Tarefas concluídas guardam a prioridade como pri:A.
+				row.Priority = todoTxtPriorityFor(strings.ToUpper(word[4:]))
+			default:
+				words = append(words, word)
+			}
+		}
+		row.Title = strings.Join(words, " ")
+		row.Description = strings.Join(tags, " ")
+		rows = append(rows, row)
+	}
+	if err := scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return nil, fmt.Errorf("line %d is too long", line)
+		}
+		return nil, err
+	}
+	return rows, nil
+}
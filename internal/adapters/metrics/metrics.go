@@ -0,0 +1,98 @@
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hoyci/todo-ddd/pkg/usecase"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "todo"
+
+// Metrics concentra os coletores Prometheus da aplicação: requisições HTTP,
+// execuções de casos de uso, pool de conexões e gauges de negócio.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequests *prometheus.CounterVec
+	httpDuration *prometheus.HistogramVec
+
+	useCaseExecutions *prometheus.CounterVec
+	useCaseDuration   *prometheus.HistogramVec
+	useCaseErrors     *prometheus.CounterVec
+}
+
+func New(db *sql.DB, tasks OpenTaskCounter) *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "Total number of HTTP requests by method, route and status.",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency by method, route and status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		useCaseExecutions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "usecase_executions_total",
+			Help:      "Total number of use case executions by outcome.",
+		}, []string{"usecase", "outcome"}),
+		useCaseDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "usecase_duration_seconds",
+			Help:      "Use case execution latency.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"usecase"}),
+		useCaseErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "usecase_errors_total",
+			Help:      "Total number of use case errors by kind.",
+		}, []string{"usecase", "kind"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		collectors.NewDBStatsCollector(db, "app"),
+		newTaskCollector(tasks),
+		m.httpRequests,
+		m.httpDuration,
+		m.useCaseExecutions,
+		m.useCaseDuration,
+		m.useCaseErrors,
+	)
+
+	return m
+}
+
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+func (m *Metrics) ObserveHTTPRequest(method, route string, status int, duration time.Duration) {
+	code := strconv.Itoa(status)
+	m.httpRequests.WithLabelValues(method, route, code).Inc()
+	m.httpDuration.WithLabelValues(method, route, code).Observe(duration.Seconds())
+}
+
+// ObserveUseCase implementa usecase.Observer.
+func (m *Metrics) ObserveUseCase(name string, duration time.Duration, err error) {
+	m.useCaseDuration.WithLabelValues(name).Observe(duration.Seconds())
+
+	if err == nil {
+		m.useCaseExecutions.WithLabelValues(name, "success").Inc()
+		return
+	}
+
+	m.useCaseExecutions.WithLabelValues(name, "error").Inc()
+	m.useCaseErrors.WithLabelValues(name, usecase.ErrorKind(err)).Inc()
+}
@@ -0,0 +1,57 @@
+package metrics
+
+import (
+	"context"
+	"strconv"
+
+	domain "github.com/hoyci/todo-ddd/pkg/domain/task"
+	"github.com/hoyci/todo-ddd/pkg/logging"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type OpenTaskCounter interface {
+	CountOpen(ctx context.Context) ([]domain.OpenTaskCount, error)
+}
+
+// taskCollector consulta o banco a cada scrape, evitando manter gauges de
+// negócio sincronizados manualmente com cada caso de uso.
+type taskCollector struct {
+	counter   OpenTaskCounter
+	openTasks *prometheus.Desc
+}
+
+func newTaskCollector(counter OpenTaskCounter) *taskCollector {
+	return &taskCollector{
+		counter: counter,
+		openTasks: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "tasks", "open"),
+			"Number of open tasks by status and priority.",
+			[]string{"status", "priority"},
+			nil,
+		),
+	}
+}
+
+func (c *taskCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.openTasks
+}
+
+func (c *taskCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx := context.Background()
+	counts, err := c.counter.CountOpen(ctx)
+	if err != nil {
+		logging.ForPackage(ctx, "metrics").Error("error counting open tasks", "error", err)
+		ch <- prometheus.NewInvalidMetric(c.openTasks, err)
+		return
+	}
+
+	for _, count := range counts {
+		ch <- prometheus.MustNewConstMetric(
+			c.openTasks,
+			prometheus.GaugeValue,
+			float64(count.Count),
+			string(count.Status),
+			strconv.Itoa(int(count.Priority)),
+		)
+	}
+}
@@ -0,0 +1,29 @@
+package notify
+
+import (
+	"context"
+	"errors"
+
+	domainNotification "github.com/hoyci/todo-ddd/pkg/domain/notification"
+	"github.com/hoyci/todo-ddd/pkg/domain/valueobject"
+)
+
+var errNoEmail = errors.New("recipient has no email address")
+
+// EmailChannel entrega notificações como email pelo Mailer configurado.
+type EmailChannel struct {
+	Mailer domainNotification.Mailer
+}
+
+func (c *EmailChannel) Name() valueobject.NotificationChannel { return valueobject.ChannelEmail }
+
+func (c *EmailChannel) Send(ctx context.Context, recipient domainNotification.Recipient, notification *domainNotification.Notification) error {
+	if recipient.Email == "" {
+		return errNoEmail
+	}
+	return c.Mailer.Send(ctx, domainNotification.Message{
+		To:      recipient.Email,
+		Subject: notification.Title,
+		Body:    notification.Body + "\n",
+	})
+}
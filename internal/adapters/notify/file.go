@@ -0,0 +1,69 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	domainNotification "github.com/hoyci/todo-ddd/pkg/domain/notification"
+	"github.com/hoyci/todo-ddd/pkg/logging"
+)
+
+// FileMailer grava cada email como um arquivo .eml em Dir, para inspecionar
+// as mensagens no desenvolvimento local sem um servidor SMTP.
+type FileMailer struct {
+	Dir string
+}
+
+func NewFileMailer(dir string) (*FileMailer, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create mail dir: %w", err)
+	}
+	return &FileMailer{Dir: dir}, nil
+}
+
+// Send grava em um arquivo temporário e o renomeia, para que quem observa o
+// diretório nunca leia uma mensagem pela metade.
+func (m *FileMailer) Send(_ context.Context, message domainNotification.Message) error {
+	now := time.Now().UTC()
+	name := now.Format("20060102T150405.000000000Z") + "-" + uuid.New().String()[:8] + ".eml"
+
+	tmp, err := os.CreateTemp(m.Dir, ".mail-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.WriteString(formatMessage(message, now)); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(m.Dir, name))
+}
+
+// LogMailer só registra os emails no log. É o padrão quando nem SMTP nem
+// diretório de emails estão configurados.
+type LogMailer struct{}
+
+func (LogMailer) Send(ctx context.Context, message domainNotification.Message) error {
+	logging.ForPackage(ctx, "notify").Info("email not sent, logged only",
+		"to", message.To, "subject", message.Subject, "body", message.Body)
+	return nil
+}
+
+func formatMessage(message domainNotification.Message, at time.Time) string {
+	var b strings.Builder
+	b.WriteString("To: " + message.To + "\n")
+	b.WriteString("Subject: " + message.Subject + "\n")
+	b.WriteString("Date: " + at.Format(time.RFC1123Z) + "\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\n\n")
+	b.WriteString(message.Body)
+	return b.String()
+}
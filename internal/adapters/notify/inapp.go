@@ -0,0 +1,19 @@
+package notify
+
+import (
+	"context"
+
+	domainNotification "github.com/hoyci/todo-ddd/pkg/domain/notification"
+	"github.com/hoyci/todo-ddd/pkg/domain/valueobject"
+)
+
+// InAppChannel guarda a notificação na caixa de entrada do usuário.
+type InAppChannel struct {
+	Inbox domainNotification.InboxRepository
+}
+
+func (c *InAppChannel) Name() valueobject.NotificationChannel { return valueobject.ChannelInApp }
+
+func (c *InAppChannel) Send(ctx context.Context, _ domainNotification.Recipient, notification *domainNotification.Notification) error {
+	return c.Inbox.Save(ctx, notification)
+}
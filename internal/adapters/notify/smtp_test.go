@@ -0,0 +1,189 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"io"
+	"mime"
+	"net"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	domainNotification "github.com/hoyci/todo-ddd/pkg/domain/notification"
+)
+
+// smtpSession é o que o servidor de teste recebeu em uma conexão.
+type smtpSession struct {
+	auth string
+	from string
+	rcpt []string
+	data string
+}
+
+// fakeSMTP atende uma única conexão em 127.0.0.1 com o mínimo do protocolo
+// (sem STARTTLS) e envia a sessão recebida no canal.
+func fakeSMTP(t *testing.T) (string, <-chan smtpSession) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	sessions := make(chan smtpSession, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+		r := bufio.NewReader(conn)
+		reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+		var session smtpSession
+
+		reply("220 fake ESMTP")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimRight(line, "\r\n")
+			verb, arg, _ := strings.Cut(line, " ")
+			switch strings.ToUpper(verb) {
+			case "EHLO":
+				reply("250-fake")
+				reply("250-8BITMIME")
+				reply("250 AUTH PLAIN")
+			case "AUTH":
+				session.auth = strings.TrimPrefix(arg, "PLAIN ")
+				reply("235 ok")
+			case "MAIL":
+				session.from = arg
+				reply("250 ok")
+			case "RCPT":
+				session.rcpt = append(session.rcpt, arg)
+				reply("250 ok")
+			case "DATA":
+				reply("354 go ahead")
+				var data strings.Builder
+				for {
+					line, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if line == ".\r\n" {
+						break
+					}
+					data.WriteString(line)
+				}
+				session.data = data.String()
+				reply("250 queued")
+			case "QUIT":
+				reply("221 bye")
+				sessions <- session
+				return
+			default:
+				reply("502 not implemented")
+			}
+		}
+	}()
+	return listener.Addr().String(), sessions
+}
+
+func TestSMTPMailer(t *testing.T) {
+	tests := []struct {
+		name     string
+		username string
+		wantAuth string
+	}{
+		{"anonymous", "", ""},
+		{"authenticated", "mailer", base64.StdEncoding.EncodeToString([]byte("\x00mailer\x00secret"))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr, sessions := fakeSMTP(t)
+			mailer, err := NewSMTPMailer(SMTPConfig{Addr: addr, From: "Todo <todo@example.com>", Username: tt.username, Password: "secret"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = mailer.Send(context.Background(), domainNotification.Message{
+				To:      "Ada <ada@example.com>",
+				Subject: "Lembrete: revisão",
+				Body:    "Due tomorrow\n.hidden line\n",
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			session := <-sessions
+
+			if session.auth != tt.wantAuth {
+				t.Errorf("AUTH = %q, want %q", session.auth, tt.wantAuth)
+			}
+			if session.from != "FROM:<todo@example.com>" && !strings.HasPrefix(session.from, "FROM:<todo@example.com> ") {
+				t.Errorf("MAIL %s", session.from)
+			}
+			if len(session.rcpt) != 1 || session.rcpt[0] != "TO:<ada@example.com>" {
+				t.Errorf("RCPT %v", session.rcpt)
+			}
+
+			// O writer do DATA dobra o ponto inicial; o leitor de mensagens
+			// espera o texto já desfeito.
+			if !strings.Contains(session.data, "\r\n..hidden line\r\n") {
+				t.Errorf("DATA did not dot-stuff the body: %q", session.data)
+			}
+			msg, err := mail.ReadMessage(strings.NewReader(strings.ReplaceAll(session.data, "\r\n..", "\r\n.")))
+			if err != nil {
+				t.Fatal(err)
+			}
+			headers := []struct{ name, want string }{
+				{"From", `"Todo" <todo@example.com>`},
+				{"To", `"Ada" <ada@example.com>`},
+				{"MIME-Version", "1.0"},
+				{"Content-Type", "text/plain; charset=utf-8"},
+				{"Content-Transfer-Encoding", "8bit"},
+			}
+			for _, h := range headers {
+				if got := msg.Header.Get(h.name); got != h.want {
+					t.Errorf("%s = %q, want %q", h.name, got, h.want)
+				}
+			}
+			subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+			if err != nil || subject != "Lembrete: revisão" {
+				t.Errorf("Subject = %q (%q), %v", subject, msg.Header.Get("Subject"), err)
+			}
+			if _, err := msg.Header.Date(); err != nil {
+				t.Errorf("Date header: %v", err)
+			}
+			body, err := io.ReadAll(msg.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(body) != "Due tomorrow\r\n.hidden line\r\n" {
+				t.Errorf("body = %q", body)
+			}
+		})
+	}
+}
+
+func TestSMTPMailerInvalidAddresses(t *testing.T) {
+	if _, err := NewSMTPMailer(SMTPConfig{Addr: "localhost", From: "todo@example.com"}); err == nil {
+		t.Error("an address without port was accepted")
+	}
+	if _, err := NewSMTPMailer(SMTPConfig{Addr: "localhost:25", From: "not an address"}); err == nil {
+		t.Error("an invalid sender was accepted")
+	}
+
+	mailer, err := NewSMTPMailer(SMTPConfig{Addr: "127.0.0.1:1", From: "todo@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mailer.Send(context.Background(), domainNotification.Message{To: "nobody"}); err == nil {
+		t.Error("an invalid recipient was accepted")
+	}
+}
@@ -0,0 +1,87 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	domainNotification "github.com/hoyci/todo-ddd/pkg/domain/notification"
+	"github.com/hoyci/todo-ddd/pkg/domain/valueobject"
+)
+
+// SignatureHeader leva o HMAC-SHA256 do corpo, em hexadecimal, quando há
+// segredo configurado.
+const SignatureHeader = "X-Todo-Signature"
+
+var errNoWebhookURL = errors.New("recipient has no webhook URL")
+
+// WebhookChannel envia a notificação em JSON por POST para a URL
+// cadastrada nas preferências do usuário. Respostas fora de 2xx são erro.
+type WebhookChannel struct {
+	client *http.Client
+	secret []byte
+}
+
+// NewWebhookChannel assina os corpos com secret; vazio não assina.
+func NewWebhookChannel(secret string) *WebhookChannel {
+	return &WebhookChannel{client: &http.Client{Timeout: 10 * time.Second}, secret: []byte(secret)}
+}
+
+func (c *WebhookChannel) Name() valueobject.NotificationChannel { return valueobject.ChannelWebhook }
+
+type webhookPayload struct {
+	ID        string    `json:"id"`
+	Kind      string    `json:"kind"`
+	UserID    string    `json:"user_id"`
+	TaskID    string    `json:"task_id,omitempty"`
+	Title     string    `json:"title"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (c *WebhookChannel) Send(ctx context.Context, recipient domainNotification.Recipient, notification *domainNotification.Notification) error {
+	if recipient.WebhookURL == "" {
+		return errNoWebhookURL
+	}
+
+	body, err := json.Marshal(webhookPayload{
+		ID:        notification.ID,
+		Kind:      string(notification.Kind),
+		UserID:    notification.UserID,
+		TaskID:    notification.TaskID,
+		Title:     notification.Title,
+		Body:      notification.Body,
+		CreatedAt: notification.CreatedAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, recipient.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if len(c.secret) > 0 {
+		mac := hmac.New(sha256.New, c.secret)
+		mac.Write(body)
+		req.Header.Set(SignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook responded %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Limiter escolhe a política de cada rota e delega o consumo ao Store.
+// As rotas são identificadas por "MÉTODO /caminho/registrado", por exemplo
+// "POST /api/v1/tasks".
+type Limiter struct {
+	store    Store
+	policies map[string]Policy
+	fallback *Policy
+	now      func() time.Time
+}
+
+func NewLimiter(store Store, policies map[string]Policy, fallback *Policy) *Limiter {
+	return &Limiter{
+		store:    store,
+		policies: policies,
+		fallback: fallback,
+		now:      time.Now,
+	}
+}
+
+// PolicyFor devolve a política da rota ou a política padrão, se houver.
+func (l *Limiter) PolicyFor(method, route string) (Policy, bool) {
+	if policy, ok := l.policies[method+" "+route]; ok {
+		return policy, true
+	}
+	if l.fallback != nil {
+		return *l.fallback, true
+	}
+	return Policy{}, false
+}
+
+// Allow consome um token do bucket de cada sujeito, em ordem, e devolve o
+// resultado mais restritivo. Um sujeito negado interrompe a consulta, para
+// não gastar os buckets seguintes com uma requisição que não passará.
+func (l *Limiter) Allow(ctx context.Context, policy Policy, subjects ...string) (Result, error) {
+	var result Result
+	now := l.now()
+	for i, subject := range subjects {
+		taken, err := l.store.Take(ctx, policy.Name+":"+subject, policy, now)
+		if err != nil {
+			return Result{}, err
+		}
+		if i == 0 || stricter(taken, result) {
+			result = taken
+		}
+		if !taken.Allowed {
+			break
+		}
+	}
+	return result, nil
+}
+
+// stricter diz se a é mais restritivo que b: negado antes de permitido,
+// depois a maior espera ou o menor saldo.
+func stricter(a, b Result) bool {
+	switch {
+	case a.Allowed != b.Allowed:
+		return !a.Allowed
+	case !a.Allowed:
+		return a.RetryAfter > b.RetryAfter
+	default:
+		return a.Remaining < b.Remaining
+	}
+}
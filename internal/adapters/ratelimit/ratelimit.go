@@ -0,0 +1,74 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Policy descreve um token bucket: até Limit requisições em rajada, com
+// reposição contínua de Limit tokens a cada Window.
+type Policy struct {
+	Name   string
+	Limit  int
+	Window time.Duration
+}
+
+// Rate é a taxa de reposição em tokens por segundo.
+func (p Policy) Rate() float64 {
+	return float64(p.Limit) / p.Window.Seconds()
+}
+
+func (p Policy) String() string {
+	return fmt.Sprintf("%d;w=%d", p.Limit, int(p.Window.Seconds()))
+}
+
+type Result struct {
+	Allowed bool
+	Limit   int
+	// Remaining é a quantidade de tokens inteiros disponíveis após a requisição.
+	Remaining int
+	// Reset é o tempo até o bucket estar cheio novamente.
+	Reset time.Duration
+	// RetryAfter é o tempo até o próximo token, preenchido quando negado.
+	RetryAfter time.Duration
+}
+
+// Store guarda o estado dos buckets. Take deve consumir um token de forma
+// atômica, mesmo com vários processos compartilhando o store.
+type Store interface {
+	Take(ctx context.Context, key string, policy Policy, now time.Time) (Result, error)
+}
+
+// NewResult calcula cabeçalhos e tempos a partir do saldo de tokens após a
+// tentativa de consumo.
+func NewResult(policy Policy, tokens float64, allowed bool) Result {
+	rate := policy.Rate()
+	result := Result{
+		Allowed:   allowed,
+		Limit:     policy.Limit,
+		Remaining: int(math.Max(0, math.Floor(tokens))),
+		Reset:     secondsToDuration((float64(policy.Limit) - tokens) / rate),
+	}
+	if !allowed {
+		result.RetryAfter = secondsToDuration((1 - tokens) / rate)
+	}
+	return result
+}
+
+// refill devolve o saldo de tokens após a reposição desde a última atualização.
+func refill(policy Policy, tokens float64, updatedAt, now time.Time) float64 {
+	elapsed := now.Sub(updatedAt).Seconds()
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	return math.Min(float64(policy.Limit), tokens+elapsed*policy.Rate())
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	if seconds <= 0 {
+		return 0
+	}
+	return time.Duration(math.Ceil(seconds)) * time.Second
+}
@@ -0,0 +1,151 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestNewResult(t *testing.T) {
+	// Três tokens a cada três segundos: um por segundo.
+	policy := Policy{Name: "test", Limit: 3, Window: 3 * time.Second}
+	tests := []struct {
+		name    string
+		tokens  float64
+		allowed bool
+		want    Result
+	}{
+		{"full after one request", 2, true, Result{Allowed: true, Limit: 3, Remaining: 2, Reset: time.Second}},
+		{"last token", 0, true, Result{Allowed: true, Limit: 3, Remaining: 0, Reset: 3 * time.Second}},
+		{"fraction left", 1.5, true, Result{Allowed: true, Limit: 3, Remaining: 1, Reset: 2 * time.Second}},
+		{"denied when empty", 0, false, Result{Limit: 3, Reset: 3 * time.Second, RetryAfter: time.Second}},
+		{"denied rounds up", 0.25, false, Result{Limit: 3, Reset: 3 * time.Second, RetryAfter: time.Second}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewResult(policy, tt.tokens, tt.allowed); got != tt.want {
+				t.Errorf("NewResult = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRefill(t *testing.T) {
+	policy := Policy{Name: "test", Limit: 3, Window: 3 * time.Second}
+	start := time.Unix(1_700_000_000, 0)
+	tests := []struct {
+		name    string
+		tokens  float64
+		elapsed time.Duration
+		want    float64
+	}{
+		{"no time passed", 1, 0, 1},
+		{"half a token", 0, 500 * time.Millisecond, 0.5},
+		{"two tokens", 0, 2 * time.Second, 2},
+		// A rajada nunca passa de Limit, por mais tempo que fique parado.
+		{"capped at the limit", 2, time.Hour, 3},
+		{"clock going back", 1, -time.Second, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := refill(policy, tt.tokens, start, start.Add(tt.elapsed)); got != tt.want {
+				t.Errorf("refill = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	store := NewMemoryStore()
+	policy := Policy{Name: "test", Limit: 3, Window: 3 * time.Second}
+	start := time.Unix(1_700_000_000, 0)
+
+	steps := []struct {
+		name       string
+		key        string
+		at         time.Duration
+		allowed    bool
+		remaining  int
+		retryAfter time.Duration
+	}{
+		{"burst 1", "a", 0, true, 2, 0},
+		{"burst 2", "a", 0, true, 1, 0},
+		{"burst 3", "a", 0, true, 0, 0},
+		{"burst exhausted", "a", 0, false, 0, time.Second},
+		{"other key has its own bucket", "b", 0, true, 2, 0},
+		{"half a token refilled", "a", 500 * time.Millisecond, false, 0, time.Second},
+		{"one token refilled", "a", time.Second, true, 0, 0},
+		{"refilled up to the limit only", "a", time.Hour, true, 2, 0},
+	}
+	for _, step := range steps {
+		got, err := store.Take(context.Background(), step.key, policy, start.Add(step.at))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Allowed != step.allowed || got.Remaining != step.remaining || got.RetryAfter != step.retryAfter {
+			t.Errorf("%s: Take = %+v, want allowed %v, remaining %d, retry after %s", step.name, got, step.allowed, step.remaining, step.retryAfter)
+		}
+	}
+
+	// Os buckets cheios são descartados na varredura seguinte.
+	if _, err := store.Take(context.Background(), "c", policy, start.Add(2*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := store.buckets["b"]; ok {
+		t.Error("a full bucket survived the sweep")
+	}
+}
+
+func TestLimiter(t *testing.T) {
+	policy := Policy{Name: "create", Limit: 2, Window: time.Minute}
+	fallback := Policy{Name: "default", Limit: 10, Window: time.Minute}
+	limiter := NewLimiter(NewMemoryStore(), map[string]Policy{"POST /tasks": policy}, &fallback)
+	now := time.Unix(1_700_000_000, 0)
+	limiter.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	if got, ok := limiter.PolicyFor("POST", "/tasks"); !ok || got != policy {
+		t.Errorf("PolicyFor(POST /tasks) = %+v, %v; want the route policy", got, ok)
+	}
+	if got, ok := limiter.PolicyFor("GET", "/tasks"); !ok || got != fallback {
+		t.Errorf("PolicyFor(GET /tasks) = %+v, %v; want the fallback", got, ok)
+	}
+	if _, ok := NewLimiter(NewMemoryStore(), nil, nil).PolicyFor("GET", "/tasks"); ok {
+		t.Error("PolicyFor without a fallback returned a policy")
+	}
+
+	// O resultado é o do bucket mais restritivo.
+	for range 2 {
+		if _, err := limiter.Allow(ctx, policy, "user:u1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got, err := limiter.Allow(ctx, policy, "ip:1", "user:u1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Allowed || got.RetryAfter != 30*time.Second {
+		t.Errorf("Allow with an exhausted user = %+v, want denied with a 30s retry", got)
+	}
+	got, err = limiter.Allow(ctx, policy, "ip:2", "user:u2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Allowed || got.Remaining != 1 {
+		t.Errorf("Allow for new subjects = %+v, want allowed with 1 remaining", got)
+	}
+
+	// Negado no primeiro sujeito, o seguinte não é consumido.
+	now = now.Add(time.Hour)
+	for range 2 {
+		if _, err := limiter.Allow(ctx, policy, "ip:3"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, _ := limiter.Allow(ctx, policy, "ip:3", "user:u3"); got.Allowed {
+		t.Fatal("Allow with an exhausted IP was allowed")
+	}
+	if got, _ := limiter.Allow(ctx, policy, "user:u3"); got.Remaining != 1 {
+		t.Errorf("user bucket after a denied IP: remaining = %d, want 1", got.Remaining)
+	}
+}
@@ -0,0 +1,136 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hoyci/todo-ddd/internal/adapters/api/middleware"
+	"github.com/hoyci/todo-ddd/internal/adapters/db/sqlite"
+	"github.com/hoyci/todo-ddd/internal/adapters/events"
+	usecasetask "github.com/hoyci/todo-ddd/pkg/usecase/task"
+	usecaseuser "github.com/hoyci/todo-ddd/pkg/usecase/user"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestNewExporter(t *testing.T) {
+	tests := []struct {
+		name    string
+		wantNil bool
+		wantErr bool
+	}{
+		{"", true, false},
+		{ExporterNone, true, false},
+		{ExporterStdout, false, false},
+		{"memory", true, true},
+		{"zipkin", true, true},
+	}
+	for _, tt := range tests {
+		exporter, err := NewExporter(context.Background(), tt.name)
+		if (err != nil) != tt.wantErr || (exporter == nil) != tt.wantNil {
+			t.Errorf("NewExporter(%q) = %v, %v", tt.name, exporter, err)
+		}
+	}
+}
+
+// TestSpans sobe o provider com um exporter em memória e confere que uma
+// requisição gera a cadeia handler → caso de uso → transação → query,
+// continuando o trace recebido no traceparent.
+func TestSpans(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	shutdown, err := Setup(Config{ServiceName: "todo-ddd-test", Exporter: exporter})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { shutdown(context.Background()) })
+	provider := otel.GetTracerProvider().(*sdktrace.TracerProvider)
+
+	t.Chdir(t.TempDir())
+	if err := os.Mkdir("data", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	db, err := sqlite.InitDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	unitOfWork := sqlite.NewSQLiteUnitOfWork(db, events.NewBroker(0))
+
+	user, err := (&usecaseuser.CreateUserUseCase{UoW: unitOfWork}).Execute(context.Background(), usecaseuser.CreateUserInput{Name: "Ada", Email: "ada@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	createUC := &usecasetask.CreateTaskUseCase{UoW: unitOfWork}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.Tracing())
+	router.POST("/tasks", func(c *gin.Context) {
+		_, err := createUC.Execute(c.Request.Context(), usecasetask.CreateTaskInput{Title: "Trace me", Priority: 1, UserID: user.User.ID})
+		if err != nil {
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+		c.Status(http.StatusCreated)
+	})
+
+	if err := provider.ForceFlush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	exporter.Reset()
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	const parentID = "00f067aa0ba902b7"
+	req := httptest.NewRequest(http.MethodPost, "/tasks", nil)
+	req.Header.Set("traceparent", "00-"+traceID+"-"+parentID+"-01")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d", rec.Code)
+	}
+	if got := rec.Header().Get("traceparent"); !strings.Contains(got, traceID) {
+		t.Errorf("response traceparent = %q, want the received trace", got)
+	}
+
+	if err := provider.ForceFlush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	spans := exporter.GetSpans()
+	byName := map[string]tracetest.SpanStub{}
+	for _, span := range spans {
+		if span.SpanContext.TraceID().String() != traceID {
+			t.Errorf("span %q is outside the received trace", span.Name)
+		}
+		if _, ok := byName[span.Name]; !ok {
+			byName[span.Name] = span
+		}
+	}
+
+	// Cada span deve ser filho do anterior na cadeia.
+	chain := []struct{ name, parent string }{
+		{"POST /tasks", ""},
+		{"usecase.create_task", "POST /tasks"},
+		{"sqlite.transaction", "usecase.create_task"},
+		{"sqlite.insert", "sqlite.transaction"},
+	}
+	for _, link := range chain {
+		span, ok := byName[link.name]
+		if !ok {
+			t.Errorf("no %q span among %d spans", link.name, len(spans))
+			continue
+		}
+		wantParent := parentID
+		if link.parent != "" {
+			wantParent = byName[link.parent].SpanContext.SpanID().String()
+		}
+		if got := span.Parent.SpanID().String(); got != wantParent {
+			t.Errorf("%q parent = %s, want %s (%s)", link.name, got, wantParent, link.parent)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+// Package domain define o log de auditoria: um registro só de inclusão das
+// mudanças de estado, encadeado por hash para evidenciar adulterações.
+package domain
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+var ErrChainBroken = errors.New("audit chain is broken")
+
+// Entry registra uma mudança de estado de uma entidade. Seq, PrevHash e Hash
+// são atribuídos pelo repositório ao encadear a entrada.
+type Entry struct {
+	Seq        int64
+	OccurredAt time.Time
+	// ActorID é quem executou a ação; vazio nas operações do sistema.
+	ActorID    string
+	Action     string
+	EntityType string
+	EntityID   string
+	// Changes é o JSON dos campos alterados, cada um com os valores antes e
+	// depois da ação. É guardado como gerado, pois o hash cobre esses bytes.
+	Changes   json.RawMessage
+	RequestID string
+	IP        string
+	PrevHash  string
+	Hash      string
+}
+
+// Change é o valor de um campo antes e depois da ação; null quando a
+// entidade não existia ou deixou de existir.
+type Change struct {
+	Before json.RawMessage `json:"before"`
+	After  json.RawMessage `json:"after"`
+}
+
+// NewEntry monta a entrada da ação sobre a entidade, calculando a diferença
+// entre before e after. Qualquer um deles pode ser nil, na criação e na
+// remoção definitiva.
+func NewEntry(action, entityType, entityID string, before, after any) (*Entry, error) {
+	changes, err := Diff(before, after)
+	if err != nil {
+		return nil, err
+	}
+	return &Entry{
+		OccurredAt: time.Now().UTC(),
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Changes:    changes,
+	}, nil
+}
+
+// Diff compara as representações JSON de before e after campo a campo e
+// devolve só os que mudaram.
+func Diff(before, after any) (json.RawMessage, error) {
+	old, err := fields(before)
+	if err != nil {
+		return nil, err
+	}
+	updated, err := fields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := map[string]Change{}
+	// Campo ausente equivale a null; null -> null não é mudança.
+	for name, value := range old {
+		if after := nullable(updated[name]); !bytes.Equal(value, after) {
+			changes[name] = Change{Before: value, After: after}
+		}
+	}
+	for name, value := range updated {
+		if _, ok := old[name]; !ok && string(value) != "null" {
+			changes[name] = Change{Before: json.RawMessage("null"), After: value}
+		}
+	}
+	return json.Marshal(changes)
+}
+
+func fields(entity any) (map[string]json.RawMessage, error) {
+	if entity == nil {
+		return nil, nil
+	}
+	raw, err := json.Marshal(entity)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, fmt.Errorf("audit snapshot must be an object: %w", err)
+	}
+	return fields, nil
+}
+
+func nullable(value json.RawMessage) json.RawMessage {
+	if value == nil {
+		return json.RawMessage("null")
+	}
+	return value
+}
+
+// ComputeHash calcula o hash da entrada, que cobre todos os campos e o hash
+// da anterior.
+func (e *Entry) ComputeHash() string {
+	h := sha256.New()
+	for _, field := range []string{
+		strconv.FormatInt(e.Seq, 10),
+		e.OccurredAt.UTC().Format(time.RFC3339Nano),
+		e.ActorID,
+		e.Action,
+		e.EntityType,
+		e.EntityID,
+		string(e.Changes),
+		e.RequestID,
+		e.IP,
+		e.PrevHash,
+	} {
+		h.Write([]byte(field))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Verify confere que a entrada segue prevHash e que o hash gravado
+// corresponde ao conteúdo.
+func (e *Entry) Verify(prevHash string) error {
+	if e.PrevHash != prevHash {
+		return fmt.Errorf("%w: entry %d does not follow the previous one", ErrChainBroken, e.Seq)
+	}
+	if e.Hash != e.ComputeHash() {
+		return fmt.Errorf("%w: entry %d was modified", ErrChainBroken, e.Seq)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDiff(t *testing.T) {
+	type task struct {
+		Title    string     `json:"title"`
+		Priority int        `json:"priority"`
+		DueAt    *time.Time `json:"due_at"`
+	}
+	due := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		before any
+		after  any
+		want   string
+	}{
+		{"unchanged", task{Title: "a", Priority: 1}, task{Title: "a", Priority: 1}, `{}`},
+		{"one field", task{Title: "a", Priority: 1}, task{Title: "b", Priority: 1}, `{"title":{"before":"a","after":"b"}}`},
+		{"created", nil, task{Title: "a", Priority: 1}, `{"priority":{"before":null,"after":1},"title":{"before":null,"after":"a"}}`},
+		{"removed", task{Title: "a", Priority: 1}, nil, `{"priority":{"before":1,"after":null},"title":{"before":"a","after":null}}`},
+		{"pointer set", task{Title: "a"}, task{Title: "a", DueAt: &due}, `{"due_at":{"before":null,"after":"2026-05-01T00:00:00Z"}}`},
+		{"nothing to nothing", nil, nil, `{}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Diff(tt.before, tt.after)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var gotMap, wantMap map[string]Change
+			if err := json.Unmarshal(got, &gotMap); err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal([]byte(tt.want), &wantMap); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(gotMap, wantMap) {
+				t.Errorf("Diff = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := Diff("not an object", nil); err == nil {
+		t.Error("Diff accepted a snapshot that is not an object")
+	}
+}
+
+func TestEntryVerify(t *testing.T) {
+	newChain := func() []*Entry {
+		var chain []*Entry
+		prev := ""
+		for i, action := range []string{"task.created", "task.updated", "task.deleted"} {
+			entry, err := NewEntry(action, "task", "t1", nil, map[string]int{"n": i})
+			if err != nil {
+				t.Fatal(err)
+			}
+			entry.Seq, entry.PrevHash = int64(i+1), prev
+			entry.Hash = entry.ComputeHash()
+			prev = entry.Hash
+			chain = append(chain, entry)
+		}
+		return chain
+	}
+
+	tests := []struct {
+		name    string
+		tamper  func(chain []*Entry) []*Entry
+		wantErr bool
+	}{
+		{"intact", func(c []*Entry) []*Entry { return c }, false},
+		{"modified action", func(c []*Entry) []*Entry { c[1].Action = "task.restored"; return c }, true},
+		{"modified changes", func(c []*Entry) []*Entry { c[1].Changes = json.RawMessage(`{}`); return c }, true},
+		{"modified time", func(c []*Entry) []*Entry { c[1].OccurredAt = c[1].OccurredAt.Add(time.Nanosecond); return c }, true},
+		{"modified actor", func(c []*Entry) []*Entry { c[0].ActorID = "intruder"; return c }, true},
+		{"rehashed after change", func(c []*Entry) []*Entry {
+			c[1].Action = "task.restored"
+			c[1].Hash = c[1].ComputeHash()
+			return c
+		}, true},
+		{"removed entry", func(c []*Entry) []*Entry { return []*Entry{c[0], c[2]} }, true},
+		{"reordered", func(c []*Entry) []*Entry { return []*Entry{c[0], c[2], c[1]} }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var err error
+			prev := ""
+			for _, entry := range tt.tamper(newChain()) {
+				if err = entry.Verify(prev); err != nil {
+					break
+				}
+				prev = entry.Hash
+			}
+			if (err != nil) != tt.wantErr || err != nil && !errors.Is(err, ErrChainBroken) {
+				t.Errorf("Verify error = %v, want broken = %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Query filtra o log em ordem de Seq. Campos vazios não filtram; AfterSeq é
+// a última entrada da página anterior e Limit zero devolve todas.
+type Query struct {
+	ActorID    string
+	EntityType string
+	EntityID   string
+	From       time.Time
+	To         time.Time
+	AfterSeq   int64
+	Limit      int
+}
+
+// Repository é o log de auditoria persistido. Não há como alterar ou remover
+// entradas.
+type Repository interface {
+	// Append encadeia a entrada à última gravada, preenchendo Seq, PrevHash
+	// e Hash.
+	Append(ctx context.Context, entry *Entry) error
+	List(ctx context.Context, query Query) ([]*Entry, error)
+}
@@ -0,0 +1,17 @@
+package domain
+
+import "context"
+
+type sourceIPContextKey struct{}
+
+// WithSourceIP guarda no contexto o IP de origem da requisição, registrado
+// nas entradas do log.
+func WithSourceIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, sourceIPContextKey{}, ip)
+}
+
+// SourceIPFrom devolve o IP de origem, ou vazio fora de uma requisição.
+func SourceIPFrom(ctx context.Context) string {
+	ip, _ := ctx.Value(sourceIPContextKey{}).(string)
+	return ip
+}
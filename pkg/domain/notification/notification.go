@@ -0,0 +1,112 @@
+// Package domain define as notificações enviadas aos usuários, as portas
+// pelas quais elas saem e as preferências de entrega de cada um.
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hoyci/todo-ddd/pkg/domain/valueobject"
+)
+
+// Kind é o motivo da notificação.
+type Kind string
+
+const (
+	KindReminder Kind = "reminder"
+	KindAssigned Kind = "assigned"
+	KindComment  Kind = "comment"
+	KindMention  Kind = "mention"
+)
+
+// Retention é por quanto tempo uma notificação fica na caixa de entrada,
+// lida ou não.
+const Retention = 90 * 24 * time.Hour
+
+// Notification é um aviso a um usuário, entregue em cada canal que ele
+// habilitou.
+type Notification struct {
+	ID     string
+	UserID string
+	Kind   Kind
+	// TaskID é a tarefa de que a notificação trata; vazio se nenhuma.
+	TaskID    string
+	Title     string
+	Body      string
+	CreatedAt time.Time
+	// ReadAt é nil enquanto a notificação não foi lida.
+	ReadAt *time.Time
+	// ExpiresAt é quando a notificação some da caixa de entrada.
+	ExpiresAt time.Time
+}
+
+func NewNotification(userID string, kind Kind, taskID, title, body string) *Notification {
+	now := time.Now()
+	return &Notification{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		Kind:      kind,
+		TaskID:    taskID,
+		Title:     title,
+		Body:      body,
+		CreatedAt: now,
+		ExpiresAt: now.Add(Retention),
+	}
+}
+
+func (n *Notification) Read() bool { return n.ReadAt != nil }
+
+// Recipient é o destinatário com o endereço de cada canal, vindo do
+// cadastro e das preferências do usuário.
+type Recipient struct {
+	UserID     string
+	Name       string
+	Email      string
+	WebhookURL string
+}
+
+// Channel é a porta de saída das notificações. Cada adaptador entrega por
+// um meio: email, webhook ou a caixa de entrada do app.
+type Channel interface {
+	Name() valueobject.NotificationChannel
+	Send(ctx context.Context, recipient Recipient, notification *Notification) error
+}
+
+// Message é um email em texto simples.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer envia emails.
+type Mailer interface {
+	Send(ctx context.Context, message Message) error
+}
+
+// InboxQuery pagina a caixa de entrada de um usuário, da notificação mais
+// recente para a mais antiga.
+type InboxQuery struct {
+	UserID     string
+	UnreadOnly bool
+	// AfterID é a última notificação da página anterior.
+	AfterID string
+	Limit   int
+}
+
+// InboxRepository guarda as notificações entregues no app. Notificações
+// expiradas não são devolvidas por nenhum método.
+type InboxRepository interface {
+	// Save também apaga as notificações já expiradas.
+	Save(ctx context.Context, notification *Notification) error
+	// Find devolve sql.ErrNoRows se a notificação não é do usuário.
+	Find(ctx context.Context, userID, id string) (*Notification, error)
+	List(ctx context.Context, query InboxQuery) ([]*Notification, error)
+	CountUnread(ctx context.Context, userID string) (int, error)
+	// SetRead marca as notificações do usuário como lidas em at, ou como não
+	// lidas se at é nil, e devolve quantas mudaram de estado.
+	SetRead(ctx context.Context, userID string, ids []string, at *time.Time) (int, error)
+	// MarkAllRead marca como lidas todas as notificações não lidas do usuário.
+	MarkAllRead(ctx context.Context, userID string, at time.Time) (int, error)
+}
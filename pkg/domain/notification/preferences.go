@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/hoyci/todo-ddd/pkg/domain/valueobject"
+)
+
+var (
+	ErrInvalidWebhookURL  = errors.New("webhook_url must be an absolute http or https URL")
+	ErrWebhookURLRequired = errors.New("webhook_url is required to enable the webhook channel")
+)
+
+// Preferences diz por quais canais o usuário quer ser avisado e em que
+// horário não quer.
+type Preferences struct {
+	UserID     string
+	Channels   []valueobject.NotificationChannel
+	WebhookURL string
+	QuietHours valueobject.QuietHours
+	UpdatedAt  *time.Time
+}
+
+// DefaultPreferences vale para quem nunca salvou preferências: caixa de
+// entrada e email, sem horário de silêncio.
+func DefaultPreferences(userID string) *Preferences {
+	return &Preferences{
+		UserID:   userID,
+		Channels: []valueobject.NotificationChannel{valueobject.ChannelInApp, valueobject.ChannelEmail},
+	}
+}
+
+func NewPreferences(userID string, channels []string, webhookURL string, quietHours valueobject.QuietHours) (*Preferences, error) {
+	prefs := &Preferences{UserID: userID, WebhookURL: webhookURL, QuietHours: quietHours}
+	for _, raw := range channels {
+		channel, err := valueobject.NewNotificationChannel(raw)
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Contains(prefs.Channels, channel) {
+			prefs.Channels = append(prefs.Channels, channel)
+		}
+	}
+
+	if webhookURL != "" {
+		u, err := url.Parse(webhookURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, ErrInvalidWebhookURL
+		}
+	}
+	if prefs.Enabled(valueobject.ChannelWebhook) && webhookURL == "" {
+		return nil, ErrWebhookURLRequired
+	}
+
+	now := time.Now()
+	prefs.UpdatedAt = &now
+	return prefs, nil
+}
+
+func (p *Preferences) Enabled(channel valueobject.NotificationChannel) bool {
+	return slices.Contains(p.Channels, channel)
+}
+
+type PreferenceRepository interface {
+	// Find devolve sql.ErrNoRows se o usuário nunca salvou preferências.
+	Find(ctx context.Context, userID string) (*Preferences, error)
+	// Save cria ou substitui as preferências do usuário.
+	Save(ctx context.Context, prefs *Preferences) error
+}
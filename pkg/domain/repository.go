@@ -3,15 +3,40 @@ package domain
 import (
 	"context"
 
+	auditDomain "github.com/hoyci/todo-ddd/pkg/domain/audit"
+	notificationDomain "github.com/hoyci/todo-ddd/pkg/domain/notification"
 	taskDomain "github.com/hoyci/todo-ddd/pkg/domain/task"
+	undoDomain "github.com/hoyci/todo-ddd/pkg/domain/undo"
 	userDomain "github.com/hoyci/todo-ddd/pkg/domain/user"
+	workspaceDomain "github.com/hoyci/todo-ddd/pkg/domain/workspace"
+	"github.com/hoyci/todo-ddd/pkg/policy"
 )
 
 type Work interface {
 	UserRepo() userDomain.UserRepository
 	TaskRepo() taskDomain.TaskRepository
+	TaskEventRepo() taskDomain.EventRepository
+	CommentRepo() taskDomain.CommentRepository
+	RevisionRepo() taskDomain.RevisionRepository
+	ReminderRepo() taskDomain.ReminderRepository
+	UndoRepo() undoDomain.Repository
+	AttachmentRepo() taskDomain.AttachmentRepository
+	ShareRepo() taskDomain.ShareRepository
+	WatcherRepo() taskDomain.WatcherRepository
+	WorkspaceRepo() workspaceDomain.WorkspaceRepository
+	InvitationRepo() workspaceDomain.InvitationRepository
+	RoleRepo() policy.RoleStore
+	// AuditRepo grava o log de auditoria na mesma transação das mudanças
+	// que ele registra.
+	AuditRepo() auditDomain.Repository
+	NotificationPreferenceRepo() notificationDomain.PreferenceRepository
+	// InboxRepo grava as notificações no app junto da mudança que as gerou.
+	InboxRepo() notificationDomain.InboxRepository
+	// Savepoint executa fn em um savepoint da transação: se fn falhar, o
+	// que ela gravou é desfeito e a transação segue com o restante.
+	Savepoint(ctx context.Context, fn func(ctx context.Context) error) error
 }
 
 type UnitOfWork interface {
-	Execute(ctx context.Context, fn func(work Work) error) error
+	Execute(ctx context.Context, fn func(ctx context.Context, work Work) error) error
 }
@@ -0,0 +1,159 @@
+package domain
+
+import (
+	"slices"
+	"time"
+
+	"github.com/hoyci/todo-ddd/pkg/domain/valueobject"
+)
+
+type ActivityKind string
+
+const (
+	ActivityTaskCreated   ActivityKind = "task_created"
+	ActivityFieldsChanged ActivityKind = "fields_changed"
+	ActivityStatusChanged ActivityKind = "status_changed"
+	ActivityTaskDeleted   ActivityKind = "task_deleted"
+	ActivityTaskRestored  ActivityKind = "task_restored"
+	ActivityComment       ActivityKind = "comment"
+)
+
+// Activity é uma entrada do histórico de uma tarefa: uma mudança vinda do
+// log de eventos ou um comentário.
+type Activity struct {
+	Kind       ActivityKind
+	OccurredAt time.Time
+	ActorID    string
+	// Changes lista os campos alterados em fields_changed e status_changed.
+	Changes []FieldChange
+	// Comment só é preenchido em comment.
+	Comment *Comment
+}
+
+// FieldChange é o valor de um campo antes e depois de uma mudança. Valores
+// ausentes (sem vencimento, sem recorrência) são nil.
+type FieldChange struct {
+	Field string
+	From  any
+	To    any
+}
+
+// BuildActivity intercala eventos e comentários em ordem cronológica. Os
+// eventos devem estar na ordem do log: as mudanças de cada um são obtidas
+// comparando seu estado com o do evento anterior.
+func BuildActivity(events []Event, comments []*Comment) []Activity {
+	activity := make([]Activity, 0, len(events)+len(comments))
+
+	var previous *Task
+	for i := range events {
+		event := &events[i]
+		entry := Activity{OccurredAt: event.OccurredAt, ActorID: event.UserID}
+
+		switch event.Type {
+		case EventCreated:
+			entry.Kind = ActivityTaskCreated
+			activity = append(activity, entry)
+		case EventDeleted:
+			entry.Kind = ActivityTaskDeleted
+			activity = append(activity, entry)
+		case EventRestored:
+			entry.Kind = ActivityTaskRestored
+			activity = append(activity, entry)
+		default:
+			// Um mesmo salvamento pode gerar task.updated e
+			// task.status_changed com o mesmo estado; o segundo não tem
+			// diferenças e é descartado.
+			if previous == nil {
+				break
+			}
+			fields, status := diffTasks(previous, &event.Task)
+			if len(fields) > 0 {
+				entry.Kind, entry.Changes = ActivityFieldsChanged, fields
+				activity = append(activity, entry)
+			}
+			if status != nil {
+				entry.Kind, entry.Changes = ActivityStatusChanged, []FieldChange{*status}
+				activity = append(activity, entry)
+			}
+		}
+		previous = &event.Task
+	}
+
+	for _, comment := range comments {
+		activity = append(activity, Activity{
+			Kind:       ActivityComment,
+			OccurredAt: comment.CreatedAt,
+			ActorID:    comment.AuthorID,
+			Comment:    comment,
+		})
+	}
+
+	slices.SortStableFunc(activity, func(a, b Activity) int {
+		return a.OccurredAt.Compare(b.OccurredAt)
+	})
+	return activity
+}
+
+// diffTasks compara dois estados da tarefa, separando a mudança de status
+// das demais.
+func diffTasks(before, after *Task) (fields []FieldChange, status *FieldChange) {
+	add := func(field string, from, to any) {
+		fields = append(fields, FieldChange{Field: field, From: from, To: to})
+	}
+
+	if before.Title != after.Title {
+		add("title", before.Title, after.Title)
+	}
+	if before.Description != after.Description {
+		add("description", before.Description, after.Description)
+	}
+	if before.Priority != after.Priority {
+		add("priority", int(before.Priority), int(after.Priority))
+	}
+	if !equalTimes(before.DueAt, after.DueAt) {
+		add("due_at", optionalTime(before.DueAt), optionalTime(after.DueAt))
+	}
+	if !slices.Equal(before.Tags, after.Tags) {
+		add("tags", tagValues(before.Tags), tagValues(after.Tags))
+	}
+	if before.Recurrence != after.Recurrence {
+		add("recurrence", optionalString(before.Recurrence.String()), optionalString(after.Recurrence.String()))
+	}
+	if before.AssigneeID != after.AssigneeID {
+		add("assignee_id", optionalString(before.AssigneeID), optionalString(after.AssigneeID))
+	}
+
+	if before.Status != after.Status {
+		status = &FieldChange{Field: "status", From: string(before.Status), To: string(after.Status)}
+	}
+	return fields, status
+}
+
+func equalTimes(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+func optionalTime(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return *t
+}
+
+func optionalString(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+func tagValues(tags []valueobject.Tag) []string {
+	values := make([]string, len(tags))
+	for i, tag := range tags {
+		values[i] = string(tag)
+	}
+	return values
+}
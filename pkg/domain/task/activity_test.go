@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hoyci/todo-ddd/pkg/domain/valueobject"
+)
+
+func TestBuildActivity(t *testing.T) {
+	start := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return start.Add(time.Duration(minutes) * time.Minute) }
+	due := at(24 * 60)
+
+	created := Task{Title: "Draft", Priority: valueobject.Low, Status: valueobject.StatusNew}
+	edited := created
+	edited.Title, edited.Priority, edited.DueAt = "Final", valueobject.High, &due
+	tagged := edited
+	tagged.Tags = []valueobject.Tag{"work"}
+	tagged.Status = valueobject.StatusCompleted
+
+	events := []Event{
+		{Type: EventCreated, UserID: "u1", Task: created, OccurredAt: at(0)},
+		{Type: EventUpdated, UserID: "u1", Task: edited, OccurredAt: at(10)},
+		// O mesmo salvamento gera dois eventos; o segundo não tem diferença.
+		{Type: EventStatusChanged, UserID: "u1", Task: edited, OccurredAt: at(10)},
+		{Type: EventUpdated, UserID: "u2", Task: tagged, OccurredAt: at(30)},
+		{Type: EventDeleted, UserID: "u1", Task: tagged, OccurredAt: at(40)},
+		{Type: EventRestored, UserID: "u1", Task: tagged, OccurredAt: at(50)},
+	}
+	comment := &Comment{ID: "c1", AuthorID: "u2", Body: "done?", CreatedAt: at(20)}
+
+	want := []Activity{
+		{Kind: ActivityTaskCreated, OccurredAt: at(0), ActorID: "u1"},
+		{Kind: ActivityFieldsChanged, OccurredAt: at(10), ActorID: "u1", Changes: []FieldChange{
+			{Field: "title", From: "Draft", To: "Final"},
+			{Field: "priority", From: 1, To: 3},
+			{Field: "due_at", From: nil, To: due},
+		}},
+		{Kind: ActivityComment, OccurredAt: at(20), ActorID: "u2", Comment: comment},
+		{Kind: ActivityFieldsChanged, OccurredAt: at(30), ActorID: "u2", Changes: []FieldChange{
+			{Field: "tags", From: []string{}, To: []string{"work"}},
+		}},
+		{Kind: ActivityStatusChanged, OccurredAt: at(30), ActorID: "u2", Changes: []FieldChange{
+			{Field: "status", From: "new", To: "completed"},
+		}},
+		{Kind: ActivityTaskDeleted, OccurredAt: at(40), ActorID: "u1"},
+		{Kind: ActivityTaskRestored, OccurredAt: at(50), ActorID: "u1"},
+	}
+
+	got := BuildActivity(events, []*Comment{comment})
+	if len(got) != len(want) {
+		t.Fatalf("got %d entries, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if !reflect.DeepEqual(got[i], want[i]) {
+			t.Errorf("entry %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hoyci/todo-ddd/pkg/domain/valueobject"
+)
+
+// Attachment é um arquivo anexado a uma tarefa. O conteúdo fica no
+// BlobStore sob a chave derivada de Hash, compartilhado por anexos com o
+// mesmo conteúdo.
+type Attachment struct {
+	ID          string
+	TaskID      string
+	UploaderID  string
+	FileName    string
+	ContentType string
+	Size        int64
+	// Hash é o SHA-256 do conteúdo, em hexadecimal.
+	Hash      string
+	CreatedAt time.Time
+}
+
+func NewAttachment(taskID, uploaderID, fileName, contentType string, size int64, hash string) (*Attachment, error) {
+	nameVO, err := valueobject.NewFileName(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Attachment{
+		ID:          uuid.New().String(),
+		TaskID:      taskID,
+		UploaderID:  uploaderID,
+		FileName:    nameVO.String(),
+		ContentType: contentType,
+		Size:        size,
+		Hash:        hash,
+		CreatedAt:   time.Now(),
+	}, nil
+}
+
+// BlobKey é a chave do conteúdo no BlobStore.
+func (a *Attachment) BlobKey() string {
+	return BlobKey(a.Hash)
+}
+
+func BlobKey(hash string) string {
+	return "sha256/" + hash
+}
+
+type AttachmentRepository interface {
+	Save(ctx context.Context, attachment *Attachment) error
+	FindByID(ctx context.Context, id, taskID string) (*Attachment, error)
+	ListByTask(ctx context.Context, taskID string) ([]*Attachment, error)
+	Delete(ctx context.Context, id string) error
+	// DeleteByTasks remove os anexos das tarefas, devolvendo os hashes que
+	// eles referenciavam.
+	DeleteByTasks(ctx context.Context, taskIDs []string) ([]string, error)
+	// CountByHash diz quantos anexos referenciam o conteúdo.
+	CountByHash(ctx context.Context, hash string) (int, error)
+}
+
+var ErrBlobNotFound = errors.New("blob not found")
+
+// BlobStore guarda o conteúdo dos anexos. Put com uma chave existente
+// substitui o conteúdo.
+type BlobStore interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Open devolve o conteúdo com suporte a Seek, usado nas leituras
+	// parciais (Range). Chaves inexistentes resultam em ErrBlobNotFound.
+	Open(ctx context.Context, key string) (io.ReadSeekCloser, error)
+	// Delete ignora chaves inexistentes.
+	Delete(ctx context.Context, key string) error
+}
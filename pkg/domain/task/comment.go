@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hoyci/todo-ddd/pkg/domain/valueobject"
+)
+
+// Comment é uma mensagem em markdown deixada em uma tarefa.
+type Comment struct {
+	ID       string
+	TaskID   string
+	AuthorID string
+	Body     string
+	// History guarda as versões anteriores do corpo, da mais antiga para a
+	// mais recente.
+	History   []CommentRevision
+	CreatedAt time.Time
+	UpdatedAt *time.Time
+	DeletedAt *time.Time
+}
+
+// CommentRevision é um corpo substituído por uma edição. EditedAt é quando
+// ele deixou de valer.
+type CommentRevision struct {
+	Body     string
+	EditedAt time.Time
+}
+
+func NewComment(taskID, authorID, body string) (*Comment, error) {
+	bodyVO, err := valueobject.NewCommentBody(body)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Comment{
+		ID:        uuid.New().String(),
+		TaskID:    taskID,
+		AuthorID:  authorID,
+		Body:      bodyVO.String(),
+		CreatedAt: time.Now(),
+		UpdatedAt: nil,
+		DeletedAt: nil,
+	}, nil
+}
+
+// Edit troca o corpo, guardando o anterior no histórico. Devolve false se o
+// corpo não mudou.
+func (c *Comment) Edit(body string) (bool, error) {
+	bodyVO, err := valueobject.NewCommentBody(body)
+	if err != nil {
+		return false, err
+	}
+	if bodyVO.String() == c.Body {
+		return false, nil
+	}
+
+	now := time.Now()
+	c.History = append(c.History, CommentRevision{Body: c.Body, EditedAt: now})
+	c.Body = bodyVO.String()
+	c.UpdatedAt = &now
+	return true, nil
+}
+
+func (c *Comment) Delete() {
+	now := time.Now()
+	c.UpdatedAt = &now
+	c.DeletedAt = &now
+}
+
+// mentionPattern casa @email no início do texto ou depois de um caractere
+// que não pode fazer parte de um endereço.
+var mentionPattern = regexp.MustCompile(`(?:^|[^A-Za-z0-9._%+\-@])@([A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,})`)
+
+// Mentions devolve, sem repetição e em minúsculas, os emails mencionados no
+// corpo como @fulano@example.com, na ordem em que aparecem.
+func (c *Comment) Mentions() []string {
+	var emails []string
+	seen := map[string]bool{}
+	for _, match := range mentionPattern.FindAllStringSubmatch(c.Body, -1) {
+		email := strings.ToLower(match[1])
+		if !seen[email] {
+			seen[email] = true
+			emails = append(emails, email)
+		}
+	}
+	return emails
+}
@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/hoyci/todo-ddd/pkg/domain/valueobject"
+)
+
+func TestCommentEdit(t *testing.T) {
+	comment, err := NewComment("task-1", "user-1", "  first  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if comment.Body != "first" {
+		t.Errorf("Body = %q, want trimmed", comment.Body)
+	}
+
+	tests := []struct {
+		body        string
+		wantChanged bool
+		wantErr     error
+		wantHistory []string
+	}{
+		{"first", false, nil, nil},
+		{"second", true, nil, []string{"first"}},
+		{"  second\n", false, nil, []string{"first"}},
+		{"   ", false, valueobject.ErrEmptyComment, []string{"first"}},
+		{strings.Repeat("é", 10001), false, valueobject.ErrCommentTooLong, []string{"first"}},
+		{"third", true, nil, []string{"first", "second"}},
+	}
+	for _, tt := range tests {
+		changed, err := comment.Edit(tt.body)
+		if changed != tt.wantChanged || !errors.Is(err, tt.wantErr) {
+			t.Errorf("Edit(%.10q) = %v, %v; want %v, %v", tt.body, changed, err, tt.wantChanged, tt.wantErr)
+		}
+		var history []string
+		for _, revision := range comment.History {
+			history = append(history, revision.Body)
+		}
+		if !slices.Equal(history, tt.wantHistory) {
+			t.Errorf("after Edit(%.10q) history = %q, want %q", tt.body, history, tt.wantHistory)
+		}
+	}
+	if comment.UpdatedAt == nil {
+		t.Error("UpdatedAt was not set by Edit")
+	}
+}
+
+func TestCommentMentions(t *testing.T) {
+	tests := []struct {
+		body string
+		want []string
+	}{
+		{"no mentions here", nil},
+		{"@ada@example.com please review", []string{"ada@example.com"}},
+		{"cc @Ada@Example.com and @bob@example.org, then @ada@example.com again", []string{"ada@example.com", "bob@example.org"}},
+		{"(@carol@example.com)", []string{"carol@example.com"}},
+		{"write to ada@example.com", nil},
+		{"foo@@ada@example.com", nil},
+		{"@ada@localhost", nil},
+	}
+	for _, tt := range tests {
+		comment := &Comment{Body: tt.body}
+		if got := comment.Mentions(); !slices.Equal(got, tt.want) {
+			t.Errorf("Mentions(%q) = %q, want %q", tt.body, got, tt.want)
+		}
+	}
+}
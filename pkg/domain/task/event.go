@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type EventType string
+
+const (
+	EventCreated       EventType = "task.created"
+	EventUpdated       EventType = "task.updated"
+	EventStatusChanged EventType = "task.status_changed"
+	EventAssigned      EventType = "task.assigned"
+	EventDeleted       EventType = "task.deleted"
+	EventRestored      EventType = "task.restored"
+	EventReordered     EventType = "task.reordered"
+)
+
+// Event registra uma mudança em uma tarefa. ID é atribuído pelo log de
+// eventos ao persistir e cresce monotonicamente.
+type Event struct {
+	ID         int64
+	Type       EventType
+	TaskID     string
+	UserID     string
+	Task       Task
+	OccurredAt time.Time
+	// WatcherIDs são os usuários que acompanham a tarefa, o responsável, os
+	// destinatários de compartilhamentos e, em um workspace, os membros dele,
+	// que também devem ser avisados do evento. Não é persistido: quem lê o log
+	// descobre esses eventos pelas tabelas de watchers, tarefas,
+	// compartilhamentos e membros.
+	WatcherIDs []string
+}
+
+// EventRepository é o log persistido de eventos de tarefas. Lê e grava no
+// workspace do contexto (ver ScopeFrom no pacote workspace).
+type EventRepository interface {
+	// Append persiste os eventos, preenchendo o ID de cada um.
+	Append(ctx context.Context, events []Event) error
+	// ListAfter devolve, em ordem, até limit eventos com ID maior que afterID
+	// das tarefas do usuário, das que ele acompanha, das atribuídas a ele e
+	// das compartilhadas com ele ou, em um workspace, de todas as tarefas
+	// dele. São os mesmos usuários avisados pelo EventPublisher.
+	ListAfter(ctx context.Context, userID string, afterID int64, limit int) ([]Event, error)
+	// ListByTask devolve, em ordem, todos os eventos da tarefa.
+	ListByTask(ctx context.Context, taskID string) ([]Event, error)
+	// ListByTaskAfter é ListAfter restrito a uma tarefa que userID enxerga,
+	// pela mesma regra de TaskRepository.FindByID.
+	ListByTaskAfter(ctx context.Context, taskID, userID string, afterID int64, limit int) ([]Event, error)
+	LastID(ctx context.Context, userID string) (int64, error)
+	LastIDByTask(ctx context.Context, taskID, userID string) (int64, error)
+}
+
+// EventPublisher é avisado dos eventos depois que a transação que os
+// registrou foi confirmada.
+type EventPublisher interface {
+	Publish(events []Event)
+}
@@ -0,0 +1,674 @@
+package domain
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/hoyci/todo-ddd/pkg/domain/valueobject"
+)
+
+type SpanKind string
+
+const (
+	SpanDate       SpanKind = "date"
+	SpanTime       SpanKind = "time"
+	SpanPriority   SpanKind = "priority"
+	SpanTag        SpanKind = "tag"
+	SpanRecurrence SpanKind = "recurrence"
+)
+
+// Span marca um trecho do texto interpretado pelo parser. Start e End são
+// posições em runas; End é exclusivo.
+type Span struct {
+	Kind  SpanKind
+	Start int
+	End   int
+	Text  string
+}
+
+// QuickAdd é a interpretação de uma frase de criação rápida, como
+// "Pagar aluguel amanhã 9h !alta #financas todo mês".
+type QuickAdd struct {
+	// Title é o texto que sobrou depois de retirados os trechos
+	// interpretados.
+	Title string
+	DueAt *time.Time
+	// Priority zero significa não informada.
+	Priority   valueobject.Priority
+	Tags       []valueobject.Tag
+	Recurrence valueobject.Recurrence
+	Spans      []Span
+}
+
+// endOfDay é o horário usado quando a frase tem data mas não tem hora.
+var endOfDay = clock{hour: 23, minute: 59}
+
+// ParseQuickAdd interpreta o texto em inglês ou português. Datas relativas
+// são calculadas a partir de now e no seu fuso horário. Só a primeira data,
+// hora, prioridade e recorrência são usadas; repetições ficam no título.
+func ParseQuickAdd(text string, now time.Time) QuickAdd {
+	p := &quickAddParser{now: now, runes: []rune(text)}
+	p.words = splitWords(p.runes)
+
+	matchers := []struct {
+		kind  SpanKind
+		match func(p *quickAddParser, i int) int
+	}{
+		{SpanTag, (*quickAddParser).tag},
+		{SpanPriority, (*quickAddParser).priority},
+		{SpanRecurrence, (*quickAddParser).recurrence},
+		{SpanDate, (*quickAddParser).date},
+		{SpanTime, (*quickAddParser).time},
+	}
+
+	var title []string
+	for i := 0; i < len(p.words); {
+		n := 0
+		for _, m := range matchers {
+			if n = m.match(p, i); n > 0 {
+				start, end := p.words[i].start, p.words[i+n-1].end
+				p.out.Spans = append(p.out.Spans, Span{
+					Kind:  m.kind,
+					Start: start,
+					End:   end,
+					Text:  string(p.runes[start:end]),
+				})
+				break
+			}
+		}
+		if n == 0 {
+			title = append(title, p.words[i].raw)
+			n = 1
+		}
+		i += n
+	}
+
+	p.out.Title = strings.TrimRight(strings.Join(title, " "), " ,;:-")
+	p.out.DueAt = p.dueAt()
+	return p.out
+}
+
+type clock struct {
+	hour, minute int
+}
+
+type word struct {
+	// raw é a palavra como digitada; text é raw sem a pontuação final e key
+	// é text em minúsculas e sem acentos, usada nas comparações.
+	raw, text, key string
+	start, end     int
+}
+
+type quickAddParser struct {
+	now   time.Time
+	runes []rune
+	words []word
+	out   QuickAdd
+
+	day   *time.Time // meia-noite do dia, no fuso de now
+	clock *clock
+	// anchor é o dia da semana de recorrências como "toda segunda"; vira a
+	// data quando a frase não traz uma.
+	anchor *time.Weekday
+}
+
+func splitWords(runes []rune) []word {
+	var words []word
+	for i := 0; i < len(runes); {
+		if unicode.IsSpace(runes[i]) {
+			i++
+			continue
+		}
+		j := i
+		for j < len(runes) && !unicode.IsSpace(runes[j]) {
+			j++
+		}
+		raw := string(runes[i:j])
+		text := strings.TrimRight(raw, ",.;:?")
+		if text == "" {
+			text = raw
+		}
+		words = append(words, word{
+			raw:   raw,
+			text:  text,
+			key:   fold(text),
+			start: i,
+			end:   i + utf8.RuneCountInString(text),
+		})
+		i = j
+	}
+	return words
+}
+
+var accents = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a",
+	"é", "e", "ê", "e", "í", "i",
+	"ó", "o", "ô", "o", "õ", "o", "ú", "u", "ç", "c",
+)
+
+func fold(s string) string {
+	return accents.Replace(strings.ToLower(s))
+}
+
+// key devolve a chave da palavra i, ou "" depois do fim do texto.
+func (p *quickAddParser) key(i int) string {
+	if i < 0 || i >= len(p.words) {
+		return ""
+	}
+	return p.words[i].key
+}
+
+// keys diz se as palavras a partir de i são exatamente as dadas.
+func (p *quickAddParser) keys(i int, keys ...string) bool {
+	for k, key := range keys {
+		if p.key(i+k) != key {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *quickAddParser) today() time.Time {
+	y, m, d := p.now.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, p.now.Location())
+}
+
+func (p *quickAddParser) tag(i int) int {
+	if !strings.HasPrefix(p.words[i].text, "#") {
+		return 0
+	}
+	tag, err := valueobject.NewTag(p.words[i].text)
+	if err != nil {
+		return 0
+	}
+	if !slices.Contains(p.out.Tags, tag) {
+		p.out.Tags = append(p.out.Tags, tag)
+	}
+	return 1
+}
+
+var priorityWords = map[string]valueobject.Priority{
+	"!high": valueobject.High, "!alta": valueobject.High, "!3": valueobject.High, "!!!": valueobject.High,
+	"!medium": valueobject.Medium, "!media": valueobject.Medium, "!2": valueobject.Medium, "!!": valueobject.Medium,
+	"!low": valueobject.Low, "!baixa": valueobject.Low, "!1": valueobject.Low,
+}
+
+func (p *quickAddParser) priority(i int) int {
+	priority, ok := priorityWords[p.key(i)]
+	if !ok || p.out.Priority != 0 {
+		return 0
+	}
+	p.out.Priority = priority
+	return 1
+}
+
+var frequencyWords = map[string]valueobject.Frequency{
+	"daily": valueobject.Daily, "diariamente": valueobject.Daily,
+	"weekly": valueobject.Weekly, "semanalmente": valueobject.Weekly,
+	"monthly": valueobject.Monthly, "mensalmente": valueobject.Monthly,
+	"yearly": valueobject.Yearly, "annually": valueobject.Yearly, "anualmente": valueobject.Yearly,
+}
+
+var unitWords = map[string]valueobject.Frequency{
+	"day": valueobject.Daily, "days": valueobject.Daily, "dia": valueobject.Daily, "dias": valueobject.Daily,
+	"week": valueobject.Weekly, "weeks": valueobject.Weekly, "semana": valueobject.Weekly, "semanas": valueobject.Weekly,
+	"month": valueobject.Monthly, "months": valueobject.Monthly, "mes": valueobject.Monthly, "meses": valueobject.Monthly,
+	"year": valueobject.Yearly, "years": valueobject.Yearly, "ano": valueobject.Yearly, "anos": valueobject.Yearly,
+}
+
+// recurrence reconhece "daily", "every 2 weeks", "every other friday",
+// "mensalmente", "todo mês", "todas as segundas" e "a cada 3 dias".
+func (p *quickAddParser) recurrence(i int) int {
+	if !p.out.Recurrence.IsZero() {
+		return 0
+	}
+	if frequency, ok := frequencyWords[p.key(i)]; ok {
+		return p.setRecurrence(frequency, 1, nil, 1)
+	}
+
+	j := i
+	switch p.key(i) {
+	case "every":
+		j++
+	case "todo", "toda":
+		j++
+	case "todos", "todas":
+		j++
+		if p.key(j) == "os" || p.key(j) == "as" {
+			j++
+		}
+	case "a":
+		if p.key(i+1) != "cada" {
+			return 0
+		}
+		j += 2
+	default:
+		return 0
+	}
+
+	interval := 1
+	if p.key(j) == "other" {
+		interval = 2
+		j++
+	} else if n, ok := number(p.key(j)); ok && p.key(i) != "todo" && p.key(i) != "toda" {
+		interval = n
+		j++
+	}
+
+	if frequency, ok := unitWords[p.key(j)]; ok {
+		return p.setRecurrence(frequency, interval, nil, j+1-i)
+	}
+	if weekday, ok := weekdayOf(p.key(j)); ok {
+		return p.setRecurrence(valueobject.Weekly, interval, &weekday, j+1-i)
+	}
+	return 0
+}
+
+func (p *quickAddParser) setRecurrence(frequency valueobject.Frequency, interval int, anchor *time.Weekday, n int) int {
+	recurrence, err := valueobject.NewRecurrence(frequency, interval)
+	if err != nil {
+		return 0
+	}
+	p.out.Recurrence = recurrence
+	p.anchor = anchor
+	return n
+}
+
+// datePrefixes podem preceder qualquer data: "on friday", "até 15/03",
+// "no dia 10".
+var datePrefixes = map[string]bool{
+	"on": true, "by": true, "due": true, "for": true, "this": true, "until": true,
+	"em": true, "no": true, "na": true, "neste": true, "nesta": true, "nesse": true, "nessa": true,
+	"ate": true, "para": true, "pra": true,
+}
+
+func (p *quickAddParser) date(i int) int {
+	if p.day != nil {
+		return 0
+	}
+	if n := p.dateAt(i); n > 0 {
+		return n
+	}
+	if datePrefixes[p.key(i)] {
+		if n := p.dateAt(i + 1); n > 0 {
+			return n + 1
+		}
+	}
+	return 0
+}
+
+func (p *quickAddParser) setDay(day time.Time, n int) int {
+	p.day = &day
+	return n
+}
+
+func (p *quickAddParser) dateAt(i int) int {
+	today := p.today()
+
+	switch {
+	case p.keys(i, "today"), p.keys(i, "hoje"):
+		return p.setDay(today, 1)
+	case p.keys(i, "day", "after", "tomorrow"), p.keys(i, "depois", "de", "amanha"):
+		return p.setDay(today.AddDate(0, 0, 2), 3)
+	case p.keys(i, "tomorrow"), p.keys(i, "amanha"):
+		return p.setDay(today.AddDate(0, 0, 1), 1)
+	case p.keys(i, "next", "week"), p.keys(i, "proxima", "semana"):
+		return p.setDay(nextWeekday(today, time.Monday, 1), 2)
+	case p.keys(i, "semana", "que", "vem"):
+		return p.setDay(nextWeekday(today, time.Monday, 1), 3)
+	case p.keys(i, "next", "month"), p.keys(i, "proximo", "mes"):
+		return p.setDay(time.Date(today.Year(), today.Month()+1, 1, 0, 0, 0, 0, today.Location()), 2)
+	case p.keys(i, "mes", "que", "vem"):
+		return p.setDay(time.Date(today.Year(), today.Month()+1, 1, 0, 0, 0, 0, today.Location()), 3)
+	}
+
+	if n := p.relativeDate(i, today); n > 0 {
+		return n
+	}
+	if n := p.weekdayDate(i, today); n > 0 {
+		return n
+	}
+	return p.calendarDate(i, today)
+}
+
+// relativeDate reconhece "in 3 days", "em 2 semanas" e "daqui a um mês".
+func (p *quickAddParser) relativeDate(i int, today time.Time) int {
+	j := i
+	switch {
+	case p.keys(i, "daqui", "a"):
+		j += 2
+	case p.keys(i, "in"), p.keys(i, "em"), p.keys(i, "daqui"):
+		j++
+	default:
+		return 0
+	}
+
+	n, ok := number(p.key(j))
+	if !ok {
+		return 0
+	}
+	switch unitWords[p.key(j+1)] {
+	case valueobject.Daily:
+		return p.setDay(today.AddDate(0, 0, n), j+2-i)
+	case valueobject.Weekly:
+		return p.setDay(today.AddDate(0, 0, 7*n), j+2-i)
+	case valueobject.Monthly:
+		return p.setDay(today.AddDate(0, n, 0), j+2-i)
+	case valueobject.Yearly:
+		return p.setDay(today.AddDate(n, 0, 0), j+2-i)
+	}
+	return 0
+}
+
+// weekdayDate reconhece "friday" e "sexta", que incluem hoje, e "next
+// friday", "próxima sexta" e "sexta que vem", que não incluem.
+func (p *quickAddParser) weekdayDate(i int, today time.Time) int {
+	j, after := i, 0
+	switch p.key(i) {
+	case "next", "proxima", "proximo":
+		j, after = i+1, 1
+	}
+
+	weekday, ok := weekdayOf(p.key(j))
+	if !ok {
+		return 0
+	}
+	n := j + 1 - i
+	if p.keys(j+1, "que", "vem") {
+		n, after = n+2, 1
+	}
+	return p.setDay(nextWeekday(today, weekday, after), n)
+}
+
+var (
+	isoDatePattern   = regexp.MustCompile(`^(\d{4})-(\d{2})-(\d{2})$`)
+	slashDatePattern = regexp.MustCompile(`^(\d{1,2})/(\d{1,2})(?:/(\d{4}))?$`)
+)
+
+// calendarDate reconhece "2026-03-15", "15/03", "15/03/2027", "15 de
+// março", "15 de março de 2027", "march 15", "15 march" e "dia 15". Datas
+// sem ano já passadas caem no ano seguinte.
+func (p *quickAddParser) calendarDate(i int, today time.Time) int {
+	key := p.key(i)
+
+	if m := isoDatePattern.FindStringSubmatch(key); m != nil {
+		return p.exactDate(atoi(m[1]), atoi(m[2]), atoi(m[3]), 1)
+	}
+	if m := slashDatePattern.FindStringSubmatch(key); m != nil {
+		if m[3] != "" {
+			return p.exactDate(atoi(m[3]), atoi(m[2]), atoi(m[1]), 1)
+		}
+		return p.yearlessDate(time.Month(atoi(m[2])), atoi(m[1]), today, 1)
+	}
+
+	if key == "dia" {
+		day, ok := dayOfMonth(p.key(i + 1))
+		if !ok {
+			return 0
+		}
+		for k := 0; k < 12; k++ {
+			first := time.Date(today.Year(), today.Month()+time.Month(k), 1, 0, 0, 0, 0, today.Location())
+			date := time.Date(first.Year(), first.Month(), day, 0, 0, 0, 0, today.Location())
+			if date.Month() == first.Month() && !date.Before(today) {
+				return p.setDay(date, 2)
+			}
+		}
+		return 0
+	}
+
+	if day, ok := dayOfMonth(key); ok {
+		if p.key(i+1) == "de" {
+			if month, ok := monthOf(p.key(i + 2)); ok {
+				if year, ok := yearOf(p.key(i + 4)); ok && p.key(i+3) == "de" {
+					return p.exactDate(year, int(month), day, 5)
+				}
+				return p.yearlessDate(month, day, today, 3)
+			}
+			return 0
+		}
+		if month, ok := monthOf(p.key(i + 1)); ok {
+			if year, ok := yearOf(p.key(i + 2)); ok {
+				return p.exactDate(year, int(month), day, 3)
+			}
+			return p.yearlessDate(month, day, today, 2)
+		}
+		return 0
+	}
+
+	if month, ok := monthOf(key); ok {
+		day, ok := dayOfMonth(p.key(i + 1))
+		if !ok {
+			return 0
+		}
+		if year, ok := yearOf(p.key(i + 2)); ok {
+			return p.exactDate(year, int(month), day, 3)
+		}
+		return p.yearlessDate(month, day, today, 2)
+	}
+	return 0
+}
+
+func (p *quickAddParser) exactDate(year, month, day, n int) int {
+	date := time.Date(year, time.Month(month), day, 0, 0, 0, 0, p.now.Location())
+	if date.Year() != year || date.Month() != time.Month(month) || date.Day() != day {
+		return 0
+	}
+	return p.setDay(date, n)
+}
+
+func (p *quickAddParser) yearlessDate(month time.Month, day int, today time.Time, n int) int {
+	// Até 8 anos à frente cobre o 29 de fevereiro.
+	for year := today.Year(); year <= today.Year()+8; year++ {
+		date := time.Date(year, month, day, 0, 0, 0, 0, today.Location())
+		if date.Month() == month && date.Day() == day && !date.Before(today) {
+			return p.setDay(date, n)
+		}
+	}
+	return 0
+}
+
+var clockPattern = regexp.MustCompile(`^(\d{1,2})(?:[:h](\d{2}))?(am|pm|h)?$`)
+
+// time reconhece "9am", "9:30 pm", "21:00", "21h", "21h30", "noon",
+// "meia-noite" e, depois de "at" ou "às", um número solto ("at 9") e os
+// complementos "da tarde", "da noite" e "da manhã".
+func (p *quickAddParser) time(i int) int {
+	if p.clock != nil {
+		return 0
+	}
+
+	j, bare := i, false
+	switch strings.ToLower(p.words[i].text) {
+	case "at", "às", "@":
+		j, bare = i+1, true
+	case "as":
+		// "as" sem acento também é palavra comum em inglês; só aceita
+		// horários explícitos.
+		j = i + 1
+	}
+
+	c, n := p.clockAt(j, bare)
+	if n == 0 {
+		return 0
+	}
+	n += j - i
+
+	switch {
+	case p.keys(i+n, "da", "tarde"), p.keys(i+n, "da", "noite"):
+		if c.hour < 12 {
+			c.hour += 12
+		}
+		n += 2
+	case p.keys(i+n, "da", "manha"), p.keys(i+n, "de", "manha"):
+		if c.hour == 12 {
+			c.hour = 0
+		}
+		n += 2
+	}
+
+	p.clock = &c
+	return n
+}
+
+func (p *quickAddParser) clockAt(i int, bare bool) (clock, int) {
+	switch p.key(i) {
+	case "noon", "midday", "meio-dia":
+		return clock{hour: 12}, 1
+	case "midnight", "meia-noite":
+		return clock{hour: 0}, 1
+	}
+
+	m := clockPattern.FindStringSubmatch(p.key(i))
+	if m == nil {
+		return clock{}, 0
+	}
+	c := clock{hour: atoi(m[1]), minute: atoi(m[2])}
+	n, suffix := 1, m[3]
+	if suffix == "" && (p.key(i+1) == "am" || p.key(i+1) == "pm") {
+		n, suffix = 2, p.key(i+1)
+	}
+	if suffix == "" && m[2] == "" && !bare {
+		return clock{}, 0
+	}
+
+	if c.minute > 59 {
+		return clock{}, 0
+	}
+	switch suffix {
+	case "am", "pm":
+		if c.hour < 1 || c.hour > 12 {
+			return clock{}, 0
+		}
+		c.hour %= 12
+		if suffix == "pm" {
+			c.hour += 12
+		}
+	default:
+		if c.hour > 23 {
+			return clock{}, 0
+		}
+	}
+	return c, n
+}
+
+// dueAt combina data e hora. Sem hora, vence no fim do dia; só com hora,
+// vence hoje, ou amanhã se o horário já passou.
+func (p *quickAddParser) dueAt() *time.Time {
+	c := endOfDay
+	if p.clock != nil {
+		c = *p.clock
+	}
+	at := func(day time.Time) time.Time {
+		return time.Date(day.Year(), day.Month(), day.Day(), c.hour, c.minute, 0, 0, p.now.Location())
+	}
+
+	var due time.Time
+	switch {
+	case p.day != nil:
+		due = at(*p.day)
+	case p.anchor != nil:
+		due = at(nextWeekday(p.today(), *p.anchor, 0))
+		if !due.After(p.now) {
+			due = at(nextWeekday(p.today(), *p.anchor, 1))
+		}
+	case p.clock != nil:
+		due = at(p.today())
+		if !due.After(p.now) {
+			due = at(p.today().AddDate(0, 0, 1))
+		}
+	default:
+		return nil
+	}
+	return &due
+}
+
+// nextWeekday devolve o próximo dia da semana a pelo menos after dias de
+// today.
+func nextWeekday(today time.Time, weekday time.Weekday, after int) time.Time {
+	days := (int(weekday) - int(today.Weekday()) + 7) % 7
+	if days < after {
+		days += 7
+	}
+	return today.AddDate(0, 0, days)
+}
+
+var weekdayWords = map[string]time.Weekday{
+	"sunday": time.Sunday, "domingo": time.Sunday,
+	"monday": time.Monday, "segunda": time.Monday,
+	"tuesday": time.Tuesday, "terca": time.Tuesday,
+	"wednesday": time.Wednesday, "quarta": time.Wednesday,
+	"thursday": time.Thursday, "quinta": time.Thursday,
+	"friday": time.Friday, "sexta": time.Friday,
+	"saturday": time.Saturday, "sabado": time.Saturday,
+}
+
+// weekdayOf aceita também o plural e o sufixo "-feira" ("segundas-feiras").
+func weekdayOf(key string) (time.Weekday, bool) {
+	key = strings.TrimSuffix(strings.TrimSuffix(key, "-feiras"), "-feira")
+	if weekday, ok := weekdayWords[key]; ok {
+		return weekday, true
+	}
+	weekday, ok := weekdayWords[strings.TrimSuffix(key, "s")]
+	return weekday, ok
+}
+
+var monthWords = map[string]time.Month{
+	"january": time.January, "janeiro": time.January,
+	"february": time.February, "fevereiro": time.February,
+	"march": time.March, "marco": time.March,
+	"april": time.April, "abril": time.April,
+	"may": time.May, "maio": time.May,
+	"june": time.June, "junho": time.June,
+	"july": time.July, "julho": time.July,
+	"august": time.August, "agosto": time.August,
+	"september": time.September, "setembro": time.September,
+	"october": time.October, "outubro": time.October,
+	"november": time.November, "novembro": time.November,
+	"december": time.December, "dezembro": time.December,
+}
+
+func monthOf(key string) (time.Month, bool) {
+	month, ok := monthWords[key]
+	return month, ok
+}
+
+var numberWords = map[string]int{
+	"a": 1, "an": 1, "one": 1, "um": 1, "uma": 1,
+	"two": 2, "dois": 2, "duas": 2,
+	"three": 3, "tres": 3,
+}
+
+func number(key string) (int, bool) {
+	if n, ok := numberWords[key]; ok {
+		return n, true
+	}
+	n, err := strconv.Atoi(key)
+	return n, err == nil && n > 0
+}
+
+// dayOfMonth aceita ordinais como "15th" e "1º".
+func dayOfMonth(key string) (int, bool) {
+	for _, suffix := range []string{"st", "nd", "rd", "th", "º"} {
+		key = strings.TrimSuffix(key, suffix)
+	}
+	day, err := strconv.Atoi(key)
+	return day, err == nil && day >= 1 && day <= 31
+}
+
+func yearOf(key string) (int, bool) {
+	if len(key) != 4 {
+		return 0, false
+	}
+	year, err := strconv.Atoi(key)
+	return year, err == nil
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
@@ -0,0 +1,96 @@
+package domain
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/hoyci/todo-ddd/pkg/domain/valueobject"
+)
+
+func TestParseQuickAdd(t *testing.T) {
+	brt := time.FixedZone("BRT", -3*3600)
+	// Quarta-feira, 11 de março de 2026, 10h.
+	now := time.Date(2026, 3, 11, 10, 0, 0, 0, brt)
+	at := func(month time.Month, day, hour, minute int) *time.Time {
+		year := 2026
+		if month > 12 {
+			year, month = year+1, month-12
+		}
+		due := time.Date(year, month, day, hour, minute, 0, 0, brt)
+		return &due
+	}
+	weekly := valueobject.Recurrence{Frequency: valueobject.Weekly, Interval: 1}
+
+	tests := []struct {
+		text       string
+		title      string
+		due        *time.Time
+		priority   valueobject.Priority
+		tags       []valueobject.Tag
+		recurrence valueobject.Recurrence
+	}{
+		{"Buy milk", "Buy milk", nil, 0, nil, valueobject.Recurrence{}},
+		{
+			"Pagar aluguel amanhã 9h !alta #financas todo mês", "Pagar aluguel", at(3, 12, 9, 0),
+			valueobject.High, []valueobject.Tag{"financas"}, valueobject.Recurrence{Frequency: valueobject.Monthly, Interval: 1},
+		},
+		{"Call mom tomorrow at 5pm", "Call mom", at(3, 12, 17, 0), 0, nil, valueobject.Recurrence{}},
+		{"Report friday", "Report", at(3, 13, 23, 59), 0, nil, valueobject.Recurrence{}},
+		{"Report wednesday", "Report", at(3, 11, 23, 59), 0, nil, valueobject.Recurrence{}},
+		{"Report next wednesday", "Report", at(3, 18, 23, 59), 0, nil, valueobject.Recurrence{}},
+		{"Reunião sexta que vem às 3 da tarde", "Reunião", at(3, 13, 15, 0), 0, nil, valueobject.Recurrence{}},
+		{"Depois de amanhã: revisar PR", "revisar PR", at(3, 13, 23, 59), 0, nil, valueobject.Recurrence{}},
+		{"Revisar PR depois de amanhã", "Revisar PR", at(3, 13, 23, 59), 0, nil, valueobject.Recurrence{}},
+		{"Dentista 15/03", "Dentista", at(3, 15, 23, 59), 0, nil, valueobject.Recurrence{}},
+		{"Dentista 10/03", "Dentista", at(15, 10, 23, 59), 0, nil, valueobject.Recurrence{}},
+		{"Invalid date 31/02", "Invalid date 31/02", nil, 0, nil, valueobject.Recurrence{}},
+		{"Trip 2026-02-30", "Trip 2026-02-30", nil, 0, nil, valueobject.Recurrence{}},
+		{"Entregar relatório até 15 de abril de 2027", "Entregar relatório", at(16, 15, 23, 59), 0, nil, valueobject.Recurrence{}},
+		{"Taxes april 30th", "Taxes", at(4, 30, 23, 59), 0, nil, valueobject.Recurrence{}},
+		{"Pagar cartão dia 5", "Pagar cartão", at(4, 5, 23, 59), 0, nil, valueobject.Recurrence{}},
+		{"Read in 2 weeks", "Read", at(3, 25, 23, 59), 0, nil, valueobject.Recurrence{}},
+		{"Pay bills daqui a um mês", "Pay bills", at(4, 11, 23, 59), 0, nil, valueobject.Recurrence{}},
+		{"Lunch at noon", "Lunch", at(3, 11, 12, 0), 0, nil, valueobject.Recurrence{}},
+		{"Wake up 7am", "Wake up", at(3, 12, 7, 0), 0, nil, valueobject.Recurrence{}},
+		{"Email as 9", "Email as 9", nil, 0, nil, valueobject.Recurrence{}},
+		{"Standup every monday 9:30", "Standup", at(3, 16, 9, 30), 0, nil, weekly},
+		{"Academia todas as segundas-feiras", "Academia", at(3, 16, 23, 59), 0, nil, weekly},
+		{"Gym every other day", "Gym", nil, 0, nil, valueobject.Recurrence{Frequency: valueobject.Daily, Interval: 2}},
+		{"Regar plantas a cada 3 dias", "Regar plantas", nil, 0, nil, valueobject.Recurrence{Frequency: valueobject.Daily, Interval: 3}},
+		{"Backup weekly, monthly", "Backup monthly", nil, 0, nil, weekly},
+		{"Task !alta !baixa #a #A", "Task !baixa", nil, valueobject.High, []valueobject.Tag{"a"}, valueobject.Recurrence{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got := ParseQuickAdd(tt.text, now)
+			if got.Title != tt.title {
+				t.Errorf("Title = %q, want %q", got.Title, tt.title)
+			}
+			if (got.DueAt == nil) != (tt.due == nil) || got.DueAt != nil && !got.DueAt.Equal(*tt.due) {
+				t.Errorf("DueAt = %v, want %v", got.DueAt, tt.due)
+			}
+			if got.Priority != tt.priority {
+				t.Errorf("Priority = %v, want %v", got.Priority, tt.priority)
+			}
+			if !slices.Equal(got.Tags, tt.tags) {
+				t.Errorf("Tags = %v, want %v", got.Tags, tt.tags)
+			}
+			if got.Recurrence != tt.recurrence {
+				t.Errorf("Recurrence = %v, want %v", got.Recurrence, tt.recurrence)
+			}
+		})
+	}
+}
+
+func TestParseQuickAddSpans(t *testing.T) {
+	got := ParseQuickAdd("Pagar amanhã às 9h #casa", time.Date(2026, 3, 11, 10, 0, 0, 0, time.UTC))
+	want := []Span{
+		{Kind: SpanDate, Start: 6, End: 12, Text: "amanhã"},
+		{Kind: SpanTime, Start: 13, End: 18, Text: "às 9h"},
+		{Kind: SpanTag, Start: 19, End: 24, Text: "#casa"},
+	}
+	if !slices.Equal(got.Spans, want) {
+		t.Errorf("Spans = %+v, want %+v", got.Spans, want)
+	}
+}
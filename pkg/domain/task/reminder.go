@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidReminder        = errors.New("reminder needs either an absolute time or a positive offset before the due date")
+	ErrReminderWithoutDueDate = errors.New("task has no due date to count the reminder offset from")
+	ErrReminderInPast         = errors.New("reminder time is in the past")
+)
+
+// Reminder avisa um usuário sobre uma tarefa em At ou, se At for nil,
+// BeforeDue antes do vencimento.
+type Reminder struct {
+	ID     string
+	TaskID string
+	// WorkspaceID é o workspace da tarefa, com o qual o agendador a busca.
+	WorkspaceID string
+	UserID      string
+	At          *time.Time
+	BeforeDue   time.Duration
+	// FireAt é quando o lembrete deve disparar. Começa em At ou no
+	// vencimento menos BeforeDue e é adiado pelo horário de silêncio.
+	FireAt    time.Time
+	FiredAt   *time.Time
+	CreatedAt time.Time
+}
+
+func NewReminder(task *Task, userID string, at *time.Time, beforeDue time.Duration) (*Reminder, error) {
+	if (at == nil) == (beforeDue <= 0) || beforeDue < 0 {
+		return nil, ErrInvalidReminder
+	}
+
+	var fireAt time.Time
+	switch {
+	case at != nil:
+		fireAt = *at
+	case task.DueAt == nil:
+		return nil, ErrReminderWithoutDueDate
+	default:
+		fireAt = task.DueAt.Add(-beforeDue)
+	}
+
+	now := time.Now()
+	if fireAt.Before(now) {
+		return nil, ErrReminderInPast
+	}
+
+	return &Reminder{
+		ID:          uuid.New().String(),
+		TaskID:      task.ID,
+		WorkspaceID: task.WorkspaceID,
+		UserID:      userID,
+		At:          at,
+		BeforeDue:   beforeDue,
+		FireAt:      fireAt,
+		CreatedAt:   now,
+	}, nil
+}
+
+func (r *Reminder) Pending() bool { return r.FiredAt == nil }
+
+// Reschedule acompanha a mudança de vencimento de um lembrete pendente
+// relativo a ele. Devolve false se nada mudou.
+func (r *Reminder) Reschedule(dueAt *time.Time) bool {
+	if !r.Pending() || r.At != nil || dueAt == nil {
+		return false
+	}
+	fireAt := dueAt.Add(-r.BeforeDue)
+	if fireAt.Equal(r.FireAt) {
+		return false
+	}
+	r.FireAt = fireAt
+	return true
+}
+
+// Postpone adia o disparo, como quando ele cai no horário de silêncio.
+func (r *Reminder) Postpone(until time.Time) {
+	r.FireAt = until
+}
+
+func (r *Reminder) MarkFired(at time.Time) {
+	r.FiredAt = &at
+}
+
+type ReminderRepository interface {
+	Save(ctx context.Context, reminder *Reminder) error
+	Update(ctx context.Context, reminder *Reminder) error
+	Find(ctx context.Context, taskID, id string) (*Reminder, error)
+	// ListByTask devolve os lembretes da tarefa, de todos os usuários, por
+	// horário de disparo.
+	ListByTask(ctx context.Context, taskID string) ([]*Reminder, error)
+	// ListDue devolve até limit lembretes pendentes com disparo até before,
+	// dos mais atrasados aos mais recentes.
+	ListDue(ctx context.Context, before time.Time, limit int) ([]*Reminder, error)
+	Delete(ctx context.Context, id string) error
+	DeleteByTasks(ctx context.Context, taskIDs []string) error
+}
@@ -0,0 +1,96 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNewReminder(t *testing.T) {
+	due := time.Now().Add(48 * time.Hour)
+	at := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Hour)
+
+	withDue := &Task{ID: "t1", DueAt: &due}
+	withoutDue := &Task{ID: "t1"}
+
+	tests := []struct {
+		name      string
+		task      *Task
+		at        *time.Time
+		beforeDue time.Duration
+		wantFire  time.Time
+		wantErr   error
+	}{
+		{"absolute time", withoutDue, &at, 0, at, nil},
+		{"offset before due", withDue, nil, 2 * time.Hour, due.Add(-2 * time.Hour), nil},
+		{"both", withDue, &at, time.Hour, time.Time{}, ErrInvalidReminder},
+		{"neither", withDue, nil, 0, time.Time{}, ErrInvalidReminder},
+		{"negative offset", withDue, nil, -time.Hour, time.Time{}, ErrInvalidReminder},
+		{"offset without due date", withoutDue, nil, time.Hour, time.Time{}, ErrReminderWithoutDueDate},
+		{"absolute time in the past", withDue, &past, 0, time.Time{}, ErrReminderInPast},
+		{"offset lands in the past", withDue, nil, 72 * time.Hour, time.Time{}, ErrReminderInPast},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reminder, err := NewReminder(tt.task, "u1", tt.at, tt.beforeDue)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if !reminder.FireAt.Equal(tt.wantFire) {
+				t.Errorf("FireAt = %s, want %s", reminder.FireAt, tt.wantFire)
+			}
+			if !reminder.Pending() {
+				t.Error("a new reminder should be pending")
+			}
+		})
+	}
+}
+
+func TestReminderReschedule(t *testing.T) {
+	due := time.Now().Add(48 * time.Hour)
+	later := due.Add(24 * time.Hour)
+	at := time.Now().Add(time.Hour)
+	task := &Task{ID: "t1", DueAt: &due}
+
+	offset := func() *Reminder {
+		r, err := NewReminder(task, "u1", nil, time.Hour)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return r
+	}
+	fired := offset()
+	fired.MarkFired(time.Now())
+	absolute, err := NewReminder(task, "u1", &at, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		reminder *Reminder
+		dueAt    *time.Time
+		want     bool
+		wantFire time.Time
+	}{
+		{"follows the new due date", offset(), &later, true, later.Add(-time.Hour)},
+		{"same due date", offset(), &due, false, due.Add(-time.Hour)},
+		{"due date removed", offset(), nil, false, due.Add(-time.Hour)},
+		{"already fired", fired, &later, false, due.Add(-time.Hour)},
+		{"absolute time", absolute, &later, false, at},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.reminder.Reschedule(tt.dueAt); got != tt.want {
+				t.Errorf("Reschedule = %v, want %v", got, tt.want)
+			}
+			if !tt.reminder.FireAt.Equal(tt.wantFire) {
+				t.Errorf("FireAt = %s, want %s", tt.reminder.FireAt, tt.wantFire)
+			}
+		})
+	}
+}
@@ -1,11 +1,98 @@
 package domain
 
-import "time"
+import (
+	"context"
+	"time"
 
+	"github.com/hoyci/todo-ddd/pkg/domain/valueobject"
+)
+
+// TaskPageQuery seleciona uma página das tarefas de um usuário em ordem de
+// criação. After é a última tarefa da página anterior (nil na primeira).
+type TaskPageQuery struct {
+	UserID         string
+	IncludeDeleted bool
+	After          *Task
+	Limit          int
+}
+
+// TaskFilter restringe uma listagem por status, prioridade e texto no
+// título ou na descrição. Campos vazios não filtram.
+type TaskFilter struct {
+	Status   valueobject.Status
+	Priority valueobject.Priority
+	Search   string
+}
+
+// TasksByUsersQuery seleciona as tarefas ativas de vários donos que
+// ViewerID enxerga, pela mesma regra de FindByID, em ordem de criação.
+// AfterID é a última tarefa da página anterior (vazio na primeira) e
+// Limit vale para cada dono; zero devolve todas.
+type TasksByUsersQuery struct {
+	UserIDs  []string
+	ViewerID string
+	Filter   TaskFilter
+	AfterID  string
+	Limit    int
+}
+
+// UserTaskCount é a quantidade de tarefas de um dono em um status.
+type UserTaskCount struct {
+	UserID string
+	Status valueobject.Status
+	Count  int
+}
+
+// TaskRepository só enxerga as tarefas do workspace do contexto (ver
+// ScopeFrom no pacote workspace), exceto nas operações de manutenção
+// ListDeletedBefore e Purge.
 type TaskRepository interface {
-	Save(task *Task) error
-	FindByID(id, userID string) (*Task, error)
-	List(userID string) ([]*Task, error)
-	Update(task *Task) error
-	Delete(id string, timestamp time.Time) error
+	Save(ctx context.Context, task *Task) error
+	// FindByID devolve a tarefa se userID for o dono, o responsável ou
+	// tiver um compartilhamento dela; em um workspace, se for membro.
+	FindByID(ctx context.Context, id, userID string) (*Task, error)
+	// List devolve as tarefas ativas do usuário ou, em um workspace, todas
+	// as do workspace se ele for membro, na ordem manual da lista: as nunca
+	// reordenadas primeiro e, empatadas, por criação.
+	List(ctx context.Context, userID string) ([]*Task, error)
+	// ListAssigned devolve as tarefas ativas designadas ao usuário, de
+	// qualquer dono, ordenadas por vencimento e depois por criação.
+	ListAssigned(ctx context.Context, assigneeID string) ([]*Task, error)
+	// ListByUserIDs devolve as tarefas da consulta em uma só ida ao banco,
+	// ordenadas por criação.
+	ListByUserIDs(ctx context.Context, query TasksByUsersQuery) ([]*Task, error)
+	// CountByUserIDs conta, por dono e status, as tarefas que a consulta
+	// selecionaria sem AfterID e Limit.
+	CountByUserIDs(ctx context.Context, query TasksByUsersQuery) ([]UserTaskCount, error)
+	ListPage(ctx context.Context, query TaskPageQuery) ([]*Task, error)
+	Update(ctx context.Context, task *Task) error
+	// Reorder grava a ordem manual das tarefas: a posição de cada uma é o
+	// índice em ids mais um.
+	Reorder(ctx context.Context, ids []string) error
+	Delete(ctx context.Context, id string, timestamp time.Time) error
+	// Restore desfaz a exclusão da tarefa.
+	Restore(ctx context.Context, id string, timestamp time.Time) error
+	// ListDeletedBefore devolve até limit IDs de tarefas excluídas antes de
+	// before, candidatas a remoção definitiva.
+	ListDeletedBefore(ctx context.Context, before time.Time, limit int) ([]string, error)
+	// Purge remove as tarefas definitivamente.
+	Purge(ctx context.Context, ids []string) error
+}
+
+// CommentPageQuery seleciona uma página dos comentários de uma tarefa em
+// ordem de criação. AfterID é o último comentário da página anterior (vazio
+// na primeira); Limit zero devolve todos.
+type CommentPageQuery struct {
+	TaskID         string
+	IncludeDeleted bool
+	AfterID        string
+	Limit          int
+}
+
+type CommentRepository interface {
+	Save(ctx context.Context, comment *Comment) error
+	FindByID(ctx context.Context, id, taskID string) (*Comment, error)
+	ListPage(ctx context.Context, query CommentPageQuery) ([]*Comment, error)
+	Update(ctx context.Context, comment *Comment) error
+	DeleteByTasks(ctx context.Context, taskIDs []string) error
 }
@@ -0,0 +1,194 @@
+package domain
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hoyci/todo-ddd/pkg/domain/valueobject"
+)
+
+// Revision é o estado editável de uma tarefa gravado a cada salvamento.
+// Number começa em 1 e cresce por tarefa.
+type Revision struct {
+	TaskID      string
+	Number      int
+	AuthorID    string
+	Title       string
+	Description string
+	Priority    valueobject.Priority
+	Status      valueobject.Status
+	AssigneeID  string
+	DueAt       *time.Time
+	Tags        []valueobject.Tag
+	Recurrence  valueobject.Recurrence
+	// RevertedFrom é a revisão restaurada, quando esta veio de uma
+	// reversão; zero caso contrário.
+	RevertedFrom int
+	CreatedAt    time.Time
+}
+
+// NewRevision registra o estado atual da tarefa. O número é atribuído pelo
+// repositório.
+func NewRevision(task *Task, authorID string) *Revision {
+	createdAt := task.CreatedAt
+	if task.UpdatedAt != nil {
+		createdAt = *task.UpdatedAt
+	}
+	return &Revision{
+		TaskID:      task.ID,
+		AuthorID:    authorID,
+		Title:       task.Title,
+		Description: task.Description,
+		Priority:    task.Priority,
+		Status:      task.Status,
+		AssigneeID:  task.AssigneeID,
+		DueAt:       task.DueAt,
+		Tags:        slices.Clone(task.Tags),
+		Recurrence:  task.Recurrence,
+		CreatedAt:   createdAt,
+	}
+}
+
+// state devolve a revisão como tarefa, para reaproveitar a comparação de
+// campos do histórico de atividade.
+func (r *Revision) state() *Task {
+	return &Task{
+		ID:          r.TaskID,
+		Title:       r.Title,
+		Description: r.Description,
+		Priority:    r.Priority,
+		Status:      r.Status,
+		AssigneeID:  r.AssigneeID,
+		DueAt:       r.DueAt,
+		Tags:        r.Tags,
+		Recurrence:  r.Recurrence,
+	}
+}
+
+// Revert devolve a tarefa ao estado da revisão. Devolve false se a tarefa
+// já estava nesse estado.
+func (t *Task) Revert(revision *Revision) bool {
+	fields, status := diffTasks(t, revision.state())
+	if len(fields) == 0 && status == nil {
+		return false
+	}
+
+	t.Title = revision.Title
+	t.Description = revision.Description
+	t.Priority = revision.Priority
+	t.AssigneeID = revision.AssigneeID
+	t.DueAt = revision.DueAt
+	t.Tags = slices.Clone(revision.Tags)
+	t.Recurrence = revision.Recurrence
+	t.touch()
+	if len(fields) > 0 {
+		t.record(EventUpdated)
+	}
+	if status != nil {
+		t.setStatus(revision.Status)
+	}
+	return true
+}
+
+type LineOp string
+
+const (
+	LineEqual  LineOp = "equal"
+	LineInsert LineOp = "insert"
+	LineDelete LineOp = "delete"
+)
+
+// LineChange é uma linha de um diff de texto.
+type LineChange struct {
+	Op   LineOp
+	Text string
+}
+
+// RevisionDiff compara duas revisões da mesma tarefa: os campos alterados e,
+// linha a linha, a descrição.
+type RevisionDiff struct {
+	From        int
+	To          int
+	Changes     []FieldChange
+	Description []LineChange
+}
+
+func DiffRevisions(from, to *Revision) RevisionDiff {
+	fields, status := diffTasks(from.state(), to.state())
+	if status != nil {
+		fields = append(fields, *status)
+	}
+
+	diff := RevisionDiff{From: from.Number, To: to.Number, Changes: fields}
+	if from.Description != to.Description {
+		diff.Description = DiffLines(from.Description, to.Description)
+	}
+	return diff
+}
+
+// DiffLines compara os textos linha a linha pela maior subsequência comum,
+// listando remoções antes das inclusões em cada trecho alterado.
+func DiffLines(before, after string) []LineChange {
+	a, b := splitLines(before), splitLines(after)
+
+	// lcs[i][j] é o tamanho da maior subsequência comum de a[i:] e b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var changes []LineChange
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			changes = append(changes, LineChange{Op: LineEqual, Text: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			changes = append(changes, LineChange{Op: LineDelete, Text: a[i]})
+			i++
+		default:
+			changes = append(changes, LineChange{Op: LineInsert, Text: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		changes = append(changes, LineChange{Op: LineDelete, Text: a[i]})
+	}
+	for ; j < len(b); j++ {
+		changes = append(changes, LineChange{Op: LineInsert, Text: b[j]})
+	}
+	return changes
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
+
+// RevisionRepository guarda o histórico de revisões das tarefas.
+type RevisionRepository interface {
+	// Append grava a revisão, atribuindo a ela o próximo número da tarefa.
+	Append(ctx context.Context, revision *Revision) error
+	// ListByTask devolve as revisões da tarefa em ordem crescente.
+	ListByTask(ctx context.Context, taskID string) ([]*Revision, error)
+	Find(ctx context.Context, taskID string, number int) (*Revision, error)
+	// LastNumber devolve o número da revisão mais recente da tarefa, ou zero
+	// se ela não tiver revisões.
+	LastNumber(ctx context.Context, taskID string) (int, error)
+	DeleteByTasks(ctx context.Context, taskIDs []string) error
+}
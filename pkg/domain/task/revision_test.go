@@ -0,0 +1,147 @@
+package domain
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/hoyci/todo-ddd/pkg/domain/valueobject"
+)
+
+func TestDiffLines(t *testing.T) {
+	eq := func(text string) LineChange { return LineChange{Op: LineEqual, Text: text} }
+	ins := func(text string) LineChange { return LineChange{Op: LineInsert, Text: text} }
+	del := func(text string) LineChange { return LineChange{Op: LineDelete, Text: text} }
+
+	tests := []struct {
+		name   string
+		before string
+		after  string
+		want   []LineChange
+	}{
+		{"both empty", "", "", nil},
+		{"from empty", "", "a\nb", []LineChange{ins("a"), ins("b")}},
+		{"to empty", "a\nb", "", []LineChange{del("a"), del("b")}},
+		{"unchanged", "a\nb", "a\nb", []LineChange{eq("a"), eq("b")}},
+		{"line appended", "a\nb", "a\nb\nc", []LineChange{eq("a"), eq("b"), ins("c")}},
+		{"line removed", "a\nb\nc", "a\nc", []LineChange{eq("a"), del("b"), eq("c")}},
+		{"line replaced", "a\nb\nc", "a\nx\nc", []LineChange{eq("a"), del("b"), ins("x"), eq("c")}},
+		{"block replaced", "a\nb\nc\nd", "a\nx\ny\nd", []LineChange{eq("a"), del("b"), del("c"), ins("x"), ins("y"), eq("d")}},
+		{"trailing newline", "a", "a\n", []LineChange{eq("a"), ins("")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DiffLines(tt.before, tt.after); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DiffLines(%q, %q) = %v, want %v", tt.before, tt.after, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffRevisions(t *testing.T) {
+	from := &Revision{Number: 1, Title: "Draft", Description: "a\nb", Priority: valueobject.Low, Status: valueobject.StatusNew}
+
+	tests := []struct {
+		name        string
+		to          Revision
+		changes     []FieldChange
+		description []LineChange
+	}{
+		{
+			name: "no changes",
+			to:   *from,
+		},
+		{
+			name:    "status listed after fields",
+			to:      Revision{Title: "Final", Description: "a\nb", Priority: valueobject.High, Status: valueobject.StatusCompleted},
+			changes: []FieldChange{{Field: "title", From: "Draft", To: "Final"}, {Field: "priority", From: 1, To: 3}, {Field: "status", From: "new", To: "completed"}},
+		},
+		{
+			name:        "description diffed by line",
+			to:          Revision{Title: "Draft", Description: "a\nc", Priority: valueobject.Low, Status: valueobject.StatusNew},
+			changes:     []FieldChange{{Field: "description", From: "a\nb", To: "a\nc"}},
+			description: []LineChange{{Op: LineEqual, Text: "a"}, {Op: LineDelete, Text: "b"}, {Op: LineInsert, Text: "c"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			to := tt.to
+			to.Number = 3
+			diff := DiffRevisions(from, &to)
+			if diff.From != 1 || diff.To != 3 {
+				t.Errorf("diff between %d and %d, want 1 and 3", diff.From, diff.To)
+			}
+			if !reflect.DeepEqual(diff.Changes, tt.changes) {
+				t.Errorf("Changes = %+v, want %+v", diff.Changes, tt.changes)
+			}
+			if !reflect.DeepEqual(diff.Description, tt.description) {
+				t.Errorf("Description = %+v, want %+v", diff.Description, tt.description)
+			}
+		})
+	}
+}
+
+func TestRevert(t *testing.T) {
+	newTask := func(t *testing.T) *Task {
+		t.Helper()
+		task, err := NewTask("Draft", "a", "u1", valueobject.Low, WithTags("work"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		task.PullEvents()
+		return task
+	}
+
+	t.Run("same state", func(t *testing.T) {
+		task := newTask(t)
+		if task.Revert(NewRevision(task, "u1")) {
+			t.Error("Revert = true for the current state")
+		}
+		if events := task.PullEvents(); events != nil {
+			t.Errorf("events = %v, want none", events)
+		}
+	})
+
+	tests := []struct {
+		name   string
+		change func(*Task)
+		events []EventType
+	}{
+		{"fields", func(task *Task) { task.Rename("Final"); task.SetTags([]valueobject.Tag{"home"}) }, []EventType{EventUpdated}},
+		{"status", func(task *Task) { task.SetCompleted() }, []EventType{EventStatusChanged}},
+		{"fields and status", func(task *Task) { task.SetPriority(valueobject.High); task.SetInProgress() }, []EventType{EventUpdated, EventStatusChanged}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := newTask(t)
+			revision := NewRevision(task, "u1")
+			tt.change(task)
+			task.PullEvents()
+
+			if !task.Revert(revision) {
+				t.Fatal("Revert = false, want true")
+			}
+			if fields, status := diffTasks(task, revision.state()); fields != nil || status != nil {
+				t.Errorf("task differs from the revision after Revert: %+v %+v", fields, status)
+			}
+			var got []EventType
+			for _, event := range task.PullEvents() {
+				got = append(got, event.Type)
+			}
+			if !slices.Equal(got, tt.events) {
+				t.Errorf("events = %v, want %v", got, tt.events)
+			}
+		})
+	}
+
+	t.Run("tags are copied", func(t *testing.T) {
+		task := newTask(t)
+		revision := NewRevision(task, "u1")
+		task.SetTags(nil)
+		task.Revert(revision)
+		task.Tags[0] = "changed"
+		if revision.Tags[0] != "work" {
+			t.Errorf("revision tags = %v, Revert shares the slice with the task", revision.Tags)
+		}
+	})
+}
@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/hoyci/todo-ddd/pkg/domain/valueobject"
+)
+
+// Access é o que um usuário pode fazer em uma tarefa. Cada nível inclui os
+// anteriores.
+type Access int
+
+const (
+	AccessNone Access = iota
+	AccessView
+	AccessEdit
+	AccessOwner
+)
+
+// Share concede a outro usuário acesso a uma tarefa.
+type Share struct {
+	TaskID     string
+	UserID     string
+	Permission valueobject.SharePermission
+	GrantedBy  string
+	CreatedAt  time.Time
+}
+
+func NewShare(taskID, userID, grantedBy, permission string) (*Share, error) {
+	permissionVO, err := valueobject.NewSharePermission(permission)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Share{
+		TaskID:     taskID,
+		UserID:     userID,
+		Permission: permissionVO,
+		GrantedBy:  grantedBy,
+		CreatedAt:  time.Now(),
+	}, nil
+}
+
+// AccessFor calcula o acesso de userID à tarefa. share é o compartilhamento
+// concedido a ele, ou nil. O responsável pode editar, mas só o dono
+// exclui a tarefa e gerencia compartilhamentos. Sem usuário não há acesso,
+// nem às tarefas sem responsável.
+func (t *Task) AccessFor(userID string, share *Share) Access {
+	switch {
+	case userID == "":
+		return AccessNone
+	case t.UserID == userID:
+		return AccessOwner
+	case t.AssigneeID == userID:
+		return AccessEdit
+	case share != nil && share.Permission == valueobject.SharePermissionEdit:
+		return AccessEdit
+	case share != nil:
+		return AccessView
+	default:
+		return AccessNone
+	}
+}
+
+// Watcher é um usuário avisado das mudanças em uma tarefa.
+type Watcher struct {
+	TaskID    string
+	UserID    string
+	CreatedAt time.Time
+}
+
+type ShareRepository interface {
+	// Save cria o compartilhamento ou troca a permissão de um existente.
+	Save(ctx context.Context, share *Share) error
+	Find(ctx context.Context, taskID, userID string) (*Share, error)
+	ListByTask(ctx context.Context, taskID string) ([]*Share, error)
+	Delete(ctx context.Context, taskID, userID string) error
+	DeleteByTasks(ctx context.Context, taskIDs []string) error
+}
+
+type WatcherRepository interface {
+	// Add ignora quem já acompanha a tarefa.
+	Add(ctx context.Context, watcher *Watcher) error
+	Remove(ctx context.Context, taskID, userID string) error
+	ListByTask(ctx context.Context, taskID string) ([]*Watcher, error)
+	DeleteByTasks(ctx context.Context, taskIDs []string) error
+}
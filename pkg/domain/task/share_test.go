@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/hoyci/todo-ddd/pkg/domain/valueobject"
+)
+
+func TestAccessFor(t *testing.T) {
+	task := &Task{UserID: "owner", AssigneeID: "assignee"}
+	view := &Share{Permission: valueobject.SharePermissionView}
+	edit := &Share{Permission: valueobject.SharePermissionEdit}
+
+	tests := []struct {
+		name   string
+		userID string
+		share  *Share
+		want   Access
+	}{
+		{"owner", "owner", nil, AccessOwner},
+		{"owner with a share", "owner", view, AccessOwner},
+		{"assignee", "assignee", nil, AccessEdit},
+		{"assignee with a view share", "assignee", view, AccessEdit},
+		{"edit share", "friend", edit, AccessEdit},
+		{"view share", "friend", view, AccessView},
+		{"stranger", "stranger", nil, AccessNone},
+	}
+	for _, tt := range tests {
+		if got := task.AccessFor(tt.userID, tt.share); got != tt.want {
+			t.Errorf("%s: AccessFor = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+	// Sem responsável, o anônimo não pode casar com o campo vazio.
+	if got := (&Task{UserID: "owner"}).AccessFor("", nil); got != AccessNone {
+		t.Errorf("anonymous on an unassigned task: AccessFor = %d, want %d", got, AccessNone)
+	}
+}
@@ -0,0 +1,11 @@
+package domain
+
+import "github.com/hoyci/todo-ddd/pkg/domain/valueobject"
+
+// OpenTaskCount é um read model com a quantidade de tarefas abertas
+// (não concluídas e não removidas) por status e prioridade.
+type OpenTaskCount struct {
+	Status   valueobject.Status
+	Priority valueobject.Priority
+	Count    int
+}
@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
@@ -14,18 +15,55 @@ type Task struct {
 	Priority    valueobject.Priority
 	Status      valueobject.Status
 	UserID      string
-	CreatedAt   time.Time
-	UpdatedAt   *time.Time
-	DeletedAt   *time.Time
+	AssigneeID  string // responsável pela execução; vazio se não houver
+	WorkspaceID string // vazio para tarefas do espaço pessoal
+	DueAt       *time.Time
+	Tags        []valueobject.Tag
+	Recurrence  valueobject.Recurrence
+	// Position é a ordem manual da tarefa na lista; zero se ela nunca foi
+	// reordenada.
+	Position  int
+	CreatedAt time.Time
+	UpdatedAt *time.Time
+	DeletedAt *time.Time
+
+	events []EventType
+}
+
+// Option define campos opcionais na criação da tarefa.
+type Option func(*Task)
+
+func WithDueAt(dueAt time.Time) Option {
+	return func(t *Task) { t.DueAt = &dueAt }
+}
+
+// WithTags adiciona as tags, ignorando repetidas.
+func WithTags(tags ...valueobject.Tag) Option {
+	return func(t *Task) {
+		for _, tag := range tags {
+			if !slices.Contains(t.Tags, tag) {
+				t.Tags = append(t.Tags, tag)
+			}
+		}
+	}
 }
 
-func NewTask(title, description, userID string, priority valueobject.Priority) (*Task, error) {
+// InWorkspace cria a tarefa dentro do workspace.
+func InWorkspace(workspaceID string) Option {
+	return func(t *Task) { t.WorkspaceID = workspaceID }
+}
+
+func WithRecurrence(recurrence valueobject.Recurrence) Option {
+	return func(t *Task) { t.Recurrence = recurrence }
+}
+
+func NewTask(title, description, userID string, priority valueobject.Priority, opts ...Option) (*Task, error) {
 	titleVO, err := valueobject.NewTaskTitle(title)
 	if err != nil {
 		return nil, err
 	}
 
-	return &Task{
+	task := &Task{
 		ID:          uuid.New().String(),
 		Title:       titleVO.String(),
 		Description: description,
@@ -35,24 +73,108 @@ func NewTask(title, description, userID string, priority valueobject.Priority) (
 		CreatedAt:   time.Now(),
 		UpdatedAt:   nil,
 		DeletedAt:   nil,
-	}, nil
+	}
+	for _, opt := range opts {
+		opt(task)
+	}
+	task.record(EventCreated)
+	return task, nil
 }
 
 func (t *Task) setStatus(status valueobject.Status) {
 	now := time.Now()
 	t.Status = status
 	t.UpdatedAt = &now
+	t.record(EventStatusChanged)
 }
 
 func (t *Task) SetAsNew()      { t.setStatus(valueobject.StatusNew) }
 func (t *Task) SetInProgress() { t.setStatus(valueobject.StatusInProgress) }
 func (t *Task) SetCompleted()  { t.setStatus(valueobject.StatusCompleted) }
 
-func (t *Task) Update(title, description string, priority valueobject.Priority) {
+func (t *Task) Update(title, description string, priority valueobject.Priority) error {
+	titleVO, err := valueobject.NewTaskTitle(title)
+	if err != nil {
+		return err
+	}
+
 	now := time.Now()
-	t.Title = title
+	t.Title = titleVO.String()
+	t.Description = description
+	t.Priority = priority
+	t.UpdatedAt = &now
+	t.record(EventUpdated)
+	return nil
+}
+
+func (t *Task) Rename(title string) error {
+	titleVO, err := valueobject.NewTaskTitle(title)
+	if err != nil {
+		return err
+	}
+
+	t.Title = titleVO.String()
+	t.touch()
+	t.record(EventUpdated)
+	return nil
+}
+
+func (t *Task) SetDescription(description string) {
 	t.Description = description
+	t.touch()
+	t.record(EventUpdated)
+}
+
+func (t *Task) SetPriority(priority valueobject.Priority) {
 	t.Priority = priority
+	t.touch()
+	t.record(EventUpdated)
+}
+
+// SetDueAt muda o vencimento da tarefa; nil o remove.
+func (t *Task) SetDueAt(dueAt *time.Time) {
+	t.DueAt = dueAt
+	t.touch()
+	t.record(EventUpdated)
+}
+
+// SetTags substitui as tags da tarefa, ignorando repetidas.
+func (t *Task) SetTags(tags []valueobject.Tag) {
+	t.Tags = nil
+	WithTags(tags...)(t)
+	t.touch()
+	t.record(EventUpdated)
+}
+
+// SetRecurrence muda a recorrência; o valor zero faz a tarefa não se
+// repetir mais.
+func (t *Task) SetRecurrence(recurrence valueobject.Recurrence) {
+	t.Recurrence = recurrence
+	t.touch()
+	t.record(EventUpdated)
+}
+
+// Assign designa o responsável pela tarefa; vazio remove a designação.
+// Devolve false se nada mudou.
+func (t *Task) Assign(assigneeID string) bool {
+	if t.AssigneeID == assigneeID {
+		return false
+	}
+	t.AssigneeID = assigneeID
+	t.touch()
+	t.record(EventAssigned)
+	return true
+}
+
+// MoveTo muda a posição da tarefa na lista.
+func (t *Task) MoveTo(position int) {
+	t.Position = position
+	t.touch()
+	t.record(EventReordered)
+}
+
+func (t *Task) touch() {
+	now := time.Now()
 	t.UpdatedAt = &now
 }
 
@@ -60,4 +182,51 @@ func (t *Task) Delete() {
 	now := time.Now()
 	t.UpdatedAt = &now
 	t.DeletedAt = &now
+	t.record(EventDeleted)
+}
+
+// Undelete desfaz a exclusão da tarefa.
+func (t *Task) Undelete() {
+	t.DeletedAt = nil
+	t.touch()
+	t.record(EventRestored)
+}
+
+// record guarda um evento pendente, ignorando repetições do mesmo tipo.
+func (t *Task) record(eventType EventType) {
+	for _, pending := range t.events {
+		if pending == eventType {
+			return
+		}
+	}
+	t.events = append(t.events, eventType)
+}
+
+// PullEvents devolve os eventos pendentes, com o estado atual da tarefa, e
+// limpa a lista.
+func (t *Task) PullEvents() []Event {
+	if len(t.events) == 0 {
+		return nil
+	}
+
+	snapshot := *t
+	snapshot.Tags = slices.Clone(t.Tags)
+	snapshot.events = nil
+	occurredAt := t.CreatedAt
+	if t.UpdatedAt != nil {
+		occurredAt = *t.UpdatedAt
+	}
+
+	events := make([]Event, 0, len(t.events))
+	for _, eventType := range t.events {
+		events = append(events, Event{
+			Type:       eventType,
+			TaskID:     t.ID,
+			UserID:     t.UserID,
+			Task:       snapshot,
+			OccurredAt: occurredAt,
+		})
+	}
+	t.events = nil
+	return events
 }
@@ -0,0 +1,95 @@
+package domain
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+)
+
+type Op string
+
+const (
+	// OpRestore volta a tarefa ao estado da revisão Restore.
+	OpRestore Op = "restore"
+	// OpDelete exclui a tarefa, desfazendo a criação.
+	OpDelete Op = "delete"
+	// OpUndelete restaura a tarefa excluída.
+	OpUndelete Op = "undelete"
+)
+
+// Step desfaz a mudança feita em uma tarefa.
+type Step struct {
+	TaskID string
+	Op     Op
+	// Restore é a revisão restaurada em OpRestore.
+	Restore int
+	// Version é a última revisão da tarefa logo depois da mudança. Se outra
+	// revisão foi gravada desde então, a tarefa mudou e o desfazer é
+	// recusado.
+	Version int
+}
+
+// Undo permite desfazer, uma única vez e até ExpiresAt, a mudança feita por
+// um caso de uso. Os passos são desfeitos do último para o primeiro.
+type Undo struct {
+	// Token não entra em representações JSON, como as do log de auditoria.
+	Token     string `json:"-"`
+	UserID    string
+	Action    string
+	Steps     []Step
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+}
+
+func NewUndo(userID, action string, steps []Step, window time.Duration) (*Undo, error) {
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	return &Undo{
+		Token:     hex.EncodeToString(token),
+		UserID:    userID,
+		Action:    action,
+		Steps:     steps,
+		CreatedAt: now,
+		ExpiresAt: now.Add(window),
+		UsedAt:    nil,
+	}, nil
+}
+
+// Pending indica se a mudança ainda pode ser desfeita.
+func (u *Undo) Pending(now time.Time) bool {
+	return u.UsedAt == nil && now.Before(u.ExpiresAt)
+}
+
+func (u *Undo) Use() {
+	now := time.Now()
+	u.UsedAt = &now
+}
+
+// State é o estado de uma tarefa deixado pela mudança.
+type State struct {
+	Version int
+	Deleted bool
+}
+
+// Expected devolve, para cada tarefa, a última revisão e se ela deve estar
+// excluída para que a mudança ainda possa ser desfeita.
+func (u *Undo) Expected() map[string]State {
+	states := map[string]State{}
+	for _, step := range u.Steps {
+		states[step.TaskID] = State{Version: step.Version, Deleted: step.Op == OpUndelete}
+	}
+	return states
+}
+
+type Repository interface {
+	// Save grava o desfazer, descartando os que já expiraram.
+	Save(ctx context.Context, undo *Undo) error
+	FindByToken(ctx context.Context, token string) (*Undo, error)
+	Update(ctx context.Context, undo *Undo) error
+}
@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPending(t *testing.T) {
+	undo, err := NewUndo("u1", "task.updated", nil, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	used := *undo
+	used.Use()
+
+	tests := []struct {
+		name string
+		undo *Undo
+		now  time.Time
+		want bool
+	}{
+		{"within the window", undo, undo.CreatedAt.Add(30 * time.Second), true},
+		{"at expiry", undo, undo.ExpiresAt, false},
+		{"after expiry", undo, undo.ExpiresAt.Add(time.Second), false},
+		{"already used", &used, undo.CreatedAt, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.undo.Pending(tt.now); got != tt.want {
+				t.Errorf("Pending = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewUndoToken(t *testing.T) {
+	a, err := NewUndo("u1", "task.created", nil, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := NewUndo("u1", "task.created", nil, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(a.Token) != 64 || a.Token == b.Token {
+		t.Errorf("tokens %q and %q, want two distinct 32-byte hex tokens", a.Token, b.Token)
+	}
+}
+
+func TestExpected(t *testing.T) {
+	tests := []struct {
+		name  string
+		steps []Step
+		want  map[string]State
+	}{
+		{"no steps", nil, map[string]State{}},
+		{
+			name:  "restore and delete",
+			steps: []Step{{TaskID: "t1", Op: OpRestore, Restore: 1, Version: 2}, {TaskID: "t2", Op: OpDelete, Version: 1}},
+			want:  map[string]State{"t1": {Version: 2}, "t2": {Version: 1}},
+		},
+		{
+			name:  "undelete expects the task deleted",
+			steps: []Step{{TaskID: "t1", Op: OpUndelete, Version: 3}},
+			want:  map[string]State{"t1": {Version: 3, Deleted: true}},
+		},
+		{
+			// O último passo de cada tarefa é o estado deixado pela mudança.
+			name:  "last step of a task wins",
+			steps: []Step{{TaskID: "t1", Op: OpRestore, Restore: 1, Version: 2}, {TaskID: "t1", Op: OpUndelete, Version: 2}},
+			want:  map[string]State{"t1": {Version: 2, Deleted: true}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			undo := &Undo{Steps: tt.steps}
+			if got := undo.Expected(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
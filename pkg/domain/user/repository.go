@@ -1,14 +1,28 @@
 package domain
 
 import (
+	"context"
 	"time"
 )
 
+// UserPageQuery seleciona uma página dos usuários ativos em ordem de
+// criação. Search filtra por nome ou email; AfterID é o último usuário da
+// página anterior (vazio na primeira).
+type UserPageQuery struct {
+	Search  string
+	AfterID string
+	Limit   int
+}
+
 type UserRepository interface {
-	Save(user User) error
-	FindByID(id string) (*User, error)
-	FindByEmail(email string) (*User, error)
-	List() ([]*User, error)
-	Update(user User) error
-	Delete(id string, timestamp time.Time) error
+	Save(ctx context.Context, user User) error
+	FindByID(ctx context.Context, id string) (*User, error)
+	FindByEmail(ctx context.Context, email string) (*User, error)
+	// FindByIDs devolve os usuários ativos encontrados entre os IDs informados.
+	FindByIDs(ctx context.Context, ids []string) ([]*User, error)
+	ListPage(ctx context.Context, query UserPageQuery) ([]*User, error)
+	// Count conta os usuários ativos que casam com search.
+	Count(ctx context.Context, search string) (int, error)
+	Update(ctx context.Context, user User) error
+	Delete(ctx context.Context, id string, timestamp time.Time) error
 }
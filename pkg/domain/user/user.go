@@ -8,15 +8,25 @@ import (
 )
 
 type User struct {
-	ID        string
-	Name      string
-	Email     string
-	CreatedAt time.Time
-	UpdatedAt *time.Time
-	DeletedAt *time.Time
+	ID    string
+	Name  string
+	Email string
+	// VerifiedAt é nil enquanto o usuário não confirmou o email.
+	VerifiedAt *time.Time
+	// VerificationSentAt é quando o último email de verificação saiu; limita
+	// os reenvios.
+	VerificationSentAt *time.Time
+	CreatedAt          time.Time
+	UpdatedAt          *time.Time
+	DeletedAt          *time.Time
 }
 
 func NewUser(name, email string) (*User, error) {
+	nameVO, err := valueobject.NewUserName(name)
+	if err != nil {
+		return nil, err
+	}
+
 	emailVO, err := valueobject.NewEmail(email)
 	if err != nil {
 		return nil, err
@@ -24,7 +34,7 @@ func NewUser(name, email string) (*User, error) {
 
 	return &User{
 		ID:        uuid.New().String(),
-		Name:      name,
+		Name:      nameVO.String(),
 		Email:     emailVO.String(),
 		CreatedAt: time.Now(),
 		UpdatedAt: nil,
@@ -32,8 +42,66 @@ func NewUser(name, email string) (*User, error) {
 	}, nil
 }
 
+func (t *User) Rename(name string) error {
+	nameVO, err := valueobject.NewUserName(name)
+	if err != nil {
+		return err
+	}
+
+	t.Name = nameVO.String()
+	t.touch()
+	return nil
+}
+
+func (t *User) ChangeEmail(email string) error {
+	emailVO, err := valueobject.NewEmail(email)
+	if err != nil {
+		return err
+	}
+
+	if emailVO.String() != t.Email {
+		// O novo endereço precisa ser verificado de novo.
+		t.VerifiedAt = nil
+		t.VerificationSentAt = nil
+	}
+	t.Email = emailVO.String()
+	t.touch()
+	return nil
+}
+
+func (t *User) Verified() bool { return t.VerifiedAt != nil }
+
+// Verify confirma o email do usuário.
+func (t *User) Verify() error {
+	if t.Verified() {
+		return ErrEmailAlreadyVerified
+	}
+	now := time.Now()
+	t.VerifiedAt = &now
+	t.UpdatedAt = &now
+	return nil
+}
+
+// NextVerificationAt é o primeiro instante em que um novo email de
+// verificação pode sair, dado o intervalo mínimo entre envios.
+func (t *User) NextVerificationAt(interval time.Duration) time.Time {
+	if t.VerificationSentAt == nil {
+		return time.Time{}
+	}
+	return t.VerificationSentAt.Add(interval)
+}
+
+func (t *User) MarkVerificationSent(at time.Time) {
+	t.VerificationSentAt = &at
+}
+
 func (t *User) Delete() {
 	now := time.Now()
 	t.UpdatedAt = &now
 	t.DeletedAt = &now
 }
+
+func (t *User) touch() {
+	now := time.Now()
+	t.UpdatedAt = &now
+}
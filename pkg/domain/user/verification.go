@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidVerificationToken = errors.New("invalid verification token")
+	ErrVerificationTokenExpired = errors.New("verification token expired")
+	ErrEmailAlreadyVerified     = errors.New("email already verified")
+)
+
+// Verifier emite e confere os tokens de verificação de email. O token leva o
+// ID do usuário, o email verificado e a expiração, assinados com HMAC-SHA256;
+// trocar o email invalida os tokens emitidos para o anterior.
+type Verifier struct {
+	secret []byte
+	ttl    time.Duration
+}
+
+func NewVerifier(secret []byte, ttl time.Duration) *Verifier {
+	return &Verifier{secret: secret, ttl: ttl}
+}
+
+// VerificationClaims é o conteúdo de um token válido.
+type VerificationClaims struct {
+	UserID    string
+	Email     string
+	ExpiresAt time.Time
+}
+
+func (v *Verifier) Issue(user *User, now time.Time) string {
+	payload := strings.Join([]string{user.ID, user.Email, strconv.FormatInt(now.Add(v.ttl).Unix(), 10)}, "\n")
+	encoded := base64.RawURLEncoding.EncodeToString([]byte(payload))
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(v.sign(encoded))
+}
+
+func (v *Verifier) Parse(token string, now time.Time) (*VerificationClaims, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, ErrInvalidVerificationToken
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(mac, v.sign(encoded)) {
+		return nil, ErrInvalidVerificationToken
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidVerificationToken
+	}
+	parts := strings.Split(string(payload), "\n")
+	if len(parts) != 3 {
+		return nil, ErrInvalidVerificationToken
+	}
+	expiresAt, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return nil, ErrInvalidVerificationToken
+	}
+
+	claims := &VerificationClaims{UserID: parts[0], Email: parts[1], ExpiresAt: time.Unix(expiresAt, 0)}
+	if !now.Before(claims.ExpiresAt) {
+		return nil, ErrVerificationTokenExpired
+	}
+	return claims, nil
+}
+
+func (v *Verifier) sign(encoded string) []byte {
+	mac := hmac.New(sha256.New, v.secret)
+	mac.Write([]byte(encoded))
+	return mac.Sum(nil)
+}
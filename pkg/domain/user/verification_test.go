@@ -0,0 +1,109 @@
+package domain
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVerifier(t *testing.T) {
+	verifier := NewVerifier([]byte("secret"), time.Hour)
+	user := &User{ID: "u1", Email: "ada@example.com"}
+	issuedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	token := verifier.Issue(user, issuedAt)
+
+	// forge assina um payload arbitrário com o segredo do verifier.
+	forge := func(payload string) string {
+		encoded := base64.RawURLEncoding.EncodeToString([]byte(payload))
+		return encoded + "." + base64.RawURLEncoding.EncodeToString(verifier.sign(encoded))
+	}
+	encoded, signature, _ := strings.Cut(token, ".")
+	otherEmail := verifier.Issue(&User{ID: "u1", Email: "bob@example.com"}, issuedAt)
+	otherPayload, _, _ := strings.Cut(otherEmail, ".")
+
+	tests := []struct {
+		name     string
+		verifier *Verifier
+		token    string
+		now      time.Time
+		wantErr  error
+	}{
+		{"valid", verifier, token, issuedAt, nil},
+		{"just before expiry", verifier, token, issuedAt.Add(time.Hour - time.Second), nil},
+		{"at expiry", verifier, token, issuedAt.Add(time.Hour), ErrVerificationTokenExpired},
+		{"after expiry", verifier, token, issuedAt.Add(2 * time.Hour), ErrVerificationTokenExpired},
+		{"another secret", NewVerifier([]byte("other"), time.Hour), token, issuedAt, ErrInvalidVerificationToken},
+		{"payload swapped", verifier, otherPayload + "." + signature, issuedAt, ErrInvalidVerificationToken},
+		{"signature truncated", verifier, encoded + "." + signature[:10], issuedAt, ErrInvalidVerificationToken},
+		{"no signature", verifier, encoded, issuedAt, ErrInvalidVerificationToken},
+		{"signature not base64", verifier, encoded + ".***", issuedAt, ErrInvalidVerificationToken},
+		{"empty", verifier, "", issuedAt, ErrInvalidVerificationToken},
+		{"signed, missing expiry", verifier, forge("u1\nada@example.com"), issuedAt, ErrInvalidVerificationToken},
+		{"signed, expiry not a number", verifier, forge("u1\nada@example.com\nsoon"), issuedAt, ErrInvalidVerificationToken},
+		{"signed, payload not base64", verifier, "***." + base64.RawURLEncoding.EncodeToString(verifier.sign("***")), issuedAt, ErrInvalidVerificationToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := tt.verifier.Parse(tt.token, tt.now)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			want := VerificationClaims{UserID: "u1", Email: "ada@example.com", ExpiresAt: issuedAt.Add(time.Hour)}
+			if claims.UserID != want.UserID || claims.Email != want.Email || !claims.ExpiresAt.Equal(want.ExpiresAt) {
+				t.Errorf("claims = %+v, want %+v", claims, want)
+			}
+		})
+	}
+}
+
+func TestChangeEmailResetsVerification(t *testing.T) {
+	user, err := NewUser("Ada", "ada@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	user.MarkVerificationSent(time.Now())
+	if err := user.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if err := user.Verify(); !errors.Is(err, ErrEmailAlreadyVerified) {
+		t.Errorf("second Verify: error = %v, want %v", err, ErrEmailAlreadyVerified)
+	}
+
+	if err := user.ChangeEmail("ADA@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if !user.Verified() {
+		t.Error("the same address in another case reset verification")
+	}
+	if err := user.ChangeEmail("bob@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if user.Verified() || user.VerificationSentAt != nil {
+		t.Errorf("after changing the address VerifiedAt = %v, VerificationSentAt = %v; want both nil", user.VerifiedAt, user.VerificationSentAt)
+	}
+}
+
+func TestNextVerificationAt(t *testing.T) {
+	sentAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		sentAt *time.Time
+		want   time.Time
+	}{
+		{"never sent", nil, time.Time{}},
+		{"sent", &sentAt, sentAt.Add(time.Minute)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := &User{VerificationSentAt: tt.sentAt}
+			if got := user.NextVerificationAt(time.Minute); !got.Equal(tt.want) {
+				t.Errorf("NextVerificationAt = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package valueobject
+
+import (
+	"errors"
+	"strings"
+)
+
+type CommentBody struct {
+	value string
+}
+
+var (
+	ErrEmptyComment   = errors.New("comment cannot be empty")
+	ErrCommentTooLong = errors.New("comment exceeds 10000 characters")
+)
+
+// NewCommentBody aceita markdown; só os espaços das pontas são removidos,
+// para preservar quebras de linha e indentação.
+func NewCommentBody(raw string) (CommentBody, error) {
+	body := strings.TrimSpace(raw)
+
+	if len(body) == 0 {
+		return CommentBody{}, ErrEmptyComment
+	}
+	if len([]rune(body)) > 10000 {
+		return CommentBody{}, ErrCommentTooLong
+	}
+
+	return CommentBody{value: body}, nil
+}
+
+func (b CommentBody) String() string {
+	return b.value
+}
@@ -0,0 +1,36 @@
+package valueobject
+
+import (
+	"errors"
+	"path"
+	"strings"
+	"unicode"
+)
+
+type FileName struct {
+	value string
+}
+
+var ErrInvalidFileName = errors.New("file name must have between 1 and 255 characters")
+
+// NewFileName guarda só o último elemento do caminho enviado pelo cliente,
+// sem caracteres de controle.
+func NewFileName(raw string) (FileName, error) {
+	name := path.Base(strings.ReplaceAll(raw, `\`, "/"))
+	name = strings.TrimSpace(strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, name))
+
+	if name == "" || name == "." || name == "/" || len([]rune(name)) > 255 {
+		return FileName{}, ErrInvalidFileName
+	}
+
+	return FileName{value: name}, nil
+}
+
+func (n FileName) String() string {
+	return n.value
+}
@@ -0,0 +1,23 @@
+package valueobject
+
+import "errors"
+
+// NotificationChannel é um meio de entrega de notificações.
+type NotificationChannel string
+
+const (
+	ChannelEmail   NotificationChannel = "email"
+	ChannelWebhook NotificationChannel = "webhook"
+	ChannelInApp   NotificationChannel = "in_app"
+)
+
+var ErrInvalidNotificationChannel = errors.New("channel must be one of email, webhook, in_app")
+
+func NewNotificationChannel(raw string) (NotificationChannel, error) {
+	switch channel := NotificationChannel(raw); channel {
+	case ChannelEmail, ChannelWebhook, ChannelInApp:
+		return channel, nil
+	default:
+		return "", ErrInvalidNotificationChannel
+	}
+}
@@ -1,5 +1,7 @@
 package valueobject
 
+import "errors"
+
 type Priority int
 
 const (
@@ -7,3 +9,13 @@ const (
 	Medium
 	High
 )
+
+var ErrInvalidPriority = errors.New("priority must be between 1 and 3")
+
+func NewPriority(raw int) (Priority, error) {
+	priority := Priority(raw)
+	if priority < Low || priority > High {
+		return 0, ErrInvalidPriority
+	}
+	return priority, nil
+}
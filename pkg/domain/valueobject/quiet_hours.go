@@ -0,0 +1,98 @@
+package valueobject
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrInvalidQuietHours = errors.New("quiet hours need distinct start and end as HH:MM and a valid IANA timezone")
+
+// QuietHours é o intervalo diário em que o usuário não quer ser avisado, no
+// fuso dele. Start e End são minutos desde a meia-noite; quando End é menor
+// que Start o intervalo atravessa a meia-noite. O valor zero não silencia
+// nada.
+type QuietHours struct {
+	Start    int
+	End      int
+	Timezone string
+	location *time.Location
+}
+
+// NewQuietHours recebe os limites em minutos desde a meia-noite. timezone
+// vazio é UTC.
+func NewQuietHours(start, end int, timezone string) (QuietHours, error) {
+	if start < 0 || start >= 24*60 || end < 0 || end >= 24*60 || start == end {
+		return QuietHours{}, ErrInvalidQuietHours
+	}
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return QuietHours{}, ErrInvalidQuietHours
+	}
+	return QuietHours{Start: start, End: end, Timezone: timezone, location: location}, nil
+}
+
+// ParseQuietHours lê os limites no formato HH:MM.
+func ParseQuietHours(start, end, timezone string) (QuietHours, error) {
+	startMinutes, err := parseClock(start)
+	if err != nil {
+		return QuietHours{}, err
+	}
+	endMinutes, err := parseClock(end)
+	if err != nil {
+		return QuietHours{}, err
+	}
+	return NewQuietHours(startMinutes, endMinutes, timezone)
+}
+
+func parseClock(raw string) (int, error) {
+	clock, err := time.Parse("15:04", raw)
+	if err != nil {
+		return 0, ErrInvalidQuietHours
+	}
+	return clock.Hour()*60 + clock.Minute(), nil
+}
+
+func (q QuietHours) IsZero() bool { return q.location == nil }
+
+// StartClock e EndClock devolvem os limites no formato HH:MM.
+func (q QuietHours) StartClock() string { return formatClock(q.Start) }
+func (q QuietHours) EndClock() string   { return formatClock(q.End) }
+
+func formatClock(minutes int) string {
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
+
+// Contains informa se t cai no intervalo de silêncio.
+func (q QuietHours) Contains(t time.Time) bool {
+	if q.IsZero() {
+		return false
+	}
+	local := t.In(q.location)
+	minute := local.Hour()*60 + local.Minute()
+	if q.Start < q.End {
+		return minute >= q.Start && minute < q.End
+	}
+	return minute >= q.Start || minute < q.End
+}
+
+// EndAfter devolve o primeiro fim do intervalo de silêncio depois de t.
+func (q QuietHours) EndAfter(t time.Time) time.Time {
+	local := t.In(q.location)
+	end := time.Date(local.Year(), local.Month(), local.Day(), q.End/60, q.End%60, 0, 0, q.location)
+	if !end.After(local) {
+		end = end.AddDate(0, 0, 1)
+	}
+	return end.UTC()
+}
+
+// Location devolve o fuso do intervalo, ou UTC no valor zero.
+func (q QuietHours) Location() *time.Location {
+	if q.IsZero() {
+		return time.UTC
+	}
+	return q.location
+}
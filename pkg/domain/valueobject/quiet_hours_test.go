@@ -0,0 +1,87 @@
+package valueobject
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseQuietHours(t *testing.T) {
+	tests := []struct {
+		start, end, timezone string
+		wantErr              bool
+	}{
+		{"22:00", "07:00", "America/Sao_Paulo", false},
+		{"09:30", "12:00", "", false},
+		{"22:00", "22:00", "UTC", true},
+		{"24:00", "07:00", "UTC", true},
+		{"10pm", "07:00", "UTC", true},
+		{"22:00", "07:00", "Mars/Olympus", true},
+	}
+	for _, tt := range tests {
+		q, err := ParseQuietHours(tt.start, tt.end, tt.timezone)
+		if tt.wantErr {
+			if !errors.Is(err, ErrInvalidQuietHours) {
+				t.Errorf("ParseQuietHours(%q, %q, %q) error = %v, want ErrInvalidQuietHours", tt.start, tt.end, tt.timezone, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseQuietHours(%q, %q, %q) error = %v", tt.start, tt.end, tt.timezone, err)
+			continue
+		}
+		if q.StartClock() != tt.start || q.EndClock() != tt.end {
+			t.Errorf("clocks = %s-%s, want %s-%s", q.StartClock(), q.EndClock(), tt.start, tt.end)
+		}
+	}
+}
+
+func TestQuietHours(t *testing.T) {
+	location, err := time.LoadLocation("America/Sao_Paulo")
+	if err != nil {
+		t.Skip("timezone database unavailable:", err)
+	}
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, time.March, day, hour, minute, 0, 0, location)
+	}
+
+	overnight, err := ParseQuietHours("22:00", "07:00", "America/Sao_Paulo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	daytime, err := ParseQuietHours("12:00", "13:30", "America/Sao_Paulo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		quiet    QuietHours
+		t        time.Time
+		contains bool
+		endAfter time.Time
+	}{
+		{"overnight before start", overnight, at(10, 21, 59), false, at(11, 7, 0)},
+		{"overnight at start", overnight, at(10, 22, 0), true, at(11, 7, 0)},
+		{"overnight after midnight", overnight, at(11, 3, 0), true, at(11, 7, 0)},
+		{"overnight at end", overnight, at(11, 7, 0), false, at(12, 7, 0)},
+		{"daytime inside", daytime, at(10, 12, 45), true, at(10, 13, 30)},
+		{"daytime after", daytime, at(10, 14, 0), false, at(11, 13, 30)},
+		{"other timezone is converted", overnight, time.Date(2026, time.March, 11, 2, 0, 0, 0, time.UTC), true, at(11, 7, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.quiet.Contains(tt.t); got != tt.contains {
+				t.Errorf("Contains(%s) = %v, want %v", tt.t, got, tt.contains)
+			}
+			if got := tt.quiet.EndAfter(tt.t); !got.Equal(tt.endAfter) {
+				t.Errorf("EndAfter(%s) = %s, want %s", tt.t, got, tt.endAfter)
+			}
+		})
+	}
+
+	var zero QuietHours
+	if zero.Contains(at(10, 23, 0)) || zero.Location() != time.UTC {
+		t.Error("the zero value should silence nothing and use UTC")
+	}
+}
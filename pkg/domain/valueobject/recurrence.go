@@ -0,0 +1,80 @@
+package valueobject
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+	Yearly  Frequency = "YEARLY"
+)
+
+var ErrInvalidRecurrence = errors.New("recurrence must be FREQ=DAILY|WEEKLY|MONTHLY|YEARLY with an optional INTERVAL between 1 and 365")
+
+// Recurrence é um subconjunto da RRULE do iCalendar (RFC 5545): frequência
+// e intervalo. O valor zero significa "não se repete".
+type Recurrence struct {
+	Frequency Frequency
+	Interval  int
+}
+
+func NewRecurrence(frequency Frequency, interval int) (Recurrence, error) {
+	switch frequency {
+	case Daily, Weekly, Monthly, Yearly:
+	default:
+		return Recurrence{}, ErrInvalidRecurrence
+	}
+	if interval < 1 || interval > 365 {
+		return Recurrence{}, ErrInvalidRecurrence
+	}
+	return Recurrence{Frequency: frequency, Interval: interval}, nil
+}
+
+// ParseRecurrence lê a forma textual gerada por String; vazio é o valor
+// zero.
+func ParseRecurrence(rule string) (Recurrence, error) {
+	if rule == "" {
+		return Recurrence{}, nil
+	}
+
+	var frequency Frequency
+	interval := 1
+	for _, part := range strings.Split(strings.ToUpper(rule), ";") {
+		key, value, _ := strings.Cut(part, "=")
+		switch key {
+		case "FREQ":
+			frequency = Frequency(value)
+		case "INTERVAL":
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return Recurrence{}, ErrInvalidRecurrence
+			}
+			interval = n
+		default:
+			return Recurrence{}, ErrInvalidRecurrence
+		}
+	}
+	return NewRecurrence(frequency, interval)
+}
+
+func (r Recurrence) IsZero() bool {
+	return r.Frequency == ""
+}
+
+// String devolve a regra no formato RRULE (ex: "FREQ=WEEKLY;INTERVAL=2").
+func (r Recurrence) String() string {
+	if r.IsZero() {
+		return ""
+	}
+	if r.Interval <= 1 {
+		return "FREQ=" + string(r.Frequency)
+	}
+	return fmt.Sprintf("FREQ=%s;INTERVAL=%d", r.Frequency, r.Interval)
+}
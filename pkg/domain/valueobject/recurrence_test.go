@@ -0,0 +1,38 @@
+package valueobject
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseRecurrence(t *testing.T) {
+	tests := []struct {
+		rule    string
+		want    Recurrence
+		wantErr error
+		str     string
+	}{
+		{"", Recurrence{}, nil, ""},
+		{"FREQ=DAILY", Recurrence{Daily, 1}, nil, "FREQ=DAILY"},
+		{"freq=weekly;interval=2", Recurrence{Weekly, 2}, nil, "FREQ=WEEKLY;INTERVAL=2"},
+		{"INTERVAL=3;FREQ=MONTHLY", Recurrence{Monthly, 3}, nil, "FREQ=MONTHLY;INTERVAL=3"},
+		{"FREQ=YEARLY;INTERVAL=1", Recurrence{Yearly, 1}, nil, "FREQ=YEARLY"},
+		{"FREQ=YEARLY;INTERVAL=365", Recurrence{Yearly, 365}, nil, "FREQ=YEARLY;INTERVAL=365"},
+		{"FREQ=HOURLY", Recurrence{}, ErrInvalidRecurrence, ""},
+		{"INTERVAL=2", Recurrence{}, ErrInvalidRecurrence, ""},
+		{"FREQ=DAILY;INTERVAL=0", Recurrence{}, ErrInvalidRecurrence, ""},
+		{"FREQ=DAILY;INTERVAL=366", Recurrence{}, ErrInvalidRecurrence, ""},
+		{"FREQ=DAILY;INTERVAL=two", Recurrence{}, ErrInvalidRecurrence, ""},
+		{"FREQ=DAILY;BYDAY=MO", Recurrence{}, ErrInvalidRecurrence, ""},
+	}
+	for _, tt := range tests {
+		got, err := ParseRecurrence(tt.rule)
+		if got != tt.want || !errors.Is(err, tt.wantErr) {
+			t.Errorf("ParseRecurrence(%q) = %+v, %v; want %+v, %v", tt.rule, got, err, tt.want, tt.wantErr)
+			continue
+		}
+		if got.String() != tt.str {
+			t.Errorf("ParseRecurrence(%q).String() = %q, want %q", tt.rule, got.String(), tt.str)
+		}
+	}
+}
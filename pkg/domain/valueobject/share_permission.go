@@ -0,0 +1,22 @@
+package valueobject
+
+import "errors"
+
+// SharePermission é o que um compartilhamento permite fazer na tarefa.
+type SharePermission string
+
+const (
+	SharePermissionView SharePermission = "view"
+	SharePermissionEdit SharePermission = "edit"
+)
+
+var ErrInvalidSharePermission = errors.New("permission must be one of view, edit")
+
+func NewSharePermission(raw string) (SharePermission, error) {
+	switch permission := SharePermission(raw); permission {
+	case SharePermissionView, SharePermissionEdit:
+		return permission, nil
+	default:
+		return "", ErrInvalidSharePermission
+	}
+}
@@ -1,5 +1,7 @@
 package valueobject
 
+import "errors"
+
 type Status string
 
 const (
@@ -7,3 +9,14 @@ const (
 	StatusInProgress Status = "in_progress"
 	StatusCompleted  Status = "completed"
 )
+
+var ErrInvalidStatus = errors.New("status must be one of new, in_progress, completed")
+
+func NewStatus(raw string) (Status, error) {
+	switch status := Status(raw); status {
+	case StatusNew, StatusInProgress, StatusCompleted:
+		return status, nil
+	default:
+		return "", ErrInvalidStatus
+	}
+}
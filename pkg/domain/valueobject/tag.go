@@ -0,0 +1,26 @@
+package valueobject
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type Tag string
+
+var ErrInvalidTag = errors.New("tag must have 1 to 32 letters, digits, '-' or '_'")
+
+// NewTag normaliza a tag para minúsculas, sem o "#" inicial.
+func NewTag(raw string) (Tag, error) {
+	tag := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(raw), "#"))
+	if tag == "" || utf8.RuneCountInString(tag) > 32 {
+		return "", ErrInvalidTag
+	}
+	for _, r := range tag {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '_' {
+			return "", ErrInvalidTag
+		}
+	}
+	return Tag(tag), nil
+}
@@ -0,0 +1,26 @@
+package valueobject
+
+import (
+	"errors"
+	"strings"
+)
+
+type UserName struct {
+	value string
+}
+
+var ErrInvalidUserName = errors.New("name must have between 3 and 100 characters")
+
+func NewUserName(raw string) (UserName, error) {
+	name := strings.Join(strings.Fields(raw), " ")
+
+	if n := len([]rune(name)); n < 3 || n > 100 {
+		return UserName{}, ErrInvalidUserName
+	}
+
+	return UserName{value: name}, nil
+}
+
+func (n UserName) String() string {
+	return n.value
+}
@@ -0,0 +1,26 @@
+package valueobject
+
+import (
+	"errors"
+	"strings"
+)
+
+type WorkspaceName struct {
+	value string
+}
+
+var ErrInvalidWorkspaceName = errors.New("workspace name must have between 2 and 100 characters")
+
+func NewWorkspaceName(raw string) (WorkspaceName, error) {
+	name := strings.Join(strings.Fields(raw), " ")
+
+	if n := len([]rune(name)); n < 2 || n > 100 {
+		return WorkspaceName{}, ErrInvalidWorkspaceName
+	}
+
+	return WorkspaceName{value: name}, nil
+}
+
+func (n WorkspaceName) String() string {
+	return n.value
+}
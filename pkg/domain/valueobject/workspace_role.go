@@ -0,0 +1,24 @@
+package valueobject
+
+import "errors"
+
+// WorkspaceRole é o papel de um membro no workspace.
+type WorkspaceRole string
+
+const (
+	RoleOwner  WorkspaceRole = "owner"
+	RoleAdmin  WorkspaceRole = "admin"
+	RoleMember WorkspaceRole = "member"
+	RoleGuest  WorkspaceRole = "guest"
+)
+
+var ErrInvalidWorkspaceRole = errors.New("role must be one of owner, admin, member, guest")
+
+func NewWorkspaceRole(raw string) (WorkspaceRole, error) {
+	switch role := WorkspaceRole(raw); role {
+	case RoleOwner, RoleAdmin, RoleMember, RoleGuest:
+		return role, nil
+	default:
+		return "", ErrInvalidWorkspaceRole
+	}
+}
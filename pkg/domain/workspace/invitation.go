@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hoyci/todo-ddd/pkg/domain/valueobject"
+)
+
+// Invitation convida um email para o workspace com um papel. Quem aceita
+// precisa ter uma conta com o mesmo email e apresentar o Token.
+type Invitation struct {
+	ID          string
+	WorkspaceID string
+	Email       string
+	Role        valueobject.WorkspaceRole
+	// Token não entra em representações JSON, como as do log de auditoria.
+	Token      string `json:"-"`
+	InvitedBy  string
+	CreatedAt  time.Time
+	ExpiresAt  time.Time
+	AcceptedAt *time.Time
+}
+
+func NewInvitation(workspaceID, email, role, invitedBy string, ttl time.Duration) (*Invitation, error) {
+	emailVO, err := valueobject.NewEmail(email)
+	if err != nil {
+		return nil, err
+	}
+	roleVO, err := valueobject.NewWorkspaceRole(role)
+	if err != nil {
+		return nil, err
+	}
+
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	return &Invitation{
+		ID:          uuid.New().String(),
+		WorkspaceID: workspaceID,
+		Email:       emailVO.String(),
+		Role:        roleVO,
+		Token:       hex.EncodeToString(token),
+		InvitedBy:   invitedBy,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(ttl),
+		AcceptedAt:  nil,
+	}, nil
+}
+
+// Pending indica se o convite ainda pode ser aceito.
+func (i *Invitation) Pending(now time.Time) bool {
+	return i.AcceptedAt == nil && now.Before(i.ExpiresAt)
+}
+
+func (i *Invitation) Accept() {
+	now := time.Now()
+	i.AcceptedAt = &now
+}
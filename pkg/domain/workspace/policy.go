@@ -0,0 +1,49 @@
+package domain
+
+import "github.com/hoyci/todo-ddd/pkg/domain/valueobject"
+
+// Action é uma operação sujeita à política de papéis do workspace.
+type Action string
+
+const (
+	ActionViewTasks       Action = "tasks.view"
+	ActionCreateTask      Action = "tasks.create"
+	ActionEditAnyTask     Action = "tasks.edit_any"
+	ActionManageAnyTask   Action = "tasks.manage_any"
+	ActionManageMembers   Action = "members.manage"
+	ActionGrantAdmin      Action = "members.grant_admin"
+	ActionRenameWorkspace Action = "workspace.rename"
+	ActionDeleteWorkspace Action = "workspace.delete"
+)
+
+// policy lista o que cada papel pode fazer. Guest só lê; member cria e
+// edita tarefas; admin também exclui tarefas e gerencia membros; só o
+// owner concede admin e exclui o workspace.
+var policy = map[valueobject.WorkspaceRole][]Action{
+	valueobject.RoleOwner: {
+		ActionViewTasks, ActionCreateTask, ActionEditAnyTask, ActionManageAnyTask,
+		ActionManageMembers, ActionGrantAdmin, ActionRenameWorkspace, ActionDeleteWorkspace,
+	},
+	valueobject.RoleAdmin: {
+		ActionViewTasks, ActionCreateTask, ActionEditAnyTask, ActionManageAnyTask,
+		ActionManageMembers, ActionRenameWorkspace,
+	},
+	valueobject.RoleMember: {ActionViewTasks, ActionCreateTask, ActionEditAnyTask},
+	valueobject.RoleGuest:  {ActionViewTasks},
+}
+
+// Can informa se o papel permite a ação.
+func Can(role valueobject.WorkspaceRole, action Action) bool {
+	for _, allowed := range policy[role] {
+		if allowed == action {
+			return true
+		}
+	}
+	return false
+}
+
+// Can informa se o membro pode executar a ação. Um membro nil (quem não
+// participa do workspace) não pode nada.
+func (m *Member) Can(action Action) bool {
+	return m != nil && Can(m.Role, action)
+}
@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hoyci/todo-ddd/pkg/domain/valueobject"
+)
+
+func TestCan(t *testing.T) {
+	tests := []struct {
+		action Action
+		// allowed são os papéis, do mais ao menos poderoso, que podem a ação.
+		allowed []valueobject.WorkspaceRole
+	}{
+		{ActionViewTasks, []valueobject.WorkspaceRole{valueobject.RoleOwner, valueobject.RoleAdmin, valueobject.RoleMember, valueobject.RoleGuest}},
+		{ActionCreateTask, []valueobject.WorkspaceRole{valueobject.RoleOwner, valueobject.RoleAdmin, valueobject.RoleMember}},
+		{ActionEditAnyTask, []valueobject.WorkspaceRole{valueobject.RoleOwner, valueobject.RoleAdmin, valueobject.RoleMember}},
+		{ActionManageAnyTask, []valueobject.WorkspaceRole{valueobject.RoleOwner, valueobject.RoleAdmin}},
+		{ActionManageMembers, []valueobject.WorkspaceRole{valueobject.RoleOwner, valueobject.RoleAdmin}},
+		{ActionRenameWorkspace, []valueobject.WorkspaceRole{valueobject.RoleOwner, valueobject.RoleAdmin}},
+		{ActionGrantAdmin, []valueobject.WorkspaceRole{valueobject.RoleOwner}},
+		{ActionDeleteWorkspace, []valueobject.WorkspaceRole{valueobject.RoleOwner}},
+	}
+	roles := []valueobject.WorkspaceRole{valueobject.RoleOwner, valueobject.RoleAdmin, valueobject.RoleMember, valueobject.RoleGuest}
+	for _, tt := range tests {
+		for _, role := range roles {
+			want := false
+			for _, allowed := range tt.allowed {
+				want = want || allowed == role
+			}
+			if got := (&Member{Role: role}).Can(tt.action); got != want {
+				t.Errorf("%s can %s = %v, want %v", role, tt.action, got, want)
+			}
+		}
+		var outsider *Member
+		if outsider.Can(tt.action) {
+			t.Errorf("a nil member can %s", tt.action)
+		}
+	}
+}
+
+func TestInvitationPending(t *testing.T) {
+	invitation, err := NewInvitation("w1", "Ada@Example.com", "member", "u1", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(invitation.Token) != 64 {
+		t.Errorf("token %q is not 32 random bytes in hex", invitation.Token)
+	}
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want bool
+	}{
+		{"just created", invitation.CreatedAt, true},
+		{"before expiry", invitation.ExpiresAt.Add(-time.Second), true},
+		{"at expiry", invitation.ExpiresAt, false},
+		{"after expiry", invitation.ExpiresAt.Add(time.Minute), false},
+	}
+	for _, tt := range tests {
+		if got := invitation.Pending(tt.now); got != tt.want {
+			t.Errorf("%s: Pending = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	invitation.Accept()
+	if invitation.Pending(invitation.CreatedAt) {
+		t.Error("an accepted invitation is still pending")
+	}
+}
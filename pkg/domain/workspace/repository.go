@@ -0,0 +1,28 @@
+package domain
+
+import "context"
+
+type WorkspaceRepository interface {
+	Save(ctx context.Context, workspace *Workspace) error
+	Update(ctx context.Context, workspace *Workspace) error
+	// FindByID ignora workspaces excluídos.
+	FindByID(ctx context.Context, id string) (*Workspace, error)
+	// ListByMember devolve os workspaces ativos de que o usuário participa.
+	ListByMember(ctx context.Context, userID string) ([]*Workspace, error)
+
+	// SaveMember cria a participação ou troca o papel de uma existente.
+	SaveMember(ctx context.Context, member *Member) error
+	FindMember(ctx context.Context, workspaceID, userID string) (*Member, error)
+	ListMembers(ctx context.Context, workspaceID string) ([]*Member, error)
+	RemoveMember(ctx context.Context, workspaceID, userID string) error
+}
+
+type InvitationRepository interface {
+	Save(ctx context.Context, invitation *Invitation) error
+	Update(ctx context.Context, invitation *Invitation) error
+	FindByToken(ctx context.Context, token string) (*Invitation, error)
+	FindByID(ctx context.Context, workspaceID, id string) (*Invitation, error)
+	// ListPending devolve os convites não aceitos e ainda válidos.
+	ListPending(ctx context.Context, workspaceID string) ([]*Invitation, error)
+	Delete(ctx context.Context, id string) error
+}
@@ -0,0 +1,18 @@
+package domain
+
+import "context"
+
+type scopeContextKey struct{}
+
+// WithScope define o workspace em que as operações do contexto acontecem.
+// Vazio é o espaço pessoal do usuário, fora de qualquer workspace.
+func WithScope(ctx context.Context, workspaceID string) context.Context {
+	return context.WithValue(ctx, scopeContextKey{}, workspaceID)
+}
+
+// ScopeFrom devolve o workspace do contexto, ou vazio para o espaço pessoal.
+// Os repositórios de tarefas filtram todas as consultas por ele.
+func ScopeFrom(ctx context.Context) string {
+	workspaceID, _ := ctx.Value(scopeContextKey{}).(string)
+	return workspaceID
+}
@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hoyci/todo-ddd/pkg/domain/valueobject"
+)
+
+// Workspace agrupa as tarefas de um time. Cada workspace tem exatamente um
+// membro com papel owner.
+type Workspace struct {
+	ID        string
+	Name      string
+	OwnerID   string
+	CreatedAt time.Time
+	UpdatedAt *time.Time
+	DeletedAt *time.Time
+}
+
+// Member é a participação de um usuário em um workspace.
+type Member struct {
+	WorkspaceID string
+	UserID      string
+	Role        valueobject.WorkspaceRole
+	JoinedAt    time.Time
+}
+
+// NewWorkspace cria o workspace e a participação do dono.
+func NewWorkspace(name, ownerID string) (*Workspace, *Member, error) {
+	nameVO, err := valueobject.NewWorkspaceName(name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	workspace := &Workspace{
+		ID:        uuid.New().String(),
+		Name:      nameVO.String(),
+		OwnerID:   ownerID,
+		CreatedAt: now,
+		UpdatedAt: nil,
+		DeletedAt: nil,
+	}
+	owner := &Member{WorkspaceID: workspace.ID, UserID: ownerID, Role: valueobject.RoleOwner, JoinedAt: now}
+	return workspace, owner, nil
+}
+
+func (w *Workspace) Rename(name string) error {
+	nameVO, err := valueobject.NewWorkspaceName(name)
+	if err != nil {
+		return err
+	}
+
+	w.Name = nameVO.String()
+	w.touch()
+	return nil
+}
+
+func (w *Workspace) touch() {
+	now := time.Now()
+	w.UpdatedAt = &now
+}
+
+func (w *Workspace) Delete() {
+	now := time.Now()
+	w.UpdatedAt = &now
+	w.DeletedAt = &now
+}
@@ -0,0 +1,95 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"unicode/utf8"
+)
+
+// packageLevelHandler aplica o nível configurado para o pacote informado em
+// logger.With(PackageKey, ...). Pacotes são hierárquicos por ponto: o nível
+// de "usecase" vale para "usecase.task" quando não houver um mais específico.
+type packageLevelHandler struct {
+	next   slog.Handler
+	level  slog.Level
+	config Config
+}
+
+func (h *packageLevelHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level
+}
+
+func (h *packageLevelHandler) Handle(ctx context.Context, r slog.Record) error {
+	return h.next.Handle(ctx, r)
+}
+
+func (h *packageLevelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	level := h.level
+	for _, attr := range attrs {
+		if attr.Key == PackageKey {
+			level = h.levelFor(attr.Value.String())
+		}
+	}
+
+	return &packageLevelHandler{
+		next:   h.next.WithAttrs(attrs),
+		level:  level,
+		config: h.config,
+	}
+}
+
+func (h *packageLevelHandler) WithGroup(name string) slog.Handler {
+	return &packageLevelHandler{
+		next:   h.next.WithGroup(name),
+		level:  h.level,
+		config: h.config,
+	}
+}
+
+func (h *packageLevelHandler) levelFor(pkg string) slog.Level {
+	for name := pkg; name != ""; {
+		if level, ok := h.config.PackageLevels[name]; ok {
+			return level
+		}
+
+		idx := strings.LastIndex(name, ".")
+		if idx < 0 {
+			break
+		}
+		name = name[:idx]
+	}
+	return h.config.Level
+}
+
+const redacted = "[REDACTED]"
+
+func redactAttr(keys []string) func(groups []string, a slog.Attr) slog.Attr {
+	set := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		set[strings.ToLower(key)] = struct{}{}
+	}
+
+	return func(_ []string, a slog.Attr) slog.Attr {
+		if _, ok := set[strings.ToLower(a.Key)]; !ok {
+			return a
+		}
+
+		if strings.EqualFold(a.Key, "email") {
+			return slog.String(a.Key, maskEmail(a.Value.String()))
+		}
+		return slog.String(a.Key, redacted)
+	}
+}
+
+// maskEmail mantém o domínio e a primeira letra, útil para depuração sem
+// expor o endereço completo: "alice@example.com" vira "a***@example.com".
+// A primeira letra é a primeira runa, para não cortar um caractere UTF-8.
+func maskEmail(email string) string {
+	local, domain, ok := strings.Cut(email, "@")
+	if !ok || local == "" {
+		return redacted
+	}
+	_, size := utf8.DecodeRuneInString(local)
+	return local[:size] + "***@" + domain
+}
@@ -0,0 +1,157 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestMaskEmail(t *testing.T) {
+	tests := []struct {
+		email string
+		want  string
+	}{
+		{"alice@example.com", "a***@example.com"},
+		{"élodie@example.com", "é***@example.com"},
+		{"日本@example.jp", "日***@example.jp"},
+		{"a@example.com", "a***@example.com"},
+		{"@example.com", redacted},
+		{"not an email", redacted},
+		{"", redacted},
+	}
+	for _, tt := range tests {
+		if got := maskEmail(tt.email); got != tt.want {
+			t.Errorf("maskEmail(%q) = %q, want %q", tt.email, got, tt.want)
+		}
+	}
+}
+
+// decode lê as linhas JSON escritas pelo logger.
+func decode(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var entries []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("invalid log line %q: %v", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestRedaction(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(Config{Format: FormatJSON, Level: slog.LevelInfo, RedactKeys: DefaultRedactKeys, Output: &buf})
+
+	logger.Info("signup",
+		"email", "élodie@example.com",
+		"token", "abc123",
+		"Password", "hunter2",
+		"AUTHORIZATION", "Bearer xyz",
+		"user_id", "u1",
+		slog.Group("request", "token", "nested"),
+	)
+	logger.With("token", "from With").Info("with attrs")
+
+	entries := decode(t, &buf)
+	if len(entries) != 2 {
+		t.Fatalf("got %d log lines, want 2", len(entries))
+	}
+	tests := []struct {
+		key  string
+		want any
+	}{
+		{"email", "é***@example.com"},
+		{"token", redacted},
+		{"Password", redacted},
+		{"AUTHORIZATION", redacted},
+		{"user_id", "u1"},
+		{"request", map[string]any{"token": redacted}},
+	}
+	for _, tt := range tests {
+		got, _ := json.Marshal(entries[0][tt.key])
+		want, _ := json.Marshal(tt.want)
+		if string(got) != string(want) {
+			t.Errorf("%s = %s, want %s", tt.key, got, want)
+		}
+	}
+	if entries[1]["token"] != redacted {
+		t.Errorf("token from With = %v, want %q", entries[1]["token"], redacted)
+	}
+}
+
+func TestPackageLevels(t *testing.T) {
+	levels, err := ParsePackageLevels(" sqlite=debug, usecase=warn ,usecase.task=error,")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	logger := New(Config{Format: FormatJSON, Level: slog.LevelInfo, PackageLevels: levels, Output: &buf})
+
+	tests := []struct {
+		pkg   string
+		level slog.Level
+		want  bool
+	}{
+		{"", slog.LevelDebug, false},
+		{"", slog.LevelInfo, true},
+		{"sqlite", slog.LevelDebug, true},
+		// Sem nível próprio, vale o do pacote pai.
+		{"sqlite.tx", slog.LevelDebug, true},
+		{"usecase", slog.LevelInfo, false},
+		{"usecase", slog.LevelWarn, true},
+		{"usecase.task", slog.LevelWarn, false},
+		{"usecase.task", slog.LevelError, true},
+		{"usecase.user", slog.LevelWarn, true},
+		{"api", slog.LevelDebug, false},
+		{"api", slog.LevelInfo, true},
+	}
+	for _, tt := range tests {
+		buf.Reset()
+		l := logger
+		if tt.pkg != "" {
+			l = ForPackage(WithLogger(context.Background(), logger), tt.pkg)
+		}
+		l.Log(context.Background(), tt.level, "message")
+		if got := buf.Len() > 0; got != tt.want {
+			t.Errorf("package %q at %s logged = %v, want %v", tt.pkg, tt.level, got, tt.want)
+		}
+	}
+}
+
+func TestParsePackageLevels(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    map[string]slog.Level
+		wantErr bool
+	}{
+		{"", map[string]slog.Level{}, false},
+		{"sqlite=debug", map[string]slog.Level{"sqlite": slog.LevelDebug}, false},
+		{" api = WARN , sqlite=error", map[string]slog.Level{"api": slog.LevelWarn, "sqlite": slog.LevelError}, false},
+		{"sqlite", nil, true},
+		{"sqlite=loud", nil, true},
+	}
+	for _, tt := range tests {
+		got, err := ParsePackageLevels(tt.raw)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParsePackageLevels(%q) error = %v, want error %v", tt.raw, err, tt.wantErr)
+			continue
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("ParsePackageLevels(%q) = %v, want %v", tt.raw, got, tt.want)
+			continue
+		}
+		for pkg, level := range tt.want {
+			if got[pkg] != level {
+				t.Errorf("ParsePackageLevels(%q)[%s] = %s, want %s", tt.raw, pkg, got[pkg], level)
+			}
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+
+	// PackageKey é o atributo usado para selecionar o nível de log por pacote:
+	// logger.With(logging.PackageKey, "sqlite").
+	PackageKey = "package"
+)
+
+type Config struct {
+	Format        string
+	Level         slog.Level
+	PackageLevels map[string]slog.Level
+	RedactKeys    []string
+	Output        io.Writer
+}
+
+// DefaultRedactKeys lista os atributos considerados PII ou segredos.
+var DefaultRedactKeys = []string{"email", "password", "token", "authorization"}
+
+// ConfigFromEnv lê LOG_FORMAT (json|text), LOG_LEVEL (debug|info|warn|error)
+// e LOG_LEVELS (ex: "sqlite=debug,usecase=warn").
+func ConfigFromEnv() (Config, error) {
+	cfg := Config{
+		Format:     FormatJSON,
+		Level:      slog.LevelInfo,
+		RedactKeys: DefaultRedactKeys,
+		Output:     os.Stdout,
+	}
+
+	if format := os.Getenv("LOG_FORMAT"); format != "" {
+		if format != FormatJSON && format != FormatText {
+			return Config{}, fmt.Errorf("invalid LOG_FORMAT %q", format)
+		}
+		cfg.Format = format
+	}
+
+	if level := os.Getenv("LOG_LEVEL"); level != "" {
+		if err := cfg.Level.UnmarshalText([]byte(level)); err != nil {
+			return Config{}, fmt.Errorf("invalid LOG_LEVEL: %w", err)
+		}
+	}
+
+	levels, err := ParsePackageLevels(os.Getenv("LOG_LEVELS"))
+	if err != nil {
+		return Config{}, err
+	}
+	cfg.PackageLevels = levels
+
+	return cfg, nil
+}
+
+func ParsePackageLevels(raw string) (map[string]slog.Level, error) {
+	levels := make(map[string]slog.Level)
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		pkg, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid package level %q, expected package=level", entry)
+		}
+
+		var level slog.Level
+		if err := level.UnmarshalText([]byte(strings.TrimSpace(value))); err != nil {
+			return nil, fmt.Errorf("invalid level for package %q: %w", pkg, err)
+		}
+		levels[strings.TrimSpace(pkg)] = level
+	}
+	return levels, nil
+}
+
+func New(cfg Config) *slog.Logger {
+	output := cfg.Output
+	if output == nil {
+		output = os.Stdout
+	}
+
+	// O handler base aceita tudo; o filtro por nível fica no packageLevelHandler.
+	minLevel := cfg.Level
+	for _, level := range cfg.PackageLevels {
+		minLevel = min(minLevel, level)
+	}
+
+	opts := &slog.HandlerOptions{
+		Level:       minLevel,
+		ReplaceAttr: redactAttr(cfg.RedactKeys),
+	}
+
+	var handler slog.Handler
+	if cfg.Format == FormatText {
+		handler = slog.NewTextHandler(output, opts)
+	} else {
+		handler = slog.NewJSONHandler(output, opts)
+	}
+
+	return slog.New(&packageLevelHandler{
+		next:   handler,
+		level:  cfg.Level,
+		config: cfg,
+	})
+}
+
+type ctxKey struct{}
+
+type requestIDKey struct{}
+
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, ctxKey{}, logger)
+}
+
+// FromContext devolve o logger da requisição ou, fora dela, o logger padrão.
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(ctxKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
+
+func ForPackage(ctx context.Context, pkg string) *slog.Logger {
+	return FromContext(ctx).With(PackageKey, pkg)
+}
+
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
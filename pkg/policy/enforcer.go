@@ -0,0 +1,62 @@
+package policy
+
+import (
+	"context"
+	"errors"
+	"slices"
+)
+
+var ErrDenied = errors.New("not allowed to perform this action")
+
+type subjectContextKey struct{}
+
+// WithSubject identifica no contexto o usuário que faz a requisição. Os
+// adaptadores de entrada (REST, gRPC, GraphQL) o preenchem.
+func WithSubject(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, subjectContextKey{}, userID)
+}
+
+// SubjectFrom devolve o usuário do contexto, ou vazio se anônimo.
+func SubjectFrom(ctx context.Context) string {
+	userID, _ := ctx.Value(subjectContextKey{}).(string)
+	return userID
+}
+
+// RoleStore guarda os papéis atribuídos explicitamente a cada usuário.
+type RoleStore interface {
+	Roles(ctx context.Context, userID string) ([]Role, error)
+	Grant(ctx context.Context, userID string, role Role) error
+	Revoke(ctx context.Context, userID string, role Role) error
+}
+
+// Enforcer monta o principal a partir do contexto e aplica a definição.
+type Enforcer struct {
+	Definition *Definition
+	Roles      RoleStore
+}
+
+// Principal devolve o principal do contexto. Todo usuário identificado tem
+// RoleUser além dos papéis gravados; o anônimo não tem papel algum.
+func (e *Enforcer) Principal(ctx context.Context) (Principal, error) {
+	userID := SubjectFrom(ctx)
+	if userID == "" {
+		return Principal{}, nil
+	}
+
+	roles, err := e.Roles.Roles(ctx, userID)
+	if err != nil {
+		return Principal{}, err
+	}
+	if !slices.Contains(roles, RoleUser) {
+		roles = append(roles, RoleUser)
+	}
+	return Principal{UserID: userID, Roles: roles}, nil
+}
+
+// Authorize devolve ErrDenied se a definição não permitir a ação.
+func (e *Enforcer) Authorize(ctx context.Context, principal Principal, action Action, resource Resource) error {
+	if !e.Definition.Can(principal, action, resource) {
+		return ErrDenied
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package policy
+
+import "context"
+
+// UseCase é a forma dos casos de uso com entrada e saída.
+type UseCase[I, O any] interface {
+	Execute(ctx context.Context, input I) (O, error)
+}
+
+// Command é a forma dos casos de uso sem saída.
+type Command[I any] interface {
+	Execute(ctx context.Context, input I) error
+}
+
+// ResourceFunc resolve o recurso alvo da entrada e as relações do
+// principal com ele. Pode devolver o erro de recurso inexistente do próprio
+// caso de uso.
+type ResourceFunc[I any] func(ctx context.Context, principal Principal, input I) (Resource, error)
+
+// NoResource serve às ações que não miram um recurso específico.
+func NoResource[I any](context.Context, Principal, I) (Resource, error) {
+	return Resource{}, nil
+}
+
+// Guarded decora um caso de uso, consultando a política antes de executá-lo.
+type Guarded[I, O any] struct {
+	Next     UseCase[I, O]
+	Enforcer *Enforcer
+	Action   Action
+	Resource ResourceFunc[I]
+}
+
+func Guard[I, O any](next UseCase[I, O], enforcer *Enforcer, action Action, resource ResourceFunc[I]) *Guarded[I, O] {
+	return &Guarded[I, O]{Next: next, Enforcer: enforcer, Action: action, Resource: resource}
+}
+
+func (g *Guarded[I, O]) Execute(ctx context.Context, input I) (O, error) {
+	var zero O
+	if err := authorize(ctx, g.Enforcer, g.Action, g.Resource, input); err != nil {
+		return zero, err
+	}
+	return g.Next.Execute(ctx, input)
+}
+
+// GuardedCommand é o Guarded dos casos de uso sem saída.
+type GuardedCommand[I any] struct {
+	Next     Command[I]
+	Enforcer *Enforcer
+	Action   Action
+	Resource ResourceFunc[I]
+}
+
+func GuardCommand[I any](next Command[I], enforcer *Enforcer, action Action, resource ResourceFunc[I]) *GuardedCommand[I] {
+	return &GuardedCommand[I]{Next: next, Enforcer: enforcer, Action: action, Resource: resource}
+}
+
+func (g *GuardedCommand[I]) Execute(ctx context.Context, input I) error {
+	if err := authorize(ctx, g.Enforcer, g.Action, g.Resource, input); err != nil {
+		return err
+	}
+	return g.Next.Execute(ctx, input)
+}
+
+func authorize[I any](ctx context.Context, enforcer *Enforcer, action Action, resolve ResourceFunc[I], input I) error {
+	principal, err := enforcer.Principal(ctx)
+	if err != nil {
+		return err
+	}
+	resource, err := resolve(ctx, principal, input)
+	if err != nil {
+		return err
+	}
+	return enforcer.Authorize(ctx, principal, action, resource)
+}
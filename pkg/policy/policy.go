@@ -0,0 +1,142 @@
+// Package policy decide se um principal pode executar uma ação sobre um
+// recurso, a partir dos papéis dele e da relação que tem com o recurso.
+package policy
+
+import (
+	"errors"
+	"strings"
+)
+
+// Action identifica uma operação no formato "recurso:verbo". Um padrão
+// "recurso:*" casa com todas as ações do recurso e "*" com qualquer ação.
+type Action string
+
+const (
+	TaskCreate Action = "task:create"
+	TaskView   Action = "task:view"
+	TaskUpdate Action = "task:update"
+	TaskDelete Action = "task:delete"
+	TaskShare  Action = "task:share"
+
+	UserList   Action = "user:list"
+	UserView   Action = "user:view"
+	UserUpdate Action = "user:update"
+	UserDelete Action = "user:delete"
+
+	WorkspaceCreate Action = "workspace:create"
+	WorkspaceList   Action = "workspace:list"
+	WorkspaceJoin   Action = "workspace:join"
+	WorkspaceView   Action = "workspace:view"
+	WorkspaceManage Action = "workspace:manage"
+	WorkspaceDelete Action = "workspace:delete"
+
+	AdminRoles Action = "admin:roles"
+	AdminAudit Action = "admin:audit"
+)
+
+// Matches informa se a ação casa com o padrão.
+func (a Action) Matches(pattern Action) bool {
+	if pattern == "*" || pattern == a {
+		return true
+	}
+	prefix, ok := strings.CutSuffix(string(pattern), "*")
+	return ok && strings.HasSuffix(prefix, ":") && strings.HasPrefix(string(a), prefix)
+}
+
+// Role é um papel global do usuário, válido para qualquer recurso.
+type Role string
+
+const (
+	// RoleUser é dado a todo principal autenticado.
+	RoleUser  Role = "user"
+	RoleAdmin Role = "admin"
+)
+
+var ErrUnknownRole = errors.New("role must be one of user, admin")
+
+func NewRole(raw string) (Role, error) {
+	switch role := Role(raw); role {
+	case RoleUser, RoleAdmin:
+		return role, nil
+	default:
+		return "", ErrUnknownRole
+	}
+}
+
+// Relation é o vínculo do principal com um recurso específico.
+type Relation string
+
+const (
+	RelationOwner  Relation = "owner"
+	RelationEditor Relation = "editor"
+	RelationViewer Relation = "viewer"
+	// RelationSelf liga o usuário ao próprio cadastro.
+	RelationSelf Relation = "self"
+)
+
+// Principal é quem executa a ação.
+type Principal struct {
+	UserID string
+	Roles  []Role
+}
+
+// Resource é o alvo da ação, com as relações que o principal tem com ele.
+// Ações que não miram um recurso específico usam o valor zero.
+type Resource struct {
+	Type      string
+	ID        string
+	Relations []Relation
+}
+
+// Definition é a política declarativa: o que cada papel concede sobre
+// qualquer recurso e o que cada relação concede sobre o recurso ligado.
+type Definition struct {
+	Roles     map[Role][]Action
+	Relations map[Relation][]Action
+}
+
+// Default é a política da aplicação. Admins administram usuários e papéis,
+// mas não enxergam tarefas nem workspaces alheios; sobre eles vale a
+// relação. Os papéis dentro do workspace continuam refinando, no próprio
+// caso de uso, o que cada membro pode fazer.
+var Default = &Definition{
+	Roles: map[Role][]Action{
+		RoleUser:  {TaskCreate, WorkspaceCreate, WorkspaceList, WorkspaceJoin, UserList, UserView},
+		RoleAdmin: {"admin:*", "user:*"},
+	},
+	Relations: map[Relation][]Action{
+		RelationOwner:  {"task:*", "workspace:*"},
+		RelationEditor: {TaskView, TaskUpdate, WorkspaceView, WorkspaceManage},
+		RelationViewer: {TaskView, WorkspaceView},
+		RelationSelf:   {"user:*"},
+	},
+}
+
+// Can informa se a definição permite a ação do principal sobre o recurso.
+func (d *Definition) Can(principal Principal, action Action, resource Resource) bool {
+	for _, role := range principal.Roles {
+		if allows(d.Roles[role], action) {
+			return true
+		}
+	}
+	for _, relation := range resource.Relations {
+		if allows(d.Relations[relation], action) {
+			return true
+		}
+	}
+	return false
+}
+
+// Can consulta a política Default.
+func Can(principal Principal, action Action, resource Resource) bool {
+	return Default.Can(principal, action, resource)
+}
+
+func allows(patterns []Action, action Action) bool {
+	for _, pattern := range patterns {
+		if action.Matches(pattern) {
+			return true
+		}
+	}
+	return false
+}
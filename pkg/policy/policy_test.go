@@ -0,0 +1,241 @@
+package policy
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestActionMatches(t *testing.T) {
+	tests := []struct {
+		action  Action
+		pattern Action
+		want    bool
+	}{
+		{TaskView, TaskView, true},
+		{TaskView, TaskUpdate, false},
+		{TaskView, "task:*", true},
+		{UserView, "task:*", false},
+		{AdminAudit, "admin:*", true},
+		{AdminRoles, "admin:*", true},
+		{"administrator:x", "admin:*", false},
+		{TaskDelete, "*", true},
+		{TaskDelete, "task*", false},
+	}
+	for _, tt := range tests {
+		if got := tt.action.Matches(tt.pattern); got != tt.want {
+			t.Errorf("%q.Matches(%q) = %v, want %v", tt.action, tt.pattern, got, tt.want)
+		}
+	}
+}
+
+func TestDefinitionCan(t *testing.T) {
+	user := Principal{UserID: "u1", Roles: []Role{RoleUser}}
+	admin := Principal{UserID: "a1", Roles: []Role{RoleUser, RoleAdmin}}
+	anonymous := Principal{}
+
+	task := func(relations ...Relation) Resource {
+		return Resource{Type: "task", ID: "t1", Relations: relations}
+	}
+	workspace := func(relations ...Relation) Resource {
+		return Resource{Type: "workspace", ID: "w1", Relations: relations}
+	}
+	account := func(relations ...Relation) Resource {
+		return Resource{Type: "user", ID: "u2", Relations: relations}
+	}
+
+	tests := []struct {
+		name      string
+		principal Principal
+		action    Action
+		resource  Resource
+		want      bool
+	}{
+		{"anonymous cannot create tasks", anonymous, TaskCreate, Resource{}, false},
+		{"user creates tasks", user, TaskCreate, Resource{}, true},
+		{"user creates workspaces", user, WorkspaceCreate, Resource{}, true},
+		{"user accepts invitations", user, WorkspaceJoin, Resource{}, true},
+		{"user lists users", user, UserList, Resource{}, true},
+
+		{"owner views task", user, TaskView, task(RelationOwner), true},
+		{"owner deletes task", user, TaskDelete, task(RelationOwner), true},
+		{"owner shares task", user, TaskShare, task(RelationOwner), true},
+		{"editor updates task", user, TaskUpdate, task(RelationEditor), true},
+		{"editor cannot delete task", user, TaskDelete, task(RelationEditor), false},
+		{"editor cannot share task", user, TaskShare, task(RelationEditor), false},
+		{"viewer views task", user, TaskView, task(RelationViewer), true},
+		{"viewer cannot update task", user, TaskUpdate, task(RelationViewer), false},
+		{"stranger cannot view task", user, TaskView, task(), false},
+
+		{"workspace owner deletes", user, WorkspaceDelete, workspace(RelationOwner), true},
+		{"workspace editor manages", user, WorkspaceManage, workspace(RelationEditor), true},
+		{"workspace editor cannot delete", user, WorkspaceDelete, workspace(RelationEditor), false},
+		{"workspace viewer views", user, WorkspaceView, workspace(RelationViewer), true},
+		{"workspace viewer cannot manage", user, WorkspaceManage, workspace(RelationViewer), false},
+
+		{"self updates own account", user, UserUpdate, account(RelationSelf), true},
+		{"self deletes own account", user, UserDelete, account(RelationSelf), true},
+		{"user cannot update others", user, UserUpdate, account(), false},
+		{"user cannot delete others", user, UserDelete, account(), false},
+
+		{"admin wildcard covers roles", admin, AdminRoles, Resource{}, true},
+		{"admin wildcard covers audit", admin, AdminAudit, Resource{}, true},
+		{"admin deletes any user", admin, UserDelete, account(), true},
+		{"admin does not see foreign tasks", admin, TaskView, task(), false},
+		{"admin does not manage foreign workspaces", admin, WorkspaceManage, workspace(), false},
+		{"user has no admin actions", user, AdminRoles, Resource{}, false},
+		{"user has no audit access", user, AdminAudit, Resource{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Default.Can(tt.principal, tt.action, tt.resource); got != tt.want {
+				t.Errorf("Default.Can(%v, %q, %v) = %v, want %v", tt.principal.Roles, tt.action, tt.resource.Relations, got, tt.want)
+			}
+			if got := Can(tt.principal, tt.action, tt.resource); got != tt.want {
+				t.Errorf("Can(%v, %q, %v) = %v, want %v", tt.principal.Roles, tt.action, tt.resource.Relations, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDefinitionCanCustom(t *testing.T) {
+	def := &Definition{
+		Roles:     map[Role][]Action{RoleAdmin: {"*"}},
+		Relations: map[Relation][]Action{RelationViewer: {TaskView}},
+	}
+	if !def.Can(Principal{Roles: []Role{RoleAdmin}}, TaskDelete, Resource{}) {
+		t.Error("\"*\" should allow every action")
+	}
+	if def.Can(Principal{Roles: []Role{RoleUser}}, TaskCreate, Resource{}) {
+		t.Error("a role missing from the definition should allow nothing")
+	}
+	if def.Can(Principal{}, TaskView, Resource{Relations: []Relation{RelationOwner}}) {
+		t.Error("a relation missing from the definition should allow nothing")
+	}
+}
+
+type fakeRoles map[string][]Role
+
+func (f fakeRoles) Roles(_ context.Context, userID string) ([]Role, error) {
+	if userID == "broken" {
+		return nil, errors.New("store unavailable")
+	}
+	return f[userID], nil
+}
+
+func (f fakeRoles) Grant(context.Context, string, Role) error  { return nil }
+func (f fakeRoles) Revoke(context.Context, string, Role) error { return nil }
+
+type echoUseCase struct{ calls int }
+
+func (u *echoUseCase) Execute(_ context.Context, input string) (string, error) {
+	u.calls++
+	return input, nil
+}
+
+type countCommand struct{ calls int }
+
+func (c *countCommand) Execute(context.Context, string) error {
+	c.calls++
+	return nil
+}
+
+func TestEnforcerPrincipal(t *testing.T) {
+	enforcer := &Enforcer{Definition: Default, Roles: fakeRoles{"a1": {RoleAdmin}}}
+
+	principal, err := enforcer.Principal(context.Background())
+	if err != nil || principal.UserID != "" || len(principal.Roles) != 0 {
+		t.Fatalf("anonymous principal = %+v, %v", principal, err)
+	}
+
+	principal, err = enforcer.Principal(WithSubject(context.Background(), "a1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(principal.Roles) != 2 || principal.Roles[0] != RoleAdmin || principal.Roles[1] != RoleUser {
+		t.Errorf("roles = %v, want [admin user]", principal.Roles)
+	}
+
+	if _, err := enforcer.Principal(WithSubject(context.Background(), "broken")); err == nil {
+		t.Error("expected the role store error")
+	}
+}
+
+func TestGuard(t *testing.T) {
+	enforcer := &Enforcer{Definition: Default, Roles: fakeRoles{"a1": {RoleAdmin}}}
+	errMissing := errors.New("task not found")
+
+	relation := func(rel Relation) ResourceFunc[string] {
+		return func(context.Context, Principal, string) (Resource, error) {
+			return Resource{Type: "task", ID: "t1", Relations: []Relation{rel}}, nil
+		}
+	}
+	missing := func(context.Context, Principal, string) (Resource, error) {
+		return Resource{}, errMissing
+	}
+
+	tests := []struct {
+		name     string
+		subject  string
+		action   Action
+		resource ResourceFunc[string]
+		wantErr  error
+	}{
+		{"anonymous is denied", "", TaskCreate, NoResource[string], ErrDenied},
+		{"user may create", "u1", TaskCreate, NoResource[string], nil},
+		{"owner may delete", "u1", TaskDelete, relation(RelationOwner), nil},
+		{"viewer may not update", "u1", TaskUpdate, relation(RelationViewer), ErrDenied},
+		{"admin wildcard", "a1", AdminAudit, NoResource[string], nil},
+		{"resource error is returned as is", "u1", TaskView, missing, errMissing},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.subject != "" {
+				ctx = WithSubject(ctx, tt.subject)
+			}
+
+			next := &echoUseCase{}
+			out, err := Guard[string, string](next, enforcer, tt.action, tt.resource).Execute(ctx, "in")
+			command := &countCommand{}
+			cmdErr := GuardCommand[string](command, enforcer, tt.action, tt.resource).Execute(ctx, "in")
+			if !errors.Is(err, tt.wantErr) || !errors.Is(cmdErr, tt.wantErr) {
+				t.Fatalf("errors = %v / %v, want %v", err, cmdErr, tt.wantErr)
+			}
+			wantCalls := 0
+			if tt.wantErr == nil {
+				wantCalls = 1
+				if out != "in" {
+					t.Errorf("output = %q, want the wrapped use case result", out)
+				}
+			}
+			if next.calls != wantCalls || command.calls != wantCalls {
+				t.Errorf("calls = %d / %d, want %d", next.calls, command.calls, wantCalls)
+			}
+		})
+	}
+}
+
+func TestGuardRoleStoreError(t *testing.T) {
+	enforcer := &Enforcer{Definition: Default, Roles: fakeRoles{}}
+	ctx := WithSubject(context.Background(), "broken")
+
+	next := &echoUseCase{}
+	if _, err := Guard[string, string](next, enforcer, TaskCreate, NoResource[string]).Execute(ctx, "in"); err == nil {
+		t.Error("expected the role store error")
+	}
+	if next.calls != 0 {
+		t.Error("the wrapped use case must not run when the principal cannot be built")
+	}
+}
+
+func TestNewRole(t *testing.T) {
+	for _, raw := range []string{"user", "admin"} {
+		if role, err := NewRole(raw); err != nil || string(role) != raw {
+			t.Errorf("NewRole(%q) = %q, %v", raw, role, err)
+		}
+	}
+	if _, err := NewRole("root"); !errors.Is(err, ErrUnknownRole) {
+		t.Errorf("NewRole(root) error = %v, want ErrUnknownRole", err)
+	}
+}
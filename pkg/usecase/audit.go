@@ -0,0 +1,29 @@
+package usecase
+
+import (
+	"context"
+
+	domainAudit "github.com/hoyci/todo-ddd/pkg/domain/audit"
+	"github.com/hoyci/todo-ddd/pkg/logging"
+	"github.com/hoyci/todo-ddd/pkg/policy"
+)
+
+// Audit registra no log de auditoria a ação sobre a entidade, com o estado
+// antes e depois dela (nil quando não existia ou deixou de existir). Deve
+// ser chamado dentro da transação da mudança, com o repositório de work,
+// para que a mudança e o registro sejam confirmados juntos.
+func Audit(ctx context.Context, repo domainAudit.Repository, action, entityType, entityID string, before, after any) error {
+	entry, err := domainAudit.NewEntry(action, entityType, entityID, before, after)
+	if err != nil {
+		return err
+	}
+	entry.ActorID = policy.SubjectFrom(ctx)
+	entry.RequestID = logging.RequestID(ctx)
+	entry.IP = domainAudit.SourceIPFrom(ctx)
+
+	if err := repo.Append(ctx, entry); err != nil {
+		logging.ForPackage(ctx, "usecase").Error("error trying to append audit entry", "action", action, "entityID", entityID, "error", err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package audit
+
+import (
+	"context"
+
+	domainAudit "github.com/hoyci/todo-ddd/pkg/domain/audit"
+	"github.com/hoyci/todo-ddd/pkg/usecase"
+)
+
+//
+// ------------------- LIST -------------------
+//
+
+type ListEntriesInput struct {
+	Query domainAudit.Query
+}
+
+type ListEntriesOutput struct {
+	Entries []*domainAudit.Entry
+	// NextSeq é o AfterSeq da próxima página; zero quando não há mais.
+	NextSeq int64
+}
+
+// ListEntriesUseCase consulta o log de auditoria. Chega às rotas decorado
+// pela política, que o libera só a admins.
+type ListEntriesUseCase struct {
+	AuditRepo domainAudit.Repository
+}
+
+func (uc *ListEntriesUseCase) Execute(ctx context.Context, input ListEntriesInput) (_ *ListEntriesOutput, err error) {
+	ctx, end := usecase.Start(ctx, "list_audit_entries")
+	defer end(&err)
+
+	// Um a mais que o limite indica se há próxima página.
+	query := input.Query
+	if query.Limit > 0 {
+		query.Limit++
+	}
+	entries, err := uc.AuditRepo.List(ctx, query)
+	if err != nil {
+		logger(ctx).Error("error trying to list audit entries", "error", err)
+		return nil, err
+	}
+
+	output := &ListEntriesOutput{Entries: entries}
+	if input.Query.Limit > 0 && len(entries) > input.Query.Limit {
+		output.Entries = entries[:input.Query.Limit]
+		output.NextSeq = output.Entries[len(output.Entries)-1].Seq
+	}
+	return output, nil
+}
+
+//
+// ------------------- VERIFY -------------------
+//
+
+type VerifyChainOutput struct {
+	Entries  int64
+	LastHash string
+}
+
+// VerifyChainUseCase percorre o log inteiro em lotes, conferindo o
+// encadeamento e o hash de cada entrada. Devolve ErrChainBroken na primeira
+// entrada adulterada ou fora de sequência.
+type VerifyChainUseCase struct {
+	AuditRepo domainAudit.Repository
+	BatchSize int
+}
+
+func (uc *VerifyChainUseCase) Execute(ctx context.Context) (output *VerifyChainOutput, err error) {
+	ctx, end := usecase.Start(ctx, "verify_audit_chain")
+	defer end(&err)
+
+	output = &VerifyChainOutput{}
+	var afterSeq int64
+	for {
+		entries, err := uc.AuditRepo.List(ctx, domainAudit.Query{AfterSeq: afterSeq, Limit: uc.BatchSize})
+		if err != nil {
+			logger(ctx).Error("error trying to list audit entries", "afterSeq", afterSeq, "error", err)
+			return nil, err
+		}
+		for _, entry := range entries {
+			if err := entry.Verify(output.LastHash); err != nil {
+				logger(ctx).Error("audit chain is broken", "seq", entry.Seq, "error", err)
+				return output, err
+			}
+			output.Entries++
+			output.LastHash = entry.Hash
+			afterSeq = entry.Seq
+		}
+		if len(entries) < uc.BatchSize || uc.BatchSize <= 0 {
+			return output, nil
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	domainAudit "github.com/hoyci/todo-ddd/pkg/domain/audit"
+)
+
+// memoryRepository encadeia as entradas em memória, como o repositório
+// SQLite.
+type memoryRepository struct {
+	entries []*domainAudit.Entry
+}
+
+func (r *memoryRepository) Append(_ context.Context, entry *domainAudit.Entry) error {
+	if n := len(r.entries); n > 0 {
+		entry.Seq, entry.PrevHash = r.entries[n-1].Seq+1, r.entries[n-1].Hash
+	} else {
+		entry.Seq = 1
+	}
+	entry.Hash = entry.ComputeHash()
+	r.entries = append(r.entries, entry)
+	return nil
+}
+
+func (r *memoryRepository) List(_ context.Context, query domainAudit.Query) ([]*domainAudit.Entry, error) {
+	var out []*domainAudit.Entry
+	for _, entry := range r.entries {
+		if entry.Seq <= query.AfterSeq || query.ActorID != "" && entry.ActorID != query.ActorID {
+			continue
+		}
+		if query.Limit > 0 && len(out) == query.Limit {
+			break
+		}
+		out = append(out, entry)
+	}
+	return out, nil
+}
+
+func newMemoryRepository(t *testing.T, n int) *memoryRepository {
+	t.Helper()
+	repo := &memoryRepository{}
+	for i := range n {
+		entry, err := domainAudit.NewEntry("task.updated", "task", "t1", nil, map[string]int{"n": i})
+		if err != nil {
+			t.Fatal(err)
+		}
+		entry.ActorID = []string{"u1", "u2"}[i%2]
+		if err := repo.Append(context.Background(), entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return repo
+}
+
+func TestVerifyChain(t *testing.T) {
+	tests := []struct {
+		name        string
+		batchSize   int
+		tamper      func(repo *memoryRepository)
+		wantErr     error
+		wantEntries int64
+	}{
+		{"intact, one batch", 0, nil, nil, 5},
+		{"intact, batches of 2", 2, nil, nil, 5},
+		{"intact, exact batches", 5, nil, nil, 5},
+		{"modified in the second batch", 2, func(r *memoryRepository) { r.entries[3].EntityID = "t2" }, domainAudit.ErrChainBroken, 3},
+		{"removed across batches", 2, func(r *memoryRepository) {
+			r.entries = append(r.entries[:2], r.entries[3:]...)
+		}, domainAudit.ErrChainBroken, 2},
+		{"first entry replaced", 2, func(r *memoryRepository) { r.entries[0].PrevHash = "forged" }, domainAudit.ErrChainBroken, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMemoryRepository(t, 5)
+			if tt.tamper != nil {
+				tt.tamper(repo)
+			}
+			out, err := (&VerifyChainUseCase{AuditRepo: repo, BatchSize: tt.batchSize}).Execute(context.Background())
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if out.Entries != tt.wantEntries {
+				t.Errorf("verified %d entries, want %d", out.Entries, tt.wantEntries)
+			}
+			if tt.wantErr == nil && out.LastHash != repo.entries[len(repo.entries)-1].Hash {
+				t.Errorf("LastHash = %s, want the last entry hash", out.LastHash)
+			}
+		})
+	}
+}
+
+func TestListEntriesPages(t *testing.T) {
+	repo := newMemoryRepository(t, 5)
+	uc := &ListEntriesUseCase{AuditRepo: repo}
+
+	var seqs []int64
+	query := domainAudit.Query{ActorID: "u1", Limit: 2}
+	for pages := 0; ; pages++ {
+		if pages > 3 {
+			t.Fatal("pagination does not end")
+		}
+		out, err := uc.Execute(context.Background(), ListEntriesInput{Query: query})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, entry := range out.Entries {
+			seqs = append(seqs, entry.Seq)
+		}
+		if out.NextSeq == 0 {
+			break
+		}
+		query.AfterSeq = out.NextSeq
+	}
+	if want := []int64{1, 3, 5}; !slices.Equal(seqs, want) {
+		t.Errorf("listed seqs %v, want %v", seqs, want)
+	}
+}
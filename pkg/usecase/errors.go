@@ -3,13 +3,43 @@ package usecase
 import "errors"
 
 var (
-	ErrUserAlreadyExists       = errors.New("user already exists")
-	ErrUserSaveFailed          = errors.New("failed to save user")
-	ErrSearchingUserByEmail    = errors.New("failed to search user by email")
-	ErrSearchingUserByID       = errors.New("failed to search user by ID")
-	ErrUserNotFoundOrDeleted   = errors.New("user not found or deleted")
-	ErrUserNotFound            = errors.New("user not found")
-	ErrTaskSaveFailed          = errors.New("failed to save task")
-	ErrTransactionCommitFailed = errors.New("failed to commit transaction")
-	ErrUnknown                 = errors.New("unexpected error")
+	ErrUserAlreadyExists        = errors.New("user already exists")
+	ErrUserSaveFailed           = errors.New("failed to save user")
+	ErrSearchingUserByEmail     = errors.New("failed to search user by email")
+	ErrSearchingUserByID        = errors.New("failed to search user by ID")
+	ErrUserNotFoundOrDeleted    = errors.New("user not found or deleted")
+	ErrUserNotFound             = errors.New("user not found")
+	ErrEmailNotVerified         = errors.New("email not verified")
+	ErrTaskSaveFailed           = errors.New("failed to save task")
+	ErrTaskNotFound             = errors.New("task not found")
+	ErrTaskForbidden            = errors.New("not allowed to perform this action on the task")
+	ErrShareNotFound            = errors.New("share not found")
+	ErrCannotShareWithOwner     = errors.New("cannot share a task with its owner")
+	ErrShareInWorkspace         = errors.New("tasks in a workspace are shared through membership")
+	ErrWorkspaceNotFound        = errors.New("workspace not found")
+	ErrWorkspaceForbidden       = errors.New("workspace role does not allow this action")
+	ErrMemberNotFound           = errors.New("workspace member not found")
+	ErrAlreadyMember            = errors.New("user is already a workspace member")
+	ErrOwnerRoleFixed           = errors.New("the workspace owner role cannot be granted, changed or removed")
+	ErrInvitationNotFound       = errors.New("invitation not found")
+	ErrInvitationExpired        = errors.New("invitation expired or already accepted")
+	ErrInvitationEmailMismatch  = errors.New("invitation was sent to another email")
+	ErrCommentNotFound          = errors.New("comment not found")
+	ErrRevisionNotFound         = errors.New("revision not found")
+	ErrReminderNotFound         = errors.New("reminder not found")
+	ErrUndoNotFound             = errors.New("undo token not found")
+	ErrUndoExpired              = errors.New("undo token expired or already used")
+	ErrUndoConflict             = errors.New("the task changed since, so the change can no longer be undone")
+	ErrNotCommentAuthor         = errors.New("only the author can change a comment")
+	ErrInvalidCursor            = errors.New("invalid cursor")
+	ErrAttachmentNotFound       = errors.New("attachment not found")
+	ErrAttachmentTooLarge       = errors.New("attachment exceeds the size limit")
+	ErrAttachmentTypeNotAllowed = errors.New("attachment type not allowed")
+	ErrInvalidBatchOperation    = errors.New("invalid batch operation")
+	ErrInvalidPatch             = errors.New("invalid patch document")
+	ErrInvalidImportRow         = errors.New("invalid import row")
+	ErrInvalidPosition          = errors.New("invalid task position")
+	ErrBatchAborted             = errors.New("operation not applied because another operation in the batch failed")
+	ErrTransactionCommitFailed  = errors.New("failed to commit transaction")
+	ErrUnknown                  = errors.New("unexpected error")
 )
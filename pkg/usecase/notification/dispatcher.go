@@ -0,0 +1,28 @@
+package notification
+
+import (
+	"context"
+	"errors"
+
+	domainNotification "github.com/hoyci/todo-ddd/pkg/domain/notification"
+)
+
+// Dispatcher entrega a notificação em cada canal habilitado nas preferências
+// do destinatário. A falha de um canal não impede os demais.
+type Dispatcher struct {
+	Channels []domainNotification.Channel
+}
+
+func (d *Dispatcher) Deliver(ctx context.Context, recipient domainNotification.Recipient, prefs *domainNotification.Preferences, notification *domainNotification.Notification) error {
+	var errs []error
+	for _, channel := range d.Channels {
+		if !prefs.Enabled(channel.Name()) {
+			continue
+		}
+		if err := channel.Send(ctx, recipient, notification); err != nil {
+			logger(ctx).Warn("error trying to deliver notification", "channel", channel.Name(), "userID", recipient.UserID, "notificationID", notification.ID, "error", err)
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
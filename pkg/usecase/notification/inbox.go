@@ -0,0 +1,183 @@
+package notification
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/hoyci/todo-ddd/pkg/domain"
+	domainNotification "github.com/hoyci/todo-ddd/pkg/domain/notification"
+	"github.com/hoyci/todo-ddd/pkg/usecase"
+)
+
+//
+// ------------------- LIST -------------------
+//
+
+type ListNotificationsInput struct {
+	UserID     string
+	UnreadOnly bool
+	// After é o ID da última notificação da página anterior.
+	After string
+	Limit int
+}
+
+type ListNotificationsOutput struct {
+	Notifications []*domainNotification.Notification
+	// NextCursor é vazio na última página.
+	NextCursor string
+	Unread     int
+}
+
+type ListNotificationsUseCase struct {
+	InboxRepo domainNotification.InboxRepository
+}
+
+func (uc *ListNotificationsUseCase) Execute(ctx context.Context, input ListNotificationsInput) (_ *ListNotificationsOutput, err error) {
+	ctx, end := usecase.Start(ctx, "list_notifications")
+	defer end(&err)
+
+	if input.After != "" {
+		if _, err := uc.InboxRepo.Find(ctx, input.UserID, input.After); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, usecase.ErrInvalidCursor
+			}
+			logger(ctx).Error("error trying to find notification", "userID", input.UserID, "error", err)
+			return nil, err
+		}
+	}
+
+	// Uma notificação a mais indica se existe próxima página.
+	notifications, err := uc.InboxRepo.List(ctx, domainNotification.InboxQuery{
+		UserID:     input.UserID,
+		UnreadOnly: input.UnreadOnly,
+		AfterID:    input.After,
+		Limit:      input.Limit + 1,
+	})
+	if err != nil {
+		logger(ctx).Error("error trying to list notifications", "userID", input.UserID, "error", err)
+		return nil, err
+	}
+	unread, err := uc.InboxRepo.CountUnread(ctx, input.UserID)
+	if err != nil {
+		logger(ctx).Error("error trying to count unread notifications", "userID", input.UserID, "error", err)
+		return nil, err
+	}
+
+	output := &ListNotificationsOutput{Notifications: notifications, Unread: unread}
+	if len(notifications) > input.Limit {
+		output.Notifications = notifications[:input.Limit]
+		output.NextCursor = output.Notifications[input.Limit-1].ID
+	}
+	return output, nil
+}
+
+//
+// ------------------- UNREAD COUNT -------------------
+//
+
+type CountUnreadUseCase struct {
+	InboxRepo domainNotification.InboxRepository
+}
+
+func (uc *CountUnreadUseCase) Execute(ctx context.Context, userID string) (_ int, err error) {
+	ctx, end := usecase.Start(ctx, "count_unread_notifications")
+	defer end(&err)
+
+	count, err := uc.InboxRepo.CountUnread(ctx, userID)
+	if err != nil {
+		logger(ctx).Error("error trying to count unread notifications", "userID", userID, "error", err)
+		return 0, err
+	}
+	return count, nil
+}
+
+//
+// ------------------- MARK READ / UNREAD -------------------
+//
+
+type MarkReadInput struct {
+	UserID string
+	// IDs são ignorados quando All é verdadeiro. IDs de notificações de
+	// outros usuários ou expiradas não contam.
+	IDs []string
+	All bool
+}
+
+type MarkReadOutput struct {
+	// Updated conta as notificações que mudaram de estado.
+	Updated int
+	Unread  int
+}
+
+// MarkReadUseCase marca notificações como lidas, uma lista delas ou todas.
+type MarkReadUseCase struct {
+	UoW domain.UnitOfWork
+}
+
+func (uc *MarkReadUseCase) Execute(ctx context.Context, input MarkReadInput) (output *MarkReadOutput, err error) {
+	ctx, end := usecase.Start(ctx, "mark_notifications_read")
+	defer end(&err)
+
+	now := time.Now()
+	err = uc.UoW.Execute(ctx, func(ctx context.Context, work domain.Work) error {
+		var updated int
+		var err error
+		if input.All {
+			updated, err = work.InboxRepo().MarkAllRead(ctx, input.UserID, now)
+		} else {
+			updated, err = work.InboxRepo().SetRead(ctx, input.UserID, input.IDs, &now)
+		}
+		if err != nil {
+			logger(ctx).Error("error trying to mark notifications as read", "userID", input.UserID, "error", err)
+			return err
+		}
+		output, err = unreadAfter(ctx, work, input.UserID, updated)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+type MarkUnreadInput struct {
+	UserID string
+	IDs    []string
+}
+
+// MarkUnreadUseCase devolve notificações lidas ao estado de não lidas.
+type MarkUnreadUseCase struct {
+	UoW domain.UnitOfWork
+}
+
+func (uc *MarkUnreadUseCase) Execute(ctx context.Context, input MarkUnreadInput) (output *MarkReadOutput, err error) {
+	ctx, end := usecase.Start(ctx, "mark_notifications_unread")
+	defer end(&err)
+
+	err = uc.UoW.Execute(ctx, func(ctx context.Context, work domain.Work) error {
+		updated, err := work.InboxRepo().SetRead(ctx, input.UserID, input.IDs, nil)
+		if err != nil {
+			logger(ctx).Error("error trying to mark notifications as unread", "userID", input.UserID, "error", err)
+			return err
+		}
+		output, err = unreadAfter(ctx, work, input.UserID, updated)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+// unreadAfter reconta as não lidas dentro da transação que acabou de
+// alterá-las.
+func unreadAfter(ctx context.Context, work domain.Work, userID string, updated int) (*MarkReadOutput, error) {
+	unread, err := work.InboxRepo().CountUnread(ctx, userID)
+	if err != nil {
+		logger(ctx).Error("error trying to count unread notifications", "userID", userID, "error", err)
+		return nil, err
+	}
+	return &MarkReadOutput{Updated: updated, Unread: unread}, nil
+}
@@ -0,0 +1,96 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/hoyci/todo-ddd/internal/adapters/db/sqlite"
+	"github.com/hoyci/todo-ddd/internal/adapters/events"
+	"github.com/hoyci/todo-ddd/pkg/domain"
+	domainNotification "github.com/hoyci/todo-ddd/pkg/domain/notification"
+	"github.com/hoyci/todo-ddd/pkg/usecase"
+)
+
+func TestInbox(t *testing.T) {
+	db := newTestDB(t)
+	uow := sqlite.NewSQLiteUnitOfWork(db, events.NewBroker(0))
+	inbox := sqlite.NewSQLiteNotificationRepository(db)
+	ctx := context.Background()
+	now := time.Now()
+
+	// n[0] é a mais antiga; a expirada é gravada por último para não ser
+	// apagada pelas outras gravações.
+	var n []string
+	err := uow.Execute(ctx, func(ctx context.Context, work domain.Work) error {
+		for i, expiresAt := range []time.Time{now.Add(time.Hour), now.Add(time.Hour), now.Add(time.Hour), now.Add(-time.Second)} {
+			notification := domainNotification.NewNotification("u1", domainNotification.KindAssigned, "t1", "Assigned", "")
+			notification.CreatedAt, notification.ExpiresAt = now.Add(time.Duration(i-10)*time.Minute), expiresAt
+			if err := work.InboxRepo().Save(ctx, notification); err != nil {
+				return err
+			}
+			n = append(n, notification.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expired := n[3]
+
+	list := &ListNotificationsUseCase{InboxRepo: inbox}
+	page := func(input ListNotificationsInput) ([]string, string, int) {
+		t.Helper()
+		out, err := list.Execute(ctx, input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ids []string
+		for _, notification := range out.Notifications {
+			ids = append(ids, notification.ID)
+		}
+		return ids, out.NextCursor, out.Unread
+	}
+
+	ids, next, unread := page(ListNotificationsInput{UserID: "u1", Limit: 2})
+	if !slices.Equal(ids, []string{n[2], n[1]}) || next != n[1] || unread != 3 {
+		t.Errorf("first page = %v, next %q, unread %d; want [n2 n1], next n1, unread 3", ids, next, unread)
+	}
+	ids, next, _ = page(ListNotificationsInput{UserID: "u1", After: next, Limit: 2})
+	if !slices.Equal(ids, []string{n[0]}) || next != "" {
+		t.Errorf("last page = %v, next %q; want [n0] and no cursor", ids, next)
+	}
+
+	for _, cursor := range []string{"missing", expired} {
+		if _, err := list.Execute(ctx, ListNotificationsInput{UserID: "u1", After: cursor, Limit: 2}); !errors.Is(err, usecase.ErrInvalidCursor) {
+			t.Errorf("cursor %q: error = %v, want %v", cursor, err, usecase.ErrInvalidCursor)
+		}
+	}
+	if _, err := list.Execute(ctx, ListNotificationsInput{UserID: "u2", After: n[1], Limit: 2}); !errors.Is(err, usecase.ErrInvalidCursor) {
+		t.Errorf("another user's cursor: error = %v, want %v", err, usecase.ErrInvalidCursor)
+	}
+
+	markRead := &MarkReadUseCase{UoW: uow}
+	out, err := markRead.Execute(ctx, MarkReadInput{UserID: "u1", IDs: []string{n[0], expired}})
+	if err != nil || out.Updated != 1 || out.Unread != 2 {
+		t.Errorf("MarkRead = %+v, %v; want 1 updated and 2 unread", out, err)
+	}
+	ids, _, _ = page(ListNotificationsInput{UserID: "u1", UnreadOnly: true, Limit: 10})
+	if !slices.Equal(ids, []string{n[2], n[1]}) {
+		t.Errorf("unread only = %v, want [n2 n1]", ids)
+	}
+
+	out, err = (&MarkUnreadUseCase{UoW: uow}).Execute(ctx, MarkUnreadInput{UserID: "u1", IDs: []string{n[0], n[1]}})
+	if err != nil || out.Updated != 1 || out.Unread != 3 {
+		t.Errorf("MarkUnread = %+v, %v; want 1 updated and 3 unread", out, err)
+	}
+	out, err = markRead.Execute(ctx, MarkReadInput{UserID: "u1", All: true, IDs: []string{n[0]}})
+	if err != nil || out.Updated != 3 || out.Unread != 0 {
+		t.Errorf("MarkRead all = %+v, %v; want 3 updated and 0 unread", out, err)
+	}
+	if count, err := (&CountUnreadUseCase{InboxRepo: inbox}).Execute(ctx, "u1"); err != nil || count != 0 {
+		t.Errorf("CountUnread = %d, %v; want 0", count, err)
+	}
+}
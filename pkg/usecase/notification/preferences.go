@@ -0,0 +1,83 @@
+package notification
+
+import (
+	"context"
+
+	"github.com/hoyci/todo-ddd/pkg/domain"
+	domainNotification "github.com/hoyci/todo-ddd/pkg/domain/notification"
+	"github.com/hoyci/todo-ddd/pkg/domain/valueobject"
+	"github.com/hoyci/todo-ddd/pkg/usecase"
+)
+
+//
+// ------------------- GET -------------------
+//
+
+type GetPreferencesUseCase struct {
+	PreferenceRepo domainNotification.PreferenceRepository
+}
+
+func (uc *GetPreferencesUseCase) Execute(ctx context.Context, userID string) (_ *domainNotification.Preferences, err error) {
+	ctx, end := usecase.Start(ctx, "get_notification_preferences")
+	defer end(&err)
+
+	return findPreferences(ctx, uc.PreferenceRepo, userID)
+}
+
+//
+// ------------------- UPDATE -------------------
+//
+
+type UpdatePreferencesInput struct {
+	UserID     string
+	Channels   []string
+	WebhookURL string
+	// QuietHours nil desliga o horário de silêncio.
+	QuietHours *QuietHoursInput
+}
+
+// QuietHoursInput traz os limites no formato HH:MM e o fuso IANA.
+type QuietHoursInput struct {
+	Start    string
+	End      string
+	Timezone string
+}
+
+// UpdatePreferencesUseCase substitui as preferências de notificação do
+// usuário.
+type UpdatePreferencesUseCase struct {
+	UoW domain.UnitOfWork
+}
+
+func (uc *UpdatePreferencesUseCase) Execute(ctx context.Context, input UpdatePreferencesInput) (output *domainNotification.Preferences, err error) {
+	ctx, end := usecase.Start(ctx, "update_notification_preferences")
+	defer end(&err)
+
+	var quietHours valueobject.QuietHours
+	if input.QuietHours != nil {
+		quietHours, err = valueobject.ParseQuietHours(input.QuietHours.Start, input.QuietHours.End, input.QuietHours.Timezone)
+		if err != nil {
+			return nil, err
+		}
+	}
+	prefs, err := domainNotification.NewPreferences(input.UserID, input.Channels, input.WebhookURL, quietHours)
+	if err != nil {
+		return nil, err
+	}
+
+	err = uc.UoW.Execute(ctx, func(ctx context.Context, work domain.Work) error {
+		before, err := findPreferences(ctx, work.NotificationPreferenceRepo(), input.UserID)
+		if err != nil {
+			return err
+		}
+		if err := work.NotificationPreferenceRepo().Save(ctx, prefs); err != nil {
+			logger(ctx).Error("error trying to save notification preferences", "userID", input.UserID, "error", err)
+			return err
+		}
+		return usecase.Audit(ctx, work.AuditRepo(), "notification_preferences.updated", "user", input.UserID, before, prefs)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return prefs, nil
+}
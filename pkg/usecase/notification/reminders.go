@@ -0,0 +1,154 @@
+package notification
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/hoyci/todo-ddd/pkg/domain"
+	domainNotification "github.com/hoyci/todo-ddd/pkg/domain/notification"
+	domainTask "github.com/hoyci/todo-ddd/pkg/domain/task"
+	"github.com/hoyci/todo-ddd/pkg/domain/valueobject"
+	domainWorkspace "github.com/hoyci/todo-ddd/pkg/domain/workspace"
+	"github.com/hoyci/todo-ddd/pkg/usecase"
+)
+
+// findPreferences devolve as preferências salvas do usuário ou, se ele
+// nunca salvou, as padrão.
+func findPreferences(ctx context.Context, repo domainNotification.PreferenceRepository, userID string) (*domainNotification.Preferences, error) {
+	prefs, err := repo.Find(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domainNotification.DefaultPreferences(userID), nil
+		}
+		logger(ctx).Error("error trying to find notification preferences", "userID", userID, "error", err)
+		return nil, err
+	}
+	return prefs, nil
+}
+
+type FireRemindersInput struct {
+	Now time.Time
+	// Limit é o máximo de lembretes tratados por execução.
+	Limit int
+}
+
+type FireRemindersOutput struct {
+	// Fired conta os lembretes entregues; Postponed, os adiados pelo
+	// horário de silêncio; Skipped, os descartados porque a tarefa foi
+	// concluída ou excluída, ou o usuário perdeu acesso a ela.
+	Fired     int
+	Postponed int
+	Skipped   int
+}
+
+// delivery é uma notificação pronta para sair depois da transação.
+type delivery struct {
+	recipient    domainNotification.Recipient
+	prefs        *domainNotification.Preferences
+	notification *domainNotification.Notification
+}
+
+// FireRemindersUseCase é chamado pelo agendador. Os lembretes vencidos são
+// marcados como disparados na transação e só depois entregues, de modo que
+// dois agendadores não enviam o mesmo lembrete; em troca, uma entrega que
+// falhe não é repetida.
+type FireRemindersUseCase struct {
+	UoW        domain.UnitOfWork
+	Dispatcher *Dispatcher
+}
+
+func (uc *FireRemindersUseCase) Execute(ctx context.Context, input FireRemindersInput) (output *FireRemindersOutput, err error) {
+	ctx, end := usecase.Start(ctx, "fire_reminders")
+	defer end(&err)
+
+	var deliveries []delivery
+	err = uc.UoW.Execute(ctx, func(ctx context.Context, work domain.Work) error {
+		reminders, err := work.ReminderRepo().ListDue(ctx, input.Now, input.Limit)
+		if err != nil {
+			logger(ctx).Error("error trying to list due reminders", "error", err)
+			return err
+		}
+
+		output = &FireRemindersOutput{}
+		for _, reminder := range reminders {
+			d, err := prepareReminder(ctx, work, reminder, input.Now)
+			if err != nil {
+				return err
+			}
+			switch {
+			case d != nil:
+				deliveries = append(deliveries, *d)
+				output.Fired++
+			case reminder.Pending():
+				output.Postponed++
+			default:
+				output.Skipped++
+			}
+			if err := work.ReminderRepo().Update(ctx, reminder); err != nil {
+				logger(ctx).Error("error trying to update reminder", "reminderID", reminder.ID, "error", err)
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, d := range deliveries {
+		_ = uc.Dispatcher.Deliver(ctx, d.recipient, d.prefs, d.notification)
+	}
+	return output, nil
+}
+
+// prepareReminder decide o destino de um lembrete vencido: devolve a entrega
+// e o marca como disparado, adia-o até o fim do horário de silêncio ou o
+// descarta. Nos dois últimos casos devolve nil.
+func prepareReminder(ctx context.Context, work domain.Work, reminder *domainTask.Reminder, now time.Time) (*delivery, error) {
+	task, err := work.TaskRepo().FindByID(domainWorkspace.WithScope(ctx, reminder.WorkspaceID), reminder.TaskID, reminder.UserID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		logger(ctx).Error("error trying to find task by id", "taskID", reminder.TaskID, "error", err)
+		return nil, err
+	}
+	if task == nil || task.DeletedAt != nil || task.Status == valueobject.StatusCompleted {
+		reminder.MarkFired(now)
+		return nil, nil
+	}
+
+	user, err := work.UserRepo().FindByID(ctx, reminder.UserID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		logger(ctx).Error("error trying to find user by id", "userID", reminder.UserID, "error", err)
+		return nil, err
+	}
+	if user == nil || user.DeletedAt != nil {
+		reminder.MarkFired(now)
+		return nil, nil
+	}
+
+	prefs, err := findPreferences(ctx, work.NotificationPreferenceRepo(), user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if prefs.QuietHours.Contains(now) {
+		reminder.Postpone(prefs.QuietHours.EndAfter(now))
+		return nil, nil
+	}
+
+	body := ""
+	if task.DueAt != nil {
+		body = "Due " + task.DueAt.In(prefs.QuietHours.Location()).Format("Mon, 02 Jan 2006 15:04 MST")
+	}
+	reminder.MarkFired(now)
+	return &delivery{
+		recipient: domainNotification.Recipient{
+			UserID:     user.ID,
+			Name:       user.Name,
+			Email:      user.Email,
+			WebhookURL: prefs.WebhookURL,
+		},
+		prefs:        prefs,
+		notification: domainNotification.NewNotification(user.ID, domainNotification.KindReminder, task.ID, "Reminder: "+task.Title, body),
+	}, nil
+}
@@ -0,0 +1,203 @@
+package notification
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/hoyci/todo-ddd/internal/adapters/db/sqlite"
+	"github.com/hoyci/todo-ddd/internal/adapters/events"
+	"github.com/hoyci/todo-ddd/pkg/domain"
+	domainNotification "github.com/hoyci/todo-ddd/pkg/domain/notification"
+	domainTask "github.com/hoyci/todo-ddd/pkg/domain/task"
+	"github.com/hoyci/todo-ddd/pkg/domain/valueobject"
+	usecasetask "github.com/hoyci/todo-ddd/pkg/usecase/task"
+	usecaseuser "github.com/hoyci/todo-ddd/pkg/usecase/user"
+)
+
+// recordingChannel guarda as notificações em vez de entregá-las.
+type recordingChannel struct {
+	sent []*domainNotification.Notification
+}
+
+func (c *recordingChannel) Name() valueobject.NotificationChannel { return valueobject.ChannelInApp }
+
+func (c *recordingChannel) Send(_ context.Context, _ domainNotification.Recipient, notification *domainNotification.Notification) error {
+	c.sent = append(c.sent, notification)
+	return nil
+}
+
+// reminderFixture tem um usuário com uma tarefa que vence em due e um
+// lembrete uma hora antes, sobre um banco SQLite temporário.
+type reminderFixture struct {
+	uow      domain.UnitOfWork
+	userID   string
+	taskID   string
+	reminder *domainTask.Reminder
+	channel  *recordingChannel
+	fire     *FireRemindersUseCase
+}
+
+// newTestDB abre um banco SQLite temporário com o esquema completo.
+func newTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	t.Chdir(t.TempDir())
+	if err := os.Mkdir("data", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	db, err := sqlite.InitDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func newReminderFixture(t *testing.T, due time.Time) *reminderFixture {
+	t.Helper()
+	uow := sqlite.NewSQLiteUnitOfWork(newTestDB(t), events.NewBroker(0))
+	ctx := context.Background()
+
+	user, err := (&usecaseuser.CreateUserUseCase{UoW: uow}).Execute(ctx, usecaseuser.CreateUserInput{Name: "Ada", Email: "ada@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	task, err := (&usecasetask.CreateTaskUseCase{UoW: uow}).Execute(ctx, usecasetask.CreateTaskInput{Title: "Pay rent", Priority: 1, UserID: user.User.ID, DueAt: &due})
+	if err != nil {
+		t.Fatal(err)
+	}
+	reminder, err := (&usecasetask.AddReminderUseCase{UoW: uow}).Execute(ctx, usecasetask.AddReminderInput{TaskID: task.ID, UserID: user.User.ID, BeforeDue: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	channel := &recordingChannel{}
+	return &reminderFixture{
+		uow:      uow,
+		userID:   user.User.ID,
+		taskID:   task.ID,
+		reminder: reminder,
+		channel:  channel,
+		fire:     &FireRemindersUseCase{UoW: uow, Dispatcher: &Dispatcher{Channels: []domainNotification.Channel{channel}}},
+	}
+}
+
+// fireAt roda o agendador no instante now e confere os contadores.
+func (f *reminderFixture) fireAt(t *testing.T, now time.Time, want FireRemindersOutput) {
+	t.Helper()
+	out, err := f.fire.Execute(context.Background(), FireRemindersInput{Now: now, Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *out != want {
+		t.Errorf("at %s: output = %+v, want %+v", now.Format(time.RFC3339), *out, want)
+	}
+}
+
+// scheduled devolve o horário de disparo atual do lembrete.
+func (f *reminderFixture) scheduled(t *testing.T) time.Time {
+	t.Helper()
+	var reminder *domainTask.Reminder
+	err := f.uow.Execute(context.Background(), func(ctx context.Context, work domain.Work) error {
+		var err error
+		reminder, err = work.ReminderRepo().Find(ctx, f.taskID, f.reminder.ID)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return reminder.FireAt
+}
+
+// nextDue é um vencimento em hora cheia daqui a dois dias, para que os
+// horários derivados dele caiam em minutos exatos.
+func nextDue() time.Time {
+	return time.Now().UTC().Truncate(time.Hour).Add(48 * time.Hour)
+}
+
+func TestFireRemindersBeforeDue(t *testing.T) {
+	due := nextDue()
+	f := newReminderFixture(t, due)
+	fireAt := due.Add(-time.Hour)
+
+	if got := f.scheduled(t); !got.Equal(fireAt) {
+		t.Fatalf("reminder scheduled for %s, want one hour before the due date", got)
+	}
+
+	f.fireAt(t, fireAt.Add(-time.Minute), FireRemindersOutput{})
+	f.fireAt(t, fireAt, FireRemindersOutput{Fired: 1})
+	f.fireAt(t, fireAt.Add(time.Minute), FireRemindersOutput{})
+
+	if len(f.channel.sent) != 1 {
+		t.Fatalf("sent %d notifications, want 1", len(f.channel.sent))
+	}
+	sent := f.channel.sent[0]
+	if sent.Kind != domainNotification.KindReminder || sent.TaskID != f.taskID || sent.Title != "Reminder: Pay rent" {
+		t.Errorf("notification = %+v", sent)
+	}
+	if want := "Due " + due.Format("Mon, 02 Jan 2006 15:04 MST"); sent.Body != want {
+		t.Errorf("body = %q, want %q", sent.Body, want)
+	}
+}
+
+func TestFireRemindersQuietHours(t *testing.T) {
+	due := nextDue()
+	f := newReminderFixture(t, due)
+	fireAt := due.Add(-time.Hour)
+	quietEnd := fireAt.Add(30 * time.Minute)
+
+	// O silêncio vai de meia hora antes a meia hora depois do disparo, no
+	// fuso de São Paulo, que não tem horário de verão.
+	location, err := time.LoadLocation("America/Sao_Paulo")
+	if err != nil {
+		t.Skip("timezone database unavailable:", err)
+	}
+	_, err = (&UpdatePreferencesUseCase{UoW: f.uow}).Execute(context.Background(), UpdatePreferencesInput{
+		UserID:   f.userID,
+		Channels: []string{string(valueobject.ChannelInApp)},
+		QuietHours: &QuietHoursInput{
+			Start:    fireAt.Add(-30 * time.Minute).In(location).Format("15:04"),
+			End:      quietEnd.In(location).Format("15:04"),
+			Timezone: location.String(),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f.fireAt(t, fireAt, FireRemindersOutput{Postponed: 1})
+	if got := f.scheduled(t); !got.Equal(quietEnd) {
+		t.Errorf("reminder postponed to %s, want the end of the quiet hours %s", got, quietEnd)
+	}
+	f.fireAt(t, quietEnd.Add(-time.Minute), FireRemindersOutput{})
+	f.fireAt(t, quietEnd, FireRemindersOutput{Fired: 1})
+
+	if len(f.channel.sent) != 1 {
+		t.Errorf("sent %d notifications, want 1 after the quiet hours", len(f.channel.sent))
+	}
+	if want := "Due " + due.In(location).Format("Mon, 02 Jan 2006 15:04 MST"); len(f.channel.sent) == 1 && f.channel.sent[0].Body != want {
+		t.Errorf("body = %q, want the due date in the user's timezone %q", f.channel.sent[0].Body, want)
+	}
+}
+
+func TestFireRemindersSkipsCompletedTask(t *testing.T) {
+	due := nextDue()
+	f := newReminderFixture(t, due)
+
+	_, err := (&usecasetask.UpdateTaskStatusUseCase{UoW: f.uow}).Execute(context.Background(), usecasetask.UpdateTaskStatusInput{
+		TaskID: f.taskID,
+		UserID: f.userID,
+		Status: valueobject.StatusCompleted,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f.fireAt(t, due, FireRemindersOutput{Skipped: 1})
+	f.fireAt(t, due.Add(time.Hour), FireRemindersOutput{})
+	if len(f.channel.sent) != 0 {
+		t.Errorf("sent %d notifications for a completed task", len(f.channel.sent))
+	}
+}
@@ -0,0 +1,35 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/hoyci/todo-ddd/pkg/domain"
+	domainNotification "github.com/hoyci/todo-ddd/pkg/domain/notification"
+	"github.com/hoyci/todo-ddd/pkg/domain/valueobject"
+	"github.com/hoyci/todo-ddd/pkg/logging"
+)
+
+// Notify coloca a notificação na caixa de entrada do destinatário, a menos
+// que ele tenha desligado o canal in_app. Como Audit, deve ser chamado
+// dentro da transação da mudança que gerou a notificação.
+func Notify(ctx context.Context, work domain.Work, notification *domainNotification.Notification) error {
+	prefs, err := work.NotificationPreferenceRepo().Find(ctx, notification.UserID)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			logging.ForPackage(ctx, "usecase").Error("error trying to find notification preferences", "userID", notification.UserID, "error", err)
+			return err
+		}
+		prefs = domainNotification.DefaultPreferences(notification.UserID)
+	}
+	if !prefs.Enabled(valueobject.ChannelInApp) {
+		return nil
+	}
+
+	if err := work.InboxRepo().Save(ctx, notification); err != nil {
+		logging.ForPackage(ctx, "usecase").Error("error trying to save notification", "userID", notification.UserID, "error", err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	domainNotification "github.com/hoyci/todo-ddd/pkg/domain/notification"
+	domainTask "github.com/hoyci/todo-ddd/pkg/domain/task"
+	domainUser "github.com/hoyci/todo-ddd/pkg/domain/user"
+	"github.com/hoyci/todo-ddd/pkg/domain/valueobject"
+	"github.com/hoyci/todo-ddd/pkg/policy"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// Observer recebe o resultado de cada execução de caso de uso. Adaptadores
+// de observabilidade (ex: Prometheus) implementam essa porta.
+type Observer interface {
+	ObserveUseCase(name string, duration time.Duration, err error)
+}
+
+var observer Observer
+
+var tracer = otel.Tracer("github.com/hoyci/todo-ddd/pkg/usecase")
+
+func SetObserver(o Observer) {
+	observer = o
+}
+
+// Start abre um span para o caso de uso e devolve a função que o encerra,
+// registrando o erro (se houver) no span e no Observer. Uso esperado:
+//
+//	ctx, end := usecase.Start(ctx, "create_task")
+//	defer end(&err)
+func Start(ctx context.Context, name string) (context.Context, func(err *error)) {
+	start := time.Now()
+	ctx, span := tracer.Start(ctx, "usecase."+name)
+
+	return ctx, func(err *error) {
+		var execErr error
+		if err != nil {
+			execErr = *err
+		}
+
+		if execErr != nil {
+			span.RecordError(execErr)
+			span.SetStatus(codes.Error, execErr.Error())
+			span.SetAttributes(attribute.String("error.kind", ErrorKind(execErr)))
+		}
+		span.End()
+
+		if observer != nil {
+			observer.ObserveUseCase(name, time.Since(start), execErr)
+		}
+	}
+}
+
+const (
+	ErrorKindValidation = "validation"
+	ErrorKindNotFound   = "not_found"
+	ErrorKindConflict   = "conflict"
+	ErrorKindForbidden  = "forbidden"
+	ErrorKindInternal   = "internal"
+	ErrorKindAborted    = "aborted"
+)
+
+// errorKinds associa os erros sentinela às categorias de ErrorKind. A
+// ordem importa para erros que embrulham mais de um sentinela: vale a
+// primeira categoria que casar.
+var errorKinds = []struct {
+	kind string
+	errs []error
+}{
+	{ErrorKindValidation, []error{
+		valueobject.ErrInvalidEmail,
+		valueobject.ErrEmptyTitle,
+		valueobject.ErrTitleTooLong,
+		valueobject.ErrInvalidPriority,
+		valueobject.ErrInvalidStatus,
+		valueobject.ErrInvalidUserName,
+		valueobject.ErrInvalidTag,
+		valueobject.ErrInvalidRecurrence,
+		valueobject.ErrEmptyComment,
+		valueobject.ErrCommentTooLong,
+		ErrInvalidCursor,
+		valueobject.ErrInvalidSharePermission,
+		ErrCannotShareWithOwner,
+		valueobject.ErrInvalidFileName,
+		ErrAttachmentTooLarge,
+		ErrAttachmentTypeNotAllowed,
+		ErrInvalidBatchOperation,
+		ErrInvalidPatch,
+		ErrInvalidImportRow,
+		ErrInvalidPosition,
+		ErrShareInWorkspace,
+		valueobject.ErrInvalidWorkspaceName,
+		valueobject.ErrInvalidWorkspaceRole,
+		ErrOwnerRoleFixed,
+		ErrInvitationExpired,
+		ErrUndoExpired,
+		domainTask.ErrInvalidReminder,
+		domainTask.ErrReminderWithoutDueDate,
+		domainTask.ErrReminderInPast,
+		valueobject.ErrInvalidNotificationChannel,
+		valueobject.ErrInvalidQuietHours,
+		domainNotification.ErrInvalidWebhookURL,
+		domainNotification.ErrWebhookURLRequired,
+		domainUser.ErrInvalidVerificationToken,
+		domainUser.ErrVerificationTokenExpired,
+		policy.ErrUnknownRole,
+	}},
+	{ErrorKindNotFound, []error{
+		ErrTaskNotFound,
+		ErrUserNotFound,
+		ErrUserNotFoundOrDeleted,
+		ErrCommentNotFound,
+		ErrRevisionNotFound,
+		ErrReminderNotFound,
+		ErrUndoNotFound,
+		ErrAttachmentNotFound,
+		ErrShareNotFound,
+		ErrWorkspaceNotFound,
+		ErrMemberNotFound,
+		ErrInvitationNotFound,
+		sql.ErrNoRows,
+	}},
+	{ErrorKindConflict, []error{
+		ErrUserAlreadyExists,
+		ErrAlreadyMember,
+		ErrUndoConflict,
+		domainUser.ErrEmailAlreadyVerified,
+	}},
+	{ErrorKindForbidden, []error{
+		ErrNotCommentAuthor,
+		ErrTaskForbidden,
+		ErrWorkspaceForbidden,
+		ErrInvitationEmailMismatch,
+		ErrEmailNotVerified,
+		policy.ErrDenied,
+	}},
+	{ErrorKindAborted, []error{
+		ErrBatchAborted,
+	}},
+}
+
+// ErrorKind classifica um erro retornado por um caso de uso em uma categoria
+// estável, adequada para labels de métricas e códigos de erro da API. Erros
+// fora de errorKinds são internos.
+func ErrorKind(err error) string {
+	if err == nil {
+		return ""
+	}
+	for _, k := range errorKinds {
+		for _, sentinel := range k.errs {
+			if errors.Is(err, sentinel) {
+				return k.kind
+			}
+		}
+	}
+	return ErrorKindInternal
+}
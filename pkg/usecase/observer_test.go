@@ -0,0 +1,41 @@
+package usecase
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/hoyci/todo-ddd/pkg/domain/valueobject"
+	"github.com/hoyci/todo-ddd/pkg/policy"
+)
+
+func TestErrorKind(t *testing.T) {
+	// Cada sentinela da tabela, embrulhado como os casos de uso costumam
+	// devolver, cai na sua própria categoria.
+	for _, k := range errorKinds {
+		for _, sentinel := range k.errs {
+			if got := ErrorKind(fmt.Errorf("wrapped: %w", sentinel)); got != k.kind {
+				t.Errorf("ErrorKind(%v) = %q, want %q", sentinel, got, k.kind)
+			}
+		}
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"nil", nil, ""},
+		{"unknown", errors.New("disk full"), ErrorKindInternal},
+		{"no rows", fmt.Errorf("find task: %w", sql.ErrNoRows), ErrorKindNotFound},
+		{"denied", fmt.Errorf("%w: task.delete", policy.ErrDenied), ErrorKindForbidden},
+		// Com mais de um sentinela, vale a primeira categoria da tabela.
+		{"joined", errors.Join(ErrTaskForbidden, valueobject.ErrEmptyTitle), ErrorKindValidation},
+	}
+	for _, tt := range tests {
+		if got := ErrorKind(tt.err); got != tt.want {
+			t.Errorf("%s: ErrorKind = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	domainWorkspace "github.com/hoyci/todo-ddd/pkg/domain/workspace"
+)
+
+// Authorize consulta a política do workspace antes de uma mutação e devolve
+// a participação de userID. Quem não é membro recebe ErrWorkspaceNotFound,
+// para não revelar que o workspace existe; quem é, mas o papel não permite
+// a ação, recebe ErrWorkspaceForbidden.
+func Authorize(ctx context.Context, repo domainWorkspace.WorkspaceRepository, workspaceID, userID string, action domainWorkspace.Action) (*domainWorkspace.Member, error) {
+	member, err := repo.FindMember(ctx, workspaceID, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrWorkspaceNotFound
+		}
+		return nil, err
+	}
+	if !member.Can(action) {
+		return nil, ErrWorkspaceForbidden
+	}
+	return member, nil
+}
+
+// AuthorizeScope aplica Authorize ao workspace do contexto. No espaço
+// pessoal não há política a consultar.
+func AuthorizeScope(ctx context.Context, repo domainWorkspace.WorkspaceRepository, userID string, action domainWorkspace.Action) error {
+	workspaceID := domainWorkspace.ScopeFrom(ctx)
+	if workspaceID == "" {
+		return nil
+	}
+	_, err := Authorize(ctx, repo, workspaceID, userID, action)
+	return err
+}
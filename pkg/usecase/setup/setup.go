@@ -4,12 +4,13 @@ import (
 	"context"
 	"database/sql"
 	"errors"
-	"log/slog"
+	"time"
 
 	"github.com/hoyci/todo-ddd/pkg/domain"
 	domainTask "github.com/hoyci/todo-ddd/pkg/domain/task"
 	domainUser "github.com/hoyci/todo-ddd/pkg/domain/user"
 	"github.com/hoyci/todo-ddd/pkg/usecase"
+	usecaseuser "github.com/hoyci/todo-ddd/pkg/usecase/user"
 )
 
 type SetupOnboardingInput struct {
@@ -19,16 +20,24 @@ type SetupOnboardingInput struct {
 
 type SetupOnboardingUseCase struct {
 	UoW domain.UnitOfWork
+	// Verification envia o email de verificação ao novo usuário, que fica
+	// pendente até confirmá-lo. Sem ela, o usuário já nasce verificado.
+	Verification *usecaseuser.VerificationSender
 }
 
-func (uc *SetupOnboardingUseCase) Execute(input SetupOnboardingInput) error {
-	return uc.UoW.Execute(context.Background(), func(work domain.Work) error {
+func (uc *SetupOnboardingUseCase) Execute(ctx context.Context, input SetupOnboardingInput) (err error) {
+	ctx, end := usecase.Start(ctx, "setup_onboarding")
+	defer end(&err)
+
+	now := time.Now()
+	var user *domainUser.User
+	err = uc.UoW.Execute(ctx, func(ctx context.Context, work domain.Work) error {
 		userRepo := work.UserRepo()
 		taskRepo := work.TaskRepo()
 
-		userExists, err := userRepo.FindByEmail(input.Email)
+		userExists, err := userRepo.FindByEmail(ctx, input.Email)
 		if !errors.Is(err, sql.ErrNoRows) {
-			slog.Error("unexpected error", "error", err)
+			logger(ctx).Error("unexpected error", "error", err)
 			return usecase.ErrUnknown
 		}
 
@@ -39,13 +48,21 @@ func (uc *SetupOnboardingUseCase) Execute(input SetupOnboardingInput) error {
 			return usecase.ErrUserAlreadyExists
 		}
 
-		user, err := domainUser.NewUser(input.Name, input.Email)
+		user, err = domainUser.NewUser(input.Name, input.Email)
 		if err != nil {
 			return err
 		}
-		if err = userRepo.Save(*user); err != nil {
+		if uc.Verification == nil {
+			_ = user.Verify()
+		} else {
+			user.MarkVerificationSent(now)
+		}
+		if err = userRepo.Save(ctx, *user); err != nil {
 			return usecase.ErrUserSaveFailed
 		}
+		if err = usecase.Audit(ctx, work.AuditRepo(), "user.created", "user", user.ID, nil, user); err != nil {
+			return err
+		}
 
 		task, err := domainTask.NewTask(
 			"Tarefa de boas-vindas",
@@ -56,10 +73,30 @@ func (uc *SetupOnboardingUseCase) Execute(input SetupOnboardingInput) error {
 		if err != nil {
 			return err
 		}
-		if err = taskRepo.Save(task); err != nil {
+		if err = taskRepo.Save(ctx, task); err != nil {
 			return usecase.ErrTaskSaveFailed
 		}
+		if err = work.TaskEventRepo().Append(ctx, task.PullEvents()); err != nil {
+			return err
+		}
+		if err = usecase.Audit(ctx, work.AuditRepo(), "task.created", "task", task.ID, nil, task); err != nil {
+			return err
+		}
+		if err = work.RevisionRepo().Append(ctx, domainTask.NewRevision(task, user.ID)); err != nil {
+			return err
+		}
 
 		return nil
 	})
+	if err != nil {
+		return err
+	}
+
+	// O usuário já foi criado; se o email falhar, ele pode pedir o reenvio.
+	if uc.Verification != nil {
+		if err := uc.Verification.Send(ctx, user, now); err != nil {
+			logger(ctx).Warn("verification email not sent", "userID", user.ID, "error", err)
+		}
+	}
+	return nil
 }
@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/hoyci/todo-ddd/pkg/domain"
+	domainTask "github.com/hoyci/todo-ddd/pkg/domain/task"
+	domainWorkspace "github.com/hoyci/todo-ddd/pkg/domain/workspace"
+	"github.com/hoyci/todo-ddd/pkg/usecase"
+)
+
+// findTaskWithAccess busca a tarefa ativa exigindo de userID ao menos o
+// acesso need. Quem não enxerga a tarefa recebe ErrTaskNotFound, e não
+// ErrTaskForbidden, para não revelar que ela existe.
+func findTaskWithAccess(ctx context.Context, work domain.Work, taskID, userID string, need domainTask.Access) (*domainTask.Task, error) {
+	task, err := findActiveTask(ctx, work.TaskRepo(), taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if need <= domainTask.AccessView {
+		return task, nil
+	}
+
+	access, err := accessFor(ctx, work, task, userID)
+	if err != nil {
+		return nil, err
+	}
+	if access < need {
+		return nil, usecase.ErrTaskForbidden
+	}
+	return task, nil
+}
+
+// accessFor calcula o acesso de userID à tarefa, consultando o
+// compartilhamento só quando dono e responsável não bastam. Em um
+// workspace quem decide é o papel do membro.
+func accessFor(ctx context.Context, work domain.Work, task *domainTask.Task, userID string) (domainTask.Access, error) {
+	if task.WorkspaceID != "" {
+		return workspaceAccess(ctx, work, task, userID)
+	}
+	if access := task.AccessFor(userID, nil); access != domainTask.AccessNone {
+		return access, nil
+	}
+
+	share, err := work.ShareRepo().Find(ctx, task.ID, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domainTask.AccessNone, nil
+		}
+		logger(ctx).Error("error trying to find task share", "taskID", task.ID, "error", err)
+		return domainTask.AccessNone, err
+	}
+	return task.AccessFor(userID, share), nil
+}
+
+// workspaceAccess traduz o papel de userID no workspace da tarefa em
+// acesso: admin e owner gerenciam qualquer tarefa, member edita todas e
+// exclui as próprias, guest só lê. Quem deixou o workspace perde o acesso,
+// mesmo às tarefas que criou.
+func workspaceAccess(ctx context.Context, work domain.Work, task *domainTask.Task, userID string) (domainTask.Access, error) {
+	member, err := work.WorkspaceRepo().FindMember(ctx, task.WorkspaceID, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domainTask.AccessNone, nil
+		}
+		logger(ctx).Error("error trying to find workspace member", "workspaceID", task.WorkspaceID, "error", err)
+		return domainTask.AccessNone, err
+	}
+
+	switch {
+	case member.Can(domainWorkspace.ActionManageAnyTask):
+		return domainTask.AccessOwner, nil
+	case member.Can(domainWorkspace.ActionEditAnyTask) && task.UserID == userID:
+		return domainTask.AccessOwner, nil
+	case member.Can(domainWorkspace.ActionEditAnyTask):
+		return domainTask.AccessEdit, nil
+	case member.Can(domainWorkspace.ActionViewTasks):
+		return domainTask.AccessView, nil
+	default:
+		return domainTask.AccessNone, nil
+	}
+}
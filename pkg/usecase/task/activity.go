@@ -0,0 +1,58 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	domainTask "github.com/hoyci/todo-ddd/pkg/domain/task"
+	"github.com/hoyci/todo-ddd/pkg/usecase"
+)
+
+type TaskActivityInput struct {
+	TaskID string
+	UserID string
+}
+
+type TaskActivityOutput struct {
+	Activity []domainTask.Activity
+}
+
+type TaskActivityUseCase struct {
+	TaskRepo    domainTask.TaskRepository
+	EventRepo   domainTask.EventRepository
+	CommentRepo domainTask.CommentRepository
+}
+
+// Execute monta o histórico também de tarefas excluídas; comentários
+// excluídos aparecem no lugar em que foram feitos.
+func (uc *TaskActivityUseCase) Execute(ctx context.Context, input TaskActivityInput) (_ *TaskActivityOutput, err error) {
+	ctx, end := usecase.Start(ctx, "task_activity")
+	defer end(&err)
+
+	task, err := uc.TaskRepo.FindByID(ctx, input.TaskID, input.UserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, usecase.ErrTaskNotFound
+		}
+		logger(ctx).Error("error trying to find task by id", "taskID", input.TaskID, "error", err)
+		return nil, err
+	}
+
+	events, err := uc.EventRepo.ListByTask(ctx, task.ID)
+	if err != nil {
+		logger(ctx).Error("error trying to list task events", "taskID", task.ID, "error", err)
+		return nil, err
+	}
+
+	comments, err := uc.CommentRepo.ListPage(ctx, domainTask.CommentPageQuery{
+		TaskID:         task.ID,
+		IncludeDeleted: true,
+	})
+	if err != nil {
+		logger(ctx).Error("error trying to list comments", "taskID", task.ID, "error", err)
+		return nil, err
+	}
+
+	return &TaskActivityOutput{Activity: domainTask.BuildActivity(events, comments)}, nil
+}
@@ -0,0 +1,264 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"slices"
+
+	"github.com/hoyci/todo-ddd/pkg/domain"
+	domainTask "github.com/hoyci/todo-ddd/pkg/domain/task"
+	"github.com/hoyci/todo-ddd/pkg/usecase"
+)
+
+//
+// ------------------- UPLOAD -------------------
+//
+
+type UploadAttachmentInput struct {
+	TaskID   string
+	UserID   string
+	FileName string
+	// ContentType é o tipo detectado a partir do conteúdo, não o informado
+	// pelo cliente.
+	ContentType string
+	Content     io.ReadSeeker
+}
+
+type UploadAttachmentOutput struct {
+	*domainTask.Attachment
+	// Deduplicated indica que o conteúdo já estava no BlobStore.
+	Deduplicated bool
+}
+
+type UploadAttachmentUseCase struct {
+	UoW   domain.UnitOfWork
+	Blobs domainTask.BlobStore
+	// MaxSize é o tamanho máximo do arquivo, em bytes.
+	MaxSize int64
+	// AllowedTypes são os tipos MIME aceitos, sem parâmetros.
+	AllowedTypes []string
+}
+
+func (uc *UploadAttachmentUseCase) Execute(ctx context.Context, input UploadAttachmentInput) (output *UploadAttachmentOutput, err error) {
+	ctx, end := usecase.Start(ctx, "upload_attachment")
+	defer end(&err)
+
+	mediaType, _, err := mime.ParseMediaType(input.ContentType)
+	if err != nil || !slices.Contains(uc.AllowedTypes, mediaType) {
+		return nil, fmt.Errorf("%w: %s", usecase.ErrAttachmentTypeNotAllowed, input.ContentType)
+	}
+
+	// O hash é calculado antes de gravar, para que conteúdo repetido não
+	// seja enviado de novo ao BlobStore.
+	hasher := sha256.New()
+	size, err := io.Copy(hasher, io.LimitReader(input.Content, uc.MaxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if size > uc.MaxSize {
+		return nil, fmt.Errorf("%w of %d bytes", usecase.ErrAttachmentTooLarge, uc.MaxSize)
+	}
+	if _, err := input.Content.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	hash := hex.EncodeToString(hasher.Sum(nil))
+
+	err = uc.UoW.Execute(ctx, func(ctx context.Context, work domain.Work) error {
+		task, err := findTaskWithAccess(ctx, work, input.TaskID, input.UserID, domainTask.AccessEdit)
+		if err != nil {
+			return err
+		}
+
+		attachment, err := domainTask.NewAttachment(task.ID, input.UserID, input.FileName, input.ContentType, size, hash)
+		if err != nil {
+			return err
+		}
+
+		refs, err := work.AttachmentRepo().CountByHash(ctx, hash)
+		if err != nil {
+			return err
+		}
+		if err := work.AttachmentRepo().Save(ctx, attachment); err != nil {
+			logger(ctx).Error("error trying to save attachment", "taskID", task.ID, "error", err)
+			return err
+		}
+		if err := usecase.Audit(ctx, work.AuditRepo(), "attachment.uploaded", "attachment", attachment.ID, nil, attachment); err != nil {
+			return err
+		}
+		// O blob é gravado dentro da transação: se falhar, o anexo não é
+		// registrado.
+		if refs == 0 {
+			if err := uc.Blobs.Put(ctx, attachment.BlobKey(), input.Content, size, input.ContentType); err != nil {
+				logger(ctx).Error("error trying to store attachment content", "taskID", task.ID, "error", err)
+				return err
+			}
+		}
+
+		output = &UploadAttachmentOutput{Attachment: attachment, Deduplicated: refs > 0}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+//
+// ------------------- LIST -------------------
+//
+
+type ListAttachmentsInput struct {
+	TaskID string
+	UserID string
+}
+
+type ListAttachmentsUseCase struct {
+	TaskRepo       domainTask.TaskRepository
+	AttachmentRepo domainTask.AttachmentRepository
+}
+
+func (uc *ListAttachmentsUseCase) Execute(ctx context.Context, input ListAttachmentsInput) (_ []*domainTask.Attachment, err error) {
+	ctx, end := usecase.Start(ctx, "list_attachments")
+	defer end(&err)
+
+	task, err := findActiveTask(ctx, uc.TaskRepo, input.TaskID, input.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	attachments, err := uc.AttachmentRepo.ListByTask(ctx, task.ID)
+	if err != nil {
+		logger(ctx).Error("error trying to list attachments", "taskID", task.ID, "error", err)
+		return nil, err
+	}
+	return attachments, nil
+}
+
+//
+// ------------------- DOWNLOAD -------------------
+//
+
+type OpenAttachmentInput struct {
+	TaskID       string
+	AttachmentID string
+	UserID       string
+}
+
+type OpenAttachmentOutput struct {
+	*domainTask.Attachment
+	// Content deve ser fechado por quem chamou.
+	Content io.ReadSeekCloser
+}
+
+type OpenAttachmentUseCase struct {
+	TaskRepo       domainTask.TaskRepository
+	AttachmentRepo domainTask.AttachmentRepository
+	Blobs          domainTask.BlobStore
+}
+
+func (uc *OpenAttachmentUseCase) Execute(ctx context.Context, input OpenAttachmentInput) (_ *OpenAttachmentOutput, err error) {
+	ctx, end := usecase.Start(ctx, "open_attachment")
+	defer end(&err)
+
+	task, err := findActiveTask(ctx, uc.TaskRepo, input.TaskID, input.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	attachment, err := findAttachment(ctx, uc.AttachmentRepo, input.AttachmentID, task.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := uc.Blobs.Open(ctx, attachment.BlobKey())
+	if err != nil {
+		logger(ctx).Error("error trying to open attachment content", "attachmentID", attachment.ID, "error", err)
+		return nil, err
+	}
+	return &OpenAttachmentOutput{Attachment: attachment, Content: content}, nil
+}
+
+//
+// ------------------- DELETE -------------------
+//
+
+type DeleteAttachmentInput struct {
+	TaskID       string
+	AttachmentID string
+	UserID       string
+}
+
+type DeleteAttachmentUseCase struct {
+	UoW   domain.UnitOfWork
+	Blobs domainTask.BlobStore
+}
+
+func (uc *DeleteAttachmentUseCase) Execute(ctx context.Context, input DeleteAttachmentInput) (err error) {
+	ctx, end := usecase.Start(ctx, "delete_attachment")
+	defer end(&err)
+
+	var orphan string
+	err = uc.UoW.Execute(ctx, func(ctx context.Context, work domain.Work) error {
+		task, err := findTaskWithAccess(ctx, work, input.TaskID, input.UserID, domainTask.AccessEdit)
+		if err != nil {
+			return err
+		}
+
+		attachment, err := findAttachment(ctx, work.AttachmentRepo(), input.AttachmentID, task.ID)
+		if err != nil {
+			return err
+		}
+
+		if err := work.AttachmentRepo().Delete(ctx, attachment.ID); err != nil {
+			logger(ctx).Error("error trying to delete attachment", "attachmentID", attachment.ID, "error", err)
+			return err
+		}
+		if err := usecase.Audit(ctx, work.AuditRepo(), "attachment.deleted", "attachment", attachment.ID, attachment, nil); err != nil {
+			return err
+		}
+
+		refs, err := work.AttachmentRepo().CountByHash(ctx, attachment.Hash)
+		if err != nil {
+			return err
+		}
+		if refs == 0 {
+			orphan = attachment.Hash
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if orphan != "" {
+		deleteBlobs(ctx, uc.Blobs, []string{orphan})
+	}
+	return nil
+}
+
+func findAttachment(ctx context.Context, repo domainTask.AttachmentRepository, attachmentID, taskID string) (*domainTask.Attachment, error) {
+	attachment, err := repo.FindByID(ctx, attachmentID, taskID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, usecase.ErrAttachmentNotFound
+		}
+		return nil, err
+	}
+	return attachment, nil
+}
+
+// deleteBlobs remove conteúdos que deixaram de ser referenciados. Roda
+// depois do commit; falhas só deixam blobs órfãos e não revertem nada.
+func deleteBlobs(ctx context.Context, blobs domainTask.BlobStore, hashes []string) {
+	for _, hash := range hashes {
+		if err := blobs.Delete(ctx, domainTask.BlobKey(hash)); err != nil {
+			logger(ctx).Error("error trying to delete attachment content", "hash", hash, "error", err)
+		}
+	}
+}
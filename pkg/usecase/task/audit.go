@@ -0,0 +1,15 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/hoyci/todo-ddd/pkg/domain"
+	domainTask "github.com/hoyci/todo-ddd/pkg/domain/task"
+	"github.com/hoyci/todo-ddd/pkg/usecase"
+)
+
+// auditTask registra a mudança da tarefa no log de auditoria. before é nil
+// na criação.
+func auditTask(ctx context.Context, work domain.Work, action string, before, after *domainTask.Task) error {
+	return usecase.Audit(ctx, work.AuditRepo(), action, "task", after.ID, before, after)
+}
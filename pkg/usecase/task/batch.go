@@ -0,0 +1,264 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/hoyci/todo-ddd/pkg/domain"
+	domainTask "github.com/hoyci/todo-ddd/pkg/domain/task"
+	domainUndo "github.com/hoyci/todo-ddd/pkg/domain/undo"
+	"github.com/hoyci/todo-ddd/pkg/domain/valueobject"
+	domainWorkspace "github.com/hoyci/todo-ddd/pkg/domain/workspace"
+	"github.com/hoyci/todo-ddd/pkg/usecase"
+)
+
+type BatchOperationType string
+
+const (
+	BatchOperationCreate BatchOperationType = "create"
+	BatchOperationUpdate BatchOperationType = "update"
+	BatchOperationStatus BatchOperationType = "status"
+	BatchOperationDelete BatchOperationType = "delete"
+)
+
+type BatchMode string
+
+const (
+	// BatchModeAllOrNothing desfaz todas as operações se qualquer uma falhar.
+	BatchModeAllOrNothing BatchMode = "all_or_nothing"
+	// BatchModeBestEffort aplica as operações válidas e reporta as que falharam.
+	BatchModeBestEffort BatchMode = "best_effort"
+)
+
+type BatchOperation struct {
+	Type        BatchOperationType
+	TaskID      string
+	Title       string
+	Description string
+	Priority    int
+	Status      string
+}
+
+type BatchTaskInput struct {
+	UserID     string
+	Mode       BatchMode
+	Operations []BatchOperation
+}
+
+type BatchOperationResult struct {
+	Type   BatchOperationType
+	TaskID string
+	Task   *domainTask.Task
+	Err    error
+}
+
+type BatchTaskOutput struct {
+	Committed bool
+	Results   []BatchOperationResult
+	// Undo desfaz de uma vez as operações aplicadas; nil se nenhuma foi
+	// aplicada ou se UndoWindow for zero.
+	Undo *domainUndo.Undo
+}
+
+type BatchTaskUseCase struct {
+	UoW domain.UnitOfWork
+	Options
+}
+
+var errBatchFailed = errors.New("batch failed")
+
+func (uc *BatchTaskUseCase) Execute(ctx context.Context, input BatchTaskInput) (output *BatchTaskOutput, err error) {
+	ctx, end := usecase.Start(ctx, "batch_tasks")
+	defer end(&err)
+
+	output = &BatchTaskOutput{Results: make([]BatchOperationResult, len(input.Operations))}
+
+	err = uc.UoW.Execute(ctx, func(ctx context.Context, work domain.Work) error {
+		user, err := activeUser(ctx, work, input.UserID)
+		if err != nil {
+			return err
+		}
+
+		failed := false
+		var steps []domainUndo.Step
+		for i, op := range input.Operations {
+			result := BatchOperationResult{Type: op.Type, TaskID: op.TaskID}
+			if failed && input.Mode == BatchModeAllOrNothing {
+				result.Err = usecase.ErrBatchAborted
+				output.Results[i] = result
+				continue
+			}
+
+			// Cada operação roda em um savepoint: uma que falhe no meio não
+			// deixa gravações parciais no modo best_effort.
+			var step domainUndo.Step
+			result.Err = work.Savepoint(ctx, func(ctx context.Context) error {
+				if op.Type == BatchOperationCreate {
+					if err := uc.authorizeCreate(ctx, work, user); err != nil {
+						return err
+					}
+				}
+				var err error
+				result.Task, step, err = applyBatchOperation(ctx, work, user.ID, op)
+				return err
+			})
+			if result.Task != nil {
+				result.TaskID = result.Task.ID
+			}
+			if result.Err != nil {
+				failed = true
+				logger(ctx).Warn("batch operation failed", "index", i, "type", op.Type, "taskID", op.TaskID, "error", result.Err)
+			} else {
+				steps = append(steps, step)
+			}
+			output.Results[i] = result
+		}
+
+		if failed && input.Mode == BatchModeAllOrNothing {
+			return errBatchFailed
+		}
+		undo, err := issueUndo(ctx, work, uc.UndoWindow, user.ID, "task.batch", steps)
+		if err != nil {
+			return err
+		}
+		output.Undo = undo
+		return nil
+	})
+
+	switch {
+	case errors.Is(err, errBatchFailed):
+		// As operações bem-sucedidas foram desfeitas junto com a transação.
+		for i := range output.Results {
+			if output.Results[i].Err == nil {
+				output.Results[i].Err = usecase.ErrBatchAborted
+				output.Results[i].Task = nil
+			}
+			if output.Results[i].Type == BatchOperationCreate {
+				output.Results[i].TaskID = ""
+			}
+		}
+		return output, nil
+	case err != nil:
+		return nil, err
+	}
+
+	output.Committed = true
+	return output, nil
+}
+
+// applyBatchOperation aplica a operação e devolve a tarefa resultante e o
+// passo que a desfaz.
+func applyBatchOperation(ctx context.Context, work domain.Work, userID string, op BatchOperation) (*domainTask.Task, domainUndo.Step, error) {
+	task, before, err := applyBatchChange(ctx, work, userID, op)
+	if err != nil {
+		return nil, domainUndo.Step{}, err
+	}
+	if err := recordEvents(ctx, work, task); err != nil {
+		return nil, domainUndo.Step{}, err
+	}
+	if err := auditTask(ctx, work, batchAuditActions[op.Type], before, task); err != nil {
+		return nil, domainUndo.Step{}, err
+	}
+	revision, err := recordRevision(ctx, work, task, userID)
+	if err != nil {
+		return nil, domainUndo.Step{}, err
+	}
+	step, err := stepFor(ctx, work, task, op.Type == BatchOperationCreate, revision)
+	if err != nil {
+		return nil, domainUndo.Step{}, err
+	}
+	return task, step, nil
+}
+
+var batchAuditActions = map[BatchOperationType]string{
+	BatchOperationCreate: "task.created",
+	BatchOperationUpdate: "task.updated",
+	BatchOperationStatus: "task.status_changed",
+	BatchOperationDelete: "task.deleted",
+}
+
+// applyBatchChange aplica a operação e devolve a tarefa resultante e, exceto
+// na criação, uma cópia dela antes da mudança.
+func applyBatchChange(ctx context.Context, work domain.Work, userID string, op BatchOperation) (*domainTask.Task, *domainTask.Task, error) {
+	repo := work.TaskRepo()
+	switch op.Type {
+	case BatchOperationCreate:
+		priority, err := valueobject.NewPriority(op.Priority)
+		if err != nil {
+			return nil, nil, err
+		}
+		task, err := domainTask.NewTask(op.Title, op.Description, userID, priority,
+			domainTask.InWorkspace(domainWorkspace.ScopeFrom(ctx)))
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := repo.Save(ctx, task); err != nil {
+			return nil, nil, usecase.ErrTaskSaveFailed
+		}
+		return task, nil, nil
+
+	case BatchOperationUpdate:
+		priority, err := valueobject.NewPriority(op.Priority)
+		if err != nil {
+			return nil, nil, err
+		}
+		task, err := findTaskWithAccess(ctx, work, op.TaskID, userID, domainTask.AccessEdit)
+		if err != nil {
+			return nil, nil, err
+		}
+		before := *task
+		if err := task.Update(op.Title, op.Description, priority); err != nil {
+			return nil, nil, err
+		}
+		if err := repo.Update(ctx, task); err != nil {
+			return nil, nil, err
+		}
+		return task, &before, nil
+
+	case BatchOperationStatus:
+		status, err := valueobject.NewStatus(op.Status)
+		if err != nil {
+			return nil, nil, err
+		}
+		task, err := findTaskWithAccess(ctx, work, op.TaskID, userID, domainTask.AccessEdit)
+		if err != nil {
+			return nil, nil, err
+		}
+		before := *task
+		applyStatus(task, status)
+		if err := repo.Update(ctx, task); err != nil {
+			return nil, nil, err
+		}
+		return task, &before, nil
+
+	case BatchOperationDelete:
+		task, err := findTaskWithAccess(ctx, work, op.TaskID, userID, domainTask.AccessOwner)
+		if err != nil {
+			return nil, nil, err
+		}
+		before := *task
+		task.Delete()
+		if err := repo.Delete(ctx, task.ID, *task.DeletedAt); err != nil {
+			return nil, nil, err
+		}
+		return task, &before, nil
+
+	default:
+		return nil, nil, usecase.ErrInvalidBatchOperation
+	}
+}
+
+func findActiveTask(ctx context.Context, repo domainTask.TaskRepository, taskID, userID string) (*domainTask.Task, error) {
+	task, err := repo.FindByID(ctx, taskID, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, usecase.ErrTaskNotFound
+		}
+		return nil, err
+	}
+	if task.DeletedAt != nil {
+		return nil, usecase.ErrTaskNotFound
+	}
+	return task, nil
+}
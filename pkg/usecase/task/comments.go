@@ -0,0 +1,244 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/hoyci/todo-ddd/pkg/domain"
+	domainTask "github.com/hoyci/todo-ddd/pkg/domain/task"
+	"github.com/hoyci/todo-ddd/pkg/usecase"
+)
+
+//
+// ------------------- ADD -------------------
+//
+
+type AddCommentInput struct {
+	TaskID string
+	UserID string
+	Body   string
+}
+
+type AddCommentOutput struct {
+	*domainTask.Comment
+}
+
+type AddCommentUseCase struct {
+	UoW domain.UnitOfWork
+}
+
+func (uc *AddCommentUseCase) Execute(ctx context.Context, input AddCommentInput) (output *AddCommentOutput, err error) {
+	ctx, end := usecase.Start(ctx, "add_comment")
+	defer end(&err)
+
+	err = uc.UoW.Execute(ctx, func(ctx context.Context, work domain.Work) error {
+		task, err := findActiveTask(ctx, work.TaskRepo(), input.TaskID, input.UserID)
+		if err != nil {
+			return err
+		}
+
+		comment, err := domainTask.NewComment(task.ID, input.UserID, input.Body)
+		if err != nil {
+			return err
+		}
+
+		if err := work.CommentRepo().Save(ctx, comment); err != nil {
+			logger(ctx).Error("error trying to save comment", "taskID", task.ID, "error", err)
+			return err
+		}
+		if err := usecase.Audit(ctx, work.AuditRepo(), "comment.added", "comment", comment.ID, nil, comment); err != nil {
+			return err
+		}
+		if err := notifyComment(ctx, work, task, comment); err != nil {
+			return err
+		}
+
+		output = &AddCommentOutput{comment}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+//
+// ------------------- EDIT -------------------
+//
+
+type EditCommentInput struct {
+	TaskID    string
+	CommentID string
+	UserID    string
+	Body      string
+}
+
+type EditCommentOutput struct {
+	*domainTask.Comment
+}
+
+type EditCommentUseCase struct {
+	UoW domain.UnitOfWork
+}
+
+func (uc *EditCommentUseCase) Execute(ctx context.Context, input EditCommentInput) (output *EditCommentOutput, err error) {
+	ctx, end := usecase.Start(ctx, "edit_comment")
+	defer end(&err)
+
+	err = uc.UoW.Execute(ctx, func(ctx context.Context, work domain.Work) error {
+		comment, err := findAuthoredComment(ctx, work, input.TaskID, input.CommentID, input.UserID)
+		if err != nil {
+			return err
+		}
+
+		before := *comment
+		changed, err := comment.Edit(input.Body)
+		if err != nil {
+			return err
+		}
+		if changed {
+			if err := work.CommentRepo().Update(ctx, comment); err != nil {
+				logger(ctx).Error("error trying to edit comment", "commentID", comment.ID, "error", err)
+				return err
+			}
+			if err := usecase.Audit(ctx, work.AuditRepo(), "comment.edited", "comment", comment.ID, &before, comment); err != nil {
+				return err
+			}
+		}
+
+		output = &EditCommentOutput{comment}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+//
+// ------------------- DELETE -------------------
+//
+
+type DeleteCommentInput struct {
+	TaskID    string
+	CommentID string
+	UserID    string
+}
+
+type DeleteCommentUseCase struct {
+	UoW domain.UnitOfWork
+}
+
+func (uc *DeleteCommentUseCase) Execute(ctx context.Context, input DeleteCommentInput) (err error) {
+	ctx, end := usecase.Start(ctx, "delete_comment")
+	defer end(&err)
+
+	return uc.UoW.Execute(ctx, func(ctx context.Context, work domain.Work) error {
+		comment, err := findAuthoredComment(ctx, work, input.TaskID, input.CommentID, input.UserID)
+		if err != nil {
+			return err
+		}
+
+		before := *comment
+		comment.Delete()
+
+		if err := work.CommentRepo().Update(ctx, comment); err != nil {
+			logger(ctx).Error("error trying to delete comment", "commentID", comment.ID, "error", err)
+			return err
+		}
+		return usecase.Audit(ctx, work.AuditRepo(), "comment.deleted", "comment", comment.ID, &before, comment)
+	})
+}
+
+// findAuthoredComment busca um comentário ativo de uma tarefa ativa do
+// usuário, exigindo que ele seja o autor.
+func findAuthoredComment(ctx context.Context, work domain.Work, taskID, commentID, userID string) (*domainTask.Comment, error) {
+	task, err := findActiveTask(ctx, work.TaskRepo(), taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	comment, err := findActiveComment(ctx, work.CommentRepo(), commentID, task.ID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.AuthorID != userID {
+		return nil, usecase.ErrNotCommentAuthor
+	}
+	return comment, nil
+}
+
+func findActiveComment(ctx context.Context, repo domainTask.CommentRepository, commentID, taskID string) (*domainTask.Comment, error) {
+	comment, err := repo.FindByID(ctx, commentID, taskID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, usecase.ErrCommentNotFound
+		}
+		return nil, err
+	}
+	if comment.DeletedAt != nil {
+		return nil, usecase.ErrCommentNotFound
+	}
+	return comment, nil
+}
+
+//
+// ------------------- LIST -------------------
+//
+
+type ListCommentsInput struct {
+	TaskID string
+	UserID string
+	// After é o ID do último comentário da página anterior.
+	After string
+	Limit int
+}
+
+type ListCommentsOutput struct {
+	Comments []*domainTask.Comment
+	// NextCursor é vazio na última página.
+	NextCursor string
+}
+
+type ListCommentsUseCase struct {
+	TaskRepo    domainTask.TaskRepository
+	CommentRepo domainTask.CommentRepository
+}
+
+func (uc *ListCommentsUseCase) Execute(ctx context.Context, input ListCommentsInput) (_ *ListCommentsOutput, err error) {
+	ctx, end := usecase.Start(ctx, "list_comments")
+	defer end(&err)
+
+	task, err := findActiveTask(ctx, uc.TaskRepo, input.TaskID, input.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.After != "" {
+		if _, err := findActiveComment(ctx, uc.CommentRepo, input.After, task.ID); err != nil {
+			if errors.Is(err, usecase.ErrCommentNotFound) {
+				return nil, usecase.ErrInvalidCursor
+			}
+			return nil, err
+		}
+	}
+
+	// Um comentário a mais indica se existe próxima página.
+	comments, err := uc.CommentRepo.ListPage(ctx, domainTask.CommentPageQuery{
+		TaskID:  task.ID,
+		AfterID: input.After,
+		Limit:   input.Limit + 1,
+	})
+	if err != nil {
+		logger(ctx).Error("error trying to list comments", "taskID", task.ID, "error", err)
+		return nil, err
+	}
+
+	output := &ListCommentsOutput{Comments: comments}
+	if len(comments) > input.Limit {
+		output.Comments = comments[:input.Limit]
+		output.NextCursor = output.Comments[input.Limit-1].ID
+	}
+	return output, nil
+}
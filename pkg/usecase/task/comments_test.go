@@ -0,0 +1,102 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hoyci/todo-ddd/internal/adapters/db/sqlite"
+	"github.com/hoyci/todo-ddd/internal/adapters/events"
+	domainTask "github.com/hoyci/todo-ddd/pkg/domain/task"
+	"github.com/hoyci/todo-ddd/pkg/usecase"
+)
+
+func TestComments(t *testing.T) {
+	db := newTestDB(t)
+	uow := sqlite.NewSQLiteUnitOfWork(db, events.NewBroker(0))
+	ctx := context.Background()
+	owner := saveTestUser(t, uow, "owner@example.com", true)
+	viewer := saveTestUser(t, uow, "viewer@example.com", true)
+	stranger := saveTestUser(t, uow, "stranger@example.com", true)
+
+	task, err := (&CreateTaskUseCase{UoW: uow}).Execute(ctx, CreateTaskInput{Title: "Task", Priority: 1, UserID: owner})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := (&ShareTaskUseCase{UoW: uow}).Execute(ctx, ShareTaskInput{TaskID: task.ID, UserID: owner, TargetUserID: viewer, Permission: "view"}); err != nil {
+		t.Fatal(err)
+	}
+
+	add := &AddCommentUseCase{UoW: uow}
+	var ids []string
+	for _, body := range []string{"first", "second", "third"} {
+		out, err := add.Execute(ctx, AddCommentInput{TaskID: task.ID, UserID: owner, Body: body})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, out.ID)
+	}
+	if _, err := add.Execute(ctx, AddCommentInput{TaskID: task.ID, UserID: stranger, Body: "hi"}); !errors.Is(err, usecase.ErrTaskNotFound) {
+		t.Errorf("comment by a user without access: error = %v", err)
+	}
+
+	edit := &EditCommentUseCase{UoW: uow}
+	editTests := []struct {
+		name    string
+		userID  string
+		id      string
+		body    string
+		wantErr error
+	}{
+		{"author edits", owner, ids[0], "first, edited", nil},
+		{"viewer is not the author", viewer, ids[0], "mine now", usecase.ErrNotCommentAuthor},
+		{"stranger cannot see the task", stranger, ids[0], "mine now", usecase.ErrTaskNotFound},
+		{"unknown comment", owner, "missing", "x", usecase.ErrCommentNotFound},
+	}
+	for _, tt := range editTests {
+		if _, err := edit.Execute(ctx, EditCommentInput{TaskID: task.ID, CommentID: tt.id, UserID: tt.userID, Body: tt.body}); !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: error = %v, want %v", tt.name, err, tt.wantErr)
+		}
+	}
+
+	if err := (&DeleteCommentUseCase{UoW: uow}).Execute(ctx, DeleteCommentInput{TaskID: task.ID, CommentID: ids[1], UserID: owner}); err != nil {
+		t.Fatal(err)
+	}
+
+	list := &ListCommentsUseCase{TaskRepo: sqlite.NewSQLiteTaskRepository(db), CommentRepo: sqlite.NewSQLiteCommentRepository(db)}
+	page, err := list.Execute(ctx, ListCommentsInput{TaskID: task.ID, UserID: viewer, Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Comments) != 1 || page.Comments[0].Body != "first, edited" || len(page.Comments[0].History) != 1 || page.NextCursor != ids[0] {
+		t.Fatalf("first page = %+v", page)
+	}
+	page, err = list.Execute(ctx, ListCommentsInput{TaskID: task.ID, UserID: viewer, After: page.NextCursor, Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Comments) != 1 || page.Comments[0].ID != ids[2] || page.NextCursor != "" {
+		t.Fatalf("second page = %+v, want only the third comment", page)
+	}
+	if _, err := list.Execute(ctx, ListCommentsInput{TaskID: task.ID, UserID: viewer, After: ids[1], Limit: 1}); !errors.Is(err, usecase.ErrInvalidCursor) {
+		t.Errorf("cursor on a deleted comment: error = %v", err)
+	}
+
+	activity, err := (&TaskActivityUseCase{
+		TaskRepo:    sqlite.NewSQLiteTaskRepository(db),
+		EventRepo:   sqlite.NewSQLiteTaskEventRepository(db),
+		CommentRepo: sqlite.NewSQLiteCommentRepository(db),
+	}).Execute(ctx, TaskActivityInput{TaskID: task.ID, UserID: owner})
+	if err != nil {
+		t.Fatal(err)
+	}
+	comments := 0
+	for _, entry := range activity.Activity {
+		if entry.Kind == domainTask.ActivityComment {
+			comments++
+		}
+	}
+	if first := activity.Activity[0].Kind; first != domainTask.ActivityTaskCreated || comments != 3 {
+		t.Errorf("activity starts with %s and has %d comments, want task_created and 3 (deleted included)", first, comments)
+	}
+}
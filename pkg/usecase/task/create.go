@@ -4,9 +4,13 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"time"
 
 	"github.com/hoyci/todo-ddd/pkg/domain"
 	domainTask "github.com/hoyci/todo-ddd/pkg/domain/task"
+	domainUndo "github.com/hoyci/todo-ddd/pkg/domain/undo"
+	domainUser "github.com/hoyci/todo-ddd/pkg/domain/user"
+	domainWorkspace "github.com/hoyci/todo-ddd/pkg/domain/workspace"
 
 	"github.com/hoyci/todo-ddd/pkg/domain/valueobject"
 	"github.com/hoyci/todo-ddd/pkg/usecase"
@@ -17,44 +21,40 @@ type CreateTaskInput struct {
 	Description string
 	Priority    valueobject.Priority
 	UserID      string
+	DueAt       *time.Time
+	Tags        []valueobject.Tag
+	Recurrence  valueobject.Recurrence
 }
 
 type CreateTaskOutput struct {
 	*domainTask.Task
+	// Undo desfaz a criação; nil se UndoWindow for zero.
+	Undo *domainUndo.Undo
 }
 
 type CreateTaskUseCase struct {
 	UoW domain.UnitOfWork
+	Options
 }
 
-func (uc *CreateTaskUseCase) Execute(input CreateTaskInput) (*CreateTaskOutput, error) {
-	var output *CreateTaskOutput
-	err := uc.UoW.Execute(context.Background(), func(work domain.Work) error {
-		userRepo := work.UserRepo()
-		taskRepo := work.TaskRepo()
+func (uc *CreateTaskUseCase) Execute(ctx context.Context, input CreateTaskInput) (output *CreateTaskOutput, err error) {
+	ctx, end := usecase.Start(ctx, "create_task")
+	defer end(&err)
 
-		user, err := userRepo.FindByID(input.UserID)
+	err = uc.UoW.Execute(ctx, func(ctx context.Context, work domain.Work) error {
+		user, err := uc.taskCreator(ctx, work, input.UserID)
 		if err != nil {
-			if errors.Is(err, sql.ErrNoRows) {
-				return usecase.ErrUserNotFoundOrDeleted
-			}
 			return err
 		}
-
-		if user.DeletedAt != nil {
-			return usecase.ErrUserNotFoundOrDeleted
-		}
-
-		task, err := domainTask.NewTask(input.Title, input.Description, user.ID, input.Priority)
+		task, revision, err := createTask(ctx, work, user, input)
 		if err != nil {
 			return err
 		}
-
-		if err := taskRepo.Save(task); err != nil {
-			return usecase.ErrTaskSaveFailed
+		undo, err := issueUndo(ctx, work, uc.UndoWindow, input.UserID, "task.created", []domainUndo.Step{deleteStep(revision)})
+		if err != nil {
+			return err
 		}
-
-		output = &CreateTaskOutput{task}
+		output = &CreateTaskOutput{Task: task, Undo: undo}
 		return nil
 	})
 	if err != nil {
@@ -62,3 +62,53 @@ func (uc *CreateTaskUseCase) Execute(input CreateTaskInput) (*CreateTaskOutput,
 	}
 	return output, nil
 }
+
+// createTask cria e grava a tarefa no workspace do contexto, dentro da
+// unidade de trabalho; também usada pela criação rápida. Devolve também a
+// primeira revisão da tarefa.
+func createTask(ctx context.Context, work domain.Work, user *domainUser.User, input CreateTaskInput) (*domainTask.Task, *domainTask.Revision, error) {
+	opts := []domainTask.Option{domainTask.InWorkspace(domainWorkspace.ScopeFrom(ctx))}
+	if input.DueAt != nil {
+		opts = append(opts, domainTask.WithDueAt(*input.DueAt))
+	}
+	if len(input.Tags) > 0 {
+		opts = append(opts, domainTask.WithTags(input.Tags...))
+	}
+	if !input.Recurrence.IsZero() {
+		opts = append(opts, domainTask.WithRecurrence(input.Recurrence))
+	}
+
+	task, err := domainTask.NewTask(input.Title, input.Description, user.ID, input.Priority, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := work.TaskRepo().Save(ctx, task); err != nil {
+		return nil, nil, usecase.ErrTaskSaveFailed
+	}
+	if err := recordEvents(ctx, work, task); err != nil {
+		return nil, nil, err
+	}
+	if err := auditTask(ctx, work, "task.created", nil, task); err != nil {
+		return nil, nil, err
+	}
+	revision, err := recordRevision(ctx, work, task, user.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return task, revision, nil
+}
+
+func activeUser(ctx context.Context, work domain.Work, userID string) (*domainUser.User, error) {
+	user, err := work.UserRepo().FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, usecase.ErrUserNotFoundOrDeleted
+		}
+		return nil, err
+	}
+	if user.DeletedAt != nil {
+		return nil, usecase.ErrUserNotFoundOrDeleted
+	}
+	return user, nil
+}
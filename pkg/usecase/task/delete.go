@@ -1,9 +1,12 @@
 package usecase
 
 import (
-	"log/slog"
+	"context"
 
-	domain "github.com/hoyci/todo-ddd/pkg/domain/task"
+	"github.com/hoyci/todo-ddd/pkg/domain"
+	domainTask "github.com/hoyci/todo-ddd/pkg/domain/task"
+	domainUndo "github.com/hoyci/todo-ddd/pkg/domain/undo"
+	"github.com/hoyci/todo-ddd/pkg/usecase"
 )
 
 type DeleteTaskInput struct {
@@ -13,25 +16,52 @@ type DeleteTaskInput struct {
 
 type DeleteTaskOutput struct {
 	ID string
+	// Undo restaura a tarefa; nil se UndoWindow for zero.
+	Undo *domainUndo.Undo
 }
 
 type DeleteTaskUseCase struct {
-	TaskRepo domain.TaskRepository
+	UoW domain.UnitOfWork
+	Options
 }
 
-func (uc *DeleteTaskUseCase) Execute(input DeleteTaskInput) (*DeleteTaskOutput, error) {
-	task, err := uc.TaskRepo.FindByID(input.TaskID, input.UserID)
-	if err != nil {
-		slog.Error("error trying to find task by id", "taskID", input.TaskID)
-		return nil, err
-	}
+func (uc *DeleteTaskUseCase) Execute(ctx context.Context, input DeleteTaskInput) (output *DeleteTaskOutput, err error) {
+	ctx, end := usecase.Start(ctx, "delete_task")
+	defer end(&err)
+
+	err = uc.UoW.Execute(ctx, func(ctx context.Context, work domain.Work) error {
+		task, err := findTaskWithAccess(ctx, work, input.TaskID, input.UserID, domainTask.AccessOwner)
+		if err != nil {
+			return err
+		}
+
+		before := *task
+		task.Delete()
 
-	task.Delete()
+		if err := work.TaskRepo().Delete(ctx, task.ID, *task.DeletedAt); err != nil {
+			logger(ctx).Error("error trying to delete task by id", "taskID", task.ID, "error", err)
+			return err
+		}
+		if err := recordEvents(ctx, work, task); err != nil {
+			return err
+		}
+		if err := auditTask(ctx, work, "task.deleted", &before, task); err != nil {
+			return err
+		}
+		step, err := undeleteStep(ctx, work, task.ID)
+		if err != nil {
+			return err
+		}
+		undo, err := issueUndo(ctx, work, uc.UndoWindow, input.UserID, "task.deleted", []domainUndo.Step{step})
+		if err != nil {
+			return err
+		}
 
-	err = uc.TaskRepo.Delete(task.ID, *task.DeletedAt)
+		output = &DeleteTaskOutput{ID: task.ID, Undo: undo}
+		return nil
+	})
 	if err != nil {
-		slog.Error("error trying to delete task by id", "taskID", task.ID)
 		return nil, err
 	}
-	return &DeleteTaskOutput{ID: task.ID}, nil
+	return output, nil
 }
@@ -0,0 +1,113 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/hoyci/todo-ddd/pkg/domain"
+	domainTask "github.com/hoyci/todo-ddd/pkg/domain/task"
+	"github.com/hoyci/todo-ddd/pkg/usecase"
+)
+
+// recordEvents grava no log, dentro da transação corrente, os eventos
+// pendentes da tarefa, marcando quem a acompanha para ser avisado.
+func recordEvents(ctx context.Context, work domain.Work, task *domainTask.Task) error {
+	events := task.PullEvents()
+	if len(events) == 0 {
+		return nil
+	}
+
+	watchers, err := work.WatcherRepo().ListByTask(ctx, task.ID)
+	if err != nil {
+		return err
+	}
+	recipients := make([]string, 0, len(watchers)+1)
+	for _, watcher := range watchers {
+		recipients = append(recipients, watcher.UserID)
+	}
+	// Responsável e quem recebeu a tarefa compartilhada também podem
+	// assinar o canal dela.
+	if task.AssigneeID != "" {
+		recipients = append(recipients, task.AssigneeID)
+	}
+	shares, err := work.ShareRepo().ListByTask(ctx, task.ID)
+	if err != nil {
+		return err
+	}
+	for _, share := range shares {
+		recipients = append(recipients, share.UserID)
+	}
+	if task.WorkspaceID != "" {
+		members, err := work.WorkspaceRepo().ListMembers(ctx, task.WorkspaceID)
+		if err != nil {
+			return err
+		}
+		for _, member := range members {
+			recipients = append(recipients, member.UserID)
+		}
+	}
+	for i := range events {
+		events[i].WatcherIDs = recipients
+	}
+
+	if err := work.TaskEventRepo().Append(ctx, events); err != nil {
+		logger(ctx).Error("error trying to record task events", "taskID", task.ID, "error", err)
+		return err
+	}
+	return nil
+}
+
+type ListTaskEventsInput struct {
+	UserID string
+	// TaskID, quando informado, restringe os eventos aos dessa tarefa, que
+	// UserID precisa enxergar.
+	TaskID string
+	// AfterID é o último evento já recebido pelo cliente. Quando negativo,
+	// nenhum evento é devolvido e LastID indica o evento mais recente.
+	AfterID int64
+	Limit   int
+}
+
+type ListTaskEventsOutput struct {
+	Events []domainTask.Event
+	LastID int64
+}
+
+type ListTaskEventsUseCase struct {
+	EventRepo domainTask.EventRepository
+}
+
+func (uc *ListTaskEventsUseCase) Execute(ctx context.Context, input ListTaskEventsInput) (_ *ListTaskEventsOutput, err error) {
+	ctx, end := usecase.Start(ctx, "list_task_events")
+	defer end(&err)
+
+	if input.AfterID < 0 {
+		var lastID int64
+		if input.TaskID != "" {
+			lastID, err = uc.EventRepo.LastIDByTask(ctx, input.TaskID, input.UserID)
+		} else {
+			lastID, err = uc.EventRepo.LastID(ctx, input.UserID)
+		}
+		if err != nil {
+			logger(ctx).Error("error trying to find last task event", "userID", input.UserID, "error", err)
+			return nil, err
+		}
+		return &ListTaskEventsOutput{LastID: lastID}, nil
+	}
+
+	var events []domainTask.Event
+	if input.TaskID != "" {
+		events, err = uc.EventRepo.ListByTaskAfter(ctx, input.TaskID, input.UserID, input.AfterID, input.Limit)
+	} else {
+		events, err = uc.EventRepo.ListAfter(ctx, input.UserID, input.AfterID, input.Limit)
+	}
+	if err != nil {
+		logger(ctx).Error("error trying to list task events", "userID", input.UserID, "error", err)
+		return nil, err
+	}
+
+	output := &ListTaskEventsOutput{Events: events, LastID: input.AfterID}
+	if len(events) > 0 {
+		output.LastID = events[len(events)-1].ID
+	}
+	return output, nil
+}
@@ -0,0 +1,129 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hoyci/todo-ddd/internal/adapters/db/sqlite"
+	"github.com/hoyci/todo-ddd/internal/adapters/events"
+	"github.com/hoyci/todo-ddd/pkg/domain"
+	"github.com/hoyci/todo-ddd/pkg/domain/valueobject"
+	domainWorkspace "github.com/hoyci/todo-ddd/pkg/domain/workspace"
+)
+
+// TestEventFeedMatchesBroker confere que cada usuário avisado pelo broker
+// encontra o evento no log, e que quem não é avisado não o encontra.
+func TestEventFeedMatchesBroker(t *testing.T) {
+	db := newTestDB(t)
+	broker := events.NewBroker(0)
+	uow := sqlite.NewSQLiteUnitOfWork(db, broker)
+	list := &ListTaskEventsUseCase{EventRepo: sqlite.NewSQLiteTaskEventRepository(db)}
+	ctx := context.Background()
+
+	owner := saveTestUser(t, uow, "owner@example.com", true)
+	watcher := saveTestUser(t, uow, "watcher@example.com", true)
+	assignee := saveTestUser(t, uow, "assignee@example.com", true)
+	recipient := saveTestUser(t, uow, "recipient@example.com", true)
+	member := saveTestUser(t, uow, "member@example.com", true)
+	stranger := saveTestUser(t, uow, "stranger@example.com", true)
+
+	workspace, ownerMember, err := domainWorkspace.NewWorkspace("Team", owner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = uow.Execute(ctx, func(ctx context.Context, work domain.Work) error {
+		if err := work.WorkspaceRepo().Save(ctx, workspace); err != nil {
+			return err
+		}
+		if err := work.WorkspaceRepo().SaveMember(ctx, ownerMember); err != nil {
+			return err
+		}
+		return work.WorkspaceRepo().SaveMember(ctx, &domainWorkspace.Member{WorkspaceID: workspace.ID, UserID: member, Role: valueobject.RoleMember, JoinedAt: time.Now()})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	personal, err := (&CreateTaskUseCase{UoW: uow}).Execute(ctx, CreateTaskInput{Title: "Personal", Priority: 1, UserID: owner})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := (&ShareTaskUseCase{UoW: uow}).Execute(ctx, ShareTaskInput{TaskID: personal.ID, UserID: owner, TargetUserID: watcher, Permission: "view"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := (&WatchTaskUseCase{UoW: uow}).Execute(ctx, WatchTaskInput{TaskID: personal.ID, UserID: watcher}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := (&ShareTaskUseCase{UoW: uow}).Execute(ctx, ShareTaskInput{TaskID: personal.ID, UserID: owner, TargetUserID: recipient, Permission: "view"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := (&AssignTaskUseCase{UoW: uow}).Execute(ctx, AssignTaskInput{TaskID: personal.ID, UserID: owner, AssigneeID: assignee}); err != nil {
+		t.Fatal(err)
+	}
+
+	scoped := domainWorkspace.WithScope(ctx, workspace.ID)
+	shared, err := (&CreateTaskUseCase{UoW: uow}).Execute(scoped, CreateTaskInput{Title: "Shared", Priority: 1, UserID: owner})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := map[string]string{owner: "owner", watcher: "watcher", assignee: "assignee", recipient: "recipient", member: "member", stranger: "stranger"}
+	tests := []struct {
+		name  string
+		ctx   context.Context
+		input UpdateTaskInput
+		want  map[string]bool
+	}{
+		{"personal task", ctx, UpdateTaskInput{TaskID: personal.ID, Title: "Renamed", Priority: 2, UserID: owner},
+			map[string]bool{owner: true, watcher: true, assignee: true, recipient: true, member: false, stranger: false}},
+		{"workspace task", scoped, UpdateTaskInput{TaskID: shared.ID, Title: "Renamed", Priority: 2, UserID: owner},
+			map[string]bool{owner: true, member: true, watcher: false, assignee: false, recipient: false, stranger: false}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			subs := make(map[string]*events.Subscription)
+			lastIDs := make(map[string]int64)
+			for user := range tt.want {
+				sub, err := broker.Subscribe(user)
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer sub.Close()
+				subs[user] = sub
+
+				out, err := list.Execute(tt.ctx, ListTaskEventsInput{UserID: user, AfterID: -1})
+				if err != nil {
+					t.Fatal(err)
+				}
+				lastIDs[user] = out.LastID
+			}
+
+			if _, err := (&UpdateTaskUseCase{UoW: uow}).Execute(tt.ctx, tt.input); err != nil {
+				t.Fatal(err)
+			}
+
+			for user, want := range tt.want {
+				var notified bool
+				select {
+				case <-subs[user].C():
+					notified = true
+				default:
+				}
+				out, err := list.Execute(tt.ctx, ListTaskEventsInput{UserID: user, AfterID: lastIDs[user], Limit: 10})
+				if err != nil {
+					t.Fatal(err)
+				}
+				last, err := list.Execute(tt.ctx, ListTaskEventsInput{UserID: user, AfterID: -1})
+				if err != nil {
+					t.Fatal(err)
+				}
+				listed := len(out.Events) == 1 && out.Events[0].TaskID == tt.input.TaskID
+				if notified != want || listed != want || (last.LastID > lastIDs[user]) != want {
+					t.Errorf("%s: notified = %v, listed = %v (%d events), LastID moved = %v; want %v",
+						names[user], notified, listed, len(out.Events), last.LastID > lastIDs[user], want)
+				}
+			}
+		})
+	}
+}
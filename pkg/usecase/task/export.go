@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"context"
+
+	domainTask "github.com/hoyci/todo-ddd/pkg/domain/task"
+	"github.com/hoyci/todo-ddd/pkg/usecase"
+)
+
+// exportPageSize é quantas tarefas são lidas do repositório por vez; a
+// exportação nunca mantém mais do que isso em memória.
+const exportPageSize = 500
+
+// TaskEncoder escreve as tarefas exportadas em um formato (CSV, JSON,
+// iCalendar...). Close finaliza o documento e descarrega o que estiver em
+// buffer.
+type TaskEncoder interface {
+	Encode(task *domainTask.Task) error
+	Close() error
+}
+
+type ExportTasksInput struct {
+	UserID         string
+	IncludeDeleted bool
+	Encoder        TaskEncoder
+}
+
+type ExportTasksOutput struct {
+	Count int
+}
+
+type ExportTasksUseCase struct {
+	TaskRepo domainTask.TaskRepository
+}
+
+func (uc *ExportTasksUseCase) Execute(ctx context.Context, input ExportTasksInput) (_ *ExportTasksOutput, err error) {
+	ctx, end := usecase.Start(ctx, "export_tasks")
+	defer end(&err)
+
+	output := &ExportTasksOutput{}
+	query := domainTask.TaskPageQuery{
+		UserID:         input.UserID,
+		IncludeDeleted: input.IncludeDeleted,
+		Limit:          exportPageSize,
+	}
+	for {
+		tasks, err := uc.TaskRepo.ListPage(ctx, query)
+		if err != nil {
+			logger(ctx).Error("error listing tasks to export", "error", err)
+			return nil, err
+		}
+
+		for _, t := range tasks {
+			if err := input.Encoder.Encode(t); err != nil {
+				return nil, err
+			}
+			output.Count++
+		}
+		if len(tasks) < exportPageSize {
+			break
+		}
+		query.After = tasks[len(tasks)-1]
+	}
+
+	if err := input.Encoder.Close(); err != nil {
+		return nil, err
+	}
+	return output, nil
+}
@@ -0,0 +1,175 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/hoyci/todo-ddd/pkg/domain"
+	domainTask "github.com/hoyci/todo-ddd/pkg/domain/task"
+	domainUndo "github.com/hoyci/todo-ddd/pkg/domain/undo"
+	"github.com/hoyci/todo-ddd/pkg/domain/valueobject"
+	domainWorkspace "github.com/hoyci/todo-ddd/pkg/domain/workspace"
+	"github.com/hoyci/todo-ddd/pkg/usecase"
+)
+
+// defaultImportPriority é usada nas linhas que não informam prioridade.
+const defaultImportPriority = valueobject.Medium
+
+// ImportRow é uma tarefa lida de um arquivo, ainda não validada.
+type ImportRow struct {
+	// Line é a linha no arquivo de origem (ou a posição no array JSON),
+	// usada para reportar erros.
+	Line        int
+	Title       string
+	Description string
+	// Priority zero significa não informada.
+	Priority int
+	// Status vazio significa "new".
+	Status     string
+	DueAt      *time.Time
+	Tags       []string
+	Recurrence string
+	// Err é um erro do parser; a linha é reportada sem ser validada.
+	Err error
+}
+
+type ImportTasksInput struct {
+	UserID string
+	Rows   []ImportRow
+	// DryRun valida as linhas sem gravar nada.
+	DryRun bool
+}
+
+type ImportRowError struct {
+	Line int
+	Err  error
+}
+
+type ImportTasksOutput struct {
+	Committed bool
+	// Tasks são as tarefas válidas: gravadas ou, em dry-run, as que seriam.
+	Tasks  []*domainTask.Task
+	Errors []ImportRowError
+	// Undo exclui de uma vez as tarefas importadas; nil em dry-run ou se
+	// UndoWindow for zero.
+	Undo *domainUndo.Undo
+}
+
+// ImportTasksUseCase valida cada linha com os mesmos value objects da
+// criação e grava as válidas em uma única transação; as inválidas são
+// reportadas e ignoradas.
+type ImportTasksUseCase struct {
+	UoW domain.UnitOfWork
+	Options
+}
+
+func (uc *ImportTasksUseCase) Execute(ctx context.Context, input ImportTasksInput) (output *ImportTasksOutput, err error) {
+	ctx, end := usecase.Start(ctx, "import_tasks")
+	defer end(&err)
+
+	output = &ImportTasksOutput{}
+	for _, row := range input.Rows {
+		task, err := newImportedTask(input.UserID, domainWorkspace.ScopeFrom(ctx), row)
+		if err != nil {
+			output.Errors = append(output.Errors, ImportRowError{Line: row.Line, Err: err})
+			continue
+		}
+		output.Tasks = append(output.Tasks, task)
+	}
+
+	err = uc.UoW.Execute(ctx, func(ctx context.Context, work domain.Work) error {
+		if _, err := uc.taskCreator(ctx, work, input.UserID); err != nil {
+			return err
+		}
+		if input.DryRun {
+			return nil
+		}
+
+		steps := make([]domainUndo.Step, 0, len(output.Tasks))
+		for _, task := range output.Tasks {
+			if err := work.TaskRepo().Save(ctx, task); err != nil {
+				logger(ctx).Error("error saving imported task", "error", err)
+				return usecase.ErrTaskSaveFailed
+			}
+			if err := recordEvents(ctx, work, task); err != nil {
+				return err
+			}
+			if err := auditTask(ctx, work, "task.created", nil, task); err != nil {
+				return err
+			}
+			revision, err := recordRevision(ctx, work, task, input.UserID)
+			if err != nil {
+				return err
+			}
+			steps = append(steps, deleteStep(revision))
+		}
+
+		undo, err := issueUndo(ctx, work, uc.UndoWindow, input.UserID, "task.imported", steps)
+		if err != nil {
+			return err
+		}
+		output.Undo = undo
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	output.Committed = !input.DryRun && len(output.Tasks) > 0
+	return output, nil
+}
+
+func newImportedTask(userID, workspaceID string, row ImportRow) (*domainTask.Task, error) {
+	if row.Err != nil {
+		return nil, row.Err
+	}
+
+	priority := defaultImportPriority
+	if row.Priority != 0 {
+		p, err := valueobject.NewPriority(row.Priority)
+		if err != nil {
+			return nil, err
+		}
+		priority = p
+	}
+
+	status := valueobject.StatusNew
+	if row.Status != "" {
+		s, err := valueobject.NewStatus(row.Status)
+		if err != nil {
+			return nil, err
+		}
+		status = s
+	}
+
+	opts := []domainTask.Option{domainTask.InWorkspace(workspaceID)}
+	if row.DueAt != nil {
+		opts = append(opts, domainTask.WithDueAt(*row.DueAt))
+	}
+	for _, raw := range row.Tags {
+		tag, err := valueobject.NewTag(raw)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, domainTask.WithTags(tag))
+	}
+	if row.Recurrence != "" {
+		recurrence, err := valueobject.ParseRecurrence(row.Recurrence)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, domainTask.WithRecurrence(recurrence))
+	}
+
+	task, err := domainTask.NewTask(row.Title, row.Description, userID, priority, opts...)
+	if err != nil {
+		return nil, err
+	}
+	switch status {
+	case valueobject.StatusInProgress:
+		task.SetInProgress()
+	case valueobject.StatusCompleted:
+		task.SetCompleted()
+	}
+	return task, nil
+}
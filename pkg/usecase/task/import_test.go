@@ -0,0 +1,92 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hoyci/todo-ddd/pkg/domain"
+	"github.com/hoyci/todo-ddd/pkg/domain/valueobject"
+	"github.com/hoyci/todo-ddd/pkg/usecase"
+)
+
+// countTasks conta as tarefas gravadas do usuário.
+func countTasks(t *testing.T, uow domain.UnitOfWork, userID string) int {
+	t.Helper()
+	n := 0
+	err := uow.Execute(context.Background(), func(ctx context.Context, work domain.Work) error {
+		tasks, err := work.TaskRepo().List(ctx, userID)
+		n = len(tasks)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestImportTasks(t *testing.T) {
+	rows := []ImportRow{
+		{Line: 1, Title: "Defaults"},
+		{Line: 2, Title: "Done", Priority: 3, Status: "completed", Tags: []string{"#Home", "home"}, Recurrence: "FREQ=DAILY"},
+		{Line: 3, Title: "Bad priority", Priority: 7},
+		{Line: 4, Title: "Bad tag", Tags: []string{"two words"}},
+		{Line: 5, Title: "Bad recurrence", Recurrence: "FREQ=HOURLY"},
+		{Line: 6, Title: ""},
+		{Line: 7, Title: "Parser error", Err: usecase.ErrInvalidImportRow},
+	}
+	wantErrors := []struct {
+		line int
+		err  error
+	}{
+		{3, valueobject.ErrInvalidPriority},
+		{4, valueobject.ErrInvalidTag},
+		{5, valueobject.ErrInvalidRecurrence},
+		{6, valueobject.ErrEmptyTitle},
+		{7, usecase.ErrInvalidImportRow},
+	}
+
+	tests := []struct {
+		name          string
+		dryRun        bool
+		wantCommitted bool
+		wantSaved     int
+	}{
+		{"dry run", true, false, 0},
+		{"commit", false, true, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uow := newTestUoW(t)
+			userID := saveTestUser(t, uow, "ada@example.com", true)
+
+			out, err := (&ImportTasksUseCase{UoW: uow}).Execute(context.Background(), ImportTasksInput{UserID: userID, Rows: rows, DryRun: tt.dryRun})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if out.Committed != tt.wantCommitted {
+				t.Errorf("Committed = %v, want %v", out.Committed, tt.wantCommitted)
+			}
+			if len(out.Tasks) != 2 {
+				t.Fatalf("got %d valid tasks, want 2", len(out.Tasks))
+			}
+			if task := out.Tasks[0]; task.Priority != defaultImportPriority || task.Status != valueobject.StatusNew {
+				t.Errorf("defaults = %v, %v", task.Priority, task.Status)
+			}
+			if task := out.Tasks[1]; task.Status != valueobject.StatusCompleted || len(task.Tags) != 1 || task.Recurrence.Frequency != valueobject.Daily {
+				t.Errorf("imported task = %+v", task)
+			}
+			if len(out.Errors) != len(wantErrors) {
+				t.Fatalf("errors = %+v", out.Errors)
+			}
+			for i, want := range wantErrors {
+				if got := out.Errors[i]; got.Line != want.line || !errors.Is(got.Err, want.err) {
+					t.Errorf("error %d = line %d %v, want line %d %v", i, got.Line, got.Err, want.line, want.err)
+				}
+			}
+			if got := countTasks(t, uow, userID); got != tt.wantSaved {
+				t.Errorf("saved %d tasks, want %d", got, tt.wantSaved)
+			}
+		})
+	}
+}
@@ -1,23 +1,36 @@
 package usecase
 
 import (
-	"log/slog"
+	"context"
 
 	domain "github.com/hoyci/todo-ddd/pkg/domain/task"
+	domainWorkspace "github.com/hoyci/todo-ddd/pkg/domain/workspace"
+	"github.com/hoyci/todo-ddd/pkg/usecase"
 )
 
 type ListTaskOutput struct {
 	*domain.Task
 }
 
+// ListTaskUseCase lista as tarefas de userID, que deve ser o usuário
+// autenticado. Com um workspace no contexto, ele precisa ser membro: quem
+// não é recebe ErrWorkspaceNotFound em vez de uma lista vazia.
 type ListTaskUseCase struct {
-	TaskRepo domain.TaskRepository
+	TaskRepo      domain.TaskRepository
+	WorkspaceRepo domainWorkspace.WorkspaceRepository
 }
 
-func (uc *ListTaskUseCase) Execute(userID string) ([]ListTaskOutput, error) {
-	tasks, err := uc.TaskRepo.List(userID)
+func (uc *ListTaskUseCase) Execute(ctx context.Context, userID string) (_ []ListTaskOutput, err error) {
+	ctx, end := usecase.Start(ctx, "list_tasks")
+	defer end(&err)
+
+	if err := usecase.AuthorizeScope(ctx, uc.WorkspaceRepo, userID, domainWorkspace.ActionViewTasks); err != nil {
+		return nil, err
+	}
+
+	tasks, err := uc.TaskRepo.List(ctx, userID)
 	if err != nil {
-		slog.Error("error trying to list tasks")
+		logger(ctx).Error("error trying to list tasks", "error", err)
 		return nil, err
 	}
 
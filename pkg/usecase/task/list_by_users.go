@@ -0,0 +1,113 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	domain "github.com/hoyci/todo-ddd/pkg/domain/task"
+	"github.com/hoyci/todo-ddd/pkg/domain/valueobject"
+	"github.com/hoyci/todo-ddd/pkg/usecase"
+)
+
+// ListTasksByUsersInput pede uma página das tarefas de cada dono em
+// UserIDs, só com as que ViewerID enxerga. After é a última tarefa da
+// página anterior; Limit zero devolve todas.
+type ListTasksByUsersInput struct {
+	ViewerID string
+	UserIDs  []string
+	Filter   domain.TaskFilter
+	After    string
+	Limit    int
+}
+
+// TaskPage é uma página das tarefas de um dono.
+type TaskPage struct {
+	Tasks   []*domain.Task
+	HasMore bool
+}
+
+// ListTasksByUsersUseCase carrega as tarefas de vários usuários de uma vez,
+// para consumidores que resolvem dados aninhados (ex: GraphQL) sem N+1.
+type ListTasksByUsersUseCase struct {
+	TaskRepo domain.TaskRepository
+}
+
+func (uc *ListTasksByUsersUseCase) Execute(ctx context.Context, input ListTasksByUsersInput) (_ map[string]*TaskPage, err error) {
+	ctx, end := usecase.Start(ctx, "list_tasks_by_users")
+	defer end(&err)
+
+	if input.After != "" {
+		if _, err := findActiveTask(ctx, uc.TaskRepo, input.After, input.ViewerID); err != nil {
+			if errors.Is(err, usecase.ErrTaskNotFound) {
+				return nil, usecase.ErrInvalidCursor
+			}
+			return nil, err
+		}
+	}
+
+	query := domain.TasksByUsersQuery{
+		UserIDs:  input.UserIDs,
+		ViewerID: input.ViewerID,
+		Filter:   input.Filter,
+		AfterID:  input.After,
+	}
+	if input.Limit > 0 {
+		// Um item a mais por dono indica se há próxima página.
+		query.Limit = input.Limit + 1
+	}
+	tasks, err := uc.TaskRepo.ListByUserIDs(ctx, query)
+	if err != nil {
+		logger(ctx).Error("error trying to list tasks by users", "users", len(input.UserIDs), "error", err)
+		return nil, err
+	}
+
+	output := make(map[string]*TaskPage, len(input.UserIDs))
+	for _, id := range input.UserIDs {
+		output[id] = &TaskPage{}
+	}
+	for _, t := range tasks {
+		page := output[t.UserID]
+		if input.Limit > 0 && len(page.Tasks) == input.Limit {
+			page.HasMore = true
+			continue
+		}
+		page.Tasks = append(page.Tasks, t)
+	}
+	return output, nil
+}
+
+type CountTasksByUsersInput struct {
+	ViewerID string
+	UserIDs  []string
+	Filter   domain.TaskFilter
+}
+
+// CountTasksByUsersUseCase conta por status as tarefas de cada dono que o
+// usuário enxerga, com o mesmo filtro de ListTasksByUsersUseCase.
+type CountTasksByUsersUseCase struct {
+	TaskRepo domain.TaskRepository
+}
+
+func (uc *CountTasksByUsersUseCase) Execute(ctx context.Context, input CountTasksByUsersInput) (_ map[string]map[valueobject.Status]int, err error) {
+	ctx, end := usecase.Start(ctx, "count_tasks_by_users")
+	defer end(&err)
+
+	counts, err := uc.TaskRepo.CountByUserIDs(ctx, domain.TasksByUsersQuery{
+		UserIDs:  input.UserIDs,
+		ViewerID: input.ViewerID,
+		Filter:   input.Filter,
+	})
+	if err != nil {
+		logger(ctx).Error("error trying to count tasks by users", "users", len(input.UserIDs), "error", err)
+		return nil, err
+	}
+
+	output := make(map[string]map[valueobject.Status]int, len(input.UserIDs))
+	for _, id := range input.UserIDs {
+		output[id] = map[valueobject.Status]int{}
+	}
+	for _, c := range counts {
+		output[c.UserID][c.Status] += c.Count
+	}
+	return output, nil
+}
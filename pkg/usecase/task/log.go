@@ -0,0 +1,12 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/hoyci/todo-ddd/pkg/logging"
+)
+
+func logger(ctx context.Context) *slog.Logger {
+	return logging.ForPackage(ctx, "usecase.task")
+}
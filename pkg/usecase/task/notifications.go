@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/hoyci/todo-ddd/pkg/domain"
+	domainNotification "github.com/hoyci/todo-ddd/pkg/domain/notification"
+	domainTask "github.com/hoyci/todo-ddd/pkg/domain/task"
+	"github.com/hoyci/todo-ddd/pkg/usecase"
+)
+
+// excerptLength é quantos caracteres do comentário vão no corpo da
+// notificação.
+const excerptLength = 140
+
+// actorName devolve o nome de quem fez a mudança, para o texto das
+// notificações.
+func actorName(ctx context.Context, work domain.Work, userID string) (string, error) {
+	user, err := work.UserRepo().FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "Someone", nil
+		}
+		logger(ctx).Error("error trying to find user by id", "userID", userID, "error", err)
+		return "", err
+	}
+	return user.Name, nil
+}
+
+// notifyAssigned avisa o novo responsável pela tarefa, a menos que ele mesmo
+// tenha se atribuído.
+func notifyAssigned(ctx context.Context, work domain.Work, task *domainTask.Task, actorID string) error {
+	if task.AssigneeID == "" || task.AssigneeID == actorID {
+		return nil
+	}
+	name, err := actorName(ctx, work, actorID)
+	if err != nil {
+		return err
+	}
+	notification := domainNotification.NewNotification(task.AssigneeID, domainNotification.KindAssigned, task.ID,
+		"Assigned: "+task.Title, name+" assigned this task to you")
+	return usecase.Notify(ctx, work, notification)
+}
+
+// notifyComment avisa os mencionados no comentário e, depois, o dono da
+// tarefa e quem a acompanha. Cada usuário recebe uma notificação só, e o
+// autor nenhuma. Menções a quem não existe ou não vê a tarefa são ignoradas.
+func notifyComment(ctx context.Context, work domain.Work, task *domainTask.Task, comment *domainTask.Comment) error {
+	name, err := actorName(ctx, work, comment.AuthorID)
+	if err != nil {
+		return err
+	}
+	notified := map[string]bool{comment.AuthorID: true}
+	body := excerpt(comment.Body)
+
+	for _, email := range comment.Mentions() {
+		user, err := work.UserRepo().FindByEmail(ctx, email)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				continue
+			}
+			logger(ctx).Error("error trying to find user by email", "error", err)
+			return err
+		}
+		if user.DeletedAt != nil || notified[user.ID] {
+			continue
+		}
+		access, err := accessFor(ctx, work, task, user.ID)
+		if err != nil {
+			return err
+		}
+		if access == domainTask.AccessNone {
+			continue
+		}
+		notified[user.ID] = true
+		notification := domainNotification.NewNotification(user.ID, domainNotification.KindMention, task.ID,
+			name+" mentioned you on: "+task.Title, body)
+		if err := usecase.Notify(ctx, work, notification); err != nil {
+			return err
+		}
+	}
+
+	watchers, err := work.WatcherRepo().ListByTask(ctx, task.ID)
+	if err != nil {
+		return err
+	}
+	recipients := []string{task.UserID}
+	for _, watcher := range watchers {
+		recipients = append(recipients, watcher.UserID)
+	}
+	for _, userID := range recipients {
+		if notified[userID] {
+			continue
+		}
+		notified[userID] = true
+		if userID == task.UserID {
+			// O dono de uma tarefa de workspace pode ter saído dele.
+			access, err := accessFor(ctx, work, task, userID)
+			if err != nil {
+				return err
+			}
+			if access == domainTask.AccessNone {
+				continue
+			}
+		}
+		notification := domainNotification.NewNotification(userID, domainNotification.KindComment, task.ID,
+			name+" commented on: "+task.Title, body)
+		if err := usecase.Notify(ctx, work, notification); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func excerpt(body string) string {
+	runes := []rune(body)
+	if len(runes) <= excerptLength {
+		return body
+	}
+	return string(runes[:excerptLength-1]) + "…"
+}
@@ -0,0 +1,45 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/hoyci/todo-ddd/pkg/domain"
+	domainUser "github.com/hoyci/todo-ddd/pkg/domain/user"
+	domainWorkspace "github.com/hoyci/todo-ddd/pkg/domain/workspace"
+	"github.com/hoyci/todo-ddd/pkg/usecase"
+)
+
+// Options são as regras comuns aos casos de uso que escrevem tarefas. Cada
+// um deles a embute; main a configura uma vez e a repassa a todos.
+type Options struct {
+	// UndoWindow é por quanto tempo uma mudança pode ser desfeita; zero não
+	// emite token.
+	UndoWindow time.Duration
+	// RequireVerifiedEmail impede quem não confirmou o email de criar
+	// tarefas.
+	RequireVerifiedEmail bool
+}
+
+// taskCreator busca o usuário que vai criar tarefas e confere as
+// pré-condições da criação com authorizeCreate.
+func (o Options) taskCreator(ctx context.Context, work domain.Work, userID string) (*domainUser.User, error) {
+	user, err := activeUser(ctx, work, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := o.authorizeCreate(ctx, work, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// authorizeCreate concentra o que todo caminho de criação (simples, lote,
+// importação e quick-add) exige: email confirmado, se configurado, e
+// permissão de criar no workspace do contexto.
+func (o Options) authorizeCreate(ctx context.Context, work domain.Work, user *domainUser.User) error {
+	if o.RequireVerifiedEmail && !user.Verified() {
+		return usecase.ErrEmailNotVerified
+	}
+	return usecase.AuthorizeScope(ctx, work.WorkspaceRepo(), user.ID, domainWorkspace.ActionCreateTask)
+}
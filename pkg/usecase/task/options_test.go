@@ -0,0 +1,136 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/hoyci/todo-ddd/internal/adapters/db/sqlite"
+	"github.com/hoyci/todo-ddd/internal/adapters/events"
+	"github.com/hoyci/todo-ddd/pkg/domain"
+	domainUser "github.com/hoyci/todo-ddd/pkg/domain/user"
+	domainWorkspace "github.com/hoyci/todo-ddd/pkg/domain/workspace"
+	"github.com/hoyci/todo-ddd/pkg/usecase"
+)
+
+// newTestDB abre um banco SQLite temporário com o esquema completo.
+func newTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	t.Chdir(t.TempDir())
+	if err := os.Mkdir("data", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	db, err := sqlite.InitDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func newTestUoW(t *testing.T) domain.UnitOfWork {
+	t.Helper()
+	return sqlite.NewSQLiteUnitOfWork(newTestDB(t), events.NewBroker(0))
+}
+
+// saveTestUser grava um usuário, confirmado ou não.
+func saveTestUser(t *testing.T, uow domain.UnitOfWork, email string, verified bool) string {
+	t.Helper()
+	user, err := domainUser.NewUser("Test User", email)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if verified {
+		if err := user.Verify(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	err = uow.Execute(context.Background(), func(ctx context.Context, work domain.Work) error {
+		return work.UserRepo().Save(ctx, *user)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return user.ID
+}
+
+// createPaths cria uma tarefa por cada caminho de criação e devolve o erro
+// que impediu a criação, se houve.
+func createPaths(uow domain.UnitOfWork, opts Options) map[string]func(ctx context.Context, userID string) error {
+	return map[string]func(ctx context.Context, userID string) error{
+		"create": func(ctx context.Context, userID string) error {
+			_, err := (&CreateTaskUseCase{UoW: uow, Options: opts}).Execute(ctx, CreateTaskInput{Title: "Task", Priority: 1, UserID: userID})
+			return err
+		},
+		"batch": func(ctx context.Context, userID string) error {
+			out, err := (&BatchTaskUseCase{UoW: uow, Options: opts}).Execute(ctx, BatchTaskInput{
+				UserID:     userID,
+				Mode:       BatchModeAllOrNothing,
+				Operations: []BatchOperation{{Type: BatchOperationCreate, Title: "Task", Priority: 1}},
+			})
+			if err != nil {
+				return err
+			}
+			return out.Results[0].Err
+		},
+		"import": func(ctx context.Context, userID string) error {
+			_, err := (&ImportTasksUseCase{UoW: uow, Options: opts}).Execute(ctx, ImportTasksInput{UserID: userID, Rows: []ImportRow{{Line: 1, Title: "Task"}}})
+			return err
+		},
+		"quick-add": func(ctx context.Context, userID string) error {
+			_, err := (&QuickAddTaskUseCase{UoW: uow, Options: opts}).Execute(ctx, QuickAddTaskInput{UserID: userID, Text: "Task", Now: time.Now()})
+			return err
+		},
+		"quick-add dry-run": func(ctx context.Context, userID string) error {
+			_, err := (&QuickAddTaskUseCase{UoW: uow, Options: opts}).Execute(ctx, QuickAddTaskInput{UserID: userID, Text: "Task", Now: time.Now(), DryRun: true})
+			return err
+		},
+	}
+}
+
+func TestCreatePreconditions(t *testing.T) {
+	uow := newTestUoW(t)
+	verified := saveTestUser(t, uow, "verified@example.com", true)
+	pending := saveTestUser(t, uow, "pending@example.com", false)
+
+	// Um workspace do qual o usuário verificado não participa.
+	workspace, owner, err := domainWorkspace.NewWorkspace("Team", saveTestUser(t, uow, "owner@example.com", true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = uow.Execute(context.Background(), func(ctx context.Context, work domain.Work) error {
+		if err := work.WorkspaceRepo().Save(ctx, workspace); err != nil {
+			return err
+		}
+		return work.WorkspaceRepo().SaveMember(ctx, owner)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	foreign := domainWorkspace.WithScope(context.Background(), workspace.ID)
+
+	tests := []struct {
+		name    string
+		require bool
+		ctx     context.Context
+		userID  string
+		wantErr error
+	}{
+		{"verification not required", false, context.Background(), pending, nil},
+		{"unverified user blocked", true, context.Background(), pending, usecase.ErrEmailNotVerified},
+		{"verified user allowed", true, context.Background(), verified, nil},
+		{"foreign workspace", false, foreign, verified, usecase.ErrWorkspaceNotFound},
+	}
+	for _, tt := range tests {
+		for path, create := range createPaths(uow, Options{RequireVerifiedEmail: tt.require}) {
+			t.Run(tt.name+"/"+path, func(t *testing.T) {
+				if err := create(tt.ctx, tt.userID); !errors.Is(err, tt.wantErr) {
+					t.Errorf("error = %v, want %v", err, tt.wantErr)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/hoyci/todo-ddd/pkg/domain"
+	domainTask "github.com/hoyci/todo-ddd/pkg/domain/task"
+	domainUndo "github.com/hoyci/todo-ddd/pkg/domain/undo"
+	"github.com/hoyci/todo-ddd/pkg/domain/valueobject"
+	"github.com/hoyci/todo-ddd/pkg/usecase"
+)
+
+// TaskFields é o estado editável de uma tarefa. Ponteiros e slices nil
+// representam campos nulos no documento resultante do patch.
+type TaskFields struct {
+	Title       *string
+	Description *string
+	Priority    *int
+	Status      *string
+	DueAt       *time.Time
+	Tags        []string
+	Recurrence  *string
+}
+
+type PatchTaskInput struct {
+	TaskID string
+	UserID string
+	// Patch recebe o estado atual da tarefa e devolve o estado desejado.
+	Patch func(current TaskFields) (TaskFields, error)
+}
+
+type PatchTaskOutput struct {
+	domainTask.Task
+	// Undo desfaz a mudança; nil se nada mudou ou se UndoWindow for zero.
+	Undo *domainUndo.Undo
+}
+
+type PatchTaskUseCase struct {
+	UoW domain.UnitOfWork
+	Options
+}
+
+func (uc *PatchTaskUseCase) Execute(ctx context.Context, input PatchTaskInput) (output *PatchTaskOutput, err error) {
+	ctx, end := usecase.Start(ctx, "patch_task")
+	defer end(&err)
+
+	err = uc.UoW.Execute(ctx, func(ctx context.Context, work domain.Work) error {
+		repo := work.TaskRepo()
+
+		task, err := findTaskWithAccess(ctx, work, input.TaskID, input.UserID, domainTask.AccessEdit)
+		if err != nil {
+			return err
+		}
+
+		desired, err := input.Patch(taskFields(task))
+		if err != nil {
+			return fmt.Errorf("%w: %w", usecase.ErrInvalidPatch, err)
+		}
+
+		before := *task
+		changed, err := applyTaskFields(task, desired)
+		if err != nil {
+			return err
+		}
+		var undo *domainUndo.Undo
+		if changed {
+			if err := repo.Update(ctx, task); err != nil {
+				logger(ctx).Error("error trying to patch task", "taskID", task.ID, "error", err)
+				return err
+			}
+			if err := recordEvents(ctx, work, task); err != nil {
+				return err
+			}
+			if err := auditTask(ctx, work, "task.updated", &before, task); err != nil {
+				return err
+			}
+			if err := rescheduleReminders(ctx, work, task); err != nil {
+				return err
+			}
+			revision, err := recordRevision(ctx, work, task, input.UserID)
+			if err != nil {
+				return err
+			}
+			if undo, err = issueUndo(ctx, work, uc.UndoWindow, input.UserID, "task.updated", []domainUndo.Step{restoreStep(revision)}); err != nil {
+				return err
+			}
+		}
+
+		output = &PatchTaskOutput{Task: *task, Undo: undo}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+func taskFields(task *domainTask.Task) TaskFields {
+	title, description := task.Title, task.Description
+	priority := int(task.Priority)
+	status := string(task.Status)
+	// Tags vazias viram [] e não null, para que "add /tags/-" funcione.
+	tags := make([]string, len(task.Tags))
+	for i, tag := range task.Tags {
+		tags[i] = string(tag)
+	}
+	fields := TaskFields{
+		Title:       &title,
+		Description: &description,
+		Priority:    &priority,
+		Status:      &status,
+		DueAt:       task.DueAt,
+		Tags:        tags,
+	}
+	if !task.Recurrence.IsZero() {
+		recurrence := task.Recurrence.String()
+		fields.Recurrence = &recurrence
+	}
+	return fields
+}
+
+// applyTaskFields valida cada campo pelos value objects e aplica apenas os
+// que mudaram. Título, prioridade e status não aceitam null; descrição,
+// vencimento, tags e recorrência nulos são limpos.
+func applyTaskFields(task *domainTask.Task, desired TaskFields) (bool, error) {
+	if desired.Title == nil {
+		return false, valueobject.ErrEmptyTitle
+	}
+	if desired.Priority == nil {
+		return false, valueobject.ErrInvalidPriority
+	}
+	if desired.Status == nil {
+		return false, valueobject.ErrInvalidStatus
+	}
+
+	priority, err := valueobject.NewPriority(*desired.Priority)
+	if err != nil {
+		return false, err
+	}
+	status, err := valueobject.NewStatus(*desired.Status)
+	if err != nil {
+		return false, err
+	}
+
+	description := ""
+	if desired.Description != nil {
+		description = *desired.Description
+	}
+
+	var tags []valueobject.Tag
+	for _, raw := range desired.Tags {
+		tag, err := valueobject.NewTag(raw)
+		if err != nil {
+			return false, err
+		}
+		if !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+
+	var recurrence valueobject.Recurrence
+	if desired.Recurrence != nil {
+		if recurrence, err = valueobject.ParseRecurrence(*desired.Recurrence); err != nil {
+			return false, err
+		}
+	}
+
+	changed := false
+	if *desired.Title != task.Title {
+		if err := task.Rename(*desired.Title); err != nil {
+			return false, err
+		}
+		changed = true
+	}
+	if description != task.Description {
+		task.SetDescription(description)
+		changed = true
+	}
+	if priority != task.Priority {
+		task.SetPriority(priority)
+		changed = true
+	}
+	if !sameDueAt(desired.DueAt, task.DueAt) {
+		task.SetDueAt(desired.DueAt)
+		changed = true
+	}
+	if !slices.Equal(tags, task.Tags) {
+		task.SetTags(tags)
+		changed = true
+	}
+	if recurrence != task.Recurrence {
+		task.SetRecurrence(recurrence)
+		changed = true
+	}
+	if status != task.Status {
+		applyStatus(task, status)
+		changed = true
+	}
+	return changed, nil
+}
+
+func sameDueAt(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
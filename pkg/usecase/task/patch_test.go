@@ -0,0 +1,165 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/hoyci/todo-ddd/pkg/domain"
+	domainTask "github.com/hoyci/todo-ddd/pkg/domain/task"
+	"github.com/hoyci/todo-ddd/pkg/domain/valueobject"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+func TestApplyTaskFields(t *testing.T) {
+	due := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	newTask := func() *domainTask.Task {
+		task, err := domainTask.NewTask("Write docs", "", "user-1", 1,
+			domainTask.WithDueAt(due),
+			domainTask.WithTags("work"),
+			domainTask.WithRecurrence(valueobject.Recurrence{Frequency: valueobject.Weekly, Interval: 1}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return task
+	}
+
+	tests := []struct {
+		name        string
+		edit        func(*TaskFields)
+		wantChanged bool
+		wantErr     error
+		check       func(*domainTask.Task) bool
+	}{
+		{
+			name: "unchanged",
+			edit: func(*TaskFields) {},
+		},
+		{
+			name:        "moves due date",
+			edit:        func(f *TaskFields) { f.DueAt = ptr(due.Add(24 * time.Hour)) },
+			wantChanged: true,
+			check:       func(task *domainTask.Task) bool { return task.DueAt.Equal(due.Add(24 * time.Hour)) },
+		},
+		{
+			name:        "same instant in another zone",
+			edit:        func(f *TaskFields) { f.DueAt = ptr(due.In(time.FixedZone("BRT", -3*3600))) },
+			wantChanged: false,
+		},
+		{
+			name:        "null due date clears it",
+			edit:        func(f *TaskFields) { f.DueAt = nil },
+			wantChanged: true,
+			check:       func(task *domainTask.Task) bool { return task.DueAt == nil },
+		},
+		{
+			name:        "tags are normalized and deduplicated",
+			edit:        func(f *TaskFields) { f.Tags = []string{"#Home", "work", "home"} },
+			wantChanged: true,
+			check: func(task *domainTask.Task) bool {
+				return slices.Equal(task.Tags, []valueobject.Tag{"home", "work"})
+			},
+		},
+		{
+			name:        "null tags clear them",
+			edit:        func(f *TaskFields) { f.Tags = nil },
+			wantChanged: true,
+			check:       func(task *domainTask.Task) bool { return len(task.Tags) == 0 },
+		},
+		{
+			name:    "invalid tag",
+			edit:    func(f *TaskFields) { f.Tags = []string{"two words"} },
+			wantErr: valueobject.ErrInvalidTag,
+		},
+		{
+			name:        "recurrence changes",
+			edit:        func(f *TaskFields) { f.Recurrence = ptr("FREQ=MONTHLY;INTERVAL=2") },
+			wantChanged: true,
+			check: func(task *domainTask.Task) bool {
+				return task.Recurrence == valueobject.Recurrence{Frequency: valueobject.Monthly, Interval: 2}
+			},
+		},
+		{
+			name:        "null recurrence stops it",
+			edit:        func(f *TaskFields) { f.Recurrence = nil },
+			wantChanged: true,
+			check:       func(task *domainTask.Task) bool { return task.Recurrence.IsZero() },
+		},
+		{
+			name:    "invalid recurrence",
+			edit:    func(f *TaskFields) { f.Recurrence = ptr("FREQ=HOURLY") },
+			wantErr: valueobject.ErrInvalidRecurrence,
+		},
+		{
+			name:    "null title",
+			edit:    func(f *TaskFields) { f.Title = nil },
+			wantErr: valueobject.ErrEmptyTitle,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := newTask()
+			before := *task
+			desired := taskFields(task)
+			tt.edit(&desired)
+
+			changed, err := applyTaskFields(task, desired)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if changed != tt.wantChanged {
+				t.Errorf("changed = %v, want %v", changed, tt.wantChanged)
+			}
+			if tt.wantErr != nil && (task.Title != before.Title || task.DueAt != before.DueAt) {
+				t.Errorf("a rejected patch modified the task: %+v", task)
+			}
+			if tt.check != nil && !tt.check(task) {
+				t.Errorf("task = %+v", task)
+			}
+		})
+	}
+}
+
+func TestPatchTaskReschedulesReminders(t *testing.T) {
+	uow := newTestUoW(t)
+	userID := saveTestUser(t, uow, "ada@example.com", true)
+	ctx := context.Background()
+
+	due := time.Now().Add(48 * time.Hour).Truncate(time.Second)
+	created, err := (&CreateTaskUseCase{UoW: uow}).Execute(ctx, CreateTaskInput{Title: "Task", Priority: 1, UserID: userID, DueAt: &due})
+	if err != nil {
+		t.Fatal(err)
+	}
+	reminder, err := (&AddReminderUseCase{UoW: uow}).Execute(ctx, AddReminderInput{TaskID: created.ID, UserID: userID, BeforeDue: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	moved := due.Add(24 * time.Hour)
+	_, err = (&PatchTaskUseCase{UoW: uow}).Execute(ctx, PatchTaskInput{
+		TaskID: created.ID,
+		UserID: userID,
+		Patch: func(current TaskFields) (TaskFields, error) {
+			current.DueAt = &moved
+			return current, nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var reminders []*domainTask.Reminder
+	err = uow.Execute(ctx, func(ctx context.Context, work domain.Work) error {
+		reminders, err = work.ReminderRepo().ListByTask(ctx, created.ID)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reminders) != 1 || reminders[0].ID != reminder.ID || !reminders[0].FireAt.Equal(moved.Add(-time.Hour)) {
+		t.Errorf("reminders = %+v, want one firing at %s", reminders, moved.Add(-time.Hour))
+	}
+}
@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/hoyci/todo-ddd/pkg/domain"
+	domainTask "github.com/hoyci/todo-ddd/pkg/domain/task"
+	"github.com/hoyci/todo-ddd/pkg/policy"
+)
+
+// TaskResource resolve a tarefa alvo da entrada para a política, traduzindo
+// o acesso do principal em relação. Quem não enxerga a tarefa recebe
+// ErrTaskNotFound, como nos próprios casos de uso; o anônimo nem chega a
+// consultar a tarefa e fica sem relação.
+func TaskResource[I any](uow domain.UnitOfWork, taskID func(I) string) policy.ResourceFunc[I] {
+	return func(ctx context.Context, principal policy.Principal, input I) (policy.Resource, error) {
+		resource := policy.Resource{Type: "task", ID: taskID(input)}
+		if principal.UserID == "" {
+			return resource, nil
+		}
+		err := uow.Execute(ctx, func(ctx context.Context, work domain.Work) error {
+			task, err := findActiveTask(ctx, work.TaskRepo(), resource.ID, principal.UserID)
+			if err != nil {
+				return err
+			}
+			access, err := accessFor(ctx, work, task, principal.UserID)
+			if err != nil {
+				return err
+			}
+			if relation, ok := taskRelations[access]; ok {
+				resource.Relations = []policy.Relation{relation}
+			}
+			return nil
+		})
+		return resource, err
+	}
+}
+
+var taskRelations = map[domainTask.Access]policy.Relation{
+	domainTask.AccessOwner: policy.RelationOwner,
+	domainTask.AccessEdit:  policy.RelationEditor,
+	domainTask.AccessView:  policy.RelationViewer,
+}
+
+// TaskListResource resolve a lista de tarefas de um usuário para a
+// política. Só o próprio usuário tem a relação owner com ela; os demais,
+// inclusive quem recebeu tarefas dele compartilhadas, não a enxergam.
+func TaskListResource[I any](ownerID func(I) string) policy.ResourceFunc[I] {
+	return func(_ context.Context, principal policy.Principal, input I) (policy.Resource, error) {
+		resource := policy.Resource{Type: "task_list", ID: ownerID(input)}
+		if principal.UserID != "" && principal.UserID == resource.ID {
+			resource.Relations = []policy.Relation{policy.RelationOwner}
+		}
+		return resource, nil
+	}
+}
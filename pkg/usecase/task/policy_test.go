@@ -0,0 +1,104 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hoyci/todo-ddd/internal/adapters/db/sqlite"
+	"github.com/hoyci/todo-ddd/internal/adapters/events"
+	"github.com/hoyci/todo-ddd/pkg/policy"
+	"github.com/hoyci/todo-ddd/pkg/usecase"
+)
+
+// allowed é o caso de uso que a política protege nos testes: só registra
+// que foi executado.
+type allowed[I any] struct{ ran bool }
+
+func (uc *allowed[I]) Execute(context.Context, I) (struct{}, error) {
+	uc.ran = true
+	return struct{}{}, nil
+}
+
+func TestTaskResource(t *testing.T) {
+	db := newTestDB(t)
+	uow := sqlite.NewSQLiteUnitOfWork(db, events.NewBroker(0))
+	enforcer := &policy.Enforcer{Definition: policy.Default, Roles: sqlite.NewSQLiteRoleStore(db)}
+	ctx := context.Background()
+	owner := saveTestUser(t, uow, "owner@example.com", true)
+	editor := saveTestUser(t, uow, "editor@example.com", true)
+	viewer := saveTestUser(t, uow, "viewer@example.com", true)
+	stranger := saveTestUser(t, uow, "stranger@example.com", true)
+
+	task, err := (&CreateTaskUseCase{UoW: uow}).Execute(ctx, CreateTaskInput{Title: "Draft", Priority: 1, UserID: owner})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for user, permission := range map[string]string{editor: "edit", viewer: "view"} {
+		if _, err := (&ShareTaskUseCase{UoW: uow}).Execute(ctx, ShareTaskInput{TaskID: task.ID, UserID: owner, TargetUserID: user, Permission: permission}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		subject string
+		action  policy.Action
+		wantErr error
+	}{
+		{"owner deletes", owner, policy.TaskDelete, nil},
+		{"editor updates", editor, policy.TaskUpdate, nil},
+		{"editor deletes", editor, policy.TaskDelete, policy.ErrDenied},
+		{"viewer views", viewer, policy.TaskView, nil},
+		{"viewer updates", viewer, policy.TaskUpdate, policy.ErrDenied},
+		{"stranger views", stranger, policy.TaskView, usecase.ErrTaskNotFound},
+		{"anonymous views", "", policy.TaskView, policy.ErrDenied},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := &allowed[ListCommentsInput]{}
+			guarded := policy.Guard[ListCommentsInput, struct{}](next, enforcer, tt.action,
+				TaskResource(uow, func(in ListCommentsInput) string { return in.TaskID }))
+			_, err := guarded.Execute(policy.WithSubject(ctx, tt.subject), ListCommentsInput{TaskID: task.ID, UserID: tt.subject})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if next.ran != (tt.wantErr == nil) {
+				t.Errorf("use case ran = %v with error %v", next.ran, err)
+			}
+		})
+	}
+}
+
+func TestTaskListResource(t *testing.T) {
+	db := newTestDB(t)
+	enforcer := &policy.Enforcer{Definition: policy.Default, Roles: sqlite.NewSQLiteRoleStore(db)}
+	ctx := context.Background()
+
+	// A exportação e o carregamento em lote usam o mesmo recurso: só o
+	// próprio usuário pede a sua lista, ou as listas que ele enxerga.
+	tests := []struct {
+		name    string
+		subject string
+		ownerID string
+		wantErr error
+	}{
+		{"own list", "u1", "u1", nil},
+		{"another user's list", "u2", "u1", policy.ErrDenied},
+		{"anonymous", "", "u1", policy.ErrDenied},
+		{"anonymous without owner", "", "", policy.ErrDenied},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := &allowed[ExportTasksInput]{}
+			guarded := policy.Guard[ExportTasksInput, struct{}](next, enforcer, policy.TaskView,
+				TaskListResource(func(in ExportTasksInput) string { return in.UserID }))
+			if _, err := guarded.Execute(policy.WithSubject(ctx, tt.subject), ExportTasksInput{UserID: tt.ownerID}); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if next.ran != (tt.wantErr == nil) {
+				t.Errorf("use case ran = %v, want %v", next.ran, tt.wantErr == nil)
+			}
+		})
+	}
+}
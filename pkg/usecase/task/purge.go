@@ -0,0 +1,99 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/hoyci/todo-ddd/pkg/domain"
+	domainTask "github.com/hoyci/todo-ddd/pkg/domain/task"
+	"github.com/hoyci/todo-ddd/pkg/usecase"
+)
+
+type PurgeTasksInput struct {
+	// DeletedBefore seleciona as tarefas excluídas há mais tempo que a
+	// retenção.
+	DeletedBefore time.Time
+	// Limit é quantas tarefas são removidas nesta execução.
+	Limit int
+}
+
+type PurgeTasksOutput struct {
+	Purged       int
+	BlobsDeleted int
+}
+
+// PurgeTasksUseCase remove definitivamente tarefas excluídas, com seus
+// comentários, anexos, compartilhamentos e watchers, e apaga os conteúdos
+// que nenhum outro anexo usa. O log de eventos é mantido.
+type PurgeTasksUseCase struct {
+	UoW   domain.UnitOfWork
+	Blobs domainTask.BlobStore
+}
+
+func (uc *PurgeTasksUseCase) Execute(ctx context.Context, input PurgeTasksInput) (output *PurgeTasksOutput, err error) {
+	ctx, end := usecase.Start(ctx, "purge_tasks")
+	defer end(&err)
+
+	output = &PurgeTasksOutput{}
+	var orphans []string
+	err = uc.UoW.Execute(ctx, func(ctx context.Context, work domain.Work) error {
+		ids, err := work.TaskRepo().ListDeletedBefore(ctx, input.DeletedBefore, input.Limit)
+		if err != nil || len(ids) == 0 {
+			return err
+		}
+
+		hashes, err := work.AttachmentRepo().DeleteByTasks(ctx, ids)
+		if err != nil {
+			return err
+		}
+		if err := work.CommentRepo().DeleteByTasks(ctx, ids); err != nil {
+			return err
+		}
+		if err := work.RevisionRepo().DeleteByTasks(ctx, ids); err != nil {
+			return err
+		}
+		if err := work.ReminderRepo().DeleteByTasks(ctx, ids); err != nil {
+			return err
+		}
+		if err := work.ShareRepo().DeleteByTasks(ctx, ids); err != nil {
+			return err
+		}
+		if err := work.WatcherRepo().DeleteByTasks(ctx, ids); err != nil {
+			return err
+		}
+		if err := work.TaskRepo().Purge(ctx, ids); err != nil {
+			return err
+		}
+		for _, id := range ids {
+			if err := usecase.Audit(ctx, work.AuditRepo(), "task.purged", "task", id, nil, nil); err != nil {
+				return err
+			}
+		}
+
+		seen := make(map[string]bool, len(hashes))
+		for _, hash := range hashes {
+			if seen[hash] {
+				continue
+			}
+			seen[hash] = true
+			refs, err := work.AttachmentRepo().CountByHash(ctx, hash)
+			if err != nil {
+				return err
+			}
+			if refs == 0 {
+				orphans = append(orphans, hash)
+			}
+		}
+
+		output.Purged = len(ids)
+		return nil
+	})
+	if err != nil {
+		logger(ctx).Error("error trying to purge tasks", "error", err)
+		return nil, err
+	}
+
+	deleteBlobs(ctx, uc.Blobs, orphans)
+	output.BlobsDeleted = len(orphans)
+	return output, nil
+}
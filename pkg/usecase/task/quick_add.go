@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"cmp"
+	"context"
+	"time"
+
+	"github.com/hoyci/todo-ddd/pkg/domain"
+	domainTask "github.com/hoyci/todo-ddd/pkg/domain/task"
+	domainUndo "github.com/hoyci/todo-ddd/pkg/domain/undo"
+	"github.com/hoyci/todo-ddd/pkg/domain/valueobject"
+	"github.com/hoyci/todo-ddd/pkg/usecase"
+)
+
+// defaultQuickAddPriority é usada quando a frase não traz prioridade.
+const defaultQuickAddPriority = valueobject.Medium
+
+type QuickAddTaskInput struct {
+	UserID string
+	Text   string
+	// Now é o instante de referência das datas relativas; o seu fuso é o
+	// do usuário.
+	Now time.Time
+	// DryRun só interpreta e valida, para a interface confirmar antes de
+	// gravar.
+	DryRun bool
+}
+
+type QuickAddTaskOutput struct {
+	Parsed domainTask.QuickAdd
+	Input  CreateTaskInput
+	// Task é nil em dry-run.
+	Task *domainTask.Task
+	// Undo desfaz a criação; nil em dry-run ou se UndoWindow for zero.
+	Undo *domainUndo.Undo
+}
+
+type QuickAddTaskUseCase struct {
+	UoW domain.UnitOfWork
+	Options
+}
+
+func (uc *QuickAddTaskUseCase) Execute(ctx context.Context, input QuickAddTaskInput) (output *QuickAddTaskOutput, err error) {
+	ctx, end := usecase.Start(ctx, "quick_add_task")
+	defer end(&err)
+
+	parsed := domainTask.ParseQuickAdd(input.Text, input.Now)
+	output = &QuickAddTaskOutput{
+		Parsed: parsed,
+		Input: CreateTaskInput{
+			Title:      parsed.Title,
+			Priority:   cmp.Or(parsed.Priority, defaultQuickAddPriority),
+			UserID:     input.UserID,
+			DueAt:      parsed.DueAt,
+			Tags:       parsed.Tags,
+			Recurrence: parsed.Recurrence,
+		},
+	}
+
+	if _, err := valueobject.NewTaskTitle(output.Input.Title); err != nil {
+		return nil, err
+	}
+
+	err = uc.UoW.Execute(ctx, func(ctx context.Context, work domain.Work) error {
+		user, err := uc.taskCreator(ctx, work, input.UserID)
+		if err != nil || input.DryRun {
+			return err
+		}
+		task, revision, err := createTask(ctx, work, user, output.Input)
+		if err != nil {
+			return err
+		}
+		undo, err := issueUndo(ctx, work, uc.UndoWindow, input.UserID, "task.created", []domainUndo.Step{deleteStep(revision)})
+		if err != nil {
+			return err
+		}
+		output.Task, output.Undo = task, undo
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return output, nil
+}
@@ -0,0 +1,169 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/hoyci/todo-ddd/pkg/domain"
+	domainTask "github.com/hoyci/todo-ddd/pkg/domain/task"
+	"github.com/hoyci/todo-ddd/pkg/usecase"
+)
+
+// rescheduleReminders acompanha, na transação corrente, uma mudança no
+// vencimento da tarefa: os lembretes relativos a ele passam a disparar
+// com a mesma antecedência do novo vencimento.
+func rescheduleReminders(ctx context.Context, work domain.Work, task *domainTask.Task) error {
+	reminders, err := work.ReminderRepo().ListByTask(ctx, task.ID)
+	if err != nil {
+		logger(ctx).Error("error trying to list task reminders", "taskID", task.ID, "error", err)
+		return err
+	}
+	for _, reminder := range reminders {
+		if !reminder.Reschedule(task.DueAt) {
+			continue
+		}
+		if err := work.ReminderRepo().Update(ctx, reminder); err != nil {
+			logger(ctx).Error("error trying to reschedule reminder", "reminderID", reminder.ID, "error", err)
+			return err
+		}
+	}
+	return nil
+}
+
+//
+// ------------------- ADD -------------------
+//
+
+type AddReminderInput struct {
+	TaskID string
+	UserID string
+	// At e BeforeDue são exclusivos: um horário absoluto ou a antecedência
+	// em relação ao vencimento.
+	At        *time.Time
+	BeforeDue time.Duration
+}
+
+// AddReminderUseCase agenda um lembrete da tarefa para quem o cria. Basta
+// poder ver a tarefa.
+type AddReminderUseCase struct {
+	UoW domain.UnitOfWork
+}
+
+func (uc *AddReminderUseCase) Execute(ctx context.Context, input AddReminderInput) (output *domainTask.Reminder, err error) {
+	ctx, end := usecase.Start(ctx, "add_reminder")
+	defer end(&err)
+
+	err = uc.UoW.Execute(ctx, func(ctx context.Context, work domain.Work) error {
+		task, err := findActiveTask(ctx, work.TaskRepo(), input.TaskID, input.UserID)
+		if err != nil {
+			return err
+		}
+
+		reminder, err := domainTask.NewReminder(task, input.UserID, input.At, input.BeforeDue)
+		if err != nil {
+			return err
+		}
+		if err := work.ReminderRepo().Save(ctx, reminder); err != nil {
+			logger(ctx).Error("error trying to save reminder", "taskID", task.ID, "error", err)
+			return err
+		}
+		if err := usecase.Audit(ctx, work.AuditRepo(), "reminder.added", "reminder", reminder.ID, nil, reminder); err != nil {
+			return err
+		}
+
+		output = reminder
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+//
+// ------------------- LIST -------------------
+//
+
+type ListRemindersInput struct {
+	TaskID string
+	UserID string
+}
+
+// ListRemindersUseCase lista os lembretes que o usuário agendou na tarefa,
+// inclusive os já disparados.
+type ListRemindersUseCase struct {
+	TaskRepo     domainTask.TaskRepository
+	ReminderRepo domainTask.ReminderRepository
+}
+
+func (uc *ListRemindersUseCase) Execute(ctx context.Context, input ListRemindersInput) (_ []*domainTask.Reminder, err error) {
+	ctx, end := usecase.Start(ctx, "list_reminders")
+	defer end(&err)
+
+	task, err := findActiveTask(ctx, uc.TaskRepo, input.TaskID, input.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	reminders, err := uc.ReminderRepo.ListByTask(ctx, task.ID)
+	if err != nil {
+		logger(ctx).Error("error trying to list task reminders", "taskID", task.ID, "error", err)
+		return nil, err
+	}
+
+	mine := make([]*domainTask.Reminder, 0, len(reminders))
+	for _, reminder := range reminders {
+		if reminder.UserID == input.UserID {
+			mine = append(mine, reminder)
+		}
+	}
+	return mine, nil
+}
+
+//
+// ------------------- DELETE -------------------
+//
+
+type DeleteReminderInput struct {
+	TaskID     string
+	ReminderID string
+	UserID     string
+}
+
+// DeleteReminderUseCase cancela um lembrete. Os de outros usuários não são
+// visíveis e dão ErrReminderNotFound.
+type DeleteReminderUseCase struct {
+	UoW domain.UnitOfWork
+}
+
+func (uc *DeleteReminderUseCase) Execute(ctx context.Context, input DeleteReminderInput) (err error) {
+	ctx, end := usecase.Start(ctx, "delete_reminder")
+	defer end(&err)
+
+	return uc.UoW.Execute(ctx, func(ctx context.Context, work domain.Work) error {
+		task, err := findActiveTask(ctx, work.TaskRepo(), input.TaskID, input.UserID)
+		if err != nil {
+			return err
+		}
+
+		reminder, err := work.ReminderRepo().Find(ctx, task.ID, input.ReminderID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return usecase.ErrReminderNotFound
+			}
+			logger(ctx).Error("error trying to find reminder", "reminderID", input.ReminderID, "error", err)
+			return err
+		}
+		if reminder.UserID != input.UserID {
+			return usecase.ErrReminderNotFound
+		}
+
+		if err := work.ReminderRepo().Delete(ctx, reminder.ID); err != nil {
+			logger(ctx).Error("error trying to delete reminder", "reminderID", reminder.ID, "error", err)
+			return err
+		}
+		return usecase.Audit(ctx, work.AuditRepo(), "reminder.deleted", "reminder", reminder.ID, reminder, nil)
+	})
+}
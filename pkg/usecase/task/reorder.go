@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+
+	"github.com/hoyci/todo-ddd/pkg/domain"
+	domainTask "github.com/hoyci/todo-ddd/pkg/domain/task"
+	"github.com/hoyci/todo-ddd/pkg/usecase"
+)
+
+type ReorderTaskInput struct {
+	TaskID string
+	UserID string
+	// Position é o índice, a partir de zero, que a tarefa passa a ocupar na
+	// lista do dono ou, em um workspace, na do workspace. Além do fim, ela
+	// vai para o fim.
+	Position int
+}
+
+// ReorderTaskUseCase move a tarefa na ordem manual da lista. A lista toda é
+// renumerada, mas só a tarefa movida gera evento: quem acompanha a lista
+// aplica o mesmo movimento à ordem que já tem.
+type ReorderTaskUseCase struct {
+	UoW domain.UnitOfWork
+}
+
+func (uc *ReorderTaskUseCase) Execute(ctx context.Context, input ReorderTaskInput) (output *domainTask.Task, err error) {
+	ctx, end := usecase.Start(ctx, "reorder_task")
+	defer end(&err)
+
+	if input.Position < 0 {
+		return nil, usecase.ErrInvalidPosition
+	}
+
+	err = uc.UoW.Execute(ctx, func(ctx context.Context, work domain.Work) error {
+		task, err := findTaskWithAccess(ctx, work, input.TaskID, input.UserID, domainTask.AccessEdit)
+		if err != nil {
+			return err
+		}
+
+		tasks, err := work.TaskRepo().List(ctx, task.UserID)
+		if err != nil {
+			logger(ctx).Error("error trying to list tasks to reorder", "taskID", task.ID, "error", err)
+			return err
+		}
+		ids := make([]string, 0, len(tasks))
+		current := -1
+		for i, t := range tasks {
+			if t.ID == task.ID {
+				current = i
+				continue
+			}
+			ids = append(ids, t.ID)
+		}
+		position := min(input.Position, len(ids))
+		if current == position && task.Position == position+1 {
+			output = task
+			return nil
+		}
+		ids = slices.Insert(ids, position, task.ID)
+
+		task.MoveTo(position + 1)
+		if err := work.TaskRepo().Reorder(ctx, ids); err != nil {
+			logger(ctx).Error("error trying to reorder tasks", "taskID", task.ID, "error", err)
+			return err
+		}
+		if err := work.TaskRepo().Update(ctx, task); err != nil {
+			logger(ctx).Error("error trying to update task", "taskID", task.ID, "error", err)
+			return err
+		}
+		if err := recordEvents(ctx, work, task); err != nil {
+			return err
+		}
+
+		output = task
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return output, nil
+}
@@ -0,0 +1,197 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/hoyci/todo-ddd/pkg/domain"
+	domainTask "github.com/hoyci/todo-ddd/pkg/domain/task"
+	domainUndo "github.com/hoyci/todo-ddd/pkg/domain/undo"
+	"github.com/hoyci/todo-ddd/pkg/usecase"
+)
+
+// recordRevision grava, dentro da transação corrente, o estado da tarefa
+// como nova revisão. Exclusões não geram revisão e devolvem nil.
+func recordRevision(ctx context.Context, work domain.Work, task *domainTask.Task, authorID string) (*domainTask.Revision, error) {
+	if task.DeletedAt != nil {
+		return nil, nil
+	}
+	revision := domainTask.NewRevision(task, authorID)
+	if err := appendRevision(ctx, work, revision); err != nil {
+		return nil, err
+	}
+	return revision, nil
+}
+
+func appendRevision(ctx context.Context, work domain.Work, revision *domainTask.Revision) error {
+	if err := work.RevisionRepo().Append(ctx, revision); err != nil {
+		logger(ctx).Error("error trying to record task revision", "taskID", revision.TaskID, "error", err)
+		return err
+	}
+	return nil
+}
+
+func findRevision(ctx context.Context, repo domainTask.RevisionRepository, taskID string, number int) (*domainTask.Revision, error) {
+	revision, err := repo.Find(ctx, taskID, number)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, usecase.ErrRevisionNotFound
+		}
+		logger(ctx).Error("error trying to find task revision", "taskID", taskID, "number", number, "error", err)
+		return nil, err
+	}
+	return revision, nil
+}
+
+//
+// ------------------- LIST -------------------
+//
+
+type ListRevisionsInput struct {
+	TaskID string
+	UserID string
+}
+
+type ListRevisionsOutput struct {
+	Revisions []*domainTask.Revision
+}
+
+type ListRevisionsUseCase struct {
+	TaskRepo     domainTask.TaskRepository
+	RevisionRepo domainTask.RevisionRepository
+}
+
+func (uc *ListRevisionsUseCase) Execute(ctx context.Context, input ListRevisionsInput) (_ *ListRevisionsOutput, err error) {
+	ctx, end := usecase.Start(ctx, "list_task_revisions")
+	defer end(&err)
+
+	task, err := findActiveTask(ctx, uc.TaskRepo, input.TaskID, input.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions, err := uc.RevisionRepo.ListByTask(ctx, task.ID)
+	if err != nil {
+		logger(ctx).Error("error trying to list task revisions", "taskID", task.ID, "error", err)
+		return nil, err
+	}
+	return &ListRevisionsOutput{Revisions: revisions}, nil
+}
+
+//
+// ------------------- DIFF -------------------
+//
+
+type DiffRevisionsInput struct {
+	TaskID string
+	UserID string
+	From   int
+	To     int
+}
+
+type DiffRevisionsUseCase struct {
+	TaskRepo     domainTask.TaskRepository
+	RevisionRepo domainTask.RevisionRepository
+}
+
+func (uc *DiffRevisionsUseCase) Execute(ctx context.Context, input DiffRevisionsInput) (_ *domainTask.RevisionDiff, err error) {
+	ctx, end := usecase.Start(ctx, "diff_task_revisions")
+	defer end(&err)
+
+	task, err := findActiveTask(ctx, uc.TaskRepo, input.TaskID, input.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	from, err := findRevision(ctx, uc.RevisionRepo, task.ID, input.From)
+	if err != nil {
+		return nil, err
+	}
+	to, err := findRevision(ctx, uc.RevisionRepo, task.ID, input.To)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := domainTask.DiffRevisions(from, to)
+	return &diff, nil
+}
+
+//
+// ------------------- REVERT -------------------
+//
+
+type RevertTaskInput struct {
+	TaskID string
+	UserID string
+	Number int
+}
+
+type RevertTaskOutput struct {
+	domainTask.Task
+	// Revision é a revisão criada pela reversão; nil se a tarefa já estava
+	// no estado pedido.
+	Revision *domainTask.Revision
+	// Undo desfaz a reversão; nil se nada mudou ou se UndoWindow for zero.
+	Undo *domainUndo.Undo
+}
+
+// RevertTaskUseCase devolve a tarefa ao estado de uma revisão anterior,
+// registrando o resultado como uma nova revisão.
+type RevertTaskUseCase struct {
+	UoW domain.UnitOfWork
+	Options
+}
+
+func (uc *RevertTaskUseCase) Execute(ctx context.Context, input RevertTaskInput) (output *RevertTaskOutput, err error) {
+	ctx, end := usecase.Start(ctx, "revert_task")
+	defer end(&err)
+
+	err = uc.UoW.Execute(ctx, func(ctx context.Context, work domain.Work) error {
+		task, err := findTaskWithAccess(ctx, work, input.TaskID, input.UserID, domainTask.AccessEdit)
+		if err != nil {
+			return err
+		}
+		target, err := findRevision(ctx, work.RevisionRepo(), task.ID, input.Number)
+		if err != nil {
+			return err
+		}
+
+		before := *task
+		if !task.Revert(target) {
+			output = &RevertTaskOutput{Task: *task}
+			return nil
+		}
+
+		if err := work.TaskRepo().Update(ctx, task); err != nil {
+			logger(ctx).Error("error trying to revert task", "taskID", task.ID, "revision", target.Number, "error", err)
+			return err
+		}
+		if err := recordEvents(ctx, work, task); err != nil {
+			return err
+		}
+		if err := auditTask(ctx, work, "task.reverted", &before, task); err != nil {
+			return err
+		}
+		if err := rescheduleReminders(ctx, work, task); err != nil {
+			return err
+		}
+
+		revision := domainTask.NewRevision(task, input.UserID)
+		revision.RevertedFrom = target.Number
+		if err := appendRevision(ctx, work, revision); err != nil {
+			return err
+		}
+		undo, err := issueUndo(ctx, work, uc.UndoWindow, input.UserID, "task.reverted", []domainUndo.Step{restoreStep(revision)})
+		if err != nil {
+			return err
+		}
+
+		output = &RevertTaskOutput{Task: *task, Revision: revision, Undo: undo}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return output, nil
+}
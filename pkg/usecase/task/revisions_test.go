@@ -0,0 +1,76 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hoyci/todo-ddd/internal/adapters/db/sqlite"
+	"github.com/hoyci/todo-ddd/internal/adapters/events"
+	domainTask "github.com/hoyci/todo-ddd/pkg/domain/task"
+	"github.com/hoyci/todo-ddd/pkg/usecase"
+)
+
+func TestRevisions(t *testing.T) {
+	db := newTestDB(t)
+	uow := sqlite.NewSQLiteUnitOfWork(db, events.NewBroker(0))
+	ctx := context.Background()
+	userID := saveTestUser(t, uow, "ada@example.com", true)
+
+	task, err := (&CreateTaskUseCase{UoW: uow}).Execute(ctx, CreateTaskInput{Title: "Draft", Description: "a\nb", Priority: 1, UserID: userID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = (&UpdateTaskUseCase{UoW: uow}).Execute(ctx, UpdateTaskInput{TaskID: task.ID, Title: "Final", Description: "a\nc", Priority: 1, UserID: userID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	taskRepo, revisionRepo := sqlite.NewSQLiteTaskRepository(db), sqlite.NewSQLiteRevisionRepository(db)
+	diff := &DiffRevisionsUseCase{TaskRepo: taskRepo, RevisionRepo: revisionRepo}
+	got, err := diff.Execute(ctx, DiffRevisionsInput{TaskID: task.ID, UserID: userID, From: 1, To: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Changes) != 2 || got.Changes[0].Field != "title" || got.Changes[1].Field != "description" {
+		t.Errorf("Changes = %+v, want title and description", got.Changes)
+	}
+	if len(got.Description) != 3 || got.Description[1].Op != domainTask.LineDelete || got.Description[2].Op != domainTask.LineInsert {
+		t.Errorf("Description = %+v, want b replaced by c", got.Description)
+	}
+	if _, err := diff.Execute(ctx, DiffRevisionsInput{TaskID: task.ID, UserID: userID, From: 1, To: 9}); !errors.Is(err, usecase.ErrRevisionNotFound) {
+		t.Errorf("diff against a missing revision: error = %v, want %v", err, usecase.ErrRevisionNotFound)
+	}
+
+	revert := &RevertTaskUseCase{UoW: uow, Options: Options{UndoWindow: time.Minute}}
+	reverted, err := revert.Execute(ctx, RevertTaskInput{TaskID: task.ID, UserID: userID, Number: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reverted.Title != "Draft" || reverted.Description != "a\nb" {
+		t.Errorf("reverted task = %q %q, want the state of revision 1", reverted.Title, reverted.Description)
+	}
+	if reverted.Revision == nil || reverted.Revision.Number != 3 || reverted.Revision.RevertedFrom != 1 {
+		t.Errorf("Revision = %+v, want number 3 reverted from 1", reverted.Revision)
+	}
+	if reverted.Undo == nil {
+		t.Error("Undo = nil with an undo window")
+	}
+
+	// A tarefa já está no estado da revisão 1: nada é gravado.
+	again, err := revert.Execute(ctx, RevertTaskInput{TaskID: task.ID, UserID: userID, Number: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.Revision != nil || again.Undo != nil {
+		t.Errorf("second revert = %+v, want no revision and no undo", again)
+	}
+	if last, err := revisionRepo.LastNumber(ctx, task.ID); err != nil || last != 3 {
+		t.Errorf("LastNumber = %d, %v; want 3", last, err)
+	}
+
+	if _, err := revert.Execute(ctx, RevertTaskInput{TaskID: task.ID, UserID: userID, Number: 9}); !errors.Is(err, usecase.ErrRevisionNotFound) {
+		t.Errorf("revert to a missing revision: error = %v, want %v", err, usecase.ErrRevisionNotFound)
+	}
+}
@@ -0,0 +1,372 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/hoyci/todo-ddd/pkg/domain"
+	domainTask "github.com/hoyci/todo-ddd/pkg/domain/task"
+	domainUndo "github.com/hoyci/todo-ddd/pkg/domain/undo"
+	"github.com/hoyci/todo-ddd/pkg/usecase"
+)
+
+//
+// ------------------- ASSIGN -------------------
+//
+
+type AssignTaskInput struct {
+	TaskID string
+	UserID string
+	// AssigneeID vazio remove a designação.
+	AssigneeID string
+}
+
+type AssignTaskOutput struct {
+	domainTask.Task
+	// Undo desfaz a designação; nil se nada mudou ou se UndoWindow for zero.
+	Undo *domainUndo.Undo
+}
+
+// AssignTaskUseCase designa o responsável pela tarefa, que passa a
+// acompanhá-la. O responsável anterior deixa de acompanhar se não tiver
+// outro acesso.
+type AssignTaskUseCase struct {
+	UoW domain.UnitOfWork
+	Options
+}
+
+func (uc *AssignTaskUseCase) Execute(ctx context.Context, input AssignTaskInput) (output *AssignTaskOutput, err error) {
+	ctx, end := usecase.Start(ctx, "assign_task")
+	defer end(&err)
+
+	err = uc.UoW.Execute(ctx, func(ctx context.Context, work domain.Work) error {
+		task, err := findTaskWithAccess(ctx, work, input.TaskID, input.UserID, domainTask.AccessEdit)
+		if err != nil {
+			return err
+		}
+		if input.AssigneeID != "" {
+			if _, err := activeUser(ctx, work, input.AssigneeID); err != nil {
+				return err
+			}
+			if err := requireMember(ctx, work, task, input.AssigneeID); err != nil {
+				return err
+			}
+		}
+
+		before := *task
+		previous := task.AssigneeID
+		if !task.Assign(input.AssigneeID) {
+			output = &AssignTaskOutput{Task: *task}
+			return nil
+		}
+
+		if err := work.TaskRepo().Update(ctx, task); err != nil {
+			logger(ctx).Error("error trying to assign task", "taskID", task.ID, "error", err)
+			return err
+		}
+		if input.AssigneeID != "" {
+			watcher := &domainTask.Watcher{TaskID: task.ID, UserID: input.AssigneeID, CreatedAt: time.Now()}
+			if err := work.WatcherRepo().Add(ctx, watcher); err != nil {
+				return err
+			}
+		}
+		if previous != "" {
+			if err := dropWatcherWithoutAccess(ctx, work, task, previous); err != nil {
+				return err
+			}
+		}
+		if err := recordEvents(ctx, work, task); err != nil {
+			return err
+		}
+		if err := auditTask(ctx, work, "task.assigned", &before, task); err != nil {
+			return err
+		}
+		if err := notifyAssigned(ctx, work, task, input.UserID); err != nil {
+			return err
+		}
+		revision, err := recordRevision(ctx, work, task, input.UserID)
+		if err != nil {
+			return err
+		}
+		undo, err := issueUndo(ctx, work, uc.UndoWindow, input.UserID, "task.assigned", []domainUndo.Step{restoreStep(revision)})
+		if err != nil {
+			return err
+		}
+
+		output = &AssignTaskOutput{Task: *task, Undo: undo}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+//
+// ------------------- SHARE -------------------
+//
+
+type ShareTaskInput struct {
+	TaskID string
+	UserID string
+	// TargetUserID recebe o acesso; um compartilhamento existente tem a
+	// permissão trocada.
+	TargetUserID string
+	Permission   string
+}
+
+// ShareTaskUseCase concede acesso à tarefa a outro usuário. Só o dono
+// compartilha.
+type ShareTaskUseCase struct {
+	UoW domain.UnitOfWork
+}
+
+func (uc *ShareTaskUseCase) Execute(ctx context.Context, input ShareTaskInput) (output *domainTask.Share, err error) {
+	ctx, end := usecase.Start(ctx, "share_task")
+	defer end(&err)
+
+	err = uc.UoW.Execute(ctx, func(ctx context.Context, work domain.Work) error {
+		task, err := findTaskWithAccess(ctx, work, input.TaskID, input.UserID, domainTask.AccessOwner)
+		if err != nil {
+			return err
+		}
+		if task.WorkspaceID != "" {
+			return usecase.ErrShareInWorkspace
+		}
+		if input.TargetUserID == task.UserID {
+			return usecase.ErrCannotShareWithOwner
+		}
+		if _, err := activeUser(ctx, work, input.TargetUserID); err != nil {
+			return err
+		}
+
+		share, err := domainTask.NewShare(task.ID, input.TargetUserID, input.UserID, input.Permission)
+		if err != nil {
+			return err
+		}
+		previous, err := work.ShareRepo().Find(ctx, task.ID, share.UserID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+		if err := work.ShareRepo().Save(ctx, share); err != nil {
+			logger(ctx).Error("error trying to save task share", "taskID", task.ID, "error", err)
+			return err
+		}
+
+		output, err = work.ShareRepo().Find(ctx, task.ID, share.UserID)
+		if err != nil {
+			return err
+		}
+		return usecase.Audit(ctx, work.AuditRepo(), "task.shared", "task", task.ID, previous, output)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+//
+// ------------------- REVOKE -------------------
+//
+
+type RevokeShareInput struct {
+	TaskID       string
+	UserID       string
+	TargetUserID string
+}
+
+// RevokeShareUseCase remove o compartilhamento. O usuário deixa de
+// acompanhar a tarefa, a menos que ainda seja o responsável.
+type RevokeShareUseCase struct {
+	UoW domain.UnitOfWork
+}
+
+func (uc *RevokeShareUseCase) Execute(ctx context.Context, input RevokeShareInput) (err error) {
+	ctx, end := usecase.Start(ctx, "revoke_share")
+	defer end(&err)
+
+	return uc.UoW.Execute(ctx, func(ctx context.Context, work domain.Work) error {
+		task, err := findTaskWithAccess(ctx, work, input.TaskID, input.UserID, domainTask.AccessOwner)
+		if err != nil {
+			return err
+		}
+
+		share, err := work.ShareRepo().Find(ctx, task.ID, input.TargetUserID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return usecase.ErrShareNotFound
+			}
+			return err
+		}
+		if err := work.ShareRepo().Delete(ctx, task.ID, input.TargetUserID); err != nil {
+			logger(ctx).Error("error trying to delete task share", "taskID", task.ID, "error", err)
+			return err
+		}
+		if err := usecase.Audit(ctx, work.AuditRepo(), "task.share_revoked", "task", task.ID, share, nil); err != nil {
+			return err
+		}
+		return dropWatcherWithoutAccess(ctx, work, task, input.TargetUserID)
+	})
+}
+
+//
+// ------------------- LIST SHARES -------------------
+//
+
+type ListSharesInput struct {
+	TaskID string
+	UserID string
+}
+
+type ListSharesUseCase struct {
+	TaskRepo  domainTask.TaskRepository
+	ShareRepo domainTask.ShareRepository
+}
+
+func (uc *ListSharesUseCase) Execute(ctx context.Context, input ListSharesInput) (_ []*domainTask.Share, err error) {
+	ctx, end := usecase.Start(ctx, "list_shares")
+	defer end(&err)
+
+	task, err := findActiveTask(ctx, uc.TaskRepo, input.TaskID, input.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	shares, err := uc.ShareRepo.ListByTask(ctx, task.ID)
+	if err != nil {
+		logger(ctx).Error("error trying to list task shares", "taskID", task.ID, "error", err)
+		return nil, err
+	}
+	return shares, nil
+}
+
+//
+// ------------------- WATCH -------------------
+//
+
+type WatchTaskInput struct {
+	TaskID string
+	UserID string
+}
+
+// WatchTaskUseCase faz o usuário acompanhar uma tarefa que ele enxerga,
+// passando a receber os eventos dela.
+type WatchTaskUseCase struct {
+	UoW domain.UnitOfWork
+}
+
+func (uc *WatchTaskUseCase) Execute(ctx context.Context, input WatchTaskInput) (err error) {
+	ctx, end := usecase.Start(ctx, "watch_task")
+	defer end(&err)
+
+	return uc.UoW.Execute(ctx, func(ctx context.Context, work domain.Work) error {
+		task, err := findActiveTask(ctx, work.TaskRepo(), input.TaskID, input.UserID)
+		if err != nil {
+			return err
+		}
+
+		watcher := &domainTask.Watcher{TaskID: task.ID, UserID: input.UserID, CreatedAt: time.Now()}
+		if err := work.WatcherRepo().Add(ctx, watcher); err != nil {
+			logger(ctx).Error("error trying to add task watcher", "taskID", task.ID, "error", err)
+			return err
+		}
+		return usecase.Audit(ctx, work.AuditRepo(), "task.watched", "task", task.ID, nil, watcher)
+	})
+}
+
+type UnwatchTaskUseCase struct {
+	UoW domain.UnitOfWork
+}
+
+func (uc *UnwatchTaskUseCase) Execute(ctx context.Context, input WatchTaskInput) (err error) {
+	ctx, end := usecase.Start(ctx, "unwatch_task")
+	defer end(&err)
+
+	return uc.UoW.Execute(ctx, func(ctx context.Context, work domain.Work) error {
+		task, err := findActiveTask(ctx, work.TaskRepo(), input.TaskID, input.UserID)
+		if err != nil {
+			return err
+		}
+
+		if err := work.WatcherRepo().Remove(ctx, task.ID, input.UserID); err != nil {
+			logger(ctx).Error("error trying to remove task watcher", "taskID", task.ID, "error", err)
+			return err
+		}
+		watcher := &domainTask.Watcher{TaskID: task.ID, UserID: input.UserID}
+		return usecase.Audit(ctx, work.AuditRepo(), "task.unwatched", "task", task.ID, watcher, nil)
+	})
+}
+
+type ListWatchersInput struct {
+	TaskID string
+	UserID string
+}
+
+type ListWatchersUseCase struct {
+	TaskRepo    domainTask.TaskRepository
+	WatcherRepo domainTask.WatcherRepository
+}
+
+func (uc *ListWatchersUseCase) Execute(ctx context.Context, input ListWatchersInput) (_ []*domainTask.Watcher, err error) {
+	ctx, end := usecase.Start(ctx, "list_watchers")
+	defer end(&err)
+
+	task, err := findActiveTask(ctx, uc.TaskRepo, input.TaskID, input.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	watchers, err := uc.WatcherRepo.ListByTask(ctx, task.ID)
+	if err != nil {
+		logger(ctx).Error("error trying to list task watchers", "taskID", task.ID, "error", err)
+		return nil, err
+	}
+	return watchers, nil
+}
+
+//
+// ------------------- ASSIGNED TO ME -------------------
+//
+
+type ListAssignedTasksUseCase struct {
+	TaskRepo domainTask.TaskRepository
+}
+
+func (uc *ListAssignedTasksUseCase) Execute(ctx context.Context, userID string) (_ []*domainTask.Task, err error) {
+	ctx, end := usecase.Start(ctx, "list_assigned_tasks")
+	defer end(&err)
+
+	tasks, err := uc.TaskRepo.ListAssigned(ctx, userID)
+	if err != nil {
+		logger(ctx).Error("error trying to list assigned tasks", "userID", userID, "error", err)
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// dropWatcherWithoutAccess remove userID dos watchers se ele não tiver mais
+// acesso à tarefa, para que não continue recebendo seus eventos.
+func dropWatcherWithoutAccess(ctx context.Context, work domain.Work, task *domainTask.Task, userID string) error {
+	access, err := accessFor(ctx, work, task, userID)
+	if err != nil || access != domainTask.AccessNone {
+		return err
+	}
+	return work.WatcherRepo().Remove(ctx, task.ID, userID)
+}
+
+// requireMember exige que userID participe do workspace da tarefa, para que
+// ela não seja designada a quem não consegue enxergá-la.
+func requireMember(ctx context.Context, work domain.Work, task *domainTask.Task, userID string) error {
+	if task.WorkspaceID == "" {
+		return nil
+	}
+	if _, err := work.WorkspaceRepo().FindMember(ctx, task.WorkspaceID, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return usecase.ErrMemberNotFound
+		}
+		return err
+	}
+	return nil
+}
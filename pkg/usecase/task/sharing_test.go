@@ -0,0 +1,151 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/hoyci/todo-ddd/internal/adapters/db/sqlite"
+	"github.com/hoyci/todo-ddd/internal/adapters/events"
+	"github.com/hoyci/todo-ddd/pkg/domain/valueobject"
+	"github.com/hoyci/todo-ddd/pkg/usecase"
+)
+
+func TestSharingAccess(t *testing.T) {
+	uow := newTestUoW(t)
+	ctx := context.Background()
+	owner := saveTestUser(t, uow, "owner@example.com", true)
+	viewer := saveTestUser(t, uow, "viewer@example.com", true)
+	editor := saveTestUser(t, uow, "editor@example.com", true)
+	stranger := saveTestUser(t, uow, "stranger@example.com", true)
+
+	task, err := (&CreateTaskUseCase{UoW: uow}).Execute(ctx, CreateTaskInput{Title: "Task", Priority: 1, UserID: owner})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	share := &ShareTaskUseCase{UoW: uow}
+	shareTests := []struct {
+		name       string
+		userID     string
+		target     string
+		permission string
+		wantErr    error
+	}{
+		{"view share", owner, viewer, "view", nil},
+		{"edit share", owner, editor, "edit", nil},
+		{"with the owner", owner, owner, "view", usecase.ErrCannotShareWithOwner},
+		{"invalid permission", owner, stranger, "admin", valueobject.ErrInvalidSharePermission},
+		{"by an editor", editor, stranger, "view", usecase.ErrTaskForbidden},
+		{"by a stranger", stranger, viewer, "view", usecase.ErrTaskNotFound},
+	}
+	for _, tt := range shareTests {
+		_, err := share.Execute(ctx, ShareTaskInput{TaskID: task.ID, UserID: tt.userID, TargetUserID: tt.target, Permission: tt.permission})
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("share %s: error = %v, want %v", tt.name, err, tt.wantErr)
+		}
+	}
+
+	rename := func(userID string) error {
+		_, err := (&PatchTaskUseCase{UoW: uow}).Execute(ctx, PatchTaskInput{
+			TaskID: task.ID,
+			UserID: userID,
+			Patch: func(current TaskFields) (TaskFields, error) {
+				title := "Renamed by " + userID
+				current.Title = &title
+				return current, nil
+			},
+		})
+		return err
+	}
+	editTests := []struct {
+		name    string
+		userID  string
+		wantErr error
+	}{
+		{"owner", owner, nil},
+		{"edit share", editor, nil},
+		{"view share", viewer, usecase.ErrTaskForbidden},
+		{"stranger", stranger, usecase.ErrTaskNotFound},
+	}
+	for _, tt := range editTests {
+		if err := rename(tt.userID); !errors.Is(err, tt.wantErr) {
+			t.Errorf("edit by %s: error = %v, want %v", tt.name, err, tt.wantErr)
+		}
+	}
+
+	if err := (&RevokeShareUseCase{UoW: uow}).Execute(ctx, RevokeShareInput{TaskID: task.ID, UserID: owner, TargetUserID: editor}); err != nil {
+		t.Fatal(err)
+	}
+	if err := rename(editor); !errors.Is(err, usecase.ErrTaskNotFound) {
+		t.Errorf("edit after revoke: error = %v, want %v", err, usecase.ErrTaskNotFound)
+	}
+}
+
+func TestAssignTaskWatchers(t *testing.T) {
+	db := newTestDB(t)
+	uow := sqlite.NewSQLiteUnitOfWork(db, events.NewBroker(0))
+	ctx := context.Background()
+	owner := saveTestUser(t, uow, "owner@example.com", true)
+	first := saveTestUser(t, uow, "first@example.com", true)
+	second := saveTestUser(t, uow, "second@example.com", true)
+
+	task, err := (&CreateTaskUseCase{UoW: uow}).Execute(ctx, CreateTaskInput{Title: "Task", Priority: 1, UserID: owner})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// first também recebe a tarefa compartilhada e continua acompanhando
+	// depois de perder a designação.
+	if _, err := (&ShareTaskUseCase{UoW: uow}).Execute(ctx, ShareTaskInput{TaskID: task.ID, UserID: owner, TargetUserID: first, Permission: "view"}); err != nil {
+		t.Fatal(err)
+	}
+
+	assign := &AssignTaskUseCase{UoW: uow}
+	watchers := &ListWatchersUseCase{TaskRepo: sqlite.NewSQLiteTaskRepository(db), WatcherRepo: sqlite.NewSQLiteWatcherRepository(db)}
+	assigned := &ListAssignedTasksUseCase{TaskRepo: sqlite.NewSQLiteTaskRepository(db)}
+
+	tests := []struct {
+		name         string
+		assignee     string
+		wantWatchers []string
+		wantAssigned map[string]int
+	}{
+		{"assign first", first, []string{first}, map[string]int{first: 1, second: 0}},
+		{"reassign to second", second, []string{first, second}, map[string]int{first: 0, second: 1}},
+		{"unassign", "", []string{first}, map[string]int{first: 0, second: 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := assign.Execute(ctx, AssignTaskInput{TaskID: task.ID, UserID: owner, AssigneeID: tt.assignee}); err != nil {
+				t.Fatal(err)
+			}
+			list, err := watchers.Execute(ctx, ListWatchersInput{TaskID: task.ID, UserID: owner})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var ids []string
+			for _, watcher := range list {
+				ids = append(ids, watcher.UserID)
+			}
+			slices.Sort(ids)
+			want := slices.Sorted(slices.Values(tt.wantWatchers))
+			if !slices.Equal(ids, want) {
+				t.Errorf("watchers = %v, want %v", ids, want)
+			}
+			for userID, n := range tt.wantAssigned {
+				tasks, err := assigned.Execute(ctx, userID)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(tasks) != n {
+					t.Errorf("%s has %d assigned tasks, want %d", userID, len(tasks), n)
+				}
+			}
+		})
+	}
+
+	if _, err := assign.Execute(ctx, AssignTaskInput{TaskID: task.ID, UserID: owner, AssigneeID: "missing"}); !errors.Is(err, usecase.ErrUserNotFoundOrDeleted) {
+		t.Errorf("assign to a missing user: error = %v", err)
+	}
+}
@@ -0,0 +1,266 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/hoyci/todo-ddd/pkg/domain"
+	domainTask "github.com/hoyci/todo-ddd/pkg/domain/task"
+	domainUndo "github.com/hoyci/todo-ddd/pkg/domain/undo"
+	"github.com/hoyci/todo-ddd/pkg/usecase"
+)
+
+// issueUndo grava, na transação corrente, o desfazer da mudança descrita
+// pelos passos. Devolve nil se window for zero ou não houver passos.
+func issueUndo(ctx context.Context, work domain.Work, window time.Duration, userID, action string, steps []domainUndo.Step) (*domainUndo.Undo, error) {
+	if window <= 0 || len(steps) == 0 {
+		return nil, nil
+	}
+
+	undo, err := domainUndo.NewUndo(userID, action, steps, window)
+	if err != nil {
+		return nil, err
+	}
+	if err := work.UndoRepo().Save(ctx, undo); err != nil {
+		logger(ctx).Error("error trying to save undo", "action", action, "error", err)
+		return nil, err
+	}
+	return undo, nil
+}
+
+// restoreStep desfaz a mudança que gravou a revisão after, voltando à
+// revisão anterior a ela.
+func restoreStep(after *domainTask.Revision) domainUndo.Step {
+	return domainUndo.Step{TaskID: after.TaskID, Op: domainUndo.OpRestore, Restore: after.Number - 1, Version: after.Number}
+}
+
+// deleteStep desfaz a criação que gravou a revisão after.
+func deleteStep(after *domainTask.Revision) domainUndo.Step {
+	return domainUndo.Step{TaskID: after.TaskID, Op: domainUndo.OpDelete, Version: after.Number}
+}
+
+// undeleteStep desfaz a exclusão da tarefa, que não grava revisão.
+func undeleteStep(ctx context.Context, work domain.Work, taskID string) (domainUndo.Step, error) {
+	version, err := work.RevisionRepo().LastNumber(ctx, taskID)
+	if err != nil {
+		logger(ctx).Error("error trying to find last task revision", "taskID", taskID, "error", err)
+		return domainUndo.Step{}, err
+	}
+	return domainUndo.Step{TaskID: taskID, Op: domainUndo.OpUndelete, Version: version}, nil
+}
+
+// stepFor escolhe o passo que desfaz a mudança: a exclusão de uma tarefa
+// criada, a restauração de uma excluída ou a volta à revisão anterior.
+func stepFor(ctx context.Context, work domain.Work, task *domainTask.Task, created bool, revision *domainTask.Revision) (domainUndo.Step, error) {
+	switch {
+	case task.DeletedAt != nil:
+		return undeleteStep(ctx, work, task.ID)
+	case created:
+		return deleteStep(revision), nil
+	default:
+		return restoreStep(revision), nil
+	}
+}
+
+type UndoInput struct {
+	Token  string
+	UserID string
+}
+
+type UndoOutput struct {
+	Action string
+	// Tasks são as tarefas afetadas, no estado em que ficaram.
+	Tasks []*domainTask.Task
+}
+
+// UndoUseCase desfaz, em uma única transação, a mudança associada ao token.
+// Recusa se alguma das tarefas mudou desde então.
+type UndoUseCase struct {
+	UoW domain.UnitOfWork
+}
+
+func (uc *UndoUseCase) Execute(ctx context.Context, input UndoInput) (output *UndoOutput, err error) {
+	ctx, end := usecase.Start(ctx, "undo")
+	defer end(&err)
+
+	err = uc.UoW.Execute(ctx, func(ctx context.Context, work domain.Work) error {
+		undo, err := work.UndoRepo().FindByToken(ctx, input.Token)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return usecase.ErrUndoNotFound
+			}
+			logger(ctx).Error("error trying to find undo", "error", err)
+			return err
+		}
+		if undo.UserID != input.UserID {
+			return usecase.ErrUndoNotFound
+		}
+		if !undo.Pending(time.Now()) {
+			return usecase.ErrUndoExpired
+		}
+
+		if err := checkUnchanged(ctx, work, undo, input.UserID); err != nil {
+			return err
+		}
+
+		output = &UndoOutput{Action: undo.Action}
+		seen := map[string]int{}
+		for i := len(undo.Steps) - 1; i >= 0; i-- {
+			task, err := applyUndoStep(ctx, work, input.UserID, undo.Steps[i])
+			if err != nil {
+				return err
+			}
+			if j, ok := seen[task.ID]; ok {
+				output.Tasks[j] = task
+				continue
+			}
+			seen[task.ID] = len(output.Tasks)
+			output.Tasks = append(output.Tasks, task)
+		}
+
+		undo.Use()
+		if err := work.UndoRepo().Update(ctx, undo); err != nil {
+			logger(ctx).Error("error trying to mark undo as used", "action", undo.Action, "error", err)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+// checkUnchanged confere, antes de aplicar qualquer passo, que cada tarefa
+// continua na revisão e no estado de exclusão deixados pela mudança.
+func checkUnchanged(ctx context.Context, work domain.Work, undo *domainUndo.Undo, userID string) error {
+	for taskID, expected := range undo.Expected() {
+		task, err := work.TaskRepo().FindByID(ctx, taskID, userID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return usecase.ErrTaskNotFound
+			}
+			logger(ctx).Error("error trying to find task by id", "taskID", taskID, "error", err)
+			return err
+		}
+		version, err := work.RevisionRepo().LastNumber(ctx, taskID)
+		if err != nil {
+			logger(ctx).Error("error trying to find last task revision", "taskID", taskID, "error", err)
+			return err
+		}
+		if version != expected.Version || (task.DeletedAt != nil) != expected.Deleted {
+			return usecase.ErrUndoConflict
+		}
+	}
+	return nil
+}
+
+func applyUndoStep(ctx context.Context, work domain.Work, userID string, step domainUndo.Step) (*domainTask.Task, error) {
+	switch step.Op {
+	case domainUndo.OpRestore:
+		task, err := findTaskWithAccess(ctx, work, step.TaskID, userID, domainTask.AccessEdit)
+		if err != nil {
+			return nil, err
+		}
+		target, err := findRevision(ctx, work.RevisionRepo(), task.ID, step.Restore)
+		if err != nil {
+			return nil, err
+		}
+
+		before := *task
+		if !task.Revert(target) {
+			return task, nil
+		}
+		if err := work.TaskRepo().Update(ctx, task); err != nil {
+			logger(ctx).Error("error trying to undo task change", "taskID", task.ID, "error", err)
+			return nil, err
+		}
+		if err := recordEvents(ctx, work, task); err != nil {
+			return nil, err
+		}
+		if err := auditTask(ctx, work, "task.reverted", &before, task); err != nil {
+			return nil, err
+		}
+		if err := rescheduleReminders(ctx, work, task); err != nil {
+			return nil, err
+		}
+		revision := domainTask.NewRevision(task, userID)
+		revision.RevertedFrom = target.Number
+		if err := appendRevision(ctx, work, revision); err != nil {
+			return nil, err
+		}
+		return task, nil
+
+	case domainUndo.OpDelete:
+		task, err := findTaskWithAccess(ctx, work, step.TaskID, userID, domainTask.AccessOwner)
+		if err != nil {
+			return nil, err
+		}
+
+		before := *task
+		task.Delete()
+		if err := work.TaskRepo().Delete(ctx, task.ID, *task.DeletedAt); err != nil {
+			logger(ctx).Error("error trying to undo task creation", "taskID", task.ID, "error", err)
+			return nil, err
+		}
+		if err := recordEvents(ctx, work, task); err != nil {
+			return nil, err
+		}
+		if err := auditTask(ctx, work, "task.deleted", &before, task); err != nil {
+			return nil, err
+		}
+		return task, nil
+
+	case domainUndo.OpUndelete:
+		task, err := findDeletedTask(ctx, work, step.TaskID, userID)
+		if err != nil {
+			return nil, err
+		}
+
+		before := *task
+		task.Undelete()
+		if err := work.TaskRepo().Restore(ctx, task.ID, *task.UpdatedAt); err != nil {
+			logger(ctx).Error("error trying to undo task deletion", "taskID", task.ID, "error", err)
+			return nil, err
+		}
+		if err := recordEvents(ctx, work, task); err != nil {
+			return nil, err
+		}
+		if err := auditTask(ctx, work, "task.restored", &before, task); err != nil {
+			return nil, err
+		}
+		if _, err := recordRevision(ctx, work, task, userID); err != nil {
+			return nil, err
+		}
+		return task, nil
+
+	default:
+		return nil, usecase.ErrUndoConflict
+	}
+}
+
+// findDeletedTask busca uma tarefa excluída exigindo de userID o acesso de
+// dono, o mesmo que a exclusão exigiu.
+func findDeletedTask(ctx context.Context, work domain.Work, taskID, userID string) (*domainTask.Task, error) {
+	task, err := work.TaskRepo().FindByID(ctx, taskID, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, usecase.ErrTaskNotFound
+		}
+		return nil, err
+	}
+	if task.DeletedAt == nil {
+		return nil, usecase.ErrUndoConflict
+	}
+
+	access, err := accessFor(ctx, work, task, userID)
+	if err != nil {
+		return nil, err
+	}
+	if access < domainTask.AccessOwner {
+		return nil, usecase.ErrTaskForbidden
+	}
+	return task, nil
+}
@@ -0,0 +1,200 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hoyci/todo-ddd/pkg/domain"
+	domainUndo "github.com/hoyci/todo-ddd/pkg/domain/undo"
+	"github.com/hoyci/todo-ddd/pkg/domain/valueobject"
+	"github.com/hoyci/todo-ddd/pkg/usecase"
+)
+
+func TestUndo(t *testing.T) {
+	uow := newTestUoW(t)
+	ctx := context.Background()
+	userID := saveTestUser(t, uow, "ada@example.com", true)
+	otherID := saveTestUser(t, uow, "bob@example.com", true)
+	opts := Options{UndoWindow: time.Minute}
+
+	create := func(t *testing.T) string {
+		t.Helper()
+		out, err := (&CreateTaskUseCase{UoW: uow}).Execute(ctx, CreateTaskInput{Title: "Draft", Priority: 1, UserID: userID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return out.ID
+	}
+	update := func(t *testing.T, taskID, title string) *domainUndo.Undo {
+		t.Helper()
+		out, err := (&UpdateTaskUseCase{UoW: uow, Options: opts}).Execute(ctx, UpdateTaskInput{TaskID: taskID, Title: title, Priority: 1, UserID: userID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return out.Undo
+	}
+	remove := func(t *testing.T, taskID string) *domainUndo.Undo {
+		t.Helper()
+		out, err := (&DeleteTaskUseCase{UoW: uow, Options: opts}).Execute(ctx, DeleteTaskInput{TaskID: taskID, UserID: userID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return out.Undo
+	}
+	share := func(t *testing.T, taskID, permission string) {
+		t.Helper()
+		if _, err := (&ShareTaskUseCase{UoW: uow}).Execute(ctx, ShareTaskInput{TaskID: taskID, UserID: userID, TargetUserID: otherID, Permission: permission}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// sharedUpdate devolve o undo de uma edição feita por otherID em uma
+	// tarefa compartilhada com ele.
+	sharedUpdate := func(t *testing.T, permission string) *domainUndo.Undo {
+		t.Helper()
+		taskID := create(t)
+		share(t, taskID, permission)
+		out, err := (&UpdateTaskUseCase{UoW: uow, Options: opts}).Execute(ctx, UpdateTaskInput{TaskID: taskID, Title: "Final", Priority: 1, UserID: otherID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return out.Undo
+	}
+	undo := &UndoUseCase{UoW: uow}
+
+	tests := []struct {
+		name    string
+		prepare func(t *testing.T) (token, userID string)
+		wantErr error
+	}{
+		{
+			name: "update",
+			prepare: func(t *testing.T) (string, string) {
+				return update(t, create(t), "Final").Token, userID
+			},
+		},
+		{
+			name: "changed by a later update",
+			prepare: func(t *testing.T) (string, string) {
+				taskID := create(t)
+				token := update(t, taskID, "Final").Token
+				update(t, taskID, "Later")
+				return token, userID
+			},
+			wantErr: usecase.ErrUndoConflict,
+		},
+		{
+			name: "changed by a status update",
+			prepare: func(t *testing.T) (string, string) {
+				taskID := create(t)
+				token := update(t, taskID, "Final").Token
+				_, err := (&UpdateTaskStatusUseCase{UoW: uow}).Execute(ctx, UpdateTaskStatusInput{TaskID: taskID, Status: valueobject.StatusCompleted, UserID: userID})
+				if err != nil {
+					t.Fatal(err)
+				}
+				return token, userID
+			},
+			wantErr: usecase.ErrUndoConflict,
+		},
+		{
+			name: "updated task deleted since",
+			prepare: func(t *testing.T) (string, string) {
+				taskID := create(t)
+				token := update(t, taskID, "Final").Token
+				remove(t, taskID)
+				return token, userID
+			},
+			wantErr: usecase.ErrUndoConflict,
+		},
+		{
+			name: "delete",
+			prepare: func(t *testing.T) (string, string) {
+				return remove(t, create(t)).Token, userID
+			},
+		},
+		{
+			name: "another user's token",
+			prepare: func(t *testing.T) (string, string) {
+				return update(t, create(t), "Final").Token, otherID
+			},
+			wantErr: usecase.ErrUndoNotFound,
+		},
+		// O undo fica fora da política; é ele quem confere o acesso atual
+		// de quem desfaz a cada tarefa do token.
+		{
+			name: "share revoked since",
+			prepare: func(t *testing.T) (string, string) {
+				token := sharedUpdate(t, "edit")
+				if err := (&RevokeShareUseCase{UoW: uow}).Execute(ctx, RevokeShareInput{TaskID: token.Steps[0].TaskID, UserID: userID, TargetUserID: otherID}); err != nil {
+					t.Fatal(err)
+				}
+				return token.Token, otherID
+			},
+			wantErr: usecase.ErrTaskNotFound,
+		},
+		{
+			name: "share lowered to view since",
+			prepare: func(t *testing.T) (string, string) {
+				token := sharedUpdate(t, "edit")
+				share(t, token.Steps[0].TaskID, "view")
+				return token.Token, otherID
+			},
+			wantErr: usecase.ErrTaskForbidden,
+		},
+		{
+			name: "unknown token",
+			prepare: func(t *testing.T) (string, string) {
+				return "missing", userID
+			},
+			wantErr: usecase.ErrUndoNotFound,
+		},
+		{
+			name: "already used",
+			prepare: func(t *testing.T) (string, string) {
+				token := update(t, create(t), "Final").Token
+				if _, err := undo.Execute(ctx, UndoInput{Token: token, UserID: userID}); err != nil {
+					t.Fatal(err)
+				}
+				return token, userID
+			},
+			wantErr: usecase.ErrUndoExpired,
+		},
+		{
+			name: "expired",
+			prepare: func(t *testing.T) (string, string) {
+				expired, err := domainUndo.NewUndo(userID, "task.updated", nil, time.Minute)
+				if err != nil {
+					t.Fatal(err)
+				}
+				expired.ExpiresAt = time.Now().Add(-time.Second)
+				err = uow.Execute(ctx, func(ctx context.Context, work domain.Work) error {
+					return work.UndoRepo().Save(ctx, expired)
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+				return expired.Token, userID
+			},
+			wantErr: usecase.ErrUndoExpired,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, user := tt.prepare(t)
+			out, err := undo.Execute(ctx, UndoInput{Token: token, UserID: user})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if len(out.Tasks) != 1 {
+				t.Fatalf("undo touched %d tasks, want 1", len(out.Tasks))
+			}
+			if task := out.Tasks[0]; task.Title != "Draft" || task.DeletedAt != nil {
+				t.Errorf("task after undo = %q deleted at %v, want the original live task", task.Title, task.DeletedAt)
+			}
+		})
+	}
+}
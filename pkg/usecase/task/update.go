@@ -1,10 +1,13 @@
 package usecase
 
 import (
-	"log/slog"
+	"context"
 
-	domain "github.com/hoyci/todo-ddd/pkg/domain/task"
+	"github.com/hoyci/todo-ddd/pkg/domain"
+	domainTask "github.com/hoyci/todo-ddd/pkg/domain/task"
+	domainUndo "github.com/hoyci/todo-ddd/pkg/domain/undo"
 	"github.com/hoyci/todo-ddd/pkg/domain/valueobject"
+	"github.com/hoyci/todo-ddd/pkg/usecase"
 )
 
 type UpdateTaskInput struct {
@@ -16,26 +19,55 @@ type UpdateTaskInput struct {
 }
 
 type UpdateTaskOutput struct {
-	domain.Task
+	domainTask.Task
+	// Undo desfaz a mudança; nil se UndoWindow for zero.
+	Undo *domainUndo.Undo
 }
 
 type UpdateTaskUseCase struct {
-	TaskRepo domain.TaskRepository
+	UoW domain.UnitOfWork
+	Options
 }
 
-func (uc *UpdateTaskUseCase) Execute(input UpdateTaskInput) (*UpdateTaskOutput, error) {
-	task, err := uc.TaskRepo.FindByID(input.TaskID, input.UserID)
-	if err != nil {
-		slog.Error("error trying to find task by id", "taskID", input.TaskID)
-		return nil, err
-	}
+func (uc *UpdateTaskUseCase) Execute(ctx context.Context, input UpdateTaskInput) (output *UpdateTaskOutput, err error) {
+	ctx, end := usecase.Start(ctx, "update_task")
+	defer end(&err)
+
+	err = uc.UoW.Execute(ctx, func(ctx context.Context, work domain.Work) error {
+		task, err := findTaskWithAccess(ctx, work, input.TaskID, input.UserID, domainTask.AccessEdit)
+		if err != nil {
+			return err
+		}
+
+		before := *task
+		if err := task.Update(input.Title, input.Description, input.Priority); err != nil {
+			return err
+		}
 
-	task.Update(input.Title, input.Description, input.Priority)
+		if err := work.TaskRepo().Update(ctx, task); err != nil {
+			logger(ctx).Error("error trying to update task", "taskID", task.ID, "error", err)
+			return err
+		}
+		if err := recordEvents(ctx, work, task); err != nil {
+			return err
+		}
+		if err := auditTask(ctx, work, "task.updated", &before, task); err != nil {
+			return err
+		}
+		revision, err := recordRevision(ctx, work, task, input.UserID)
+		if err != nil {
+			return err
+		}
+		undo, err := issueUndo(ctx, work, uc.UndoWindow, input.UserID, "task.updated", []domainUndo.Step{restoreStep(revision)})
+		if err != nil {
+			return err
+		}
 
-	err = uc.TaskRepo.Update(task)
+		output = &UpdateTaskOutput{Task: *task, Undo: undo}
+		return nil
+	})
 	if err != nil {
-		slog.Error("error trying to update task", "taskID", task.ID)
 		return nil, err
 	}
-	return &UpdateTaskOutput{*task}, nil
+	return output, nil
 }
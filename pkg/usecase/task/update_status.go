@@ -1,10 +1,13 @@
 package usecase
 
 import (
-	"log/slog"
+	"context"
 
-	domain "github.com/hoyci/todo-ddd/pkg/domain/task"
+	"github.com/hoyci/todo-ddd/pkg/domain"
+	domainTask "github.com/hoyci/todo-ddd/pkg/domain/task"
+	domainUndo "github.com/hoyci/todo-ddd/pkg/domain/undo"
 	"github.com/hoyci/todo-ddd/pkg/domain/valueobject"
+	"github.com/hoyci/todo-ddd/pkg/usecase"
 )
 
 type UpdateTaskStatusInput struct {
@@ -14,21 +17,59 @@ type UpdateTaskStatusInput struct {
 }
 
 type UpdateTaskStatusOutput struct {
-	domain.Task
+	domainTask.Task
+	// Undo desfaz a mudança; nil se UndoWindow for zero.
+	Undo *domainUndo.Undo
 }
 
 type UpdateTaskStatusUseCase struct {
-	TaskRepo domain.TaskRepository
+	UoW domain.UnitOfWork
+	Options
 }
 
-func (uc *UpdateTaskStatusUseCase) Execute(input UpdateTaskStatusInput) (*UpdateTaskStatusOutput, error) {
-	task, err := uc.TaskRepo.FindByID(input.TaskID, input.UserID)
+func (uc *UpdateTaskStatusUseCase) Execute(ctx context.Context, input UpdateTaskStatusInput) (output *UpdateTaskStatusOutput, err error) {
+	ctx, end := usecase.Start(ctx, "update_task_status")
+	defer end(&err)
+
+	err = uc.UoW.Execute(ctx, func(ctx context.Context, work domain.Work) error {
+		task, err := findTaskWithAccess(ctx, work, input.TaskID, input.UserID, domainTask.AccessEdit)
+		if err != nil {
+			return err
+		}
+
+		before := *task
+		applyStatus(task, input.Status)
+
+		if err := work.TaskRepo().Update(ctx, task); err != nil {
+			logger(ctx).Error("error trying to update task status", "taskID", task.ID, "taskStatus", task.Status, "error", err)
+			return err
+		}
+		if err := recordEvents(ctx, work, task); err != nil {
+			return err
+		}
+		if err := auditTask(ctx, work, "task.status_changed", &before, task); err != nil {
+			return err
+		}
+		revision, err := recordRevision(ctx, work, task, input.UserID)
+		if err != nil {
+			return err
+		}
+		undo, err := issueUndo(ctx, work, uc.UndoWindow, input.UserID, "task.status_changed", []domainUndo.Step{restoreStep(revision)})
+		if err != nil {
+			return err
+		}
+
+		output = &UpdateTaskStatusOutput{Task: *task, Undo: undo}
+		return nil
+	})
 	if err != nil {
-		slog.Error("error trying to find task by id", "taskID", input.TaskID)
 		return nil, err
 	}
+	return output, nil
+}
 
-	switch input.Status {
+func applyStatus(task *domainTask.Task, status valueobject.Status) {
+	switch status {
 	case valueobject.StatusNew:
 		task.SetAsNew()
 	case valueobject.StatusInProgress:
@@ -36,11 +77,4 @@ func (uc *UpdateTaskStatusUseCase) Execute(input UpdateTaskStatusInput) (*Update
 	case valueobject.StatusCompleted:
 		task.SetCompleted()
 	}
-
-	err = uc.TaskRepo.Update(task)
-	if err != nil {
-		slog.Error("error trying to update task status", "taskID", task.ID, "taskStatus", task.Status)
-		return nil, err
-	}
-	return &UpdateTaskStatusOutput{*task}, nil
 }
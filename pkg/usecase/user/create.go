@@ -1,9 +1,12 @@
 package user
 
 import (
-	"log/slog"
+	"context"
+	"time"
 
-	domain "github.com/hoyci/todo-ddd/pkg/domain/user"
+	"github.com/hoyci/todo-ddd/pkg/domain"
+	domainUser "github.com/hoyci/todo-ddd/pkg/domain/user"
+	"github.com/hoyci/todo-ddd/pkg/usecase"
 )
 
 type CreateUserInput struct {
@@ -12,23 +15,47 @@ type CreateUserInput struct {
 }
 
 type CreateUserOutput struct {
-	User *domain.User
+	User *domainUser.User
 }
 
 type CreateUserUseCase struct {
-	UserRepo domain.UserRepository
+	UoW domain.UnitOfWork
+	// Verification envia o email de verificação ao novo usuário, que fica
+	// pendente até confirmá-lo. Sem ela, o usuário já nasce verificado.
+	Verification *VerificationSender
 }
 
-func (uc *CreateUserUseCase) Execute(input CreateUserInput) (*CreateUserOutput, error) {
-	user, err := domain.NewUser(input.Name, input.Email)
+func (uc *CreateUserUseCase) Execute(ctx context.Context, input CreateUserInput) (_ *CreateUserOutput, err error) {
+	ctx, end := usecase.Start(ctx, "create_user")
+	defer end(&err)
+
+	user, err := domainUser.NewUser(input.Name, input.Email)
 	if err != nil {
 		return nil, err
 	}
+	now := time.Now()
+	if uc.Verification == nil {
+		_ = user.Verify()
+	} else {
+		user.MarkVerificationSent(now)
+	}
 
-	if err := uc.UserRepo.Save(*user); err != nil {
-		slog.Error("error saving user", "email", input.Email)
+	err = uc.UoW.Execute(ctx, func(ctx context.Context, work domain.Work) error {
+		if err := work.UserRepo().Save(ctx, *user); err != nil {
+			logger(ctx).Error("error saving user", "email", input.Email, "error", err)
+			return err
+		}
+		return usecase.Audit(ctx, work.AuditRepo(), "user.created", "user", user.ID, nil, user)
+	})
+	if err != nil {
 		return nil, err
 	}
 
+	// O usuário já foi criado; se o email falhar, ele pode pedir o reenvio.
+	if uc.Verification != nil {
+		if err := uc.Verification.Send(ctx, user, now); err != nil {
+			logger(ctx).Warn("verification email not sent", "userID", user.ID, "error", err)
+		}
+	}
 	return &CreateUserOutput{User: user}, nil
 }
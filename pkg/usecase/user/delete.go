@@ -1,9 +1,10 @@
 package user
 
 import (
-	"log/slog"
+	"context"
 
-	domain "github.com/hoyci/todo-ddd/pkg/domain/user"
+	"github.com/hoyci/todo-ddd/pkg/domain"
+	"github.com/hoyci/todo-ddd/pkg/usecase"
 )
 
 type DeleteUserInput struct {
@@ -11,21 +12,27 @@ type DeleteUserInput struct {
 }
 
 type DeleteUserUseCase struct {
-	UserRepo domain.UserRepository
+	UoW domain.UnitOfWork
 }
 
-func (uc *DeleteUserUseCase) Execute(input DeleteUserInput) error {
-	user, err := uc.UserRepo.FindByID(input.ID)
-	if err != nil {
-		slog.Error("error finding user to delete", "id", input.ID)
-		return err
-	}
+func (uc *DeleteUserUseCase) Execute(ctx context.Context, input DeleteUserInput) (err error) {
+	ctx, end := usecase.Start(ctx, "delete_user")
+	defer end(&err)
 
-	user.Delete()
+	return uc.UoW.Execute(ctx, func(ctx context.Context, work domain.Work) error {
+		user, err := work.UserRepo().FindByID(ctx, input.ID)
+		if err != nil {
+			logger(ctx).Error("error finding user to delete", "id", input.ID, "error", err)
+			return err
+		}
 
-	if err := uc.UserRepo.Delete(user.ID, *user.DeletedAt); err != nil {
-		slog.Error("error deleting user", "id", input.ID)
-		return err
-	}
-	return nil
+		before := *user
+		user.Delete()
+
+		if err := work.UserRepo().Delete(ctx, user.ID, *user.DeletedAt); err != nil {
+			logger(ctx).Error("error deleting user", "id", input.ID, "error", err)
+			return err
+		}
+		return usecase.Audit(ctx, work.AuditRepo(), "user.deleted", "user", user.ID, &before, user)
+	})
 }
@@ -1,9 +1,10 @@
 package user
 
 import (
-	"log/slog"
+	"context"
 
 	domain "github.com/hoyci/todo-ddd/pkg/domain/user"
+	"github.com/hoyci/todo-ddd/pkg/usecase"
 )
 
 type FindUserInput struct {
@@ -18,10 +19,13 @@ type FindUserUseCase struct {
 	UserRepo domain.UserRepository
 }
 
-func (uc *FindUserUseCase) Execute(input FindUserInput) (*FindUserOutput, error) {
-	user, err := uc.UserRepo.FindByID(input.ID)
+func (uc *FindUserUseCase) Execute(ctx context.Context, input FindUserInput) (_ *FindUserOutput, err error) {
+	ctx, end := usecase.Start(ctx, "find_user")
+	defer end(&err)
+
+	user, err := uc.UserRepo.FindByID(ctx, input.ID)
 	if err != nil {
-		slog.Error("error finding user by id", "id", input.ID)
+		logger(ctx).Error("error finding user by id", "id", input.ID, "error", err)
 		return nil, err
 	}
 	return &FindUserOutput{User: user}, nil
@@ -0,0 +1,87 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	domain "github.com/hoyci/todo-ddd/pkg/domain/user"
+	"github.com/hoyci/todo-ddd/pkg/usecase"
+)
+
+type ListUserInput struct {
+	Search string
+	After  string
+	Limit  int
+}
+
+type ListUserOutput struct {
+	Users   []*domain.User
+	HasMore bool
+	// Total conta todos os usuários que casam com Search, não só a página.
+	Total int
+}
+
+// ListUserUseCase pagina os usuários ativos em ordem de criação.
+type ListUserUseCase struct {
+	UserRepo domain.UserRepository
+}
+
+func (uc *ListUserUseCase) Execute(ctx context.Context, input ListUserInput) (_ *ListUserOutput, err error) {
+	ctx, end := usecase.Start(ctx, "list_users")
+	defer end(&err)
+
+	if input.After != "" {
+		after, err := uc.UserRepo.FindByID(ctx, input.After)
+		if errors.Is(err, sql.ErrNoRows) || (err == nil && after.DeletedAt != nil) {
+			return nil, usecase.ErrInvalidCursor
+		}
+		if err != nil {
+			logger(ctx).Error("error finding cursor user", "id", input.After, "error", err)
+			return nil, err
+		}
+	}
+
+	users, err := uc.UserRepo.ListPage(ctx, domain.UserPageQuery{
+		Search:  input.Search,
+		AfterID: input.After,
+		Limit:   input.Limit + 1,
+	})
+	if err != nil {
+		logger(ctx).Error("error listing users", "error", err)
+		return nil, err
+	}
+	total, err := uc.UserRepo.Count(ctx, input.Search)
+	if err != nil {
+		logger(ctx).Error("error counting users", "error", err)
+		return nil, err
+	}
+
+	output := &ListUserOutput{Users: users, Total: total}
+	if len(users) > input.Limit {
+		output.Users, output.HasMore = users[:input.Limit], true
+	}
+	return output, nil
+}
+
+// FindUsersUseCase busca vários usuários de uma vez, indexados pelo ID.
+type FindUsersUseCase struct {
+	UserRepo domain.UserRepository
+}
+
+func (uc *FindUsersUseCase) Execute(ctx context.Context, ids []string) (_ map[string]*domain.User, err error) {
+	ctx, end := usecase.Start(ctx, "find_users")
+	defer end(&err)
+
+	users, err := uc.UserRepo.FindByIDs(ctx, ids)
+	if err != nil {
+		logger(ctx).Error("error finding users by ids", "users", len(ids), "error", err)
+		return nil, err
+	}
+
+	output := make(map[string]*domain.User, len(users))
+	for _, u := range users {
+		output[u.ID] = u
+	}
+	return output, nil
+}
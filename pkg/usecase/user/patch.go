@@ -0,0 +1,109 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/hoyci/todo-ddd/pkg/domain"
+	domainUser "github.com/hoyci/todo-ddd/pkg/domain/user"
+	"github.com/hoyci/todo-ddd/pkg/domain/valueobject"
+	"github.com/hoyci/todo-ddd/pkg/usecase"
+)
+
+// UserFields é o estado editável de um usuário. Ponteiros nil representam
+// campos nulos no documento resultante do patch.
+type UserFields struct {
+	Name  *string
+	Email *string
+}
+
+type PatchUserInput struct {
+	ID string
+	// Patch recebe o estado atual do usuário e devolve o estado desejado.
+	Patch func(current UserFields) (UserFields, error)
+}
+
+type PatchUserOutput struct {
+	User *domainUser.User
+}
+
+type PatchUserUseCase struct {
+	UoW domain.UnitOfWork
+}
+
+func (uc *PatchUserUseCase) Execute(ctx context.Context, input PatchUserInput) (output *PatchUserOutput, err error) {
+	ctx, end := usecase.Start(ctx, "patch_user")
+	defer end(&err)
+
+	err = uc.UoW.Execute(ctx, func(ctx context.Context, work domain.Work) error {
+		repo := work.UserRepo()
+
+		user, err := repo.FindByID(ctx, input.ID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return usecase.ErrUserNotFoundOrDeleted
+			}
+			logger(ctx).Error("error finding user to patch", "id", input.ID, "error", err)
+			return err
+		}
+		if user.DeletedAt != nil {
+			return usecase.ErrUserNotFoundOrDeleted
+		}
+
+		name, email := user.Name, user.Email
+		desired, err := input.Patch(UserFields{Name: &name, Email: &email})
+		if err != nil {
+			return fmt.Errorf("%w: %w", usecase.ErrInvalidPatch, err)
+		}
+
+		// Nome e e-mail são obrigatórios: null não limpa esses campos.
+		if desired.Name == nil {
+			return valueobject.ErrInvalidUserName
+		}
+		if desired.Email == nil {
+			return valueobject.ErrInvalidEmail
+		}
+
+		before := *user
+		changed := false
+		if *desired.Name != user.Name {
+			if err := user.Rename(*desired.Name); err != nil {
+				return err
+			}
+			changed = true
+		}
+		if *desired.Email != user.Email {
+			if err := user.ChangeEmail(*desired.Email); err != nil {
+				return err
+			}
+
+			existing, err := repo.FindByEmail(ctx, user.Email)
+			switch {
+			case err == nil && existing.ID != user.ID:
+				return usecase.ErrUserAlreadyExists
+			case err != nil && !errors.Is(err, sql.ErrNoRows):
+				return usecase.ErrSearchingUserByEmail
+			}
+			changed = true
+		}
+
+		if changed {
+			if err := repo.Update(ctx, *user); err != nil {
+				logger(ctx).Error("error patching user", "id", input.ID, "error", err)
+				return err
+			}
+			if err := usecase.Audit(ctx, work.AuditRepo(), "user.updated", "user", user.ID, &before, user); err != nil {
+				return err
+			}
+		}
+
+		output = &PatchUserOutput{User: user}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return output, nil
+}
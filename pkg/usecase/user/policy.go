@@ -0,0 +1,19 @@
+package user
+
+import (
+	"context"
+
+	"github.com/hoyci/todo-ddd/pkg/policy"
+)
+
+// UserResource resolve o cadastro alvo da entrada para a política. O
+// próprio usuário tem a relação self com ele.
+func UserResource[I any](id func(I) string) policy.ResourceFunc[I] {
+	return func(_ context.Context, principal policy.Principal, input I) (policy.Resource, error) {
+		resource := policy.Resource{Type: "user", ID: id(input)}
+		if principal.UserID != "" && principal.UserID == resource.ID {
+			resource.Relations = []policy.Relation{policy.RelationSelf}
+		}
+		return resource, nil
+	}
+}
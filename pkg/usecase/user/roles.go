@@ -0,0 +1,128 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/hoyci/todo-ddd/pkg/domain"
+	domainUser "github.com/hoyci/todo-ddd/pkg/domain/user"
+	"github.com/hoyci/todo-ddd/pkg/policy"
+	"github.com/hoyci/todo-ddd/pkg/usecase"
+)
+
+type ListRolesInput struct {
+	UserID string
+}
+
+type ListRolesOutput struct {
+	Roles []policy.Role
+}
+
+// ListRolesUseCase devolve os papéis concedidos explicitamente ao usuário;
+// RoleUser, implícito a todo usuário identificado, não aparece.
+type ListRolesUseCase struct {
+	UserRepo domainUser.UserRepository
+	Roles    policy.RoleStore
+}
+
+func (uc *ListRolesUseCase) Execute(ctx context.Context, input ListRolesInput) (_ *ListRolesOutput, err error) {
+	ctx, end := usecase.Start(ctx, "list_roles")
+	defer end(&err)
+
+	if err := findActiveUser(ctx, uc.UserRepo, input.UserID); err != nil {
+		return nil, err
+	}
+
+	roles, err := uc.Roles.Roles(ctx, input.UserID)
+	if err != nil {
+		logger(ctx).Error("error listing user roles", "id", input.UserID, "error", err)
+		return nil, err
+	}
+	return &ListRolesOutput{Roles: roles}, nil
+}
+
+type ChangeRoleInput struct {
+	UserID string
+	Role   string
+}
+
+type GrantRoleUseCase struct {
+	UoW domain.UnitOfWork
+}
+
+func (uc *GrantRoleUseCase) Execute(ctx context.Context, input ChangeRoleInput) (err error) {
+	ctx, end := usecase.Start(ctx, "grant_role")
+	defer end(&err)
+
+	role, err := policy.NewRole(input.Role)
+	if err != nil {
+		return err
+	}
+
+	return uc.UoW.Execute(ctx, func(ctx context.Context, work domain.Work) error {
+		if err := findActiveUser(ctx, work.UserRepo(), input.UserID); err != nil {
+			return err
+		}
+		before, err := work.RoleRepo().Roles(ctx, input.UserID)
+		if err != nil {
+			return err
+		}
+		if err := work.RoleRepo().Grant(ctx, input.UserID, role); err != nil {
+			logger(ctx).Error("error granting user role", "id", input.UserID, "role", role, "error", err)
+			return err
+		}
+		return auditRoles(ctx, work, "user.role_granted", input.UserID, before)
+	})
+}
+
+type RevokeRoleUseCase struct {
+	UoW domain.UnitOfWork
+}
+
+func (uc *RevokeRoleUseCase) Execute(ctx context.Context, input ChangeRoleInput) (err error) {
+	ctx, end := usecase.Start(ctx, "revoke_role")
+	defer end(&err)
+
+	role, err := policy.NewRole(input.Role)
+	if err != nil {
+		return err
+	}
+
+	return uc.UoW.Execute(ctx, func(ctx context.Context, work domain.Work) error {
+		before, err := work.RoleRepo().Roles(ctx, input.UserID)
+		if err != nil {
+			return err
+		}
+		if err := work.RoleRepo().Revoke(ctx, input.UserID, role); err != nil {
+			logger(ctx).Error("error revoking user role", "id", input.UserID, "role", role, "error", err)
+			return err
+		}
+		return auditRoles(ctx, work, "user.role_revoked", input.UserID, before)
+	})
+}
+
+// auditRoles registra os papéis do usuário antes e depois da mudança.
+func auditRoles(ctx context.Context, work domain.Work, action, userID string, before []policy.Role) error {
+	after, err := work.RoleRepo().Roles(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return usecase.Audit(ctx, work.AuditRepo(), action, "user", userID,
+		map[string][]policy.Role{"Roles": before}, map[string][]policy.Role{"Roles": after})
+}
+
+func findActiveUser(ctx context.Context, repo domainUser.UserRepository, id string) error {
+	user, err := repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return usecase.ErrUserNotFoundOrDeleted
+		}
+		logger(ctx).Error("error finding user", "id", id, "error", err)
+		return err
+	}
+	if user.DeletedAt != nil {
+		return usecase.ErrUserNotFoundOrDeleted
+	}
+	return nil
+}
@@ -1,10 +1,11 @@
 package user
 
 import (
-	"log/slog"
-	"time"
+	"context"
 
-	domain "github.com/hoyci/todo-ddd/pkg/domain/user"
+	"github.com/hoyci/todo-ddd/pkg/domain"
+	domainUser "github.com/hoyci/todo-ddd/pkg/domain/user"
+	"github.com/hoyci/todo-ddd/pkg/usecase"
 )
 
 type UpdateUserInput struct {
@@ -14,29 +15,45 @@ type UpdateUserInput struct {
 }
 
 type UpdateUserOutput struct {
-	User *domain.User
+	User *domainUser.User
 }
 
 type UpdateUserUseCase struct {
-	UserRepo domain.UserRepository
+	UoW domain.UnitOfWork
 }
 
-func (uc *UpdateUserUseCase) Execute(input UpdateUserInput) (*UpdateUserOutput, error) {
-	user, err := uc.UserRepo.FindByID(input.ID)
+func (uc *UpdateUserUseCase) Execute(ctx context.Context, input UpdateUserInput) (output *UpdateUserOutput, err error) {
+	ctx, end := usecase.Start(ctx, "update_user")
+	defer end(&err)
+
+	err = uc.UoW.Execute(ctx, func(ctx context.Context, work domain.Work) error {
+		user, err := work.UserRepo().FindByID(ctx, input.ID)
+		if err != nil {
+			logger(ctx).Error("error finding user to update", "id", input.ID, "error", err)
+			return err
+		}
+
+		before := *user
+		if err := user.Rename(input.Name); err != nil {
+			return err
+		}
+		if err := user.ChangeEmail(input.Email); err != nil {
+			return err
+		}
+
+		if err := work.UserRepo().Update(ctx, *user); err != nil {
+			logger(ctx).Error("error updating user", "id", input.ID, "error", err)
+			return err
+		}
+		if err := usecase.Audit(ctx, work.AuditRepo(), "user.updated", "user", user.ID, &before, user); err != nil {
+			return err
+		}
+
+		output = &UpdateUserOutput{User: user}
+		return nil
+	})
 	if err != nil {
-		slog.Error("error finding user to update", "id", input.ID)
 		return nil, err
 	}
-
-	now := time.Now()
-	user.Name = input.Name
-	user.Email = input.Email
-	user.UpdatedAt = &now
-
-	if err := uc.UserRepo.Update(*user); err != nil {
-		slog.Error("error updating user", "id", input.ID)
-		return nil, err
-	}
-
-	return &UpdateUserOutput{User: user}, nil
+	return output, nil
 }
@@ -0,0 +1,168 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hoyci/todo-ddd/pkg/domain"
+	domainNotification "github.com/hoyci/todo-ddd/pkg/domain/notification"
+	domainUser "github.com/hoyci/todo-ddd/pkg/domain/user"
+	"github.com/hoyci/todo-ddd/pkg/usecase"
+)
+
+// VerificationSender emite o token de verificação e o envia por email, em um
+// link para o endpoint de verificação.
+type VerificationSender struct {
+	Verifier *domainUser.Verifier
+	Mailer   domainNotification.Mailer
+	// BaseURL é a raiz pública da API, usada no link do email.
+	BaseURL string
+}
+
+func (s *VerificationSender) Send(ctx context.Context, user *domainUser.User, now time.Time) error {
+	token := s.Verifier.Issue(user, now)
+	link := strings.TrimRight(s.BaseURL, "/") + "/api/v1/onboarding/verify?token=" + url.QueryEscape(token)
+
+	return s.Mailer.Send(ctx, domainNotification.Message{
+		To:      user.Email,
+		Subject: "Confirm your email",
+		Body: "Hi " + user.Name + ",\n\n" +
+			"Confirm your email address by opening the link below:\n\n" +
+			link + "\n\n" +
+			"If you did not sign up, ignore this message.\n",
+	})
+}
+
+//
+// ------------------- VERIFY -------------------
+//
+
+type VerifyEmailInput struct {
+	Token string
+}
+
+type VerifyEmailUseCase struct {
+	UoW      domain.UnitOfWork
+	Verifier *domainUser.Verifier
+}
+
+func (uc *VerifyEmailUseCase) Execute(ctx context.Context, input VerifyEmailInput) (output *domainUser.User, err error) {
+	ctx, end := usecase.Start(ctx, "verify_email")
+	defer end(&err)
+
+	claims, err := uc.Verifier.Parse(input.Token, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	err = uc.UoW.Execute(ctx, func(ctx context.Context, work domain.Work) error {
+		user, err := work.UserRepo().FindByID(ctx, claims.UserID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return usecase.ErrUserNotFoundOrDeleted
+			}
+			logger(ctx).Error("error finding user to verify", "id", claims.UserID, "error", err)
+			return err
+		}
+		if user.DeletedAt != nil {
+			return usecase.ErrUserNotFoundOrDeleted
+		}
+		// O token foi emitido para um email que o usuário já trocou.
+		if user.Email != claims.Email {
+			return domainUser.ErrInvalidVerificationToken
+		}
+
+		before := *user
+		if err := user.Verify(); err != nil {
+			return err
+		}
+		if err := work.UserRepo().Update(ctx, *user); err != nil {
+			logger(ctx).Error("error verifying user", "id", user.ID, "error", err)
+			return err
+		}
+		if err := usecase.Audit(ctx, work.AuditRepo(), "user.verified", "user", user.ID, &before, user); err != nil {
+			return err
+		}
+
+		output = user
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+//
+// ------------------- RESEND -------------------
+//
+
+type ResendVerificationInput struct {
+	Email string
+}
+
+type ResendVerificationOutput struct {
+	// Sent é falso quando o último envio foi há menos de Interval; RetryAfter
+	// diz quanto falta para o próximo ser aceito.
+	Sent       bool
+	RetryAfter time.Duration
+}
+
+// ResendVerificationUseCase reenvia o email de verificação, no máximo um a
+// cada Interval por usuário.
+type ResendVerificationUseCase struct {
+	UoW      domain.UnitOfWork
+	Sender   *VerificationSender
+	Interval time.Duration
+}
+
+func (uc *ResendVerificationUseCase) Execute(ctx context.Context, input ResendVerificationInput) (output *ResendVerificationOutput, err error) {
+	ctx, end := usecase.Start(ctx, "resend_verification")
+	defer end(&err)
+
+	now := time.Now()
+	var user *domainUser.User
+	err = uc.UoW.Execute(ctx, func(ctx context.Context, work domain.Work) error {
+		user, err = work.UserRepo().FindByEmail(ctx, strings.ToLower(strings.TrimSpace(input.Email)))
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return usecase.ErrUserNotFound
+			}
+			logger(ctx).Error("error finding user by email", "error", err)
+			return err
+		}
+		if user.DeletedAt != nil {
+			return usecase.ErrUserNotFound
+		}
+		if user.Verified() {
+			return domainUser.ErrEmailAlreadyVerified
+		}
+
+		if next := user.NextVerificationAt(uc.Interval); now.Before(next) {
+			output = &ResendVerificationOutput{RetryAfter: next.Sub(now)}
+			return nil
+		}
+		user.MarkVerificationSent(now)
+		if err := work.UserRepo().Update(ctx, *user); err != nil {
+			logger(ctx).Error("error updating user verification", "id", user.ID, "error", err)
+			return err
+		}
+		output = &ResendVerificationOutput{Sent: true}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if output.Sent {
+		if err := uc.Sender.Send(ctx, user, now); err != nil {
+			logger(ctx).Error("error trying to send verification email", "userID", user.ID, "error", err)
+			return nil, err
+		}
+	}
+	return output, nil
+}
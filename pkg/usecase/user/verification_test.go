@@ -0,0 +1,130 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hoyci/todo-ddd/internal/adapters/db/sqlite"
+	"github.com/hoyci/todo-ddd/internal/adapters/events"
+	"github.com/hoyci/todo-ddd/pkg/domain"
+	domainNotification "github.com/hoyci/todo-ddd/pkg/domain/notification"
+	domainUser "github.com/hoyci/todo-ddd/pkg/domain/user"
+	"github.com/hoyci/todo-ddd/pkg/usecase"
+)
+
+// recordingMailer guarda os emails em vez de enviá-los.
+type recordingMailer struct {
+	sent []domainNotification.Message
+}
+
+func (m *recordingMailer) Send(_ context.Context, message domainNotification.Message) error {
+	m.sent = append(m.sent, message)
+	return nil
+}
+
+// lastToken extrai o token do link do último email enviado.
+func (m *recordingMailer) lastToken(t *testing.T) string {
+	t.Helper()
+	if len(m.sent) == 0 {
+		t.Fatal("no email sent")
+	}
+	body := m.sent[len(m.sent)-1].Body
+	_, rest, ok := strings.Cut(body, "?token=")
+	if !ok {
+		t.Fatalf("no verification link in %q", body)
+	}
+	token, err := url.QueryUnescape(strings.Fields(rest)[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func newTestUoW(t *testing.T) domain.UnitOfWork {
+	t.Helper()
+	t.Chdir(t.TempDir())
+	if err := os.Mkdir("data", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	db, err := sqlite.InitDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return sqlite.NewSQLiteUnitOfWork(db, events.NewBroker(0))
+}
+
+func TestEmailVerification(t *testing.T) {
+	uow := newTestUoW(t)
+	ctx := context.Background()
+	mailer := &recordingMailer{}
+	verifier := domainUser.NewVerifier([]byte("secret"), time.Hour)
+	sender := &VerificationSender{Verifier: verifier, Mailer: mailer, BaseURL: "http://localhost:8080/"}
+
+	created, err := (&CreateUserUseCase{UoW: uow, Verification: sender}).Execute(ctx, CreateUserInput{Name: "Ada", Email: "ada@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.User.Verified() || len(mailer.sent) != 1 || mailer.sent[0].To != "ada@example.com" {
+		t.Fatalf("created user verified = %v with %d emails, want pending with one email", created.User.Verified(), len(mailer.sent))
+	}
+	if !strings.Contains(mailer.sent[0].Body, "http://localhost:8080/api/v1/onboarding/verify?token=") {
+		t.Errorf("email body = %q, want a link to the verify endpoint", mailer.sent[0].Body)
+	}
+	firstToken := mailer.lastToken(t)
+
+	// Reenviar logo depois do cadastro esbarra no intervalo mínimo.
+	resend := &ResendVerificationUseCase{UoW: uow, Sender: sender, Interval: time.Minute}
+	out, err := resend.Execute(ctx, ResendVerificationInput{Email: " ADA@example.com "})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Sent || out.RetryAfter <= 0 || out.RetryAfter > time.Minute || len(mailer.sent) != 1 {
+		t.Errorf("throttled resend = %+v with %d emails, want not sent and a retry within a minute", out, len(mailer.sent))
+	}
+	noInterval := &ResendVerificationUseCase{UoW: uow, Sender: sender}
+	if out, err := noInterval.Execute(ctx, ResendVerificationInput{Email: "ada@example.com"}); err != nil || !out.Sent || len(mailer.sent) != 2 {
+		t.Errorf("resend after the interval = %+v, %v with %d emails; want sent", out, err, len(mailer.sent))
+	}
+	if _, err := resend.Execute(ctx, ResendVerificationInput{Email: "nobody@example.com"}); !errors.Is(err, usecase.ErrUserNotFound) {
+		t.Errorf("resend to an unknown address: error = %v, want %v", err, usecase.ErrUserNotFound)
+	}
+
+	// Trocar o email invalida os tokens emitidos para o anterior.
+	if _, err := (&UpdateUserUseCase{UoW: uow}).Execute(ctx, UpdateUserInput{ID: created.User.ID, Name: "Ada", Email: "ada@work.example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	verify := &VerifyEmailUseCase{UoW: uow, Verifier: verifier}
+	if _, err := verify.Execute(ctx, VerifyEmailInput{Token: firstToken}); !errors.Is(err, domainUser.ErrInvalidVerificationToken) {
+		t.Errorf("token for the old address: error = %v, want %v", err, domainUser.ErrInvalidVerificationToken)
+	}
+	if _, err := noInterval.Execute(ctx, ResendVerificationInput{Email: "ada@work.example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	token := mailer.lastToken(t)
+
+	// Um token com a assinatura certa, mas vencido.
+	expired := domainUser.NewVerifier([]byte("secret"), -time.Second).Issue(&domainUser.User{ID: created.User.ID, Email: "ada@work.example.com"}, time.Now())
+	if _, err := verify.Execute(ctx, VerifyEmailInput{Token: expired}); !errors.Is(err, domainUser.ErrVerificationTokenExpired) {
+		t.Errorf("expired token: error = %v, want %v", err, domainUser.ErrVerificationTokenExpired)
+	}
+
+	verified, err := verify.Execute(ctx, VerifyEmailInput{Token: token})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !verified.Verified() {
+		t.Error("user not verified by a valid token")
+	}
+	if _, err := verify.Execute(ctx, VerifyEmailInput{Token: token}); !errors.Is(err, domainUser.ErrEmailAlreadyVerified) {
+		t.Errorf("token reused: error = %v, want %v", err, domainUser.ErrEmailAlreadyVerified)
+	}
+	if _, err := noInterval.Execute(ctx, ResendVerificationInput{Email: "ada@work.example.com"}); !errors.Is(err, domainUser.ErrEmailAlreadyVerified) {
+		t.Errorf("resend to a verified user: error = %v, want %v", err, domainUser.ErrEmailAlreadyVerified)
+	}
+}
@@ -0,0 +1,230 @@
+package workspace
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/hoyci/todo-ddd/pkg/domain"
+	"github.com/hoyci/todo-ddd/pkg/domain/valueobject"
+	domainWorkspace "github.com/hoyci/todo-ddd/pkg/domain/workspace"
+	"github.com/hoyci/todo-ddd/pkg/usecase"
+)
+
+//
+// ------------------- INVITE -------------------
+//
+
+type InviteMemberInput struct {
+	WorkspaceID string
+	UserID      string
+	Email       string
+	Role        string
+}
+
+// InviteMemberUseCase convida um email para o workspace. O token do
+// convite é devolvido para que quem convidou o repasse.
+type InviteMemberUseCase struct {
+	UoW domain.UnitOfWork
+	// TTL é quanto tempo o convite pode ser aceito.
+	TTL time.Duration
+}
+
+func (uc *InviteMemberUseCase) Execute(ctx context.Context, input InviteMemberInput) (output *domainWorkspace.Invitation, err error) {
+	ctx, end := usecase.Start(ctx, "invite_workspace_member")
+	defer end(&err)
+
+	err = uc.UoW.Execute(ctx, func(ctx context.Context, work domain.Work) error {
+		actor, err := usecase.Authorize(ctx, work.WorkspaceRepo(), input.WorkspaceID, input.UserID, domainWorkspace.ActionManageMembers)
+		if err != nil {
+			return err
+		}
+
+		invitation, err := domainWorkspace.NewInvitation(input.WorkspaceID, input.Email, input.Role, input.UserID, uc.TTL)
+		if err != nil {
+			return err
+		}
+		if err := checkRoleChange(actor, valueobject.RoleGuest, invitation.Role); err != nil {
+			return err
+		}
+		if err := ensureNotMember(ctx, work, input.WorkspaceID, invitation.Email); err != nil {
+			return err
+		}
+
+		if err := work.InvitationRepo().Save(ctx, invitation); err != nil {
+			logger(ctx).Error("error trying to save invitation", "workspaceID", input.WorkspaceID, "error", err)
+			return err
+		}
+		if err := usecase.Audit(ctx, work.AuditRepo(), "workspace.member_invited", "workspace", input.WorkspaceID, nil, invitation); err != nil {
+			return err
+		}
+
+		output = invitation
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+// ensureNotMember falha se o dono do email já participa do workspace.
+func ensureNotMember(ctx context.Context, work domain.Work, workspaceID, email string) error {
+	user, err := work.UserRepo().FindByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+
+	if _, err := work.WorkspaceRepo().FindMember(ctx, workspaceID, user.ID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+	return usecase.ErrAlreadyMember
+}
+
+//
+// ------------------- LIST -------------------
+//
+
+type ListInvitationsInput struct {
+	WorkspaceID string
+	UserID      string
+}
+
+type ListInvitationsUseCase struct {
+	WorkspaceRepo  domainWorkspace.WorkspaceRepository
+	InvitationRepo domainWorkspace.InvitationRepository
+}
+
+func (uc *ListInvitationsUseCase) Execute(ctx context.Context, input ListInvitationsInput) (_ []*domainWorkspace.Invitation, err error) {
+	ctx, end := usecase.Start(ctx, "list_invitations")
+	defer end(&err)
+
+	if _, err := usecase.Authorize(ctx, uc.WorkspaceRepo, input.WorkspaceID, input.UserID, domainWorkspace.ActionManageMembers); err != nil {
+		return nil, err
+	}
+
+	invitations, err := uc.InvitationRepo.ListPending(ctx, input.WorkspaceID)
+	if err != nil {
+		logger(ctx).Error("error trying to list invitations", "workspaceID", input.WorkspaceID, "error", err)
+		return nil, err
+	}
+	return invitations, nil
+}
+
+//
+// ------------------- REVOKE -------------------
+//
+
+type RevokeInvitationInput struct {
+	WorkspaceID  string
+	UserID       string
+	InvitationID string
+}
+
+type RevokeInvitationUseCase struct {
+	UoW domain.UnitOfWork
+}
+
+func (uc *RevokeInvitationUseCase) Execute(ctx context.Context, input RevokeInvitationInput) (err error) {
+	ctx, end := usecase.Start(ctx, "revoke_invitation")
+	defer end(&err)
+
+	return uc.UoW.Execute(ctx, func(ctx context.Context, work domain.Work) error {
+		if _, err := usecase.Authorize(ctx, work.WorkspaceRepo(), input.WorkspaceID, input.UserID, domainWorkspace.ActionManageMembers); err != nil {
+			return err
+		}
+
+		invitation, err := work.InvitationRepo().FindByID(ctx, input.WorkspaceID, input.InvitationID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return usecase.ErrInvitationNotFound
+			}
+			return err
+		}
+		if err := work.InvitationRepo().Delete(ctx, invitation.ID); err != nil {
+			logger(ctx).Error("error trying to delete invitation", "invitationID", invitation.ID, "error", err)
+			return err
+		}
+		return usecase.Audit(ctx, work.AuditRepo(), "workspace.invitation_revoked", "workspace", input.WorkspaceID, invitation, nil)
+	})
+}
+
+//
+// ------------------- ACCEPT -------------------
+//
+
+type AcceptInvitationInput struct {
+	UserID string
+	Token  string
+}
+
+// AcceptInvitationUseCase torna o usuário membro com o papel do convite.
+// O email da conta precisa ser o mesmo para o qual o convite foi enviado.
+type AcceptInvitationUseCase struct {
+	UoW domain.UnitOfWork
+}
+
+func (uc *AcceptInvitationUseCase) Execute(ctx context.Context, input AcceptInvitationInput) (output *domainWorkspace.Member, err error) {
+	ctx, end := usecase.Start(ctx, "accept_invitation")
+	defer end(&err)
+
+	err = uc.UoW.Execute(ctx, func(ctx context.Context, work domain.Work) error {
+		user, err := activeUser(ctx, work, input.UserID)
+		if err != nil {
+			return err
+		}
+
+		invitation, err := work.InvitationRepo().FindByToken(ctx, input.Token)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return usecase.ErrInvitationNotFound
+			}
+			return err
+		}
+		if !invitation.Pending(time.Now()) {
+			return usecase.ErrInvitationExpired
+		}
+		if invitation.Email != user.Email {
+			return usecase.ErrInvitationEmailMismatch
+		}
+		if _, err := findWorkspace(ctx, work.WorkspaceRepo(), invitation.WorkspaceID); err != nil {
+			return err
+		}
+		if err := ensureNotMember(ctx, work, invitation.WorkspaceID, user.Email); err != nil {
+			return err
+		}
+
+		member := &domainWorkspace.Member{
+			WorkspaceID: invitation.WorkspaceID,
+			UserID:      user.ID,
+			Role:        invitation.Role,
+			JoinedAt:    time.Now(),
+		}
+		if err := work.WorkspaceRepo().SaveMember(ctx, member); err != nil {
+			logger(ctx).Error("error trying to save workspace member", "workspaceID", member.WorkspaceID, "error", err)
+			return err
+		}
+		invitation.Accept()
+		if err := work.InvitationRepo().Update(ctx, invitation); err != nil {
+			logger(ctx).Error("error trying to update invitation", "invitationID", invitation.ID, "error", err)
+			return err
+		}
+		if err := usecase.Audit(ctx, work.AuditRepo(), "workspace.member_joined", "workspace", member.WorkspaceID, nil, member); err != nil {
+			return err
+		}
+
+		output = member
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return output, nil
+}
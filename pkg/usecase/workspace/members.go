@@ -0,0 +1,172 @@
+package workspace
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/hoyci/todo-ddd/pkg/domain"
+	"github.com/hoyci/todo-ddd/pkg/domain/valueobject"
+	domainWorkspace "github.com/hoyci/todo-ddd/pkg/domain/workspace"
+	"github.com/hoyci/todo-ddd/pkg/usecase"
+)
+
+//
+// ------------------- LIST -------------------
+//
+
+type ListMembersInput struct {
+	WorkspaceID string
+	UserID      string
+}
+
+type ListMembersUseCase struct {
+	WorkspaceRepo domainWorkspace.WorkspaceRepository
+}
+
+func (uc *ListMembersUseCase) Execute(ctx context.Context, input ListMembersInput) (_ []*domainWorkspace.Member, err error) {
+	ctx, end := usecase.Start(ctx, "list_workspace_members")
+	defer end(&err)
+
+	if _, err := usecase.Authorize(ctx, uc.WorkspaceRepo, input.WorkspaceID, input.UserID, domainWorkspace.ActionViewTasks); err != nil {
+		return nil, err
+	}
+
+	members, err := uc.WorkspaceRepo.ListMembers(ctx, input.WorkspaceID)
+	if err != nil {
+		logger(ctx).Error("error trying to list workspace members", "workspaceID", input.WorkspaceID, "error", err)
+		return nil, err
+	}
+	return members, nil
+}
+
+//
+// ------------------- CHANGE ROLE -------------------
+//
+
+type ChangeMemberRoleInput struct {
+	WorkspaceID  string
+	UserID       string
+	TargetUserID string
+	Role         string
+}
+
+// ChangeMemberRoleUseCase troca o papel de um membro. O papel owner não é
+// concedido nem retirado, e só o owner promove ou rebaixa admins.
+type ChangeMemberRoleUseCase struct {
+	UoW domain.UnitOfWork
+}
+
+func (uc *ChangeMemberRoleUseCase) Execute(ctx context.Context, input ChangeMemberRoleInput) (output *domainWorkspace.Member, err error) {
+	ctx, end := usecase.Start(ctx, "change_workspace_member_role")
+	defer end(&err)
+
+	err = uc.UoW.Execute(ctx, func(ctx context.Context, work domain.Work) error {
+		repo := work.WorkspaceRepo()
+		actor, err := usecase.Authorize(ctx, repo, input.WorkspaceID, input.UserID, domainWorkspace.ActionManageMembers)
+		if err != nil {
+			return err
+		}
+		role, err := valueobject.NewWorkspaceRole(input.Role)
+		if err != nil {
+			return err
+		}
+		target, err := findMember(ctx, repo, input.WorkspaceID, input.TargetUserID)
+		if err != nil {
+			return err
+		}
+		if err := checkRoleChange(actor, target.Role, role); err != nil {
+			return err
+		}
+
+		before := *target
+		target.Role = role
+		if err := repo.SaveMember(ctx, target); err != nil {
+			logger(ctx).Error("error trying to change workspace member role", "workspaceID", input.WorkspaceID, "error", err)
+			return err
+		}
+		if err := usecase.Audit(ctx, work.AuditRepo(), "workspace.member_role_changed", "workspace", input.WorkspaceID, &before, target); err != nil {
+			return err
+		}
+
+		output = target
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+//
+// ------------------- REMOVE -------------------
+//
+
+type RemoveMemberInput struct {
+	WorkspaceID string
+	UserID      string
+	// TargetUserID igual a UserID faz o usuário sair do workspace, o que
+	// qualquer membro pode, exceto o owner.
+	TargetUserID string
+}
+
+type RemoveMemberUseCase struct {
+	UoW domain.UnitOfWork
+}
+
+func (uc *RemoveMemberUseCase) Execute(ctx context.Context, input RemoveMemberInput) (err error) {
+	ctx, end := usecase.Start(ctx, "remove_workspace_member")
+	defer end(&err)
+
+	return uc.UoW.Execute(ctx, func(ctx context.Context, work domain.Work) error {
+		repo := work.WorkspaceRepo()
+		action := domainWorkspace.ActionManageMembers
+		if input.TargetUserID == input.UserID {
+			action = domainWorkspace.ActionViewTasks
+		}
+		actor, err := usecase.Authorize(ctx, repo, input.WorkspaceID, input.UserID, action)
+		if err != nil {
+			return err
+		}
+		target, err := findMember(ctx, repo, input.WorkspaceID, input.TargetUserID)
+		if err != nil {
+			return err
+		}
+		if target.Role == valueobject.RoleOwner {
+			return usecase.ErrOwnerRoleFixed
+		}
+		if target.UserID != actor.UserID && target.Role == valueobject.RoleAdmin && !actor.Can(domainWorkspace.ActionGrantAdmin) {
+			return usecase.ErrWorkspaceForbidden
+		}
+
+		if err := repo.RemoveMember(ctx, input.WorkspaceID, target.UserID); err != nil {
+			logger(ctx).Error("error trying to remove workspace member", "workspaceID", input.WorkspaceID, "error", err)
+			return err
+		}
+		return usecase.Audit(ctx, work.AuditRepo(), "workspace.member_removed", "workspace", input.WorkspaceID, target, nil)
+	})
+}
+
+// checkRoleChange valida a troca de from para to feita por actor: owner
+// não entra nem sai, e admins só são criados ou rebaixados pelo owner.
+func checkRoleChange(actor *domainWorkspace.Member, from, to valueobject.WorkspaceRole) error {
+	if from == valueobject.RoleOwner || to == valueobject.RoleOwner {
+		return usecase.ErrOwnerRoleFixed
+	}
+	if (from == valueobject.RoleAdmin || to == valueobject.RoleAdmin) && !actor.Can(domainWorkspace.ActionGrantAdmin) {
+		return usecase.ErrWorkspaceForbidden
+	}
+	return nil
+}
+
+func findMember(ctx context.Context, repo domainWorkspace.WorkspaceRepository, workspaceID, userID string) (*domainWorkspace.Member, error) {
+	member, err := repo.FindMember(ctx, workspaceID, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, usecase.ErrMemberNotFound
+		}
+		logger(ctx).Error("error trying to find workspace member", "workspaceID", workspaceID, "error", err)
+		return nil, err
+	}
+	return member, nil
+}
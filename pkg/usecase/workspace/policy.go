@@ -0,0 +1,39 @@
+package workspace
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/hoyci/todo-ddd/pkg/domain/valueobject"
+	domainWorkspace "github.com/hoyci/todo-ddd/pkg/domain/workspace"
+	"github.com/hoyci/todo-ddd/pkg/policy"
+	"github.com/hoyci/todo-ddd/pkg/usecase"
+)
+
+// WorkspaceResource resolve o workspace alvo da entrada para a política,
+// traduzindo o papel do membro em relação. Quem não é membro recebe
+// ErrWorkspaceNotFound, como em usecase.Authorize.
+func WorkspaceResource[I any](repo domainWorkspace.WorkspaceRepository, workspaceID func(I) string) policy.ResourceFunc[I] {
+	return func(ctx context.Context, principal policy.Principal, input I) (policy.Resource, error) {
+		resource := policy.Resource{Type: "workspace", ID: workspaceID(input)}
+		member, err := repo.FindMember(ctx, resource.ID, principal.UserID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return resource, usecase.ErrWorkspaceNotFound
+			}
+			return resource, err
+		}
+		if relation, ok := workspaceRelations[member.Role]; ok {
+			resource.Relations = []policy.Relation{relation}
+		}
+		return resource, nil
+	}
+}
+
+var workspaceRelations = map[valueobject.WorkspaceRole]policy.Relation{
+	valueobject.RoleOwner:  policy.RelationOwner,
+	valueobject.RoleAdmin:  policy.RelationEditor,
+	valueobject.RoleMember: policy.RelationViewer,
+	valueobject.RoleGuest:  policy.RelationViewer,
+}
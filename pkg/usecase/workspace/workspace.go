@@ -0,0 +1,228 @@
+package workspace
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/hoyci/todo-ddd/pkg/domain"
+	domainUser "github.com/hoyci/todo-ddd/pkg/domain/user"
+	domainWorkspace "github.com/hoyci/todo-ddd/pkg/domain/workspace"
+	"github.com/hoyci/todo-ddd/pkg/usecase"
+)
+
+//
+// ------------------- CREATE -------------------
+//
+
+type CreateWorkspaceInput struct {
+	UserID string
+	Name   string
+}
+
+// CreateWorkspaceUseCase cria o workspace com quem o criou como owner.
+type CreateWorkspaceUseCase struct {
+	UoW domain.UnitOfWork
+}
+
+func (uc *CreateWorkspaceUseCase) Execute(ctx context.Context, input CreateWorkspaceInput) (output *domainWorkspace.Workspace, err error) {
+	ctx, end := usecase.Start(ctx, "create_workspace")
+	defer end(&err)
+
+	err = uc.UoW.Execute(ctx, func(ctx context.Context, work domain.Work) error {
+		if _, err := activeUser(ctx, work, input.UserID); err != nil {
+			return err
+		}
+
+		workspace, owner, err := domainWorkspace.NewWorkspace(input.Name, input.UserID)
+		if err != nil {
+			return err
+		}
+		if err := work.WorkspaceRepo().Save(ctx, workspace); err != nil {
+			logger(ctx).Error("error trying to save workspace", "error", err)
+			return err
+		}
+		if err := work.WorkspaceRepo().SaveMember(ctx, owner); err != nil {
+			logger(ctx).Error("error trying to save workspace owner", "workspaceID", workspace.ID, "error", err)
+			return err
+		}
+		if err := usecase.Audit(ctx, work.AuditRepo(), "workspace.created", "workspace", workspace.ID, nil, workspace); err != nil {
+			return err
+		}
+
+		output = workspace
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+//
+// ------------------- LIST -------------------
+//
+
+type ListWorkspacesUseCase struct {
+	WorkspaceRepo domainWorkspace.WorkspaceRepository
+}
+
+func (uc *ListWorkspacesUseCase) Execute(ctx context.Context, userID string) (_ []*domainWorkspace.Workspace, err error) {
+	ctx, end := usecase.Start(ctx, "list_workspaces")
+	defer end(&err)
+
+	workspaces, err := uc.WorkspaceRepo.ListByMember(ctx, userID)
+	if err != nil {
+		logger(ctx).Error("error trying to list workspaces", "userID", userID, "error", err)
+		return nil, err
+	}
+	return workspaces, nil
+}
+
+//
+// ------------------- GET -------------------
+//
+
+type GetWorkspaceInput struct {
+	WorkspaceID string
+	UserID      string
+}
+
+type GetWorkspaceOutput struct {
+	Workspace *domainWorkspace.Workspace
+	// Member é a participação de quem consultou.
+	Member *domainWorkspace.Member
+}
+
+type GetWorkspaceUseCase struct {
+	WorkspaceRepo domainWorkspace.WorkspaceRepository
+}
+
+func (uc *GetWorkspaceUseCase) Execute(ctx context.Context, input GetWorkspaceInput) (_ *GetWorkspaceOutput, err error) {
+	ctx, end := usecase.Start(ctx, "get_workspace")
+	defer end(&err)
+
+	member, err := usecase.Authorize(ctx, uc.WorkspaceRepo, input.WorkspaceID, input.UserID, domainWorkspace.ActionViewTasks)
+	if err != nil {
+		return nil, err
+	}
+	workspace, err := findWorkspace(ctx, uc.WorkspaceRepo, input.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+	return &GetWorkspaceOutput{Workspace: workspace, Member: member}, nil
+}
+
+//
+// ------------------- RENAME -------------------
+//
+
+type RenameWorkspaceInput struct {
+	WorkspaceID string
+	UserID      string
+	Name        string
+}
+
+type RenameWorkspaceUseCase struct {
+	UoW domain.UnitOfWork
+}
+
+func (uc *RenameWorkspaceUseCase) Execute(ctx context.Context, input RenameWorkspaceInput) (output *domainWorkspace.Workspace, err error) {
+	ctx, end := usecase.Start(ctx, "rename_workspace")
+	defer end(&err)
+
+	err = uc.UoW.Execute(ctx, func(ctx context.Context, work domain.Work) error {
+		repo := work.WorkspaceRepo()
+		if _, err := usecase.Authorize(ctx, repo, input.WorkspaceID, input.UserID, domainWorkspace.ActionRenameWorkspace); err != nil {
+			return err
+		}
+		workspace, err := findWorkspace(ctx, repo, input.WorkspaceID)
+		if err != nil {
+			return err
+		}
+
+		before := *workspace
+		if err := workspace.Rename(input.Name); err != nil {
+			return err
+		}
+		if err := repo.Update(ctx, workspace); err != nil {
+			logger(ctx).Error("error trying to rename workspace", "workspaceID", workspace.ID, "error", err)
+			return err
+		}
+		if err := usecase.Audit(ctx, work.AuditRepo(), "workspace.renamed", "workspace", workspace.ID, &before, workspace); err != nil {
+			return err
+		}
+
+		output = workspace
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+//
+// ------------------- DELETE -------------------
+//
+
+type DeleteWorkspaceInput struct {
+	WorkspaceID string
+	UserID      string
+}
+
+// DeleteWorkspaceUseCase exclui o workspace. As tarefas dele continuam
+// gravadas, mas deixam de ser visíveis porque ninguém mais é membro ativo.
+type DeleteWorkspaceUseCase struct {
+	UoW domain.UnitOfWork
+}
+
+func (uc *DeleteWorkspaceUseCase) Execute(ctx context.Context, input DeleteWorkspaceInput) (err error) {
+	ctx, end := usecase.Start(ctx, "delete_workspace")
+	defer end(&err)
+
+	return uc.UoW.Execute(ctx, func(ctx context.Context, work domain.Work) error {
+		repo := work.WorkspaceRepo()
+		if _, err := usecase.Authorize(ctx, repo, input.WorkspaceID, input.UserID, domainWorkspace.ActionDeleteWorkspace); err != nil {
+			return err
+		}
+		workspace, err := findWorkspace(ctx, repo, input.WorkspaceID)
+		if err != nil {
+			return err
+		}
+
+		before := *workspace
+		workspace.Delete()
+		if err := repo.Update(ctx, workspace); err != nil {
+			logger(ctx).Error("error trying to delete workspace", "workspaceID", workspace.ID, "error", err)
+			return err
+		}
+		return usecase.Audit(ctx, work.AuditRepo(), "workspace.deleted", "workspace", workspace.ID, &before, workspace)
+	})
+}
+
+func findWorkspace(ctx context.Context, repo domainWorkspace.WorkspaceRepository, workspaceID string) (*domainWorkspace.Workspace, error) {
+	workspace, err := repo.FindByID(ctx, workspaceID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, usecase.ErrWorkspaceNotFound
+		}
+		logger(ctx).Error("error trying to find workspace", "workspaceID", workspaceID, "error", err)
+		return nil, err
+	}
+	return workspace, nil
+}
+
+func activeUser(ctx context.Context, work domain.Work, userID string) (*domainUser.User, error) {
+	user, err := work.UserRepo().FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, usecase.ErrUserNotFoundOrDeleted
+		}
+		return nil, err
+	}
+	if user.DeletedAt != nil {
+		return nil, usecase.ErrUserNotFoundOrDeleted
+	}
+	return user, nil
+}